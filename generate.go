@@ -0,0 +1,77 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere"
+)
+
+const generateUsage = `Usage: terraform-provider-vsphere generate -datacenter NAME [-out FILE]
+
+Generates the Terraform configuration and import blocks for the inventory of a
+datacenter. The connection to vSphere is configured with the same environment
+variables as the provider, such as VSPHERE_SERVER, VSPHERE_USER and
+VSPHERE_PASSWORD.
+
+Options:
+`
+
+// runGenerate runs the generate command with args, and returns the exit code
+// of the command.
+func runGenerate(args []string) int {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	datacenter := fs.String("datacenter", "", "the name or inventory path of the datacenter to generate the configuration for")
+	out := fs.String("out", "", "the file to write the configuration to, instead of standard output")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), generateUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if err := generate(*datacenter, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// generate configures the provider from the environment and writes the
+// configuration of datacenter to the file out, or to standard output if out is
+// empty.
+func generate(datacenter, out string) (err error) {
+	ctx := context.Background()
+	p := vsphere.Provider()
+	diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{}))
+	defer vsphere.CloseClients()
+	for _, d := range diags {
+		if d.Severity == diag.Error {
+			return fmt.Errorf("error configuring the provider: %s", d.Summary)
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if out != "" {
+		f, cerr := os.Create(out)
+		if cerr != nil {
+			return cerr
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		w = f
+	}
+	return vsphere.GenerateConfig(ctx, p, vsphere.GenerateOptions{Datacenter: datacenter}, w)
+}
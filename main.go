@@ -4,24 +4,37 @@
 package main
 
 import (
+	"context"
 	"flag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"log"
+	"os"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "generate" {
+		os.Exit(runGenerate(os.Args[2:]))
+	}
+
 	var debugMode bool
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		ProviderFunc: vsphere.Provider,
+	serverFactory, err := vsphere.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
 	}
 
+	var serveOpts []tf5server.ServeOpt
 	if debugMode {
-		opts.Debug = true
-		opts.ProviderAddr = "hashicorp/vsphere"
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
 	}
 
-	plugin.Serve(opts)
+	err = tf5server.Serve("registry.terraform.io/hashicorp/vsphere", serverFactory, serveOpts...)
+	vsphere.CloseClients()
+	if err != nil {
+		log.Fatal(err)
+	}
 }
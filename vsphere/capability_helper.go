@@ -0,0 +1,50 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+// capabilityRequirement declares that a resource needs a capability of the
+// connected vSphere endpoint, from the registry in viapi. If attribute is set,
+// the capability is only needed when the attribute is set to a non-zero
+// value.
+type capabilityRequirement struct {
+	capability string
+	attribute  string
+}
+
+// requireCapabilities returns a CustomizeDiffFunc that fails the plan with a
+// viapi.CapabilityError if the connected endpoint is missing a capability the
+// resource requires. Combine it with the other diff customizations of a
+// resource using customdiff.Sequence:
+//
+//	CustomizeDiff: customdiff.Sequence(
+//	  requireCapabilities(capabilityRequirement{capability: viapi.CapabilityRestAPI}),
+//	  resourceVSphereFooCustomizeDiff,
+//	),
+func requireCapabilities(reqs ...capabilityRequirement) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		client := meta.(*Client).vimClient
+		for _, r := range reqs {
+			if r.attribute != "" {
+				if _, ok := d.GetOk(r.attribute); !ok {
+					continue
+				}
+			}
+			if err := viapi.CheckCapability(client, r.capability); err != nil {
+				if r.attribute != "" {
+					return fmt.Errorf("%s: %s", r.attribute, err)
+				}
+				return err
+			}
+		}
+		return nil
+	}
+}
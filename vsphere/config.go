@@ -6,7 +6,7 @@ package vsphere
 import (
 	"context"
 	"crypto/sha1"
-	"encoding/json"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"log"
@@ -16,18 +16,23 @@ import (
 	"path/filepath"
 	"strings"
 	"time"
-	"github.com/vmware/govmomi/sts"
-	"github.com/vmware/govmomi/license"
-	"github.com/vmware/govmomi/vapi/rest"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/debuglog"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/sessionfile"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/transport"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/license"
 	"github.com/vmware/govmomi/pbm"
 	"github.com/vmware/govmomi/session"
 	"github.com/vmware/govmomi/session/cache"
 	"github.com/vmware/govmomi/session/keepalive"
 	"github.com/vmware/govmomi/ssoadmin"
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vapi/rest"
 	"github.com/vmware/govmomi/vapi/tags"
 	"github.com/vmware/govmomi/vim25"
 	"github.com/vmware/govmomi/vim25/debug"
@@ -56,8 +61,28 @@ type Client struct {
 	// The SSO client
 	ssoClient *ssoadmin.Client
 
+	// The TLS and proxy settings, for HTTP clients created outside of the
+	// API clients above.
+	transport *transport.Settings
+
 	// client timeout for certain operations
 	timeout time.Duration
+
+	// The IDs of the tags applied to every resource that supports tags.
+	defaultTags []string
+
+	// The default of deletion_protection for the resources that support it.
+	deletionProtection bool
+
+	// The tracer of the API clients, if the debug format is trace.
+	tracer *debuglog.Tracer
+}
+
+// Close releases what the client holds open besides its API sessions, which
+// is the trace file of client_debug. The sessions are left to expire, or to be
+// reused if they are persisted.
+func (c *Client) Close() error {
+	return c.tracer.Close()
 }
 
 // TagsManager returns the embedded tags manager used for tags, after determining
@@ -77,7 +102,7 @@ type Client struct {
 // are, read them from the object and save them in the resource:
 //
 //	if tm, _ := meta.(*VSphereClient).TagsManager(); tm != nil {
-//	  if err := readTagsForResource(restClient, obj, d); err != nil {
+//	  if err := readTagsForResource(ctx, tm, obj, d, meta); err != nil {
 //	    return err
 //	  }
 //	}
@@ -86,11 +111,30 @@ func (c *Client) TagsManager() (*tags.Manager, error) {
 		return nil, err
 	}
 	if c.restClient == nil {
-		return nil, fmt.Errorf("tags require %s or higher", tagsMinVersion)
+		if err := viapi.CheckCapability(c.vimClient, viapi.CapabilityRestAPI); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("tags require a connection to the vSphere REST API")
 	}
 	return tags.NewManager(c.restClient), nil
 }
 
+// SSOClient returns the client for SSO administration, which resources that
+// manage SSO users, groups and identity sources use.
+func (c *Client) SSOClient() (*ssoadmin.Client, error) {
+	if err := viapi.ValidateVirtualCenter(c.vimClient); err != nil {
+		return nil, err
+	}
+	if c.ssoClient == nil {
+		return nil, fmt.Errorf("SSO administration is not available when authenticating with api_session_id")
+	}
+	return c.ssoClient, nil
+}
+
+// secretFingerprintKeyFile is the name of the file in the SOAP session
+// directory that holds the generated key of secret fingerprints.
+const secretFingerprintKeyFile = "fingerprint.key"
+
 // Config holds the provider configuration, and delivers a populated
 // VSphereClient based off the contained settings.
 type Config struct {
@@ -102,11 +146,56 @@ type Config struct {
 	VSphereServer   string
 	DebugPath       string
 	DebugPathRun    string
+	DebugFormat     string
 	VimSessionPath  string
 	RestSessionPath string
 	LicenseKey      string
 	KeepAlive       int
 	APITimeout      time.Duration
+	MaxRetries      int
+	RetryMaxWait    time.Duration
+
+	// The maximum numbers of concurrent API calls and tasks.
+	Concurrency viapi.ConcurrencyLimits
+
+	// The IDs of the tags applied to every resource that supports tags.
+	DefaultTags []string
+
+	// The default of deletion_protection for the resources that support it.
+	DeletionProtection bool
+
+	// The key used to encrypt persisted sessions, supplied directly or as a
+	// path to a file containing it.
+	SessionEncryptionKey     string
+	SessionEncryptionKeyFile string
+
+	// Credentials used instead of User and Password. The certificate, key and
+	// token can be supplied inline or as a path to a file.
+	SAMLToken         string
+	ClientCertificate string
+	ClientKey         string
+	APISessionID      string
+
+	// TLS and proxy settings. The CA bundle can be supplied inline or as a
+	// path to a file.
+	CABundle         string
+	ServerThumbprint string
+	ProxyURL         string
+
+	// The signer for the SAML token used to authenticate, once issued.
+	signer *sts.Signer
+
+	// The parsed TLS and proxy settings.
+	transport *transport.Settings
+
+	// The store for persisted sessions.
+	store *sessionfile.Store
+
+	// The limiter shared by the API clients.
+	limits *viapi.Limiter
+
+	// The tracer for the API clients, if the debug format is trace.
+	tracer *debuglog.Tracer
 }
 
 // NewConfig returns a new Config from a supplied ResourceData.
@@ -135,17 +224,88 @@ func NewConfig(d *schema.ResourceData) (*Config, error) {
 		Debug:           d.Get("client_debug").(bool),
 		DebugPathRun:    d.Get("client_debug_path_run").(string),
 		DebugPath:       d.Get("client_debug_path").(string),
+		DebugFormat:     d.Get("client_debug_format").(string),
 		Persist:         d.Get("persist_session").(bool),
 		VimSessionPath:  d.Get("vim_session_path").(string),
 		RestSessionPath: d.Get("rest_session_path").(string),
 		LicenseKey:      d.Get("license_key").(string),
 		KeepAlive:       d.Get("vim_keep_alive").(int),
 		APITimeout:      timeout,
+		MaxRetries:      d.Get("max_retries").(int),
+		RetryMaxWait:    time.Duration(d.Get("retry_max_wait").(int)) * time.Second,
+
+		Concurrency: viapi.ConcurrencyLimits{
+			APICalls:   d.Get("max_concurrent_api_calls").(int),
+			RestCalls:  d.Get("max_concurrent_rest_calls").(int),
+			Clones:     d.Get("max_concurrent_clones").(int),
+			Relocates:  d.Get("max_concurrent_relocates").(int),
+			OvfDeploys: d.Get("max_concurrent_ovf_deploys").(int),
+		},
+
+		SessionEncryptionKey:     d.Get("session_encryption_key").(string),
+		SessionEncryptionKeyFile: d.Get("session_encryption_key_file").(string),
+
+		SAMLToken:         d.Get("saml_token").(string),
+		ClientCertificate: d.Get("client_certificate").(string),
+		ClientKey:         d.Get("client_key").(string),
+		APISessionID:      d.Get("api_session_id").(string),
+
+		CABundle:         d.Get("ca_bundle").(string),
+		ServerThumbprint: d.Get("server_thumbprint").(string),
+		ProxyURL:         d.Get("proxy_url").(string),
+
+		DefaultTags:        structure.SliceInterfacesToStrings(d.Get("default_tags").(*schema.Set).List()),
+		DeletionProtection: d.Get("deletion_protection").(bool),
+	}
+
+	if err := c.validateCredentials(); err != nil {
+		return nil, err
+	}
+
+	if c.Persist && c.User == "" {
+		log.Printf("[WARN] persist_session is only supported with user and password authentication, sessions will not be persisted")
+		c.Persist = false
 	}
 
 	return c, nil
 }
 
+// validateCredentials checks that exactly one authentication method is
+// configured. saml_token can be combined with client_certificate and
+// client_key to present a holder-of-key token.
+func (c *Config) validateCredentials() error {
+	if (c.ClientCertificate == "") != (c.ClientKey == "") {
+		return fmt.Errorf("client_certificate and client_key must be provided together")
+	}
+
+	var methods []string
+	if c.User != "" {
+		methods = append(methods, "user")
+	}
+	switch {
+	case c.SAMLToken != "":
+		methods = append(methods, "saml_token")
+	case c.ClientCertificate != "":
+		methods = append(methods, "client_certificate")
+	}
+	if c.APISessionID != "" {
+		methods = append(methods, "api_session_id")
+	}
+
+	switch len(methods) {
+	case 0:
+		return fmt.Errorf("one of user, saml_token, client_certificate or api_session_id must be provided")
+	case 1:
+	default:
+		return fmt.Errorf("only one of user, saml_token, client_certificate or api_session_id can be provided, got %s", strings.Join(methods, ", "))
+	}
+
+	if c.User != "" && c.Password == "" {
+		return fmt.Errorf("password must be provided with user")
+	}
+	return nil
+}
+
 // vimURL returns a URL to pass to the VIM SOAP client.
 func (c *Config) vimURL() (*url.URL, error) {
 	u, err := url.Parse("https://" + c.VSphereServer + "/sdk")
@@ -153,41 +313,73 @@ func (c *Config) vimURL() (*url.URL, error) {
 		return nil, fmt.Errorf("Error parse url: %s", err)
 	}
 
-	u.User = url.UserPassword(c.User, c.Password)
+	if c.User != "" {
+		u.User = url.UserPassword(c.User, c.Password)
+	}
 
 	return u, nil
 }
 
 // Client returns a new client for accessing VMWare vSphere.
-func (c *Config) Client() (*Client, error) {
+func (c *Config) Client() (_ *Client, err error) {
 	client := new(Client)
+	defer func() {
+		if err != nil {
+			_ = client.Close()
+		}
+	}()
 
 	u, err := c.vimURL()
 	if err != nil {
 		return nil, fmt.Errorf("Error generating SOAP endpoint url: %s", err)
 	}
 
+	client.transport, err = c.transportSettings()
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := c.secretFingerprintKey()
+	if err != nil {
+		return nil, err
+	}
+	secret.SetKey(key)
+
 	err = c.EnableDebug()
 	if err != nil {
 		return nil, fmt.Errorf("Error setting up client debug: %s", err)
 	}
+	client.tracer = c.tracer
 
 	// Set up the VIM/govmomi client connection, or load a previous session
 	client.vimClient, err = c.SavedVimSessionOrNew(u)
 	if err != nil {
 		return nil, err
 	}
+	// Properties read with viapi.RetrieveProperties are shared between the
+	// resources of the run, until the client changes something.
+	cache := viapi.NewPropertyCache()
+	client.vimClient.RoundTripper = viapi.NewRetryRoundTripper(
+		cache.RoundTripper(viapi.NewLimitRoundTripper(c.tracer.RoundTripper(client.vimClient.RoundTripper), c.limiter())),
+		c.retryPolicy(),
+	)
+	viapi.SetPropertyCache(client.vimClient.Client, cache)
 
 	log.Printf("[DEBUG] VMWare vSphere Client configured for URL: %s", c.VSphereServer)
 
 	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
 	defer cancel()
-	s := new(cache.Session)
 	if isEligibleRestEndpoint(client.vimClient) {
-		s, err = c.restURL()
+		s, err := c.restURL()
 		if err != nil {
 			return nil, err
 		}
+		if c.User == "" {
+			vc := client.vimClient.Client
+			s.LoginREST = func(ctx context.Context, rc *rest.Client) error {
+				return c.loginRestClient(ctx, rc, vc)
+			}
+		}
 		client.restClient, err = c.SavedRestSessionOrNew(s)
 		if err != nil {
 			return nil, err
@@ -222,30 +414,23 @@ func (c *Config) Client() (*Client, error) {
 		log.Printf("[DEBUG] Connected endpoint does not support vSAN service")
 	}
 
-	if isEligibleSSOEndpoint(client.vimClient) {
+	if isEligibleSSOEndpoint(client.vimClient) && c.APISessionID != "" {
+		// A session cannot be exchanged for the SAML token that SSO
+		// administration requires.
+		log.Printf("[DEBUG] SSO administration is not available when authenticating with api_session_id")
+	} else if isEligibleSSOEndpoint(client.vimClient) {
 		ssoclient, err := ssoadmin.NewClient(ctx, client.vimClient.Client)
 		if err != nil {
 			return nil, fmt.Errorf("error creating sso client: %s", err)
 		}
 
-		tokens, err := sts.NewClient(ctx, client.vimClient.Client)
+		signer, err := c.tokenSigner(ctx, client.vimClient.Client)
 		if err != nil {
 			return nil, fmt.Errorf("error trying to get security token for sso client: %s", err)
 		}
 
-		req := sts.TokenRequest{
-			Certificate: client.vimClient.Certificate(),
-			Userinfo:    url.UserPassword(c.User, c.Password),
-		}
-
 		header := soap.Header{
-			Security: &sts.Signer{
-				Certificate: client.vimClient.Certificate(),
-			},
-		}
-
-		if header.Security, err = tokens.Issue(ctx, req); err != nil {
-			return nil, fmt.Errorf("error trying to set security header with token for sso client: %s", err)
+			Security: signer,
 		}
 
 		if err = ssoclient.Login(client.vimClient.WithHeader(ctx, header)); err != nil {
@@ -261,7 +446,7 @@ func (c *Config) Client() (*Client, error) {
 	if err := c.SaveVimClient(client.vimClient); err != nil {
 		return nil, fmt.Errorf("error persisting SOAP session to disk: %s", err)
 	}
-	if err := c.SaveRestClient(client.restClient, s); err != nil {
+	if err := c.SaveRestClient(client.restClient); err != nil {
 		return nil, fmt.Errorf("error persisting REST session to disk: %s", err)
 	}
 
@@ -273,6 +458,8 @@ func (c *Config) Client() (*Client, error) {
 	}
 
 	client.timeout = c.APITimeout
+	client.defaultTags = c.DefaultTags
+	client.deletionProtection = c.DeletionProtection
 
 	return client, nil
 }
@@ -322,7 +509,9 @@ func (c *Config) restURL() (*cache.Session, error) {
 	if err != nil {
 		return nil, err
 	}
-	u.User = url.UserPassword(c.User, c.Password)
+	if c.User != "" {
+		u.User = url.UserPassword(c.User, c.Password)
+	}
 	s := &cache.Session{
 		URL:      u,
 		Insecure: c.InsecureFlag,
@@ -335,25 +524,79 @@ func (c *Config) SavedRestSessionOrNew(s *cache.Session) (*rest.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
 	defer cancel()
 
-	s.DirREST = c.RestSessionPath
-	s.Passthrough = !c.Persist
-	restClient := new(rest.Client)
-	err := s.Login(ctx, restClient, nil)
+	settings, err := c.transportSettings()
 	if err != nil {
 		return nil, err
 	}
+
+	restClient, err := c.LoadRestClient(ctx, settings)
+	if err != nil {
+		return nil, fmt.Errorf("error trying to load vSphere REST session from disk: %s", err)
+	}
+	if restClient == nil {
+		// Sessions are persisted by SaveRestClient, so that they can be
+		// encrypted and locked.
+		s.Passthrough = true
+		restClient = new(rest.Client)
+		err = s.Login(ctx, restClient, settings.ConfigureSOAP)
+		if err != nil {
+			return nil, err
+		}
+	}
 	// Setup keepalive functionality
 	var f func() error
 	t := keepalive.NewHandlerREST(restClient, time.Duration(c.KeepAlive)*time.Minute, f)
 	t.Start()
-	restClient.Transport = t
+	restClient.Transport = viapi.NewRestRetryTransport(
+		viapi.NewRestLimitTransport(c.tracer.Transport(t), c.limiter()),
+		c.retryPolicy(),
+		c.restRelogin(s, restClient),
+	)
 
 	log.Println("[DEBUG] CIS REST client configuration successful")
 	return restClient, nil
 }
 
+// retryPolicy returns the policy for retrying calls that fail with a transient
+// fault.
+func (c *Config) retryPolicy() viapi.RetryPolicy {
+	return viapi.RetryPolicy{
+		MaxRetries: c.MaxRetries,
+		MaxWait:    c.RetryMaxWait,
+	}
+}
+
+// limiter returns the limiter for the API clients. It is created once, so that
+// the limits apply across the SOAP and REST clients.
+func (c *Config) limiter() *viapi.Limiter {
+	if c.limits == nil {
+		c.limits = viapi.NewLimiter(c.Concurrency)
+	}
+	return c.limits
+}
+
+// restRelogin returns a function that creates a new session for rc after its
+// session has expired, or nil if a new session cannot be created.
+func (c *Config) restRelogin(s *cache.Session, rc *rest.Client) func(context.Context) (string, error) {
+	if c.APISessionID != "" {
+		return nil
+	}
+	return func(ctx context.Context) (string, error) {
+		rc.SessionID("")
+		var err error
+		if s.LoginREST != nil {
+			err = s.LoginREST(ctx, rc)
+		} else {
+			err = rc.Login(ctx, s.URL.User)
+		}
+		return rc.SessionID(), err
+	}
+}
+
 // EnableDebug turns on govmomi API operation logging, if appropriate settings
-// are set on the provider.
+// are set on the provider. Credentials are redacted from the request and
+// response dumps. With the trace format, the tracer is set up instead, and is
+// installed on the API clients by Client.
 func (c *Config) EnableDebug() error {
 	if !c.Debug {
 		return nil
@@ -383,11 +626,18 @@ func (c *Config) EnableDebug() error {
 		return err
 	}
 
-	p := debug.FileProvider{
-		Path: r,
+	if c.DebugFormat == debuglog.FormatTrace {
+		// The trace file is closed by Client.Close.
+		tracer, err := debuglog.OpenTracer(filepath.Join(r, debuglog.TraceFile))
+		if err != nil {
+			log.Printf("[ERROR] Client debug setup failed: %v", err)
+			return err
+		}
+		c.tracer = tracer
+		return nil
 	}
 
-	debug.SetProvider(&p)
+	debug.SetProvider(debuglog.NewProvider(r))
 	return nil
 }
 
@@ -397,7 +647,9 @@ func (c *Config) vimURLWithoutPassword() (*url.URL, error) {
 		return nil, err
 	}
 	withoutCredentials := u
-	withoutCredentials.User = url.User(u.User.Username())
+	if u.User != nil {
+		withoutCredentials.User = url.User(u.User.Username())
+	}
 	return withoutCredentials, nil
 }
 
@@ -406,11 +658,12 @@ func (c *Config) vimURLWithoutPassword() (*url.URL, error) {
 //
 // This is the same logic used as part of govmomi and is designed to be
 // consistent so that sessions can be shared if possible between both tools.
-func (c *Config) sessionFile() (string, error) {
+func (c *Config) sessionFile(path string) (string, error) {
 	u, err := c.vimURLWithoutPassword()
 	if err != nil {
 		return "", err
 	}
+	u.Path = path
 
 	// Key session file off of full URI and insecure setting.
 	// Hash key to get a predictable, canonical format.
@@ -422,7 +675,7 @@ func (c *Config) sessionFile() (string, error) {
 // restSessionFile is takes the session file name generated by sessionFile and
 // then prefixes the REST client session path to it.
 func (c *Config) restSessionFile() (string, error) {
-	p, err := c.sessionFile()
+	p, err := c.sessionFile(rest.Path)
 	if err != nil {
 		return "", err
 	}
@@ -432,7 +685,7 @@ func (c *Config) restSessionFile() (string, error) {
 // vimSessionFile is takes the session file name generated by sessionFile and
 // then prefixes the SOAP client session path to it.
 func (c *Config) vimSessionFile() (string, error) {
-	p, err := c.sessionFile()
+	p, err := c.sessionFile(vim25.Path)
 	if err != nil {
 		return "", err
 	}
@@ -454,36 +707,33 @@ func (c *Config) SaveVimClient(client *govmomi.Client) error {
 		return err
 	}
 
-	log.Printf("[DEBUG] Will persist SOAP client session data to %q", p)
-	err = os.MkdirAll(filepath.Dir(p), 0700)
+	store, err := c.sessionStore()
 	if err != nil {
 		return err
 	}
 
-	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY, 0600)
+	log.Printf("[DEBUG] Will persist SOAP client session data to %q", p)
+	return store.Write(p, client.Client)
+}
+
+// SaveRestClient saves a REST client session to disk, in the same format as
+// SaveVimClient.
+func (c *Config) SaveRestClient(client *rest.Client) error {
+	if !c.Persist || client == nil {
+		return nil
+	}
+
+	p, err := c.restSessionFile()
 	if err != nil {
 		return err
 	}
-
-	defer func() {
-		if err = f.Close(); err != nil {
-			log.Printf("[DEBUG] Error closing SOAP client session file %q: %s", p, err)
-		}
-	}()
-
-	err = json.NewEncoder(f).Encode(client.Client)
+	store, err := c.sessionStore()
 	if err != nil {
 		return err
 	}
 
-	return nil
-}
-
-func (c *Config) SaveRestClient(client *rest.Client, s *cache.Session) error {
-	if !c.Persist {
-		return nil
-	}
-	return s.Save(client)
+	log.Printf("[DEBUG] Will persist REST client session data to %q", p)
+	return store.Write(p, client)
 }
 
 // restoreVimClient loads the saved session from disk. Note that this is a helper
@@ -497,30 +747,20 @@ func (c *Config) restoreVimClient(client *vim25.Client) (bool, error) {
 	if err != nil {
 		return false, fmt.Errorf("error determining SOAP session filename: %s", err)
 	}
-	log.Printf("[DEBUG] Attempting to locate SOAP client session data in %q", p)
-	f, err := os.Open(p)
+	store, err := c.sessionStore()
 	if err != nil {
-		if os.IsNotExist(err) {
-			log.Printf("[DEBUG] SOAP client session data not found in %q", p)
-			return false, nil
-		}
-
-		return false, fmt.Errorf("error opening SOAP client session: %s", err)
+		return false, err
 	}
 
-	defer func() {
-		if err = f.Close(); err != nil {
-			log.Printf("[DEBUG] Error closing SOAP client session file %q: %s", p, err)
-		}
-	}()
-
-	dec := json.NewDecoder(f)
-	err = dec.Decode(client)
+	log.Printf("[DEBUG] Attempting to locate SOAP client session data in %q", p)
+	ok, err := store.Read(p, client)
 	if err != nil {
-		return false, fmt.Errorf("error decoding SOAP client session: %s", err)
+		return false, fmt.Errorf("error reading SOAP client session: %s", err)
 	}
-
-	return true, nil
+	if !ok {
+		log.Printf("[DEBUG] SOAP client session data not found in %q", p)
+	}
+	return ok, nil
 }
 
 // LoadVimClient loads a saved vSphere SOAP API session from disk, previously
@@ -543,6 +783,14 @@ func (c *Config) LoadVimClient() (*govmomi.Client, error) {
 		return nil, nil
 	}
 
+	settings, err := c.transportSettings()
+	if err != nil {
+		return nil, err
+	}
+	if err := settings.ConfigureSOAP(client.Client); err != nil {
+		return nil, err
+	}
+
 	m := session.NewManager(client)
 	u, err := m.UserSession(context.TODO())
 	if err != nil {
@@ -571,6 +819,48 @@ func (c *Config) LoadVimClient() (*govmomi.Client, error) {
 	}, nil
 }
 
+// LoadRestClient loads a saved vSphere REST API session from disk, previously
+// saved by SaveRestClient, checking it for validity before returning it. A nil
+// client means that the session is no longer valid and should be created from
+// scratch.
+func (c *Config) LoadRestClient(ctx context.Context, settings *transport.Settings) (*rest.Client, error) {
+	if !c.Persist {
+		return nil, nil
+	}
+
+	p, err := c.restSessionFile()
+	if err != nil {
+		return nil, fmt.Errorf("error determining REST session filename: %s", err)
+	}
+	store, err := c.sessionStore()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[DEBUG] Attempting to locate REST client session data in %q", p)
+	client := new(rest.Client)
+	ok, err := store.Read(p, client)
+	if err != nil {
+		return nil, fmt.Errorf("error reading REST client session: %s", err)
+	}
+	if !ok || !client.Valid() {
+		log.Println("[DEBUG] Cached REST client session data not valid, new session necessary")
+		return nil, nil
+	}
+
+	if err := settings.ConfigureSOAP(client.Client); err != nil {
+		return nil, err
+	}
+	s, err := client.Session(ctx)
+	if err != nil || s == nil {
+		log.Printf("[DEBUG] Cached REST client session not active, new session necessary (%v)", err)
+		return nil, nil
+	}
+
+	log.Println("[DEBUG] Cached REST client session loaded successfully")
+	return client, nil
+}
+
 // SavedVimSessionOrNew either loads a saved SOAP session from disk, or creates
 // a new one.
 func (c *Config) SavedVimSessionOrNew(u *url.URL) (*govmomi.Client, error) {
@@ -583,17 +873,29 @@ func (c *Config) SavedVimSessionOrNew(u *url.URL) (*govmomi.Client, error) {
 	}
 	if client == nil {
 		log.Printf("[DEBUG] Creating new SOAP API session on endpoint %s", c.VSphereServer)
-		client, err = newClientWithKeepAlive(ctx, u, c.InsecureFlag, c.KeepAlive)
+		settings, err := c.transportSettings()
+		if err != nil {
+			return nil, err
+		}
+		client, err = newClientWithKeepAlive(ctx, u, settings, c.KeepAlive)
 		if err != nil {
 			return nil, fmt.Errorf("error setting up new vSphere SOAP client: %s", err)
 		}
+		if u.User == nil {
+			if err := c.loginVimClient(ctx, client); err != nil {
+				return nil, fmt.Errorf("error logging in to vSphere SOAP API: %s", err)
+			}
+		}
 		log.Println("[DEBUG] SOAP API session creation successful")
 	}
 	return client, nil
 }
 
-func newClientWithKeepAlive(ctx context.Context, u *url.URL, insecure bool, keepAlive int) (*govmomi.Client, error) {
-	soapClient := soap.NewClient(u, insecure)
+func newClientWithKeepAlive(ctx context.Context, u *url.URL, settings *transport.Settings, keepAlive int) (*govmomi.Client, error) {
+	soapClient := soap.NewClient(u, settings.Insecure)
+	if err := settings.ConfigureSOAP(soapClient); err != nil {
+		return nil, err
+	}
 	vimClient, err := vim25.NewClient(ctx, soapClient)
 	if err != nil {
 		return nil, err
@@ -618,6 +920,233 @@ func newClientWithKeepAlive(ctx context.Context, u *url.URL, insecure bool, keep
 	return c, nil
 }
 
+// loginVimClient authenticates a SOAP client with the configured SAML token,
+// client certificate or session ID. User and password authentication is
+// handled by newClientWithKeepAlive.
+func (c *Config) loginVimClient(ctx context.Context, client *govmomi.Client) error {
+	if c.APISessionID != "" {
+		client.Jar.SetCookies(client.URL(), []*http.Cookie{
+			{
+				Name:  soap.SessionCookieName,
+				Value: c.APISessionID,
+			},
+		})
+		s, err := client.SessionManager.UserSession(ctx)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("api_session_id does not refer to an active session")
+		}
+		return nil
+	}
+
+	signer, err := c.tokenSigner(ctx, client.Client)
+	if err != nil {
+		return err
+	}
+	header := soap.Header{
+		Security: signer,
+	}
+	return client.SessionManager.LoginByToken(client.WithHeader(ctx, header))
+}
+
+// loginRestClient authenticates a REST client with the configured SAML
+// token, client certificate or session ID. vc is the SOAP client used to
+// request a token from the STS, if one is needed.
+func (c *Config) loginRestClient(ctx context.Context, rc *rest.Client, vc *vim25.Client) error {
+	if c.APISessionID != "" {
+		rc.SessionID(c.APISessionID)
+		s, err := rc.Session(ctx)
+		if err != nil {
+			return err
+		}
+		if s == nil {
+			return fmt.Errorf("api_session_id does not refer to an active REST API session")
+		}
+		return nil
+	}
+
+	signer, err := c.tokenSigner(ctx, vc)
+	if err != nil {
+		return err
+	}
+	return rc.LoginByToken(rc.WithSigner(ctx, signer))
+}
+
+// tokenSigner returns the signer for the SAML token used with the SOAP, REST
+// and SSO administration APIs. Unless saml_token is set, a token is issued by
+// the STS of vc for the client certificate, or the user and password. The
+// signer is issued once and reused afterwards.
+func (c *Config) tokenSigner(ctx context.Context, vc *vim25.Client) (*sts.Signer, error) {
+	if c.signer != nil {
+		return c.signer, nil
+	}
+
+	cert, err := c.clientCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	if c.SAMLToken != "" {
+		token, err := readCredential(c.SAMLToken, "<")
+		if err != nil {
+			return nil, fmt.Errorf("error reading saml_token: %s", err)
+		}
+		c.signer = &sts.Signer{
+			Certificate: cert,
+			Token:       token,
+		}
+		return c.signer, nil
+	}
+
+	tokens, err := sts.NewClient(ctx, vc)
+	if err != nil {
+		return nil, err
+	}
+	req := sts.TokenRequest{
+		Certificate: cert,
+	}
+	if c.User != "" {
+		req.Certificate = vc.Certificate()
+		req.Userinfo = url.UserPassword(c.User, c.Password)
+	}
+	signer, err := tokens.Issue(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("error issuing token: %s", err)
+	}
+	c.signer = signer
+	return c.signer, nil
+}
+
+// clientCertificate loads the holder-of-key certificate and private key. It
+// returns nil if no certificate is configured.
+func (c *Config) clientCertificate() (*tls.Certificate, error) {
+	if c.ClientCertificate == "" {
+		return nil, nil
+	}
+	certPEM, err := readCredential(c.ClientCertificate, "-----BEGIN")
+	if err != nil {
+		return nil, fmt.Errorf("error reading client_certificate: %s", err)
+	}
+	keyPEM, err := readCredential(c.ClientKey, "-----BEGIN")
+	if err != nil {
+		return nil, fmt.Errorf("error reading client_key: %s", err)
+	}
+	cert, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM))
+	if err != nil {
+		return nil, fmt.Errorf("error loading client certificate: %s", err)
+	}
+	return &cert, nil
+}
+
+// transportSettings returns the TLS and proxy settings for the connections
+// made by the provider. The settings are parsed once and reused afterwards.
+func (c *Config) transportSettings() (*transport.Settings, error) {
+	if c.transport != nil {
+		return c.transport, nil
+	}
+
+	s := &transport.Settings{
+		Insecure:   c.InsecureFlag,
+		Server:     c.VSphereServer,
+		Thumbprint: c.ServerThumbprint,
+	}
+	if c.CABundle != "" {
+		bundle, err := readCredential(c.CABundle, "-----BEGIN")
+		if err != nil {
+			return nil, fmt.Errorf("error reading ca_bundle: %s", err)
+		}
+		s.CABundle = []byte(bundle)
+	}
+	if c.ProxyURL != "" {
+		u, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing proxy_url: %s", err)
+		}
+		s.Proxy = u
+	}
+	if err := s.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid TLS or proxy settings: %s", err)
+	}
+
+	c.transport = s
+	return c.transport, nil
+}
+
+// sessionStore returns the store used to read and write persisted sessions,
+// which encrypts them if an encryption key is configured.
+func (c *Config) sessionStore() (*sessionfile.Store, error) {
+	if c.store != nil {
+		return c.store, nil
+	}
+
+	key, err := c.sessionEncryptionKey()
+	if err != nil {
+		return nil, err
+	}
+	store, err := sessionfile.NewStore(key)
+	if err != nil {
+		return nil, fmt.Errorf("error setting up session encryption: %s", err)
+	}
+
+	c.store = store
+	return c.store, nil
+}
+
+// sessionEncryptionKey returns the key to encrypt persisted sessions with,
+// which is empty if none is configured.
+func (c *Config) sessionEncryptionKey() (string, error) {
+	if c.SessionEncryptionKeyFile == "" {
+		return c.SessionEncryptionKey, nil
+	}
+	if c.SessionEncryptionKey != "" {
+		return "", fmt.Errorf("only one of session_encryption_key and session_encryption_key_file can be provided")
+	}
+	key, err := sessionfile.ReadKeyFile(c.SessionEncryptionKeyFile)
+	if err != nil {
+		return "", fmt.Errorf("error reading session_encryption_key_file: %s", err)
+	}
+	return key, nil
+}
+
+// secretFingerprintKey returns the key of the fingerprints that secret
+// arguments save in state. It is the session encryption key if one is
+// configured, or else a random key generated the first time and saved in
+// secretFingerprintKeyFile in the SOAP session directory, so that the
+// fingerprints cannot be guessed from anything in the configuration or state.
+func (c *Config) secretFingerprintKey() (string, error) {
+	key, err := c.sessionEncryptionKey()
+	if err != nil || key != "" {
+		return key, err
+	}
+	if c.VimSessionPath == "" {
+		return "", fmt.Errorf("no key for the fingerprints of secrets in state: set session_encryption_key or vim_session_path")
+	}
+	p := filepath.Join(c.VimSessionPath, secretFingerprintKeyFile)
+	key, created, err := sessionfile.ReadOrCreateKeyFile(p)
+	if err != nil {
+		return "", fmt.Errorf("error reading the key for the fingerprints of secrets in state from %q, set session_encryption_key to use another key: %s", p, err)
+	}
+	if created {
+		log.Printf("[WARN] No session_encryption_key is set, fingerprints of secrets in state are keyed with a key generated in %q. Runs that do not share this file cannot detect changes to secrets.", p)
+	}
+	return key, nil
+}
+
+// readCredential returns v if it is an inline value, recognized by prefix, or
+// the contents of the file at path v otherwise.
+func readCredential(v, prefix string) (string, error) {
+	if strings.HasPrefix(strings.TrimSpace(v), prefix) {
+		return v, nil
+	}
+	b, err := os.ReadFile(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
 func restSessionValid(client *rest.Client) bool {
 	sessionURL := client.URL().String() + "/com/vmware/cis/session?~action=get"
 	resp, err := client.Post(sessionURL, "", nil)
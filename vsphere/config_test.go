@@ -5,15 +5,26 @@ package vsphere
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
-	"log"
+	"net"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/debuglog"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/transport"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi/license"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/debug"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
@@ -89,7 +100,7 @@ func testAccClientRemoveVcenterLicense(client *Client) error {
 	return nil
 }
 
-func testAccClientGenerateConfig() *Config {
+func testAccClientGenerateConfig(t *testing.T) *Config {
 	insecure, _ := strconv.ParseBool(os.Getenv("VSPHERE_ALLOW_UNVERIFIED_SSL"))
 	debug, _ := strconv.ParseBool(os.Getenv("VSPHERE_CLIENT_DEBUG"))
 
@@ -101,6 +112,10 @@ func testAccClientGenerateConfig() *Config {
 		VSphereServer: os.Getenv("VSPHERE_SERVER"),
 		DebugPath:     os.Getenv("VSPHERE_CLIENT_DEBUG_PATH"),
 		DebugPathRun:  os.Getenv("VSPHERE_CLIENT_DEBUG_PATH_RUN"),
+		// Sessions are not persisted unless a test sets Persist, but the key of
+		// secret fingerprints is generated in the session directory.
+		VimSessionPath:  t.TempDir(),
+		RestSessionPath: t.TempDir(),
 	}
 }
 
@@ -138,28 +153,13 @@ func testAccClientCheckStatNoExist(t *testing.T, p string) {
 func TestAccClient_persistence(t *testing.T) {
 	testAccClientPreCheck(t)
 
-	vimSessionDir, err := os.MkdirTemp("", "tf-vsphere-test-vimsessiondir")
-	if err != nil {
-		t.Fatalf("error creating VIM session temp directory: %s", err)
-	}
-	restSessionDir, err := os.MkdirTemp("", "tf-vsphere-test-restsessiondir")
-	if err != nil {
-		t.Fatalf("error creating REST session temp directory: %s", err)
-	}
-	defer func() {
-		if err = os.RemoveAll(vimSessionDir); err != nil {
-			log.Printf("[DEBUG] Error removing test VIM session directory %q: %s", vimSessionDir, err)
-		}
-	}()
-	defer func() {
-		if err = os.RemoveAll(restSessionDir); err != nil {
-			log.Printf("[DEBUG] Error removing test REST session directory %q: %s", restSessionDir, err)
-		}
-	}()
+	vimSessionDir := t.TempDir()
+	restSessionDir := t.TempDir()
 
-	c := testAccClientGenerateConfig()
+	c := testAccClientGenerateConfig(t)
 	c.Persist = true
 	c.VimSessionPath = vimSessionDir
+	c.RestSessionPath = restSessionDir
 
 	expectedVim := testAccClientGenerateData(t, c)
 
@@ -174,31 +174,16 @@ func TestAccClient_persistence(t *testing.T) {
 func TestAccClient_noPersistence(t *testing.T) {
 	testAccClientPreCheck(t)
 
-	vimSessionDir, err := os.MkdirTemp("", "tf-vsphere-test-vimsessiondir")
-	if err != nil {
-		t.Fatalf("error creating VIM session temp directory: %s", err)
-	}
-	restSessionDir, err := os.MkdirTemp("", "tf-vsphere-test-restsessiondir")
-	if err != nil {
-		t.Fatalf("error creating REST session temp directory: %s", err)
-	}
-	defer func() {
-		if err = os.RemoveAll(vimSessionDir); err != nil {
-			log.Printf("[DEBUG] Error removing test VIM session directory %q: %s", vimSessionDir, err)
-		}
-	}()
-	defer func() {
-		if err = os.RemoveAll(restSessionDir); err != nil {
-			log.Printf("[DEBUG] Error removing test REST session directory %q: %s", restSessionDir, err)
-		}
-	}()
+	vimSessionDir := t.TempDir()
+	restSessionDir := t.TempDir()
 
-	c := testAccClientGenerateConfig()
+	c := testAccClientGenerateConfig(t)
 	// Just to be explicit on intent
 	c.Persist = false
 	c.VimSessionPath = vimSessionDir
+	c.RestSessionPath = restSessionDir
 
-	_, err = c.Client()
+	_, err := c.Client()
 	if err != nil {
 		t.Fatalf("error setting up client: %s", err)
 	}
@@ -211,11 +196,74 @@ func TestAccClient_noPersistence(t *testing.T) {
 	testAccClientCheckStatNoExist(t, vimSessionFile)
 }
 
+func TestAccClient_encryptedPersistence(t *testing.T) {
+	testAccClientPreCheck(t)
+
+	c := testAccClientGenerateConfig(t)
+	c.Persist = true
+	c.VimSessionPath = t.TempDir()
+	c.RestSessionPath = t.TempDir()
+	c.SessionEncryptionKey = "0123456789abcdef0123456789abcdef"
+
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error setting up client: %s", err)
+	}
+	var cookie string
+	for _, ck := range client.vimClient.Jar.Cookies(client.vimClient.URL()) {
+		if ck.Name == soap.SessionCookieName {
+			cookie = ck.Value
+		}
+	}
+
+	vimSessionFile, err := c.vimSessionFile()
+	if err != nil {
+		t.Fatalf("error computing VIM session file: %s", err)
+	}
+	restSessionFile, err := c.restSessionFile()
+	if err != nil {
+		t.Fatalf("error computing REST session file: %s", err)
+	}
+	for _, p := range []string{vimSessionFile, restSessionFile} {
+		data, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("error reading session file: %s", err)
+		}
+		if cookie != "" && strings.Contains(string(data), cookie) {
+			t.Fatalf("expected session file %q to be encrypted", p)
+		}
+	}
+
+	// The saved session should be reused.
+	reused, err := c.Client()
+	if err != nil {
+		t.Fatalf("error setting up client from saved session: %s", err)
+	}
+	if reused.restClient.SessionID() != client.restClient.SessionID() {
+		t.Fatalf("expected the saved REST session to be reused")
+	}
+
+	// A session saved with another key cannot be decrypted, and a new session
+	// is created instead.
+	nc := testAccClientGenerateConfig(t)
+	nc.Persist = true
+	nc.VimSessionPath = c.VimSessionPath
+	nc.RestSessionPath = c.RestSessionPath
+	nc.SessionEncryptionKey = "fedcba9876543210fedcba9876543210"
+	fresh, err := nc.Client()
+	if err != nil {
+		t.Fatalf("error setting up client with a different key: %s", err)
+	}
+	if fresh.restClient.SessionID() == client.restClient.SessionID() {
+		t.Fatalf("expected a new REST session to be created")
+	}
+}
+
 func TestAccClient_license(t *testing.T) {
 	testAccClientPreCheck(t)
 	testAccCheckEnvVariables(t, []string{"TF_VAR_VSPHERE_LICENSE_KEY"})
 
-	c := testAccClientGenerateConfig()
+	c := testAccClientGenerateConfig(t)
 	c.LicenseKey = os.Getenv("TF_VAR_VSPHERE_LICENSE_KEY")
 
 	client, err := c.Client()
@@ -261,3 +309,432 @@ func TestNewConfig(t *testing.T) {
 		t.Fatalf("expected %#v, got %#v", expected, actual)
 	}
 }
+
+func TestNewConfig_credentials(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings map[string]interface{}
+		err      string
+	}{
+		{
+			name:     "user and password",
+			settings: map[string]interface{}{"user": "foo", "password": "bar"},
+		},
+		{
+			name:     "saml token",
+			settings: map[string]interface{}{"saml_token": "<saml2:Assertion/>"},
+		},
+		{
+			name: "holder-of-key token",
+			settings: map[string]interface{}{
+				"saml_token":         "<saml2:Assertion/>",
+				"client_certificate": "cert.pem",
+				"client_key":         "key.pem",
+			},
+		},
+		{
+			name: "client certificate",
+			settings: map[string]interface{}{
+				"client_certificate": "cert.pem",
+				"client_key":         "key.pem",
+			},
+		},
+		{
+			name:     "session id",
+			settings: map[string]interface{}{"api_session_id": "52a3f6d0"},
+		},
+		{
+			name:     "no credentials",
+			settings: map[string]interface{}{},
+			err:      "one of user, saml_token, client_certificate or api_session_id must be provided",
+		},
+		{
+			name:     "user without password",
+			settings: map[string]interface{}{"user": "foo"},
+			err:      "password must be provided with user",
+		},
+		{
+			name:     "certificate without key",
+			settings: map[string]interface{}{"client_certificate": "cert.pem"},
+			err:      "client_certificate and client_key must be provided together",
+		},
+		{
+			name: "user and session id",
+			settings: map[string]interface{}{
+				"user":           "foo",
+				"password":       "bar",
+				"api_session_id": "52a3f6d0",
+			},
+			err: "only one of user, saml_token, client_certificate or api_session_id can be provided, got user, api_session_id",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range []string{"VSPHERE_USER", "VSPHERE_PASSWORD", "VSPHERE_SAML_TOKEN", "VSPHERE_CLIENT_CERTIFICATE", "VSPHERE_CLIENT_KEY", "VSPHERE_API_SESSION_ID"} {
+				t.Setenv(k, "")
+			}
+			r := &schema.Resource{Schema: Provider().Schema}
+			d := r.Data(nil)
+			_ = d.Set("vsphere_server", "vsphere.foo.internal")
+			for k, v := range tc.settings {
+				_ = d.Set(k, v)
+			}
+
+			_, err := NewConfig(d)
+			switch {
+			case err == nil && tc.err != "":
+				t.Fatalf("expected error %q, got none", tc.err)
+			case err != nil && err.Error() != tc.err:
+				t.Fatalf("expected error %q, got %q", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestAccClient_samlToken(t *testing.T) {
+	testAccClientPreCheck(t)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	c := testAccClientGenerateConfig(t)
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error setting up client: %s", err)
+	}
+	signer, err := c.tokenSigner(ctx, client.vimClient.Client)
+	if err != nil {
+		t.Fatalf("error issuing token: %s", err)
+	}
+
+	tc := testAccClientGenerateConfig(t)
+	tc.User = ""
+	tc.Password = ""
+	tc.SAMLToken = signer.Token
+	tokenClient, err := tc.Client()
+	if err != nil {
+		t.Fatalf("error setting up client with token: %s", err)
+	}
+	s, err := tokenClient.vimClient.SessionManager.UserSession(ctx)
+	if err != nil {
+		t.Fatalf("error reading session: %s", err)
+	}
+	if s == nil {
+		t.Fatalf("expected an authenticated SOAP session")
+	}
+}
+
+func TestAccClient_apiSessionID(t *testing.T) {
+	testAccClientPreCheck(t)
+	testAccSkipIfSimulator(t, "vcsim does not share sessions between the SOAP and REST APIs")
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	c := testAccClientGenerateConfig(t)
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error setting up client: %s", err)
+	}
+	var id string
+	for _, cookie := range client.vimClient.Jar.Cookies(client.vimClient.URL()) {
+		if cookie.Name == soap.SessionCookieName {
+			id = cookie.Value
+		}
+	}
+
+	sc := testAccClientGenerateConfig(t)
+	sc.User = ""
+	sc.Password = ""
+	sc.APISessionID = id
+	sessionClient, err := sc.Client()
+	if err != nil {
+		t.Fatalf("error setting up client with session ID: %s", err)
+	}
+	s, err := sessionClient.vimClient.SessionManager.UserSession(ctx)
+	if err != nil {
+		t.Fatalf("error reading session: %s", err)
+	}
+	if s == nil {
+		t.Fatalf("expected an authenticated SOAP session")
+	}
+}
+
+func TestNewConfig_transportSettings(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings map[string]interface{}
+		err      string
+	}{
+		{
+			name:     "defaults",
+			settings: map[string]interface{}{},
+		},
+		{
+			name:     "thumbprint",
+			settings: map[string]interface{}{"server_thumbprint": strings.Repeat("AB:", 31) + "AB"},
+		},
+		{
+			name:     "sha-1 thumbprint",
+			settings: map[string]interface{}{"server_thumbprint": strings.Repeat("AB:", 19) + "AB"},
+			err:      "is not a SHA-256 thumbprint",
+		},
+		{
+			name:     "socks proxy",
+			settings: map[string]interface{}{"proxy_url": "socks5://proxy.foo.internal:1080"},
+		},
+		{
+			name:     "unsupported proxy",
+			settings: map[string]interface{}{"proxy_url": "ftp://proxy.foo.internal"},
+			err:      "unsupported proxy scheme",
+		},
+		{
+			name:     "missing CA bundle file",
+			settings: map[string]interface{}{"ca_bundle": "does-not-exist.pem"},
+			err:      "error reading ca_bundle",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, k := range []string{"VSPHERE_CA_BUNDLE", "VSPHERE_SERVER_THUMBPRINT", "VSPHERE_PROXY_URL"} {
+				t.Setenv(k, "")
+			}
+			r := &schema.Resource{Schema: Provider().Schema}
+			d := r.Data(nil)
+			_ = d.Set("vsphere_server", "vsphere.foo.internal")
+			_ = d.Set("user", "foo")
+			_ = d.Set("password", "bar")
+			for k, v := range tc.settings {
+				_ = d.Set(k, v)
+			}
+
+			c, err := NewConfig(d)
+			if err != nil {
+				t.Fatalf("error creating config: %s", err)
+			}
+			_, err = c.transportSettings()
+			switch {
+			case err == nil && tc.err != "":
+				t.Fatalf("expected error %q, got none", tc.err)
+			case err != nil && (tc.err == "" || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected error %q, got %q", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestConfig_secretFingerprintKey(t *testing.T) {
+	c := &Config{VSphereServer: "vsphere.foo.internal", SessionEncryptionKey: "configured"}
+	key, err := c.secretFingerprintKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key != "configured" {
+		t.Fatalf("expected the session encryption key, got %q", key)
+	}
+
+	c = &Config{VSphereServer: "vsphere.foo.internal", VimSessionPath: t.TempDir()}
+	key, err = c.secretFingerprintKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key == "" || strings.Contains(strings.ToLower(key), c.VSphereServer) {
+		t.Fatalf("expected a generated key, got %q", key)
+	}
+	info, err := os.Stat(filepath.Join(c.VimSessionPath, secretFingerprintKeyFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected key file mode 0600, got %o", info.Mode().Perm())
+	}
+	if again, err := c.secretFingerprintKey(); err != nil || again != key {
+		t.Fatalf("expected the generated key to be reused, got %q, %v", again, err)
+	}
+
+	c = &Config{VSphereServer: "vsphere.foo.internal"}
+	if _, err := c.secretFingerprintKey(); err == nil {
+		t.Fatal("expected an error without a key or a session directory")
+	}
+}
+
+func TestAccClient_serverThumbprint(t *testing.T) {
+	testAccClientPreCheck(t)
+
+	c := testAccClientGenerateConfig(t)
+	u, err := c.vimURL()
+	if err != nil {
+		t.Fatalf("error generating SOAP endpoint url: %s", err)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "443")
+	}
+	conn, err := tls.Dial("tcp", host, &tls.Config{InsecureSkipVerify: true})
+	if err != nil {
+		t.Fatalf("error connecting to %s: %s", host, err)
+	}
+	thumbprint := transport.Thumbprint(conn.ConnectionState().PeerCertificates[0])
+	_ = conn.Close()
+
+	c.InsecureFlag = false
+	c.Persist = false
+	c.ServerThumbprint = thumbprint
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("error setting up client with pinned thumbprint: %s", err)
+	}
+
+	c = testAccClientGenerateConfig(t)
+	c.InsecureFlag = false
+	c.Persist = false
+	c.ServerThumbprint = strings.Repeat("00:", 31) + "00"
+	if _, err := c.Client(); err == nil || !strings.Contains(err.Error(), "does not match the pinned thumbprint") {
+		t.Fatalf("expected thumbprint mismatch, got %v", err)
+	}
+}
+
+func TestAccClient_debug(t *testing.T) {
+	testAccClientPreCheck(t)
+	defer debug.SetProvider(nil)
+
+	dir := t.TempDir()
+	c := testAccClientGenerateConfig(t)
+	c.Persist = false
+	c.Debug = true
+	c.DebugPath = dir
+	c.DebugPathRun = "dump"
+	c.DebugFormat = debuglog.FormatDump
+	if _, err := c.Client(); err != nil {
+		t.Fatalf("error setting up client: %s", err)
+	}
+	debug.Flush()
+	files, err := filepath.Glob(filepath.Join(dir, "debug", "dump", "*"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) == 0 {
+		t.Fatalf("expected debug files to be written")
+	}
+	for _, f := range files {
+		b, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if strings.Contains(string(b), "<password>"+c.Password+"</password>") {
+			t.Fatalf("%s contains the login password", f)
+		}
+	}
+
+	debug.SetProvider(nil)
+	c = testAccClientGenerateConfig(t)
+	c.Persist = false
+	c.Debug = true
+	c.DebugPath = dir
+	c.DebugPathRun = "trace"
+	c.DebugFormat = debuglog.FormatTrace
+	client, err := c.Client()
+	if err != nil {
+		t.Fatalf("error setting up client: %s", err)
+	}
+	if _, err := methods.GetCurrentTime(context.Background(), client.vimClient); err != nil {
+		t.Fatalf("error getting current time: %s", err)
+	}
+	if err := client.Close(); err != nil {
+		t.Fatalf("error closing client: %s", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "debug", "trace", debuglog.TraceFile))
+	if err != nil {
+		t.Fatalf("error reading trace: %s", err)
+	}
+	if !strings.Contains(string(b), `"method":"CurrentTime","type":"ServiceInstance"`) {
+		t.Fatalf("expected the trace to contain CurrentTime, got:\n%s", b)
+	}
+}
+
+func TestClient_limiter(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		password, _ := simulator.DefaultLogin.Password()
+		c := &Config{
+			User:           simulator.DefaultLogin.Username(),
+			Password:       password,
+			VSphereServer:  vc.URL().Host,
+			InsecureFlag:   true,
+			Debug:          true,
+			DebugFormat:    debuglog.FormatTrace,
+			DebugPath:      t.TempDir(),
+			VimSessionPath: t.TempDir(),
+			Concurrency:    viapi.ConcurrencyLimits{Clones: 1},
+		}
+		client, err := c.Client()
+		if err != nil {
+			t.Fatalf("error setting up client: %s", err)
+		}
+		defer func() { _ = client.Close() }()
+		if viapi.LimiterFromClient(client.vimClient.Client) == nil {
+			t.Fatalf("expected the limiter to be found on the client's round tripper chain")
+		}
+	})
+}
+
+func TestClient_closeTrace(t *testing.T) {
+	simulator.Test(func(ctx context.Context, vc *vim25.Client) {
+		password, _ := simulator.DefaultLogin.Password()
+		dir := t.TempDir()
+		c := &Config{
+			User:           simulator.DefaultLogin.Username(),
+			Password:       password,
+			VSphereServer:  vc.URL().Host,
+			InsecureFlag:   true,
+			Debug:          true,
+			DebugFormat:    debuglog.FormatTrace,
+			DebugPath:      dir,
+			DebugPathRun:   "trace",
+			VimSessionPath: t.TempDir(),
+		}
+		client, err := c.Client()
+		if err != nil {
+			t.Fatalf("error setting up client: %s", err)
+		}
+		if _, err := methods.GetCurrentTime(ctx, client.vimClient); err != nil {
+			t.Fatalf("error getting current time: %s", err)
+		}
+		if err := client.Close(); err != nil {
+			t.Fatalf("error closing client: %s", err)
+		}
+		p := filepath.Join(dir, "debug", "trace", debuglog.TraceFile)
+		b, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), `"method":"CurrentTime"`) {
+			t.Fatalf("expected the trace to contain CurrentTime, got:\n%s", b)
+		}
+		testClientCheckNotTraced(ctx, t, c, vc, p)
+
+		// The trace file is also closed when the client cannot be set up.
+		c.VSphereServer = "127.0.0.1:1"
+		c.DebugPathRun = "failed"
+		if _, err := c.Client(); err == nil {
+			t.Fatal("expected an error connecting to a closed port")
+		}
+		testClientCheckNotTraced(ctx, t, c, vc, filepath.Join(dir, "debug", "failed", debuglog.TraceFile))
+	})
+}
+
+// testClientCheckNotTraced checks that a call made through the tracer of c is
+// not written to the trace file at p, as the tracer is closed.
+func testClientCheckNotTraced(ctx context.Context, t *testing.T, c *Config, vc *vim25.Client, p string) {
+	t.Helper()
+	before, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := methods.GetCurrentTime(ctx, c.tracer.RoundTripper(vc)); err != nil {
+		t.Fatalf("error getting current time: %s", err)
+	}
+	after, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Fatalf("expected nothing to be traced once the tracer is closed, got:\n%s", after[len(before):])
+	}
+}
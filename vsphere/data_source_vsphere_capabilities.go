@@ -0,0 +1,134 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+func dataSourceVSphereCapabilities() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereCapabilitiesRead,
+		Schema: map[string]*schema.Schema{
+			"product": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The product name of the connected endpoint.",
+			},
+			"version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The version of the connected endpoint.",
+			},
+			"build": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The build number of the connected endpoint.",
+			},
+			"supported": {
+				Type:        schema.TypeSet,
+				Computed:    true,
+				Description: "The names of the capabilities the connected endpoint supports.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"capability": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The capabilities known to the provider.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the capability.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A short description of the capability.",
+						},
+						"supported": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the connected endpoint supports the capability.",
+						},
+						"vcenter_only": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the capability is only available on vCenter Server.",
+						},
+						"min_vcenter_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The minimum version of vCenter Server that supports the capability, if any.",
+						},
+						"min_esxi_version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The minimum version of ESXi that supports the capability, if any.",
+						},
+						"requirement": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A description of the products and versions that support the capability.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereCapabilitiesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+	about := client.ServiceContent.About
+
+	var supported []string
+	var caps []map[string]interface{}
+	for _, c := range viapi.Capabilities() {
+		err := c.Check(about)
+		if err == nil {
+			supported = append(supported, c.Name)
+		}
+		caps = append(caps, map[string]interface{}{
+			"name":                c.Name,
+			"description":         c.Description,
+			"supported":           err == nil,
+			"vcenter_only":        c.VCenterOnly,
+			"min_vcenter_version": capabilityVersionString(c.MinVCenterVersion),
+			"min_esxi_version":    capabilityVersionString(c.MinESXiVersion),
+			"requirement":         c.Requirement(),
+		})
+	}
+
+	id := about.InstanceUuid
+	if id == "" {
+		id = fmt.Sprintf("%s-%s", about.Version, about.Build)
+	}
+	d.SetId(id)
+	_ = d.Set("product", about.Name)
+	_ = d.Set("version", about.Version)
+	_ = d.Set("build", about.Build)
+	if err := d.Set("supported", supported); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting supported capabilities: %s", err))
+	}
+	if err := d.Set("capability", caps); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting capabilities: %s", err))
+	}
+	return nil
+}
+
+// capabilityVersionString returns the version number of a minimum version in
+// the capability registry, or an empty string if there is no minimum.
+func capabilityVersionString(v viapi.VSphereVersion) string {
+	if v == (viapi.VSphereVersion{}) {
+		return ""
+	}
+	return fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+}
@@ -0,0 +1,63 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+func TestAccDataSourceVSphereCapabilities_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereCapabilitiesConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet("data.vsphere_capabilities.caps", "product"),
+					resource.TestCheckResourceAttrSet("data.vsphere_capabilities.caps", "version"),
+					resource.TestCheckResourceAttr("data.vsphere_capabilities.caps", "capability.#", strconv.Itoa(len(viapi.Capabilities()))),
+					testAccDataSourceVSphereCapabilitiesMatchClient(),
+				),
+			},
+		},
+	})
+}
+
+// testAccDataSourceVSphereCapabilitiesMatchClient checks that the supported
+// capabilities in the data source are the ones the provider's client reports.
+func testAccDataSourceVSphereCapabilitiesMatchClient() resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources["data.vsphere_capabilities.caps"]
+		if !ok {
+			return fmt.Errorf("data.vsphere_capabilities.caps not found in state")
+		}
+		client := testAccProvider.Meta().(*Client).vimClient
+		for i, c := range viapi.Capabilities() {
+			if rs.Primary.Attributes[fmt.Sprintf("capability.%d.name", i)] != c.Name {
+				return fmt.Errorf("expected capability %d to be %q", i, c.Name)
+			}
+			expected := strconv.FormatBool(viapi.HasCapability(client, c.Name))
+			if actual := rs.Primary.Attributes[fmt.Sprintf("capability.%d.supported", i)]; actual != expected {
+				return fmt.Errorf("expected supported to be %s for capability %q, got %s", expected, c.Name, actual)
+			}
+		}
+		return nil
+	}
+}
+
+func testAccDataSourceVSphereCapabilitiesConfig() string {
+	return `
+data "vsphere_capabilities" "caps" {}
+`
+}
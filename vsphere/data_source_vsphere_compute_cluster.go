@@ -4,15 +4,17 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 )
 
 func dataSourceVSphereComputeCluster() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereComputeClusterRead,
+		ReadContext: dataSourceVSphereComputeClusterRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -34,14 +36,14 @@ func dataSourceVSphereComputeCluster() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereComputeClusterRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereComputeClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cluster, err := resourceVSphereComputeClusterGetClusterFromPath(meta, d.Get("name").(string), d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("error loading cluster: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading cluster: %s", err))
 	}
 	props, err := clustercomputeresource.Properties(cluster)
 	if err != nil {
-		return fmt.Errorf("error loading cluster properties: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading cluster properties: %s", err))
 	}
 
 	d.SetId(cluster.Reference().Value)
@@ -4,15 +4,17 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 )
 
 func dataSourceVSphereComputeClusterHostGroup() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereComputeClusterHostGroupRead,
+		ReadContext: dataSourceVSphereComputeClusterHostGroupRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -35,15 +37,15 @@ func dataSourceVSphereComputeClusterHostGroup() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereComputeClusterHostGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
 	if err != nil {
-		return fmt.Errorf("cannot locate resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot locate resource: %s", err))
 	}
 
 	props, err := clustercomputeresource.Properties(cluster)
 	if err != nil {
-		return fmt.Errorf("cannot read cluster properties: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot read cluster properties: %s", err))
 	}
 
 	hostSystemIDs := make([]string, len(props.Host))
@@ -53,7 +55,7 @@ func dataSourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta i
 
 	d.SetId(name)
 	if err := d.Set("host_system_ids", hostSystemIDs); err != nil {
-		return fmt.Errorf("cannot set host_system_ids: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot set host_system_ids: %s", err))
 	}
 
 	return nil
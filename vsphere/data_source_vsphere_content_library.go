@@ -4,6 +4,8 @@
 package vsphere
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
@@ -11,7 +13,7 @@ import (
 
 func dataSourceVSphereContentLibrary() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereContentLibraryRead,
+		ReadContext: dataSourceVSphereContentLibraryRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -22,11 +24,11 @@ func dataSourceVSphereContentLibrary() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereContentLibraryRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereContentLibraryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	c := meta.(*Client).restClient
 	lib, err := contentlibrary.FromName(c, d.Get("name").(string))
 	if err != nil {
-		return provider.Error(d.Get("name").(string), "dataSourceVSphereContentLibraryRead", err)
+		return diag.FromErr(provider.Error(d.Get("name").(string), "dataSourceVSphereContentLibraryRead", err))
 	}
 	d.SetId(lib.ID)
 	return nil
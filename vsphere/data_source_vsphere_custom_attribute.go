@@ -4,8 +4,10 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
 	"github.com/vmware/govmomi/object"
@@ -13,7 +15,7 @@ import (
 
 func dataSourceVSphereCustomAttribute() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereCustomAttributeRead,
+		ReadContext: dataSourceVSphereCustomAttributeRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -29,21 +31,21 @@ func dataSourceVSphereCustomAttribute() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereCustomAttributeRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereCustomAttributeRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	err := customattribute.VerifySupport(client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	fm, err := object.GetCustomFieldsManager(client.Client)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	field, err := customattribute.ByName(fm, d.Get("name").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(fmt.Sprint(field.Key))
 	_ = d.Set("managed_object_type", field.ManagedObjectType)
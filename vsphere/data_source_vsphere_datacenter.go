@@ -4,14 +4,16 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVSphereDatacenter() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatacenterRead,
+		ReadContext: dataSourceVSphereDatacenterRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -23,12 +25,12 @@ func dataSourceVSphereDatacenter() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDatacenterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	datacenter := d.Get("name").(string)
 	dc, err := getDatacenter(client, datacenter)
 	if err != nil {
-		return fmt.Errorf("error fetching datacenter: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching datacenter: %s", err))
 	}
 	id := dc.Reference().Value
 	d.SetId(id)
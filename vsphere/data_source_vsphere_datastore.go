@@ -4,8 +4,10 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/datastore"
 	"github.com/vmware/govmomi/object"
@@ -13,7 +15,7 @@ import (
 
 func dataSourceVSphereDatastore() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatastoreRead,
+		ReadContext: dataSourceVSphereDatastoreRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -30,7 +32,7 @@ func dataSourceVSphereDatastore() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDatastoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	name := d.Get("name").(string)
@@ -39,12 +41,12 @@ func dataSourceVSphereDatastoreRead(d *schema.ResourceData, meta interface{}) er
 		var err error
 		dc, err = datacenterFromID(client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.FromErr(fmt.Errorf("cannot locate datacenter: %s", err))
 		}
 	}
 	ds, err := datastore.FromPath(client, name, dc)
 	if err != nil {
-		return fmt.Errorf("error fetching datastore: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching datastore: %s", err))
 	}
 
 	d.SetId(ds.Reference().Value)
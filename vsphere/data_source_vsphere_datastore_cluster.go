@@ -4,14 +4,16 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVSphereDatastoreCluster() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDatastoreClusterRead,
+		ReadContext: dataSourceVSphereDatastoreClusterRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -28,10 +30,10 @@ func dataSourceVSphereDatastoreCluster() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDatastoreClusterRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDatastoreClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	pod, err := resourceVSphereDatastoreClusterGetPodFromPath(meta, d.Get("name").(string), d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("error loading datastore cluster: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading datastore cluster: %s", err))
 	}
 	d.SetId(pod.Reference().Value)
 	return nil
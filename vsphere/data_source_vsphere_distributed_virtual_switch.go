@@ -4,8 +4,10 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi/object"
@@ -14,7 +16,7 @@ import (
 
 func dataSourceVSphereDistributedVirtualSwitch() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDistributedVirtualSwitchRead,
+		ReadContext: dataSourceVSphereDistributedVirtualSwitchRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -37,10 +39,10 @@ func dataSourceVSphereDistributedVirtualSwitch() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDistributedVirtualSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	name := d.Get("name").(string)
@@ -49,16 +51,16 @@ func dataSourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta
 		var err error
 		dc, err = datacenterFromID(client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.FromErr(fmt.Errorf("cannot locate datacenter: %s", err))
 		}
 	}
 	dvs, err := dvsFromPath(client, name, dc)
 	if err != nil {
-		return fmt.Errorf("error fetching distributed virtual switch: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching distributed virtual switch: %s", err))
 	}
 	props, err := dvsProperties(dvs)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS properties: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching DVS properties: %s", err))
 	}
 
 	d.SetId(props.Uuid)
@@ -9,6 +9,7 @@ import (
 	"log"
 	"regexp"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vapi/tags"
@@ -16,7 +17,7 @@ import (
 
 func dataSourceVSphereDynamic() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereDynamicRead,
+		ReadContext: dataSourceVSphereDynamicRead,
 
 		Schema: map[string]*schema.Schema{
 			"filter": {
@@ -39,27 +40,27 @@ func dataSourceVSphereDynamic() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereDynamicRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereDynamicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] dataSourceDynamic: Beginning dynamic data source read.")
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	tagIds := d.Get("filter").(*schema.Set).List()
 	matches, err := filterObjectsByTag(tm, tagIds)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	filtered, err := filterObjectsByName(d, meta, matches)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	switch {
 	case len(filtered) < 1:
-		return fmt.Errorf("no matching resources found")
+		return diag.FromErr(fmt.Errorf("no matching resources found"))
 	case len(filtered) > 1:
 		log.Printf("dataSourceVSphereDynamic: Multiple matches found: %v", filtered)
-		return fmt.Errorf("multiple objects match the supplied criteria")
+		return diag.FromErr(fmt.Errorf("multiple objects match the supplied criteria"))
 	}
 	d.SetId(filtered[0])
 	log.Printf("[DEBUG] dataSourceDynamic: Read complete. Resource located: %s", filtered[0])
@@ -4,10 +4,12 @@
 package vsphere
 
 import (
+	"context"
 	"log"
 	"regexp"
 	"strconv"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/vmware/govmomi/vim25/types"
@@ -15,7 +17,7 @@ import (
 
 func dataSourceVSphereHostPciDevice() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostPciDeviceRead,
+		ReadContext: dataSourceVSphereHostPciDeviceRead,
 
 		Schema: map[string]*schema.Schema{
 			"host_id": {
@@ -47,20 +49,20 @@ func dataSourceVSphereHostPciDevice() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereHostPciDeviceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] DataHostPCIDev: Beginning PCI device lookup on %s", d.Get("host_id").(string))
 	client := meta.(*Client).vimClient
 	host, err := hostsystem.FromID(client, d.Get("host_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	hprops, err := hostsystem.Properties(host)
+	hprops, err := hostsystem.Properties(host, "hardware.pciDevice")
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	devices, err := matchName(d, hprops.Hardware.PciDevice)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] DataHostPCIDev: Looking for a device with matching class_id and vendor_id")
 	for _, device := range devices {
@@ -68,7 +70,7 @@ func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}
 		if class, exists := d.GetOk("class_id"); exists {
 			classInt, err := strconv.ParseInt(class.(string), 16, 16)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if device.ClassId != int16(classInt) {
 				continue
@@ -78,7 +80,7 @@ func dataSourceVSphereHostPciDeviceRead(d *schema.ResourceData, meta interface{}
 		if vendor, exists := d.GetOk("vendor_id"); exists {
 			vendorInt, err := strconv.ParseInt(vendor.(string), 16, 16)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			if device.VendorId != int16(vendorInt) {
 				continue
@@ -4,9 +4,11 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostservicestate"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
@@ -14,7 +16,7 @@ import (
 
 func dataSourceVSphereHostServiceState() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostServiceStateRead,
+		ReadContext: dataSourceVSphereHostServiceStateRead,
 
 		Schema: map[string]*schema.Schema{
 			"host_system_id": {
@@ -50,14 +52,14 @@ func dataSourceVSphereHostServiceState() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereHostServiceStateRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereHostServiceStateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] entering data_source_vsphere_host_service_state read function")
 
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 	hsList, err := hostservicestate.GetHostServies(client, hostID, provider.DefaultAPITimeout)
 	if err != nil {
-		return fmt.Errorf("error retrieving host services for host '%s': %s", hostID, err)
+		return diag.FromErr(fmt.Errorf("error retrieving host services for host '%s': %s", hostID, err))
 	}
 
 	srvList := make([]interface{}, 0, len(hsList))
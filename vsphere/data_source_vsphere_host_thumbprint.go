@@ -5,16 +5,18 @@ package vsphere
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha1"
 	"crypto/tls"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVSphereHostThumbprint() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereHostThumbprintRead,
+		ReadContext: dataSourceVSphereHostThumbprintRead,
 		Schema: map[string]*schema.Schema{
 			"address": {
 				Type:        schema.TypeString,
@@ -36,12 +38,12 @@ func dataSourceVSphereHostThumbprint() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereHostThumbprintRead(d *schema.ResourceData, _ interface{}) error {
+func dataSourceVSphereHostThumbprintRead(ctx context.Context, d *schema.ResourceData, _ interface{}) diag.Diagnostics {
 	config := &tls.Config{}
 	config.InsecureSkipVerify = d.Get("insecure").(bool)
 	conn, err := tls.Dial("tcp", d.Get("address").(string)+":"+d.Get("port").(string), config)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cert := conn.ConnectionState().PeerCertificates[0]
 	fingerprint := sha1.Sum(cert.Raw)
@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func dataSourceVSphereIscsiSoftwareAdapter() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereIscsiSoftwareAdapterRead,
+		ReadContext: dataSourceVSphereIscsiSoftwareAdapterRead,
 
 		Schema: map[string]*schema.Schema{
 			"host_system_id": {
@@ -26,10 +28,10 @@ func dataSourceVSphereIscsiSoftwareAdapter() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereIscsiSoftwareAdapterRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereIscsiSoftwareAdapterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := iscsiSoftwareAdapterRead(d, meta, true)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(d.Get("host_system_id").(string))
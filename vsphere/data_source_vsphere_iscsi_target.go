@@ -4,15 +4,17 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/iscsi"
 )
 
 func dataSourceVSphereIscsiTarget() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereIscsiTargetRead,
+		ReadContext: dataSourceVSphereIscsiTargetRead,
 
 		Schema: map[string]*schema.Schema{
 			"host_system_id": {
@@ -54,7 +56,7 @@ func dataSourceVSphereIscsiTarget() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereIscsiTargetRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereIscsiTargetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	hostID := d.Get("host_system_id").(string)
 	adapterID := d.Get("adapter_id").(string)
 
@@ -66,7 +68,7 @@ func dataSourceVSphereIscsiTargetRead(d *schema.ResourceData, meta interface{})
 		true,
 	)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%s:%s", hostID, adapterID))
@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/identitysource"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+)
+
+func dataSourceVSphereLDAPIdentitySource() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereLDAPIdentitySourceRead,
+		Schema: map[string]*schema.Schema{
+			"domain_name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The domain name of the LDAP identity source.",
+			},
+			"ldap_password": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Sensitive:   true,
+				Description: "The password of the account that the identity source binds with, or file:// followed by the path of a local file that contains it. Only a fingerprint of the password is saved in state.",
+			},
+			"domain_alias": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The alias of the domain.",
+			},
+			"server_type": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The type of LDAP server, such as ActiveDirectory or OpenLdap.",
+			},
+			"friendly_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The friendly name of the identity source.",
+			},
+			"user_base_dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The base distinguished name of users.",
+			},
+			"group_base_dn": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The base distinguished name of groups.",
+			},
+			"primary_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the primary LDAP server.",
+			},
+			"failover_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The URL of the failover LDAP server.",
+			},
+			"ldap_username": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The account that the identity source binds with.",
+			},
+			"default_domain": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the identity source is the default domain.",
+			},
+			"healthy": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether vCenter could bind to every LDAP server of the identity source.",
+			},
+			"servers": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The result of the probe of each LDAP server of the identity source.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The URL of the LDAP server.",
+						},
+						"connected": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether vCenter could bind to the LDAP server.",
+						},
+						"error": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The error of the probe, if vCenter could not bind to the LDAP server.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereLDAPIdentitySourceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	name := d.Get("domain_name").(string)
+	identitySource, err := identitySourceExists(ssoclient, name)
+	if err != nil {
+		if errors.Is(err, identitynotfound) {
+			return diag.FromErr(fmt.Errorf("ldap identity source %s not found", name))
+		}
+		return diag.FromErr(err)
+	}
+	password, err := secret.Get(d, "ldap_password")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(identitySource.Name)
+	_ = d.Set("domain_alias", ldapIdentitySourceAlias(identitySource))
+	_ = d.Set("server_type", identitySource.Type)
+	_ = d.Set("friendly_name", identitySource.Details.FriendlyName)
+	_ = d.Set("user_base_dn", identitySource.Details.UserBaseDn)
+	_ = d.Set("group_base_dn", identitySource.Details.GroupBaseDn)
+	_ = d.Set("primary_url", identitySource.Details.PrimaryURL)
+	_ = d.Set("failover_url", identitySource.Details.FailoverURL)
+	_ = d.Set("ldap_username", identitySource.AuthenticationDetails.Username)
+	_ = d.Set("ldap_password", secret.Fingerprint(d.Get("ldap_password").(string)))
+
+	defaultDomain, err := ldapIdentitySourceIsDefault(ctx, ssoclient, identitySource.Name)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	_ = d.Set("default_domain", defaultDomain)
+
+	healthy := true
+	var servers []interface{}
+	for _, url := range []string{identitySource.Details.PrimaryURL, identitySource.Details.FailoverURL} {
+		if url == "" {
+			continue
+		}
+		server := map[string]interface{}{
+			"url":       url,
+			"connected": true,
+			"error":     "",
+		}
+		if err := identitysource.Probe(ctx, ssoclient, url, identitySource.AuthenticationDetails.Username, password); err != nil {
+			log.Printf("[DEBUG] vCenter could not bind to %s: %s", url, err)
+			healthy = false
+			server["connected"] = false
+			server["error"] = err.Error()
+		}
+		servers = append(servers, server)
+	}
+	_ = d.Set("healthy", healthy)
+	if err := d.Set("servers", servers); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting servers: %s", err))
+	}
+
+	return nil
+}
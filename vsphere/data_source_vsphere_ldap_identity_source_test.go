@@ -0,0 +1,51 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+)
+
+func TestAccDataSourceVSphereLdapIdentitySource_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccCheckEnvVariables(t, []string{"ldap_username", "ldap_password", "domain_name", "domain_alias", "user_base_dn", "group_base_dn", "primary_url"})
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereLdapIdentitySourceConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vsphere_ldap_identity_source.test", "id", os.Getenv("domain_name")),
+					resource.TestCheckResourceAttr("data.vsphere_ldap_identity_source.test", "primary_url", os.Getenv("primary_url")),
+					resource.TestCheckResourceAttr("data.vsphere_ldap_identity_source.test", "ldap_password", secret.Fingerprint(os.Getenv("ldap_password"))),
+					resource.TestCheckResourceAttr("data.vsphere_ldap_identity_source.test", "healthy", "true"),
+					resource.TestCheckResourceAttr("data.vsphere_ldap_identity_source.test", "servers.#", "1"),
+					resource.TestCheckResourceAttr("data.vsphere_ldap_identity_source.test", "servers.0.connected", "true"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereLdapIdentitySourceConfig() string {
+	return fmt.Sprintf(`
+%s
+
+data "vsphere_ldap_identity_source" "test" {
+  domain_name   = vsphere_ldap_identity_source.test.domain_name
+  ldap_password = "%s"
+}
+`,
+		testAccResourceVSphereLdapIdentitySourceConfig("test_friendly_name"),
+		os.Getenv("ldap_password"),
+	)
+}
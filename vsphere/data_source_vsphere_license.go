@@ -4,15 +4,17 @@
 package vsphere
 
 import (
+	"context"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/license"
 )
 
 func dataSourceVSphereLicense() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereLicenseRead,
+		ReadContext: dataSourceVSphereLicenseRead,
 
 		Schema: map[string]*schema.Schema{
 			"license_key": {
@@ -50,23 +52,23 @@ func dataSourceVSphereLicense() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereLicenseRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereLicenseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 
 	client := meta.(*Client).vimClient
 	manager := license.NewManager(client.Client)
 	licenseKey := d.Get("license_key").(string)
-	if info := getLicenseInfoFromKey(d.Get("license_key").(string), manager); info != nil {
+	if info := getLicenseInfoFromKey(ctx, d.Get("license_key").(string), manager); info != nil {
 		log.Println("[INFO] Setting the values")
 		d.Set("edition_key", info.EditionKey)
 		d.Set("total", info.Total)
 		d.Set("used", info.Used)
 		d.Set("name", info.Name)
 		if err := d.Set("labels", keyValuesToMap(info.Labels)); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		d.SetId(licenseKey)
 		return nil
 	} else {
-		return ErrNoSuchKeyFound
+		return diag.FromErr(ErrNoSuchKeyFound)
 	}
 }
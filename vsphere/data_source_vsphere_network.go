@@ -4,8 +4,10 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/network"
 	"github.com/vmware/govmomi/object"
@@ -13,7 +15,7 @@ import (
 
 func dataSourceVSphereNetwork() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereNetworkRead,
+		ReadContext: dataSourceVSphereNetworkRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -40,7 +42,7 @@ func dataSourceVSphereNetwork() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereNetworkRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	name := d.Get("name").(string)
@@ -50,12 +52,12 @@ func dataSourceVSphereNetworkRead(d *schema.ResourceData, meta interface{}) erro
 		var err error
 		dc, err = datacenterFromID(client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.FromErr(fmt.Errorf("cannot locate datacenter: %s", err))
 		}
 	}
 	net, err := network.FromNameAndDVSUuid(client, name, dc, dvSwitchUUID)
 	if err != nil {
-		return fmt.Errorf("error fetching network: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching network: %s", err))
 	}
 
 	d.SetId(net.Reference().Value)
@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"reflect"
 
@@ -13,6 +14,7 @@ import (
 
 	"github.com/vmware/govmomi/vim25/types"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/vmworkflow"
@@ -139,8 +141,8 @@ func dataSourceVSphereOvfVMTemplate() *schema.Resource {
 	structure.MergeSchema(s, vmConfigSpecSchema)
 
 	return &schema.Resource{
-		Read:   dataSourceVSphereOvfVMTemplateRead,
-		Schema: s,
+		ReadContext: dataSourceVSphereOvfVMTemplateRead,
+		Schema:      s,
 	}
 }
 
@@ -163,17 +165,18 @@ func NewOvfHelperParamsFromVMDatasource(d *schema.ResourceData) *ovfdeploy.OvfHe
 	return ovfParams
 }
 
-func dataSourceVSphereOvfVMTemplateRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereOvfVMTemplateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	ovfParams := NewOvfHelperParamsFromVMDatasource(d)
+	ovfParams.Transport = meta.(*Client).transport
 	ovfHelper, err := ovfdeploy.NewOvfHelper(client, ovfParams)
 	if err != nil {
-		return fmt.Errorf("while extracting OVF parameters: %s", err)
+		return diag.FromErr(fmt.Errorf("while extracting OVF parameters: %s", err))
 	}
 
 	is, err := ovfHelper.GetImportSpec(client)
 	if err != nil {
-		return fmt.Errorf("while retrieving import spec: %s", err)
+		return diag.FromErr(fmt.Errorf("while retrieving import spec: %s", err))
 	}
 
 	vmConfigSpec := is.ImportSpec.(*types.VirtualMachineImportSpec).ConfigSpec
@@ -201,21 +204,21 @@ func dataSourceVSphereOvfVMTemplateRead(d *schema.ResourceData, meta interface{}
 			if scsiType == "" {
 				scsiType = "lsilogic"
 			} else if scsiType != "lsilogic" {
-				return fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic")
+				return diag.FromErr(fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic"))
 			}
 			controllers["scsi"]++
 		case reflect.TypeOf(&types.VirtualLsiLogicSASController{}):
 			if scsiType == "" {
 				scsiType = "lsilogic-sas"
 			} else if scsiType != "lsilogic-sas" {
-				return fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic-sas")
+				return diag.FromErr(fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "lsilogic-sas"))
 			}
 			controllers["scsi"]++
 		case reflect.TypeOf(&types.ParaVirtualSCSIController{}):
 			if scsiType == "" {
 				scsiType = "pvscsi"
 			} else if scsiType != "pvscsi" {
-				return fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "pvsci")
+				return diag.FromErr(fmt.Errorf("multiple scsi controller types are not supported (found %s and %s)", scsiType, "pvsci"))
 			}
 			controllers["scsi"]++
 		case reflect.TypeOf(&types.VirtualSATAController{}):
@@ -4,8 +4,10 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
@@ -14,7 +16,7 @@ import (
 
 func dataSourceVSphereResourcePool() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereResourcePoolRead,
+		ReadContext: dataSourceVSphereResourcePoolRead,
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -31,13 +33,13 @@ func dataSourceVSphereResourcePool() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereResourcePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	name := d.Get("name").(string)
 	if err := viapi.ValidateVirtualCenter(client); err == nil {
 		if name == "" {
-			return fmt.Errorf("name cannot be empty when using vCenter")
+			return diag.FromErr(fmt.Errorf("name cannot be empty when using vCenter"))
 		}
 	}
 
@@ -46,12 +48,12 @@ func dataSourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{})
 		var err error
 		dc, err = datacenterFromID(client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return diag.FromErr(fmt.Errorf("cannot locate datacenter: %s", err))
 		}
 	}
 	rp, err := resourcepool.FromPathOrDefault(client, name, dc)
 	if err != nil {
-		return fmt.Errorf("error fetching resource pool: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching resource pool: %s", err))
 	}
 
 	d.SetId(rp.Reference().Value)
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
@@ -17,7 +18,7 @@ import (
 
 func dataSourceVsphereRole() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereRoleRead,
+		ReadContext: dataSourceVSphereRoleRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -44,15 +45,15 @@ func dataSourceVsphereRole() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereRoleRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] : Reading vsphere role with label %s", d.Get("label"))
 	client := meta.(*Client).vimClient
 	authorizationManager := object.NewAuthorizationManager(client.Client)
 
 	label := d.Get("label").(string)
-	roleList, err := authorizationManager.RoleList(context.Background())
+	roleList, err := authorizationManager.RoleList(ctx)
 	if err != nil {
-		return fmt.Errorf("error while fetching the role list %s", err)
+		return diag.FromErr(fmt.Errorf("error while fetching the role list %s", err))
 	}
 	var foundRole = types.AuthorizationRole{}
 	for _, role := range roleList {
@@ -64,7 +65,7 @@ func dataSourceVSphereRoleRead(d *schema.ResourceData, meta interface{}) error {
 	}
 
 	if foundRole.RoleId == 0 {
-		return fmt.Errorf("role with label %s not found", label)
+		return diag.FromErr(fmt.Errorf("role with label %s not found", label))
 	}
 
 	d.SetId(strconv.Itoa(int(foundRole.RoleId)))
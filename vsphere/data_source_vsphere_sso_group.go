@@ -0,0 +1,108 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+func dataSourceVSphereSSOGroup() *schema.Resource {
+	memberSchema := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: description,
+			Elem:        &schema.Schema{Type: schema.TypeString},
+		}
+	}
+
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereSSOGroupRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the group, without the domain.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The domain of the group. Defaults to the system domain, such as vsphere.local.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the group.",
+			},
+			"local": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the group is in the system domain, rather than in an external identity source.",
+			},
+			"member_of":      memberSchema("The groups that the group is a member of, directly or through other groups, in the form name@domain."),
+			"users":          memberSchema("The users that are direct members of the group, in the form name@domain."),
+			"groups":         memberSchema("The groups that are direct members of the group, in the form name@domain."),
+			"solution_users": memberSchema("The solution users that are direct members of the group, in the form name@domain."),
+		},
+	}
+}
+
+func dataSourceVSphereSSOGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	principal := ssoadmin_types.PrincipalId{
+		Name:   d.Get("name").(string),
+		Domain: ssoclient.Domain,
+	}
+	if domain, ok := d.GetOk("domain"); ok {
+		principal.Domain = domain.(string)
+	}
+	id := ssoPrincipalIDString(principal)
+	group, err := ssoclient.FindGroup(ctx, id)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading SSO group %s: %s", id, err))
+	}
+	if group == nil {
+		return diag.FromErr(fmt.Errorf("SSO group %s not found", id))
+	}
+	memberOf, err := ssoParentGroups(ctx, ssoclient, group.Id, true)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	users, groups, solutionUsers, err := ssoGroupMembers(ctx, ssoclient, ssoPrincipalIDString(group.Id))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(ssoPrincipalIDString(group.Id))
+	_ = d.Set("name", group.Id.Name)
+	_ = d.Set("domain", group.Id.Domain)
+	_ = d.Set("description", group.Details.Description)
+	_ = d.Set("local", ssoIsLocal(ssoclient, group.Id.Domain))
+	for k, v := range map[string][]string{
+		"member_of":      memberOf,
+		"users":          users,
+		"groups":         groups,
+		"solution_users": solutionUsers,
+	} {
+		sort.Strings(v)
+		if err := d.Set(k, v); err != nil {
+			return diag.FromErr(fmt.Errorf("error setting %s: %s", k, err))
+		}
+	}
+
+	return nil
+}
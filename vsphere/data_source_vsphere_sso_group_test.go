@@ -0,0 +1,58 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceVSphereSSOGroup_basic(t *testing.T) {
+	testAccSkipIfSimulator(t, "vcsim does not implement FindUsersInGroup or FindDirectParentGroups")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereSSOGroupConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_group.group", "id", testAccResourceVSphereSSOGroupName, "id"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_group.group", "description", "Service accounts"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_group.group", "local", "true"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_group.group", "users.#", "1"),
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_group.group", "users.0", testAccResourceVSphereSSOUserName, "id"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_group.group", "member_of.#", "0"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_user.user", "member_of.#", "1"),
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_user.user", "member_of.0", testAccResourceVSphereSSOGroupName, "id"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereSSOGroupConfig() string {
+	return testAccResourceVSphereSSOGroupConfig("Service accounts") +
+		testAccResourceVSphereSSOUserConfig("Terraform-1!", "Test") + `
+resource "vsphere_sso_group_members" "members" {
+  group = vsphere_sso_group.group.id
+  users = [vsphere_sso_user.user.id]
+}
+
+data "vsphere_sso_group" "group" {
+  name = vsphere_sso_group.group.name
+
+  depends_on = [vsphere_sso_group_members.members]
+}
+
+data "vsphere_sso_user" "user" {
+  name = vsphere_sso_user.user.name
+
+  depends_on = [vsphere_sso_group_members.members]
+}
+`
+}
@@ -0,0 +1,234 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/govmomi/ssoadmin/methods"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+const (
+	ssoPrincipalTypeUser         = "user"
+	ssoPrincipalTypeGroup        = "group"
+	ssoPrincipalTypeSolutionUser = "solution_user"
+)
+
+var ssoPrincipalTypes = []string{ssoPrincipalTypeUser, ssoPrincipalTypeGroup, ssoPrincipalTypeSolutionUser}
+
+func dataSourceVSphereSSOPrincipals() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereSSOPrincipalsRead,
+		Schema: map[string]*schema.Schema{
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The domain to search. Defaults to the system domain, such as vsphere.local.",
+			},
+			"search": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "A string that SSO searches for in the names of principals. By default, all principals of the domain are found.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Description:  "A regular expression that the names of the principals must match, without the domain.",
+				ValidateFunc: validation.StringIsValidRegExp,
+			},
+			"types": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "The types of principals to find. Can include user, group and solution_user. By default, all types are found.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: validation.StringInSlice(ssoPrincipalTypes, false),
+				},
+			},
+			"include_membership": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Whether to look up the groups of each principal in member_of. This takes one or more requests for each principal found.",
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The IDs of the principals that were found, in the form name@domain.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"principals": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The principals that were found.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The ID of the principal, in the form name@domain.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The name of the principal.",
+						},
+						"domain": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The domain of the principal.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The type of the principal: user, group or solution_user.",
+						},
+						"local": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the principal is in the system domain, rather than in an external identity source.",
+						},
+						"disabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Whether the user or solution user is disabled. Always false for a group.",
+						},
+						"member_of": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "The groups that the principal is a member of, directly or through other groups, in the form name@domain. Only set if include_membership is true.",
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// ssoPrincipal is a principal found by vsphere_sso_principals.
+type ssoPrincipal struct {
+	id       ssoadmin_types.PrincipalId
+	kind     string
+	disabled bool
+}
+
+func dataSourceVSphereSSOPrincipalsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	domain := ssoclient.Domain
+	if v, ok := d.GetOk("domain"); ok {
+		domain = v.(string)
+	}
+	search := d.Get("search").(string)
+	types := ssoPrincipalTypes
+	if v, ok := d.GetOk("types"); ok {
+		types = structure.SliceInterfacesToStrings(v.(*schema.Set).List())
+	}
+	includeMembership := d.Get("include_membership").(bool)
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+
+	criteria := ssoadmin_types.AdminPrincipalDiscoveryServiceSearchCriteria{
+		Domain:       domain,
+		SearchString: search,
+	}
+	var found []ssoPrincipal
+	for _, kind := range types {
+		switch kind {
+		case ssoPrincipalTypeUser:
+			res, err := methods.FindPersonUsers(ctx, ssoclient, &ssoadmin_types.FindPersonUsers{
+				This:     ssoclient.ServiceContent.PrincipalDiscoveryService,
+				Criteria: criteria,
+				Limit:    ssoclient.Limit,
+			})
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error searching SSO users in %s: %s", domain, err))
+			}
+			for _, user := range res.Returnval {
+				found = append(found, ssoPrincipal{id: user.Id, kind: kind, disabled: user.Disabled})
+			}
+		case ssoPrincipalTypeGroup:
+			res, err := methods.FindGroups(ctx, ssoclient, &ssoadmin_types.FindGroups{
+				This:     ssoclient.ServiceContent.PrincipalDiscoveryService,
+				Criteria: criteria,
+				Limit:    ssoclient.Limit,
+			})
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error searching SSO groups in %s: %s", domain, err))
+			}
+			for _, group := range res.Returnval {
+				found = append(found, ssoPrincipal{id: group.Id, kind: kind})
+			}
+		case ssoPrincipalTypeSolutionUser:
+			// solution users only exist in the system domain
+			if !ssoIsLocal(ssoclient, domain) {
+				continue
+			}
+			users, err := ssoclient.FindSolutionUsers(ctx, search)
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error searching SSO solution users: %s", err))
+			}
+			for _, user := range users {
+				found = append(found, ssoPrincipal{id: user.Id, kind: kind, disabled: user.Disabled})
+			}
+		}
+	}
+
+	sort.Slice(found, func(i, j int) bool {
+		return ssoPrincipalIDString(found[i].id) < ssoPrincipalIDString(found[j].id)
+	})
+	ids := make([]string, 0, len(found))
+	principals := make([]interface{}, 0, len(found))
+	for _, principal := range found {
+		if nameRegex != nil && !nameRegex.MatchString(principal.id.Name) {
+			continue
+		}
+		var memberOf []string
+		if includeMembership {
+			memberOf, err = ssoParentGroups(ctx, ssoclient, principal.id, principal.kind == ssoPrincipalTypeGroup)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+		}
+		id := ssoPrincipalIDString(principal.id)
+		ids = append(ids, id)
+		principals = append(principals, map[string]interface{}{
+			"id":        id,
+			"name":      principal.id.Name,
+			"domain":    principal.id.Domain,
+			"type":      principal.kind,
+			"local":     ssoIsLocal(ssoclient, principal.id.Domain),
+			"disabled":  principal.disabled,
+			"member_of": memberOf,
+		})
+	}
+
+	d.SetId(fmt.Sprintf("%s:%s", domain, search))
+	_ = d.Set("domain", domain)
+	if err := d.Set("ids", ids); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting ids: %s", err))
+	}
+	if err := d.Set("principals", principals); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting principals: %s", err))
+	}
+
+	return nil
+}
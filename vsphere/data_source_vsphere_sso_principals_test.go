@@ -0,0 +1,73 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceVSphereSSOPrincipals_basic(t *testing.T) {
+	testAccSkipIfSimulator(t, "vcsim does not implement FindParentGroups")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereSSOPrincipalsConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.users", "ids.#", "1"),
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_principals.users", "ids.0", testAccResourceVSphereSSOUserName, "id"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.users", "principals.0.name", "terraform-test-user"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.users", "principals.0.type", "user"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.users", "principals.0.local", "true"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.users", "principals.0.disabled", "false"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.users", "principals.0.member_of.#", "0"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.membership", "principals.0.member_of.#", "1"),
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_principals.membership", "principals.0.member_of.0", testAccResourceVSphereSSOGroupName, "id"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_principals.none", "ids.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereSSOPrincipalsConfig() string {
+	return testAccResourceVSphereSSOGroupConfig("Service accounts") +
+		testAccResourceVSphereSSOUserConfig("Terraform-1!", "Test") + `
+resource "vsphere_sso_group_members" "members" {
+  group = vsphere_sso_group.group.id
+  users = [vsphere_sso_user.user.id]
+}
+
+data "vsphere_sso_principals" "users" {
+  search     = "terraform-test"
+  name_regex = "-user$"
+  types      = ["user"]
+
+  depends_on = [vsphere_sso_group_members.members]
+}
+
+data "vsphere_sso_principals" "membership" {
+  search             = "terraform-test"
+  name_regex         = "-user$"
+  types              = ["user"]
+  include_membership = true
+
+  depends_on = [vsphere_sso_group_members.members]
+}
+
+data "vsphere_sso_principals" "none" {
+  search     = "terraform-test"
+  name_regex = "^nomatch"
+  types      = ["user"]
+
+  depends_on = [vsphere_sso_group_members.members]
+}
+`
+}
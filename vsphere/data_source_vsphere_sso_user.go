@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+func dataSourceVSphereSSOUser() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereSSOUserRead,
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The name of the user, without the domain.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The domain of the user. Defaults to the system domain, such as vsphere.local.",
+			},
+			"first_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The first name of the user.",
+			},
+			"last_name": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The last name of the user.",
+			},
+			"email_address": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The email address of the user.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The description of the user.",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user is disabled.",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user is locked out.",
+			},
+			"local": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user is in the system domain, rather than in an external identity source.",
+			},
+			"member_of": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The groups that the user is a member of, directly or through other groups, in the form name@domain.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+		},
+	}
+}
+
+func dataSourceVSphereSSOUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	principal := ssoadmin_types.PrincipalId{
+		Name:   d.Get("name").(string),
+		Domain: ssoclient.Domain,
+	}
+	if domain, ok := d.GetOk("domain"); ok {
+		principal.Domain = domain.(string)
+	}
+	id := ssoPrincipalIDString(principal)
+	user, err := ssoclient.FindPersonUser(ctx, id)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading SSO user %s: %s", id, err))
+	}
+	if user == nil {
+		return diag.FromErr(fmt.Errorf("SSO user %s not found", id))
+	}
+	memberOf, err := ssoParentGroups(ctx, ssoclient, user.Id, false)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(ssoPrincipalIDString(user.Id))
+	_ = d.Set("name", user.Id.Name)
+	_ = d.Set("domain", user.Id.Domain)
+	_ = d.Set("first_name", user.Details.FirstName)
+	_ = d.Set("last_name", user.Details.LastName)
+	_ = d.Set("email_address", user.Details.EmailAddress)
+	_ = d.Set("description", user.Details.Description)
+	_ = d.Set("disabled", user.Disabled)
+	_ = d.Set("locked", user.Locked)
+	_ = d.Set("local", ssoIsLocal(ssoclient, user.Id.Domain))
+	if err := d.Set("member_of", memberOf); err != nil {
+		return diag.FromErr(fmt.Errorf("error setting member_of: %s", err))
+	}
+
+	return nil
+}
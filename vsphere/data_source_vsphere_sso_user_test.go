@@ -0,0 +1,41 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccDataSourceVSphereSSOUser_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccDataSourceVSphereSSOUserConfig(),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_user.user", "id", testAccResourceVSphereSSOUserName, "id"),
+					resource.TestCheckResourceAttrPair("data.vsphere_sso_user.user", "domain", testAccResourceVSphereSSOUserName, "domain"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_user.user", "first_name", "Test"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_user.user", "disabled", "false"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_user.user", "local", "true"),
+					resource.TestCheckResourceAttr("data.vsphere_sso_user.user", "member_of.#", "0"),
+				),
+			},
+		},
+	})
+}
+
+func testAccDataSourceVSphereSSOUserConfig() string {
+	return testAccResourceVSphereSSOUserConfig("Terraform-1!", "Test") + `
+data "vsphere_sso_user" "user" {
+  name = vsphere_sso_user.user.name
+}
+`
+}
@@ -3,11 +3,15 @@
 
 package vsphere
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func dataSourceVSphereTag() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereTagRead,
+		ReadContext: dataSourceVSphereTagRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -28,10 +32,10 @@ func dataSourceVSphereTag() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereTagRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereTagRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	name := d.Get("name").(string)
@@ -39,9 +43,9 @@ func dataSourceVSphereTagRead(d *schema.ResourceData, meta interface{}) error {
 
 	tagID, err := tagByName(tm, name, categoryID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(tagID)
-	return resourceVSphereTagRead(d, meta)
+	return resourceVSphereTagRead(ctx, d, meta)
 }
@@ -3,11 +3,15 @@
 
 package vsphere
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func dataSourceVSphereTagCategory() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereTagCategoryRead,
+		ReadContext: dataSourceVSphereTagCategoryRead,
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -34,17 +38,17 @@ func dataSourceVSphereTagCategory() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereTagCategoryRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereTagCategoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := tagCategoryByName(tm, d.Get("name").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(id)
-	return resourceVSphereTagCategoryRead(d, meta)
+	return resourceVSphereTagCategoryRead(ctx, d, meta)
 }
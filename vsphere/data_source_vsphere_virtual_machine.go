@@ -4,9 +4,11 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -168,12 +170,12 @@ func dataSourceVSphereVirtualMachine() *schema.Resource {
 	// Now that the schema has been composed and merged, we can attach our reader and
 	// return the resource back to our host process.
 	return &schema.Resource{
-		Read:   dataSourceVSphereVirtualMachineRead,
-		Schema: s,
+		ReadContext: dataSourceVSphereVirtualMachineRead,
+		Schema:      s,
 	}
 }
 
-func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereVirtualMachineRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	uuid := d.Get("uuid").(string)
 	moid := d.Get("moid").(string)
@@ -193,7 +195,7 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 		if dcID, ok := d.GetOk("datacenter_id"); ok {
 			dc, err = datacenterFromID(client, dcID.(string))
 			if err != nil {
-				return fmt.Errorf("cannot locate datacenter: %s", err)
+				return diag.FromErr(fmt.Errorf("cannot locate datacenter: %s", err))
 			}
 			log.Printf("[DEBUG] Datacenter for VM/template search: %s", dc.InventoryPath)
 		}
@@ -201,28 +203,28 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 	}
 
 	if err != nil {
-		return fmt.Errorf("error fetching virtual machine: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching virtual machine: %s", err))
 	}
 
 	// Set the managed object id.
 	d.Set("moid", vm.Reference().Value)
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config", "guest")
 	if err != nil {
-		return fmt.Errorf("error fetching virtual machine properties: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching virtual machine properties: %s", err))
 	}
 
 	if props.Config == nil {
-		return fmt.Errorf("no configuration returned for virtual machine %q", vm.InventoryPath)
+		return diag.FromErr(fmt.Errorf("no configuration returned for virtual machine %q", vm.InventoryPath))
 	}
 
 	if props.Config.Uuid == "" {
-		return fmt.Errorf("virtual machine %q does not have a UUID", vm.InventoryPath)
+		return diag.FromErr(fmt.Errorf("virtual machine %q does not have a UUID", vm.InventoryPath))
 	}
 
 	// Read general VM config info
 	if err := flattenVirtualMachineConfigInfo(d, props.Config, client); err != nil {
-		return fmt.Errorf("error reading virtual machine configuration: %s", err)
+		return diag.FromErr(fmt.Errorf("error reading virtual machine configuration: %s", err))
 	}
 
 	d.SetId(props.Config.Uuid)
@@ -233,28 +235,28 @@ func dataSourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{
 	_ = d.Set("firmware", props.Config.Firmware)
 	disks, err := virtualdevice.ReadDiskAttrsForDataSource(object.VirtualDeviceList(props.Config.Hardware.Device), d)
 	if err != nil {
-		return fmt.Errorf("error reading disk sizes: %s", err)
+		return diag.FromErr(fmt.Errorf("error reading disk sizes: %s", err))
 	}
 	nics, err := virtualdevice.ReadNetworkInterfaceTypes(object.VirtualDeviceList(props.Config.Hardware.Device))
 	if err != nil {
-		return fmt.Errorf("error reading network interface types: %s", err)
+		return diag.FromErr(fmt.Errorf("error reading network interface types: %s", err))
 	}
 	networkInterfaces, err := virtualdevice.ReadNetworkInterfaces(object.VirtualDeviceList(props.Config.Hardware.Device))
 	if err != nil {
-		return fmt.Errorf("error reading network interfaces: %s", err)
+		return diag.FromErr(fmt.Errorf("error reading network interfaces: %s", err))
 	}
 	if err := d.Set("disks", disks); err != nil {
-		return fmt.Errorf("error setting disk sizes: %s", err)
+		return diag.FromErr(fmt.Errorf("error setting disk sizes: %s", err))
 	}
 	if err := d.Set("network_interface_types", nics); err != nil {
-		return fmt.Errorf("error setting network interface types: %s", err)
+		return diag.FromErr(fmt.Errorf("error setting network interface types: %s", err))
 	}
 	if err := d.Set("network_interfaces", networkInterfaces); err != nil {
-		return fmt.Errorf("error setting network interfaces: %s", err)
+		return diag.FromErr(fmt.Errorf("error setting network interfaces: %s", err))
 	}
 	if props.Guest != nil {
 		if err := buildAndSelectGuestIPs(d, *props.Guest); err != nil {
-			return fmt.Errorf("error setting guest IP addresses: %s", err)
+			return diag.FromErr(fmt.Errorf("error setting guest IP addresses: %s", err))
 		}
 	}
 	log.Printf("[DEBUG] VM search for %q completed successfully (UUID %q)", name, props.Config.Uuid)
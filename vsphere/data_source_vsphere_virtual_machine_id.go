@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+)
+
+func dataSourceVSphereVirtualMachineID() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVSphereVirtualMachineIDRead,
+		Schema: map[string]*schema.Schema{
+			"uuid": {
+				Type:         schema.TypeString,
+				Description:  "The UUID of the virtual machine. Either this or moid must be set.",
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"uuid", "moid"},
+			},
+			"moid": {
+				Type:        schema.TypeString,
+				Description: "The managed object ID of the virtual machine. Either this or uuid must be set.",
+				Optional:    true,
+				Computed:    true,
+			},
+		},
+	}
+}
+
+func dataSourceVSphereVirtualMachineIDRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	client := meta.(*Client).vimClient
+
+	var result virtualmachine.MOIDForUUIDResult
+	var err error
+	if uuid, ok := d.GetOk("uuid"); ok {
+		result, err = virtualmachine.MOIDForUUID(client, uuid.(string))
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("cannot locate virtual machine with UUID %q: %s", uuid, err))
+		}
+	} else {
+		moid := d.Get("moid").(string)
+		result, err = virtualmachine.UUIDForMOID(client, moid)
+		if err != nil {
+			return diag.FromErr(fmt.Errorf("cannot locate virtual machine with managed object ID %q: %s", moid, err))
+		}
+	}
+
+	d.SetId(result.MOID)
+	_ = d.Set("uuid", result.UUID)
+	_ = d.Set("moid", result.MOID)
+
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+)
+
+func TestDataSourceVSphereVirtualMachineID(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var props mo.VirtualMachine
+		if err := vm.Properties(ctx, vm.Reference(), []string{"config.uuid"}, &props); err != nil {
+			t.Fatal(err)
+		}
+		moid, uuid := vm.Reference().Value, props.Config.Uuid
+
+		p := testSimulatorProvider(ctx, t, c)
+		ds := p.DataSourcesMap["vsphere_virtual_machine_id"]
+		for name, raw := range map[string]map[string]interface{}{
+			"uuid": {"uuid": uuid},
+			"moid": {"moid": moid},
+		} {
+			t.Run(name, func(t *testing.T) {
+				d := schema.TestResourceDataRaw(t, ds.Schema, raw)
+				if diags := ds.ReadContext(ctx, d, p.Meta()); diags.HasError() {
+					t.Fatalf("%#v", diags)
+				}
+				if d.Id() != moid || d.Get("moid").(string) != moid {
+					t.Errorf("expected ID and moid %q, got %q and %q", moid, d.Id(), d.Get("moid"))
+				}
+				if d.Get("uuid").(string) != uuid {
+					t.Errorf("expected uuid %q, got %q", uuid, d.Get("uuid"))
+				}
+			})
+		}
+
+		d := schema.TestResourceDataRaw(t, ds.Schema, map[string]interface{}{"moid": "vm-404"})
+		if diags := ds.ReadContext(ctx, d, p.Meta()); !diags.HasError() {
+			t.Error("expected an error for a managed object ID that does not exist")
+		}
+	})
+}
@@ -10,6 +10,7 @@ import (
 	"sort"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/govmomi/vim25/mo"
@@ -18,7 +19,7 @@ import (
 
 func dataSourceVSphereVmfsDisks() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVSphereVmfsDisksRead,
+		ReadContext: dataSourceVSphereVmfsDisksRead,
 
 		Schema: map[string]*schema.Schema{
 			"host_system_id": {
@@ -47,27 +48,27 @@ func dataSourceVSphereVmfsDisks() *schema.Resource {
 	}
 }
 
-func dataSourceVSphereVmfsDisksRead(d *schema.ResourceData, meta interface{}) error {
+func dataSourceVSphereVmfsDisksRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID := d.Get("host_system_id").(string)
 	ss, err := hostStorageSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host storage system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host storage system: %s", err))
 	}
 
 	if d.Get("rescan").(bool) {
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 		defer cancel()
 		if err := ss.RescanAllHba(ctx); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	var hss mo.HostStorageSystem
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	if err := ss.Properties(ctx, ss.Reference(), nil, &hss); err != nil {
-		return fmt.Errorf("error querying storage system properties: %s", err)
+		return diag.FromErr(fmt.Errorf("error querying storage system properties: %s", err))
 	}
 
 	d.SetId(time.Now().UTC().String())
@@ -84,7 +85,7 @@ func dataSourceVSphereVmfsDisksRead(d *schema.ResourceData, meta interface{}) er
 	sort.Strings(disks)
 
 	if err := d.Set("disks", disks); err != nil {
-		return fmt.Errorf("error saving results to state: %s", err)
+		return diag.FromErr(fmt.Errorf("error saving results to state: %s", err))
 	}
 
 	return nil
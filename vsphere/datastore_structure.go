@@ -5,7 +5,6 @@ package vsphere
 
 import (
 	"fmt"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/datastore"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
@@ -108,7 +107,7 @@ func resourceVSphereDatastoreStorageClusterPathNormalized(meta interface{}, id s
 // path of the supplied datastore and checks to see if it is a normal folder or
 // if it's a datastore cluster, and saves the attributes accordingly.
 func resourceVSphereDatastoreReadFolderOrStorageClusterPath(d *schema.ResourceData, ds *object.Datastore) error {
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ds, "parent")
 	if err != nil {
 		return fmt.Errorf("error fetching datastore properties while parsing path: %s", err)
 	}
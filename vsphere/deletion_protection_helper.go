@@ -0,0 +1,158 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+)
+
+// deletionProtectionAttributeKey is the key of the attribute that prevents a
+// resource from being destroyed. Resources that support it add the following
+// to their schema and CustomizeDiff, and call checkDeletionProtection at the
+// start of Delete and readDeletionProtection in Read:
+//
+//	deletionProtectionAttributeKey: deletionProtectionSchema(),
+//	...
+//	CustomizeDiff: deletionProtectionCustomizeDiff("vsphere_example", resourceVSphereExample),
+//
+// deletionProtectionCustomizeDiff must come last in the CustomizeDiff of the
+// resource. A CustomizeDiff that forces a new resource with d.ForceNew uses
+// deletionProtectionForceNew instead, as the forced replacement cannot be
+// seen afterwards.
+const deletionProtectionAttributeKey = "deletion_protection"
+
+// deletionProtectionSchema returns the schema for the deletion_protection
+// attribute. It is computed so that it can take the default of the provider
+// when it is not set.
+func deletionProtectionSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		Computed:    true,
+		Description: "Prevents the resource from being destroyed or replaced. Defaults to the deletion_protection setting of the provider.",
+	}
+}
+
+// deletionProtectionCustomizeDiff returns a CustomizeDiffFunc for the resource
+// name that fails the plan when a protected resource would be replaced. With
+// create_before_destroy, the replacement would otherwise be created before
+// Delete refuses to destroy the original. resource returns the resource, the
+// schema of which tells which changes force a new resource.
+//
+// It also plans deletion_protection as the default of the provider when it is
+// not set in the configuration, so that the value saved in state, which is all
+// that Delete can see, is the one in effect.
+func deletionProtectionCustomizeDiff(name string, resource func() *schema.Resource) schema.CustomizeDiffFunc {
+	return func(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+		if deletionProtectionEnabled(d) {
+			s := resource().Schema
+			for _, k := range d.GetChangedKeysPrefix("") {
+				if schemaForcesNew(s, k) {
+					return deletionProtectionReplaceError(d, name, k)
+				}
+			}
+		}
+
+		config := d.GetRawConfig()
+		if config.IsNull() || !config.GetAttr(deletionProtectionAttributeKey).IsNull() {
+			return nil
+		}
+		enabled := meta.(*Client).deletionProtection
+		if d.Id() != "" && d.Get(deletionProtectionAttributeKey).(bool) == enabled {
+			return nil
+		}
+		return d.SetNew(deletionProtectionAttributeKey, enabled)
+	}
+}
+
+// deletionProtectionForceNew forces a new resource for a change to key, like
+// d.ForceNew, unless the resource name is protected, in which case the plan
+// fails. Like d.ForceNew, it does nothing if key has not changed.
+func deletionProtectionForceNew(d *schema.ResourceDiff, name, key string) error {
+	if !d.HasChange(key) {
+		return nil
+	}
+	if deletionProtectionEnabled(d) {
+		return deletionProtectionReplaceError(d, name, key)
+	}
+	return d.ForceNew(key)
+}
+
+// deletionProtectionEnabled returns true if an existing resource is protected.
+// The value in state is the one that Delete sees, so a resource is protected
+// until deletion_protection is set to false and applied.
+func deletionProtectionEnabled(d *schema.ResourceDiff) bool {
+	if d.Id() == "" {
+		return false
+	}
+	o, _ := d.GetChange(deletionProtectionAttributeKey)
+	enabled, _ := o.(bool)
+	return enabled
+}
+
+// deletionProtectionReplaceError returns the error for a change to key that
+// would replace the protected resource name.
+func deletionProtectionReplaceError(d *schema.ResourceDiff, name, key string) error {
+	return fmt.Errorf(
+		"%s has deletion_protection enabled and cannot be replaced, which a change to %s requires; set deletion_protection to false and apply before replacing it",
+		structure.ResourceIDString(d, name),
+		key,
+	)
+}
+
+// schemaForcesNew returns true if the schema of the attribute at the flatmap
+// address key, or of any block that contains it, has ForceNew set.
+func schemaForcesNew(s map[string]*schema.Schema, key string) bool {
+	parts := strings.Split(key, ".")
+	for len(parts) > 0 {
+		v, ok := s[parts[0]]
+		if !ok {
+			return false
+		}
+		if v.ForceNew {
+			return true
+		}
+		parts = parts[1:]
+		r, ok := v.Elem.(*schema.Resource)
+		if !ok {
+			return false
+		}
+		// Skip the index of the list or set element, or the count.
+		if len(parts) > 0 {
+			parts = parts[1:]
+		}
+		s = r.Schema
+	}
+	return false
+}
+
+// readDeletionProtection sets deletion_protection to the default of the
+// provider if it is not in state, which is the case for imported resources and
+// resources created before the attribute was added. It is not read from
+// vSphere. GetOkExists is used as GetOk cannot tell an unset bool from false.
+func readDeletionProtection(d *schema.ResourceData, meta interface{}) error {
+	if _, ok := d.GetOkExists(deletionProtectionAttributeKey); ok {
+		return nil
+	}
+	return d.Set(deletionProtectionAttributeKey, meta.(*Client).deletionProtection)
+}
+
+// checkDeletionProtection returns an error if deletion_protection is enabled
+// on the resource name with the ResourceData d. Terraform replaces a resource
+// by destroying it, so this also stops a change that forces a new resource.
+// A resource that is rolled back during its creation is not protected.
+func checkDeletionProtection(d *schema.ResourceData, name string) error {
+	if d.IsNewResource() || !d.Get(deletionProtectionAttributeKey).(bool) {
+		return nil
+	}
+	return fmt.Errorf(
+		"%s has deletion_protection enabled and cannot be destroyed or replaced; set deletion_protection to false and apply before destroying or replacing it",
+		structure.ResourceIDString(d, name),
+	)
+}
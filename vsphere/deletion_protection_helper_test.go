@@ -0,0 +1,65 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func TestSchemaForcesNew(t *testing.T) {
+	s := map[string]*schema.Schema{
+		"name":   {Type: schema.TypeString, Required: true},
+		"folder": {Type: schema.TypeString, Optional: true, ForceNew: true},
+		"tags": {
+			Type:     schema.TypeSet,
+			Optional: true,
+			Elem:     &schema.Schema{Type: schema.TypeString},
+		},
+		"publication": {
+			Type:     schema.TypeList,
+			Optional: true,
+			ForceNew: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"username": {Type: schema.TypeString, Optional: true},
+				},
+			},
+		},
+		"disk": {
+			Type:     schema.TypeList,
+			Optional: true,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"label": {Type: schema.TypeString, Optional: true},
+					"path":  {Type: schema.TypeString, Optional: true, ForceNew: true},
+				},
+			},
+		},
+	}
+
+	cases := []struct {
+		key      string
+		expected bool
+	}{
+		{"name", false},
+		{"folder", true},
+		{"tags.#", false},
+		{"tags.1234", false},
+		{"publication.#", true},
+		{"publication.0.username", true},
+		{"disk.#", false},
+		{"disk.1.label", false},
+		{"disk.1.path", true},
+		{"unknown", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.key, func(t *testing.T) {
+			if actual := schemaForcesNew(s, tc.key); actual != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
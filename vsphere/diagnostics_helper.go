@@ -0,0 +1,53 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+)
+
+// diagnosticsError returns the errors in diags as an error, or nil if there
+// are none. It is used where a CRUD function is called by code that returns an
+// error, such as an importer or the rollback of a failed create.
+func diagnosticsError(diags diag.Diagnostics) error {
+	var msgs []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		msg := d.Summary
+		if d.Detail != "" {
+			msg += ": " + d.Detail
+		}
+		msgs = append(msgs, msg)
+	}
+	if len(msgs) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(msgs, "; "))
+}
+
+// errorDiagnostics returns err as diagnostics, pointing at the attribute in
+// path if it is known. When err carries a vSphere fault, the fault class, the
+// property it refers to, the reasons given by vSphere and the ID of the failed
+// task are added to the detail, as they are otherwise lost in the message.
+func errorDiagnostics(err error, path cty.Path) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	d := diag.Diagnostic{
+		Severity:      diag.Error,
+		Summary:       err.Error(),
+		AttributePath: path,
+	}
+	if f, ok := viapi.FaultFromError(err); ok {
+		d.Detail = f.Detail()
+	}
+	return diag.Diagnostics{d}
+}
@@ -0,0 +1,71 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestErrorDiagnostics(t *testing.T) {
+	if diags := errorDiagnostics(nil, nil); diags != nil {
+		t.Fatalf("expected no diagnostics, got %#v", diags)
+	}
+
+	diags := errorDiagnostics(errors.New("connection refused"), nil)
+	expected := diag.Diagnostics{{Severity: diag.Error, Summary: "connection refused"}}
+	if fmt.Sprintf("%#v", diags) != fmt.Sprintf("%#v", expected) {
+		t.Fatalf("expected %#v, got %#v", expected, diags)
+	}
+}
+
+func TestResourceVSphereVirtualMachineErrorDiagnostics(t *testing.T) {
+	d := schema.TestResourceDataRaw(t, resourceVSphereVirtualMachine().Schema, map[string]interface{}{})
+	if err := d.Set("disk", []interface{}{
+		map[string]interface{}{"label": "disk0", "key": 2000},
+		map[string]interface{}{"label": "disk1", "key": 2001},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []types.BaseVirtualDeviceConfigSpec{
+		&types.VirtualDeviceConfigSpec{
+			Operation: types.VirtualDeviceConfigSpecOperationEdit,
+			Device:    &types.VirtualDisk{VirtualDevice: types.VirtualDevice{Key: 2001}},
+		},
+	}
+	err := fmt.Errorf("error reconfiguring virtual machine: %w", &virtualmachine.DeviceChangeError{
+		Change: changes[0],
+		Err: &viapi.TaskError{
+			TaskID: "task-123",
+			Err: task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{
+				LocalizedMessage: "Invalid configuration for device '0'.",
+				Fault:            &types.InvalidDeviceSpec{DeviceIndex: 0},
+			}},
+		},
+	})
+
+	diags := resourceVSphereVirtualMachineErrorDiagnostics(d, err)
+	if len(diags) != 1 {
+		t.Fatalf("expected 1 diagnostic, got %d", len(diags))
+	}
+	if !diags[0].AttributePath.Equals(cty.GetAttrPath("disk").IndexInt(1)) {
+		t.Fatalf("unexpected attribute path: %#v", diags[0].AttributePath)
+	}
+	for _, s := range []string{"InvalidDeviceSpec", "task-123", "at key 2001"} {
+		if !strings.Contains(diags[0].Detail, s) {
+			t.Fatalf("expected detail to contain %q, got %q", s, diags[0].Detail)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/network"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -78,12 +79,13 @@ func dvsFromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*o
 }
 
 // dvsProperties is a convenience method that wraps fetching the DVS MO from
-// its higher-level object.
-func dvsProperties(dvs *object.VmwareDistributedVirtualSwitch) (*mo.VmwareDistributedVirtualSwitch, error) {
+// its higher-level object. If paths is not empty, only those properties are
+// retrieved.
+func dvsProperties(dvs *object.VmwareDistributedVirtualSwitch, paths ...string) (*mo.VmwareDistributedVirtualSwitch, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
 	defer cancel()
 	var props mo.VmwareDistributedVirtualSwitch
-	if err := dvs.Properties(ctx, dvs.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, dvs.Client(), dvs.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -92,7 +94,7 @@ func dvsProperties(dvs *object.VmwareDistributedVirtualSwitch) (*mo.VmwareDistri
 // upgradeDVS upgrades a DVS to a specific version. Downgrades are not
 // supported and will result in an error. This should be checked before running
 // this function.
-func upgradeDVS(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, version string) error {
+func upgradeDVS(ctx context.Context, client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, version string) error {
 	req := &types.PerformDvsProductSpecOperation_Task{
 		This:      dvs.Reference(),
 		Operation: "upgrade",
@@ -101,43 +103,35 @@ func upgradeDVS(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwit
 		},
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
 	resp, err := methods.PerformDvsProductSpecOperation_Task(ctx, client, req)
 	if err != nil {
 		return err
 	}
 	task := object.NewTask(client.Client, resp.Returnval)
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer tcancel()
-	return task.Wait(tctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // updateDVSConfiguration contains the atomic update/wait operation for a DVS.
-func updateDVSConfiguration(dvs *object.VmwareDistributedVirtualSwitch, spec *types.VMwareDVSConfigSpec) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
+func updateDVSConfiguration(ctx context.Context, dvs *object.VmwareDistributedVirtualSwitch, spec *types.VMwareDVSConfigSpec) error {
 	task, err := dvs.Reconfigure(ctx, spec)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer tcancel()
-	return task.Wait(tctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // enableDVSNetworkResourceManagement exposes the
 // EnableNetworkResourceManagement method of the DistributedVirtualSwitch MO.
 // This local implementation may go away if this is exposed in the higher-level
 // object upstream.
-func enableDVSNetworkResourceManagement(client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, enabled bool) error {
+func enableDVSNetworkResourceManagement(ctx context.Context, client *govmomi.Client, dvs *object.VmwareDistributedVirtualSwitch, enabled bool) error {
 	req := &types.EnableNetworkResourceManagement{
 		This:   dvs.Reference(),
 		Enable: enabled,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
 	_, err := methods.EnableNetworkResourceManagement(ctx, client, req)
 	if err != nil {
 		return err
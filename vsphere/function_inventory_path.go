@@ -0,0 +1,94 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
+)
+
+// inventoryPath is the result of the parse_inventory_path function.
+type inventoryPath struct {
+	DatacenterFolder string `tfsdk:"datacenter_folder"`
+	Datacenter       string `tfsdk:"datacenter"`
+	Type             string `tfsdk:"type"`
+	Folder           string `tfsdk:"folder"`
+	Name             string `tfsdk:"name"`
+}
+
+// parseInventoryPath splits the absolute inventory path p, such as
+// /dc-folder/dc1/vm/folder1/vm1, into the folder and the name of its
+// datacenter, the folder type of its root folder, the folder relative to that
+// root folder and the name of the object. The root folder is the first vm,
+// host, datastore or network component after the datacenter.
+func parseInventoryPath(p string) (inventoryPath, error) {
+	if !strings.HasPrefix(p, "/") {
+		return inventoryPath{}, fmt.Errorf("%q is not an absolute inventory path", p)
+	}
+	components := strings.Split(strings.Trim(path.Clean(p), "/"), "/")
+	for i := 1; i < len(components); i++ {
+		switch folder.VSphereFolderType(components[i]) {
+		case folder.VSphereFolderTypeVM, folder.VSphereFolderTypeHost, folder.VSphereFolderTypeDatastore, folder.VSphereFolderTypeNetwork:
+		default:
+			continue
+		}
+		result := inventoryPath{
+			DatacenterFolder: strings.Join(components[:i-1], "/"),
+			Datacenter:       components[i-1],
+			Type:             components[i],
+		}
+		if relative := components[i+1:]; len(relative) > 0 {
+			result.Folder = strings.Join(relative[:len(relative)-1], "/")
+			result.Name = relative[len(relative)-1]
+		}
+		return result, nil
+	}
+	return inventoryPath{}, fmt.Errorf("%q has no vm, host, datastore or network folder after the datacenter", p)
+}
+
+type parseInventoryPathFunction struct{}
+
+var _ function.Function = parseInventoryPathFunction{}
+
+func newParseInventoryPathFunction() function.Function {
+	return parseInventoryPathFunction{}
+}
+
+func (f parseInventoryPathFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "parse_inventory_path"
+}
+
+func (f parseInventoryPathFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Parse a vSphere inventory path",
+		Description: "Splits an absolute inventory path, such as /dc1/vm/folder1/vm1, into datacenter_folder, datacenter, type (vm, host, datastore or network), folder, relative to the root folder of the type, and name.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Description: "The absolute inventory path.",
+			},
+		},
+		Return: function.ObjectReturn{
+			AttributeTypes: frameworkStringAttrTypes("datacenter_folder", "datacenter", "type", "folder", "name"),
+		},
+	}
+}
+
+func (f parseInventoryPathFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var p string
+	resp.Diagnostics.Append(req.Arguments.Get(ctx, &p)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	result, err := parseInventoryPath(p)
+	if err != nil {
+		resp.Diagnostics.AddArgumentError(0, "Invalid inventory path", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, result)...)
+}
@@ -0,0 +1,59 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseInventoryPath(t *testing.T) {
+	cases := []struct {
+		path     string
+		expected inventoryPath
+		err      bool
+	}{
+		{
+			path:     "/dc1/vm/folder1/folder2/vm1",
+			expected: inventoryPath{Datacenter: "dc1", Type: "vm", Folder: "folder1/folder2", Name: "vm1"},
+		},
+		{
+			path:     "/dc-folder/dc1/host/cluster1",
+			expected: inventoryPath{DatacenterFolder: "dc-folder", Datacenter: "dc1", Type: "host", Name: "cluster1"},
+		},
+		{
+			path:     "/dc1/datastore/",
+			expected: inventoryPath{Datacenter: "dc1", Type: "datastore"},
+		},
+		{
+			path:     "/dc1/network/vm",
+			expected: inventoryPath{Datacenter: "dc1", Type: "network", Name: "vm"},
+		},
+		{
+			path: "dc1/vm/vm1",
+			err:  true,
+		},
+		{
+			path: "/dc1/templates/vm1",
+			err:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.path, func(t *testing.T) {
+			actual, err := parseInventoryPath(tc.path)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error, got %#v", actual)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err: %s", err)
+			}
+			if !reflect.DeepEqual(tc.expected, actual) {
+				t.Fatalf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+)
+
+// parseMACAddress parses an Ethernet MAC address in any of the formats of
+// net.ParseMAC, such as 00:50:56:aa:bb:cc, 00-50-56-AA-BB-CC or
+// 0050.56aa.bbcc.
+func parseMACAddress(mac string) (net.HardwareAddr, error) {
+	addr, err := net.ParseMAC(mac)
+	if err != nil {
+		return nil, err
+	}
+	if len(addr) != 6 {
+		return nil, fmt.Errorf("%q is not an Ethernet MAC address", mac)
+	}
+	return addr, nil
+}
+
+// isStaticMACAddress returns true if addr is in 00:50:56:00:00:00 to
+// 00:50:56:3f:ff:ff, the range that vSphere accepts for manually assigned MAC
+// addresses.
+func isStaticMACAddress(addr net.HardwareAddr) bool {
+	return addr[0] == 0x00 && addr[1] == 0x50 && addr[2] == 0x56 && addr[3] <= 0x3f
+}
+
+// netmaskToPrefixLength returns the prefix length of the IPv4 or IPv6 netmask
+// mask, such as 24 for 255.255.255.0.
+func netmaskToPrefixLength(mask string) (int64, error) {
+	ip := net.ParseIP(mask)
+	if ip == nil {
+		return 0, fmt.Errorf("%q is not an IP address", mask)
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		ip = ip4
+	}
+	ones, bits := net.IPMask(ip).Size()
+	if bits == 0 {
+		return 0, fmt.Errorf("%q is not a netmask", mask)
+	}
+	return int64(ones), nil
+}
+
+type normalizeMACAddressFunction struct{}
+
+var _ function.Function = normalizeMACAddressFunction{}
+
+func newNormalizeMACAddressFunction() function.Function {
+	return normalizeMACAddressFunction{}
+}
+
+func (f normalizeMACAddressFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "normalize_mac_address"
+}
+
+func (f normalizeMACAddressFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Normalize a MAC address",
+		Description: "Returns a MAC address in the lower case, colon separated format that vSphere reports, such as 00:50:56:aa:bb:cc. Accepts colon, hyphen and dot separated addresses.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Description: "The MAC address.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f normalizeMACAddressFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mac string
+	resp.Diagnostics.Append(req.Arguments.Get(ctx, &mac)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	addr, err := parseMACAddress(mac)
+	if err != nil {
+		resp.Diagnostics.AddArgumentError(0, "Invalid MAC address", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, addr.String())...)
+}
+
+type isStaticMACAddressFunction struct{}
+
+var _ function.Function = isStaticMACAddressFunction{}
+
+func newIsStaticMACAddressFunction() function.Function {
+	return isStaticMACAddressFunction{}
+}
+
+func (f isStaticMACAddressFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "is_static_mac_address"
+}
+
+func (f isStaticMACAddressFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Check a MAC address for use as a static MAC address",
+		Description: "Returns true if a MAC address is in the range 00:50:56:00:00:00 to 00:50:56:3f:ff:ff, which vSphere requires of a network interface with use_static_mac enabled.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Description: "The MAC address.",
+			},
+		},
+		Return: function.BoolReturn{},
+	}
+}
+
+func (f isStaticMACAddressFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mac string
+	resp.Diagnostics.Append(req.Arguments.Get(ctx, &mac)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	addr, err := parseMACAddress(mac)
+	if err != nil {
+		resp.Diagnostics.AddArgumentError(0, "Invalid MAC address", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, isStaticMACAddress(addr))...)
+}
+
+type netmaskToPrefixLengthFunction struct{}
+
+var _ function.Function = netmaskToPrefixLengthFunction{}
+
+func newNetmaskToPrefixLengthFunction() function.Function {
+	return netmaskToPrefixLengthFunction{}
+}
+
+func (f netmaskToPrefixLengthFunction) Metadata(_ context.Context, _ function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "netmask_to_prefix_length"
+}
+
+func (f netmaskToPrefixLengthFunction) Definition(_ context.Context, _ function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:     "Convert a netmask to a prefix length",
+		Description: "Returns the prefix length of an IPv4 or IPv6 netmask, such as 24 for 255.255.255.0, for the ipv4_netmask and ipv6_netmask arguments of virtual machine customization.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Description: "The netmask.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f netmaskToPrefixLengthFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var mask string
+	resp.Diagnostics.Append(req.Arguments.Get(ctx, &mask)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	length, err := netmaskToPrefixLength(mask)
+	if err != nil {
+		resp.Diagnostics.AddArgumentError(0, "Invalid netmask", err.Error())
+		return
+	}
+	resp.Diagnostics.Append(resp.Result.Set(ctx, length)...)
+}
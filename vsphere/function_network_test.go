@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"testing"
+)
+
+func TestParseMACAddress(t *testing.T) {
+	for _, mac := range []string{"00:50:56:AA:BB:CC", "00-50-56-aa-bb-cc", "0050.56aa.bbcc"} {
+		addr, err := parseMACAddress(mac)
+		if err != nil {
+			t.Fatalf("%s: %s", mac, err)
+		}
+		if addr.String() != "00:50:56:aa:bb:cc" {
+			t.Fatalf("%s: expected 00:50:56:aa:bb:cc, got %s", mac, addr)
+		}
+	}
+
+	if _, err := parseMACAddress("00:00:00:00:fe:80:00:00:00:00:00:00:02:00:5e:10:00:00:00:01"); err == nil {
+		t.Fatal("expected error for an IPoIB address")
+	}
+}
+
+func TestIsStaticMACAddress(t *testing.T) {
+	cases := map[string]bool{
+		"00:50:56:00:00:00": true,
+		"00:50:56:3f:ff:ff": true,
+		"00:50:56:40:00:00": false,
+		"00:0c:29:aa:bb:cc": false,
+	}
+	for mac, expected := range cases {
+		addr, err := parseMACAddress(mac)
+		if err != nil {
+			t.Fatalf("%s: %s", mac, err)
+		}
+		if actual := isStaticMACAddress(addr); actual != expected {
+			t.Fatalf("%s: expected %t, got %t", mac, expected, actual)
+		}
+	}
+}
+
+func TestNetmaskToPrefixLength(t *testing.T) {
+	cases := map[string]int64{
+		"255.255.255.0":         24,
+		"255.255.252.0":         22,
+		"0.0.0.0":               0,
+		"ffff:ffff:ffff:ffff::": 64,
+	}
+	for mask, expected := range cases {
+		actual, err := netmaskToPrefixLength(mask)
+		if err != nil {
+			t.Fatalf("%s: %s", mask, err)
+		}
+		if actual != expected {
+			t.Fatalf("%s: expected %d, got %d", mask, expected, actual)
+		}
+	}
+
+	for _, mask := range []string{"255.0.255.0", "24", "10.0.0.1"} {
+		if _, err := netmaskToPrefixLength(mask); err == nil {
+			t.Fatalf("expected error for %q", mask)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"reflect"
 	"regexp"
 	"strconv"
@@ -25,6 +26,7 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/network"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/recorder"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/spbm"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/storagepod"
@@ -51,6 +53,96 @@ const testAccResourceVSphereEmpty = `
 provider vsphere{}
 `
 
+// testRecordFixturesEnvVar is the environment variable that, when set to a
+// non-empty value, makes testReplayClient record its fixture from the
+// endpoint configured through the VSPHERE_* environment variables, instead of
+// replaying it.
+const testRecordFixturesEnvVar = "VSPHERE_RECORD_FIXTURES"
+
+// testFixturesDir is the directory that holds recorded API fixtures. It is
+// resolved when the package is initialized, as the simulator harness changes
+// the working directory of the test binary.
+var testFixturesDir = func() string {
+	wd, _ := os.Getwd()
+	return filepath.Join(wd, "testdata", "fixtures")
+}()
+
+// testFixtureSources are the directories in testFixturesDir that hold the
+// fixtures recorded against each kind of endpoint, in the order they are
+// replayed from. A fixture recorded against vCenter Server is preferred to one
+// recorded against the simulator, which only models vCenter Server.
+var testFixtureSources = []string{"vcenter", "vcsim"}
+
+// testFixtureSource returns the directory in testFixturesDir that a fixture
+// recorded now is saved to.
+func testFixtureSource() string {
+	if testAccUsingSimulator() {
+		return "vcsim"
+	}
+	return "vcenter"
+}
+
+// testReplayClient returns a recorder and a client for the named fixture.
+//
+// By default the client replays the fixture and never touches the network,
+// and the test is skipped if the fixture does not exist. The fixture is
+// replayed from the first of testFixtureSources that has it. When
+// VSPHERE_RECORD_FIXTURES is set, the client connects to the configured
+// endpoint instead, and the traffic is saved to the fixture of the endpoint
+// when the test finishes.
+func testReplayClient(t *testing.T, name string) (*recorder.Recorder, *govmomi.Client) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+
+	if os.Getenv(testRecordFixturesEnvVar) == "" {
+		for _, source := range testFixtureSources {
+			p := filepath.Join(testFixturesDir, source, name+".json")
+			if _, err := os.Stat(p); os.IsNotExist(err) {
+				continue
+			}
+			t.Logf("replaying fixture %q, recorded against %s", name, source)
+			rec, err := recorder.New(p, recorder.ModeReplay)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(rec.Close)
+			client, err := rec.NewReplayClient(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return rec, client
+		}
+		t.Skipf("fixture %q does not exist, set %s to record it", name, testRecordFixturesEnvVar)
+	}
+
+	p := filepath.Join(testFixturesDir, testFixtureSource(), name+".json")
+	testAccPreCheck(t)
+	c := testAccClientGenerateConfig(t)
+	u, err := c.vimURL()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec, err := recorder.New(p, recorder.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(rec.Close)
+	client, err := rec.NewRecordingClient(ctx, u, c.InsecureFlag)
+	if err != nil {
+		t.Fatalf("error connecting to record fixture %q: %s", name, err)
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			return
+		}
+		if err := rec.Save(); err != nil {
+			t.Errorf("error saving fixture %q: %s", name, err)
+		}
+	})
+	return rec, client
+}
+
 // testCheckVariables bundles common variables needed by various test checkers.
 type testCheckVariables struct {
 	// A client for various operations.
@@ -393,6 +485,7 @@ func testRenameVMFirstDisk(s *terraform.State, resourceName string, new string)
 	for _, d := range vprops.Config.Hardware.Device {
 		if oldDisk, ok := d.(*types.VirtualDisk); ok {
 			newFileName, err := virtualdisk.Move(
+				context.Background(),
 				tVars.client,
 				oldDisk.Backing.(*types.VirtualDiskFlatVer2BackingInfo).FileName,
 				dc,
@@ -438,7 +531,7 @@ func testRenameVMFirstDisk(s *terraform.State, resourceName string, new string)
 	spec := types.VirtualMachineConfigSpec{
 		DeviceChange: dcSpec,
 	}
-	return virtualmachine.Reconfigure(vm, spec, defaultAPITimeout)
+	return virtualmachine.Reconfigure(context.Background(), vm, spec, defaultAPITimeout)
 }
 
 // testDeleteVMDisk deletes a VMDK file from the virtual machine directory. It
@@ -473,7 +566,7 @@ func testDeleteVMDisk(s *terraform.State, name string) error {
 		Datastore: vmxPath.Datastore,
 		Path:      path.Join(path.Dir(vmxPath.Path), name),
 	}
-	return virtualdisk.Delete(tVars.client, p.String(), dc)
+	return virtualdisk.Delete(context.Background(), tVars.client, p.String(), dc)
 }
 
 // testDeleteVM deletes the virtual machine. This is used to test resource
@@ -1225,8 +1318,8 @@ func vmSweep(string) error {
 	}
 	for _, vm := range vms {
 		if regexp.MustCompile("testacc").Match([]byte(vm.Name())) {
-			_ = virtualmachine.PowerOff(vm)
-			_ = virtualmachine.Destroy(vm)
+			_ = virtualmachine.PowerOff(context.Background(), vm)
+			_ = virtualmachine.Destroy(context.Background(), vm)
 		}
 	}
 	return nil
@@ -1243,7 +1336,7 @@ func rpSweep(string) error {
 	}
 	for _, rp := range rps {
 		if regexp.MustCompile("testacc").Match([]byte(rp.Name())) {
-			return resourcepool.Delete(rp)
+			return resourcepool.Delete(context.Background(), rp)
 		}
 	}
 	return nil
@@ -1260,12 +1353,12 @@ func dsSweep(string) error {
 	}
 	for _, ds := range dss {
 		if regexp.MustCompile(testhelper.NfsDsName2).Match([]byte(ds.Name())) {
-			if err := datastore.Unmount(client.vimClient, ds); err != nil {
+			if err := datastore.Unmount(context.Background(), client.vimClient, ds); err != nil {
 				return err
 			}
 		}
 		if regexp.MustCompile("testacc").Match([]byte(ds.Name())) {
-			if err := datastore.Unmount(client.vimClient, ds); err != nil {
+			if err := datastore.Unmount(context.Background(), client.vimClient, ds); err != nil {
 				return err
 			}
 		}
@@ -1284,7 +1377,7 @@ func dspSweep(string) error {
 	}
 	for _, dsp := range dsps {
 		if regexp.MustCompile("testacc").Match([]byte(dsp.Name())) {
-			return storagepod.Delete(dsp)
+			return storagepod.Delete(context.Background(), dsp)
 		}
 	}
 	return nil
@@ -1301,7 +1394,7 @@ func ccSweep(string) error {
 	}
 	for _, dsp := range dsps {
 		if regexp.MustCompile("testacc").Match([]byte(dsp.Name())) {
-			return clustercomputeresource.Delete(dsp)
+			return clustercomputeresource.Delete(context.Background(), dsp)
 		}
 	}
 	return nil
@@ -0,0 +1,177 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/batch"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// hostNetworkBatcher combines the changes submitted with
+// updateHostNetworkConfig. Changes are held back for a second, so that the
+// changes made by resources applied in parallel can be sent to the host in a
+// single UpdateNetworkConfig call.
+var hostNetworkBatcher = batch.NewBatcher(time.Second)
+
+// hostNetworkKey returns the key of ns in hostNetworkBatcher. Network systems
+// are keyed on the vCenter or host they belong to as well as their ID, as
+// managed object IDs are only unique within a vCenter.
+func hostNetworkKey(ns *object.HostNetworkSystem) string {
+	return ns.Client().URL().Host + "/" + ns.Reference().Value
+}
+
+// hostVirtualSwitchChange returns a change that applies op to the virtual
+// switch name.
+func hostVirtualSwitchChange(op types.HostConfigChangeOperation, name string, spec *types.HostVirtualSwitchSpec) types.HostNetworkConfig {
+	return types.HostNetworkConfig{
+		Vswitch: []types.HostVirtualSwitchConfig{
+			{
+				ChangeOperation: string(op),
+				Name:            name,
+				Spec:            spec,
+			},
+		},
+	}
+}
+
+// hostPortGroupChange returns a change that applies op to the port group in
+// spec. Port groups are identified by the name in spec.
+func hostPortGroupChange(op types.HostConfigChangeOperation, spec *types.HostPortGroupSpec) types.HostNetworkConfig {
+	return types.HostNetworkConfig{
+		Portgroup: []types.HostPortGroupConfig{
+			{
+				ChangeOperation: string(op),
+				Spec:            spec,
+			},
+		},
+	}
+}
+
+// hostVirtualNicChange returns a change that applies op to the virtual NIC
+// device. device is empty when adding a NIC, and portgroup is only used when
+// adding a NIC to a standard port group.
+func hostVirtualNicChange(op types.HostConfigChangeOperation, device, portgroup string, spec *types.HostVirtualNicSpec) types.HostNetworkConfig {
+	return types.HostNetworkConfig{
+		Vnic: []types.HostVirtualNicConfig{
+			{
+				ChangeOperation: string(op),
+				Device:          device,
+				Portgroup:       portgroup,
+				Spec:            spec,
+			},
+		},
+	}
+}
+
+// updateHostNetworkConfig applies change to the network configuration of ns,
+// along with the changes submitted for the same host by other resources at
+// about the same time. The changes of a batch are sent in a single
+// UpdateNetworkConfig call in modify mode, which saves a call per resource
+// when a host's standard networking is built. When a combined update fails,
+// every change of it fails with its error, as some of the changes may have
+// been made before the failure.
+//
+// change must hold a single virtual switch, port group or virtual NIC change,
+// as returned by hostVirtualSwitchChange, hostPortGroupChange and
+// hostVirtualNicChange. For an added virtual NIC, the name of the new device
+// is returned.
+//
+// If ctx is done before the batch has been sent, the change is taken out of it
+// and the error of ctx is returned.
+func updateHostNetworkConfig(ctx context.Context, ns *object.HostNetworkSystem, change types.HostNetworkConfig) (string, error) {
+	device, err := hostNetworkBatcher.Submit(ctx, hostNetworkKey(ns), change, defaultAPITimeout, func(ctx context.Context, changes []interface{}) ([]interface{}, error) {
+		return sendHostNetworkChanges(ctx, ns, changes)
+	})
+	if err != nil {
+		return "", err
+	}
+	s, _ := device.(string)
+	return s, nil
+}
+
+// sendHostNetworkChanges sends the batched changes to ns, and returns the
+// device of each change, which is empty for changes that do not add a virtual
+// NIC.
+func sendHostNetworkChanges(ctx context.Context, ns *object.HostNetworkSystem, changes []interface{}) ([]interface{}, error) {
+	if len(changes) == 1 {
+		device, err := applyHostNetworkChange(ctx, ns, changes[0].(types.HostNetworkConfig))
+		return []interface{}{device}, err
+	}
+
+	log.Printf("[DEBUG] Sending %d network change(s) to %q in a single update", len(changes), ns.Reference().Value)
+	var config types.HostNetworkConfig
+	for _, c := range changes {
+		change := c.(types.HostNetworkConfig)
+		config.Vswitch = append(config.Vswitch, change.Vswitch...)
+		config.Portgroup = append(config.Portgroup, change.Portgroup...)
+		config.Vnic = append(config.Vnic, change.Vnic...)
+	}
+	res, err := ns.UpdateNetworkConfig(ctx, config, string(types.HostConfigChangeModeModify))
+	if err != nil {
+		return nil, err
+	}
+	// The devices of added virtual NICs are returned in the order the NICs
+	// were added in.
+	var devices []string
+	if res != nil {
+		devices = res.VnicDevice
+	}
+	results := make([]interface{}, len(changes))
+	for i, c := range changes {
+		change := c.(types.HostNetworkConfig)
+		results[i] = ""
+		if len(change.Vnic) > 0 && change.Vnic[0].ChangeOperation == string(types.HostConfigChangeOperationAdd) {
+			if len(devices) == 0 {
+				return nil, fmt.Errorf("no device was returned for the added virtual NIC")
+			}
+			results[i], devices = devices[0], devices[1:]
+		}
+	}
+	return results, nil
+}
+
+// applyHostNetworkChange applies a single change with the HostNetworkSystem
+// method for it. This is used when a change is sent on its own, so that the
+// error returned is the one of that method.
+func applyHostNetworkChange(ctx context.Context, ns *object.HostNetworkSystem, change types.HostNetworkConfig) (string, error) {
+	switch {
+	case len(change.Vswitch) == 1:
+		c := change.Vswitch[0]
+		switch types.HostConfigChangeOperation(c.ChangeOperation) {
+		case types.HostConfigChangeOperationAdd:
+			return "", ns.AddVirtualSwitch(ctx, c.Name, c.Spec)
+		case types.HostConfigChangeOperationEdit:
+			return "", ns.UpdateVirtualSwitch(ctx, c.Name, *c.Spec)
+		case types.HostConfigChangeOperationRemove:
+			return "", ns.RemoveVirtualSwitch(ctx, c.Name)
+		}
+	case len(change.Portgroup) == 1:
+		c := change.Portgroup[0]
+		switch types.HostConfigChangeOperation(c.ChangeOperation) {
+		case types.HostConfigChangeOperationAdd:
+			return "", ns.AddPortGroup(ctx, *c.Spec)
+		case types.HostConfigChangeOperationEdit:
+			return "", ns.UpdatePortGroup(ctx, c.Spec.Name, *c.Spec)
+		case types.HostConfigChangeOperationRemove:
+			return "", ns.RemovePortGroup(ctx, c.Spec.Name)
+		}
+	case len(change.Vnic) == 1:
+		c := change.Vnic[0]
+		switch types.HostConfigChangeOperation(c.ChangeOperation) {
+		case types.HostConfigChangeOperationAdd:
+			return ns.AddVirtualNic(ctx, c.Portgroup, *c.Spec)
+		case types.HostConfigChangeOperationEdit:
+			return "", ns.UpdateVirtualNic(ctx, c.Device, *c.Spec)
+		case types.HostConfigChangeOperationRemove:
+			return "", ns.RemoveVirtualNic(ctx, c.Device)
+		}
+	}
+	return "", fmt.Errorf("unsupported host network change: %#v", change)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func testHostNetworkSystem(ctx context.Context, t *testing.T, c *vim25.Client) *object.HostNetworkSystem {
+	host, err := find.NewFinder(c).HostSystem(ctx, "DC0_H0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ns, err := host.ConfigManager().NetworkSystem(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ns
+}
+
+func TestUpdateHostNetworkConfig(t *testing.T) {
+	defer func(d time.Duration) { hostNetworkBatcher.Delay = d }(hostNetworkBatcher.Delay)
+	hostNetworkBatcher.Delay = 200 * time.Millisecond
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		ns := testHostNetworkSystem(ctx, t, c)
+
+		// A change sent on its own uses the method for it.
+		change := hostVirtualSwitchChange(types.HostConfigChangeOperationAdd, "vSwitchSingle", &types.HostVirtualSwitchSpec{NumPorts: 128})
+		if _, err := updateHostNetworkConfig(ctx, ns, change); err != nil {
+			t.Fatal(err)
+		}
+		var found bool
+		for _, sw := range simulator.Map.Get(ns.Reference()).(*simulator.HostNetworkSystem).NetworkInfo.Vswitch {
+			if sw.Name == "vSwitchSingle" {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected vSwitchSingle to be added")
+		}
+
+		// Changes submitted at about the same time are sent in a single
+		// UpdateNetworkConfig call.
+		changes := []types.HostNetworkConfig{
+			hostVirtualSwitchChange(types.HostConfigChangeOperationAdd, "vSwitchBatch", &types.HostVirtualSwitchSpec{NumPorts: 128}),
+			hostPortGroupChange(types.HostConfigChangeOperationAdd, &types.HostPortGroupSpec{Name: "pg0", VswitchName: "vSwitchBatch"}),
+			hostPortGroupChange(types.HostConfigChangeOperationAdd, &types.HostPortGroupSpec{Name: "pg1", VswitchName: "vSwitchBatch"}),
+		}
+		errs := make([]error, len(changes))
+		var wg sync.WaitGroup
+		for i, change := range changes {
+			wg.Add(1)
+			go func(i int, change types.HostNetworkConfig) {
+				defer wg.Done()
+				_, errs[i] = updateHostNetworkConfig(ctx, ns, change)
+			}(i, change)
+		}
+		wg.Wait()
+		for _, err := range errs {
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		config := simulator.Map.Get(ns.Reference()).(*simulator.HostNetworkSystem).NetworkConfig
+		if config == nil || len(config.Vswitch) != 1 || len(config.Portgroup) != 2 {
+			t.Fatalf("expected the changes to be sent in a single update, got %#v", config)
+		}
+	})
+}
+
+func TestUpdateHostNetworkConfigCancelled(t *testing.T) {
+	defer func(d time.Duration) { hostNetworkBatcher.Delay = d }(hostNetworkBatcher.Delay)
+	hostNetworkBatcher.Delay = 200 * time.Millisecond
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		ns := testHostNetworkSystem(ctx, t, c)
+
+		cctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		change := hostVirtualSwitchChange(types.HostConfigChangeOperationAdd, "vSwitchCancelled", &types.HostVirtualSwitchSpec{NumPorts: 128})
+		if _, err := updateHostNetworkConfig(cctx, ns, change); err != context.DeadlineExceeded {
+			t.Fatalf("expected %s, got %v", context.DeadlineExceeded, err)
+		}
+		time.Sleep(2 * hostNetworkBatcher.Delay)
+		for _, sw := range simulator.Map.Get(ns.Reference()).(*simulator.HostNetworkSystem).NetworkInfo.Vswitch {
+			if sw.Name == "vSwitchCancelled" {
+				t.Fatalf("expected the cancelled change not to be sent")
+			}
+		}
+	})
+}
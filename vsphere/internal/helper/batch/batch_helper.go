@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package batch combines the changes that resources applied in parallel make
+// to the same vSphere object, so that they are sent to it in a single call.
+package batch
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SendFunc sends changes to an object in a single call. It returns the result
+// of each change, in the order of changes. A result is nil for a change that
+// has none, and the results are ignored when an error is returned.
+type SendFunc func(ctx context.Context, changes []interface{}) ([]interface{}, error)
+
+// Batcher holds back the changes submitted for an object for Delay, and sends
+// the changes submitted in that time together. The batches and other changes
+// of an object are serialized, so that they do not fail or overwrite each
+// other. Objects are identified by a key, such as the host of the vCenter and
+// the managed object ID.
+type Batcher struct {
+	// Delay is how long changes are held back before they are sent.
+	Delay time.Duration
+
+	// mu protects objects and the pending batches of its entries.
+	mu      sync.Mutex
+	objects map[string]*objectState
+}
+
+// objectState tracks the changes made to a single object.
+type objectState struct {
+	// lock is held while the object is being changed, so that only one change
+	// runs at a time.
+	lock chan struct{}
+
+	// pending is the batch that changes are currently added to, or nil if no
+	// batch is waiting to be sent.
+	pending *batch
+}
+
+// batch is a set of changes that are sent to an object together.
+type batch struct {
+	entries []*entry
+	done    chan struct{}
+}
+
+// entry is one change in a batch, and the result of sending it.
+type entry struct {
+	change interface{}
+	result interface{}
+	err    error
+}
+
+// NewBatcher returns a Batcher that holds back changes for delay.
+func NewBatcher(delay time.Duration) *Batcher {
+	return &Batcher{
+		Delay:   delay,
+		objects: make(map[string]*objectState),
+	}
+}
+
+// state returns the state of the object key.
+func (b *Batcher) state(key string) *objectState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	s, ok := b.objects[key]
+	if !ok {
+		s = &objectState{lock: make(chan struct{}, 1)}
+		b.objects[key] = s
+	}
+	return s
+}
+
+// Lock waits until no other change of the object key is running, and returns
+// the function that lets the next one run. It is used for changes that are
+// not batched.
+func (b *Batcher) Lock(ctx context.Context, key string) (func(), error) {
+	return b.state(key).acquire(ctx)
+}
+
+// acquire waits until no other change of the object is running, and returns
+// the function that lets the next one run.
+func (s *objectState) acquire(ctx context.Context) (func(), error) {
+	select {
+	case s.lock <- struct{}{}:
+		return func() { <-s.lock }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Submit adds change to the batch of the object key, and returns the result of
+// change once the batch has been sent with send.
+//
+// The batch is sent in the background, with a timeout of its own. If ctx is
+// done before the batch has been sent, change is taken out of it and the error
+// of ctx is returned.
+//
+// When a batch of several changes fails, every change of the batch fails with
+// the error, as it cannot be told which change caused it, nor which changes
+// were made before it. Changes are never sent twice.
+func (b *Batcher) Submit(ctx context.Context, key string, change interface{}, timeout time.Duration, send SendFunc) (interface{}, error) {
+	s := b.state(key)
+	e := &entry{change: change}
+
+	b.mu.Lock()
+	p := s.pending
+	if p == nil {
+		p = &batch{done: make(chan struct{})}
+		s.pending = p
+		go b.send(s, p, b.Delay, timeout, send)
+	}
+	p.entries = append(p.entries, e)
+	b.mu.Unlock()
+
+	select {
+	case <-p.done:
+		return e.result, e.err
+	case <-ctx.Done():
+	}
+
+	b.mu.Lock()
+	if s.pending == p {
+		for i, pe := range p.entries {
+			if pe == e {
+				p.entries = append(p.entries[:i], p.entries[i+1:]...)
+				break
+			}
+		}
+		b.mu.Unlock()
+		return nil, ctx.Err()
+	}
+	b.mu.Unlock()
+	// The batch is already being sent, so the change may still be made. Wait
+	// for the result, so that it is not lost.
+	<-p.done
+	return e.result, e.err
+}
+
+// send waits for delay and for any running change of the object, then sends
+// the changes in p. Changes added to p while waiting are sent with it.
+func (b *Batcher) send(s *objectState, p *batch, delay, timeout time.Duration, send SendFunc) {
+	defer close(p.done)
+	time.Sleep(delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	unlock, err := s.acquire(ctx)
+
+	b.mu.Lock()
+	if s.pending == p {
+		s.pending = nil
+	}
+	entries := p.entries
+	b.mu.Unlock()
+
+	if err != nil {
+		for _, e := range entries {
+			e.err = err
+		}
+		return
+	}
+	defer unlock()
+
+	if len(entries) == 0 {
+		return
+	}
+	changes := make([]interface{}, len(entries))
+	for i, e := range entries {
+		changes[i] = e.change
+	}
+	results, err := send(ctx, changes)
+	if err != nil && len(entries) > 1 {
+		err = fmt.Errorf("%w (sent together with %d other change(s), any of which may have caused it)", err, len(entries)-1)
+	}
+	for i, e := range entries {
+		e.err = err
+		if err == nil && i < len(results) {
+			e.result = results[i]
+		}
+	}
+}
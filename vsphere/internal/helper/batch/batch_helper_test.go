@@ -0,0 +1,127 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package batch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testSender records the batches sent through it.
+type testSender struct {
+	mu      sync.Mutex
+	batches [][]interface{}
+	err     error
+}
+
+func (s *testSender) send(_ context.Context, changes []interface{}) ([]interface{}, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.batches = append(s.batches, changes)
+	if s.err != nil {
+		return nil, s.err
+	}
+	results := make([]interface{}, len(changes))
+	for i, c := range changes {
+		results[i] = fmt.Sprintf("%s-result", c)
+	}
+	return results, nil
+}
+
+func testSubmitAll(b *Batcher, s *testSender, changes []string) ([]interface{}, []error) {
+	results := make([]interface{}, len(changes))
+	errs := make([]error, len(changes))
+	var wg sync.WaitGroup
+	for i, change := range changes {
+		wg.Add(1)
+		go func(i int, change string) {
+			defer wg.Done()
+			results[i], errs[i] = b.Submit(context.Background(), "obj", change, time.Minute, s.send)
+		}(i, change)
+	}
+	wg.Wait()
+	return results, errs
+}
+
+func TestSubmit(t *testing.T) {
+	b := NewBatcher(100 * time.Millisecond)
+	s := &testSender{}
+
+	changes := []string{"c0", "c1", "c2"}
+	results, errs := testSubmitAll(b, s, changes)
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("unexpected error for %s: %s", changes[i], err)
+		}
+		if results[i] != changes[i]+"-result" {
+			t.Fatalf("expected the result of %s, got %v", changes[i], results[i])
+		}
+	}
+	if len(s.batches) != 1 || len(s.batches[0]) != 3 {
+		t.Fatalf("expected the changes to be sent in a single batch, got %v", s.batches)
+	}
+}
+
+func TestSubmitFailed(t *testing.T) {
+	b := NewBatcher(100 * time.Millisecond)
+	s := &testSender{err: errors.New("duplicate name")}
+
+	_, errs := testSubmitAll(b, s, []string{"c0", "c1", "c2"})
+	for i, err := range errs {
+		if err == nil || !errors.Is(err, s.err) {
+			t.Fatalf("expected the error of the batch for change %d, got %v", i, err)
+		}
+	}
+	if len(s.batches) != 1 {
+		t.Fatalf("expected the changes of a failed batch not to be sent again, got %v", s.batches)
+	}
+
+	_, errs = testSubmitAll(b, s, []string{"c3"})
+	if errs[0] != s.err {
+		t.Fatalf("expected the error of a change sent on its own to be returned as is, got %v", errs[0])
+	}
+}
+
+func TestSubmitCancelled(t *testing.T) {
+	b := NewBatcher(100 * time.Millisecond)
+	s := &testSender{}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := b.Submit(ctx, "obj", "cancelled", time.Minute, s.send); err != context.DeadlineExceeded {
+		t.Fatalf("expected %s, got %v", context.DeadlineExceeded, err)
+	}
+	if _, err := b.Submit(context.Background(), "obj", "kept", time.Minute, s.send); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.batches) != 1 || len(s.batches[0]) != 1 || s.batches[0][0] != "kept" {
+		t.Fatalf("expected only the change that was not cancelled to be sent, got %v", s.batches)
+	}
+}
+
+func TestLock(t *testing.T) {
+	b := NewBatcher(0)
+	unlock, err := b.Lock(context.Background(), "obj")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := b.Lock(ctx, "obj"); err != context.DeadlineExceeded {
+		t.Fatalf("expected the object to be locked, got %v", err)
+	}
+	if _, err := b.Lock(context.Background(), "other"); err != nil {
+		t.Fatalf("expected other objects not to be locked, got %v", err)
+	}
+
+	unlock()
+	if _, err := b.Lock(context.Background(), "obj"); err != nil {
+		t.Fatal(err)
+	}
+}
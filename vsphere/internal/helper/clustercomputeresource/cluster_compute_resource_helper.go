@@ -14,6 +14,7 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -94,11 +95,12 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 
 // Properties is a convenience method that wraps fetching the
 // ClusterComputeResource MO from its higher-level object.
-func Properties(cluster *object.ClusterComputeResource) (*mo.ClusterComputeResource, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(cluster *object.ClusterComputeResource, paths ...string) (*mo.ClusterComputeResource, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.ClusterComputeResource
-	if err := cluster.Properties(ctx, cluster.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, cluster.Client(), cluster.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -106,9 +108,9 @@ func Properties(cluster *object.ClusterComputeResource) (*mo.ClusterComputeResou
 
 // Create creates a ClusterComputeResource in a supplied folder. The resulting
 // ClusterComputeResource is returned.
-func Create(f *object.Folder, name string, spec types.ClusterConfigSpecEx) (*object.ClusterComputeResource, error) {
+func Create(ctx context.Context, f *object.Folder, name string, spec types.ClusterConfigSpecEx) (*object.ClusterComputeResource, error) {
 	log.Printf("[DEBUG] Creating compute cluster %q", fmt.Sprintf("%s/%s", f.InventoryPath, name))
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	cluster, err := f.CreateCluster(ctx, name, spec)
 	if err != nil {
@@ -118,26 +120,27 @@ func Create(f *object.Folder, name string, spec types.ClusterConfigSpecEx) (*obj
 }
 
 // Rename renames a ClusterComputeResource.
-func Rename(cluster *object.ClusterComputeResource, name string) error {
+func Rename(ctx context.Context, cluster *object.ClusterComputeResource, name string) error {
 	log.Printf("[DEBUG] Renaming compute cluster %q to %s", cluster.InventoryPath, name)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := cluster.Rename(ctx, name)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // MoveToFolder is a complex method that moves a ClusterComputeResource to a given relative
 // compute folder path. "Relative" here means relative to a datacenter, which
 // is discovered from the current ClusterComputeResource path.
-func MoveToFolder(client *govmomi.Client, cluster *object.ClusterComputeResource, relative string) error {
+func MoveToFolder(ctx context.Context, client *govmomi.Client, cluster *object.ClusterComputeResource, relative string) error {
 	f, err := folder.HostFolderFromObject(client, cluster, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(cluster.Reference(), f)
+	return folder.MoveObjectTo(ctx, cluster.Reference(), f)
 }
 
 // HasChildren checks to see if a compute cluster has any child items (hosts
@@ -150,22 +153,17 @@ func HasChildren(cluster *object.ClusterComputeResource) (bool, error) {
 	return computeresource.HasChildren(cluster)
 }
 
-// Reconfigure reconfigures a cluster. This just gets dispatched to
-// computeresource as both methods are the same.
-func Reconfigure(cluster *object.ClusterComputeResource, spec *types.ClusterConfigSpecEx) error {
-	return computeresource.Reconfigure(cluster, spec)
-}
-
 // Delete destroys a ClusterComputeResource.
-func Delete(cluster *object.ClusterComputeResource) error {
+func Delete(ctx context.Context, cluster *object.ClusterComputeResource) error {
 	log.Printf("[DEBUG] Deleting compute cluster %q", cluster.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := cluster.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 func Hosts(cluster *object.ClusterComputeResource) ([]*object.HostSystem, error) {
@@ -176,7 +174,7 @@ func Hosts(cluster *object.ClusterComputeResource) ([]*object.HostSystem, error)
 // MoveHostsInto moves all of the supplied hosts into the cluster. All virtual
 // machines are moved to the cluster's root resource pool and any resource
 // pools on the host itself are deleted.
-func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResource, hosts []*object.HostSystem) error {
+func MoveHostsInto(ctx context.Context, client *govmomi.Client, cluster *object.ClusterComputeResource, hosts []*object.HostSystem) error {
 	var hsNames []string
 	var hsRefs []types.ManagedObjectReference
 
@@ -216,7 +214,7 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 			}
 
 			totalVMTimeout := provider.DefaultAPITimeout * time.Duration(len(hsProps.Vm)+1)
-			err = hostsystem.EnterMaintenanceMode(hs, totalVMTimeout, evacuate)
+			err = hostsystem.EnterMaintenanceMode(ctx, hs, totalVMTimeout, evacuate)
 			if err != nil {
 				return fmt.Errorf("while putting host %q in maintenance mode: %s", hs.Reference().Value, err)
 			}
@@ -229,7 +227,7 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 		Host: hsRefs,
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	resp, err := methods.MoveInto_Task(ctx, cluster.Client(), &req)
 	if err != nil {
@@ -237,7 +235,8 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 	}
 
 	task := object.NewTask(cluster.Client(), resp.Returnval)
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // MoveHostsOutOf moves a supplied list of hosts out of the specified cluster.
@@ -253,19 +252,19 @@ func MoveHostsInto(client *govmomi.Client, cluster *object.ClusterComputeResourc
 //
 // Individual hosts are taken out of maintenance mode after its operation is
 // complete.
-func MoveHostsOutOf(cluster *object.ClusterComputeResource, hosts []*object.HostSystem, timeout int) error {
+func MoveHostsOutOf(ctx context.Context, cluster *object.ClusterComputeResource, hosts []*object.HostSystem, timeout int) error {
 	for _, host := range hosts {
-		if err := moveHostOutOf(cluster, host, timeout); err != nil {
+		if err := moveHostOutOf(ctx, cluster, host, timeout); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func moveHostOutOf(cluster *object.ClusterComputeResource, host *object.HostSystem, timeout int) error {
+func moveHostOutOf(ctx context.Context, cluster *object.ClusterComputeResource, host *object.HostSystem, timeout int) error {
 	// Place the host into maintenance mode. This blocks until the host is ready.
 	timeoutDuration := time.Duration(timeout) * time.Second
-	if err := hostsystem.EnterMaintenanceMode(host, timeoutDuration, true); err != nil {
+	if err := hostsystem.EnterMaintenanceMode(ctx, host, timeoutDuration, true); err != nil {
 		return fmt.Errorf("error putting host %q into maintenance mode: %s", host.Name(), err)
 	}
 
@@ -275,12 +274,12 @@ func moveHostOutOf(cluster *object.ClusterComputeResource, host *object.HostSyst
 		return err
 	}
 	log.Printf("[DEBUG] Moving host %q out of cluster %q and to folder %q", host.Name(), cluster.Name(), f.InventoryPath)
-	if err := folder.MoveObjectTo(host.Reference(), f); err != nil {
+	if err := folder.MoveObjectTo(ctx, host.Reference(), f); err != nil {
 		return fmt.Errorf("error moving host %q out of cluster %q: %s", host.Name(), cluster.Name(), err)
 	}
 
 	// Move the host out of maintenance mode now that it's out of the cluster.
-	if err := hostsystem.ExitMaintenanceMode(host, timeoutDuration); err != nil {
+	if err := hostsystem.ExitMaintenanceMode(ctx, host, timeoutDuration); err != nil {
 		return fmt.Errorf("error taking host %q out of maintenance mode: %s", host.Name(), err)
 	}
 
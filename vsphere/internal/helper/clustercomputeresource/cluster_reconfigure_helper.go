@@ -0,0 +1,83 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clustercomputeresource
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/batch"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/computeresource"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// batcher combines the changes submitted with ReconfigureBatched. Changes are
+// held back for a second, so that the changes made by resources applied in
+// parallel can be sent to the cluster in a single reconfiguration.
+var batcher = batch.NewBatcher(time.Second)
+
+// clusterKey returns the key of cluster in batcher. Clusters are keyed on the
+// vCenter they belong to as well as their ID, as managed object IDs are only
+// unique within a vCenter.
+func clusterKey(cluster *object.ClusterComputeResource) string {
+	return cluster.Client().URL().Host + "/" + cluster.Reference().Value
+}
+
+// Reconfigure reconfigures a cluster. This just gets dispatched to
+// computeresource as both methods are the same. Reconfigurations of the same
+// cluster are serialized, so that they do not fail or overwrite each other
+// when they run in parallel.
+func Reconfigure(ctx context.Context, cluster *object.ClusterComputeResource, spec *types.ClusterConfigSpecEx) error {
+	unlock, err := batcher.Lock(ctx, clusterKey(cluster))
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return computeresource.Reconfigure(ctx, cluster, spec)
+}
+
+// ReconfigureBatched sends the rule, group and override changes in spec to the
+// cluster, along with the changes submitted for the same cluster by other
+// resources at about the same time. This saves a reconfiguration of the
+// cluster per resource when many rules, groups or overrides are managed at
+// once.
+//
+// Only the RulesSpec, GroupSpec, DrsVmConfigSpec, DasVmConfigSpec,
+// DpmHostConfigSpec and VmOrchestrationSpec fields of spec are sent. When a
+// combined reconfiguration fails, every change of it fails with its error, as
+// some of the changes may have been made before the failure. The next plan
+// then reads what was made.
+//
+// The batch is sent in the background, with a timeout of its own. If ctx is
+// done before the batch has been sent, spec is taken out of it and the error
+// of ctx is returned.
+func ReconfigureBatched(ctx context.Context, cluster *object.ClusterComputeResource, spec *types.ClusterConfigSpecEx) error {
+	_, err := batcher.Submit(ctx, clusterKey(cluster), spec, provider.DefaultAPITimeout, func(ctx context.Context, changes []interface{}) ([]interface{}, error) {
+		specs := make([]*types.ClusterConfigSpecEx, len(changes))
+		for i, c := range changes {
+			specs[i] = c.(*types.ClusterConfigSpecEx)
+		}
+		log.Printf("[DEBUG] Sending %d change(s) to cluster %q in a single reconfiguration", len(specs), cluster.InventoryPath)
+		return nil, computeresource.Reconfigure(ctx, cluster, mergeClusterConfigSpecs(specs))
+	})
+	return err
+}
+
+// mergeClusterConfigSpecs combines the rule, group and override changes of
+// specs into a single spec.
+func mergeClusterConfigSpecs(specs []*types.ClusterConfigSpecEx) *types.ClusterConfigSpecEx {
+	merged := &types.ClusterConfigSpecEx{}
+	for _, spec := range specs {
+		merged.RulesSpec = append(merged.RulesSpec, spec.RulesSpec...)
+		merged.GroupSpec = append(merged.GroupSpec, spec.GroupSpec...)
+		merged.DrsVmConfigSpec = append(merged.DrsVmConfigSpec, spec.DrsVmConfigSpec...)
+		merged.DasVmConfigSpec = append(merged.DasVmConfigSpec, spec.DasVmConfigSpec...)
+		merged.DpmHostConfigSpec = append(merged.DpmHostConfigSpec, spec.DpmHostConfigSpec...)
+		merged.VmOrchestrationSpec = append(merged.VmOrchestrationSpec, spec.VmOrchestrationSpec...)
+	}
+	return merged
+}
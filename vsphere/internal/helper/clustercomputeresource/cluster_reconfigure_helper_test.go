@@ -0,0 +1,163 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package clustercomputeresource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func testVMGroupSpec(name string) *types.ClusterConfigSpecEx {
+	return &types.ClusterConfigSpecEx{
+		GroupSpec: []types.ClusterGroupSpec{
+			{
+				ArrayUpdateSpec: types.ArrayUpdateSpec{
+					Operation: types.ArrayUpdateOperationAdd,
+				},
+				Info: &types.ClusterVmGroup{
+					ClusterGroupInfo: types.ClusterGroupInfo{Name: name},
+				},
+			},
+		},
+	}
+}
+
+func TestReconfigureBatched(t *testing.T) {
+	defer func(d time.Duration) { batcher.Delay = d }(batcher.Delay)
+	batcher.Delay = 200 * time.Millisecond
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		cluster, err := find.NewFinder(c).DefaultClusterComputeResource(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := Reconfigure(ctx, cluster, testVMGroupSpec("existing")); err != nil {
+			t.Fatal(err)
+		}
+
+		names := []string{"group0", "group1", "group2"}
+		errs := make([]error, len(names))
+		var wg sync.WaitGroup
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				errs[i] = ReconfigureBatched(ctx, cluster, testVMGroupSpec(name))
+			}(i, name)
+		}
+		wg.Wait()
+		for i, err := range errs {
+			if err != nil {
+				t.Fatalf("unexpected error for %q: %s", names[i], err)
+			}
+		}
+		expected := append([]string{"existing"}, names...)
+		if actual := testClusterGroups(ctx, t, cluster); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("expected groups %v, got %v", expected, actual)
+		}
+
+		// A failed reconfiguration fails every change of it, and no change is
+		// sent again. The simulator applies the changes of a spec in order, so
+		// group3 is made before the duplicate group fails the reconfiguration.
+		names = []string{"group3", "existing"}
+		for i, name := range names {
+			wg.Add(1)
+			go func(i int, name string) {
+				defer wg.Done()
+				errs[i] = ReconfigureBatched(ctx, cluster, testVMGroupSpec(name))
+			}(i, name)
+			time.Sleep(10 * time.Millisecond)
+		}
+		wg.Wait()
+		for i, err := range errs[:len(names)] {
+			if err == nil {
+				t.Fatalf("expected an error for %q", names[i])
+			}
+		}
+		expected = append(expected, "group3")
+		if actual := testClusterGroups(ctx, t, cluster); fmt.Sprint(actual) != fmt.Sprint(expected) {
+			t.Fatalf("expected groups %v, got %v", expected, actual)
+		}
+	})
+}
+
+func testClusterGroups(ctx context.Context, t *testing.T, cluster *object.ClusterComputeResource) []string {
+	var props mo.ClusterComputeResource
+	if err := cluster.Properties(ctx, cluster.Reference(), []string{"configurationEx"}, &props); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, g := range props.ConfigurationEx.(*types.ClusterConfigInfoEx).Group {
+		names = append(names, g.GetClusterGroupInfo().Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestReconfigureBatchedCancelled(t *testing.T) {
+	defer func(d time.Duration) { batcher.Delay = d }(batcher.Delay)
+	batcher.Delay = 200 * time.Millisecond
+
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		cluster, err := find.NewFinder(c).DefaultClusterComputeResource(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		if err := ReconfigureBatched(cctx, cluster, testVMGroupSpec("cancelled")); err != context.DeadlineExceeded {
+			t.Fatalf("expected %s, got %v", context.DeadlineExceeded, err)
+		}
+		if err := ReconfigureBatched(ctx, cluster, testVMGroupSpec("kept")); err != nil {
+			t.Fatal(err)
+		}
+
+		if groups := testClusterGroups(ctx, t, cluster); fmt.Sprint(groups) != "[kept]" {
+			t.Fatalf("expected only the group that was not cancelled, got %v", groups)
+		}
+	})
+}
+
+func TestMergeClusterConfigSpecs(t *testing.T) {
+	specs := []*types.ClusterConfigSpecEx{
+		testVMGroupSpec("group0"),
+		{
+			RulesSpec: []types.ClusterRuleSpec{
+				{
+					ArrayUpdateSpec: types.ArrayUpdateSpec{
+						Operation: types.ArrayUpdateOperationRemove,
+						RemoveKey: int32(1),
+					},
+				},
+			},
+			DrsVmConfigSpec: []types.ClusterDrsVmConfigSpec{
+				{
+					ArrayUpdateSpec: types.ArrayUpdateSpec{
+						Operation: types.ArrayUpdateOperationAdd,
+					},
+				},
+			},
+		},
+		testVMGroupSpec("group1"),
+	}
+	merged := mergeClusterConfigSpecs(specs)
+	if len(merged.GroupSpec) != 2 || len(merged.RulesSpec) != 1 || len(merged.DrsVmConfigSpec) != 1 {
+		t.Fatalf("unexpected merged spec: %#v", merged)
+	}
+	if merged.GroupSpec[1].Info.GetClusterGroupInfo().Name != "group1" {
+		t.Fatalf("expected the order of the changes to be kept")
+	}
+}
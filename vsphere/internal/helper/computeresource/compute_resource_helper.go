@@ -13,6 +13,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/envbrowse"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -209,7 +210,7 @@ func EnvironmentBrowserFromReference(client *govmomi.Client, ref types.ManagedOb
 // Reconfigure reconfigures any BaseComputeResource that uses a
 // BaseComputeResourceConfigSpec as configuration (example: standalone hosts,
 // or clusters). Modify is always set.
-func Reconfigure(obj BaseComputeResource, spec types.BaseComputeResourceConfigSpec) error {
+func Reconfigure(ctx context.Context, obj BaseComputeResource, spec types.BaseComputeResourceConfigSpec) error {
 	var c *object.ComputeResource
 	switch t := obj.(type) {
 	case *object.ComputeResource:
@@ -222,13 +223,14 @@ func Reconfigure(obj BaseComputeResource, spec types.BaseComputeResourceConfigSp
 		return fmt.Errorf("unsupported type for reconfigure: %T", t)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := c.Reconfigure(ctx, spec, true)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // HasChildren checks to see if a compute resource has any child items (hosts
@@ -20,7 +20,9 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/datastore"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/ovfdeploy"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/transport"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/ovf"
 	"github.com/vmware/govmomi/vapi/library"
@@ -62,11 +64,10 @@ func FromID(c *rest.Client, id string) (*library.Library, error) {
 }
 
 // CreateLibrary creates a Content Library.
-func CreateLibrary(d *schema.ResourceData, restclient *rest.Client, backings []library.StorageBackings) (string, error) {
+func CreateLibrary(ctx context.Context, d *schema.ResourceData, restclient *rest.Client, backings []library.StorageBackings) (string, error) {
 	name := d.Get("name").(string)
 	log.Printf("[DEBUG] contentlibrary.CreateLibrary: Creating content library %s", name)
 	clm := library.NewManager(restclient)
-	ctx := context.TODO()
 	description := d.Get("description").(string)
 	lib := library.Library{
 		Description: &description,
@@ -76,21 +77,29 @@ func CreateLibrary(d *schema.ResourceData, restclient *rest.Client, backings []l
 	}
 	if len(d.Get("publication").([]interface{})) > 0 {
 		publication := d.Get("publication").([]interface{})[0].(map[string]interface{})
+		password, err := secret.Get(d, "publication.0.password")
+		if err != nil {
+			return "", err
+		}
 		lib.Publication = &library.Publication{
 			Published:            structure.BoolPtr(publication["published"].(bool)),
 			AuthenticationMethod: publication["authentication_method"].(string),
 			UserName:             publication["username"].(string),
-			Password:             publication["password"].(string),
+			Password:             password,
 		}
 	}
 	if len(d.Get("subscription").([]interface{})) > 0 {
 		subscription := d.Get("subscription").([]interface{})[0].(map[string]interface{})
+		password, err := secret.Get(d, "subscription.0.password")
+		if err != nil {
+			return "", err
+		}
 		lib.Subscription = &library.Subscription{
 			AutomaticSyncEnabled: structure.BoolPtr(subscription["automatic_sync"].(bool)),
 			OnDemand:             structure.BoolPtr(subscription["on_demand"].(bool)),
 			AuthenticationMethod: subscription["authentication_method"].(string),
 			UserName:             subscription["username"].(string),
-			Password:             subscription["password"].(string),
+			Password:             password,
 			SubscriptionURL:      subscription["subscription_url"].(string),
 		}
 		lib.Type = "SUBSCRIBED"
@@ -104,10 +113,9 @@ func CreateLibrary(d *schema.ResourceData, restclient *rest.Client, backings []l
 }
 
 // DeleteLibrary deletes a Content Library.
-func DeleteLibrary(c *rest.Client, lib *library.Library) error {
+func DeleteLibrary(ctx context.Context, c *rest.Client, lib *library.Library) error {
 	log.Printf("[DEBUG] contentlibrary.DeleteLibrary: Deleting library %s", lib.Name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	err := clm.DeleteLibrary(ctx, lib)
 	if err != nil {
 		return provider.Error(lib.ID, "DeleteLibrary", err)
@@ -161,10 +169,9 @@ func IsContentLibraryItem(c *rest.Client, id string) bool {
 }
 
 // CreateLibraryItem creates an item in a Content Library.
-func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc string, t string, file string, moid string) (*string, error) {
+func CreateLibraryItem(ctx context.Context, c *rest.Client, l *library.Library, name string, desc string, t string, file string, moid string, settings *transport.Settings) (*string, error) {
 	log.Printf("[DEBUG] contentlibrary.CreateLibraryItem: Creating content library item %s.", name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	item := library.Item{
 		Description: &desc,
 		LibraryID:   l.ID,
@@ -175,9 +182,10 @@ func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc str
 		ContentLibraryManager: clm,
 		RestClient:            c,
 		LibraryID:             l.ID,
+		Transport:             settings,
 	}
 	if moid != "" {
-		return uploadSession.cloneTemplate(moid, name, t)
+		return uploadSession.cloneTemplate(ctx, moid, name, t)
 	}
 
 	id, err := clm.CreateLibraryItem(ctx, item)
@@ -207,30 +215,29 @@ func CreateLibraryItem(c *rest.Client, l *library.Library, name string, desc str
 		isIso = true
 	}
 
-	ovfDescriptor, err := ovfdeploy.GetOvfDescriptor(file, isOva, isLocal, true)
+	ovfDescriptor, err := ovfdeploy.GetOvfDescriptor(file, isOva, isLocal, true, settings)
 	if err != nil {
 		return nil, provider.Error(name, "CreateLibraryItem", err)
 	}
 
 	switch {
 	case isLocal && isOva:
-		return &id, uploadSession.deployLocalOva(file, ovfDescriptor)
+		return &id, uploadSession.deployLocalOva(ctx, file, ovfDescriptor)
 	case isLocal && !isOva && !isIso:
-		return &id, uploadSession.deployLocalOvf(file, ovfDescriptor)
+		return &id, uploadSession.deployLocalOvf(ctx, file, ovfDescriptor)
 	case isLocal && isIso:
-		return &id, uploadSession.deployLocalIso(file)
+		return &id, uploadSession.deployLocalIso(ctx, file)
 	case !isLocal && isOva:
-		return &id, uploadSession.deployRemoteOva(file, ovfDescriptor)
+		return &id, uploadSession.deployRemoteOva(ctx, file, ovfDescriptor)
 	case !isLocal && !isOva:
-		return &id, uploadSession.deployRemoteOvf(file)
+		return &id, uploadSession.deployRemoteOvf(ctx, file)
 	}
 
 	log.Printf("[DEBUG] contentlibrary.CreateLibraryItem: Successfully created content library item %s.", name)
 	return &id, nil
 }
 
-func (uploadSession *libraryUploadSession) deployRemoteOvf(file string) error {
-	ctx := context.TODO()
+func (uploadSession *libraryUploadSession) deployRemoteOvf(ctx context.Context, file string) error {
 	_, err := uploadSession.ContentLibraryManager.AddLibraryItemFileFromURI(ctx, uploadSession.UploadSession, filepath.Base(file), file)
 	if err != nil {
 		return err
@@ -238,54 +245,54 @@ func (uploadSession *libraryUploadSession) deployRemoteOvf(file string) error {
 	return uploadSession.ContentLibraryManager.WaitOnLibraryItemUpdateSession(ctx, uploadSession.UploadSession, time.Second*10, func() { log.Printf("Waiting...") })
 }
 
-func (uploadSession *libraryUploadSession) deployRemoteOva(file string, ovfDescriptor string) error {
+func (uploadSession *libraryUploadSession) deployRemoteOva(ctx context.Context, file string, ovfDescriptor string) error {
 	e, err := readEnvelope(ovfDescriptor)
 	if err != nil {
 		return fmt.Errorf("failed to parse ovf: %s", err)
 	}
 	name := strings.TrimSuffix(filepath.Base(file), "ova")
-	if err := uploadSession.uploadString(ovfDescriptor, name+"ovf"); err != nil {
+	if err := uploadSession.uploadString(ctx, ovfDescriptor, name+"ovf"); err != nil {
 		return err
 	}
 	for _, disk := range e.References {
-		if err := uploadSession.uploadOvaDisksFromURL(file, disk.Href, int64(disk.Size)); err != nil {
+		if err := uploadSession.uploadOvaDisksFromURL(ctx, file, disk.Href, int64(disk.Size)); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (uploadSession *libraryUploadSession) deployLocalOvf(file string, ovfDescriptor string) error {
+func (uploadSession *libraryUploadSession) deployLocalOvf(ctx context.Context, file string, ovfDescriptor string) error {
 	e, err := readEnvelope(ovfDescriptor)
 	if err != nil {
 		return fmt.Errorf("failed to parse ovf: %s", err)
 	}
-	if err := uploadSession.uploadLocalFile(file); err != nil {
+	if err := uploadSession.uploadLocalFile(ctx, file); err != nil {
 		return err
 	}
 	dir := filepath.Dir(file)
 	for i := range e.References {
-		if err := uploadSession.uploadLocalFile(dir + "/" + e.References[i].Href); err != nil {
+		if err := uploadSession.uploadLocalFile(ctx, dir+"/"+e.References[i].Href); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-func (uploadSession *libraryUploadSession) deployLocalOva(file string, ovfDescriptor string) error {
+func (uploadSession *libraryUploadSession) deployLocalOva(ctx context.Context, file string, ovfDescriptor string) error {
 	e, err := readEnvelope(ovfDescriptor)
 	if err != nil {
 		return fmt.Errorf("failed to parse ovf: %s", err)
 	}
 	name := strings.TrimSuffix(filepath.Base(file), "ova")
-	if err := uploadSession.uploadString(ovfDescriptor, name+"ovf"); err != nil {
+	if err := uploadSession.uploadString(ctx, ovfDescriptor, name+"ovf"); err != nil {
 		return err
 	}
-	return uploadSession.uploadOvaDisksFromLocal(file, e)
+	return uploadSession.uploadOvaDisksFromLocal(ctx, file, e)
 }
 
-func (uploadSession *libraryUploadSession) deployLocalIso(file string) error {
-	if err := uploadSession.uploadLocalFile(file); err != nil {
+func (uploadSession *libraryUploadSession) deployLocalIso(ctx context.Context, file string) error {
+	if err := uploadSession.uploadLocalFile(ctx, file); err != nil {
 		return err
 	}
 	return nil
@@ -296,10 +303,10 @@ type libraryUploadSession struct {
 	RestClient            *rest.Client
 	UploadSession         string
 	LibraryID             string
+	Transport             *transport.Settings
 }
 
-func (uploadSession libraryUploadSession) cloneTemplate(moid string, name string, templateType string) (*string, error) {
-	ctx := context.TODO()
+func (uploadSession libraryUploadSession) cloneTemplate(ctx context.Context, moid string, name string, templateType string) (*string, error) {
 	if templateType == "ovf" {
 		ovfItem := vcenter.OVF{
 			Spec: vcenter.CreateSpec{
@@ -321,20 +328,20 @@ func (uploadSession libraryUploadSession) cloneTemplate(moid string, name string
 	return nil, fmt.Errorf("Unsupported template type. Only ovf can be used when cloning from vCenter")
 }
 
-func (uploadSession libraryUploadSession) uploadString(data string, name string) error {
+func (uploadSession libraryUploadSession) uploadString(ctx context.Context, data string, name string) error {
 	stringReader := strings.NewReader(data)
 	openFile := io.Reader(stringReader)
 	size := int64(len([]byte(data)))
-	return uploadSession.upload(name, &openFile, size)
+	return uploadSession.upload(ctx, name, &openFile, size)
 }
 
-func (uploadSession libraryUploadSession) uploadLocalFile(file string) error {
+func (uploadSession libraryUploadSession) uploadLocalFile(ctx context.Context, file string) error {
 	openFile, size, err := openLocalFile(file)
 	if err != nil {
 		return err
 	}
 
-	return uploadSession.upload(filepath.Base(file), openFile, *size)
+	return uploadSession.upload(ctx, filepath.Base(file), openFile, *size)
 }
 
 func openLocalFile(file string) (*io.Reader, *int64, error) {
@@ -351,7 +358,7 @@ func openLocalFile(file string) (*io.Reader, *int64, error) {
 	return &openFileReader, &size, nil
 }
 
-func (uploadSession libraryUploadSession) uploadOvaDisksFromLocal(ovaFilePath string, envelope *ovf.Envelope) error {
+func (uploadSession libraryUploadSession) uploadOvaDisksFromLocal(ctx context.Context, ovaFilePath string, envelope *ovf.Envelope) error {
 	ovaFile, _, err := openLocalFile(ovaFilePath)
 	if err != nil {
 		return err
@@ -360,20 +367,24 @@ func (uploadSession libraryUploadSession) uploadOvaDisksFromLocal(ovaFilePath st
 	for _, disk := range envelope.References {
 		size := disk.Size
 		fileName := disk.Href
-		if err = uploadSession.findAndUploadDiskFromOva(*ovaFile, fileName, int64(size)); err != nil {
+		if err = uploadSession.findAndUploadDiskFromOva(ctx, *ovaFile, fileName, int64(size)); err != nil {
 			return err
 		}
 	}
 	return err
 }
 
-func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ovfFilePath string, diskName string, size int64) error {
-	resp, err := http.Get(ovfFilePath)
+func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ctx context.Context, ovfFilePath string, diskName string, size int64) error {
+	client, err := uploadSession.Transport.HTTPClient(false)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Get(ovfFilePath)
 	if err != nil {
 		return err
 	}
 	if resp.StatusCode == http.StatusOK {
-		err = uploadSession.findAndUploadDiskFromOva(resp.Body, diskName, size)
+		err = uploadSession.findAndUploadDiskFromOva(ctx, resp.Body, diskName, size)
 		if err != nil {
 			return err
 		}
@@ -383,7 +394,7 @@ func (uploadSession libraryUploadSession) uploadOvaDisksFromURL(ovfFilePath stri
 	return nil
 }
 
-func (uploadSession libraryUploadSession) findAndUploadDiskFromOva(ovaFile io.Reader, diskName string, size int64) error {
+func (uploadSession libraryUploadSession) findAndUploadDiskFromOva(ctx context.Context, ovaFile io.Reader, diskName string, size int64) error {
 	log.Printf("[DEBUG] findAndUploadDiskFromOva: Finding %s", diskName)
 	ovaReader := tar.NewReader(ovaFile)
 	for {
@@ -397,7 +408,7 @@ func (uploadSession libraryUploadSession) findAndUploadDiskFromOva(ovaFile io.Re
 		if fileHdr.Name == diskName {
 			log.Printf("[DEBUG] findAndUploadDiskFromOva: %s found", diskName)
 			ioOvaReader := io.Reader(ovaReader)
-			err = uploadSession.upload(diskName, &ioOvaReader, size)
+			err = uploadSession.upload(ctx, diskName, &ioOvaReader, size)
 			if err != nil {
 				return fmt.Errorf("error while uploading the file %s %s", diskName, err)
 			}
@@ -416,8 +427,7 @@ func readEnvelope(data string) (*ovf.Envelope, error) {
 	return e, nil
 }
 
-func (uploadSession libraryUploadSession) upload(name string, file *io.Reader, size int64) error {
-	ctx := context.TODO()
+func (uploadSession libraryUploadSession) upload(ctx context.Context, name string, file *io.Reader, size int64) error {
 
 	info := library.UpdateFile{
 		Name:       name,
@@ -440,10 +450,9 @@ func (uploadSession libraryUploadSession) upload(name string, file *io.Reader, s
 }
 
 // DeleteLibraryItem deletes an item from a Content Library.
-func DeleteLibraryItem(c *rest.Client, item *library.Item) error {
+func DeleteLibraryItem(ctx context.Context, c *rest.Client, item *library.Item) error {
 	log.Printf("[DEBUG] contentlibrary.DeleteLibraryItem: Deleting content library item %s.", item.Name)
 	clm := library.NewManager(c)
-	ctx := context.TODO()
 	err := clm.DeleteLibraryItem(ctx, item)
 	if err != nil {
 		return err
@@ -479,7 +488,7 @@ func FlattenPublication(d *schema.ResourceData, publication *library.Publication
 	flatPublication := map[string]interface{}{}
 	flatPublication["authentication_method"] = publication.AuthenticationMethod
 	flatPublication["username"] = publication.UserName
-	flatPublication["password"] = d.Get("publication.0.password").(string)
+	flatPublication["password"] = secret.Refingerprint(d, "publication.0.password", d.Get("publication.0.password").(string))
 	flatPublication["publish_url"] = publication.PublishURL
 	flatPublication["published"] = publication.Published
 	return d.Set("publication", []interface{}{flatPublication})
@@ -494,7 +503,7 @@ func FlattenSubscription(d *schema.ResourceData, subscription *library.Subscript
 	flatSubscription := map[string]interface{}{}
 	flatSubscription["authentication_method"] = subscription.AuthenticationMethod
 	flatSubscription["username"] = subscription.UserName
-	flatSubscription["password"] = d.Get("subscription.0.password").(string)
+	flatSubscription["password"] = secret.Refingerprint(d, "subscription.0.password", d.Get("subscription.0.password").(string))
 	flatSubscription["subscription_url"] = subscription.SubscriptionURL
 	flatSubscription["automatic_sync"] = subscription.AutomaticSyncEnabled
 	flatSubscription["on_demand"] = subscription.OnDemand
@@ -12,6 +12,7 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -90,17 +91,18 @@ func getDatastores(client *govmomi.Client, path string) ([]*object.Datastore, er
 
 // Properties is a convenience method that wraps fetching the
 // Datastore MO from its higher-level object.
-func Properties(ds *object.Datastore) (*mo.Datastore, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(ds *object.Datastore, paths ...string) (*mo.Datastore, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.Datastore
-	if err := ds.Properties(ctx, ds.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, ds.Client(), ds.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
 }
 
-func Unmount(client *govmomi.Client, ds *object.Datastore) error {
+func Unmount(ctx context.Context, client *govmomi.Client, ds *object.Datastore) error {
 	dsprops, err := Properties(ds)
 	if err != nil {
 		return err
@@ -110,11 +112,11 @@ func Unmount(client *govmomi.Client, ds *object.Datastore) error {
 		if err != nil {
 			return err
 		}
-		hds, err := host.ConfigManager().DatastoreSystem(context.TODO())
+		hds, err := host.ConfigManager().DatastoreSystem(ctx)
 		if err != nil {
 			return err
 		}
-		err = hds.Remove(context.TODO(), ds)
+		err = hds.Remove(ctx, ds)
 		if err != nil {
 			return err
 		}
@@ -125,19 +127,19 @@ func Unmount(client *govmomi.Client, ds *object.Datastore) error {
 // MoveToFolder is a complex method that moves a datastore to a given
 // relative datastore folder path. "Relative" here means relative to a
 // datacenter, which is discovered from the current datastore path.
-func MoveToFolder(client *govmomi.Client, ds *object.Datastore, relative string) error {
+func MoveToFolder(ctx context.Context, client *govmomi.Client, ds *object.Datastore, relative string) error {
 	f, err := folder.DatastoreFolderFromObject(client, ds, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(ds.Reference(), f)
+	return folder.MoveObjectTo(ctx, ds.Reference(), f)
 }
 
 // MoveToFolderRelativeHostSystemID is a complex method that moves a
 // datastore to a given datastore path, similar to MoveToFolder,
 // except the path is relative to a HostSystem supplied by ID instead of the
 // datastore.
-func MoveToFolderRelativeHostSystemID(client *govmomi.Client, ds *object.Datastore, hsID, relative string) error {
+func MoveToFolderRelativeHostSystemID(ctx context.Context, client *govmomi.Client, ds *object.Datastore, hsID, relative string) error {
 	hs, err := hostsystem.FromID(client, hsID)
 	if err != nil {
 		return err
@@ -146,7 +148,7 @@ func MoveToFolderRelativeHostSystemID(client *govmomi.Client, ds *object.Datasto
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(ds.Reference(), f)
+	return folder.MoveObjectTo(ctx, ds.Reference(), f)
 }
 
 // Browser returns the HostDatastoreBrowser for a certain datastore. This is a
@@ -161,8 +163,8 @@ func Browser(ds *object.Datastore) (*object.HostDatastoreBrowser, error) {
 // and a supplied path. The current implementation only returns the basic
 // information, so all FileQueryFlags set, but not any flags for specific types
 // of files.
-func SearchDatastore(ds *object.Datastore, name string) ([]*types.FileInfo, error) {
-	result, err := searchDatastore(ds, name)
+func SearchDatastore(ctx context.Context, ds *object.Datastore, name string) ([]*types.FileInfo, error) {
+	result, err := searchDatastore(ctx, ds, name)
 	if err != nil {
 		return nil, err
 	}
@@ -173,7 +175,7 @@ func SearchDatastore(ds *object.Datastore, name string) ([]*types.FileInfo, erro
 	return files, nil
 }
 
-func searchDatastore(ds *object.Datastore, name string) (*types.HostDatastoreBrowserSearchResults, error) {
+func searchDatastore(ctx context.Context, ds *object.Datastore, name string) (*types.HostDatastoreBrowserSearchResults, error) {
 	browser, err := Browser(ds)
 	if err != nil {
 		return nil, err
@@ -203,15 +205,11 @@ func searchDatastore(ds *object.Datastore, name string) (*types.HostDatastoreBro
 			Modification: true,
 		},
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
 	task, err := browser.SearchDatastore(ctx, dp.String(), spec)
 	if err != nil {
 		return nil, err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer tcancel()
-	info, err := task.WaitForResult(tctx, nil)
+	info, err := viapi.WaitForTask(ctx, task)
 	if err != nil {
 		return nil, err
 	}
@@ -222,8 +220,8 @@ func searchDatastore(ds *object.Datastore, name string) (*types.HostDatastoreBro
 // FileExists takes a path in the datastore and checks to see if it exists.
 //
 // The path should be a bare path, not a datastore path. Globs are not allowed.
-func FileExists(ds *object.Datastore, name string) (bool, error) {
-	files, err := SearchDatastore(ds, name)
+func FileExists(ctx context.Context, ds *object.Datastore, name string) (bool, error) {
+	files, err := SearchDatastore(ctx, ds, name)
 	if err != nil {
 		return false, err
 	}
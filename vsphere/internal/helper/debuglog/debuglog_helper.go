@@ -0,0 +1,341 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package debuglog writes the client debug logs enabled by client_debug: dumps
+// of each request and response with credentials redacted, or a trace of each
+// call in JSON lines.
+package debuglog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/redact"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// The formats of the client debug logs.
+const (
+	// FormatDump writes the headers and body of each request and response to
+	// files of their own. This is the format of govc's debug logs.
+	FormatDump = "dump"
+
+	// FormatTrace writes a line of JSON for each call to a single file.
+	FormatTrace = "trace"
+)
+
+// Formats are the valid formats of the client debug logs.
+var Formats = []string{FormatDump, FormatTrace}
+
+// TraceFile is the name of the file the trace is written to.
+const TraceFile = "trace.jsonl"
+
+// Provider is a debug.Provider that redacts credentials and session secrets
+// from the files it writes. Files are buffered in memory and written when
+// they are closed, so that a secret split across writes is still redacted.
+type Provider struct {
+	path string
+
+	mu       sync.Mutex
+	files    map[*file]struct{}
+	sessions map[string]struct{}
+}
+
+// NewProvider returns a Provider that writes files to the directory at path.
+func NewProvider(path string) *Provider {
+	return &Provider{
+		path:     path,
+		files:    make(map[*file]struct{}),
+		sessions: make(map[string]struct{}),
+	}
+}
+
+// NewFile implements debug.Provider for Provider.
+func (p *Provider) NewFile(name string) io.WriteCloser {
+	f := &file{
+		provider: p,
+		name:     name,
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.files[f] = struct{}{}
+	return f
+}
+
+// Flush implements debug.Provider for Provider. It writes the files that are
+// still open.
+func (p *Provider) Flush() {
+	p.mu.Lock()
+	files := make([]*file, 0, len(p.files))
+	for f := range p.files {
+		files = append(files, f)
+	}
+	p.mu.Unlock()
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
+// file is a debug file written by Provider. Its name is made of the client and
+// request numbers of the round trip, followed by its contents, for example
+// "1-0002.req.headers" or "1-0002.res.json".
+type file struct {
+	provider *Provider
+	name     string
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	closed bool
+}
+
+// Write implements io.Writer for file.
+func (f *file) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.buf.Write(b)
+}
+
+// Close implements io.Closer for file. It redacts the contents of the file and
+// writes it to disk.
+func (f *file) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+
+	p := f.provider
+	p.mu.Lock()
+	delete(p.files, f)
+	p.mu.Unlock()
+
+	roundTrip, kind, _ := strings.Cut(f.name, ".")
+	contents := f.buf.String()
+	switch kind {
+	case "req.headers":
+		if line, _, _ := strings.Cut(contents, "\n"); len(strings.Fields(line)) > 1 && redact.IsSessionPath(strings.Fields(line)[1]) {
+			p.mu.Lock()
+			p.sessions[roundTrip] = struct{}{}
+			p.mu.Unlock()
+		}
+		fallthrough
+	case "res.headers":
+		contents = redact.Headers(contents)
+	case "res.json":
+		p.mu.Lock()
+		_, ok := p.sessions[roundTrip]
+		delete(p.sessions, roundTrip)
+		p.mu.Unlock()
+		if ok {
+			contents = redact.JSONStrings(contents)
+		}
+		contents = redact.Body(contents)
+	default:
+		contents = redact.Body(contents)
+	}
+	return os.WriteFile(filepath.Join(p.path, f.name), []byte(contents), 0600)
+}
+
+// Entry is a line in a trace.
+type Entry struct {
+	// The time the call was made.
+	Time time.Time `json:"time"`
+
+	// The API the call was made to, either "soap" or "rest".
+	API string `json:"api"`
+
+	// The name of the SOAP method, or the HTTP method and path of the REST
+	// call.
+	Method string `json:"method"`
+
+	// The managed object the SOAP method was called on.
+	Type string `json:"type,omitempty"`
+	MOID string `json:"moid,omitempty"`
+
+	// The time the call took, in milliseconds.
+	Duration float64 `json:"duration_ms"`
+
+	// The HTTP status code of the REST call.
+	Status int `json:"status,omitempty"`
+
+	// The type of the fault the SOAP call failed with, or the error of a call
+	// that failed without a response.
+	Fault string `json:"fault,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Tracer writes a trace of calls as JSON lines. A nil Tracer writes nothing.
+type Tracer struct {
+	mu     sync.Mutex
+	w      io.Writer
+	f      *os.File
+	closed bool
+}
+
+// NewTracer returns a Tracer that writes to w.
+func NewTracer(w io.Writer) *Tracer {
+	return &Tracer{w: w}
+}
+
+// OpenTracer returns a Tracer that appends to the file at path, which is
+// created if it does not exist. The file is closed by Close.
+func OpenTracer(path string) (*Tracer, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Tracer{w: f, f: f}, nil
+}
+
+// Close flushes the trace to disk and closes the file of a Tracer returned by
+// OpenTracer. Calls traced after Close are not written.
+func (t *Tracer) Close() error {
+	if t == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return nil
+	}
+	t.closed = true
+	if t.f == nil {
+		return nil
+	}
+	err := t.f.Sync()
+	if cerr := t.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// write writes e to the trace.
+func (t *Tracer) write(e Entry) {
+	b, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	_, _ = t.w.Write(append(b, '\n'))
+}
+
+// RoundTripper returns a soap.RoundTripper that traces the calls made through
+// rt.
+func (t *Tracer) RoundTripper(rt soap.RoundTripper) soap.RoundTripper {
+	if t == nil {
+		return rt
+	}
+	return &traceRoundTripper{
+		RoundTripper: rt,
+		tracer:       t,
+	}
+}
+
+// Transport returns an http.RoundTripper that traces the requests made
+// through rt.
+func (t *Tracer) Transport(rt http.RoundTripper) http.RoundTripper {
+	if t == nil {
+		return rt
+	}
+	return &traceTransport{
+		RoundTripper: rt,
+		tracer:       t,
+	}
+}
+
+// traceRoundTripper traces SOAP calls.
+type traceRoundTripper struct {
+	soap.RoundTripper
+	tracer *Tracer
+}
+
+// Unwrap returns the soap.RoundTripper that traceRoundTripper sends calls
+// through.
+func (r *traceRoundTripper) Unwrap() soap.RoundTripper {
+	return r.RoundTripper
+}
+
+// RoundTrip implements soap.RoundTripper for traceRoundTripper.
+func (r *traceRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	e := Entry{
+		Time:   time.Now(),
+		API:    "soap",
+		Method: strings.TrimSuffix(reflect.Indirect(reflect.ValueOf(req)).Type().Name(), "Body"),
+	}
+	if ref, ok := this(req); ok {
+		e.Type = ref.Type
+		e.MOID = ref.Value
+	}
+	err := r.RoundTripper.RoundTrip(ctx, req, res)
+	e.Duration = float64(time.Since(e.Time).Microseconds()) / 1000
+	switch {
+	case soap.IsSoapFault(err):
+		if f := soap.ToSoapFault(err).VimFault(); f != nil {
+			e.Fault = reflect.Indirect(reflect.ValueOf(f)).Type().Name()
+		} else {
+			e.Fault = soap.ToSoapFault(err).Code
+		}
+	case err != nil:
+		e.Error = err.Error()
+	}
+	r.tracer.write(e)
+	return err
+}
+
+// this returns the managed object a SOAP method is called on, from the This
+// field of its request.
+func this(req soap.HasFault) (types.ManagedObjectReference, bool) {
+	v := reflect.Indirect(reflect.ValueOf(req))
+	if v.Kind() != reflect.Struct {
+		return types.ManagedObjectReference{}, false
+	}
+	r := reflect.Indirect(v.FieldByName("Req"))
+	if r.Kind() != reflect.Struct {
+		return types.ManagedObjectReference{}, false
+	}
+	f := r.FieldByName("This")
+	if !f.IsValid() {
+		return types.ManagedObjectReference{}, false
+	}
+	ref, ok := f.Interface().(types.ManagedObjectReference)
+	return ref, ok
+}
+
+// traceTransport traces REST API requests.
+type traceTransport struct {
+	http.RoundTripper
+	tracer *Tracer
+}
+
+// RoundTrip implements http.RoundTripper for traceTransport.
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	e := Entry{
+		Time:   time.Now(),
+		API:    "rest",
+		Method: req.Method + " " + req.URL.Path,
+	}
+	res, err := t.RoundTripper.RoundTrip(req)
+	e.Duration = float64(time.Since(e.Time).Microseconds()) / 1000
+	if err != nil {
+		e.Error = err.Error()
+	} else {
+		e.Status = res.StatusCode
+	}
+	t.tracer.write(e)
+	return res, err
+}
@@ -0,0 +1,182 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package debuglog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func testWriteFile(t *testing.T, p *Provider, name string, chunks ...string) string {
+	t.Helper()
+	f := p.NewFile(name)
+	for _, c := range chunks {
+		if _, err := f.Write([]byte(c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	b, err := os.ReadFile(filepath.Join(p.path, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestProvider(t *testing.T) {
+	p := NewProvider(t.TempDir())
+
+	// The password is split across writes, as it is when a body is streamed.
+	actual := testWriteFile(t, p, "1-0001.req.xml", `<Login><userName>user</userName><pass`, `word>hunter2</password></Login>`)
+	if strings.Contains(actual, "hunter2") {
+		t.Fatalf("expected the login password to be redacted, got %q", actual)
+	}
+
+	actual = testWriteFile(t, p, "1-0002.req.headers", "POST /rest/com/vmware/cis/session HTTP/1.1\r\nAuthorization: Basic dXNlcjpodW50ZXIy\r\n\r\n")
+	if strings.Contains(actual, "dXNlcjpodW50ZXIy") {
+		t.Fatalf("expected the authorization header to be redacted, got %q", actual)
+	}
+	actual = testWriteFile(t, p, "1-0002.res.json", `{"value":"3c9d2ba1f3e1"}`)
+	if strings.Contains(actual, "3c9d2ba1f3e1") {
+		t.Fatalf("expected the session ID to be redacted, got %q", actual)
+	}
+
+	actual = testWriteFile(t, p, "1-0003.res.json", `{"value":"urn:vmomi:InventoryServiceTag:1:GLOBAL"}`)
+	if !strings.Contains(actual, "InventoryServiceTag") {
+		t.Fatalf("expected the response of a call other than login to be kept, got %q", actual)
+	}
+
+	f := p.NewFile("1-0004.res.xml")
+	_, _ = f.Write([]byte("<RetrieveServiceContentResponse/>"))
+	p.Flush()
+	if _, err := os.Stat(filepath.Join(p.path, "1-0004.res.xml")); err != nil {
+		t.Fatalf("expected open files to be written on flush: %s", err)
+	}
+}
+
+// testRoundTripper returns err for every call made through it.
+type testRoundTripper struct {
+	err error
+}
+
+func (rt testRoundTripper) RoundTrip(_ context.Context, _, _ soap.HasFault) error {
+	return rt.err
+}
+
+func testTrace(t *testing.T, b *bytes.Buffer) []Entry {
+	t.Helper()
+	var entries []Entry
+	for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+		var e Entry
+		if err := json.Unmarshal([]byte(line), &e); err != nil {
+			t.Fatalf("bad trace line %q: %s", line, err)
+		}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+func TestTracer(t *testing.T) {
+	var b bytes.Buffer
+	tracer := NewTracer(&b)
+
+	fault := &soap.Fault{Code: "ServerFaultCode"}
+	fault.Detail.Fault = types.ManagedObjectNotFound{}
+	req := &methods.ReconfigVM_TaskBody{
+		Req: &types.ReconfigVM_Task{
+			This: types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"},
+		},
+	}
+	_ = tracer.RoundTripper(testRoundTripper{err: soap.WrapSoapFault(fault)}).RoundTrip(context.Background(), req, req)
+	_ = tracer.RoundTripper(testRoundTripper{err: errors.New("connection refused")}).RoundTrip(context.Background(), &methods.RetrieveServiceContentBody{}, &methods.RetrieveServiceContentBody{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+	restReq, err := http.NewRequest(http.MethodGet, server.URL+"/rest/com/vmware/cis/tagging/tag", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := tracer.Transport(http.DefaultTransport).RoundTrip(restReq)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	_ = res.Body.Close()
+
+	entries := testTrace(t, &b)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 trace entries, got %d", len(entries))
+	}
+	expected := []Entry{
+		{API: "soap", Method: "ReconfigVM_Task", Type: "VirtualMachine", MOID: "vm-42", Fault: "ManagedObjectNotFound"},
+		{API: "soap", Method: "RetrieveServiceContent", Error: "connection refused"},
+		{API: "rest", Method: "GET /rest/com/vmware/cis/tagging/tag", Status: http.StatusNotFound},
+	}
+	for i, e := range entries {
+		if e.Time.IsZero() {
+			t.Fatalf("expected entry %d to have a time", i)
+		}
+		e.Time = expected[i].Time
+		e.Duration = 0
+		if e != expected[i] {
+			t.Fatalf("expected entry %d to be %#v, got %#v", i, expected[i], e)
+		}
+	}
+
+	// A nil tracer does not wrap.
+	var nilTracer *Tracer
+	rt := testRoundTripper{}
+	if nilTracer.RoundTripper(rt) != soap.RoundTripper(rt) {
+		t.Fatalf("expected a nil tracer to return the round tripper as is")
+	}
+}
+
+func TestOpenTracer(t *testing.T) {
+	p := filepath.Join(t.TempDir(), TraceFile)
+	tracer, err := OpenTracer(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := tracer.RoundTripper(testRoundTripper{})
+	_ = rt.RoundTrip(context.Background(), &methods.RetrieveServiceContentBody{}, &methods.RetrieveServiceContentBody{})
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if err := tracer.Close(); err != nil {
+		t.Fatalf("expected closing twice to succeed, got %s", err)
+	}
+	if err := tracer.f.Close(); err == nil {
+		t.Fatal("expected the trace file to be closed")
+	}
+
+	// Calls traced after Close are dropped.
+	_ = rt.RoundTrip(context.Background(), &methods.RetrieveServiceContentBody{}, &methods.RetrieveServiceContentBody{})
+	b, err := os.ReadFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries := testTrace(t, bytes.NewBuffer(b)); len(entries) != 1 {
+		t.Fatalf("expected 1 trace entry, got %d", len(entries))
+	}
+
+	var nilTracer *Tracer
+	if err := nilTracer.Close(); err != nil {
+		t.Fatalf("expected closing a nil tracer to succeed, got %s", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -103,11 +104,12 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 
 // Properties is a convenience method that wraps fetching the
 // portgroup MO from its higher-level object.
-func Properties(pg *object.DistributedVirtualPortgroup) (*mo.DistributedVirtualPortgroup, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(pg *object.DistributedVirtualPortgroup, paths ...string) (*mo.DistributedVirtualPortgroup, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.DistributedVirtualPortgroup
-	if err := pg.Properties(ctx, pg.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, pg.Client(), pg.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -287,16 +287,17 @@ func NormalizePath(v interface{}) string {
 }
 
 // MoveObjectTo moves a object by reference into a folder.
-func MoveObjectTo(ref types.ManagedObjectReference, folder *object.Folder) error {
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+func MoveObjectTo(ctx context.Context, ref types.ManagedObjectReference, folder *object.Folder) error {
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := folder.MoveInto(ctx, []types.ManagedObjectReference{ref})
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return task.Wait(tctx)
+	_, err = viapi.WaitForTask(tctx, task)
+	return err
 }
 
 // FromPath takes a relative folder path, an object type, and an optional
@@ -372,11 +373,12 @@ func getFolders(client *govmomi.Client, path string) ([]*object.Folder, error) {
 
 // Properties is a convenience method that wraps fetching the
 // Folder MO from its higher-level object.
-func Properties(folder *object.Folder) (*mo.Folder, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(folder *object.Folder, paths ...string) (*mo.Folder, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.Folder
-	if err := folder.Properties(ctx, folder.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, folder.Client(), folder.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
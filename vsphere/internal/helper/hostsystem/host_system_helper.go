@@ -62,11 +62,14 @@ func FromID(client *govmomi.Client, id string) (*object.HostSystem, error) {
 
 // Properties is a convenience method that wraps fetching the HostSystem MO
 // from its higher-level object.
-func Properties(host *object.HostSystem) (*mo.HostSystem, error) {
+//
+// Only the property paths in paths are retrieved, or all properties if paths
+// is empty. See viapi.RetrieveProperties.
+func Properties(host *object.HostSystem, paths ...string) (*mo.HostSystem, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.HostSystem
-	if err := host.Properties(ctx, host.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, host.Client(), host.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -150,7 +153,7 @@ func NameOrID(client *govmomi.Client, id string) string {
 // HostInMaintenance checks a HostSystem's maintenance mode and returns true if the
 // the host is in maintenance mode.
 func HostInMaintenance(host *object.HostSystem) (bool, error) {
-	hostObject, err := Properties(host)
+	hostObject, err := Properties(host, "runtime.inMaintenanceMode")
 	if err != nil {
 		return false, err
 	}
@@ -162,7 +165,7 @@ func HostInMaintenance(host *object.HostSystem) (bool, error) {
 // to true, all powered off VMs will be removed from the host, or the task will
 // block until this is the case, depending on whether or not DRS is on or off
 // for the host's cluster. This parameter is ignored on direct ESXi.
-func EnterMaintenanceMode(host *object.HostSystem, timeout time.Duration, evacuate bool) error {
+func EnterMaintenanceMode(ctx context.Context, host *object.HostSystem, timeout time.Duration, evacuate bool) error {
 	if err := viapi.VimValidateVirtualCenter(host.Client()); err != nil {
 		evacuate = false
 	}
@@ -178,19 +181,19 @@ func EnterMaintenanceMode(host *object.HostSystem, timeout time.Duration, evacua
 
 	log.Printf("[DEBUG] Host %q is entering maintenance mode (evacuate: %t)", host.Name(), evacuate)
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	task, err := host.EnterMaintenanceMode(ctx, int32(timeout.Seconds()), evacuate, nil)
 	if err != nil {
 		return err
 	}
 
-	err = task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		return err
 	}
 	var to mo.Task
-	err = task.Properties(context.TODO(), task.Reference(), nil, &to)
+	err = task.Properties(ctx, task.Reference(), nil, &to)
 	if err != nil {
 		log.Printf("[DEBUG] Failed while getting task results: %s", err)
 		return err
@@ -203,7 +206,7 @@ func EnterMaintenanceMode(host *object.HostSystem, timeout time.Duration, evacua
 }
 
 // ExitMaintenanceMode takes a host out of maintenance mode.
-func ExitMaintenanceMode(host *object.HostSystem, timeout time.Duration) error {
+func ExitMaintenanceMode(ctx context.Context, host *object.HostSystem, timeout time.Duration) error {
 	maintMode, err := HostInMaintenance(host)
 	if err != nil {
 		return err
@@ -215,19 +218,19 @@ func ExitMaintenanceMode(host *object.HostSystem, timeout time.Duration) error {
 
 	log.Printf("[DEBUG] Host %q is exiting maintenance mode", host.Name())
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	task, err := host.ExitMaintenanceMode(ctx, int32(timeout.Seconds()))
 	if err != nil {
 		return err
 	}
 
-	err = task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		return err
 	}
 	var to mo.Task
-	err = task.Properties(context.TODO(), task.Reference(), nil, &to)
+	err = task.Properties(ctx, task.Reference(), nil, &to)
 	if err != nil {
 		log.Printf("[DEBUG] Failed while getting task results: %s", err)
 		return err
@@ -241,7 +244,7 @@ func ExitMaintenanceMode(host *object.HostSystem, timeout time.Duration) error {
 
 // GetConnectionState returns the host's connection state (see vim.HostSystem.ConnectionState)
 func GetConnectionState(host *object.HostSystem) (types.HostSystemConnectionState, error) {
-	hostProps, err := Properties(host)
+	hostProps, err := Properties(host, "runtime.connectionState")
 	if err != nil {
 		return "", err
 	}
@@ -0,0 +1,266 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package identitysource registers, updates and probes LDAP identity sources
+// in vCenter SSO. The requests of govmomi do not carry the certificates of the
+// LDAP servers, which vCenter needs to trust an ldaps:// URL, and encode the
+// URL of a probe incorrectly, so this package sends its own versions of them.
+package identitysource
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/vmware/govmomi/ssoadmin"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+	"github.com/vmware/govmomi/vim25/soap"
+	vim_types "github.com/vmware/govmomi/vim25/types"
+)
+
+const (
+	// ServerTypeActiveDirectory is the server type of an Active Directory
+	// server reached over LDAP.
+	ServerTypeActiveDirectory = "ActiveDirectory"
+
+	// ServerTypeOpenLdap is the server type of an OpenLDAP server.
+	ServerTypeOpenLdap = "OpenLdap"
+)
+
+// attributeTypePattern matches the attribute type of a relative distinguished
+// name, either a name such as ou or an OID such as 2.5.4.11.
+var attributeTypePattern = regexp.MustCompile(`^([A-Za-z][A-Za-z0-9-]*|[0-9]+(\.[0-9]+)*)$`)
+
+// ServerTypes are the supported server types of an LDAP identity source.
+var ServerTypes = []string{ServerTypeActiveDirectory, ServerTypeOpenLdap}
+
+// Details are the connection details of an LDAP identity source. Unlike
+// govmomi's LdapIdentitySourceDetails, they include the certificates of the
+// LDAP servers, as base64 encoded DER.
+type Details struct {
+	FriendlyName string   `xml:"friendlyName"`
+	UserBaseDn   string   `xml:"userBaseDn,omitempty"`
+	GroupBaseDn  string   `xml:"groupBaseDn,omitempty"`
+	PrimaryURL   string   `xml:"primaryUrl"`
+	FailoverURL  string   `xml:"failoverUrl,omitempty"`
+	Certificates []string `xml:"certificates,omitempty"`
+}
+
+type registerLdapRequest struct {
+	This               vim_types.ManagedObjectReference                                                 `xml:"_this"`
+	ServerType         string                                                                           `xml:"serverType"`
+	DomainName         string                                                                           `xml:"domainName"`
+	DomainAlias        string                                                                           `xml:"domainAlias,omitempty"`
+	Details            Details                                                                          `xml:"details"`
+	AuthenticationType string                                                                           `xml:"authenticationType"`
+	AuthnCredentials   *ssoadmin_types.SsoAdminIdentitySourceManagementServiceAuthenticationCredentails `xml:"authnCredentials,omitempty"`
+}
+
+type registerLdapBody struct {
+	Req    *registerLdapRequest                 `xml:"urn:sso RegisterLdap,omitempty"`
+	Res    *ssoadmin_types.RegisterLdapResponse `xml:"urn:sso RegisterLdapResponse,omitempty"`
+	Fault_ *soap.Fault                          `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *registerLdapBody) Fault() *soap.Fault { return b.Fault_ }
+
+type updateLdapRequest struct {
+	This       vim_types.ManagedObjectReference `xml:"_this"`
+	DomainName string                           `xml:"name"`
+	Details    Details                          `xml:"details"`
+}
+
+type updateLdapBody struct {
+	Req    *updateLdapRequest                 `xml:"urn:sso UpdateLdap,omitempty"`
+	Res    *ssoadmin_types.UpdateLdapResponse `xml:"urn:sso UpdateLdapResponse,omitempty"`
+	Fault_ *soap.Fault                        `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *updateLdapBody) Fault() *soap.Fault { return b.Fault_ }
+
+// probeConnectivityRequest differs from govmomi's ProbeConnectivity in the
+// type of ServiceUri, which govmomi encodes as a net/url.URL struct rather
+// than as a URI.
+type probeConnectivityRequest struct {
+	This               vim_types.ManagedObjectReference                                      `xml:"_this"`
+	ServiceURI         string                                                                `xml:"serviceUri"`
+	AuthenticationType string                                                                `xml:"authenticationType"`
+	AuthnCredentials   *ssoadmin_types.AdminDomainManagementServiceAuthenticationCredentails `xml:"authnCredentials,omitempty"`
+}
+
+type probeConnectivityBody struct {
+	Req    *probeConnectivityRequest                 `xml:"urn:sso ProbeConnectivity,omitempty"`
+	Res    *ssoadmin_types.ProbeConnectivityResponse `xml:"urn:sso ProbeConnectivityResponse,omitempty"`
+	Fault_ *soap.Fault                               `xml:"http://schemas.xmlsoap.org/soap/envelope/ Fault,omitempty"`
+}
+
+func (b *probeConnectivityBody) Fault() *soap.Fault { return b.Fault_ }
+
+// RegisterLdap registers an LDAP identity source for the domain name, which
+// authenticates to the LDAP servers with a username and password.
+func RegisterLdap(ctx context.Context, client *ssoadmin.Client, serverType, name, alias string, details Details, auth ssoadmin_types.SsoAdminIdentitySourceManagementServiceAuthenticationCredentails) error {
+	req := registerLdapBody{
+		Req: &registerLdapRequest{
+			This:               client.ServiceContent.IdentitySourceManagementService,
+			ServerType:         serverType,
+			DomainName:         name,
+			DomainAlias:        alias,
+			Details:            details,
+			AuthenticationType: "password",
+			AuthnCredentials:   &auth,
+		},
+	}
+	var res registerLdapBody
+	return client.RoundTrip(ctx, &req, &res)
+}
+
+// UpdateLdap updates the connection details of the LDAP identity source for
+// the domain name.
+func UpdateLdap(ctx context.Context, client *ssoadmin.Client, name string, details Details) error {
+	req := updateLdapBody{
+		Req: &updateLdapRequest{
+			This:       client.ServiceContent.IdentitySourceManagementService,
+			DomainName: name,
+			Details:    details,
+		},
+	}
+	var res updateLdapBody
+	return client.RoundTrip(ctx, &req, &res)
+}
+
+// Probe asks vCenter to connect to the LDAP server at url and to bind as
+// username. It returns an error if vCenter cannot reach the server or the
+// server rejects the credentials. The probe does not check the base DNs of
+// an identity source, which are only used when searching for principals.
+func Probe(ctx context.Context, client *ssoadmin.Client, url, username, password string) error {
+	req := probeConnectivityBody{
+		Req: &probeConnectivityRequest{
+			This:               client.ServiceContent.DomainManagementService,
+			ServiceURI:         url,
+			AuthenticationType: "password",
+			AuthnCredentials: &ssoadmin_types.AdminDomainManagementServiceAuthenticationCredentails{
+				Username: username,
+				Password: password,
+			},
+		},
+	}
+	var res probeConnectivityBody
+	return client.RoundTrip(ctx, &req, &res)
+}
+
+// ValidateDN is a schema.SchemaValidateFunc that checks the syntax of a
+// distinguished name, such as ou=people,dc=example,dc=com.
+func ValidateDN(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if err := parseDN(v); err != nil {
+		return nil, []error{fmt.Errorf("%s is not a valid distinguished name: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// EncodeCertificates converts PEM encoded certificates to the base64 encoded
+// DER that vCenter expects. Each entry can hold a chain of several
+// certificates.
+func EncodeCertificates(pems []string) ([]string, error) {
+	var result []string
+	for i, v := range pems {
+		certs, err := parseCertificates(v)
+		if err != nil {
+			return nil, fmt.Errorf("certificate %d: %s", i, err)
+		}
+		for _, cert := range certs {
+			result = append(result, base64.StdEncoding.EncodeToString(cert.Raw))
+		}
+	}
+	return result, nil
+}
+
+// ValidateCertificate is a schema.SchemaValidateFunc that checks that a value
+// holds one or more PEM encoded certificates.
+func ValidateCertificate(i interface{}, k string) ([]string, []error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, []error{fmt.Errorf("expected type of %s to be string", k)}
+	}
+	if _, err := parseCertificates(v); err != nil {
+		return nil, []error{fmt.Errorf("%s: %s", k, err)}
+	}
+	return nil, nil
+}
+
+// IsLdaps returns true if url uses LDAP over TLS.
+func IsLdaps(url string) bool {
+	return strings.HasPrefix(strings.ToLower(url), "ldaps://")
+}
+
+// parseCertificates parses the PEM encoded certificates in v.
+func parseCertificates(v string) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := []byte(v)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			return nil, fmt.Errorf("unexpected PEM block of type %s", block.Type)
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing certificate: %s", err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no PEM encoded certificate found")
+	}
+	return certs, nil
+}
+
+// parseDN checks that v is a sequence of relative distinguished names
+// separated by commas, each one or more type=value pairs separated by plus
+// signs. A comma or plus sign in a value must be escaped with a backslash.
+func parseDN(v string) error {
+	if strings.TrimSpace(v) == "" {
+		return fmt.Errorf("empty distinguished name")
+	}
+	for _, rdn := range splitUnescaped(v, ',') {
+		for _, pair := range splitUnescaped(rdn, '+') {
+			attr, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("%q is not in the form type=value", strings.TrimSpace(pair))
+			}
+			if !attributeTypePattern.MatchString(strings.TrimSpace(attr)) {
+				return fmt.Errorf("%q is not a valid attribute type", strings.TrimSpace(attr))
+			}
+			if strings.TrimSpace(value) == "" {
+				return fmt.Errorf("%q has an empty value", strings.TrimSpace(pair))
+			}
+		}
+	}
+	return nil
+}
+
+// splitUnescaped splits v at each sep that is not escaped with a backslash.
+func splitUnescaped(v string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(v); i++ {
+		switch v[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, v[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, v[start:])
+}
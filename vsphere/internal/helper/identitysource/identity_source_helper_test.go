@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package identitysource
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/xml"
+)
+
+func testCertificate(t *testing.T, name string) ([]byte, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return der, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}))
+}
+
+func TestEncodeCertificates(t *testing.T) {
+	der1, pem1 := testCertificate(t, "dc01.example.com")
+	der2, pem2 := testCertificate(t, "dc02.example.com")
+
+	got, err := EncodeCertificates([]string{pem1, pem1 + pem2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		base64.StdEncoding.EncodeToString(der1),
+		base64.StdEncoding.EncodeToString(der1),
+		base64.StdEncoding.EncodeToString(der2),
+	}
+	if strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+
+	if _, err := EncodeCertificates([]string{"not a certificate"}); err == nil {
+		t.Fatal("expected an error for a value without a certificate")
+	}
+}
+
+func TestValidateCertificate(t *testing.T) {
+	_, cert := testCertificate(t, "dc01.example.com")
+	key := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte("key")}))
+	invalid := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}))
+
+	cases := []struct {
+		name  string
+		value interface{}
+		valid bool
+	}{
+		{"certificate", cert, true},
+		{"empty", "", false},
+		{"private key", key, false},
+		{"invalid certificate", invalid, false},
+		{"not a string", 1, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, errs := ValidateCertificate(tc.value, "certificates.0")
+			if tc.valid != (len(errs) == 0) {
+				t.Fatalf("expected valid to be %t, got errors %v", tc.valid, errs)
+			}
+		})
+	}
+}
+
+func TestRegisterLdapBody(t *testing.T) {
+	body := registerLdapBody{
+		Req: &registerLdapRequest{
+			ServerType: ServerTypeOpenLdap,
+			DomainName: "example.com",
+			Details: Details{
+				FriendlyName: "example",
+				PrimaryURL:   "ldaps://ldap01.example.com:636",
+				Certificates: []string{"MIIB", "MIIC"},
+			},
+		},
+	}
+	b, err := xml.Marshal(&body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "<primaryUrl>ldaps://ldap01.example.com:636</primaryUrl><certificates>MIIB</certificates><certificates>MIIC</certificates></details>"
+	if !strings.Contains(string(b), expected) {
+		t.Fatalf("expected %s to contain %s", b, expected)
+	}
+}
+
+func TestProbeConnectivityBody(t *testing.T) {
+	body := probeConnectivityBody{
+		Req: &probeConnectivityRequest{
+			ServiceURI:         "ldap://ldap01.example.com:389",
+			AuthenticationType: "password",
+		},
+	}
+	b, err := xml.Marshal(&body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "<serviceUri>ldap://ldap01.example.com:389</serviceUri>"
+	if !strings.Contains(string(b), expected) {
+		t.Fatalf("expected %s to contain %s", b, expected)
+	}
+}
+
+func TestValidateDN(t *testing.T) {
+	cases := []struct {
+		value string
+		valid bool
+	}{
+		{"dc=example,dc=com", true},
+		{"OU=Service Accounts, DC=corp, DC=example, DC=com", true},
+		{"cn=Smith\\, John+uid=jsmith,ou=people,dc=example,dc=com", true},
+		{"2.5.4.11=people,dc=example,dc=com", true},
+		{"", false},
+		{"example.com", false},
+		{"dc=example,,dc=com", false},
+		{"dc=,dc=com", false},
+		{"d c=example,dc=com", false},
+		{"=example,dc=com", false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.value, func(t *testing.T) {
+			_, errs := ValidateDN(tc.value, "user_base_dn")
+			if tc.valid != (len(errs) == 0) {
+				t.Fatalf("expected valid to be %t, got errors %v", tc.valid, errs)
+			}
+		})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -173,8 +174,9 @@ func RemoveInternetScsiSendTargets(
 	return nil
 }
 
-// ExtractChapCredsFromTarget is helper function takes given target map and returns the chap username and password creds
-func ExtractChapCredsFromTarget(target map[string]interface{}, outgoingCreds bool) map[string]interface{} {
+// ExtractChapCredsFromTarget is helper function takes given target map and returns the chap username and password creds.
+// The password is read with secret.Read, so it can refer to a file.
+func ExtractChapCredsFromTarget(target map[string]interface{}, outgoingCreds bool) (map[string]interface{}, error) {
 	chapList := target["chap"].([]interface{})
 	chapCreds := map[string]interface{}{
 		"username": "",
@@ -193,7 +195,64 @@ func ExtractChapCredsFromTarget(target map[string]interface{}, outgoingCreds boo
 		}
 	}
 
-	return chapCreds
+	password, err := secret.Read(chapCreds["password"].(string))
+	if err != nil {
+		return nil, err
+	}
+	chapCreds["password"] = password
+
+	return chapCreds, nil
+}
+
+// FingerprintChap returns a copy of the chap block of a target with the
+// passwords replaced by their fingerprints, which is what is saved in state.
+func FingerprintChap(v interface{}) []interface{} {
+	chapList, _ := v.([]interface{})
+	result := make([]interface{}, 0, len(chapList))
+	for _, c := range chapList {
+		chap, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		m := make(map[string]interface{})
+		for _, k := range []string{"outgoing_creds", "incoming_creds"} {
+			credsList, _ := chap[k].([]interface{})
+			fingerprinted := make([]interface{}, 0, len(credsList))
+			for _, cc := range credsList {
+				creds, ok := cc.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				password, _ := creds["password"].(string)
+				fingerprinted = append(fingerprinted, map[string]interface{}{
+					"username": creds["username"],
+					"password": secret.StateValue(password),
+				})
+			}
+			m[k] = fingerprinted
+		}
+		result = append(result, m)
+	}
+	return result
+}
+
+// TargetHashFunc returns the set function of send_target or static_target,
+// where elem is the schema of a target. It hashes the target with its chap
+// passwords fingerprinted, so that a target in the configuration has the same
+// hash as the target in state.
+func TargetHashFunc(elem *schema.Resource) schema.SchemaSetFunc {
+	hash := schema.HashResource(elem)
+	return func(v interface{}) int {
+		target := v.(map[string]interface{})
+		m := make(map[string]interface{}, len(target))
+		for k, vv := range target {
+			m[k] = vv
+		}
+		if _, ok := target[ChapResourceKey]; ok {
+			m[ChapResourceKey] = FingerprintChap(target[ChapResourceKey])
+		}
+		return hash(m)
+	}
 }
 
 /////////////////////////
@@ -222,10 +281,12 @@ func ChapSchema() *schema.Schema {
 								Description: "Username to auth against iscsi device",
 							},
 							"password": {
-								Type:        schema.TypeString,
-								Required:    true,
-								Description: "Password to auth against iscsi device",
-								Sensitive:   true,
+								Type:             schema.TypeString,
+								Required:         true,
+								Description:      "Password to auth against iscsi device. Can be a file:// reference to a file with the password. Only a fingerprint of the password is saved in state.",
+								Sensitive:        true,
+								StateFunc:        secret.StateFunc,
+								DiffSuppressFunc: secret.DiffSuppressFunc,
 							},
 						},
 					},
@@ -243,10 +304,12 @@ func ChapSchema() *schema.Schema {
 								Description: "Username to auth against host",
 							},
 							"password": {
-								Type:        schema.TypeString,
-								Required:    true,
-								Description: "Password to auth against host",
-								Sensitive:   true,
+								Type:             schema.TypeString,
+								Required:         true,
+								Description:      "Password to auth against host. Can be a file:// reference to a file with the password. Only a fingerprint of the password is saved in state.",
+								Sensitive:        true,
+								StateFunc:        secret.StateFunc,
+								DiffSuppressFunc: secret.DiffSuppressFunc,
 							},
 						},
 					},
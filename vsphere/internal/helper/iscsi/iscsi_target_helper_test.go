@@ -0,0 +1,66 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package iscsi
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+)
+
+func testTarget(password string) map[string]interface{} {
+	return map[string]interface{}{
+		IPResourceKey:   "172.16.0.1",
+		PortResourceKey: 3260,
+		ChapResourceKey: []interface{}{
+			map[string]interface{}{
+				"outgoing_creds": []interface{}{
+					map[string]interface{}{
+						"username": "user",
+						"password": password,
+					},
+				},
+				"incoming_creds": []interface{}{},
+			},
+		},
+	}
+}
+
+func TestTargetHashFunc(t *testing.T) {
+	elem := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			IPResourceKey:   IPSchema(),
+			PortResourceKey: PortSchema(),
+			ChapResourceKey: ChapSchema(),
+		},
+	}
+	hash := TargetHashFunc(elem)
+
+	plain := hash(testTarget("secret"))
+	if fingerprinted := hash(testTarget(secret.Fingerprint("secret"))); plain != fingerprinted {
+		t.Fatalf("expected a target with a fingerprinted password to hash to %d, got %d", plain, fingerprinted)
+	}
+	if other := hash(testTarget("other")); plain == other {
+		t.Fatalf("expected targets with different passwords to hash differently")
+	}
+}
+
+func TestExtractChapCredsFromTarget(t *testing.T) {
+	creds, err := ExtractChapCredsFromTarget(testTarget("secret"), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds["username"] != "user" || creds["password"] != "secret" {
+		t.Fatalf("unexpected outgoing creds: %v", creds)
+	}
+
+	creds, err = ExtractChapCredsFromTarget(testTarget("secret"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if creds["username"] != "" || creds["password"] != "" {
+		t.Fatalf("unexpected incoming creds: %v", creds)
+	}
+}
@@ -6,7 +6,6 @@ package ovfdeploy
 import (
 	"archive/tar"
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
@@ -20,7 +19,10 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/datastore"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/transport"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/network"
 	"github.com/vmware/govmomi"
@@ -49,23 +51,29 @@ func (pr *ProgressReader) Read(p []byte) (n int, err error) {
 	return
 }
 
-func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *types.OvfCreateImportSpecResult, resourcePoolObj *object.ResourcePool,
-	folder *object.Folder, host *object.HostSystem, filePath string, deployOva bool, fromLocal bool, allowUnverifiedSSL bool) error {
+func DeployOvfAndGetResult(ctx context.Context, client *govmomi.Client, ovfCreateImportSpecResult *types.OvfCreateImportSpecResult, resourcePoolObj *object.ResourcePool,
+	folder *object.Folder, host *object.HostSystem, filePath string, deployOva bool, fromLocal bool, allowUnverifiedSSL bool, settings *transport.Settings) error {
 
 	var currBytesRead int64
 	var totalBytes int64
 
-	nfcLease, err := resourcePoolObj.ImportVApp(context.Background(), ovfCreateImportSpecResult.ImportSpec, folder, host)
+	release, err := viapi.AcquireTask(ctx, client.Client, viapi.TaskClassOvfDeploy, fmt.Sprintf("deployment of %q", filePath))
 	if err != nil {
 		return err
 	}
+	defer release()
 
-	leaseInfo, err := nfcLease.Wait(context.Background(), ovfCreateImportSpecResult.FileItem)
+	nfcLease, err := resourcePoolObj.ImportVApp(ctx, ovfCreateImportSpecResult.ImportSpec, folder, host)
 	if err != nil {
 		return err
 	}
 
-	u := nfcLease.StartUpdater(context.Background(), leaseInfo)
+	leaseInfo, err := nfcLease.Wait(ctx, ovfCreateImportSpecResult.FileItem)
+	if err != nil {
+		return err
+	}
+
+	u := nfcLease.StartUpdater(ctx, leaseInfo)
 	defer u.Done()
 
 	for _, ovfFileItem := range ovfCreateImportSpecResult.FileItem {
@@ -82,12 +90,12 @@ func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *ty
 				break
 			default:
 				if totalBytes == 0 {
-					_ = nfcLease.Progress(context.Background(), 100)
+					_ = nfcLease.Progress(ctx, 100)
 					return
 				}
 				log.Printf("Uploaded %v of %v Bytes", getTotalBytesRead(&currBytesRead), totalBytes)
 				progress := (getTotalBytesRead(&currBytesRead) / totalBytes) * 100
-				_ = nfcLease.Progress(context.Background(), int32(progress))
+				_ = nfcLease.Progress(ctx, int32(progress))
 				time.Sleep(10 * time.Second)
 			}
 		}
@@ -100,29 +108,34 @@ func DeployOvfAndGetResult(client *govmomi.Client, ovfCreateImportSpecResult *ty
 			}
 			if !deployOva {
 				if fromLocal {
-					err = uploadDisksFromLocal(client, filePath, ovfFileItem, deviceObj, &currBytesRead)
+					err = uploadDisksFromLocal(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead)
 				} else {
-					err = uploadDisksFromURL(client, filePath, ovfFileItem, deviceObj, &currBytesRead, allowUnverifiedSSL)
+					err = uploadDisksFromURL(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead, allowUnverifiedSSL, settings)
 				}
 			} else {
 				if fromLocal {
-					err = uploadOvaDisksFromLocal(client, filePath, ovfFileItem, deviceObj, &currBytesRead)
+					err = uploadOvaDisksFromLocal(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead)
 				} else {
-					err = uploadOvaDisksFromURL(client, filePath, ovfFileItem, deviceObj, &currBytesRead, allowUnverifiedSSL)
+					err = uploadOvaDisksFromURL(ctx, client, filePath, ovfFileItem, deviceObj, &currBytesRead, allowUnverifiedSSL, settings)
 				}
 			}
 			if err != nil {
+				// Abort the lease, so that the import does not linger in vCenter when
+				// the upload failed or was cancelled.
+				actx, acancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+				defer acancel()
+				_ = nfcLease.Abort(actx, nil)
 				return fmt.Errorf("error while uploading the disk %s %s", ovfFileItem.Path, err)
 			}
 			log.Print(" DEBUG : Completed uploading the vmdk file", ovfFileItem.Path)
 		}
 	}
 	statusChannel <- true
-	err = nfcLease.Progress(context.Background(), 100)
+	err = nfcLease.Progress(ctx, 100)
 	if err != nil {
 		return err
 	}
-	return nfcLease.Complete(context.Background())
+	return nfcLease.Complete(ctx)
 }
 
 func upload(ctx context.Context, client *govmomi.Client, item types.OvfFileItem, f io.Reader, rawUrl string, size int64, totalBytesRead *int64) error {
@@ -186,7 +199,7 @@ func upload(ctx context.Context, client *govmomi.Client, item types.OvfFileItem,
 	return err
 }
 
-func uploadDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
+func uploadDisksFromLocal(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
 	absoluteFilePath := ""
 	if strings.Contains(filePath, string(os.PathSeparator)) {
 		absoluteFilePath = string(filePath[0 : strings.LastIndex(filePath, string(os.PathSeparator))+1])
@@ -197,7 +210,7 @@ func uploadDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem t
 	if err != nil {
 		return err
 	}
-	err = upload(context.Background(), client, ovfFileItem, file, deviceObj.Url, ovfFileItem.Size, currBytesRead)
+	err = upload(ctx, client, ovfFileItem, file, deviceObj.Url, ovfFileItem.Size, currBytesRead)
 	if err != nil {
 		return fmt.Errorf("error while uploading the file %s %s", vmdkFilePath, err)
 	}
@@ -208,14 +221,17 @@ func uploadDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem t
 	return nil
 }
 
-func uploadDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
-	allowUnverifiedSSL bool) error {
+func uploadDisksFromURL(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
+	allowUnverifiedSSL bool, settings *transport.Settings) error {
 	absoluteFilePath := ""
 	if strings.Contains(filePath, "/") {
 		absoluteFilePath = string(filePath[0 : strings.LastIndex(filePath, "/")+1])
 	}
 	vmdkFilePath := absoluteFilePath + ovfFileItem.Path
-	httpClient := getClient(allowUnverifiedSSL)
+	httpClient, err := settings.HTTPClient(allowUnverifiedSSL)
+	if err != nil {
+		return err
+	}
 	resp, err := httpClient.Get(vmdkFilePath)
 	log.Print(" [DEBUG] Absolute vmdk path: " + vmdkFilePath)
 	if err != nil {
@@ -224,11 +240,11 @@ func uploadDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem typ
 	defer func(Body io.ReadCloser) {
 		_ = Body.Close()
 	}(resp.Body)
-	err = upload(context.Background(), client, ovfFileItem, resp.Body, deviceObj.Url, ovfFileItem.Size, currBytesRead)
+	err = upload(ctx, client, ovfFileItem, resp.Body, deviceObj.Url, ovfFileItem.Size, currBytesRead)
 	return err
 }
 
-func uploadOvaDisksFromLocal(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
+func uploadOvaDisksFromLocal(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
 	diskName := ovfFileItem.Path
 	ovaFile, err := os.Open(filePath)
 	if err != nil {
@@ -238,14 +254,17 @@ func uploadOvaDisksFromLocal(client *govmomi.Client, filePath string, ovfFileIte
 		_ = ovaFile.Close()
 	}(ovaFile)
 
-	err = findAndUploadDiskFromOva(client, ovaFile, diskName, ovfFileItem, deviceObj, currBytesRead)
+	err = findAndUploadDiskFromOva(ctx, client, ovaFile, diskName, ovfFileItem, deviceObj, currBytesRead)
 	return err
 }
 
-func uploadOvaDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
-	allowUnverifiedSSL bool) error {
+func uploadOvaDisksFromURL(ctx context.Context, client *govmomi.Client, filePath string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64,
+	allowUnverifiedSSL bool, settings *transport.Settings) error {
 	diskName := ovfFileItem.Path
-	httpClient := getClient(allowUnverifiedSSL)
+	httpClient, err := settings.HTTPClient(allowUnverifiedSSL)
+	if err != nil {
+		return err
+	}
 	resp, err := httpClient.Get(filePath)
 	if err != nil {
 		return err
@@ -254,7 +273,7 @@ func uploadOvaDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem
 		_ = Body.Close()
 	}(resp.Body)
 	if resp.StatusCode == http.StatusOK {
-		err = findAndUploadDiskFromOva(client, resp.Body, diskName, ovfFileItem, deviceObj, currBytesRead)
+		err = findAndUploadDiskFromOva(ctx, client, resp.Body, diskName, ovfFileItem, deviceObj, currBytesRead)
 		if err != nil {
 			return err
 		}
@@ -264,7 +283,7 @@ func uploadOvaDisksFromURL(client *govmomi.Client, filePath string, ovfFileItem
 	return nil
 }
 
-func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnverifiedSSL bool) (string, error) {
+func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnverifiedSSL bool, settings *transport.Settings) (string, error) {
 	ovfDescriptor := ""
 	if !deployOva {
 		if fromLocal {
@@ -274,7 +293,10 @@ func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnve
 			}
 			ovfDescriptor = string(fileBuffer)
 		} else {
-			client := getClient(allowUnverifiedSSL)
+			client, err := settings.HTTPClient(allowUnverifiedSSL)
+			if err != nil {
+				return "", err
+			}
 			resp, err := client.Get(filePath)
 			if err != nil {
 				return "", err
@@ -305,7 +327,10 @@ func GetOvfDescriptor(filePath string, deployOva bool, fromLocal bool, allowUnve
 				return "", err
 			}
 		} else {
-			client := getClient(allowUnverifiedSSL)
+			client, err := settings.HTTPClient(allowUnverifiedSSL)
+			if err != nil {
+				return "", err
+			}
 			resp, err := client.Get(filePath)
 			if err != nil {
 				return "", err
@@ -346,7 +371,7 @@ func getOvfDescriptorFromOva(ovaFile io.Reader) (string, error) {
 	return "", fmt.Errorf("ovf file not found inside the ova")
 }
 
-func findAndUploadDiskFromOva(client *govmomi.Client, ovaFile io.Reader, diskName string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
+func findAndUploadDiskFromOva(ctx context.Context, client *govmomi.Client, ovaFile io.Reader, diskName string, ovfFileItem types.OvfFileItem, deviceObj types.HttpNfcLeaseDeviceUrl, currBytesRead *int64) error {
 	ovaReader := tar.NewReader(ovaFile)
 	for {
 		fileHdr, err := ovaReader.Next()
@@ -357,7 +382,7 @@ func findAndUploadDiskFromOva(client *govmomi.Client, ovaFile io.Reader, diskNam
 			return err
 		}
 		if fileHdr.Name == diskName {
-			err = upload(context.Background(), client, ovfFileItem, ovaReader, deviceObj.Url, ovfFileItem.Size, currBytesRead)
+			err = upload(ctx, client, ovfFileItem, ovaReader, deviceObj.Url, ovfFileItem.Size, currBytesRead)
 			if err != nil {
 				return fmt.Errorf("error while uploading the file %s %s", diskName, err)
 			}
@@ -380,16 +405,6 @@ func GetNetworkMapping(client *govmomi.Client, m map[string]interface{}) ([]type
 	return ovfNetworkMappings, nil
 }
 
-func getClient(allowUnverifiedSSL bool) *http.Client {
-	if allowUnverifiedSSL {
-		tr := &http.Transport{
-			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-		}
-		return &http.Client{Transport: tr}
-	}
-	return &http.Client{}
-}
-
 func CheckDeploymentOption(client *govmomi.Client, deploymentOption, ovfDescriptor string) error {
 	ovfManager := ovf.NewManager(client.Client)
 
@@ -424,6 +439,7 @@ type OvfHelper struct {
 	IPProtocol         string
 	NetworkMapping     []types.OvfNetworkMapping
 	ResourcePool       *object.ResourcePool
+	Transport          *transport.Settings
 }
 
 type OvfHelperParams struct {
@@ -440,6 +456,7 @@ type OvfHelperParams struct {
 	NetworkMappings    map[string]interface{}
 	OvfURL             string
 	PoolID             string
+	Transport          *transport.Settings
 }
 
 func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error) {
@@ -450,6 +467,7 @@ func NewOvfHelper(client *govmomi.Client, o *OvfHelperParams) (*OvfHelper, error
 		IPAllocationPolicy: o.IPAllocationPolicy,
 		IPProtocol:         o.IPProtocol,
 		Name:               o.Name,
+		Transport:          o.Transport,
 	}
 
 	ovfParams.DeployOva = false
@@ -524,7 +542,7 @@ func (o *OvfHelper) GetImportSpec(client *govmomi.Client) (*types.OvfCreateImpor
 		DiskProvisioning:   o.DiskProvisioning,
 	}
 
-	ovfDescriptor, err := GetOvfDescriptor(o.FilePath, o.DeployOva, o.IsLocal, o.AllowUnverifiedSSL)
+	ovfDescriptor, err := GetOvfDescriptor(o.FilePath, o.DeployOva, o.IsLocal, o.AllowUnverifiedSSL, o.Transport)
 	if err != nil {
 		return nil, fmt.Errorf("error while reading the ovf file %s, %s ", o.FilePath, err)
 	}
@@ -558,7 +576,7 @@ func (o *OvfHelper) GetImportSpec(client *govmomi.Client) (*types.OvfCreateImpor
 	return is, nil
 }
 
-func (o *OvfHelper) DeployOvf(client *govmomi.Client, spec *types.OvfCreateImportSpecResult) error {
-	return DeployOvfAndGetResult(client, spec, o.ResourcePool, o.Folder, o.HostSystem,
-		o.FilePath, o.DeployOva, o.IsLocal, o.AllowUnverifiedSSL)
+func (o *OvfHelper) DeployOvf(ctx context.Context, client *govmomi.Client, spec *types.OvfCreateImportSpecResult) error {
+	return DeployOvfAndGetResult(ctx, client, spec, o.ResourcePool, o.Folder, o.HostSystem,
+		o.FilePath, o.DeployOva, o.IsLocal, o.AllowUnverifiedSSL, o.Transport)
 }
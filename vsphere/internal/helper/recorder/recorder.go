@@ -0,0 +1,402 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package recorder records vSphere SOAP and REST traffic to a fixture file,
+// and replays it later without a live endpoint.
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/redact"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/session"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// Mode is the operating mode of a Recorder.
+type Mode int
+
+const (
+	// ModeRecord passes requests through to the wrapped transport and records
+	// each request/response pair.
+	ModeRecord Mode = iota
+
+	// ModeReplay serves responses from the fixture without touching the
+	// network.
+	ModeReplay
+)
+
+// replayHost is the host that replayed requests are addressed to. It is never
+// dialed.
+const replayHost = "recorder.invalid"
+
+// Interaction is a single recorded request/response pair.
+type Interaction struct {
+	// The HTTP method of the request.
+	Method string `json:"method"`
+
+	// The request path, including the query string.
+	Path string `json:"path"`
+
+	// The SOAP operation, if this is a SOAP request. Informational only.
+	Operation string `json:"operation,omitempty"`
+
+	// The redacted request body.
+	RequestBody string `json:"request_body,omitempty"`
+
+	// The response status code.
+	StatusCode int `json:"status_code"`
+
+	// The response content type.
+	ContentType string `json:"content_type,omitempty"`
+
+	// The response body.
+	ResponseBody string `json:"response_body"`
+}
+
+// soapHeaderRe matches the header of a SOAP envelope.
+var soapHeaderRe = regexp.MustCompile(`(?s)<(?:\w+:)?Header[\s>].*?</(?:\w+:)?Header>`)
+
+// key returns the lookup key for the interaction. SOAP headers only carry
+// session state, which differs between a recording and its replay, so they
+// are not part of the key.
+func (i *Interaction) key() string {
+	return i.Method + " " + i.Path + "\n" + soapHeaderRe.ReplaceAllString(i.RequestBody, "")
+}
+
+// Fixture is the on-disk format of a recording.
+type Fixture struct {
+	// Free-form values saved by the test alongside the recording, such as the
+	// managed object IDs that the recording refers to.
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	// The recorded interactions, in the order they happened.
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder records or replays HTTP interactions. A single Recorder can back
+// several clients, for example a SOAP client and the REST client derived
+// from it.
+type Recorder struct {
+	mu      sync.Mutex
+	mode    Mode
+	path    string
+	fixture Fixture
+
+	// Per-key queues of interactions still to be replayed, and the last
+	// interaction replayed for each key.
+	pending map[string][]*Interaction
+	last    map[string]*Interaction
+
+	// The local endpoints serving the clients of the recorder.
+	servers []*httptest.Server
+}
+
+// New returns a Recorder for the fixture at path. In replay mode, the fixture
+// must exist.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{
+		mode: mode,
+		path: path,
+		fixture: Fixture{
+			Metadata: make(map[string]string),
+		},
+		pending: make(map[string][]*Interaction),
+		last:    make(map[string]*Interaction),
+	}
+	if mode == ModeRecord {
+		return r, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading fixture: %s", err)
+	}
+	if err := json.Unmarshal(b, &r.fixture); err != nil {
+		return nil, fmt.Errorf("error decoding fixture %q: %s", path, err)
+	}
+	for _, i := range r.fixture.Interactions {
+		k := i.key()
+		r.pending[k] = append(r.pending[k], i)
+	}
+	return r, nil
+}
+
+// Mode returns the mode the recorder was created with.
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Set saves a metadata value in the fixture.
+func (r *Recorder) Set(key, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Metadata[key] = value
+}
+
+// Get returns a metadata value from the fixture.
+func (r *Recorder) Get(key string) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.fixture.Metadata[key]
+}
+
+// Save writes the recorded interactions to the fixture path. It is a no-op in
+// replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != ModeRecord {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	b, err := json.MarshalIndent(&r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	log.Printf("[DEBUG] Saving %d recorded interactions to %q", len(r.fixture.Interactions), r.path)
+	return os.WriteFile(r.path, append(b, '\n'), 0644)
+}
+
+// Transport returns an http.RoundTripper that records through next, or
+// replays from the fixture, depending on the mode of the recorder. next is
+// ignored in replay mode.
+func (r *Recorder) Transport(next http.RoundTripper) http.RoundTripper {
+	return &transport{
+		recorder: r,
+		next:     next,
+	}
+}
+
+// NewReplayClient returns a govmomi client that is served entirely from the
+// fixture. The recorder must be in replay mode, and the fixture must contain
+// the RetrieveServiceContent call made when the recording client was created.
+func (r *Recorder) NewReplayClient(ctx context.Context) (*govmomi.Client, error) {
+	if r.mode != ModeReplay {
+		return nil, fmt.Errorf("recorder for %q is not in replay mode", r.path)
+	}
+	sc := soap.NewClient(r.listen(nil, nil), true)
+	vc, err := vim25.NewClient(ctx, sc)
+	if err != nil {
+		return nil, fmt.Errorf("error replaying service content: %s", err)
+	}
+	return &govmomi.Client{
+		Client:         vc,
+		SessionManager: session.NewManager(vc),
+	}, nil
+}
+
+// NewRecordingClient connects to the endpoint in u through the recorder and
+// logs in with the credentials in u. The recorder must be in record mode.
+func (r *Recorder) NewRecordingClient(ctx context.Context, u *url.URL, insecure bool) (*govmomi.Client, error) {
+	if r.mode != ModeRecord {
+		return nil, fmt.Errorf("recorder for %q is not in record mode", r.path)
+	}
+	next := http.DefaultTransport.(*http.Transport).Clone()
+	next.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecure}
+	pu := r.listen(u, next)
+	pu.Path = u.Path
+	sc := soap.NewClient(pu, true)
+	vc, err := vim25.NewClient(ctx, sc)
+	if err != nil {
+		return nil, err
+	}
+	c := &govmomi.Client{
+		Client:         vc,
+		SessionManager: session.NewManager(vc),
+	}
+	if u.User != nil {
+		if err := c.Login(ctx, u.User); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// Close shuts down the endpoints started for the clients of the recorder.
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range r.servers {
+		s.Close()
+	}
+	r.servers = nil
+}
+
+// listen starts a local TLS endpoint that forwards to target through the
+// recorder, and returns its SDK URL. target is nil in replay mode.
+//
+// Clients are pointed at this endpoint rather than having their transport
+// wrapped, as the service clients that govmomi derives from a SOAP client,
+// such as the REST, SPBM and vSAN clients, create their own transports. Going
+// through an endpoint captures all of them.
+func (r *Recorder) listen(target *url.URL, next http.RoundTripper) *url.URL {
+	rt := r.Transport(next)
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		out := req.Clone(req.Context())
+		out.RequestURI = ""
+		out.URL.Scheme = "https"
+		out.URL.Host = replayHost
+		if target != nil {
+			out.URL.Scheme = target.Scheme
+			out.URL.Host = target.Host
+		}
+		out.Host = out.URL.Host
+		// Let the outbound transport negotiate compression so that recorded
+		// bodies are stored in plain text.
+		out.Header.Del("Accept-Encoding")
+
+		res, err := rt.RoundTrip(out)
+		if err != nil {
+			log.Printf("[ERROR] %s", err)
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer res.Body.Close()
+		for k, v := range res.Header {
+			w.Header()[k] = v
+		}
+		w.WriteHeader(res.StatusCode)
+		_, _ = io.Copy(w, res.Body)
+	}))
+
+	r.mu.Lock()
+	r.servers = append(r.servers, s)
+	r.mu.Unlock()
+
+	u, _ := url.Parse(s.URL + "/sdk")
+	return u
+}
+
+func (r *Recorder) record(i *Interaction) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fixture.Interactions = append(r.fixture.Interactions, i)
+}
+
+// match returns the next recorded interaction for the request. Once all the
+// interactions for a request have been replayed, the last one is served
+// again, which accommodates polling calls.
+func (r *Recorder) match(i *Interaction) (*Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	k := i.key()
+	if q := r.pending[k]; len(q) > 0 {
+		r.pending[k] = q[1:]
+		r.last[k] = q[0]
+		return q[0], nil
+	}
+	if l, ok := r.last[k]; ok {
+		return l, nil
+	}
+	op := i.Operation
+	if op == "" {
+		op = "request"
+	}
+	return nil, fmt.Errorf("no recorded interaction in %q for %s %s (%s)", r.path, i.Method, i.Path, op)
+}
+
+// transport is the http.RoundTripper returned by Recorder.Transport.
+type transport struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper for transport.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	i := &Interaction{
+		Method:      req.Method,
+		Path:        req.URL.RequestURI(),
+		Operation:   soapOperation(body),
+		RequestBody: redact.Body(string(body)),
+	}
+
+	if t.recorder.mode == ModeReplay {
+		m, err := t.recorder.match(i)
+		if err != nil {
+			return nil, err
+		}
+		return m.response(req), nil
+	}
+
+	next := t.next
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	res, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+	i.StatusCode = res.StatusCode
+	i.ContentType = res.Header.Get("Content-Type")
+	i.ResponseBody = redact.Body(string(resBody))
+	t.recorder.record(i)
+	return res, nil
+}
+
+// response builds an http.Response for a recorded interaction.
+func (i *Interaction) response(req *http.Request) *http.Response {
+	h := make(http.Header)
+	if i.ContentType != "" {
+		h.Set("Content-Type", i.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.StatusCode, http.StatusText(i.StatusCode)),
+		StatusCode:    i.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(strings.NewReader(i.ResponseBody)),
+		ContentLength: int64(len(i.ResponseBody)),
+		Request:       req,
+	}
+}
+
+// soapOperationRe matches the first element inside a SOAP body.
+var soapOperationRe = regexp.MustCompile(`<(?:soapenv:)?Body[^>]*>\s*<(?:\w+:)?(\w+)`)
+
+// soapOperation returns the name of the SOAP operation in body, or an empty
+// string if body is not a SOAP envelope.
+func soapOperation(body []byte) string {
+	m := soapOperationRe.FindSubmatch(body)
+	if m == nil {
+		return ""
+	}
+	return string(m[1])
+}
@@ -0,0 +1,98 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package recorder
+
+import (
+	"context"
+	"crypto/tls"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestRecordReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "fixture.json")
+
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatalf("error creating simulator model: %s", err)
+	}
+	model.Service.TLS = new(tls.Config)
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	// readVM runs the same calls in both modes, so that the replay is served
+	// entirely from the recording.
+	readVM := func(r *Recorder, moid string) (mo.VirtualMachine, error) {
+		var vm mo.VirtualMachine
+		var c *govmomi.Client
+		var err error
+		if r.Mode() == ModeRecord {
+			c, err = r.NewRecordingClient(ctx, server.URL, true)
+		} else {
+			c, err = r.NewReplayClient(ctx)
+		}
+		if err != nil {
+			return vm, err
+		}
+		ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: moid}
+		err = property.DefaultCollector(c.Client).RetrieveOne(ctx, ref, []string{"name", "config.hardware"}, &vm)
+		return vm, err
+	}
+
+	rec, err := New(path, ModeRecord)
+	if err != nil {
+		t.Fatalf("error creating recorder: %s", err)
+	}
+	defer rec.Close()
+	moid := simulator.Map.Any("VirtualMachine").Reference().Value
+	rec.Set("moid", moid)
+	expected, err := readVM(rec, moid)
+	if err != nil {
+		t.Fatalf("error recording: %s", err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatalf("error saving fixture: %s", err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("error reading fixture: %s", err)
+	}
+	password, _ := server.URL.User.Password()
+	if strings.Contains(string(b), "<password>"+password+"</password>") {
+		t.Fatalf("fixture contains the login password")
+	}
+
+	server.Close()
+	rep, err := New(path, ModeReplay)
+	if err != nil {
+		t.Fatalf("error loading fixture: %s", err)
+	}
+	defer rep.Close()
+	actual, err := readVM(rep, rep.Get("moid"))
+	if err != nil {
+		t.Fatalf("error replaying: %s", err)
+	}
+	if !reflect.DeepEqual(expected.Config, actual.Config) || expected.Name != actual.Name {
+		t.Fatalf("expected %#v, got %#v", expected, actual)
+	}
+
+	ref := types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-unrecorded"}
+	c, _ := rep.NewReplayClient(ctx)
+	var vm mo.VirtualMachine
+	if err := property.DefaultCollector(c.Client).RetrieveOne(ctx, ref, []string{"name"}, &vm); err == nil {
+		t.Fatalf("expected error for unrecorded request")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package redact removes credentials and session secrets from vSphere SOAP and
+// REST traffic before it is written to fixtures or debug logs.
+package redact
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Placeholder is the value that sensitive fields are replaced with.
+const Placeholder = "**REDACTED**"
+
+// sensitiveXMLElements matches the SOAP elements whose contents are
+// redacted. Element names are matched without regard to case, as the STS
+// spells the password of a UsernameToken wsse:Password.
+var sensitiveXMLElements = regexp.MustCompile(`(?i)<((?:\w+:)?(?:password|newPassword|oldPassword|vimAccountPassword|token|vcSessionCookie|sessionID))(\s[^>]*)?>[^<]*</`)
+
+// sensitiveXMLBlocks matches the SOAP structures that are redacted in their
+// entirety.
+var sensitiveXMLBlocks = []*regexp.Regexp{
+	regexp.MustCompile(`(?is)<(?:adminPassword|domainAdminPassword)(\s[^>]*)?>.*?</(?:adminPassword|domainAdminPassword)>`),
+	regexp.MustCompile(`(?is)<password(\s[^>]*)?>\s*<value>.*?</password>`),
+	regexp.MustCompile(`(?is)<(?:\w+:)?Assertion[\s>].*?</(?:\w+:)?Assertion>`),
+}
+
+// sensitiveJSONFields matches the JSON string fields whose values are
+// redacted.
+var sensitiveJSONFields = regexp.MustCompile(`(?i)"(\w*(?:password|secret|token))"\s*:\s*"[^"]*"`)
+
+// jsonStrings matches every JSON string value.
+var jsonStrings = regexp.MustCompile(`"(?:[^"\\]|\\.)*"(\s*[,}\]]|\s*$)`)
+
+// sensitiveHeaders matches the HTTP headers whose values are redacted, in a
+// request or response dump.
+var sensitiveHeaders = regexp.MustCompile(`(?im)^((?:authorization|proxy-authorization|cookie|set-cookie|vmware-api-session-id):[ \t]*)[^\r\n]*`)
+
+// Body removes credentials and session secrets from a SOAP or JSON body.
+func Body(body string) string {
+	for _, re := range sensitiveXMLBlocks {
+		body = re.ReplaceAllString(body, Placeholder)
+	}
+	body = sensitiveXMLElements.ReplaceAllString(body, "<$1$2>"+Placeholder+"</")
+	return sensitiveJSONFields.ReplaceAllString(body, `"$1":"`+Placeholder+`"`)
+}
+
+// JSONStrings redacts every string value in a JSON body. Use it for bodies
+// that are secrets in their entirety, such as the response to a REST API
+// login, which is the session ID.
+func JSONStrings(body string) string {
+	return jsonStrings.ReplaceAllString(body, `"`+Placeholder+`"$1`)
+}
+
+// Headers removes credentials and session IDs from a dump of HTTP headers, as
+// written by httputil.DumpRequest or httputil.DumpResponse.
+func Headers(dump string) string {
+	return sensitiveHeaders.ReplaceAllString(dump, "${1}"+Placeholder)
+}
+
+// IsSessionPath returns true if path is the path of a REST API login, whose
+// response body is the session ID.
+func IsSessionPath(path string) bool {
+	if i := strings.IndexAny(path, "?#"); i >= 0 {
+		path = path[:i]
+	}
+	return strings.HasSuffix(path, "/cis/session") || strings.HasSuffix(path, "/api/session")
+}
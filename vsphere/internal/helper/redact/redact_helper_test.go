@@ -0,0 +1,151 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package redact
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/vmware/govmomi/sts"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestBody(t *testing.T) {
+	cases := []struct {
+		name     string
+		subject  string
+		expected string
+	}{
+		{
+			name:     "login password",
+			subject:  `<Login xmlns="urn:vim25"><userName>user</userName><password>hunter2</password></Login>`,
+			expected: `<Login xmlns="urn:vim25"><userName>user</userName><password>**REDACTED**</password></Login>`,
+		},
+		{
+			name:     "customization admin password",
+			subject:  `<adminPassword><value>hunter2</value><plainText>true</plainText></adminPassword>`,
+			expected: `**REDACTED**`,
+		},
+		{
+			name:     "customization domain password",
+			subject:  `<identification><domainAdmin>admin</domainAdmin><domainAdminPassword><value>hunter2</value><plainText>true</plainText></domainAdminPassword></identification>`,
+			expected: `<identification><domainAdmin>admin</domainAdmin>**REDACTED**</identification>`,
+		},
+		{
+			name:     "windows customization auto logon password",
+			subject:  `<guiUnattended><password><value>hunter2</value><plainText>true</plainText></password></guiUnattended>`,
+			expected: `<guiUnattended>**REDACTED**</guiUnattended>`,
+		},
+		{
+			name:     "ldap bind password",
+			subject:  `<RegisterLdap xmlns="urn:sso"><authenticationDetails><username>cn=admin</username><password>hunter2</password></authenticationDetails></RegisterLdap>`,
+			expected: `<RegisterLdap xmlns="urn:sso"><authenticationDetails><username>cn=admin</username><password>**REDACTED**</password></authenticationDetails></RegisterLdap>`,
+		},
+		{
+			name:     "ws-security password",
+			subject:  `<wsse:UsernameToken><wsse:Username>administrator@vsphere.local</wsse:Username><wsse:Password>hunter2</wsse:Password></wsse:UsernameToken>`,
+			expected: `<wsse:UsernameToken><wsse:Username>administrator@vsphere.local</wsse:Username><wsse:Password>**REDACTED**</wsse:Password></wsse:UsernameToken>`,
+		},
+		{
+			name:     "saml token",
+			subject:  `<Header><saml2:Assertion ID="_1">token</saml2:Assertion></Header>`,
+			expected: `<Header>**REDACTED**</Header>`,
+		},
+		{
+			name:     "json password",
+			subject:  `{"user":"user","password": "hunter2"}`,
+			expected: `{"user":"user","password":"**REDACTED**"}`,
+		},
+		{
+			name:     "json root password",
+			subject:  `{"root_password":"hunter2"}`,
+			expected: `{"root_password":"**REDACTED**"}`,
+		},
+		{
+			name:     "no secrets",
+			subject:  `<RetrieveServiceContent xmlns="urn:vim25"></RetrieveServiceContent>`,
+			expected: `<RetrieveServiceContent xmlns="urn:vim25"></RetrieveServiceContent>`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := Body(tc.subject); actual != tc.expected {
+				t.Fatalf("expected %q, got %q", tc.expected, actual)
+			}
+		})
+	}
+}
+
+// TestBodySTSIssue redacts the envelope that govmomi sends to the STS to
+// issue a SAML token for a username and password.
+func TestBodySTSIssue(t *testing.T) {
+	var body string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		body = string(b)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	u, err := url.Parse(server.URL + "/sts/STSService")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := soap.NewClient(u, true)
+	client := &sts.Client{Client: sc, RoundTripper: sc}
+	_, _ = client.Issue(context.Background(), sts.TokenRequest{
+		Userinfo: url.UserPassword("administrator@vsphere.local", "hunter2"),
+	})
+	if !strings.Contains(body, "hunter2") {
+		t.Fatalf("expected the STS Issue request to contain the password, got %q", body)
+	}
+
+	redacted := Body(body)
+	if strings.Contains(redacted, "hunter2") {
+		t.Fatalf("expected the password to be redacted, got %q", redacted)
+	}
+	if !strings.Contains(redacted, Placeholder) {
+		t.Fatalf("expected %q to contain %s", redacted, Placeholder)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	subject := "POST /sdk HTTP/1.1\r\nHost: vcenter\r\nAuthorization: Basic dXNlcjpodW50ZXIy\r\nCookie: vmware_soap_session=\"abc\"\r\nVmware-Api-Session-Id: abc\r\n\r\n"
+	expected := "POST /sdk HTTP/1.1\r\nHost: vcenter\r\nAuthorization: **REDACTED**\r\nCookie: **REDACTED**\r\nVmware-Api-Session-Id: **REDACTED**\r\n\r\n"
+	if actual := Headers(subject); actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestJSONStrings(t *testing.T) {
+	cases := map[string]string{
+		`"abc123"`:                     `"**REDACTED**"`,
+		`{"value":"abc123"}`:           `{"value":"**REDACTED**"}`,
+		`{"value": "a\"b", "n": 1}`:    `{"value": "**REDACTED**", "n": 1}`,
+		"{\"value\":[\"a\", \"b\"]}\n": "{\"value\":[\"**REDACTED**\", \"**REDACTED**\"]}\n",
+	}
+	for subject, expected := range cases {
+		if actual := JSONStrings(subject); actual != expected {
+			t.Fatalf("expected %q, got %q", expected, actual)
+		}
+	}
+}
+
+func TestIsSessionPath(t *testing.T) {
+	for path, expected := range map[string]bool{
+		"/rest/com/vmware/cis/session":             true,
+		"/rest/com/vmware/cis/session?~action=get": true,
+		"/api/session":                     true,
+		"/rest/com/vmware/cis/tagging/tag": false,
+	} {
+		if actual := IsSessionPath(path); actual != expected {
+			t.Fatalf("%s: expected %t, got %t", path, expected, actual)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/computeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -98,11 +99,12 @@ func FromID(client *govmomi.Client, id string) (*object.ResourcePool, error) {
 
 // Properties returns the ResourcePool managed object from its higher-level
 // object.
-func Properties(obj *object.ResourcePool) (*mo.ResourcePool, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(obj *object.ResourcePool, paths ...string) (*mo.ResourcePool, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.ResourcePool
-	if err := obj.Properties(ctx, obj.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, obj.Client(), obj.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -184,15 +186,14 @@ func Update(rp *object.ResourcePool, name string, spec *types.ResourceConfigSpec
 }
 
 // Delete destroys a ResourcePool.
-func Delete(rp *object.ResourcePool) error {
+func Delete(ctx context.Context, rp *object.ResourcePool) error {
 	log.Printf("[DEBUG] Deleting resource pool %q", rp.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
 	task, err := rp.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // MoveIntoResourcePool moves a virtual machine, resource pool, or
@@ -0,0 +1,202 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package secret keeps passwords and other secret arguments out of state. A
+// secret argument saves a fingerprint of its value in state, which Terraform
+// compares to the fingerprint of the configured value to detect a change. The
+// value itself is only read from the configuration when it is sent to
+// vSphere.
+//
+// A fingerprint is an HMAC-SHA-256 of the secret with the key set by SetKey,
+// so that the fingerprints in a state cannot be looked up in precomputed
+// tables. The fingerprint records the ID of its key, and a fingerprint made
+// with another key is not compared to the configured value, so that a change
+// to the key does not plan a change to every secret argument.
+package secret
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// FilePrefix marks a secret argument whose value is the path of a local file
+// that holds the secret, such as file:///etc/vsphere/ldap-password.
+const FilePrefix = "file://"
+
+// fingerprintPrefix is the prefix of a fingerprint saved in state. It is
+// followed by the ID of the key, a colon and the HMAC.
+const fingerprintPrefix = "hmac-sha256:"
+
+// fingerprintPattern matches a fingerprint. Fingerprints saved before they
+// recorded the ID of their key have no key ID.
+var fingerprintPattern = regexp.MustCompile(`^hmac-sha256:(?:([0-9a-f]{8}):)?[0-9a-f]{64}$`)
+
+var (
+	keyMu sync.RWMutex
+	key   []byte
+)
+
+// SetKey sets the key of the fingerprints. The provider sets it when it is
+// configured, before any secret is fingerprinted. A change to the key changes
+// every fingerprint, but the fingerprints made with the previous key are not
+// planned as changed. See DiffSuppressFunc.
+func SetKey(k string) {
+	keyMu.Lock()
+	defer keyMu.Unlock()
+	key = []byte(k)
+}
+
+// keyID returns the ID of the key k, which is saved with the fingerprints made
+// with it.
+func keyID(k []byte) string {
+	mac := hmac.New(sha256.New, k)
+	mac.Write([]byte("terraform-provider-vsphere key id"))
+	return hex.EncodeToString(mac.Sum(nil))[:8]
+}
+
+// Read returns the secret v, or if v has FilePrefix, the contents of the file
+// that it refers to without trailing line breaks.
+func Read(v string) (string, error) {
+	if !strings.HasPrefix(v, FilePrefix) {
+		return v, nil
+	}
+	path := strings.TrimPrefix(v, FilePrefix)
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("error reading secret from %s: %s", path, err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
+
+// Fingerprint returns the fingerprint of the secret v. The fingerprint of a
+// file reference is the one of the file contents, so that a change to the
+// file is a change to the secret. If the file cannot be read, the fingerprint
+// is the one of the reference itself, and the error is returned by Read when
+// the secret is sent to vSphere. An empty value has an empty fingerprint, so
+// that an unset argument stays unset.
+func Fingerprint(v string) string {
+	if v == "" {
+		return ""
+	}
+	if s, err := Read(v); err == nil {
+		v = s
+	}
+	keyMu.RLock()
+	mac := hmac.New(sha256.New, key)
+	id := keyID(key)
+	keyMu.RUnlock()
+	mac.Write([]byte(v))
+	return fingerprintPrefix + id + ":" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsFingerprint returns true if v is a fingerprint.
+func IsFingerprint(v string) bool {
+	return fingerprintPattern.MatchString(v)
+}
+
+// hasCurrentKey returns true if the fingerprint v was made with the key that is
+// set now.
+func hasCurrentKey(v string) bool {
+	m := fingerprintPattern.FindStringSubmatch(v)
+	if m == nil {
+		return false
+	}
+	keyMu.RLock()
+	defer keyMu.RUnlock()
+	return m[1] == keyID(key)
+}
+
+// DiffSuppressFunc is the schema.SchemaDiffSuppressFunc of a secret argument.
+// A fingerprint in state that was made with another key cannot be compared to
+// the fingerprint of the configured value, so the value is treated as
+// unchanged, and its fingerprint is made again with the current key by
+// Refingerprint the next time the resource is created or updated.
+func DiffSuppressFunc(_, old, new string, _ *schema.ResourceData) bool {
+	return IsFingerprint(old) && IsFingerprint(new) && !hasCurrentKey(old)
+}
+
+// StateFunc is the schema.SchemaStateFunc of a secret argument.
+func StateFunc(v interface{}) string {
+	return Fingerprint(v.(string))
+}
+
+// StateValue returns the value to save in state for v, the value of a secret
+// argument that Read got from a ResourceData. While a resource is created or
+// updated, that value is the secret, and it was saved in state before secrets
+// were fingerprinted, so anything other than a fingerprint is fingerprinted.
+func StateValue(v string) string {
+	if v == "" || IsFingerprint(v) {
+		return v
+	}
+	return Fingerprint(v)
+}
+
+// Refingerprint is StateValue for v, the value of the secret argument at key
+// in d, that also replaces a fingerprint made with another key with the
+// fingerprint of the configured value. The key addresses the argument like
+// the one of Get. A fingerprint is left as is when there is no configuration,
+// such as while the resource is refreshed.
+func Refingerprint(d *schema.ResourceData, key, v string) string {
+	if !IsFingerprint(v) || hasCurrentKey(v) {
+		return StateValue(v)
+	}
+	if s, err := Get(d, key); err == nil && s != "" {
+		return Fingerprint(s)
+	}
+	return v
+}
+
+// Get returns the secret at key in the configuration of d, reading it from a
+// file if the value has FilePrefix. Unlike d.Get, which returns the
+// fingerprint in state for a secret that has not changed, Get returns the
+// secret. The key addresses blocks in lists by index, such as
+// clone.0.customize.0.windows_options.0.admin_password, and cannot address
+// blocks in sets. An unset secret is an empty string.
+func Get(d *schema.ResourceData, key string) (string, error) {
+	return getFromConfig(d.GetRawConfig(), key)
+}
+
+// GetFromDiff is Get for the configuration of a resource that is planned.
+func GetFromDiff(d *schema.ResourceDiff, key string) (string, error) {
+	return getFromConfig(d.GetRawConfig(), key)
+}
+
+// getFromConfig returns the secret at key in the raw configuration v.
+func getFromConfig(v cty.Value, key string) (string, error) {
+	for _, part := range strings.Split(key, ".") {
+		if v.IsNull() || !v.IsKnown() {
+			return "", nil
+		}
+		if i, err := strconv.Atoi(part); err == nil {
+			if !v.Type().IsListType() && !v.Type().IsTupleType() {
+				return "", fmt.Errorf("cannot index %s: not a list", key)
+			}
+			if i >= v.LengthInt() {
+				return "", nil
+			}
+			v = v.Index(cty.NumberIntVal(int64(i)))
+			continue
+		}
+		if !v.Type().IsObjectType() || !v.Type().HasAttribute(part) {
+			return "", fmt.Errorf("cannot read %s: %q is not an attribute", key, part)
+		}
+		v = v.GetAttr(part)
+	}
+	if v.IsNull() || !v.IsKnown() {
+		return "", nil
+	}
+	if !v.Type().Equals(cty.String) {
+		return "", fmt.Errorf("cannot read %s: not a string", key)
+	}
+	return Read(v.AsString())
+}
@@ -0,0 +1,186 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package secret
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+func TestFingerprint(t *testing.T) {
+	if v := Fingerprint(""); v != "" {
+		t.Fatalf("expected an empty fingerprint, got %q", v)
+	}
+
+	fp := Fingerprint("VMware1!")
+	if !IsFingerprint(fp) {
+		t.Fatalf("expected %q to be a fingerprint", fp)
+	}
+	if fp == Fingerprint("VMware2!") {
+		t.Fatal("expected different secrets to have different fingerprints")
+	}
+	if StateValue(fp) != fp {
+		t.Fatal("expected a fingerprint to be saved as is")
+	}
+	if StateValue("VMware1!") != fp {
+		t.Fatal("expected a secret to be saved as its fingerprint")
+	}
+}
+
+func TestFingerprintKey(t *testing.T) {
+	t.Cleanup(func() { SetKey("") })
+
+	SetKey("key1")
+	fp := Fingerprint("VMware1!")
+	if fp != Fingerprint("VMware1!") {
+		t.Fatal("expected the same secret and key to have the same fingerprint")
+	}
+	unkeyed := sha256.Sum256([]byte("VMware1!"))
+	if strings.HasSuffix(fp, hex.EncodeToString(unkeyed[:])) {
+		t.Fatal("expected the fingerprint to be keyed")
+	}
+
+	SetKey("key2")
+	if fp == Fingerprint("VMware1!") {
+		t.Fatal("expected different keys to give different fingerprints")
+	}
+}
+
+func TestDiffSuppressFunc(t *testing.T) {
+	t.Cleanup(func() { SetKey("") })
+
+	SetKey("key1")
+	old := Fingerprint("VMware1!")
+	legacy := fingerprintPrefix + strings.TrimPrefix(old, fingerprintPrefix)[9:]
+	if !IsFingerprint(legacy) {
+		t.Fatalf("expected %q to be a fingerprint", legacy)
+	}
+
+	if DiffSuppressFunc("password", old, Fingerprint("VMware2!"), nil) {
+		t.Fatal("expected a change to the secret to not be suppressed")
+	}
+	SetKey("key2")
+	for _, v := range []string{old, legacy} {
+		if !DiffSuppressFunc("password", v, Fingerprint("VMware2!"), nil) {
+			t.Fatalf("expected %q, made with another key, to be treated as unchanged", v)
+		}
+	}
+	if DiffSuppressFunc("password", old, "", nil) {
+		t.Fatal("expected removing the secret to not be suppressed")
+	}
+	if DiffSuppressFunc("password", "", Fingerprint("VMware2!"), nil) {
+		t.Fatal("expected setting the secret to not be suppressed")
+	}
+}
+
+func TestRefingerprint(t *testing.T) {
+	t.Cleanup(func() { SetKey("") })
+
+	r := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"password": {
+				Type:             schema.TypeString,
+				Optional:         true,
+				Sensitive:        true,
+				StateFunc:        StateFunc,
+				DiffSuppressFunc: DiffSuppressFunc,
+			},
+		},
+	}
+	SetKey("key1")
+	old := Fingerprint("VMware1!")
+	SetKey("key2")
+
+	state := &terraform.InstanceState{ID: "test", Attributes: map[string]string{"password": old}}
+	refreshed := r.Data(state)
+	if v := Refingerprint(refreshed, "password", old); v != old {
+		t.Fatalf("expected the fingerprint to be kept without a configuration, got %q", v)
+	}
+
+	config := terraform.NewResourceConfigRaw(map[string]interface{}{"password": "VMware1!"})
+	diff, err := r.SimpleDiff(context.Background(), state, config, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff != nil && diff.Attributes["password"] != nil {
+		t.Fatalf("expected no change to the password after a key change, got %#v", diff.Attributes["password"])
+	}
+	applied := state.DeepCopy()
+	applied.RawConfig = cty.ObjectVal(map[string]cty.Value{"password": cty.StringVal("VMware1!")})
+	d := r.Data(applied)
+	if v := Refingerprint(d, "password", old); v != Fingerprint("VMware1!") {
+		t.Fatalf("expected the fingerprint to be made again with the current key, got %q", v)
+	}
+	if v := Refingerprint(d, "password", "VMware1!"); v != Fingerprint("VMware1!") {
+		t.Fatalf("expected a secret to be fingerprinted, got %q", v)
+	}
+}
+
+func TestFingerprintFile(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(p, []byte("VMware1!\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	v, err := Read(FilePrefix + p)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if v != "VMware1!" {
+		t.Fatalf("expected VMware1!, got %q", v)
+	}
+	if Fingerprint(FilePrefix+p) != Fingerprint("VMware1!") {
+		t.Fatal("expected the fingerprint of a file reference to be the one of the file contents")
+	}
+
+	missing := FilePrefix + filepath.Join(t.TempDir(), "missing")
+	if _, err := Read(missing); err == nil {
+		t.Fatal("expected error reading a missing file")
+	}
+	if !IsFingerprint(Fingerprint(missing)) {
+		t.Fatal("expected a fingerprint for a file reference that cannot be read")
+	}
+}
+
+func TestGetFromConfig(t *testing.T) {
+	config := cty.ObjectVal(map[string]cty.Value{
+		"password": cty.StringVal("VMware1!"),
+		"clone": cty.ListVal([]cty.Value{
+			cty.ObjectVal(map[string]cty.Value{
+				"admin_password":  cty.StringVal("VMware2!"),
+				"domain_password": cty.NullVal(cty.String),
+			}),
+		}),
+		"customize": cty.ListValEmpty(cty.Object(map[string]cty.Type{"admin_password": cty.String})),
+	})
+
+	cases := map[string]string{
+		"password":                   "VMware1!",
+		"clone.0.admin_password":     "VMware2!",
+		"clone.0.domain_password":    "",
+		"customize.0.admin_password": "",
+	}
+	for key, expected := range cases {
+		actual, err := getFromConfig(config, key)
+		if err != nil {
+			t.Fatalf("%s: %s", key, err)
+		}
+		if actual != expected {
+			t.Fatalf("%s: expected %q, got %q", key, expected, actual)
+		}
+	}
+
+	if _, err := getFromConfig(config, "user"); err == nil {
+		t.Fatal("expected error for an attribute that is not in the configuration")
+	}
+}
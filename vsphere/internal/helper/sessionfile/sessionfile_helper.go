@@ -0,0 +1,254 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package sessionfile reads and writes the SOAP and REST session files saved
+// when session persistence is enabled. Files can be encrypted at rest, and are
+// locked while they are read and written so that Terraform runs sharing a
+// session directory do not race on them.
+package sessionfile
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// encryptedPrefix marks a session file encrypted by a Store. Plaintext session
+// files are JSON, and so never start with it.
+const encryptedPrefix = "vsphere-session:aes256-gcm:"
+
+// Store reads and writes session files. Files are encrypted with AES-256-GCM
+// if the store has a key, and are stored as plain JSON otherwise, which is
+// compatible with govc.
+type Store struct {
+	aead cipher.AEAD
+}
+
+// NewStore returns a store that encrypts session files with key. An empty key
+// returns a store that reads and writes plaintext session files.
+//
+// The key is hashed with SHA-256 to derive the AES key, so it should be a
+// high-entropy random value rather than a password.
+func NewStore(key string) (*Store, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return &Store{}, nil
+	}
+	sum := sha256.Sum256([]byte(key))
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Store{aead: aead}, nil
+}
+
+// ReadKeyFile reads an encryption key from a file. Surrounding whitespace is
+// ignored.
+func ReadKeyFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimSpace(string(b))
+	if key == "" {
+		return "", fmt.Errorf("key file %q is empty", path)
+	}
+	return key, nil
+}
+
+// ReadOrCreateKeyFile reads the key in the file at path like ReadKeyFile, or if
+// there is no file, generates a random key and saves it there, readable only by
+// the current user. created is true if the key was generated. The key is
+// saved in a temporary file that is then linked to path, so that concurrent
+// runs that both generate a key all use the one that was linked first.
+func ReadOrCreateKeyFile(path string) (key string, created bool, err error) {
+	key, err = ReadKeyFile(path)
+	if err == nil || !os.IsNotExist(err) {
+		return key, false, err
+	}
+
+	b := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, b); err != nil {
+		return "", false, err
+	}
+	key = base64.StdEncoding.EncodeToString(b)
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return "", false, err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(path)+".*")
+	if err != nil {
+		return "", false, err
+	}
+	defer func() {
+		_ = os.Remove(f.Name())
+	}()
+	_, err = f.WriteString(key + "\n")
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return "", false, err
+	}
+	// CreateTemp creates the file with mode 0600.
+	if err := os.Link(f.Name(), path); err != nil {
+		if !os.IsExist(err) {
+			return "", false, err
+		}
+		key, err = ReadKeyFile(path)
+		return key, false, err
+	}
+	return key, true, nil
+}
+
+// Encrypted returns true if the store encrypts session files.
+func (s *Store) Encrypted() bool {
+	return s.aead != nil
+}
+
+// Read decodes the session file at path into v. It returns false if the file
+// does not exist, or if it cannot be decrypted or decoded, in which case a new
+// session should be created and saved over it.
+func (s *Store) Read(path string, v interface{}) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+
+	var data []byte
+	err = withLock(f, false, func() error {
+		var err error
+		data, err = io.ReadAll(f)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	data, err = s.decrypt(data)
+	if err != nil {
+		log.Printf("[WARN] Ignoring session file %q: %s", path, err)
+		return false, nil
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		log.Printf("[WARN] Ignoring session file %q: error decoding session: %s", path, err)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Write encodes v and saves it to the session file at path. The file is
+// rewritten in place while it is locked, so concurrent readers see either the
+// old or the new session. A file left incomplete by an interrupted write is
+// ignored by Read.
+func (s *Store) Write(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	data, err = s.encrypt(data)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	err = withLock(f, true, func() error {
+		if err := f.Truncate(0); err != nil {
+			return err
+		}
+		if _, err := f.Write(data); err != nil {
+			return err
+		}
+		return f.Sync()
+	})
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// encrypt seals data if the store has a key.
+func (s *Store) encrypt(data []byte) ([]byte, error) {
+	if s.aead == nil {
+		return data, nil
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	sealed := s.aead.Seal(nonce, nonce, data, nil)
+	return []byte(encryptedPrefix + base64.StdEncoding.EncodeToString(sealed)), nil
+}
+
+// decrypt opens data if it is encrypted. An error is returned if data is
+// encrypted and the store has no key or a different key, or if data is not
+// encrypted and the store has a key.
+func (s *Store) decrypt(data []byte) ([]byte, error) {
+	encrypted := bytes.HasPrefix(data, []byte(encryptedPrefix))
+	switch {
+	case s.aead == nil && !encrypted:
+		return data, nil
+	case s.aead == nil:
+		return nil, fmt.Errorf("session is encrypted and no encryption key is configured")
+	case !encrypted:
+		return nil, fmt.Errorf("session is not encrypted")
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(data[len(encryptedPrefix):])))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding encrypted session: %s", err)
+	}
+	if len(sealed) < s.aead.NonceSize() {
+		return nil, fmt.Errorf("encrypted session is truncated")
+	}
+	nonce, ciphertext := sealed[:s.aead.NonceSize()], sealed[s.aead.NonceSize():]
+	data, err = s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decrypting session, the encryption key may have changed: %s", err)
+	}
+	return data, nil
+}
+
+// withLock runs fn while holding a lock on the session file f. Readers share
+// the lock, writers hold it exclusively. Locking the session file itself,
+// rather than a separate lock file, leaves nothing behind in the session
+// directory.
+func withLock(f *os.File, exclusive bool, fn func() error) error {
+	if err := lockFile(f, exclusive); err != nil {
+		return fmt.Errorf("error locking session file %q: %s", f.Name(), err)
+	}
+	defer func() {
+		_ = unlockFile(f)
+	}()
+	return fn()
+}
@@ -0,0 +1,172 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package sessionfile
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+type testSession struct {
+	Cookie string `json:"cookie"`
+}
+
+func testStore(t *testing.T, key string) *Store {
+	t.Helper()
+	s, err := NewStore(key)
+	if err != nil {
+		t.Fatalf("error creating store: %s", err)
+	}
+	return s
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	for _, key := range []string{"", "0123456789abcdef0123456789abcdef"} {
+		t.Run(fmt.Sprintf("encrypted=%t", key != ""), func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "sessions", "session")
+			s := testStore(t, key)
+
+			var actual testSession
+			ok, err := s.Read(p, &actual)
+			if err != nil || ok {
+				t.Fatalf("expected missing session to not be found, got %t, %v", ok, err)
+			}
+
+			expected := testSession{Cookie: "vmware_soap_session=52a3f6d0"}
+			if err := s.Write(p, expected); err != nil {
+				t.Fatalf("error writing session: %s", err)
+			}
+			ok, err = s.Read(p, &actual)
+			if err != nil || !ok {
+				t.Fatalf("expected session to be read, got %t, %v", ok, err)
+			}
+			if actual != expected {
+				t.Fatalf("expected %#v, got %#v", expected, actual)
+			}
+
+			data, err := os.ReadFile(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if s.Encrypted() == strings.Contains(string(data), expected.Cookie) {
+				t.Fatalf("unexpected session file contents: %s", data)
+			}
+			info, err := os.Stat(p)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != 0600 {
+				t.Fatalf("expected session file mode 0600, got %o", info.Mode().Perm())
+			}
+		})
+	}
+}
+
+func TestStoreReadMismatch(t *testing.T) {
+	cases := []struct {
+		name  string
+		write string
+		read  string
+	}{
+		{name: "different key", write: "key-one", read: "key-two"},
+		{name: "encrypted without key", write: "key-one", read: ""},
+		{name: "plaintext with key", write: "", read: "key-one"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "session")
+			if err := testStore(t, tc.write).Write(p, testSession{Cookie: "foo"}); err != nil {
+				t.Fatalf("error writing session: %s", err)
+			}
+			var actual testSession
+			ok, err := testStore(t, tc.read).Read(p, &actual)
+			if err != nil || ok {
+				t.Fatalf("expected session to be ignored, got %t, %v", ok, err)
+			}
+		})
+	}
+}
+
+func TestStoreReadCorrupt(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "session")
+	if err := os.WriteFile(p, []byte(`{"cookie":`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var actual testSession
+	ok, err := testStore(t, "").Read(p, &actual)
+	if err != nil || ok {
+		t.Fatalf("expected corrupt session to be ignored, got %t, %v", ok, err)
+	}
+}
+
+func TestStoreConcurrentWrites(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "session")
+	s := testStore(t, "key")
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := s.Write(p, testSession{Cookie: strings.Repeat("x", i*100)}); err != nil {
+				t.Errorf("error writing session: %s", err)
+			}
+			var actual testSession
+			if ok, err := s.Read(p, &actual); err != nil || !ok {
+				t.Errorf("expected a complete session to be read, got %t, %v", ok, err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	entries, err := os.ReadDir(filepath.Dir(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the session file to be left, found %d files", len(entries))
+	}
+}
+
+func TestReadOrCreateKeyFile(t *testing.T) {
+	dir := t.TempDir()
+	p := filepath.Join(dir, "sessions", "key")
+
+	key, created, err := ReadOrCreateKeyFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created || len(key) < 32 {
+		t.Fatalf("expected a random key to be created, got %q, %t", key, created)
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Fatalf("expected key file mode 0600, got %o", info.Mode().Perm())
+	}
+	entries, err := os.ReadDir(filepath.Dir(p))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected only the key file to be left, got %d files", len(entries))
+	}
+
+	again, created, err := ReadOrCreateKeyFile(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || again != key {
+		t.Fatalf("expected the saved key to be read, got %q, %t", again, created)
+	}
+	if other, _, err := ReadOrCreateKeyFile(filepath.Join(dir, "other")); err != nil || other == key {
+		t.Fatalf("expected a different key for another file, got %q, %v", other, err)
+	}
+}
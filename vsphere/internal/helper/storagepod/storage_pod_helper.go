@@ -94,11 +94,12 @@ func FromPath(client *govmomi.Client, name string, dc *object.Datacenter) (*obje
 
 // Properties is a convenience method that wraps fetching the
 // StoragePod MO from its higher-level object.
-func Properties(pod *object.StoragePod) (*mo.StoragePod, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(pod *object.StoragePod, paths ...string) (*mo.StoragePod, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.StoragePod
-	if err := pod.Properties(ctx, pod.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, pod.Client(), pod.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -119,39 +120,41 @@ func Create(f *object.Folder, name string) (*object.StoragePod, error) {
 
 // ApplyDRSConfiguration takes a types.StorageDrsConfigSpec and applies it
 // against the specified StoragePod.
-func ApplyDRSConfiguration(client *govmomi.Client, pod *object.StoragePod, spec types.StorageDrsConfigSpec) error {
+func ApplyDRSConfiguration(ctx context.Context, client *govmomi.Client, pod *object.StoragePod, spec types.StorageDrsConfigSpec) error {
 	log.Printf("[DEBUG] Applying storage DRS configuration against datastore cluster %q", pod.InventoryPath)
 	mgr := object.NewStorageResourceManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := mgr.ConfigureStorageDrsForPod(ctx, pod, spec, true)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // Rename renames a StoragePod.
-func Rename(pod *object.StoragePod, name string) error {
+func Rename(ctx context.Context, pod *object.StoragePod, name string) error {
 	log.Printf("[DEBUG] Renaming storage pod %q to %s", pod.InventoryPath, name)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := pod.Rename(ctx, name)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // MoveToFolder is a complex method that moves a StoragePod to a given relative
 // datastore folder path. "Relative" here means relative to a datacenter, which
 // is discovered from the current StoragePod path.
-func MoveToFolder(client *govmomi.Client, pod *object.StoragePod, relative string) error {
+func MoveToFolder(ctx context.Context, client *govmomi.Client, pod *object.StoragePod, relative string) error {
 	f, err := folder.DatastoreFolderFromObject(client, pod, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(pod.Reference(), f)
+	return folder.MoveObjectTo(ctx, pod.Reference(), f)
 }
 
 // HasChildren checks to see if a datastore cluster has any child items
@@ -165,15 +168,16 @@ func HasChildren(pod *object.StoragePod) (bool, error) {
 }
 
 // Delete destroys a StoragePod.
-func Delete(pod *object.StoragePod) error {
+func Delete(ctx context.Context, pod *object.StoragePod) error {
 	log.Printf("[DEBUG] Deleting datastore cluster %q", pod.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := pod.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // StorageDRSEnabled checks a StoragePod to see if Storage DRS is enabled.
@@ -192,6 +196,7 @@ func StorageDRSEnabled(pod *object.StoragePod) (bool, error) {
 // StorageResourceManager API. It mimics our helper in the virtualmachine
 // package in functionality, returning a VM helper object on success.
 func CreateVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	fo *object.Folder,
 	spec types.VirtualMachineConfigSpec,
@@ -226,7 +231,7 @@ func CreateVM(
 		sps.Host = types.NewReference(host.Reference())
 	}
 
-	placement, err := recommendSDRS(client, sps, timeout)
+	placement, err := recommendSDRS(ctx, client, sps, timeout)
 	if err != nil {
 		return nil, err
 	}
@@ -238,18 +243,19 @@ func CreateVM(
 		case viapi.IsManagedObjectNotFoundError(err):
 			// This isn't a vApp container, so continue with normal SDRS work flow.
 		case err == nil:
-			return createVAppVMFromSPS(client, placement, spec, sps, vc, timeout)
+			return createVAppVMFromSPS(ctx, client, placement, spec, sps, vc, timeout)
 		default:
 			return nil, err
 		}
 	}
-	return applySDRS(client, placement, timeout)
+	return applySDRS(ctx, client, placement, timeout)
 }
 
 // CloneVM clones a virtual machine to a datastore cluster via the
 // StorageResourceManager API. It mimics our helper in the virtualmachine
 // package in functionality, returning a VM helper object on success.
 func CloneVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	src *object.VirtualMachine,
 	fo *object.Folder,
@@ -271,6 +277,11 @@ func CloneVM(
 		fmt.Sprintf("%s/%s", fo.InventoryPath, name),
 		pod.Name(),
 	)
+	release, err := viapi.AcquireTask(ctx, client.Client, viapi.TaskClassClone, fmt.Sprintf("clone of %q", name))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	sps := types.StoragePlacementSpec{
 		Folder:    types.NewReference(fo.Reference()),
@@ -283,7 +294,7 @@ func CloneVM(
 		Type: string(types.StoragePlacementSpecPlacementTypeClone),
 	}
 
-	return recommendAndApplySDRS(client, sps, time.Minute*time.Duration(timeout))
+	return recommendAndApplySDRS(ctx, client, sps, time.Minute*time.Duration(timeout))
 }
 
 // ReconfigureVM reconfigures a virtual machine via the StorageResourceManager
@@ -295,6 +306,7 @@ func CloneVM(
 // are necessary, use the regular Reconfigure function in the virtualmachine
 // helper package.
 func ReconfigureVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	vm *object.VirtualMachine,
 	spec types.VirtualMachineConfigSpec,
@@ -323,7 +335,7 @@ func ReconfigureVM(
 		ConfigSpec: &spec,
 	}
 
-	_, err = recommendAndApplySDRS(client, sps, provider.DefaultAPITimeout)
+	_, err = recommendAndApplySDRS(ctx, client, sps, provider.DefaultAPITimeout)
 	return err
 }
 
@@ -331,6 +343,7 @@ func ReconfigureVM(
 // StorageResourceManager API. It mimics our helper in the virtualmachine
 // package in functionality.
 func RelocateVM(
+	ctx context.Context,
 	client *govmomi.Client,
 	vm *object.VirtualMachine,
 	spec types.VirtualMachineRelocateSpec,
@@ -349,6 +362,11 @@ func RelocateVM(
 		vm.InventoryPath,
 		pod.Name(),
 	)
+	release, err := viapi.AcquireTask(ctx, client.Client, viapi.TaskClassRelocate, fmt.Sprintf("migration of %q", vm.InventoryPath))
+	if err != nil {
+		return err
+	}
+	defer release()
 
 	sps := types.StoragePlacementSpec{
 		Vm: types.NewReference(vm.Reference()),
@@ -360,26 +378,27 @@ func RelocateVM(
 		Type:         string(types.StoragePlacementSpecPlacementTypeRelocate),
 	}
 
-	_, err = recommendAndApplySDRS(client, sps, time.Minute*time.Duration(timeout))
+	_, err = recommendAndApplySDRS(ctx, client, sps, time.Minute*time.Duration(timeout))
 	return err
 }
 
 func recommendAndApplySDRS(
+	ctx context.Context,
 	client *govmomi.Client,
 	sps types.StoragePlacementSpec,
 	timeout time.Duration,
 ) (*object.VirtualMachine, error) {
-	placement, err := recommendSDRS(client, sps, timeout)
+	placement, err := recommendSDRS(ctx, client, sps, timeout)
 	if err != nil {
 		return nil, err
 	}
-	return applySDRS(client, placement, timeout)
+	return applySDRS(ctx, client, placement, timeout)
 }
 
-func recommendSDRS(client *govmomi.Client, sps types.StoragePlacementSpec, timeout time.Duration) (*types.StoragePlacementResult, error) {
+func recommendSDRS(ctx context.Context, client *govmomi.Client, sps types.StoragePlacementSpec, timeout time.Duration) (*types.StoragePlacementResult, error) {
 	log.Printf("[DEBUG] Acquiring Storage DRS recommendations (type: %q)", sps.Type)
 	srm := object.NewStorageResourceManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	placement, err := srm.RecommendDatastores(ctx, sps)
 	if err != nil {
@@ -392,17 +411,17 @@ func recommendSDRS(client *govmomi.Client, sps types.StoragePlacementSpec, timeo
 	return placement, nil
 }
 
-func applySDRS(client *govmomi.Client, placement *types.StoragePlacementResult, timeout time.Duration) (*object.VirtualMachine, error) {
+func applySDRS(ctx context.Context, client *govmomi.Client, placement *types.StoragePlacementResult, timeout time.Duration) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] Applying Storage DRS recommendations (type: %q)", placement.Recommendations[0].Type)
 	srm := object.NewStorageResourceManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	// Apply the first recommendation
 	task, err := srm.ApplyStorageDrsRecommendation(ctx, []string{placement.Recommendations[0].Key})
 	if err != nil {
 		return nil, err
 	}
-	result, err := task.WaitForResult(ctx, nil)
+	result, err := viapi.WaitForTask(ctx, task)
 	if err != nil {
 		// Provide a friendly error message for timeouts
 		if ctx.Err() == context.DeadlineExceeded {
@@ -427,6 +446,7 @@ func applySDRS(client *govmomi.Client, placement *types.StoragePlacementResult,
 }
 
 func createVAppVMFromSPS(
+	ctx context.Context,
 	client *govmomi.Client,
 	placement *types.StoragePlacementResult,
 	spec types.VirtualMachineConfigSpec,
@@ -446,7 +466,7 @@ func createVAppVMFromSPS(
 	if err != nil {
 		return nil, err
 	}
-	return virtualmachine.Create(client, f, spec, vc.ResourcePool, nil, timeout)
+	return virtualmachine.Create(ctx, client, f, spec, vc.ResourcePool, nil, timeout)
 }
 
 // HasDiskCreationOperations is an exported function that checks a list of
@@ -0,0 +1,386 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+// Package transport applies the provider's TLS and proxy settings to the HTTP
+// transports used to talk to vSphere and to fetch OVF sources.
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/soap"
+	"golang.org/x/net/proxy"
+)
+
+// Settings holds the TLS and proxy settings for the connections made by the
+// provider. The zero value, and a nil *Settings, leave transports unchanged.
+type Settings struct {
+	// Skip certificate verification.
+	Insecure bool
+
+	// PEM-encoded CA certificates trusted in addition to the system roots.
+	CABundle []byte
+
+	// The vSphere server, and the SHA-256 thumbprint its certificate is
+	// pinned to. A server whose certificate matches the thumbprint is trusted
+	// without certificate authority verification.
+	Server     string
+	Thumbprint string
+
+	// The HTTP, HTTPS or SOCKS5 proxy to connect through. If nil, the proxy
+	// is taken from the environment.
+	Proxy *url.URL
+}
+
+// Validate checks the thumbprint and proxy settings.
+func (s *Settings) Validate() error {
+	if s.Thumbprint != "" {
+		if s.Server == "" {
+			return fmt.Errorf("a server is required to pin a thumbprint")
+		}
+		b, err := hex.DecodeString(normalizeThumbprint(s.Thumbprint))
+		if err != nil || len(b) != sha256.Size {
+			return fmt.Errorf("%q is not a SHA-256 thumbprint", s.Thumbprint)
+		}
+	}
+	if s.Proxy != nil {
+		switch s.Proxy.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("unsupported proxy scheme %q, must be one of http, https, socks5 or socks5h", s.Proxy.Scheme)
+		}
+	}
+	if len(s.CABundle) > 0 {
+		if ok := x509.NewCertPool().AppendCertsFromPEM(s.CABundle); !ok {
+			return fmt.Errorf("no certificates found in CA bundle")
+		}
+	}
+	return nil
+}
+
+// Thumbprint returns the SHA-256 thumbprint of a certificate, in the colon
+// separated format shown by vSphere.
+func Thumbprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// normalizeThumbprint strips separators and case from a thumbprint so that
+// thumbprints can be compared.
+func normalizeThumbprint(thumbprint string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(thumbprint))
+}
+
+// enabled returns true if any of the settings that require the transport to
+// be taken over are set.
+func (s *Settings) enabled() bool {
+	return s != nil && (len(s.CABundle) > 0 || s.Thumbprint != "" || s.Proxy != nil)
+}
+
+// Configure applies the settings to t. Connections made through t dial
+// through the proxy, and verify certificates against the system roots and the
+// CA bundle, or the pinned thumbprint for the vSphere server.
+//
+// Configure only changes t if a CA bundle, thumbprint or proxy is set, so
+// that the default behavior of govmomi is kept otherwise. Direct connections
+// to hosts other than the pinned server are still made by the DialTLSContext
+// that t already has, if any.
+func (s *Settings) Configure(t *http.Transport) error {
+	return s.configure(t, nil)
+}
+
+// ConfigureSOAP applies the settings to the transport of a SOAP client. The
+// REST, SSO and STS clients derived from c share its transport. The host
+// thumbprints known to c, such as those of ESXi hosts used for NFC transfers,
+// remain trusted, including for connections made through the proxy.
+func (s *Settings) ConfigureSOAP(c *soap.Client) error {
+	return s.configure(c.DefaultTransport(), c.Thumbprint)
+}
+
+// configure applies the settings to t. known returns the SHA-1 thumbprint
+// that a host is trusted by, if any.
+func (s *Settings) configure(t *http.Transport, known func(string) string) error {
+	if !s.enabled() {
+		return nil
+	}
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	var roots *x509.CertPool
+	if t.TLSClientConfig != nil && t.TLSClientConfig.RootCAs != nil {
+		roots = t.TLSClientConfig.RootCAs.Clone()
+	} else if roots, _ = x509.SystemCertPool(); roots == nil {
+		roots = x509.NewCertPool()
+	}
+	roots.AppendCertsFromPEM(s.CABundle)
+
+	// The CA bundle is added to the roots of the transport so that the
+	// existing dialer trusts it too.
+	config := &tls.Config{}
+	if t.TLSClientConfig != nil {
+		config = t.TLSClientConfig.Clone()
+	}
+	config.RootCAs = roots
+	t.TLSClientConfig = config
+
+	d := &dialer{
+		settings: s,
+		roots:    roots,
+		base:     t,
+		next:     t.DialTLSContext,
+		known:    known,
+	}
+	// HTTPS connections are tunneled through the proxy by DialTLSContext so
+	// that the name of the host is known when verifying its certificate. Go
+	// does not use DialTLSContext for proxied requests, so the proxy is only
+	// handed to the transport for plain HTTP.
+	t.Proxy = func(req *http.Request) (*url.URL, error) {
+		if req.URL.Scheme == "https" {
+			return nil, nil
+		}
+		return d.proxyFor(req.URL)
+	}
+	t.DialContext = d.dialContext
+	t.DialTLSContext = d.dialTLSContext
+	return nil
+}
+
+// HTTPClient returns an HTTP client for fetching OVF and content library
+// sources with the settings applied. insecure controls certificate
+// verification for the client, regardless of the Insecure setting.
+func (s *Settings) HTTPClient(insecure bool) (*http.Client, error) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.TLSClientConfig = &tls.Config{InsecureSkipVerify: insecure}
+	if s != nil {
+		c := *s
+		c.Insecure = insecure
+		if err := c.Configure(t); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Client{Transport: t}, nil
+}
+
+// dialer dials connections for a transport configured with Configure.
+type dialer struct {
+	settings *Settings
+	roots    *x509.CertPool
+
+	// The transport being configured. Client certificates are taken from its
+	// TLS configuration when a connection is made.
+	base *http.Transport
+
+	// The DialTLSContext that the transport had before it was configured,
+	// such as the one of govmomi that trusts known host thumbprints. It makes
+	// the direct connections to hosts other than the pinned server.
+	next func(ctx context.Context, network, addr string) (net.Conn, error)
+
+	// Returns the SHA-1 thumbprint that a host is trusted by, if any.
+	known func(string) string
+}
+
+// proxyFor returns the proxy to use for u.
+func (d *dialer) proxyFor(u *url.URL) (*url.URL, error) {
+	if d.settings.Proxy != nil {
+		return d.settings.Proxy, nil
+	}
+	return http.ProxyFromEnvironment(&http.Request{URL: u})
+}
+
+// dialContext dials addr directly. Plain HTTP requests are proxied by the
+// transport itself.
+func (d *dialer) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	nd := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return nd.DialContext(ctx, network, addr)
+}
+
+// dialTLSContext dials addr, through the proxy if there is one, and performs
+// the TLS handshake.
+func (d *dialer) dialTLSContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	p, err := d.proxyFor(&url.URL{Scheme: "https", Host: addr})
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil && d.next != nil && !d.pinned(host) {
+		return d.next(ctx, network, addr)
+	}
+
+	var conn net.Conn
+	if p == nil {
+		conn, err = d.dialContext(ctx, network, addr)
+	} else {
+		conn, err = d.dialProxy(ctx, p, network, addr)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{}
+	if d.base.TLSClientConfig != nil {
+		config = d.base.TLSClientConfig.Clone()
+	}
+	config.ServerName = host
+	config.InsecureSkipVerify = true
+	config.VerifyConnection = d.verifyConnection(addr)
+
+	tc := tls.Client(conn, config)
+	if err := tc.HandshakeContext(ctx); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return tc, nil
+}
+
+// pinned returns true if the certificate of host is pinned to a thumbprint.
+func (d *dialer) pinned(host string) bool {
+	return d.settings.Thumbprint != "" && strings.EqualFold(host, serverHost(d.settings.Server))
+}
+
+// verifyConnection returns a function that verifies the certificate presented
+// by addr.
+func (d *dialer) verifyConnection(addr string) func(tls.ConnectionState) error {
+	host, _, _ := net.SplitHostPort(addr)
+	return func(cs tls.ConnectionState) error {
+		if len(cs.PeerCertificates) == 0 {
+			return fmt.Errorf("%s did not present a certificate", host)
+		}
+		leaf := cs.PeerCertificates[0]
+
+		if d.pinned(host) {
+			if actual := Thumbprint(leaf); normalizeThumbprint(actual) != normalizeThumbprint(d.settings.Thumbprint) {
+				return fmt.Errorf("certificate thumbprint %s of %s does not match the pinned thumbprint %s", actual, host, d.settings.Thumbprint)
+			}
+			return nil
+		}
+		if d.settings.Insecure {
+			return nil
+		}
+
+		intermediates := x509.NewCertPool()
+		for _, cert := range cs.PeerCertificates[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err := leaf.Verify(x509.VerifyOptions{
+			DNSName:       host,
+			Roots:         d.roots,
+			Intermediates: intermediates,
+		})
+		if err != nil && d.known != nil {
+			if thumbprint := d.known(addr); thumbprint != "" && normalizeThumbprint(thumbprint) == normalizeThumbprint(soap.ThumbprintSHA1(leaf)) {
+				return nil
+			}
+		}
+		return err
+	}
+}
+
+// dialProxy connects to addr through the proxy p.
+func (d *dialer) dialProxy(ctx context.Context, p *url.URL, network, addr string) (net.Conn, error) {
+	if p.Scheme == "socks5" || p.Scheme == "socks5h" {
+		pd, err := proxy.FromURL(p, &net.Dialer{Timeout: 30 * time.Second})
+		if err != nil {
+			return nil, err
+		}
+		if cd, ok := pd.(proxy.ContextDialer); ok {
+			return cd.DialContext(ctx, network, addr)
+		}
+		return pd.Dial(network, addr)
+	}
+
+	proxyAddr := p.Host
+	if p.Port() == "" {
+		port := "80"
+		if p.Scheme == "https" {
+			port = "443"
+		}
+		proxyAddr = net.JoinHostPort(p.Hostname(), port)
+	}
+	conn, err := d.dialContext(ctx, network, proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("error connecting to proxy %s: %s", p.Host, err)
+	}
+	if p.Scheme == "https" {
+		tc := tls.Client(conn, &tls.Config{
+			ServerName: p.Hostname(),
+			RootCAs:    d.roots,
+		})
+		if err := tc.HandshakeContext(ctx); err != nil {
+			_ = conn.Close()
+			return nil, fmt.Errorf("error connecting to proxy %s: %s", p.Host, err)
+		}
+		conn = tc
+	}
+	if err := connect(ctx, conn, p, addr); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// connect opens a tunnel to addr through an HTTP proxy on conn.
+func connect(ctx context.Context, conn net.Conn, p *url.URL, addr string) error {
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if p.User != nil {
+		password, _ := p.User.Password()
+		auth := base64.StdEncoding.EncodeToString([]byte(p.User.Username() + ":" + password))
+		req.Header.Set("Proxy-Authorization", "Basic "+auth)
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+		defer func() {
+			_ = conn.SetDeadline(time.Time{})
+		}()
+	}
+	if err := req.Write(conn); err != nil {
+		return fmt.Errorf("error writing CONNECT request to proxy %s: %s", p.Host, err)
+	}
+	res, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		return fmt.Errorf("error reading CONNECT response from proxy %s: %s", p.Host, err)
+	}
+	_ = res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("proxy %s refused to connect to %s: %s", p.Host, addr, res.Status)
+	}
+	return nil
+}
+
+// serverHost returns the host part of a server address that may include a
+// port.
+func serverHost(server string) string {
+	if host, _, err := net.SplitHostPort(server); err == nil {
+		return host
+	}
+	return server
+}
@@ -0,0 +1,237 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package transport
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+func TestSettingsValidate(t *testing.T) {
+	cases := []struct {
+		name     string
+		settings Settings
+		err      string
+	}{
+		{
+			name: "thumbprint with colons",
+			settings: Settings{
+				Server:     "vcenter.example.com",
+				Thumbprint: strings.Repeat("AB:", 31) + "AB",
+			},
+		},
+		{
+			name: "sha-1 thumbprint",
+			settings: Settings{
+				Server:     "vcenter.example.com",
+				Thumbprint: strings.Repeat("AB:", 19) + "AB",
+			},
+			err: "is not a SHA-256 thumbprint",
+		},
+		{
+			name: "socks proxy",
+			settings: Settings{
+				Proxy: &url.URL{Scheme: "socks5", Host: "proxy.example.com:1080"},
+			},
+		},
+		{
+			name: "unsupported proxy",
+			settings: Settings{
+				Proxy: &url.URL{Scheme: "ftp", Host: "proxy.example.com"},
+			},
+			err: "unsupported proxy scheme",
+		},
+		{
+			name: "empty CA bundle",
+			settings: Settings{
+				CABundle: []byte("not a certificate"),
+			},
+			err: "no certificates found",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.settings.Validate()
+			switch {
+			case err == nil && tc.err != "":
+				t.Fatalf("expected error %q, got none", tc.err)
+			case err != nil && (tc.err == "" || !strings.Contains(err.Error(), tc.err)):
+				t.Fatalf("expected error %q, got %q", tc.err, err)
+			}
+		})
+	}
+}
+
+// testGet makes a request to the server with the settings applied.
+func testGet(s *Settings, server *httptest.Server) error {
+	client, err := s.HTTPClient(s.Insecure)
+	if err != nil {
+		return err
+	}
+	res, err := client.Get(server.URL)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+	return nil
+}
+
+func testServer(t *testing.T) (*httptest.Server, []byte) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(server.Close)
+	bundle := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	return server, bundle
+}
+
+func TestConfigureThumbprint(t *testing.T) {
+	server, _ := testServer(t)
+	host := server.Listener.Addr().String()
+
+	s := &Settings{
+		Server:     host,
+		Thumbprint: Thumbprint(server.Certificate()),
+	}
+	if err := testGet(s, server); err != nil {
+		t.Fatalf("expected pinned certificate to be accepted, got %s", err)
+	}
+
+	s.Thumbprint = strings.Repeat("00:", 31) + "00"
+	err := testGet(s, server)
+	if err == nil || !strings.Contains(err.Error(), "does not match the pinned thumbprint") {
+		t.Fatalf("expected thumbprint mismatch, got %v", err)
+	}
+}
+
+// testOtherBundle returns a PEM-encoded self-signed CA certificate that did
+// not sign the test server's certificate.
+func testOtherBundle(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Other CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestConfigureCABundle(t *testing.T) {
+	server, bundle := testServer(t)
+
+	if err := testGet(&Settings{CABundle: bundle}, server); err != nil {
+		t.Fatalf("expected certificate to be trusted through the CA bundle, got %s", err)
+	}
+	if err := testGet(&Settings{CABundle: testOtherBundle(t)}, server); err == nil {
+		t.Fatalf("expected certificate not in the CA bundle to be rejected")
+	}
+	if err := testGet(&Settings{CABundle: testOtherBundle(t), Insecure: true}, server); err != nil {
+		t.Fatalf("expected certificate to be accepted when insecure, got %s", err)
+	}
+}
+
+// testProxy starts an HTTP proxy that tunnels CONNECT requests, and returns
+// its URL and the number of tunnels it opened.
+func testProxy(t *testing.T) (*url.URL, *int32) {
+	var tunnels int32
+	proxyServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodConnect {
+			http.Error(w, "only CONNECT is supported", http.StatusMethodNotAllowed)
+			return
+		}
+		dst, err := net.Dial("tcp", r.Host)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		src, _, err := w.(http.Hijacker).Hijack()
+		if err != nil {
+			_ = dst.Close()
+			return
+		}
+		atomic.AddInt32(&tunnels, 1)
+		_, _ = io.WriteString(src, "HTTP/1.1 200 Connection established\r\n\r\n")
+		go func() {
+			_, _ = io.Copy(dst, src)
+			_ = dst.Close()
+		}()
+		_, _ = io.Copy(src, dst)
+		_ = src.Close()
+	}))
+	t.Cleanup(proxyServer.Close)
+
+	p, _ := url.Parse(proxyServer.URL)
+	return p, &tunnels
+}
+
+func TestConfigureProxy(t *testing.T) {
+	server, bundle := testServer(t)
+	p, tunnels := testProxy(t)
+
+	s := &Settings{
+		CABundle: bundle,
+		Proxy:    p,
+	}
+	if err := testGet(s, server); err != nil {
+		t.Fatalf("error connecting through proxy: %s", err)
+	}
+	if atomic.LoadInt32(tunnels) != 1 {
+		t.Fatalf("expected the request to be tunneled through the proxy")
+	}
+}
+
+func TestConfigureSOAPKnownThumbprint(t *testing.T) {
+	server, _ := testServer(t)
+	p, _ := testProxy(t)
+	u, _ := url.Parse(server.URL)
+
+	for name, s := range map[string]*Settings{
+		"ca bundle": {CABundle: testOtherBundle(t)},
+		"proxy":     {CABundle: testOtherBundle(t), Proxy: p},
+	} {
+		t.Run(name, func(t *testing.T) {
+			c := soap.NewClient(u, false)
+			if err := s.ConfigureSOAP(c); err != nil {
+				t.Fatal(err)
+			}
+			client := &http.Client{Transport: c.DefaultTransport()}
+			if res, err := client.Get(server.URL); err == nil {
+				_ = res.Body.Close()
+				t.Fatalf("expected certificate of unknown host to be rejected")
+			}
+
+			c.SetThumbprint(u.Host, soap.ThumbprintSHA1(server.Certificate()))
+			res, err := client.Get(server.URL)
+			if err != nil {
+				t.Fatalf("expected host with known thumbprint to be trusted, got %s", err)
+			}
+			_ = res.Body.Close()
+		})
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"log"
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
@@ -56,11 +57,12 @@ func IsVApp(client *govmomi.Client, rp string) bool {
 
 // Properties returns the VirtualApp managed object from its higher-level
 // object.
-func Properties(obj *object.VirtualApp) (*mo.VirtualApp, error) {
+// If paths is not empty, only those properties are retrieved.
+func Properties(obj *object.VirtualApp, paths ...string) (*mo.VirtualApp, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.VirtualApp
-	if err := obj.Properties(ctx, obj.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, obj.Client(), obj.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -87,15 +89,14 @@ func Update(vc *object.VirtualApp, spec types.VAppConfigSpec) error {
 }
 
 // Delete destroys a VirtualApp.
-func Delete(vc *object.VirtualApp) error {
+func Delete(ctx context.Context, vc *object.VirtualApp) error {
 	log.Printf("[DEBUG] Deleting vApp container %q", vc.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
 	task, err := vc.Destroy(ctx)
 	if err != nil {
 		return err
 	}
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
 
 // HasChildren checks to see if a vApp container has any child items (virtual
@@ -0,0 +1,231 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// The product names of vCenter Server and ESXi, as reported in AboutInfo.
+const (
+	ProductVCenter = "VMware vCenter Server"
+	ProductESXi    = "VMware ESXi"
+)
+
+// The names of the capabilities in the registry.
+const (
+	// CapabilityRestAPI is the vSphere Automation (CIS) REST API, used for
+	// tags and content libraries.
+	CapabilityRestAPI = "rest_api"
+
+	// CapabilityEFISecureBoot is EFI secure boot for virtual machines.
+	CapabilityEFISecureBoot = "efi_secure_boot"
+
+	// CapabilityVBS is virtualization-based security for virtual machines.
+	CapabilityVBS = "virtualization_based_security"
+
+	// CapabilityVVTD is I/O MMU virtualization (Intel VT-d) for virtual
+	// machines.
+	CapabilityVVTD = "vvtd"
+
+	// CapabilityMultiWriterDiskSharing is multi-writer sharing of virtual
+	// disks.
+	CapabilityMultiWriterDiskSharing = "multi_writer_disk_sharing"
+
+	// CapabilityVMUUIDSearchIndex is looking up virtual machines by UUID
+	// through the SearchIndex.
+	CapabilityVMUUIDSearchIndex = "vm_uuid_search_index"
+
+	// CapabilityVsanESA is the vSAN Express Storage Architecture.
+	CapabilityVsanESA = "vsan_esa"
+)
+
+// Capability is a feature of vSphere that is only available on some products
+// or versions.
+type Capability struct {
+	// The name of the capability, used to look it up in the registry.
+	Name string
+
+	// A short description of the capability.
+	Description string
+
+	// The minimum version of vCenter Server that has the capability. The zero
+	// value means any version.
+	MinVCenterVersion VSphereVersion
+
+	// The minimum version of ESXi that has the capability, when connected to it
+	// directly. The zero value means any version. Ignored if VCenterOnly is
+	// true.
+	MinESXiVersion VSphereVersion
+
+	// True if the capability is only available when connected to vCenter
+	// Server.
+	VCenterOnly bool
+}
+
+// capabilities is the registry of capabilities, keyed by name.
+var capabilities = map[string]Capability{}
+
+// registerCapability adds c to the registry.
+func registerCapability(c Capability) {
+	if _, ok := capabilities[c.Name]; ok {
+		panic(fmt.Sprintf("capability %q is already registered", c.Name))
+	}
+	if c.MinVCenterVersion != (VSphereVersion{}) {
+		c.MinVCenterVersion.Product = ProductVCenter
+	}
+	if c.MinESXiVersion != (VSphereVersion{}) {
+		c.MinESXiVersion.Product = ProductESXi
+	}
+	capabilities[c.Name] = c
+}
+
+func init() {
+	registerCapability(Capability{
+		Name:              CapabilityRestAPI,
+		Description:       "the vSphere Automation REST API, used for tags and content libraries",
+		MinVCenterVersion: VSphereVersion{Major: 6, Build: 2559268},
+		VCenterOnly:       true,
+	})
+	registerCapability(Capability{
+		Name:              CapabilityEFISecureBoot,
+		Description:       "EFI secure boot",
+		MinVCenterVersion: VSphereVersion{Major: 6, Minor: 5},
+		MinESXiVersion:    VSphereVersion{Major: 6, Minor: 5},
+	})
+	registerCapability(Capability{
+		Name:              CapabilityVBS,
+		Description:       "virtualization-based security",
+		MinVCenterVersion: VSphereVersion{Major: 6, Minor: 7},
+		MinESXiVersion:    VSphereVersion{Major: 6, Minor: 7},
+	})
+	registerCapability(Capability{
+		Name:              CapabilityVVTD,
+		Description:       "I/O MMU virtualization",
+		MinVCenterVersion: VSphereVersion{Major: 6, Minor: 7},
+		MinESXiVersion:    VSphereVersion{Major: 6, Minor: 7},
+	})
+	registerCapability(Capability{
+		Name:              CapabilityMultiWriterDiskSharing,
+		Description:       "multi-writer disk sharing",
+		MinVCenterVersion: VSphereVersion{Major: 6},
+		MinESXiVersion:    VSphereVersion{Major: 6},
+	})
+	registerCapability(Capability{
+		Name:              CapabilityVMUUIDSearchIndex,
+		Description:       "virtual machine lookup by UUID through the search index",
+		MinVCenterVersion: VSphereVersion{Major: 6, Minor: 5},
+		MinESXiVersion:    VSphereVersion{Major: 6, Minor: 5},
+	})
+	registerCapability(Capability{
+		Name:              CapabilityVsanESA,
+		Description:       "vSAN Express Storage Architecture",
+		MinVCenterVersion: VSphereVersion{Major: 8},
+		VCenterOnly:       true,
+	})
+}
+
+// Capabilities returns the registered capabilities, sorted by name.
+func Capabilities() []Capability {
+	l := make([]Capability, 0, len(capabilities))
+	for _, c := range capabilities {
+		l = append(l, c)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l
+}
+
+// LookupCapability returns the capability registered as name. It panics if
+// there is none, as capabilities are only looked up by the names declared in
+// this package.
+func LookupCapability(name string) Capability {
+	c, ok := capabilities[name]
+	if !ok {
+		panic(fmt.Sprintf("capability %q is not registered", name))
+	}
+	return c
+}
+
+// Requirement describes the products and versions that have the capability.
+func (c Capability) Requirement() string {
+	vc := "vCenter Server"
+	if c.MinVCenterVersion != (VSphereVersion{}) {
+		vc = fmt.Sprintf("vCenter Server %s or later", shortVersion(c.MinVCenterVersion))
+	}
+	if c.VCenterOnly {
+		return vc
+	}
+	esxi := "ESXi"
+	if c.MinESXiVersion != (VSphereVersion{}) {
+		esxi = fmt.Sprintf("ESXi %s or later", shortVersion(c.MinESXiVersion))
+	}
+	return vc + ", or " + esxi
+}
+
+// shortVersion returns the version number of v, without its product name.
+// The build number is only included if it is part of the requirement.
+func shortVersion(v VSphereVersion) string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Build != 0 {
+		s += fmt.Sprintf(" build %d", v.Build)
+	}
+	return s
+}
+
+// Check returns a CapabilityError if the product and version in about do not
+// have the capability.
+func (c Capability) Check(about types.AboutInfo) error {
+	v := parseVersionFromAboutInfo(about)
+	var min VSphereVersion
+	switch {
+	case about.ApiType == "VirtualCenter":
+		min = c.MinVCenterVersion
+	case c.VCenterOnly:
+		return &CapabilityError{Capability: c, Version: v}
+	default:
+		min = c.MinESXiVersion
+	}
+	if min == (VSphereVersion{}) {
+		return nil
+	}
+	min.Product = v.Product
+	if v.Older(min) {
+		return &CapabilityError{Capability: c, Version: v}
+	}
+	return nil
+}
+
+// CapabilityError is returned when the connected vSphere endpoint does not
+// have a capability.
+type CapabilityError struct {
+	Capability Capability
+	Version    VSphereVersion
+}
+
+// Error implements error for CapabilityError.
+func (e *CapabilityError) Error() string {
+	return fmt.Sprintf(
+		"%s (capability %q) is not supported by %s, it requires %s",
+		e.Capability.Description,
+		e.Capability.Name,
+		e.Version,
+		e.Capability.Requirement(),
+	)
+}
+
+// CheckCapability returns a CapabilityError if the endpoint client is
+// connected to does not have the named capability.
+func CheckCapability(client *govmomi.Client, name string) error {
+	return LookupCapability(name).Check(client.ServiceContent.About)
+}
+
+// HasCapability returns true if the endpoint client is connected to has the
+// named capability.
+func HasCapability(client *govmomi.Client, name string) bool {
+	return CheckCapability(client, name) == nil
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"errors"
+	"sort"
+	"testing"
+
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func testAboutInfo(vcenter bool, version, build string) types.AboutInfo {
+	if vcenter {
+		return types.AboutInfo{Name: ProductVCenter, ApiType: "VirtualCenter", Version: version, Build: build}
+	}
+	return types.AboutInfo{Name: ProductESXi, ApiType: "HostAgent", Version: version, Build: build}
+}
+
+func TestCapabilityCheck(t *testing.T) {
+	cases := []struct {
+		name       string
+		capability string
+		about      types.AboutInfo
+		expected   bool
+	}{
+		{
+			name:       "rest api on vcenter",
+			capability: CapabilityRestAPI,
+			about:      testAboutInfo(true, "6.5.0", "5973321"),
+			expected:   true,
+		},
+		{
+			name:       "rest api on an older build of vcenter 6.0",
+			capability: CapabilityRestAPI,
+			about:      testAboutInfo(true, "6.0.0", "2559267"),
+		},
+		{
+			name:       "rest api on esxi",
+			capability: CapabilityRestAPI,
+			about:      testAboutInfo(false, "8.0.2", "22380479"),
+		},
+		{
+			name:       "efi secure boot on esxi 6.5",
+			capability: CapabilityEFISecureBoot,
+			about:      testAboutInfo(false, "6.5.0", "4564106"),
+			expected:   true,
+		},
+		{
+			name:       "virtualization-based security on vcenter 6.5",
+			capability: CapabilityVBS,
+			about:      testAboutInfo(true, "6.5.0", "5973321"),
+		},
+		{
+			name:       "vsan esa on vcenter 8",
+			capability: CapabilityVsanESA,
+			about:      testAboutInfo(true, "8.0.0", "20519528"),
+			expected:   true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := LookupCapability(tc.capability).Check(tc.about)
+			if tc.expected && err != nil {
+				t.Fatalf("expected capability to be supported, got: %s", err)
+			}
+			if !tc.expected {
+				var ce *CapabilityError
+				if !errors.As(err, &ce) {
+					t.Fatalf("expected a CapabilityError, got %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestCapabilityError(t *testing.T) {
+	err := LookupCapability(CapabilityVBS).Check(testAboutInfo(false, "6.5.0", "4564106"))
+	expected := `virtualization-based security (capability "virtualization_based_security") is not supported by VMware ESXi 6.5.0 build-4564106, it requires vCenter Server 6.7.0 or later, or ESXi 6.7.0 or later`
+	if err == nil || err.Error() != expected {
+		t.Fatalf("expected %q, got %v", expected, err)
+	}
+
+	expected = "vCenter Server 8.0.0 or later"
+	if actual := LookupCapability(CapabilityVsanESA).Requirement(); actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
+
+func TestCapabilities(t *testing.T) {
+	l := Capabilities()
+	if len(l) != len(capabilities) {
+		t.Fatalf("expected %d capabilities, got %d", len(capabilities), len(l))
+	}
+	if !sort.SliceIsSorted(l, func(i, j int) bool { return l[i].Name < l[j].Name }) {
+		t.Fatalf("expected capabilities to be sorted by name")
+	}
+	for _, c := range l {
+		if c.Description == "" {
+			t.Fatalf("capability %q has no description", c.Name)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// TaskError is returned by WaitForTask when a task does not complete
+// successfully. It carries the ID of the task alongside the original error,
+// which can still be reached with errors.As.
+type TaskError struct {
+	// TaskID is the managed object ID of the task, such as "task-123".
+	TaskID string
+	// Err is the error returned while waiting on the task.
+	Err error
+}
+
+// Error implements error for TaskError. The message of the original error is
+// returned unchanged.
+func (e *TaskError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the original error.
+func (e *TaskError) Unwrap() error {
+	return e.Err
+}
+
+// Fault describes a fault returned by vSphere, either directly by an API call
+// or as the result of a task.
+type Fault struct {
+	// Class is the type name of the fault, such as InvalidDeviceSpec.
+	Class string
+	// Message is the localized message of the fault.
+	Message string
+	// Reasons holds the messages vSphere attaches to the fault to explain it,
+	// such as the reason a device spec was rejected.
+	Reasons []string
+	// Property is the name of the property the fault refers to, if any.
+	Property string
+	// DeviceIndex is the index of the device change that caused an
+	// InvalidDeviceSpec fault in the device changes of the config spec. It is
+	// -1 when the fault does not refer to a device change.
+	DeviceIndex int
+	// TaskID is the managed object ID of the failed task, if the fault is the
+	// result of a task waited on with WaitForTask.
+	TaskID string
+}
+
+// FaultFromError extracts the vSphere fault from err. Check the returned
+// boolean value to see if err carries a fault.
+func FaultFromError(err error) (*Fault, bool) {
+	f := &Fault{DeviceIndex: -1}
+	var mf types.BaseMethodFault
+	if tf, ok := taskFault(err); ok {
+		mf = tf
+		f.Message = taskErrorMessage(err)
+	} else if sf, ok := soapFault(err); ok {
+		mf = baseMethodFault(sf.VimFault())
+		f.Message = sf.String
+	}
+	if mf == nil {
+		return nil, false
+	}
+
+	f.Class = reflect.Indirect(reflect.ValueOf(mf)).Type().Name()
+	for _, m := range mf.GetMethodFault().FaultMessage {
+		if m.Message != "" {
+			f.Reasons = append(f.Reasons, m.Message)
+		}
+	}
+	switch t := mf.(type) {
+	case types.BaseInvalidDeviceSpec:
+		f.DeviceIndex = int(t.GetInvalidDeviceSpec().DeviceIndex)
+		f.Property = t.GetInvalidDeviceSpec().Property
+	case types.BaseInvalidVmConfig:
+		f.Property = t.GetInvalidVmConfig().Property
+	case types.BaseInvalidArgument:
+		f.Property = t.GetInvalidArgument().InvalidProperty
+	}
+
+	var te *TaskError
+	if errors.As(err, &te) {
+		f.TaskID = te.TaskID
+	}
+	return f, true
+}
+
+// Detail renders the fault as a multi-line description, suitable for the
+// detail of a diagnostic.
+func (f *Fault) Detail() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "vSphere fault: %s", f.Class)
+	if f.Property != "" {
+		fmt.Fprintf(&b, "\nProperty: %s", f.Property)
+	}
+	if f.TaskID != "" {
+		fmt.Fprintf(&b, "\nTask: %s", f.TaskID)
+	}
+	for _, r := range f.Reasons {
+		if r != f.Message {
+			fmt.Fprintf(&b, "\nReason: %s", r)
+		}
+	}
+	return b.String()
+}
+
+// taskErrorMessage returns the localized message of the task error in err.
+func taskErrorMessage(err error) string {
+	var te task.Error
+	if errors.As(err, &te) {
+		return te.LocalizedMessage
+	}
+	return ""
+}
+
+// baseMethodFault converts a fault decoded from a SOAP fault, which is held as
+// a value, into a BaseMethodFault. It returns nil if the value is not a
+// method fault.
+func baseMethodFault(v types.AnyType) types.BaseMethodFault {
+	if v == nil {
+		return nil
+	}
+	if mf, ok := v.(types.BaseMethodFault); ok {
+		return mf
+	}
+	p := reflect.New(reflect.TypeOf(v))
+	p.Elem().Set(reflect.ValueOf(v))
+	mf, _ := p.Interface().(types.BaseMethodFault)
+	return mf
+}
@@ -0,0 +1,104 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+func TestFaultFromError(t *testing.T) {
+	sf := &soap.Fault{String: "The object has already been deleted or has not been completely created"}
+	sf.Detail.Fault = types.ManagedObjectNotFound{
+		Obj: types.ManagedObjectReference{Type: "VirtualMachine", Value: "vm-42"},
+	}
+	soapErr := soap.WrapSoapFault(sf)
+
+	cases := []struct {
+		name     string
+		err      error
+		expected *Fault
+	}{
+		{
+			name: "task fault with device index",
+			err: fmt.Errorf("error reconfiguring virtual machine: %w", &TaskError{
+				TaskID: "task-123",
+				Err: task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{
+					LocalizedMessage: "Invalid configuration for device '0'.",
+					Fault: &types.InvalidDeviceSpec{
+						InvalidVmConfig: types.InvalidVmConfig{
+							VmConfigFault: types.VmConfigFault{
+								VimFault: types.VimFault{
+									MethodFault: types.MethodFault{
+										FaultMessage: []types.LocalizableMessage{
+											{Key: "msg.disk.noBackEnd", Message: "Cannot open the disk."},
+										},
+									},
+								},
+							},
+							Property: "virtualDeviceSpec.device.backing.fileName",
+						},
+						DeviceIndex: 2,
+					},
+				}},
+			}),
+			expected: &Fault{
+				Class:       "InvalidDeviceSpec",
+				Message:     "Invalid configuration for device '0'.",
+				Reasons:     []string{"Cannot open the disk."},
+				Property:    "virtualDeviceSpec.device.backing.fileName",
+				DeviceIndex: 2,
+				TaskID:      "task-123",
+			},
+		},
+		{
+			name: "wrapped SOAP fault",
+			err:  fmt.Errorf("error fetching properties: %w", soapErr),
+			expected: &Fault{
+				Class:       "ManagedObjectNotFound",
+				Message:     "The object has already been deleted or has not been completely created",
+				DeviceIndex: -1,
+			},
+		},
+		{
+			name: "not a fault",
+			err:  errors.New("connection refused"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual, ok := FaultFromError(tc.err)
+			if ok != (tc.expected != nil) {
+				t.Fatalf("expected fault %t, got %t", tc.expected != nil, ok)
+			}
+			if !reflect.DeepEqual(tc.expected, actual) {
+				t.Fatalf("expected %#v, got %#v", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestFaultDetail(t *testing.T) {
+	f := &Fault{
+		Class:    "InvalidDeviceSpec",
+		Message:  "Invalid configuration for device '0'.",
+		Reasons:  []string{"Invalid configuration for device '0'.", "Cannot open the disk."},
+		Property: "virtualDeviceSpec.device.backing.fileName",
+		TaskID:   "task-123",
+	}
+	expected := "vSphere fault: InvalidDeviceSpec\n" +
+		"Property: virtualDeviceSpec.device.backing.fileName\n" +
+		"Task: task-123\n" +
+		"Reason: Cannot open the disk."
+	if actual := f.Detail(); actual != expected {
+		t.Fatalf("expected %q, got %q", expected, actual)
+	}
+}
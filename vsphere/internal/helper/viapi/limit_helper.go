@@ -0,0 +1,255 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// TaskClass is a class of long-running operation that has a concurrency limit
+// of its own.
+type TaskClass string
+
+const (
+	// TaskClassClone is the class of virtual machine clones, including
+	// deployments of VM templates from a content library.
+	TaskClassClone = TaskClass("clone")
+
+	// TaskClassRelocate is the class of virtual machine migrations.
+	TaskClassRelocate = TaskClass("relocate")
+
+	// TaskClassOvfDeploy is the class of OVF and OVA deployments, including
+	// deployments of OVF templates from a content library.
+	TaskClassOvfDeploy = TaskClass("OVF deploy")
+)
+
+// longPollMethods are the vSphere API methods that block until something
+// changes on the server. They are not counted against the call limit, as
+// they would otherwise hold on to slots for as long as the tasks they wait
+// for.
+var longPollMethods = []string{
+	"WaitForUpdates",
+	"WaitForUpdatesEx",
+	"CheckForUpdates",
+}
+
+// ConcurrencyLimits are the maximum numbers of concurrent calls and tasks. A
+// limit of 0 or less is unlimited.
+type ConcurrencyLimits struct {
+	// The number of vSphere API (SOAP) calls in flight.
+	APICalls int
+
+	// The number of REST API calls in flight.
+	RestCalls int
+
+	// The number of running operations of each task class.
+	Clones     int
+	Relocates  int
+	OvfDeploys int
+}
+
+// semaphore limits the number of concurrent holders of its slots. A nil
+// semaphore is unlimited.
+type semaphore struct {
+	name   string
+	slots  chan struct{}
+	queued int32
+}
+
+// newSemaphore returns a semaphore with n slots, or nil if n is 0 or less.
+func newSemaphore(name string, n int) *semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return &semaphore{
+		name:  name,
+		slots: make(chan struct{}, n),
+	}
+}
+
+// acquire waits for a free slot, and returns the function that releases it.
+// what describes the caller in the debug log written when it has to wait.
+func (s *semaphore) acquire(ctx context.Context, what string) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+	select {
+	case s.slots <- struct{}{}:
+		return s.release, nil
+	default:
+	}
+
+	queued := atomic.AddInt32(&s.queued, 1)
+	log.Printf("[DEBUG] %s limit of %d reached, %s is queued (queue depth: %d)", s.name, cap(s.slots), what, queued)
+	start := time.Now()
+	defer atomic.AddInt32(&s.queued, -1)
+	select {
+	case s.slots <- struct{}{}:
+		log.Printf("[DEBUG] %s: %s left the queue after %s (queue depth: %d)", s.name, what, time.Since(start), atomic.LoadInt32(&s.queued)-1)
+		return s.release, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire.
+func (s *semaphore) release() {
+	<-s.slots
+}
+
+// Limiter limits the number of concurrent API calls and tasks made by the
+// provider. The zero value and a nil Limiter are unlimited.
+type Limiter struct {
+	api   *semaphore
+	rest  *semaphore
+	tasks map[TaskClass]*semaphore
+}
+
+// NewLimiter returns a Limiter for l.
+func NewLimiter(l ConcurrencyLimits) *Limiter {
+	return &Limiter{
+		api:  newSemaphore("vSphere API call", l.APICalls),
+		rest: newSemaphore("REST API call", l.RestCalls),
+		tasks: map[TaskClass]*semaphore{
+			TaskClassClone:     newSemaphore(string(TaskClassClone), l.Clones),
+			TaskClassRelocate:  newSemaphore(string(TaskClassRelocate), l.Relocates),
+			TaskClassOvfDeploy: newSemaphore(string(TaskClassOvfDeploy), l.OvfDeploys),
+		},
+	}
+}
+
+// AcquireTask waits until another task of class can be run, and returns the
+// function that must be called when the task is complete.
+func (l *Limiter) AcquireTask(ctx context.Context, class TaskClass, what string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	return l.tasks[class].acquire(ctx, what)
+}
+
+// limitRoundTripper limits the number of SOAP calls in flight.
+type limitRoundTripper struct {
+	soap.RoundTripper
+	limiter *Limiter
+}
+
+// NewLimitRoundTripper returns a soap.RoundTripper that limits the calls made
+// through rt according to l. Long polls for property updates are not
+// limited.
+//
+// When combined with NewRetryRoundTripper, install it underneath, so that
+// calls do not hold on to a slot while waiting to be retried:
+//
+//	c.RoundTripper = NewRetryRoundTripper(NewLimitRoundTripper(c.RoundTripper, l), p)
+func NewLimitRoundTripper(rt soap.RoundTripper, l *Limiter) soap.RoundTripper {
+	return &limitRoundTripper{
+		RoundTripper: rt,
+		limiter:      l,
+	}
+}
+
+// RoundTrip implements soap.RoundTripper for limitRoundTripper.
+func (r *limitRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	name := strings.TrimSuffix(reflect.Indirect(reflect.ValueOf(req)).Type().Name(), "Body")
+	if r.limiter == nil || isLongPollMethod(name) {
+		return r.RoundTripper.RoundTrip(ctx, req, res)
+	}
+	release, err := r.limiter.api.acquire(ctx, name)
+	if err != nil {
+		return err
+	}
+	defer release()
+	return r.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+// isLongPollMethod returns true if name is the name of a method in
+// longPollMethods.
+func isLongPollMethod(name string) bool {
+	for _, m := range longPollMethods {
+		if name == m {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the soap.RoundTripper that limitRoundTripper sends calls
+// through.
+func (r *limitRoundTripper) Unwrap() soap.RoundTripper {
+	return r.RoundTripper
+}
+
+// wrappingRoundTripper is implemented by the soap.RoundTrippers that wrap
+// another one, such as the retry, limit, property cache and trace round
+// trippers.
+type wrappingRoundTripper interface {
+	Unwrap() soap.RoundTripper
+}
+
+// LimiterFromClient returns the Limiter installed on c by
+// NewLimitRoundTripper, or nil if there is none. The round trippers that are
+// installed on top of it must implement Unwrap() soap.RoundTripper for it to
+// be found.
+func LimiterFromClient(c *vim25.Client) *Limiter {
+	rt := c.RoundTripper
+	for {
+		switch r := rt.(type) {
+		case *limitRoundTripper:
+			return r.limiter
+		case wrappingRoundTripper:
+			rt = r.Unwrap()
+		default:
+			return nil
+		}
+	}
+}
+
+// AcquireTask waits until another task of class can be run, according to the
+// Limiter installed on c, and returns the function that must be called when
+// the task is complete. what describes the task in debug logs. An error is
+// returned if ctx is done before the task can run.
+func AcquireTask(ctx context.Context, c *vim25.Client, class TaskClass, what string) (func(), error) {
+	return LimiterFromClient(c).AcquireTask(ctx, class, what)
+}
+
+// restLimitTransport limits the number of REST API requests in flight.
+type restLimitTransport struct {
+	http.RoundTripper
+	limiter *Limiter
+}
+
+// NewRestLimitTransport returns an http.RoundTripper for a rest.Client that
+// limits the requests made through rt according to l. A request holds its
+// slot until its response headers are received.
+//
+// When combined with NewRestRetryTransport, install it underneath, so that
+// requests do not hold on to a slot while waiting to be retried.
+func NewRestLimitTransport(rt http.RoundTripper, l *Limiter) http.RoundTripper {
+	return &restLimitTransport{
+		RoundTripper: rt,
+		limiter:      l,
+	}
+}
+
+// RoundTrip implements http.RoundTripper for restLimitTransport.
+func (t *restLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.limiter == nil {
+		return t.RoundTripper.RoundTrip(req)
+	}
+	release, err := t.limiter.rest.acquire(req.Context(), req.Method+" "+req.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	return t.RoundTripper.RoundTrip(req)
+}
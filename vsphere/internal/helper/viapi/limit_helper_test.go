@@ -0,0 +1,118 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// testSlowRoundTripper records the largest number of calls made through it
+// at the same time.
+type testSlowRoundTripper struct {
+	inFlight int32
+	max      int32
+}
+
+func (rt *testSlowRoundTripper) RoundTrip(_ context.Context, _, _ soap.HasFault) error {
+	n := atomic.AddInt32(&rt.inFlight, 1)
+	defer atomic.AddInt32(&rt.inFlight, -1)
+	for {
+		m := atomic.LoadInt32(&rt.max)
+		if n <= m || atomic.CompareAndSwapInt32(&rt.max, m, n) {
+			break
+		}
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func testConcurrentCalls(rt soap.RoundTripper, n int, req func() soap.HasFault) {
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_ = rt.RoundTrip(context.Background(), req(), req())
+		}()
+	}
+	wg.Wait()
+}
+
+func TestLimitRoundTripper(t *testing.T) {
+	base := &testSlowRoundTripper{}
+	rt := NewLimitRoundTripper(base, NewLimiter(ConcurrencyLimits{APICalls: 2}))
+	testConcurrentCalls(rt, 8, func() soap.HasFault { return &methods.RetrievePropertiesExBody{} })
+	if base.max != 2 {
+		t.Fatalf("expected at most 2 calls in flight, got %d", base.max)
+	}
+
+	base = &testSlowRoundTripper{}
+	rt = NewLimitRoundTripper(base, NewLimiter(ConcurrencyLimits{APICalls: 1}))
+	testConcurrentCalls(rt, 4, func() soap.HasFault { return &methods.WaitForUpdatesExBody{} })
+	if base.max < 2 {
+		t.Fatalf("expected long polls to not be limited, got %d in flight", base.max)
+	}
+}
+
+func TestAcquireTask(t *testing.T) {
+	l := NewLimiter(ConcurrencyLimits{Clones: 1})
+	c := &vim25.Client{RoundTripper: NewRetryRoundTripper(NewLimitRoundTripper(&testSlowRoundTripper{}, l), testRetryPolicy)}
+	if LimiterFromClient(c) != l {
+		t.Fatalf("expected the limiter to be found underneath the retry round tripper")
+	}
+
+	ctx := context.Background()
+	release, err := AcquireTask(ctx, c, TaskClassClone, "first clone")
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	acquired := make(chan struct{})
+	go func() {
+		release, _ := AcquireTask(ctx, c, TaskClassClone, "second clone")
+		defer release()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatalf("expected the second clone to wait for the first")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Other task classes are not limited.
+	relocate, err := AcquireTask(ctx, c, TaskClassRelocate, "migration")
+	if err != nil {
+		t.Fatalf("expected other task classes to not be limited, got %s", err)
+	}
+	relocate()
+
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatalf("expected the second clone to run after the first was released")
+	}
+
+	release, _ = AcquireTask(ctx, c, TaskClassClone, "third clone")
+	defer release()
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := AcquireTask(cctx, c, TaskClassClone, "cancelled clone"); err == nil {
+		t.Fatalf("expected a cancelled wait to return an error")
+	}
+
+	// A client without a limiter is not limited.
+	unlimited, err := AcquireTask(ctx, &vim25.Client{}, TaskClassClone, "unlimited clone")
+	if err != nil {
+		t.Fatalf("expected a client without a limiter to not be limited, got %s", err)
+	}
+	unlimited()
+}
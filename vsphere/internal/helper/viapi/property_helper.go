@@ -0,0 +1,246 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mitchellh/copystructure"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// PropertyCache shares the properties retrieved with RetrieveProperties
+// between the resources of a Terraform run, so that several resources reading
+// the same properties of an object only retrieve them once.
+//
+// The cache is cleared whenever the client makes a call that may change
+// something, or that waits for something to change, such as a task. This
+// keeps reads made after a change consistent with it, at the cost of making
+// the cache mostly useful for runs that only read, such as a refresh or a
+// plan. Changes made outside of the provider during the run are not seen.
+type PropertyCache struct {
+	mu         sync.Mutex
+	generation uint64
+	objects    map[types.ManagedObjectReference]*cachedObject
+}
+
+// cachedObject holds the cached properties of a single object.
+type cachedObject struct {
+	// properties maps the property paths that have been retrieved to their
+	// values. A nil value marks a property that is not set.
+	properties map[string]*types.DynamicProperty
+
+	// retrieving is closed when a running retrieval for the object completes.
+	// It is nil when there is none.
+	retrieving chan struct{}
+}
+
+// propertyCaches holds the cache installed on each client with
+// SetPropertyCache.
+var propertyCaches sync.Map
+
+// NewPropertyCache returns an empty PropertyCache.
+func NewPropertyCache() *PropertyCache {
+	return &PropertyCache{
+		objects: make(map[types.ManagedObjectReference]*cachedObject),
+	}
+}
+
+// SetPropertyCache installs cache on c, so that RetrieveProperties uses it for
+// calls made with c. The round tripper returned by cache.RoundTripper must
+// also be installed on c, so that the cache is cleared when c changes
+// something.
+func SetPropertyCache(c *vim25.Client, cache *PropertyCache) {
+	propertyCaches.Store(c, cache)
+}
+
+// propertyCacheFor returns the cache installed on c, or nil if there is none.
+func propertyCacheFor(c *vim25.Client) *PropertyCache {
+	if v, ok := propertyCaches.Load(c); ok {
+		return v.(*PropertyCache)
+	}
+	return nil
+}
+
+// propertyCacheRoundTripper clears a PropertyCache around calls that may
+// change something.
+type propertyCacheRoundTripper struct {
+	soap.RoundTripper
+	cache *PropertyCache
+}
+
+// RoundTripper returns a soap.RoundTripper that sends calls through rt, and
+// clears the cache before and after each call that is not read-only. Calls
+// that wait for updates, such as the ones made while waiting for a task, are
+// not read-only, as the object they wait on has changed once they return.
+//
+// Install it on a vim25.Client with:
+//
+//	c.RoundTripper = cache.RoundTripper(c.RoundTripper)
+func (pc *PropertyCache) RoundTripper(rt soap.RoundTripper) soap.RoundTripper {
+	return &propertyCacheRoundTripper{
+		RoundTripper: rt,
+		cache:        pc,
+	}
+}
+
+// Unwrap returns the soap.RoundTripper that propertyCacheRoundTripper sends
+// calls through.
+func (r *propertyCacheRoundTripper) Unwrap() soap.RoundTripper {
+	return r.RoundTripper
+}
+
+// RoundTrip implements soap.RoundTripper for propertyCacheRoundTripper.
+func (r *propertyCacheRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	if isReadOnlyMethod(req) {
+		return r.RoundTripper.RoundTrip(ctx, req, res)
+	}
+	r.cache.clear()
+	defer r.cache.clear()
+	return r.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+// clear drops all cached properties. Retrievals that are running while the
+// cache is cleared do not store their results.
+func (pc *PropertyCache) clear() {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	pc.generation++
+	for ref, o := range pc.objects {
+		if o.retrieving == nil {
+			delete(pc.objects, ref)
+			continue
+		}
+		o.properties = make(map[string]*types.DynamicProperty)
+	}
+}
+
+// RetrieveProperties retrieves the properties in paths of the object ref into
+// dst, which must be a pointer to the mo type of ref, such as
+// *mo.VirtualMachine. Only the properties in paths are set in dst. If paths is
+// empty, all properties of the object are retrieved.
+//
+// When a PropertyCache is installed on c, the properties are served from it,
+// and only the properties that are not cached yet are retrieved. Properties
+// are cached by path, so a property must be asked for with the same path to
+// be served from the cache.
+func RetrieveProperties(ctx context.Context, c *vim25.Client, ref types.ManagedObjectReference, paths []string, dst interface{}) error {
+	cache := propertyCacheFor(c)
+	if cache == nil || len(paths) == 0 {
+		return property.DefaultCollector(c).RetrieveOne(ctx, ref, paths, dst)
+	}
+	return cache.retrieve(ctx, c, ref, paths, dst)
+}
+
+// retrieve implements RetrieveProperties for a client with a cache.
+func (pc *PropertyCache) retrieve(ctx context.Context, c *vim25.Client, ref types.ManagedObjectReference, paths []string, dst interface{}) error {
+	var fetched bool
+	for {
+		pc.mu.Lock()
+		o, ok := pc.objects[ref]
+		if !ok {
+			o = &cachedObject{properties: make(map[string]*types.DynamicProperty)}
+			pc.objects[ref] = o
+		}
+		if o.retrieving != nil {
+			// Wait for the running retrieval, which may fetch the properties
+			// that are needed here, then check the cache again.
+			retrieving := o.retrieving
+			pc.mu.Unlock()
+			select {
+			case <-retrieving:
+				continue
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+
+		var missing []string
+		content := types.ObjectContent{Obj: ref}
+		for _, p := range paths {
+			v, ok := o.properties[p]
+			switch {
+			case !ok:
+				missing = append(missing, p)
+			case v != nil:
+				content.PropSet = append(content.PropSet, *v)
+			}
+		}
+		if len(missing) == 0 {
+			pc.mu.Unlock()
+			if !fetched {
+				log.Printf("[DEBUG] Using cached properties of %s: %s", ref, strings.Join(paths, ", "))
+			}
+			return loadCachedContent(content, dst)
+		}
+		o.retrieving = make(chan struct{})
+		generation := pc.generation
+		pc.mu.Unlock()
+
+		fetched = true
+		stored, err := pc.fetch(ctx, c, ref, missing, generation, o)
+		if err != nil {
+			return err
+		}
+		if !stored {
+			// The properties could not be cached, either because the cache was
+			// cleared while they were retrieved or because some of them are
+			// missing. Retrieve them all again without the cache, so that the
+			// result is consistent and any fault is returned.
+			return property.DefaultCollector(c).RetrieveOne(ctx, ref, paths, dst)
+		}
+	}
+}
+
+// fetch retrieves the properties in paths of ref and stores them in o. It
+// returns false if the properties were not stored, which happens when the
+// cache was cleared since generation or when some of the properties are
+// missing.
+func (pc *PropertyCache) fetch(ctx context.Context, c *vim25.Client, ref types.ManagedObjectReference, paths []string, generation uint64, o *cachedObject) (bool, error) {
+	var content []types.ObjectContent
+	err := property.DefaultCollector(c).Retrieve(ctx, []types.ManagedObjectReference{ref}, paths, &content)
+
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	close(o.retrieving)
+	o.retrieving = nil
+	if err != nil {
+		return false, err
+	}
+	if len(content) != 1 || len(content[0].MissingSet) > 0 || pc.generation != generation {
+		return false, nil
+	}
+	for _, p := range paths {
+		o.properties[p] = nil
+	}
+	for i := range content[0].PropSet {
+		prop := content[0].PropSet[i]
+		o.properties[prop.Name] = &prop
+	}
+	return true, nil
+}
+
+// loadCachedContent loads content into dst. The values are copied, so that
+// callers cannot change what is in the cache.
+func loadCachedContent(content types.ObjectContent, dst interface{}) error {
+	v, err := copystructure.Copy(content.PropSet)
+	if err != nil {
+		return err
+	}
+	content.PropSet = v.([]types.DynamicProperty)
+	// Properties are loaded in path order, so that a property is loaded
+	// before the properties nested in it.
+	sort.Slice(content.PropSet, func(i, j int) bool {
+		return content.PropSet[i].Name < content.PropSet[j].Name
+	})
+	return mo.LoadObjectContent([]types.ObjectContent{content}, dst)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/soap"
+)
+
+// testCountingRoundTripper counts the RetrieveProperties calls made through
+// it.
+type testCountingRoundTripper struct {
+	soap.RoundTripper
+	retrievals int
+}
+
+func (rt *testCountingRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	switch req.(type) {
+	case *methods.RetrievePropertiesBody, *methods.RetrievePropertiesExBody:
+		rt.retrievals++
+	}
+	return rt.RoundTripper.RoundTrip(ctx, req, res)
+}
+
+func TestRetrieveProperties(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		counter := &testCountingRoundTripper{RoundTripper: c.RoundTripper}
+		cache := NewPropertyCache()
+		c.RoundTripper = cache.RoundTripper(counter)
+		SetPropertyCache(c, cache)
+
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		counter.retrievals = 0
+
+		var props mo.VirtualMachine
+		if err := RetrieveProperties(ctx, c, vm.Reference(), []string{"name", "runtime.powerState", "config.uuid"}, &props); err != nil {
+			t.Fatal(err)
+		}
+		if props.Name != "DC0_H0_VM0" || props.Runtime.PowerState != "poweredOn" || props.Config == nil || props.Config.Uuid == "" {
+			t.Fatalf("unexpected properties: %#v", props)
+		}
+		if props.Guest != nil {
+			t.Fatalf("expected guest not to be retrieved")
+		}
+		if counter.retrievals != 1 {
+			t.Fatalf("expected 1 retrieval, got %d", counter.retrievals)
+		}
+
+		// Cached properties are not retrieved again, and only the missing ones
+		// are.
+		var cached mo.VirtualMachine
+		if err := RetrieveProperties(ctx, c, vm.Reference(), []string{"name", "config.uuid", "parent"}, &cached); err != nil {
+			t.Fatal(err)
+		}
+		if cached.Name != props.Name || cached.Config.Uuid != props.Config.Uuid || cached.Parent == nil {
+			t.Fatalf("unexpected properties: %#v", cached)
+		}
+		if err := RetrieveProperties(ctx, c, vm.Reference(), []string{"name", "parent"}, &cached); err != nil {
+			t.Fatal(err)
+		}
+		if counter.retrievals != 2 {
+			t.Fatalf("expected 2 retrievals, got %d", counter.retrievals)
+		}
+
+		// Changing the values returned does not change the cache.
+		cached.Name = "changed"
+		if err := RetrieveProperties(ctx, c, vm.Reference(), []string{"name"}, &cached); err != nil {
+			t.Fatal(err)
+		}
+		if cached.Name != "DC0_H0_VM0" {
+			t.Fatalf("expected the cached name, got %q", cached.Name)
+		}
+
+		// A change clears the cache.
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		before := counter.retrievals
+		var changed mo.VirtualMachine
+		if err := RetrieveProperties(ctx, c, vm.Reference(), []string{"runtime.powerState"}, &changed); err != nil {
+			t.Fatal(err)
+		}
+		if changed.Runtime.PowerState != "poweredOff" {
+			t.Fatalf("expected poweredOff, got %q", changed.Runtime.PowerState)
+		}
+		if counter.retrievals != before+1 {
+			t.Fatalf("expected the properties to be retrieved again")
+		}
+	})
+}
+
+func TestRetrievePropertiesNoCache(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var props mo.VirtualMachine
+		if err := RetrieveProperties(ctx, c, vm.Reference(), nil, &props); err != nil {
+			t.Fatal(err)
+		}
+		if props.Name != "DC0_H0_VM0" || props.Config == nil || props.Guest == nil {
+			t.Fatalf("expected all properties to be retrieved, got %#v", props)
+		}
+	})
+}
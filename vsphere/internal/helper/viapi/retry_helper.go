@@ -0,0 +1,369 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// retryBaseWait is the wait before the first retry. The wait doubles with
+// every attempt, up to RetryPolicy.MaxWait.
+const retryBaseWait = time.Second
+
+// restSessionHeader is the header the REST API session ID is sent in.
+const restSessionHeader = "vmware-api-session-id"
+
+// RetryPolicy controls how calls that fail with a transient fault are retried.
+// The zero value does not retry.
+type RetryPolicy struct {
+	// The number of times a call is retried before its error is returned.
+	MaxRetries int
+
+	// The longest time to wait between two attempts.
+	MaxWait time.Duration
+}
+
+// wait returns the time to wait before the retry following attempt, starting
+// at 0. The wait grows exponentially, and is jittered so that concurrent
+// callers that failed together do not retry together.
+func (p RetryPolicy) wait(attempt int) time.Duration {
+	max := p.MaxWait
+	if max <= 0 {
+		max = retryBaseWait
+	}
+	d := max
+	if attempt < 16 && retryBaseWait<<attempt < max {
+		d = retryBaseWait << attempt
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// Do calls f until it succeeds, returns an error that retryable does not
+// accept, or the retries are exhausted. The last error is returned.
+func (p RetryPolicy) Do(ctx context.Context, name string, retryable func(error) bool, f func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := f()
+		if err == nil || attempt >= p.MaxRetries || !retryable(err) {
+			return err
+		}
+		d := p.wait(attempt)
+		log.Printf("[DEBUG] %s failed with a transient fault, retrying in %s (retry %d of %d): %s", name, d, attempt+1, p.MaxRetries, err)
+		select {
+		case <-time.After(d):
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// taskFault extracts the fault from the error of a failed task. Check the
+// returned boolean value to see if you have a fault.
+func taskFault(err error) (types.BaseMethodFault, bool) {
+	var te task.Error
+	if errors.As(err, &te) && te.LocalizedMethodFault != nil {
+		return te.Fault(), true
+	}
+	return nil, false
+}
+
+// IsTaskInProgressError checks an error to see if it's of the TaskInProgress
+// type, either as a SOAP fault or as the result of a task.
+func IsTaskInProgressError(err error) bool {
+	if f, ok := vimSoapFault(err); ok {
+		switch f.(type) {
+		case types.TaskInProgress, types.VAppTaskInProgress:
+			return true
+		}
+	}
+	if f, ok := taskFault(err); ok {
+		if _, ok := f.(types.BaseTaskInProgress); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsConcurrentAccessError checks an error to see if it's of the
+// ConcurrentAccess type, either as a SOAP fault or as the result of a task.
+func IsConcurrentAccessError(err error) bool {
+	if f, ok := vimSoapFault(err); ok {
+		if _, ok := f.(types.ConcurrentAccess); ok {
+			return true
+		}
+	}
+	if f, ok := taskFault(err); ok {
+		if _, ok := f.(*types.ConcurrentAccess); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsHostCommunicationError checks an error to see if it's of the
+// HostCommunication type or one of its subtypes, either as a SOAP fault or as
+// the result of a task.
+func IsHostCommunicationError(err error) bool {
+	if f, ok := vimSoapFault(err); ok {
+		switch f.(type) {
+		case types.HostCommunication, types.HostNotConnected, types.HostNotReachable:
+			return true
+		}
+	}
+	if f, ok := taskFault(err); ok {
+		if _, ok := f.(types.BaseHostCommunication); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// IsServiceUnavailableError checks an error to see if it's an HTTP 503
+// response, which is returned while vCenter services are starting or
+// overloaded.
+func IsServiceUnavailableError(err error) bool {
+	var ue *url.Error
+	if errors.As(err, &ue) && ue.Err != nil {
+		return strings.HasPrefix(ue.Err.Error(), fmt.Sprintf("%d ", http.StatusServiceUnavailable))
+	}
+	return false
+}
+
+// IsTransientError checks an error to see if it's a fault that goes away on
+// its own, after which the failed call or task can be retried.
+func IsTransientError(err error) bool {
+	switch {
+	case IsTaskInProgressError(err):
+		fallthrough
+	case IsConcurrentAccessError(err):
+		fallthrough
+	case IsHostCommunicationError(err):
+		fallthrough
+	case IsServiceUnavailableError(err):
+		return true
+	}
+	return false
+}
+
+// isRejectedError checks an error to see if it's a fault that is returned
+// before a call has had any effect, so that the call can be retried even if it
+// is not idempotent.
+func isRejectedError(err error) bool {
+	return IsTaskInProgressError(err) || IsServiceUnavailableError(err)
+}
+
+// readOnlyMethodPrefixes are the prefixes of the names of vSphere API methods
+// that do not change anything, and can be retried after any transient fault.
+var readOnlyMethodPrefixes = []string{
+	"Retrieve",
+	"Query",
+	"Find",
+	"Fetch",
+	"CurrentTime",
+}
+
+// isReadOnlyMethod returns true if the request is for a method that does not
+// change anything.
+func isReadOnlyMethod(req soap.HasFault) bool {
+	name := reflect.Indirect(reflect.ValueOf(req)).Type().Name()
+	for _, p := range readOnlyMethodPrefixes {
+		if strings.HasPrefix(name, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// retryRoundTripper retries SOAP calls that fail with a transient fault.
+type retryRoundTripper struct {
+	soap.RoundTripper
+	policy RetryPolicy
+}
+
+// NewRetryRoundTripper returns a soap.RoundTripper that retries calls made
+// through rt according to p. Calls that are rejected with TaskInProgress or an
+// HTTP 503 are retried, as well as read-only calls that fail with any other
+// transient fault.
+//
+// Install it on a vim25.Client with:
+//
+//	c.RoundTripper = NewRetryRoundTripper(c.RoundTripper, p)
+func NewRetryRoundTripper(rt soap.RoundTripper, p RetryPolicy) soap.RoundTripper {
+	return &retryRoundTripper{
+		RoundTripper: rt,
+		policy:       p,
+	}
+}
+
+// Unwrap returns the soap.RoundTripper that retryRoundTripper sends calls
+// through.
+func (r *retryRoundTripper) Unwrap() soap.RoundTripper {
+	return r.RoundTripper
+}
+
+// RoundTrip implements soap.RoundTripper for retryRoundTripper.
+func (r *retryRoundTripper) RoundTrip(ctx context.Context, req, res soap.HasFault) error {
+	retryable := isRejectedError
+	if isReadOnlyMethod(req) {
+		retryable = IsTransientError
+	}
+	name := strings.TrimSuffix(reflect.Indirect(reflect.ValueOf(req)).Type().Name(), "Body")
+
+	first := true
+	return r.policy.Do(ctx, name, retryable, func() error {
+		if !first {
+			// The fault of the previous attempt is left on the response
+			// otherwise.
+			v := reflect.ValueOf(res).Elem()
+			v.Set(reflect.Zero(v.Type()))
+		}
+		first = false
+		return r.RoundTripper.RoundTrip(ctx, req, res)
+	})
+}
+
+// RetryPolicyFromClient returns the retry policy installed on c by
+// NewRetryRoundTripper. The zero policy is returned if there is none.
+func RetryPolicyFromClient(c *vim25.Client) RetryPolicy {
+	if r, ok := c.RoundTripper.(*retryRoundTripper); ok {
+		return r.policy
+	}
+	return RetryPolicy{}
+}
+
+// RetryTask calls f, which should submit a task and wait for it to complete,
+// and calls it again if the task is rejected with TaskInProgress or an HTTP
+// 503, according to the retry policy installed on c. Tasks that fail with
+// other transient faults, such as HostCommunication or ConcurrentAccess, may
+// have been partially applied and are not submitted again. Retries stop when
+// ctx is done.
+func RetryTask(ctx context.Context, c *vim25.Client, name string, f func() error) error {
+	return RetryPolicyFromClient(c).Do(ctx, name, isRejectedError, f)
+}
+
+// restRetryTransport retries REST API requests that fail with a transient
+// error, and logs in again when the session has expired.
+type restRetryTransport struct {
+	http.RoundTripper
+	policy RetryPolicy
+	login  func(context.Context) (string, error)
+}
+
+// errRestServiceUnavailable is returned internally by restRetryTransport for a
+// 503 response.
+var errRestServiceUnavailable = errors.New(http.StatusText(http.StatusServiceUnavailable))
+
+// restLoginContext marks requests made by the login function of a
+// restRetryTransport, so that a failed login is not retried by logging in.
+type restLoginContext struct{}
+
+// NewRestRetryTransport returns an http.RoundTripper for a rest.Client that
+// retries requests made through rt that fail with an HTTP 503 according to p.
+// If login is not nil, it is called to create a new session when a request
+// fails with an HTTP 401, and the request is sent again with the session ID
+// it returns.
+//
+// Only JSON API requests are retried. Uploads and downloads are not, as their
+// bodies cannot be replayed.
+func NewRestRetryTransport(rt http.RoundTripper, p RetryPolicy, login func(context.Context) (string, error)) http.RoundTripper {
+	return &restRetryTransport{
+		RoundTripper: rt,
+		policy:       p,
+		login:        login,
+	}
+}
+
+// RoundTrip implements http.RoundTripper for restRetryTransport.
+func (t *restRetryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept") != "application/json" {
+		return t.RoundTripper.RoundTrip(req)
+	}
+	var body []byte
+	if req.Body != nil {
+		b, err := io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = b
+	}
+
+	var res *http.Response
+	// sessionID is the session of a new login, which replaces the one of req in
+	// the requests sent after it. req itself is never modified.
+	var sessionID string
+	name := fmt.Sprintf("%s %s", req.Method, req.URL.Path)
+	retryable := func(err error) bool {
+		return errors.Is(err, errRestServiceUnavailable)
+	}
+	err := t.policy.Do(req.Context(), name, retryable, func() error {
+		if res != nil {
+			_ = res.Body.Close()
+		}
+		var err error
+		res, err = t.send(req, body, sessionID)
+		if err != nil {
+			return err
+		}
+		if res.StatusCode == http.StatusUnauthorized && sessionID == "" && t.canLogin(req) {
+			_ = res.Body.Close()
+			log.Printf("[DEBUG] REST API session has expired, logging in again")
+			id, err := t.login(context.WithValue(req.Context(), restLoginContext{}, true))
+			if err != nil {
+				return fmt.Errorf("error logging in to the REST API after the session expired: %s", err)
+			}
+			sessionID = id
+			res, err = t.send(req, body, sessionID)
+			if err != nil {
+				return err
+			}
+		}
+		if res.StatusCode == http.StatusServiceUnavailable {
+			return errRestServiceUnavailable
+		}
+		return nil
+	})
+	if errors.Is(err, errRestServiceUnavailable) {
+		// The retries are exhausted. Hand the response back to the client,
+		// which turns it into an error of its own.
+		return res, nil
+	}
+	return res, err
+}
+
+// send sends a copy of req with body, and with the session sessionID if it is
+// not empty.
+func (t *restRetryTransport) send(req *http.Request, body []byte, sessionID string) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	if sessionID != "" {
+		r.Header.Set(restSessionHeader, sessionID)
+	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
+	r.ContentLength = int64(len(body))
+	return t.RoundTripper.RoundTrip(r)
+}
+
+// canLogin returns true if a new session can be created for req.
+func (t *restRetryTransport) canLogin(req *http.Request) bool {
+	if t.login == nil || req.Header.Get(restSessionHeader) == "" {
+		return false
+	}
+	_, ok := req.Context().Value(restLoginContext{}).(bool)
+	return !ok
+}
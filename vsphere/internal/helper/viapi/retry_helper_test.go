@@ -0,0 +1,277 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/task"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/soap"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+var testRetryPolicy = RetryPolicy{
+	MaxRetries: 2,
+	MaxWait:    time.Millisecond,
+}
+
+// testFaultRoundTripper fails the first calls made through it with a fault.
+type testFaultRoundTripper struct {
+	fault types.AnyType
+	fails int
+	calls int
+}
+
+func (rt *testFaultRoundTripper) RoundTrip(_ context.Context, _, res soap.HasFault) error {
+	rt.calls++
+	if rt.calls > rt.fails {
+		return nil
+	}
+	f := &soap.Fault{String: "fault"}
+	f.Detail.Fault = rt.fault
+	switch b := res.(type) {
+	case *methods.RetrievePropertiesExBody:
+		b.Fault_ = f
+	case *methods.ReconfigVM_TaskBody:
+		b.Fault_ = f
+	}
+	return soap.WrapSoapFault(f)
+}
+
+func TestRetryRoundTripper(t *testing.T) {
+	cases := []struct {
+		name      string
+		readOnly  bool
+		fault     types.AnyType
+		fails     int
+		expectErr bool
+		expected  int
+	}{
+		{
+			name:     "read-only call with concurrent access",
+			readOnly: true,
+			fault:    types.ConcurrentAccess{},
+			fails:    1,
+			expected: 2,
+		},
+		{
+			name:      "update with concurrent access",
+			fault:     types.ConcurrentAccess{},
+			fails:     1,
+			expectErr: true,
+			expected:  1,
+		},
+		{
+			name:     "update with task in progress",
+			fault:    types.TaskInProgress{},
+			fails:    2,
+			expected: 3,
+		},
+		{
+			name:      "retries exhausted",
+			readOnly:  true,
+			fault:     types.HostNotConnected{},
+			fails:     5,
+			expectErr: true,
+			expected:  3,
+		},
+		{
+			name:      "permanent fault",
+			readOnly:  true,
+			fault:     types.InvalidArgument{},
+			fails:     1,
+			expectErr: true,
+			expected:  1,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			rt := &testFaultRoundTripper{fault: tc.fault, fails: tc.fails}
+			r := NewRetryRoundTripper(rt, testRetryPolicy)
+
+			var req, res soap.HasFault
+			if tc.readOnly {
+				req, res = &methods.RetrievePropertiesExBody{}, &methods.RetrievePropertiesExBody{}
+			} else {
+				req, res = &methods.ReconfigVM_TaskBody{}, &methods.ReconfigVM_TaskBody{}
+			}
+			err := r.RoundTrip(context.Background(), req, res)
+			switch {
+			case tc.expectErr && err == nil:
+				t.Fatalf("expected error, got none")
+			case !tc.expectErr && err != nil:
+				t.Fatalf("bad: %s", err)
+			case !tc.expectErr && res.Fault() != nil:
+				t.Fatalf("expected the fault of the failed attempt to be cleared")
+			}
+			if rt.calls != tc.expected {
+				t.Fatalf("expected %d calls, got %d", tc.expected, rt.calls)
+			}
+		})
+	}
+}
+
+func TestRetryTask(t *testing.T) {
+	c := &vim25.Client{RoundTripper: NewRetryRoundTripper(&testFaultRoundTripper{}, testRetryPolicy)}
+
+	var calls int
+	err := RetryTask(context.Background(), c, "ReconfigVM_Task", func() error {
+		calls++
+		if calls == 1 {
+			return task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.TaskInProgress{}}}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	if calls != 2 {
+		t.Fatalf("expected 2 calls, got %d", calls)
+	}
+
+	for _, fault := range []types.BaseMethodFault{&types.HostNotConnected{}, &types.ConcurrentAccess{}} {
+		calls = 0
+		err = RetryTask(context.Background(), c, "ReconfigVM_Task", func() error {
+			calls++
+			return task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: fault}}
+		})
+		if err == nil || calls != 1 {
+			t.Fatalf("expected a task that failed with %T to not be submitted again, got %d calls, error %v", fault, calls, err)
+		}
+	}
+
+	calls = 0
+	err = RetryTask(context.Background(), &vim25.Client{}, "ReconfigVM_Task", func() error {
+		calls++
+		return task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.ConcurrentAccess{}}}
+	})
+	if err == nil || calls != 1 {
+		t.Fatalf("expected a client without a retry policy to not retry, got %d calls, error %v", calls, err)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	cases := []struct {
+		name     string
+		err      error
+		expected bool
+	}{
+		{
+			name:     "task in progress task fault",
+			err:      task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.VAppTaskInProgress{}}},
+			expected: true,
+		},
+		{
+			name:     "service unavailable",
+			err:      &url.Error{Op: "POST", URL: "/sdk", Err: errors.New("503 Service Unavailable")},
+			expected: true,
+		},
+		{
+			name: "bad gateway",
+			err:  &url.Error{Op: "POST", URL: "/sdk", Err: errors.New("502 Bad Gateway")},
+		},
+		{
+			name: "not found",
+			err:  task.Error{LocalizedMethodFault: &types.LocalizedMethodFault{Fault: &types.ManagedObjectNotFound{}}},
+		},
+		{
+			name: "other error",
+			err:  errors.New("boom"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if actual := IsTransientError(tc.err); actual != tc.expected {
+				t.Fatalf("expected %t, got %t", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func testRestPost(t *testing.T, rt http.RoundTripper, u string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, u, strings.NewReader(`{"spec":{}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set(restSessionHeader, "expired")
+	res, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatalf("bad: %s", err)
+	}
+	_ = res.Body.Close()
+	if id := req.Header.Get(restSessionHeader); id != "expired" {
+		t.Fatalf("expected the request to be left unmodified, got session %q", id)
+	}
+	return res
+}
+
+func TestRestRetryTransport(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if b, _ := io.ReadAll(r.Body); string(b) != `{"spec":{}}` {
+			t.Errorf("expected the request body to be replayed, got %q", b)
+		}
+		switch {
+		case calls == 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.Header.Get(restSessionHeader) != "renewed":
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	var logins int
+	rt := NewRestRetryTransport(http.DefaultTransport, testRetryPolicy, func(context.Context) (string, error) {
+		logins++
+		return "renewed", nil
+	})
+	res := testRestPost(t, rt, server.URL)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %s", res.Status)
+	}
+	if calls != 3 || logins != 1 {
+		t.Fatalf("expected 3 calls and 1 login, got %d calls and %d logins", calls, logins)
+	}
+
+	// A request that is retried after a new login keeps the new session.
+	calls = 0
+	logins = 0
+	renewed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.Header.Get(restSessionHeader) != "renewed":
+			w.WriteHeader(http.StatusUnauthorized)
+		case calls == 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer renewed.Close()
+	res = testRestPost(t, rt, renewed.URL)
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %s", res.Status)
+	}
+	if calls != 3 || logins != 1 {
+		t.Fatalf("expected 3 calls and 1 login, got %d calls and %d logins", calls, logins)
+	}
+
+	// Without a login function the expired session is returned as is.
+	calls = 1
+	res = testRestPost(t, NewRestRetryTransport(http.DefaultTransport, testRetryPolicy, nil), server.URL)
+	if res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected status 401, got %s", res.Status)
+	}
+}
@@ -0,0 +1,42 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"log"
+
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// WaitForTask waits for task to complete and returns its result. If ctx is
+// done first, for example because Terraform was interrupted or the resource
+// timed out, the task is cancelled, so that it does not keep running in
+// vCenter. Not all tasks can be cancelled, so this is done on a best-effort
+// basis.
+//
+// Errors are returned as a *TaskError, which carries the ID of the task.
+func WaitForTask(ctx context.Context, task *object.Task) (*types.TaskInfo, error) {
+	info, err := task.WaitForResult(ctx, nil)
+	if err != nil {
+		if ctx.Err() != nil {
+			cancelTask(task)
+		}
+		return info, &TaskError{TaskID: task.Reference().Value, Err: err}
+	}
+	return info, nil
+}
+
+// cancelTask requests the cancellation of task, logging the result. A new
+// context is used, as the one the task was waited on is already done.
+func cancelTask(task *object.Task) {
+	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	defer cancel()
+	log.Printf("[DEBUG] Cancelling task %q", task.Reference().Value)
+	if err := task.Cancel(ctx); err != nil {
+		log.Printf("[DEBUG] Could not cancel task %q: %s", task.Reference().Value, err)
+	}
+}
@@ -0,0 +1,48 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package viapi
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vim25"
+)
+
+func TestWaitForTask(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := WaitForTask(ctx, task); err != nil {
+			t.Fatalf("bad: %s", err)
+		}
+
+		// Task delays are keyed by the name of the task, rather than the method.
+		simulator.TaskDelay.MethodDelay = map[string]int{"PowerOn": 1000, "LockHandoff": 0}
+		defer func() { simulator.TaskDelay.MethodDelay = nil }()
+		task, err = vm.PowerOn(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := WaitForTask(tctx, task); err == nil || tctx.Err() == nil {
+			t.Fatalf("expected the wait to end with the context, got %v", err)
+		}
+		if time.Since(start) > 500*time.Millisecond {
+			t.Fatalf("expected the wait to end when the context was done, took %s", time.Since(start))
+		}
+	})
+}
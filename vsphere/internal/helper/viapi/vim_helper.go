@@ -27,11 +27,14 @@ type ManagedObject interface {
 // ErrVirtualCenterOnly is the error message that validateVirtualCenter returns.
 const ErrVirtualCenterOnly = "this operation is only supported on vCenter"
 
-// soapFault extracts the SOAP fault from an error fault, if it exists. Check
-// the returned boolean value to see if you have a SoapFault.
+// soapFault extracts the SOAP fault from an error fault, if it exists. Errors
+// wrapping the fault are unwrapped. Check the returned boolean value to see if
+// you have a SoapFault.
 func soapFault(err error) (*soap.Fault, bool) {
-	if soap.IsSoapFault(err) {
-		return soap.ToSoapFault(err), true
+	for ; err != nil; err = errors.Unwrap(err) {
+		if soap.IsSoapFault(err) {
+			return soap.ToSoapFault(err), true
+		}
 	}
 	return nil, false
 }
@@ -95,23 +98,26 @@ func IsResourceInUseError(err error) bool {
 }
 
 // RenameObject renames a MO and tracks the task to make sure it completes.
-func RenameObject(client *govmomi.Client, ref types.ManagedObjectReference, new string) error {
+func RenameObject(ctx context.Context, client *govmomi.Client, ref types.ManagedObjectReference, new string) error {
 	req := types.Rename_Task{
 		This:    ref,
 		NewName: new,
 	}
 
-	rctx, rcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer rcancel()
-	res, err := methods.Rename_Task(rctx, client.Client, &req)
-	if err != nil {
-		return err
-	}
+	return RetryTask(ctx, client.Client, "Rename_Task", func() error {
+		rctx, rcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		defer rcancel()
+		res, err := methods.Rename_Task(rctx, client.Client, &req)
+		if err != nil {
+			return err
+		}
 
-	t := object.NewTask(client.Client, res.Returnval)
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer tcancel()
-	return t.Wait(tctx)
+		t := object.NewTask(client.Client, res.Returnval)
+		tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		defer tcancel()
+		_, err = WaitForTask(tctx, t)
+		return err
+	})
 }
 
 // ValidateVirtualCenter ensures that the client is connected to vCenter.
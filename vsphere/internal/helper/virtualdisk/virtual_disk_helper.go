@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
@@ -51,7 +52,7 @@ func IsVmdkDatastorePath(p string) bool {
 //
 // The new datastore path is returned along with any error, to avoid the need
 // to re-calculate the path separately.
-func Move(client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstPath string, dstDC *object.Datacenter) (string, error) {
+func Move(ctx context.Context, client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstPath string, dstDC *object.Datacenter) (string, error) {
 	vdm := object.NewVirtualDiskManager(client.Client)
 	if srcDC == nil {
 		return "", fmt.Errorf("source datacenter cannot be nil")
@@ -74,15 +75,11 @@ func Move(client *govmomi.Client, srcPath string, srcDC *object.Datacenter, dstP
 		dstPath,
 		structure.LogCond(dstDC != nil, fmt.Sprintf("in datacenter %s", dstDC), ""),
 	)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
 	task, err := vdm.MoveVirtualDisk(ctx, srcPath, srcDC, dstPath, dstDC, false)
 	if err != nil {
 		return "", err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer tcancel()
-	if err := task.Wait(tctx); err != nil {
+	if _, err := viapi.WaitForTask(ctx, task); err != nil {
 		return "", err
 	}
 	log.Printf("[DEBUG] Virtual disk %q in datacenter %s successfully moved to destination %s%s",
@@ -123,21 +120,17 @@ func dstDataStorePathFromLocalSrc(src, dst string) string {
 }
 
 // Delete deletes the virtual disk at the specified datastore path.
-func Delete(client *govmomi.Client, name string, dc *object.Datacenter) error {
+func Delete(ctx context.Context, client *govmomi.Client, name string, dc *object.Datacenter) error {
 	if dc == nil {
 		return fmt.Errorf("datacenter cannot be nil")
 	}
 	log.Printf("[DEBUG] Deleting virtual disk %q in datacenter %s", name, dc)
 	vdm := object.NewVirtualDiskManager(client.Client)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
 	task, err := vdm.DeleteVirtualDisk(ctx, name, dc)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer tcancel()
-	if err := task.Wait(tctx); err != nil {
+	if _, err := viapi.WaitForTask(ctx, task); err != nil {
 		return err
 	}
 	log.Printf("[DEBUG] Virtual disk %q in datacenter %s deleted successfully", name, dc)
@@ -34,14 +34,6 @@ import (
 
 var errGuestShutdownTimeout = errors.New("the VM did not power off within the specified amount of time")
 
-// vmUUIDSearchIndexVersion denotes the minimum version we use the SearchIndex
-// VM UUID search for. All versions lower than this use ContainerView to find
-// the VM.
-var vmUUIDSearchIndexVersion = viapi.VSphereVersion{
-	Major: 6,
-	Minor: 5,
-}
-
 // UUIDNotFoundError is an error type that is returned when a
 // virtual machine could not be found by UUID.
 type UUIDNotFoundError struct {
@@ -127,10 +119,9 @@ func FromUUID(client *govmomi.Client, uuid string) (*object.VirtualMachine, erro
 
 	var result object.Reference
 	var err error
-	version := viapi.ParseVersionFromClient(client)
-	expected := vmUUIDSearchIndexVersion
-	expected.Product = version.Product
-	if version.Older(expected) {
+	// Versions without the SearchIndex VM UUID search use ContainerView to
+	// find the VM.
+	if !viapi.HasCapability(client, viapi.CapabilityVMUUIDSearchIndex) {
 		result, err = virtualMachineFromContainerView(ctx, client, uuid)
 	} else {
 		result, err = virtualMachineFromSearchIndex(ctx, client, uuid)
@@ -256,12 +247,17 @@ func FromPath(client *govmomi.Client, path string, dc *object.Datacenter) (*obje
 
 // Properties is a convenience method that wraps fetching the
 // VirtualMachine MO from its higher-level object.
-func Properties(vm *object.VirtualMachine) (*mo.VirtualMachine, error) {
+//
+// Only the property paths in paths are retrieved, or all properties if paths
+// is empty. Reads should pass the paths they use, as a full VirtualMachine is
+// large, and retrievals of specific paths are shared through the property
+// cache of the client. See viapi.RetrieveProperties.
+func Properties(vm *object.VirtualMachine, paths ...string) (*mo.VirtualMachine, error) {
 	log.Printf("[DEBUG] Fetching properties for VM %q", vm.InventoryPath)
 	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
 	defer cancel()
 	var props mo.VirtualMachine
-	if err := vm.Properties(ctx, vm.Reference(), nil, &props); err != nil {
+	if err := viapi.RetrieveProperties(ctx, vm.Client(), vm.Reference(), paths, &props); err != nil {
 		return nil, err
 	}
 	return &props, nil
@@ -296,7 +292,7 @@ func ConfigOptions(vm *object.VirtualMachine) (*types.VirtualMachineConfigOption
 //
 // The timeout is specified in minutes. If zero or a negative value is passed,
 // the waiter returns without error immediately.
-func WaitForGuestIP(client *govmomi.Client, vm *object.VirtualMachine, timeout int, ignoredGuestIPs []interface{}) error {
+func WaitForGuestIP(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int, ignoredGuestIPs []interface{}) error {
 	if timeout < 1 {
 		log.Printf("[DEBUG] Skipping IP waiter for VM %q", vm.InventoryPath)
 		return nil
@@ -308,7 +304,7 @@ func WaitForGuestIP(client *govmomi.Client, vm *object.VirtualMachine, timeout i
 	)
 
 	p := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 
 	err := property.Wait(ctx, p, vm.Reference(), []string{"guest.ipAddress"}, func(pc []types.PropertyChange) bool {
@@ -351,7 +347,7 @@ func WaitForGuestIP(client *govmomi.Client, vm *object.VirtualMachine, timeout i
 //
 // The timeout is specified in minutes. If zero or a negative value is passed,
 // the waiter returns without error immediately.
-func WaitForGuestNet(client *govmomi.Client, vm *object.VirtualMachine, routable bool, timeout int, ignoredGuestIPs []interface{}) error {
+func WaitForGuestNet(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, routable bool, timeout int, ignoredGuestIPs []interface{}) error {
 	if timeout < 1 {
 		log.Printf("[DEBUG] Skipping network waiter for VM %q", vm.InventoryPath)
 		return nil
@@ -365,7 +361,7 @@ func WaitForGuestNet(client *govmomi.Client, vm *object.VirtualMachine, routable
 	var v4gw, v6gw net.IP
 
 	p := client.PropertyCollector()
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 
 	err := property.Wait(ctx, p, vm.Reference(), []string{"guest.net", "guest.ipStack"}, func(pc []types.PropertyChange) bool {
@@ -491,12 +487,43 @@ func blockUntilReadyForMethod(ctx context.Context, method string, vm *object.Vir
 	return nil
 }
 
+// DeviceChangeError is returned by Create and Reconfigure when vSphere rejects
+// one of the device changes in the config spec. It carries the rejected
+// change, so that the error can be traced back to the device in the
+// configuration.
+type DeviceChangeError struct {
+	// Change is the device change that vSphere rejected.
+	Change types.BaseVirtualDeviceConfigSpec
+	// Err is the error returned by vSphere.
+	Err error
+}
+
+// Error implements error for DeviceChangeError.
+func (e *DeviceChangeError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error returned by vSphere.
+func (e *DeviceChangeError) Unwrap() error {
+	return e.Err
+}
+
+// deviceChangeError wraps err in a DeviceChangeError if it carries a fault
+// that refers to one of changes. Other errors, including nil, are returned
+// as-is.
+func deviceChangeError(err error, changes []types.BaseVirtualDeviceConfigSpec) error {
+	if f, ok := viapi.FaultFromError(err); ok && f.DeviceIndex >= 0 && f.DeviceIndex < len(changes) {
+		return &DeviceChangeError{Change: changes[f.DeviceIndex], Err: err}
+	}
+	return err
+}
+
 // Create wraps the creation of a virtual machine and the subsequent waiting of
 // the task. A higher-level virtual machine object is returned.
-func Create(c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpec, p *object.ResourcePool,
+func Create(ctx context.Context, c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpec, p *object.ResourcePool,
 	h *object.HostSystem, timeout time.Duration) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] Creating virtual machine %q", fmt.Sprintf("%s/%s", f.InventoryPath, s.Name))
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 	var task *object.Task
 	// Check to see if the resource pool is a vApp
@@ -512,11 +539,11 @@ func Create(c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpe
 	if err != nil {
 		return nil, err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), timeout)
+	tctx, tcancel := context.WithTimeout(ctx, timeout)
 	defer tcancel()
-	result, err := task.WaitForResult(tctx, nil)
+	result, err := viapi.WaitForTask(tctx, task)
 	if err != nil {
-		return nil, err
+		return nil, deviceChangeError(err, s.DeviceChange)
 	}
 	log.Printf("[DEBUG] Virtual machine %q: creation complete (MOID: %q)", fmt.Sprintf("%s/%s", f.InventoryPath, s.Name), result.Result.(types.ManagedObjectReference).Value)
 	return FromMOID(c, result.Result.(types.ManagedObjectReference).Value)
@@ -524,9 +551,14 @@ func Create(c *govmomi.Client, f *object.Folder, s types.VirtualMachineConfigSpe
 
 // Clone wraps the creation of a virtual machine and the subsequent waiting of
 // the task. A higher-level virtual machine object is returned.
-func Clone(c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name string, spec types.VirtualMachineCloneSpec, timeout int) (*object.VirtualMachine, error) {
+func Clone(ctx context.Context, c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name string, spec types.VirtualMachineCloneSpec, timeout int) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] Cloning virtual machine %q", fmt.Sprintf("%s/%s", f.InventoryPath, name))
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	release, err := viapi.AcquireTask(ctx, c.Client, viapi.TaskClassClone, fmt.Sprintf("clone of %q", name))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 	task, err := src.Clone(ctx, f, name, spec)
 	if err != nil {
@@ -535,7 +567,7 @@ func Clone(c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name
 		}
 		return nil, err
 	}
-	result, err := task.WaitForResult(ctx, nil)
+	result, err := viapi.WaitForTask(ctx, task)
 	if err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			err = errors.New("timeout waiting for clone to complete")
@@ -547,21 +579,21 @@ func Clone(c *govmomi.Client, src *object.VirtualMachine, f *object.Folder, name
 }
 
 // Deploy clones a virtual machine from a content library item.
-func Deploy(deployData *VCenterDeploy) (*types.ManagedObjectReference, error) {
+func Deploy(ctx context.Context, deployData *VCenterDeploy) (*types.ManagedObjectReference, error) {
 	log.Printf("[DEBUG] virtualmachine.Deploy: Deploying VM from Content Library item.")
 	// Get OVF mappings for NICs
 
 	switch deployData.LibraryItem.Type {
 	case library.ItemTypeOVF:
-		return deployData.deployOvf()
+		return deployData.deployOvf(ctx)
 	case library.ItemTypeVMTX:
-		return deployData.deployVmtx()
+		return deployData.deployVmtx(ctx)
 	default:
 		return nil, fmt.Errorf("unsupported library item type: %s", deployData.LibraryItem.Type)
 	}
 }
 
-func (deployData *VCenterDeploy) deployVmtx() (*types.ManagedObjectReference, error) {
+func (deployData *VCenterDeploy) deployVmtx(ctx context.Context) (*types.ManagedObjectReference, error) {
 	storage := &vcenter.DiskStorage{
 		Datastore: deployData.DatastoreID,
 		StoragePolicy: &vcenter.StoragePolicy{
@@ -584,11 +616,10 @@ func (deployData *VCenterDeploy) deployVmtx() (*types.ManagedObjectReference, er
 			Folder:       deployData.FolderID,
 		},
 	}
-	ctx := context.TODO()
 	return deployData.VCenterManager.DeployTemplateLibraryItem(ctx, deployData.LibraryItem.ID, deploy)
 }
 
-func (deployData *VCenterDeploy) deployOvf() (*types.ManagedObjectReference, error) {
+func (deployData *VCenterDeploy) deployOvf(ctx context.Context) (*types.ManagedObjectReference, error) {
 	deploy := vcenter.Deploy{
 		DeploymentSpec: vcenter.DeploymentSpec{
 			Name:               deployData.VMName,
@@ -616,7 +647,6 @@ func (deployData *VCenterDeploy) deployOvf() (*types.ManagedObjectReference, err
 			FolderID:       deployData.FolderID,
 		},
 	}
-	ctx := context.TODO()
 	return deployData.VCenterManager.DeployLibraryItem(ctx, deployData.LibraryItem.ID, deploy)
 }
 
@@ -653,21 +683,22 @@ func DiskType(d *schema.ResourceData) string {
 
 // Customize wraps the customization of a virtual machine and the subsequent
 // waiting of the task.
-func Customize(vm *object.VirtualMachine, spec types.CustomizationSpec) error {
+func Customize(ctx context.Context, vm *object.VirtualMachine, spec types.CustomizationSpec) error {
 	log.Printf("[DEBUG] Sending customization spec to virtual machine %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 	task, err := vm.Customize(ctx, spec)
 	if err != nil {
 		return err
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer tcancel()
-	return task.Wait(tctx)
+	_, err = viapi.WaitForTask(tctx, task)
+	return err
 }
 
 // PowerOn wraps powering on a VM and the waiting for the subsequent task.
-func PowerOn(vm *object.VirtualMachine, pTimeout time.Duration) error {
+func PowerOn(ctx context.Context, vm *object.VirtualMachine, pTimeout time.Duration) error {
 	vmPath := vm.InventoryPath
 	log.Printf("[DEBUG] Powering on virtual machine %q", vmPath)
 	var ctxTimeout time.Duration
@@ -677,7 +708,7 @@ func PowerOn(vm *object.VirtualMachine, pTimeout time.Duration) error {
 		ctxTimeout = provider.DefaultAPITimeout
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), ctxTimeout)
+	ctx, cancel := context.WithTimeout(ctx, ctxTimeout)
 	defer cancel()
 
 	err := blockUntilReadyForMethod(ctx, "PowerOnVM_Task", vm)
@@ -707,7 +738,7 @@ powerLoop:
 					log.Printf("[DEBUG] Failed to submit PowerOn task for vm %q. Error: %s", vmPath, err)
 					return fmt.Errorf("failed to submit poweron task for vm %q: %s", vmPath, err)
 				}
-				err = task.Wait(ctx)
+				_, err = viapi.WaitForTask(ctx, task)
 				if err != nil {
 					if err.Error() == "The operation is not allowed in the current state." {
 						log.Printf("[DEBUG] vm %q cannot be powered on in the current state", vmPath)
@@ -728,17 +759,20 @@ powerLoop:
 }
 
 // PowerOff wraps powering off a VM and the waiting for the subsequent task.
-func PowerOff(vm *object.VirtualMachine) error {
+func PowerOff(ctx context.Context, vm *object.VirtualMachine) error {
 	log.Printf("[DEBUG] Forcing power off of virtual machine of %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
-	task, err := vm.PowerOff(ctx)
-	if err != nil {
+	return viapi.RetryTask(ctx, vm.Client(), "PowerOffVM_Task", func() error {
+		ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		defer cancel()
+		task, err := vm.PowerOff(ctx)
+		if err != nil {
+			return err
+		}
+		tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		defer tcancel()
+		_, err = viapi.WaitForTask(tctx, task)
 		return err
-	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer tcancel()
-	return task.Wait(tctx)
+	})
 }
 
 // ShutdownGuest wraps the graceful shutdown of a guest VM, and then waiting an
@@ -748,9 +782,9 @@ func PowerOff(vm *object.VirtualMachine) error {
 //
 // The minimum value for timeout is 1 minute - setting to a 0 or negative value
 // is not allowed and will just reset the timeout to the minimum.
-func ShutdownGuest(client *govmomi.Client, vm *object.VirtualMachine, timeout int) error {
+func ShutdownGuest(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int) error {
 	log.Printf("[DEBUG] Attempting guest shutdown of virtual machine %q", vm.InventoryPath)
-	sctx, scancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	sctx, scancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer scancel()
 	if err := vm.ShutdownGuest(sctx); err != nil {
 		return err
@@ -761,7 +795,7 @@ func ShutdownGuest(client *govmomi.Client, vm *object.VirtualMachine, timeout in
 	if timeout < 1 {
 		timeout = 1
 	}
-	pctx, pcancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	pctx, pcancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer pcancel()
 
 	err := property.Wait(pctx, p, vm.Reference(), []string{"runtime.powerState"}, func(pc []types.PropertyChange) bool {
@@ -794,7 +828,7 @@ func ShutdownGuest(client *govmomi.Client, vm *object.VirtualMachine, timeout in
 // machines. A graceful shutdown is attempted first if possible (VMware Tools
 // is installed, and the guest state is not suspended), and then, if allowed, a
 // power-off is forced if that fails.
-func GracefulPowerOff(client *govmomi.Client, vm *object.VirtualMachine, timeout int, force bool) error {
+func GracefulPowerOff(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, timeout int, force bool) error {
 	vprops, err := Properties(vm)
 	if err != nil {
 		return err
@@ -803,7 +837,7 @@ func GracefulPowerOff(client *govmomi.Client, vm *object.VirtualMachine, timeout
 	// actually powered on (we don't expect that a graceful shutdown would
 	// complete on a suspended VM, so there's really no point in trying).
 	if vprops.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn && vprops.Guest != nil && vprops.Guest.ToolsRunningStatus == string(types.VirtualMachineToolsRunningStatusGuestToolsRunning) {
-		if err := ShutdownGuest(client, vm, timeout); err != nil {
+		if err := ShutdownGuest(ctx, client, vm, timeout); err != nil {
 			if err == errGuestShutdownTimeout && !force {
 				return err
 			}
@@ -814,66 +848,79 @@ func GracefulPowerOff(client *govmomi.Client, vm *object.VirtualMachine, timeout
 	// If the guest shutdown failed (and we were allowed to proceed), or
 	// conditions did not satisfy the criteria for a graceful shutdown, do a full
 	// power-off of the VM.
-	return PowerOff(vm)
+	return PowerOff(ctx, vm)
 }
 
 // MoveToFolder moves a virtual machine to the specified folder.
-func MoveToFolder(client *govmomi.Client, vm *object.VirtualMachine, relative string) error {
+func MoveToFolder(ctx context.Context, client *govmomi.Client, vm *object.VirtualMachine, relative string) error {
 	log.Printf("[DEBUG] Moving virtual %q to VM path %q", vm.InventoryPath, relative)
 	f, err := folder.VirtualMachineFolderFromObject(client, vm, relative)
 	if err != nil {
 		return err
 	}
-	return folder.MoveObjectTo(vm.Reference(), f)
+	return folder.MoveObjectTo(ctx, vm.Reference(), f)
 }
 
 // Reconfigure wraps the Reconfigure task and the subsequent waiting for
 // the task to complete.
-func Reconfigure(vm *object.VirtualMachine, spec types.VirtualMachineConfigSpec, timeout time.Duration) error {
+func Reconfigure(ctx context.Context, vm *object.VirtualMachine, spec types.VirtualMachineConfigSpec, timeout time.Duration) error {
 	log.Printf("[DEBUG] Reconfiguring virtual machine %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), timeout)
-	defer cancel()
-	task, err := vm.Reconfigure(ctx, spec)
-	if err != nil {
+	err := viapi.RetryTask(ctx, vm.Client(), "ReconfigVM_Task", func() error {
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		task, err := vm.Reconfigure(ctx, spec)
+		if err != nil {
+			return err
+		}
+		tctx, tcancel := context.WithTimeout(ctx, timeout)
+		defer tcancel()
+		_, err = viapi.WaitForTask(tctx, task)
 		return err
-	}
-	tctx, tcancel := context.WithTimeout(context.Background(), timeout)
-	defer tcancel()
-	return task.Wait(tctx)
+	})
+	return deviceChangeError(err, spec.DeviceChange)
 }
 
 // Relocate wraps the Relocate task and the subsequent waiting for the task to
 // complete.
-func Relocate(vm *object.VirtualMachine, spec types.VirtualMachineRelocateSpec, timeout int) error {
+func Relocate(ctx context.Context, vm *object.VirtualMachine, spec types.VirtualMachineRelocateSpec, timeout int) error {
 	log.Printf("[DEBUG] Beginning migration of virtual machine %q (timeout %d)", vm.InventoryPath, timeout)
-	ctx, cancel := context.WithTimeout(context.Background(), time.Minute*time.Duration(timeout))
+	release, err := viapi.AcquireTask(ctx, vm.Client(), viapi.TaskClassRelocate, fmt.Sprintf("migration of %q", vm.InventoryPath))
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx, cancel := context.WithTimeout(ctx, time.Minute*time.Duration(timeout))
 	defer cancel()
 	task, err := vm.Relocate(ctx, spec, "")
 	if err != nil {
 		return err
 	}
-	if err := task.Wait(ctx); err != nil {
+	if _, err := viapi.WaitForTask(ctx, task); err != nil {
 		// Provide a friendly error message if we timed out waiting for the migration.
 		if ctx.Err() == context.DeadlineExceeded {
 			return errors.New("timeout waiting for migration to complete")
 		}
+		return err
 	}
 	return nil
 }
 
 // Destroy wraps the Destroy task and the subsequent waiting for the task to
 // complete.
-func Destroy(vm *object.VirtualMachine) error {
+func Destroy(ctx context.Context, vm *object.VirtualMachine) error {
 	log.Printf("[DEBUG] Deleting virtual machine %q", vm.InventoryPath)
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer cancel()
-	task, err := vm.Destroy(ctx)
-	if err != nil {
+	return viapi.RetryTask(ctx, vm.Client(), "Destroy_Task", func() error {
+		ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		defer cancel()
+		task, err := vm.Destroy(ctx)
+		if err != nil {
+			return err
+		}
+		tctx, tcancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
+		defer tcancel()
+		_, err = viapi.WaitForTask(tctx, task)
 		return err
-	}
-	tctx, tcancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
-	defer tcancel()
-	return task.Wait(tctx)
+	})
 }
 
 // MOIDForUUIDResult is a struct that holds a virtual machine UUID -> MOID
@@ -1017,7 +1064,7 @@ func GetHardwareVersionNumber(vstring string) int {
 
 // SetHardwareVersion sets the virtual machine's hardware version. The virtual
 // machine must be powered off, and the version can only be increased.
-func SetHardwareVersion(vm *object.VirtualMachine, target int) error {
+func SetHardwareVersion(ctx context.Context, vm *object.VirtualMachine, target int) error {
 	// First query for the configuration options of the vm
 	copts, err := ConfigOptions(vm)
 	if err != nil {
@@ -1041,7 +1088,7 @@ func SetHardwareVersion(vm *object.VirtualMachine, target int) error {
 	}
 
 	// We can now proceed to upgrade the hardware version on the vm
-	ctx, cancel := context.WithTimeout(context.Background(), provider.DefaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, provider.DefaultAPITimeout)
 	defer cancel()
 
 	log.Printf("[DEBUG] Upgrading VM from hw version %d to hw version %d", current, target)
@@ -1049,7 +1096,7 @@ func SetHardwareVersion(vm *object.VirtualMachine, target int) error {
 	if err != nil {
 		return err
 	}
-	_, err = task.WaitForResult(ctx, nil)
+	_, err = viapi.WaitForTask(ctx, task)
 	return err
 }
 
@@ -7,6 +7,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -53,7 +54,7 @@ func RemoveDiskMapping(client *govmomi.Client, host *object.HostSystem, hvs *obj
 			return err
 		}
 		task := object.NewTask(client.Client, resp.Returnval)
-		if err := task.Wait(ctx); err != nil {
+		if _, err := viapi.WaitForTask(ctx, task); err != nil {
 			return err
 		}
 	} else {
@@ -67,7 +68,7 @@ func RemoveDiskMapping(client *govmomi.Client, host *object.HostSystem, hvs *obj
 			return err
 		}
 		task := object.NewTask(client.Client, resp.Returnval)
-		if err := task.Wait(ctx); err != nil {
+		if _, err := viapi.WaitForTask(ctx, task); err != nil {
 			return err
 		}
 	}
@@ -89,5 +90,6 @@ func InitializeDisks(client *govmomi.Client, host *object.HostSystem, hvs *objec
 		return err
 	}
 	task := object.NewTask(client.Client, resp.Returnval)
-	return task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
+	return err
 }
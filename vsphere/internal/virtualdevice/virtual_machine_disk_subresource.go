@@ -1647,10 +1647,9 @@ func (r *DiskSubresource) DiffGeneral() error {
 		return fmt.Errorf("size for disk %q: required option not set", name)
 	}
 	// Block certain options from being set depending on the vSphere version.
-	version := viapi.ParseVersionFromClient(r.client)
 	if r.Get("disk_sharing").(string) != string(types.VirtualDiskSharingSharingNone) {
-		if version.Older(viapi.VSphereVersion{Product: version.Product, Major: 6}) {
-			return fmt.Errorf("multi-writer disk_sharing is only supported on vSphere 6 and higher")
+		if err := viapi.CheckCapability(r.client, viapi.CapabilityMultiWriterDiskSharing); err != nil {
+			return fmt.Errorf("disk_sharing for disk %q: %s", name, err)
 		}
 	}
 	// Prevent eagerly_scrub and thin_provisioned from both being set to true. A
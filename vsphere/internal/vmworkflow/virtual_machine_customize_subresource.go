@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/vmware/govmomi/vim25/types"
 )
@@ -141,10 +142,12 @@ func VirtualMachineCustomizeSchema() map[string]*schema.Schema {
 					Description: "Specifies how many times the VM should auto-logon the Administrator account when auto_logon is true.",
 				},
 				"admin_password": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					Description: "The new administrator password for this virtual machine.",
+					Type:             schema.TypeString,
+					Optional:         true,
+					Sensitive:        true,
+					StateFunc:        secret.StateFunc,
+					DiffSuppressFunc: secret.DiffSuppressFunc,
+					Description:      "The new administrator password for this virtual machine. Can be a file:// reference to a file with the password. Only a fingerprint of the password is saved in state.",
 				},
 				"time_zone": {
 					Type:        schema.TypeInt,
@@ -162,12 +165,14 @@ func VirtualMachineCustomizeSchema() map[string]*schema.Schema {
 					RequiredWith:  []string{cWindowsKeyPrefix + "." + "join_domain"},
 				},
 				"domain_admin_password": {
-					Type:          schema.TypeString,
-					Optional:      true,
-					Sensitive:     true,
-					ConflictsWith: []string{cWindowsKeyPrefix + "." + "workgroup"},
-					Description:   "The password of the domain administrator used to join this virtual machine to the domain.",
-					RequiredWith:  []string{cWindowsKeyPrefix + "." + "join_domain"},
+					Type:             schema.TypeString,
+					Optional:         true,
+					Sensitive:        true,
+					StateFunc:        secret.StateFunc,
+					DiffSuppressFunc: secret.DiffSuppressFunc,
+					ConflictsWith:    []string{cWindowsKeyPrefix + "." + "workgroup"},
+					Description:      "The password of the domain administrator used to join this virtual machine to the domain. Can be a file:// reference to a file with the password. Only a fingerprint of the password is saved in state.",
+					RequiredWith:     []string{cWindowsKeyPrefix + "." + "join_domain"},
 				},
 				"join_domain": {
 					Type:          schema.TypeString,
@@ -320,37 +325,45 @@ func expandCustomizationGuiRunOnce(d *schema.ResourceData) *types.CustomizationG
 
 // expandCustomizationGuiUnattended reads certain ResourceData keys and
 // returns a CustomizationGuiUnattended.
-func expandCustomizationGuiUnattended(d *schema.ResourceData) types.CustomizationGuiUnattended {
+func expandCustomizationGuiUnattended(d *schema.ResourceData) (types.CustomizationGuiUnattended, error) {
 	obj := types.CustomizationGuiUnattended{
 		TimeZone:       int32(d.Get(cWindowsKeyPrefix + "." + "time_zone").(int)),
 		AutoLogon:      d.Get(cWindowsKeyPrefix + "." + "auto_logon").(bool),
 		AutoLogonCount: int32(d.Get(cWindowsKeyPrefix + "." + "auto_logon_count").(int)),
 	}
-	if v, ok := d.GetOk(cWindowsKeyPrefix + "." + "admin_password"); ok {
+	password, err := secret.Get(d, cWindowsKeyPrefix+"."+"admin_password")
+	if err != nil {
+		return obj, err
+	}
+	if password != "" {
 		obj.Password = &types.CustomizationPassword{
-			Value:     v.(string),
+			Value:     password,
 			PlainText: true,
 		}
 	}
 
-	return obj
+	return obj, nil
 }
 
 // expandCustomizationIdentification reads certain ResourceData keys and
 // returns a CustomizationIdentification.
-func expandCustomizationIdentification(d *schema.ResourceData) types.CustomizationIdentification {
+func expandCustomizationIdentification(d *schema.ResourceData) (types.CustomizationIdentification, error) {
 	obj := types.CustomizationIdentification{
 		JoinWorkgroup: d.Get(cWindowsKeyPrefix + "." + "workgroup").(string),
 		JoinDomain:    d.Get(cWindowsKeyPrefix + "." + "join_domain").(string),
 		DomainAdmin:   d.Get(cWindowsKeyPrefix + "." + "domain_admin_user").(string),
 	}
-	if v, ok := d.GetOk(cWindowsKeyPrefix + "." + "domain_admin_password"); ok {
+	password, err := secret.Get(d, cWindowsKeyPrefix+"."+"domain_admin_password")
+	if err != nil {
+		return obj, err
+	}
+	if password != "" {
 		obj.DomainAdminPassword = &types.CustomizationPassword{
-			Value:     v.(string),
+			Value:     password,
 			PlainText: true,
 		}
 	}
-	return obj
+	return obj, nil
 }
 
 // expandCustomizationUserData reads certain ResourceData keys and
@@ -369,14 +382,22 @@ func expandCustomizationUserData(d *schema.ResourceData) types.CustomizationUser
 
 // expandCustomizationSysprep reads certain ResourceData keys and
 // returns a CustomizationSysprep.
-func expandCustomizationSysprep(d *schema.ResourceData) *types.CustomizationSysprep {
+func expandCustomizationSysprep(d *schema.ResourceData) (*types.CustomizationSysprep, error) {
+	guiUnattended, err := expandCustomizationGuiUnattended(d)
+	if err != nil {
+		return nil, err
+	}
+	identification, err := expandCustomizationIdentification(d)
+	if err != nil {
+		return nil, err
+	}
 	obj := &types.CustomizationSysprep{
-		GuiUnattended:  expandCustomizationGuiUnattended(d),
+		GuiUnattended:  guiUnattended,
 		UserData:       expandCustomizationUserData(d),
 		GuiRunOnce:     expandCustomizationGuiRunOnce(d),
-		Identification: expandCustomizationIdentification(d),
+		Identification: identification,
 	}
-	return obj
+	return obj, nil
 }
 
 // expandCustomizationSysprepText reads certain ResourceData keys and
@@ -394,7 +415,7 @@ func expandCustomizationSysprepText(d *schema.ResourceData) *types.Customization
 // Only one of the three types of identity settings can be specified: Linux
 // settings (from linux_options), Windows settings (from windows_options), and
 // the raw Windows sysprep file (via windows_sysprep_text).
-func expandBaseCustomizationIdentitySettings(d *schema.ResourceData, family string) types.BaseCustomizationIdentitySettings {
+func expandBaseCustomizationIdentitySettings(d *schema.ResourceData, family string) (types.BaseCustomizationIdentitySettings, error) {
 	var obj types.BaseCustomizationIdentitySettings
 	_, windowsExists := d.GetOkExists(cKeyPrefix + "." + "windows_options")
 	_, sysprepExists := d.GetOkExists(cKeyPrefix + "." + "windows_sysprep_text")
@@ -402,13 +423,17 @@ func expandBaseCustomizationIdentitySettings(d *schema.ResourceData, family stri
 	case family == string(types.VirtualMachineGuestOsFamilyLinuxGuest):
 		obj = expandCustomizationLinuxPrep(d)
 	case family == string(types.VirtualMachineGuestOsFamilyWindowsGuest) && windowsExists:
-		obj = expandCustomizationSysprep(d)
+		sysprep, err := expandCustomizationSysprep(d)
+		if err != nil {
+			return nil, err
+		}
+		obj = sysprep
 	case family == string(types.VirtualMachineGuestOsFamilyWindowsGuest) && sysprepExists:
 		obj = expandCustomizationSysprepText(d)
 	default:
 		obj = &types.CustomizationIdentitySettings{}
 	}
-	return obj
+	return obj, nil
 }
 
 // expandCustomizationIPSettingsIPV6AddressSpec reads certain ResourceData keys and
@@ -487,13 +512,50 @@ func expandSliceOfCustomizationAdapterMapping(d *schema.ResourceData) []types.Cu
 
 // ExpandCustomizationSpec reads certain ResourceData keys and
 // returns a CustomizationSpec.
-func ExpandCustomizationSpec(d *schema.ResourceData, family string) types.CustomizationSpec {
+func ExpandCustomizationSpec(d *schema.ResourceData, family string) (types.CustomizationSpec, error) {
+	identity, err := expandBaseCustomizationIdentitySettings(d, family)
+	if err != nil {
+		return types.CustomizationSpec{}, err
+	}
 	obj := types.CustomizationSpec{
-		Identity:         expandBaseCustomizationIdentitySettings(d, family),
+		Identity:         identity,
 		GlobalIPSettings: expandCustomizationGlobalIPSettings(d),
 		NicSettingMap:    expandSliceOfCustomizationAdapterMapping(d),
 	}
-	return obj
+	return obj, nil
+}
+
+// FingerprintCustomizationPasswords replaces the Windows customization
+// passwords in the clone block of d with their fingerprints. The passwords
+// are sent to vSphere when the virtual machine is cloned and never read
+// back, so the fingerprints are all that the clone block keeps of them. A
+// fingerprint made with another key is made again with the current one.
+func FingerprintCustomizationPasswords(d *schema.ResourceData) error {
+	clone := d.Get("clone").([]interface{})
+	if len(clone) == 0 || clone[0] == nil {
+		return nil
+	}
+	customize, _ := clone[0].(map[string]interface{})["customize"].([]interface{})
+	if len(customize) == 0 || customize[0] == nil {
+		return nil
+	}
+	windowsOptions, _ := customize[0].(map[string]interface{})["windows_options"].([]interface{})
+	if len(windowsOptions) == 0 || windowsOptions[0] == nil {
+		return nil
+	}
+	options := windowsOptions[0].(map[string]interface{})
+	var changed bool
+	for _, k := range []string{"admin_password", "domain_admin_password"} {
+		v, _ := options[k].(string)
+		if fingerprint := secret.Refingerprint(d, cWindowsKeyPrefix+"."+k, v); fingerprint != v {
+			options[k] = fingerprint
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	return d.Set("clone", clone)
 }
 
 // ValidateCustomizationSpec checks the validity of the supplied customization
@@ -0,0 +1,372 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/dvportgroup"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/resourcepool"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// GenerateOptions are the options of GenerateConfig.
+type GenerateOptions struct {
+	// Datacenter is the name or inventory path of the datacenter to generate
+	// the configuration for. The default datacenter is used when it is empty.
+	Datacenter string
+}
+
+// generatedResource is an inventory object that has been read into the state
+// of the resource that manages it, ready to be written as configuration.
+type generatedResource struct {
+	typeName string
+	name     string
+	resource *schema.Resource
+	data     *schema.ResourceData
+
+	// importID is the ID that the resource is imported with.
+	importID string
+
+	// entity is the managed object of the resource, if it has one.
+	entity types.ManagedObjectReference
+}
+
+// inventoryGenerator walks an inventory and collects the resources read from
+// it.
+type inventoryGenerator struct {
+	provider *schema.Provider
+	client   *Client
+
+	// networkFolder is the inventory path of the network folder of the
+	// datacenter.
+	networkFolder string
+
+	resources []*generatedResource
+	skipped   []string
+	names     map[string]map[string]bool
+}
+
+// GenerateConfig walks a datacenter and writes the configuration of the
+// folders, clusters, resource pools, distributed switches and port groups,
+// virtual machines and permissions in it, as well as of the tags, tag
+// categories and roles of the vCenter, to w. An import block is written for
+// every resource, so that the configuration can be adopted with a single plan
+// and apply.
+//
+// p must be a provider returned by Provider that has been configured. Objects
+// are read with the importers and read functions of their resources, so that
+// the configuration written matches what the provider reads and plans without
+// changes. Objects that cannot be read are skipped and listed in comments at
+// the end of the configuration.
+func GenerateConfig(ctx context.Context, p *schema.Provider, opts GenerateOptions, w io.Writer) error {
+	client, ok := p.Meta().(*Client)
+	if !ok || client == nil {
+		return fmt.Errorf("provider is not configured")
+	}
+	dc, err := getDatacenter(client.vimClient, opts.Datacenter)
+	if err != nil {
+		return fmt.Errorf("cannot locate datacenter: %s", err)
+	}
+	log.Printf("[DEBUG] Generating configuration for datacenter %q", dc.InventoryPath)
+
+	g := &inventoryGenerator{
+		provider:      p,
+		client:        client,
+		networkFolder: path.Join(dc.InventoryPath, "network"),
+		names:         make(map[string]map[string]bool),
+	}
+	if err := g.generateTags(ctx); err != nil {
+		return err
+	}
+	if err := g.generateRoles(ctx); err != nil {
+		return err
+	}
+	for _, root := range []string{"vm", "host", "datastore", "network"} {
+		if err := g.walk(ctx, path.Join(dc.InventoryPath, root)); err != nil {
+			return err
+		}
+	}
+	if err := g.generatePermissions(ctx, dc.Reference()); err != nil {
+		return err
+	}
+
+	return writeGeneratedConfig(w, dc, g.resources, g.skipped)
+}
+
+// walk generates the resources for the children of the inventory path p,
+// recursing into folders, clusters and resource pools.
+func (g *inventoryGenerator) walk(ctx context.Context, p string) error {
+	finder := find.NewFinder(g.client.vimClient.Client, false)
+	children, err := finder.ManagedObjectListChildren(ctx, p)
+	if err != nil {
+		return fmt.Errorf("error listing the children of %q: %s", p, err)
+	}
+	for _, child := range children {
+		// Objects without children are listed as their own child.
+		if child.Path == p {
+			continue
+		}
+		ref := child.Object.Reference()
+		// Clusters and hosts list the networks they are connected to as their
+		// children. These are generated from the network folder instead.
+		isNetwork := ref.Type == "VmwareDistributedVirtualSwitch" || ref.Type == "DistributedVirtualPortgroup"
+		if isNetwork && !strings.HasPrefix(child.Path, g.networkFolder+"/") {
+			continue
+		}
+		var typeName string
+		recurse := false
+		switch ref.Type {
+		case "Folder":
+			typeName, recurse = "vsphere_folder", true
+		case "ClusterComputeResource":
+			typeName, recurse = "vsphere_compute_cluster", true
+		case "ComputeResource":
+			recurse = true
+		case "ResourcePool":
+			// Only the root resource pool of a cluster or standalone host is
+			// listed here, and it is managed along with them.
+			if err := g.walkResourcePool(ctx, child.Path, ref); err != nil {
+				return err
+			}
+		case "VmwareDistributedVirtualSwitch":
+			typeName = "vsphere_distributed_virtual_switch"
+		case "DistributedVirtualPortgroup":
+			pg := object.NewDistributedVirtualPortgroup(g.client.vimClient.Client, ref)
+			props, err := dvportgroup.Properties(pg, "config.uplink", "config.distributedVirtualSwitch")
+			if err != nil {
+				return fmt.Errorf("error fetching properties of port group %q: %s", child.Path, err)
+			}
+			// Uplink port groups are managed by their switch, and only the port
+			// groups of VMware distributed switches can be managed.
+			uplink := props.Config.Uplink != nil && *props.Config.Uplink
+			dvs := props.Config.DistributedVirtualSwitch
+			if !uplink && dvs != nil && dvs.Type == "VmwareDistributedVirtualSwitch" {
+				typeName = "vsphere_distributed_port_group"
+			}
+		case "VirtualMachine":
+			vm := object.NewVirtualMachine(g.client.vimClient.Client, ref)
+			props, err := virtualmachine.Properties(vm, "config.template")
+			if err != nil {
+				return fmt.Errorf("error fetching properties of virtual machine %q: %s", child.Path, err)
+			}
+			// Templates cannot be imported.
+			if props.Config != nil && !props.Config.Template {
+				typeName = "vsphere_virtual_machine"
+			}
+		}
+		if typeName != "" {
+			g.importResource(ctx, typeName, generatedNameForPath(child.Path), child.Path, ref)
+		}
+		if recurse {
+			if err := g.walk(ctx, child.Path); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walkResourcePool generates the resources for the child resource pools of the
+// resource pool ref at the inventory path p, and recurses into them. The
+// children are read from the pool instead of being listed, as listing the
+// children of a resource pool also lists the virtual machines in it.
+func (g *inventoryGenerator) walkResourcePool(ctx context.Context, p string, ref types.ManagedObjectReference) error {
+	props, err := resourcepool.Properties(object.NewResourcePool(g.client.vimClient.Client, ref), "resourcePool")
+	if err != nil {
+		return fmt.Errorf("error fetching properties of resource pool %q: %s", p, err)
+	}
+	for _, childRef := range props.ResourcePool {
+		// vApps are resource pools as well, but are not generated.
+		if childRef.Type != "ResourcePool" {
+			continue
+		}
+		child, err := resourcepool.Properties(object.NewResourcePool(g.client.vimClient.Client, childRef), "name")
+		if err != nil {
+			return fmt.Errorf("error fetching properties of resource pool %q: %s", childRef.Value, err)
+		}
+		childPath := path.Join(p, child.Name)
+		g.importResource(ctx, "vsphere_resource_pool", generatedNameForPath(childPath), childPath, childRef)
+		if err := g.walkResourcePool(ctx, childPath, childRef); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// generateTags generates the tag categories and tags of the vCenter. Nothing is
+// generated when the connection does not support tags.
+func (g *inventoryGenerator) generateTags(ctx context.Context) error {
+	tm, err := g.client.TagsManager()
+	if err != nil {
+		log.Printf("[DEBUG] Not generating tags: %s", err)
+		return nil
+	}
+	categories, err := tm.GetCategories(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing tag categories: %s", err)
+	}
+	categoryNames := make(map[string]string)
+	for _, category := range categories {
+		categoryNames[category.ID] = category.Name
+		g.importResource(ctx, "vsphere_tag_category", generatedName(category.Name), category.Name, types.ManagedObjectReference{})
+	}
+	tags, err := tm.GetTags(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing tags: %s", err)
+	}
+	for _, tag := range tags {
+		id, err := json.Marshal(map[string]string{
+			"category_name": categoryNames[tag.CategoryID],
+			"tag_name":      tag.Name,
+		})
+		if err != nil {
+			return err
+		}
+		g.importResource(ctx, "vsphere_tag", generatedName(categoryNames[tag.CategoryID]+"_"+tag.Name), string(id), types.ManagedObjectReference{})
+	}
+	return nil
+}
+
+// generateRoles generates the roles of the vCenter. System roles cannot be
+// managed, and are skipped.
+func (g *inventoryGenerator) generateRoles(ctx context.Context) error {
+	roles, err := object.NewAuthorizationManager(g.client.vimClient.Client).RoleList(ctx)
+	if err != nil {
+		return fmt.Errorf("error listing roles: %s", err)
+	}
+	for _, role := range roles {
+		if role.System {
+			continue
+		}
+		g.importResource(ctx, "vsphere_role", generatedName(role.Name), strconv.Itoa(int(role.RoleId)), types.ManagedObjectReference{})
+	}
+	return nil
+}
+
+// generatePermissions generates the permissions set on dc and on the objects
+// that resources were generated for. Inherited permissions are left out, as
+// they are generated for the object they are set on.
+func (g *inventoryGenerator) generatePermissions(ctx context.Context, dc types.ManagedObjectReference) error {
+	am := object.NewAuthorizationManager(g.client.vimClient.Client)
+	entities := []types.ManagedObjectReference{dc}
+	names := map[types.ManagedObjectReference]string{dc: "datacenter"}
+	for _, r := range g.resources {
+		if r.entity.Value != "" {
+			entities = append(entities, r.entity)
+			names[r.entity] = r.name
+		}
+	}
+	for _, entity := range entities {
+		permissions, err := am.RetrieveEntityPermissions(ctx, entity, false)
+		if err != nil {
+			return fmt.Errorf("error listing permissions of %q: %s", entity.Value, err)
+		}
+		if len(permissions) == 0 {
+			continue
+		}
+		g.importResource(ctx, "vsphere_entity_permissions", names[entity], entity.Type+":"+entity.Value, types.ManagedObjectReference{})
+	}
+	return nil
+}
+
+// importResource imports the object with importID into a resource of type
+// typeName, and adds the resource to the generated resources. entity is the
+// managed object of the resource, if it has one.
+func (g *inventoryGenerator) importResource(ctx context.Context, typeName, name, importID string, entity types.ManagedObjectReference) {
+	r := g.provider.ResourcesMap[typeName]
+	d := r.Data(nil)
+	d.SetId(importID)
+	imported, err := r.Importer.StateContext(ctx, d, g.client)
+	if err != nil {
+		g.skip(typeName, importID, err)
+		return
+	}
+	if gr := g.readResource(ctx, typeName, name, importID, imported[0]); gr != nil {
+		gr.entity = entity
+	}
+}
+
+// readResource reads d with the read function of the resource of type
+// typeName, and adds it to the generated resources.
+func (g *inventoryGenerator) readResource(ctx context.Context, typeName, name, importID string, d *schema.ResourceData) *generatedResource {
+	r := g.provider.ResourcesMap[typeName]
+	if err := diagnosticsError(r.ReadContext(ctx, d, g.client)); err != nil {
+		g.skip(typeName, importID, err)
+		return nil
+	}
+	if d.Id() == "" {
+		return nil
+	}
+	names, ok := g.names[typeName]
+	if !ok {
+		names = make(map[string]bool)
+		g.names[typeName] = names
+	}
+	unique := name
+	for i := 2; names[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", name, i)
+	}
+	names[unique] = true
+
+	gr := &generatedResource{
+		typeName: typeName,
+		name:     unique,
+		resource: r,
+		data:     d,
+		importID: importID,
+	}
+	g.resources = append(g.resources, gr)
+	return gr
+}
+
+// skip records an object that could not be read.
+func (g *inventoryGenerator) skip(typeName, importID string, err error) {
+	log.Printf("[WARN] Skipping %s %q: %s", typeName, importID, err)
+	g.skipped = append(g.skipped, fmt.Sprintf("%s %q: %s", typeName, importID, err))
+}
+
+// generatedNameForPath returns the resource name for the object at the
+// inventory path p. The path is used from below the root folder of the
+// datacenter, so that objects with the same name in different folders get
+// different names.
+func generatedNameForPath(p string) string {
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	if len(parts) > 2 {
+		parts = parts[2:]
+	}
+	return generatedName(strings.Join(parts, "_"))
+}
+
+// generatedName turns s into a valid Terraform resource name.
+func generatedName(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_', r == '-':
+			b.WriteRune(r)
+		default:
+			b.WriteRune('_')
+		}
+	}
+	name := strings.Trim(b.String(), "_")
+	switch {
+	case name == "":
+		return "unnamed"
+	case name[0] >= '0' && name[0] <= '9', name[0] == '-':
+		return "_" + name
+	}
+	return name
+}
@@ -0,0 +1,280 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/govmomi/object"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// generatedReferences maps the IDs of generated resources and data sources to
+// the expressions that refer to them, so that generated attributes holding one
+// of these IDs refer to the resource instead of repeating the ID.
+type generatedReferences map[string]hcl.Traversal
+
+// traversal returns the expression to use for the value v of the attribute k,
+// if the attribute holds the ID of another generated resource.
+func (refs generatedReferences) traversal(k string, v interface{}) (hcl.Traversal, bool) {
+	s, ok := v.(string)
+	if !ok || s == "" {
+		return nil, false
+	}
+	if !strings.HasSuffix(k, "_id") && !strings.HasSuffix(k, "_ids") && !strings.HasSuffix(k, "_uuid") && k != "tags" {
+		return nil, false
+	}
+	t, ok := refs[s]
+	return t, ok
+}
+
+// writeGeneratedConfig writes the configuration of resources to w, along with
+// a data source for dc and an import block for every resource. skipped lists
+// the objects that could not be generated.
+func writeGeneratedConfig(w io.Writer, dc *object.Datacenter, resources []*generatedResource, skipped []string) error {
+	f := hclwrite.NewEmptyFile()
+	body := f.Body()
+
+	dcName := generatedName(dc.Name())
+	dcBlock := body.AppendNewBlock("data", []string{"vsphere_datacenter", dcName})
+	dcBlock.Body().SetAttributeValue("name", cty.StringVal(dc.Name()))
+
+	refs := generatedReferences{
+		dc.Reference().Value: hcl.Traversal{
+			hcl.TraverseRoot{Name: "data"},
+			hcl.TraverseAttr{Name: "vsphere_datacenter"},
+			hcl.TraverseAttr{Name: dcName},
+			hcl.TraverseAttr{Name: "id"},
+		},
+	}
+	for _, r := range resources {
+		// Permissions are keyed on the ID of the object they are set on.
+		if r.typeName == "vsphere_entity_permissions" {
+			continue
+		}
+		refs[r.data.Id()] = hcl.Traversal{
+			hcl.TraverseRoot{Name: r.typeName},
+			hcl.TraverseAttr{Name: r.name},
+			hcl.TraverseAttr{Name: "id"},
+		}
+		// The root resource pool of a cluster is not generated, and is referred
+		// to through the cluster instead.
+		if r.typeName == "vsphere_compute_cluster" {
+			refs[r.data.Get("resource_pool_id").(string)] = hcl.Traversal{
+				hcl.TraverseRoot{Name: r.typeName},
+				hcl.TraverseAttr{Name: r.name},
+				hcl.TraverseAttr{Name: "resource_pool_id"},
+			}
+		}
+	}
+
+	for _, r := range resources {
+		body.AppendNewline()
+		block := body.AppendNewBlock("resource", []string{r.typeName, r.name})
+		values := make(map[string]interface{})
+		for k := range r.resource.Schema {
+			values[k] = r.data.Get(k)
+		}
+		writeGeneratedBody(block.Body(), r.resource.Schema, values, refs, r.typeName+"."+r.name)
+
+		body.AppendNewline()
+		importBlock := body.AppendNewBlock("import", nil)
+		importBlock.Body().SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: r.typeName},
+			hcl.TraverseAttr{Name: r.name},
+		})
+		importBlock.Body().SetAttributeValue("id", cty.StringVal(r.importID))
+	}
+
+	if len(skipped) > 0 {
+		body.AppendNewline()
+		body.AppendUnstructuredTokens(hclwrite.Tokens{
+			{Type: hclsyntax.TokenComment, Bytes: []byte("# The following objects could not be read and were skipped:\n")},
+		})
+		for _, s := range skipped {
+			body.AppendUnstructuredTokens(hclwrite.Tokens{
+				{Type: hclsyntax.TokenComment, Bytes: []byte(fmt.Sprintf("# - %s\n", strings.ReplaceAll(s, "\n", " ")))},
+			})
+		}
+	}
+
+	_, err := f.WriteTo(w)
+	return err
+}
+
+// generatedAddress returns the string form of the traversal t, such as
+// vsphere_folder.prod.id.
+func generatedAddress(t hcl.Traversal) string {
+	parts := []string{t.RootName()}
+	for _, step := range t.SimpleSplit().Rel {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			parts = append(parts, attr.Name)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// writeGeneratedBody writes the values of the attributes and blocks of the
+// schema s to body. Attributes are written in alphabetical order, followed by
+// blocks. self is the address of the resource being written, which never
+// refers to itself.
+//
+// Only the attributes that can be configured are written, and attributes that
+// are not set or hold their default value are left out, as setting them does
+// not change the plan.
+func writeGeneratedBody(body *hclwrite.Body, s map[string]*schema.Schema, values map[string]interface{}, refs generatedReferences, self string) {
+	keys := make([]string, 0, len(s))
+	for k := range s {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	written := make(map[string]bool)
+	var blocks []string
+	for _, k := range keys {
+		sch := s[k]
+		if !generatedAttribute(sch) {
+			continue
+		}
+		if _, ok := sch.Elem.(*schema.Resource); ok {
+			blocks = append(blocks, k)
+			continue
+		}
+		v := values[k]
+		if !sch.Required && generatedDefault(sch, v) {
+			continue
+		}
+		conflicts := false
+		for _, c := range sch.ConflictsWith {
+			if written[c] {
+				conflicts = true
+			}
+		}
+		if conflicts {
+			continue
+		}
+		written[k] = true
+		body.SetAttributeRaw(k, generatedValueTokens(k, sch, v, refs, self))
+	}
+
+	for _, k := range blocks {
+		sch := s[k]
+		elem := sch.Elem.(*schema.Resource)
+		for _, item := range generatedList(values[k]) {
+			m, ok := item.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			block := body.AppendNewBlock(k, nil)
+			writeGeneratedBody(block.Body(), elem.Schema, m, refs, self)
+		}
+	}
+}
+
+// generatedAttribute returns true if the attribute with schema sch can be
+// written to the configuration.
+func generatedAttribute(sch *schema.Schema) bool {
+	if sch.Computed && !sch.Optional {
+		return false
+	}
+	// Deprecated attributes have replacements that are read as well, and
+	// secrets cannot be read back.
+	return sch.Deprecated == "" && !sch.Sensitive
+}
+
+// generatedDefault returns true if v is the default value of the attribute with
+// schema sch, or the zero value of an attribute without a default.
+func generatedDefault(sch *schema.Schema, v interface{}) bool {
+	if sch.Default != nil {
+		return reflect.DeepEqual(v, sch.Default)
+	}
+	switch sch.Type {
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		return generatedLen(v) == 0
+	}
+	return v == nil || reflect.ValueOf(v).IsZero()
+}
+
+// generatedLen returns the number of elements of the list, set or map v.
+func generatedLen(v interface{}) int {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return len(v)
+	default:
+		return len(generatedList(v))
+	}
+}
+
+// generatedList returns the elements of the list or set v.
+func generatedList(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		return v
+	case *schema.Set:
+		return v.List()
+	}
+	return nil
+}
+
+// generatedValueTokens returns the expression for the value v of the
+// attribute k with schema sch.
+func generatedValueTokens(k string, sch *schema.Schema, v interface{}, refs generatedReferences, self string) hclwrite.Tokens {
+	switch sch.Type {
+	case schema.TypeList, schema.TypeSet:
+		elemSchema, ok := sch.Elem.(*schema.Schema)
+		if !ok {
+			elemSchema = &schema.Schema{Type: schema.TypeString}
+		}
+		items := generatedList(v)
+		elems := make([]hclwrite.Tokens, 0, len(items))
+		for _, item := range items {
+			elems = append(elems, generatedValueTokens(k, elemSchema, item, refs, self))
+		}
+		if sch.Type == schema.TypeSet {
+			// Sets have no order, so sort them to keep the output stable.
+			sort.Slice(elems, func(i, j int) bool {
+				return string(elems[i].Bytes()) < string(elems[j].Bytes())
+			})
+		}
+		return hclwrite.TokensForTuple(elems)
+	case schema.TypeMap:
+		m, _ := v.(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for mk := range m {
+			keys = append(keys, mk)
+		}
+		sort.Strings(keys)
+		attrs := make([]hclwrite.ObjectAttrTokens, 0, len(keys))
+		for _, mk := range keys {
+			attrs = append(attrs, hclwrite.ObjectAttrTokens{
+				Name:  hclwrite.TokensForValue(cty.StringVal(mk)),
+				Value: hclwrite.TokensForValue(cty.StringVal(fmt.Sprint(m[mk]))),
+			})
+		}
+		return hclwrite.TokensForObject(attrs)
+	}
+
+	if t, ok := refs.traversal(k, v); ok && !strings.HasPrefix(generatedAddress(t), self+".") {
+		return hclwrite.TokensForTraversal(t)
+	}
+	switch v := v.(type) {
+	case string:
+		return hclwrite.TokensForValue(cty.StringVal(v))
+	case int:
+		return hclwrite.TokensForValue(cty.NumberIntVal(int64(v)))
+	case float64:
+		return hclwrite.TokensForValue(cty.NumberFloatVal(v))
+	case bool:
+		return hclwrite.TokensForValue(cty.BoolVal(v))
+	}
+	return hclwrite.TokensForValue(cty.NullVal(cty.DynamicPseudoType))
+}
@@ -0,0 +1,398 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	sdkctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hcldec"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/types"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+)
+
+func TestGeneratedName(t *testing.T) {
+	cases := map[string]string{
+		"web-01":       "web-01",
+		"Prod VMs":     "prod_vms",
+		"01-db":        "_01-db",
+		"/DC0/vm/a/b":  "dc0_vm_a_b",
+		"!!!":          "unnamed",
+		"Team (Ops) 2": "team__ops__2",
+	}
+	for in, expected := range cases {
+		if actual := generatedName(in); actual != expected {
+			t.Errorf("generatedName(%q): expected %q, got %q", in, expected, actual)
+		}
+	}
+	if actual := generatedNameForPath("/DC0/vm/prod/web"); actual != "prod_web" {
+		t.Errorf("expected prod_web, got %q", actual)
+	}
+}
+
+func TestGenerateConfig(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+		dc, err := finder.Datacenter(ctx, "DC0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		folders, err := dc.Folders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		prod, err := folders.VmFolder.CreateFolder(ctx, "prod")
+		if err != nil {
+			t.Fatal(err)
+		}
+		pool, err := finder.ResourcePool(ctx, "/DC0/host/DC0_C0/Resources")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := pool.Create(ctx, "batch", types.DefaultResourceConfigSpec()); err != nil {
+			t.Fatal(err)
+		}
+		am := object.NewAuthorizationManager(c)
+		roleID, err := am.AddRole(ctx, "operators", []string{"VirtualMachine.Interact.PowerOn"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = am.SetEntityPermissions(ctx, prod.Reference(), []types.Permission{
+			{Principal: "ops@vsphere.local", RoleId: roleID, Propagate: true},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		rc := rest.NewClient(c)
+		if err := rc.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		tm := tags.NewManager(rc)
+		categoryID, err := tm.CreateCategory(ctx, &tags.Category{Name: "env", Cardinality: "SINGLE", AssociableTypes: []string{"VirtualMachine"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tm.CreateTag(ctx, &tags.Tag{Name: "prod", CategoryID: categoryID}); err != nil {
+			t.Fatal(err)
+		}
+
+		p := testSimulatorProvider(ctx, t, c)
+		var out bytes.Buffer
+		if err := GenerateConfig(ctx, p, GenerateOptions{Datacenter: "DC0"}, &out); err != nil {
+			t.Fatal(err)
+		}
+		config := out.String()
+
+		f, hclDiags := hclwrite.ParseConfig(out.Bytes(), "generated.tf", hcl.InitialPos)
+		if hclDiags.HasErrors() {
+			t.Fatalf("generated configuration does not parse: %s\n%s", hclDiags, config)
+		}
+		addresses := make(map[string]bool)
+		imports := 0
+		for _, block := range f.Body().Blocks() {
+			switch block.Type() {
+			case "resource":
+				addresses[strings.Join(block.Labels(), ".")] = true
+			case "import":
+				imports++
+			}
+		}
+		// The virtual machines and clusters of the simulator cannot be read by
+		// their resources as they are, and are skipped. They are covered by
+		// TestGenerateConfigRoundTrip.
+		for _, expected := range []string{
+			"vsphere_folder.prod",
+			"vsphere_resource_pool.dc0_c0_resources_batch",
+			"vsphere_tag_category.env",
+			"vsphere_tag.env_prod",
+			"vsphere_role.operators",
+			"vsphere_entity_permissions.prod",
+		} {
+			if !addresses[expected] {
+				t.Errorf("expected %s in the generated configuration:\n%s", expected, config)
+			}
+		}
+		if imports != len(addresses) {
+			t.Errorf("expected %d import blocks, got %d", len(addresses), imports)
+		}
+		for _, expected := range []string{
+			"datacenter_id = data.vsphere_datacenter.dc0.id",
+			"category_id = vsphere_tag_category.env.id",
+			"entity_id   = vsphere_folder.prod.id",
+			"role_id       = vsphere_role.operators.id",
+		} {
+			if !strings.Contains(config, expected) {
+				t.Errorf("expected %q in the generated configuration:\n%s", expected, config)
+			}
+		}
+		if strings.Contains(config, "vsphere_resource_pool.dc0_c0_resources ") {
+			t.Errorf("expected the root resource pool to be skipped:\n%s", config)
+		}
+	})
+}
+
+// TestGenerateConfigRoundTrip checks that the configuration generated for a
+// virtual machine and a cluster imports and plans without changes, the way
+// Terraform imports and plans it.
+func TestGenerateConfigRoundTrip(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		finder := find.NewFinder(c)
+		dc, err := finder.Datacenter(ctx, "DC0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		p := testSimulatorProvider(ctx, t, c)
+
+		// The hosts of the simulator have no vSAN system, which the cluster
+		// reads for each of its hosts, so an empty cluster is created. It is
+		// configured the way the provider creates it, as the simulator does not
+		// fill in the settings that vCenter Server defaults.
+		folders, err := dc.Folders(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := schema.TestResourceDataRaw(t, p.ResourcesMap["vsphere_compute_cluster"].Schema, map[string]interface{}{
+			"name":          "batch",
+			"datacenter_id": dc.Reference().Value,
+		})
+		spec := expandClusterConfigSpecEx(d, viapi.ParseVersionFromClient(p.Meta().(*Client).vimClient))
+		cluster, err := folders.HostFolder.CreateCluster(ctx, "batch", types.ClusterConfigSpecEx{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The simulator only applies rules, groups and overrides, so the other
+		// settings are set on its object instead.
+		if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+			t.Fatal(err)
+		}
+		info := simulator.Map.Get(cluster.Reference()).(*simulator.ClusterComputeResource).ConfigurationEx.(*types.ClusterConfigInfoEx)
+		info.DasConfig = *spec.DasConfig
+		info.DrsConfig = *spec.DrsConfig
+		info.DpmConfigInfo = spec.DpmConfig
+		info.Orchestration = spec.Orchestration
+		info.InfraUpdateHaConfig = spec.InfraUpdateHaConfig
+		info.ProactiveDrsConfig = spec.ProactiveDrsConfig
+
+		// The simulator puts the CD-ROM and the disk of its virtual machines on
+		// the same unit of the SCSI controller, so that the disk cannot be told
+		// apart, and leaves settings empty that vCenter Server reports. The
+		// CD-ROM is removed and the settings are set to their defaults.
+		vm, err := finder.VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		devices, err := vm.Device(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		vmSpec := types.VirtualMachineConfigSpec{
+			Flags: &types.VirtualMachineFlagInfo{
+				VirtualMmuUsage:  "automatic",
+				VirtualExecUsage: "hvAuto",
+			},
+			Tools: &types.ToolsConfigInfo{ToolsUpgradePolicy: "manual"},
+		}
+		for _, device := range devices.SelectByType((*types.VirtualCdrom)(nil)) {
+			vmSpec.DeviceChange = append(vmSpec.DeviceChange, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationRemove,
+				Device:    device,
+			})
+		}
+		for _, device := range devices.SelectByType((*types.VirtualDisk)(nil)) {
+			disk := device.(*types.VirtualDisk)
+			disk.Shares = &types.SharesInfo{Level: types.SharesLevelNormal}
+			disk.StorageIOAllocation = &types.StorageIOAllocationInfo{
+				Limit:  types.NewInt64(-1),
+				Shares: &types.SharesInfo{Level: types.SharesLevelNormal, Shares: 1000},
+			}
+			backing := disk.Backing.(*types.VirtualDiskFlatVer2BackingInfo)
+			backing.Sharing = string(types.VirtualDiskSharingSharingNone)
+			vmSpec.DeviceChange = append(vmSpec.DeviceChange, &types.VirtualDeviceConfigSpec{
+				Operation: types.VirtualDeviceConfigSpecOperationEdit,
+				Device:    disk,
+			})
+		}
+		task, err := vm.Reconfigure(ctx, vmSpec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := task.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+		// The simulator does not apply the swap placement of a reconfiguration.
+		simulator.Map.Get(vm.Reference()).(*simulator.VirtualMachine).Config.SwapPlacement = "inherit"
+
+		var out bytes.Buffer
+		if err := GenerateConfig(ctx, p, GenerateOptions{Datacenter: "DC0"}, &out); err != nil {
+			t.Fatal(err)
+		}
+		config := out.String()
+
+		f, diags := hclsyntax.ParseConfig(out.Bytes(), "generated.tf", hcl.InitialPos)
+		if diags.HasErrors() {
+			t.Fatalf("generated configuration does not parse: %s\n%s", diags, config)
+		}
+		blocks := make(map[string]*hclsyntax.Block)
+		importIDs := make(map[string]string)
+		for _, block := range f.Body.(*hclsyntax.Body).Blocks {
+			switch block.Type {
+			case "resource":
+				blocks[strings.Join(block.Labels, ".")] = block
+			case "import":
+				to, diags := hcl.AbsTraversalForExpr(block.Body.Attributes["to"].Expr)
+				if diags.HasErrors() {
+					t.Fatal(diags)
+				}
+				id, diags := block.Body.Attributes["id"].Expr.Value(nil)
+				if diags.HasErrors() {
+					t.Fatal(diags)
+				}
+				importIDs[generatedAddress(to)] = id.AsString()
+			}
+		}
+
+		// Import every resource, the way the import blocks do, and make the
+		// refreshed state available to the references between resources.
+		meta := p.Meta()
+		states := make(map[string]*terraform.InstanceState)
+		vars := map[string]map[string]cty.Value{
+			"data": {
+				"vsphere_datacenter": cty.ObjectVal(map[string]cty.Value{
+					"dc0": cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal(dc.Reference().Value)}),
+				}),
+			},
+		}
+		for address, block := range blocks {
+			r := p.ResourcesMap[block.Labels[0]]
+			d := r.Data(nil)
+			d.SetId(importIDs[address])
+			imported, err := r.Importer.StateContext(ctx, d, meta)
+			if err != nil {
+				t.Fatalf("error importing %s: %s", address, err)
+			}
+			state, readDiags := r.RefreshWithoutUpgrade(ctx, imported[0].State(), meta)
+			if readDiags.HasError() {
+				t.Fatalf("error reading %s: %#v", address, readDiags)
+			}
+			states[address] = state
+			attrs := make(map[string]cty.Value)
+			for k, v := range state.Attributes {
+				if !strings.Contains(k, ".") {
+					attrs[k] = cty.StringVal(v)
+				}
+			}
+			if vars[block.Labels[0]] == nil {
+				vars[block.Labels[0]] = make(map[string]cty.Value)
+			}
+			vars[block.Labels[0]][block.Labels[1]] = cty.ObjectVal(attrs)
+		}
+		evalCtx := &hcl.EvalContext{Variables: make(map[string]cty.Value)}
+		for k, v := range vars {
+			evalCtx.Variables[k] = cty.ObjectVal(v)
+		}
+
+		for _, expected := range []string{
+			"vsphere_virtual_machine.dc0_h0_vm0",
+			"vsphere_compute_cluster.batch",
+		} {
+			if blocks[expected] == nil {
+				t.Errorf("expected %s in the generated configuration:\n%s", expected, config)
+			}
+		}
+		for address, block := range blocks {
+			r := p.ResourcesMap[block.Labels[0]]
+			val, diags := hcldec.Decode(block.Body, testGenerateDecoderSpec(r.Schema), evalCtx)
+			if diags.HasErrors() {
+				t.Errorf("error decoding %s: %s", address, diags)
+				continue
+			}
+			// The SDK has its own copy of cty, which the value is passed to
+			// through JSON.
+			b, err := ctyjson.Marshal(val, val.Type())
+			if err != nil {
+				t.Fatal(err)
+			}
+			sdkVal, err := sdkctyjson.Unmarshal(b, r.CoreConfigSchema().ImpliedType())
+			if err != nil {
+				t.Errorf("error decoding %s: %s", address, err)
+				continue
+			}
+			rc := terraform.NewResourceConfigShimmed(sdkVal, r.CoreConfigSchema())
+			if diags := r.Validate(rc); diags.HasError() {
+				for _, d := range diags {
+					t.Errorf("invalid configuration for %s: %s: %s", address, d.Summary, d.Detail)
+				}
+				continue
+			}
+			diff, err := r.SimpleDiff(ctx, states[address], rc, meta)
+			if err != nil {
+				t.Errorf("error planning %s: %s", address, err)
+				continue
+			}
+			for k, attr := range diff.Attributes {
+				t.Errorf("expected no changes to %s, got %s: %q => %q", address, k, attr.Old, attr.New)
+			}
+		}
+	})
+}
+
+// testGenerateDecoderSpec returns the spec to decode a configuration body of
+// the schema s with.
+func testGenerateDecoderSpec(s map[string]*schema.Schema) hcldec.Spec {
+	spec := make(hcldec.ObjectSpec)
+	for k, sch := range s {
+		if elem, ok := sch.Elem.(*schema.Resource); ok {
+			nested := testGenerateDecoderSpec(elem.Schema)
+			if sch.Type == schema.TypeSet {
+				spec[k] = &hcldec.BlockSetSpec{TypeName: k, Nested: nested}
+			} else {
+				spec[k] = &hcldec.BlockListSpec{TypeName: k, Nested: nested}
+			}
+			continue
+		}
+		spec[k] = &hcldec.AttrSpec{Name: k, Type: testGenerateType(sch)}
+	}
+	return spec
+}
+
+// testGenerateType returns the type of the attribute with schema sch.
+func testGenerateType(sch *schema.Schema) cty.Type {
+	switch sch.Type {
+	case schema.TypeBool:
+		return cty.Bool
+	case schema.TypeInt, schema.TypeFloat:
+		return cty.Number
+	case schema.TypeList, schema.TypeSet, schema.TypeMap:
+		elem := cty.String
+		if elemSchema, ok := sch.Elem.(*schema.Schema); ok {
+			elem = testGenerateType(elemSchema)
+		}
+		switch sch.Type {
+		case schema.TypeList:
+			return cty.List(elem)
+		case schema.TypeSet:
+			return cty.Set(elem)
+		}
+		return cty.Map(elem)
+	}
+	return cty.String
+}
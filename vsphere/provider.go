@@ -4,11 +4,15 @@
 package vsphere
 
 import (
+	"log"
 	"os"
 	"path/filepath"
+	"sync"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/debuglog"
 )
 
 // defaultAPITimeout is a default timeout value that is passed to functions
@@ -21,17 +25,44 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"user": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_USER", nil),
 				Description: "The user name for vSphere API operations.",
 			},
 
 			"password": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_PASSWORD", nil),
 				Description: "The user password for vSphere API operations.",
 			},
+			"saml_token": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_SAML_TOKEN", nil),
+				Description: "A SAML token issued by vCenter Single Sign-On, or the path to a file containing one, to authenticate with instead of a user name and password. Combine with client_certificate and client_key for a holder-of-key token.",
+			},
+			"client_certificate": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CLIENT_CERTIFICATE", nil),
+				Description: "A PEM-encoded solution user certificate, or the path to a file containing one, used to obtain a holder-of-key token from vCenter Single Sign-On.",
+			},
+			"client_key": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CLIENT_KEY", nil),
+				Description: "The PEM-encoded private key for client_certificate, or the path to a file containing it.",
+			},
+			"api_session_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Sensitive:   true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_API_SESSION_ID", nil),
+				Description: "An existing vmware-api-session-id to use instead of logging in.",
+			},
 
 			"vsphere_server": {
 				Type:        schema.TypeString,
@@ -45,12 +76,42 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_ALLOW_UNVERIFIED_SSL", false),
 				Description: "If set, VMware vSphere client will permit unverifiable SSL certificates.",
 			},
+			"ca_bundle": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CA_BUNDLE", nil),
+				Description: "PEM-encoded CA certificates, or the path to a file containing them, to trust in addition to the system roots.",
+			},
+			"server_thumbprint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_SERVER_THUMBPRINT", nil),
+				Description: "The SHA-256 thumbprint the certificate of the vSphere server is pinned to.",
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_PROXY_URL", nil),
+				Description: "The URL of an HTTP, HTTPS or SOCKS5 proxy to connect through.",
+			},
 			"license_key": {
 				Type:        schema.TypeString,
 				Optional:    true,
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_LICENSE_KEY", nil),
 				Description: "If set, will apply license to vcenter instance",
 			},
+			"default_tags": {
+				Type:        schema.TypeSet,
+				Optional:    true,
+				Description: "A list of tag IDs applied to every resource that supports tags, in addition to the tags set on the resource.",
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"deletion_protection": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "The default of deletion_protection for the resources that support it.",
+			},
 			"vcenter_server": {
 				Type:        schema.TypeString,
 				Optional:    true,
@@ -75,6 +136,13 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_CLIENT_DEBUG_PATH", ""),
 				Description: "govmomi debug path for debug",
 			},
+			"client_debug_format": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_CLIENT_DEBUG_FORMAT", debuglog.FormatDump),
+				Description:  "The format of the client debug logs, either dump or trace",
+				ValidateFunc: validation.StringInSlice(debuglog.Formats, false),
+			},
 			"persist_session": {
 				Type:        schema.TypeBool,
 				Optional:    true,
@@ -93,6 +161,21 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_REST_SESSION_PATH", filepath.Join(os.Getenv("HOME"), ".govmomi", "rest_sessions")),
 				Description: "The directory to save vSphere REST API sessions to",
 			},
+			"session_encryption_key": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				Sensitive:     true,
+				DefaultFunc:   schema.EnvDefaultFunc("VSPHERE_SESSION_ENCRYPTION_KEY", nil),
+				ConflictsWith: []string{"session_encryption_key_file"},
+				Description:   "The key to encrypt persisted vSphere sessions with.",
+			},
+			"session_encryption_key_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc("VSPHERE_SESSION_ENCRYPTION_KEY_FILE", nil),
+				ConflictsWith: []string{"session_encryption_key"},
+				Description:   "The path to a file containing the key to encrypt persisted vSphere sessions with.",
+			},
 			"vim_keep_alive": {
 				Type:        schema.TypeInt,
 				Optional:    true,
@@ -105,6 +188,55 @@ func Provider() *schema.Provider {
 				DefaultFunc: schema.EnvDefaultFunc("VSPHERE_API_TIMEOUT", 5),
 				Description: "API timeout in minutes (Default: 5)",
 			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_MAX_RETRIES", 3),
+				Description:  "The number of times to retry calls and tasks that fail with a transient fault (Default: 3)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"retry_max_wait": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_RETRY_MAX_WAIT", 30),
+				Description:  "The maximum time in seconds to wait between retries (Default: 30)",
+				ValidateFunc: validation.IntAtLeast(1),
+			},
+			"max_concurrent_api_calls": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_MAX_CONCURRENT_API_CALLS", 0),
+				Description:  "The maximum number of concurrent vSphere API calls (Default: 0, unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_rest_calls": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_MAX_CONCURRENT_REST_CALLS", 0),
+				Description:  "The maximum number of concurrent REST API calls (Default: 0, unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_clones": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_MAX_CONCURRENT_CLONES", 0),
+				Description:  "The maximum number of concurrent virtual machine clones (Default: 0, unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_relocates": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_MAX_CONCURRENT_RELOCATES", 0),
+				Description:  "The maximum number of concurrent virtual machine migrations (Default: 0, unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
+			"max_concurrent_ovf_deploys": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				DefaultFunc:  schema.EnvDefaultFunc("VSPHERE_MAX_CONCURRENT_OVF_DEPLOYS", 0),
+				Description:  "The maximum number of concurrent OVF and OVA deployments (Default: 0, unlimited)",
+				ValidateFunc: validation.IntAtLeast(0),
+			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
@@ -152,9 +284,13 @@ func Provider() *schema.Provider {
 			"vsphere_host_service_state":                      resourceVsphereHostServiceState(),
 			"vsphere_iscsi_software_adapter":                  resourceVSphereIscsiSoftwareAdapter(),
 			"vsphere_iscsi_target":                            resourceVSphereIscsiTarget(),
+			"vsphere_sso_user":                                resourceVSphereSSOUser(),
+			"vsphere_sso_group":                               resourceVSphereSSOGroup(),
+			"vsphere_sso_group_members":                       resourceVSphereSSOGroupMembers(),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
+			"vsphere_capabilities":               dataSourceVSphereCapabilities(),
 			"vsphere_compute_cluster":            dataSourceVSphereComputeCluster(),
 			"vsphere_compute_cluster_host_group": dataSourceVSphereComputeClusterHostGroup(),
 			"vsphere_content_library":            dataSourceVSphereContentLibrary(),
@@ -178,17 +314,29 @@ func Provider() *schema.Provider {
 			"vsphere_tag_category":               dataSourceVSphereTagCategory(),
 			"vsphere_vapp_container":             dataSourceVSphereVAppContainer(),
 			"vsphere_virtual_machine":            dataSourceVSphereVirtualMachine(),
+			"vsphere_virtual_machine_id":         dataSourceVSphereVirtualMachineID(),
 			"vsphere_vmfs_disks":                 dataSourceVSphereVmfsDisks(),
 			"vsphere_role":                       dataSourceVsphereRole(),
 			"vsphere_host_service_state":         dataSourceVSphereHostServiceState(),
 			"vsphere_iscsi_software_adapter":     dataSourceVSphereIscsiSoftwareAdapter(),
 			"vsphere_iscsi_target":               dataSourceVSphereIscsiTarget(),
+			"vsphere_ldap_identity_source":       dataSourceVSphereLDAPIdentitySource(),
+			"vsphere_sso_user":                   dataSourceVSphereSSOUser(),
+			"vsphere_sso_group":                  dataSourceVSphereSSOGroup(),
+			"vsphere_sso_principals":             dataSourceVSphereSSOPrincipals(),
 		},
 
 		ConfigureFunc: providerConfigure,
 	}
 }
 
+// configuredClients are the clients of the providers configured in this
+// process, which CloseClients closes.
+var (
+	configuredClientsMu sync.Mutex
+	configuredClients   []*Client
+)
+
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	timeoutMins := time.Duration(d.Get("api_timeout").(int))
 	defaultAPITimeout = timeoutMins * time.Minute
@@ -197,5 +345,26 @@ func providerConfigure(d *schema.ResourceData) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
-	return c.Client()
+	client, err := c.Client()
+	if err != nil {
+		return nil, err
+	}
+	configuredClientsMu.Lock()
+	defer configuredClientsMu.Unlock()
+	configuredClients = append(configuredClients, client)
+	return client, nil
+}
+
+// CloseClients closes the clients of the providers configured in this
+// process. The SDK has no hook for the end of a run, so the plugin calls it
+// once it stops serving.
+func CloseClients() {
+	configuredClientsMu.Lock()
+	defer configuredClientsMu.Unlock()
+	for _, client := range configuredClients {
+		if err := client.Close(); err != nil {
+			log.Printf("[WARN] Error closing client: %s", err)
+		}
+	}
+	configuredClients = nil
 }
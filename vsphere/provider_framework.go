@@ -0,0 +1,174 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	fwdatasource "github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	fwschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+// ProviderServer returns a factory for the provider server, which muxes the
+// SDKv2 provider returned by Provider with a plugin framework provider. The
+// framework provider serves the features that SDKv2 cannot, such as
+// provider-defined functions.
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	p := Provider()
+	servers := []func() tfprotov5.ProviderServer{
+		p.GRPCProvider,
+		providerserver.NewProtocol5(newFrameworkProvider(p)),
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, fmt.Errorf("error creating provider server: %s", err)
+	}
+	return muxServer.ProviderServer, nil
+}
+
+// frameworkProvider is the plugin framework half of the provider. Terraform
+// requires both halves of a muxed provider to have the same schema, so the
+// schema of the framework provider is converted from the one of the SDKv2
+// provider. The SDKv2 provider owns the configuration and the API clients.
+type frameworkProvider struct {
+	sdkProvider *schema.Provider
+}
+
+var _ provider.ProviderWithFunctions = &frameworkProvider{}
+
+// newFrameworkProvider returns the framework provider that is muxed with the
+// SDKv2 provider p.
+func newFrameworkProvider(p *schema.Provider) provider.Provider {
+	return &frameworkProvider{
+		sdkProvider: p,
+	}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "vsphere"
+}
+
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	attributes := make(map[string]fwschema.Attribute)
+	for k, s := range p.sdkProvider.Schema {
+		attribute, err := frameworkProviderAttribute(s)
+		if err != nil {
+			resp.Diagnostics.AddError(
+				"Invalid provider schema",
+				fmt.Sprintf("cannot convert provider argument %q: %s", k, err),
+			)
+			continue
+		}
+		attributes[k] = attribute
+	}
+	resp.Schema = fwschema.Schema{
+		Attributes: attributes,
+	}
+}
+
+// frameworkProviderAttribute converts the SDKv2 schema s of a provider
+// argument to a framework attribute with the same wire schema. An argument is
+// only required if it has no default, which is what SDKv2 reports to
+// Terraform.
+func frameworkProviderAttribute(s *schema.Schema) (fwschema.Attribute, error) {
+	if s.Computed {
+		return nil, fmt.Errorf("computed provider arguments are not supported")
+	}
+	required := s.Required && s.Default == nil && s.DefaultFunc == nil
+	optional := !required
+
+	switch s.Type {
+	case schema.TypeString:
+		return fwschema.StringAttribute{
+			Required:           required,
+			Optional:           optional,
+			Sensitive:          s.Sensitive,
+			Description:        s.Description,
+			DeprecationMessage: s.Deprecated,
+		}, nil
+	case schema.TypeBool:
+		return fwschema.BoolAttribute{
+			Required:           required,
+			Optional:           optional,
+			Sensitive:          s.Sensitive,
+			Description:        s.Description,
+			DeprecationMessage: s.Deprecated,
+		}, nil
+	case schema.TypeInt:
+		return fwschema.Int64Attribute{
+			Required:           required,
+			Optional:           optional,
+			Sensitive:          s.Sensitive,
+			Description:        s.Description,
+			DeprecationMessage: s.Deprecated,
+		}, nil
+	case schema.TypeSet, schema.TypeList:
+		elem, ok := s.Elem.(*schema.Schema)
+		if !ok || elem.Type != schema.TypeString {
+			return nil, fmt.Errorf("only collections of strings are supported")
+		}
+		if s.Type == schema.TypeList {
+			return fwschema.ListAttribute{
+				ElementType:        types.StringType,
+				Required:           required,
+				Optional:           optional,
+				Sensitive:          s.Sensitive,
+				Description:        s.Description,
+				DeprecationMessage: s.Deprecated,
+			}, nil
+		}
+		return fwschema.SetAttribute{
+			ElementType:        types.StringType,
+			Required:           required,
+			Optional:           optional,
+			Sensitive:          s.Sensitive,
+			Description:        s.Description,
+			DeprecationMessage: s.Deprecated,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported type %s", s.Type)
+}
+
+// Configure does nothing, as the SDKv2 provider handles the configuration and
+// provider-defined functions are called without it.
+func (p *frameworkProvider) Configure(_ context.Context, _ provider.ConfigureRequest, _ *provider.ConfigureResponse) {
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return nil
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() fwdatasource.DataSource {
+	return nil
+}
+
+func (p *frameworkProvider) Functions(_ context.Context) []func() function.Function {
+	return []func() function.Function{
+		newParseInventoryPathFunction,
+		newNormalizeMACAddressFunction,
+		newIsStaticMACAddressFunction,
+		newNetmaskToPrefixLengthFunction,
+	}
+}
+
+// frameworkStringAttrTypes returns the attribute types of an object whose
+// attributes named keys are all strings.
+func frameworkStringAttrTypes(keys ...string) map[string]attr.Type {
+	m := make(map[string]attr.Type)
+	for _, k := range keys {
+		m[k] = types.StringType
+	}
+	return m
+}
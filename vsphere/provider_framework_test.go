@@ -0,0 +1,93 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+)
+
+func TestProviderServer(t *testing.T) {
+	ctx := context.Background()
+	serverFactory, err := ProviderServer(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp, err := serverFactory().GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	// The mux server reports an error if the provider schemas of the SDKv2 and
+	// framework providers differ.
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+	if _, ok := resp.ResourceSchemas["vsphere_virtual_machine"]; !ok {
+		t.Error("expected the resources of the SDKv2 provider")
+	}
+	for _, name := range []string{
+		"parse_inventory_path",
+		"normalize_mac_address",
+		"is_static_mac_address",
+		"netmask_to_prefix_length",
+	} {
+		if _, ok := resp.Functions[name]; !ok {
+			t.Errorf("expected function %s", name)
+		}
+	}
+}
+
+func TestProviderServerCallFunction(t *testing.T) {
+	ctx := context.Background()
+	serverFactory, err := ProviderServer(ctx)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	arg, err := tfprotov5.NewDynamicValue(tftypes.String, tftypes.NewValue(tftypes.String, "/dc1/vm/folder1/vm1"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	// Terraform only calls functions of a server that implements
+	// FunctionServer.
+	server, ok := serverFactory().(tfprotov5.FunctionServer)
+	if !ok {
+		t.Fatal("expected the provider server to implement FunctionServer")
+	}
+	resp, err := server.CallFunction(ctx, &tfprotov5.CallFunctionRequest{
+		Name:      "parse_inventory_path",
+		Arguments: []*tfprotov5.DynamicValue{&arg},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	objectType := tftypes.Object{AttributeTypes: map[string]tftypes.Type{
+		"datacenter_folder": tftypes.String,
+		"datacenter":        tftypes.String,
+		"type":              tftypes.String,
+		"folder":            tftypes.String,
+		"name":              tftypes.String,
+	}}
+	result, err := resp.Result.Unmarshal(objectType)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var attrs map[string]tftypes.Value
+	if err := result.As(&attrs); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var name string
+	if err := attrs["name"].As(&name); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if name != "vm1" {
+		t.Fatalf("expected name vm1, got %q", name)
+	}
+}
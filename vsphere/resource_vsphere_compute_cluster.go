@@ -9,6 +9,7 @@ import (
 	"log"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/datastore"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
@@ -16,6 +17,8 @@ import (
 
 	"github.com/vmware/govmomi/vim25/mo"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
@@ -131,13 +134,25 @@ var clusterInfraUpdateHaConfigInfoRemediationTypeAllowedValues = []string{
 
 func resourceVSphereComputeCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterCreate,
-		Read:   resourceVSphereComputeClusterRead,
-		Update: resourceVSphereComputeClusterUpdate,
-		Delete: resourceVSphereComputeClusterDelete,
+		CreateContext: resourceVSphereComputeClusterCreate,
+		ReadContext:   resourceVSphereComputeClusterRead,
+		UpdateContext: resourceVSphereComputeClusterUpdate,
+		DeleteContext: resourceVSphereComputeClusterDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterImport,
+			StateContext: resourceVSphereComputeClusterImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(90 * time.Minute),
+		},
+		CustomizeDiff: customdiff.Sequence(
+			requireCapabilities(
+				capabilityRequirement{capability: viapi.CapabilityVsanESA, attribute: "vsan_esa_enabled"},
+			),
+			tagsAllCustomizeDiff,
+			deletionProtectionCustomizeDiff("vsphere_compute_cluster", resourceVSphereComputeCluster),
+		),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -584,49 +599,54 @@ func resourceVSphereComputeCluster() *schema.Resource {
 					},
 				},
 			},
-			vSphereTagAttributeKey:    tagsSchema(),
-			customattribute.ConfigKey: customattribute.ConfigSchema(),
+			vSphereTagAttributeKey:         tagsSchema(),
+			vSphereTagsAllAttributeKey:     tagsAllSchema(),
+			deletionProtectionAttributeKey: deletionProtectionSchema(),
+			customattribute.ConfigKey:      customattribute.ConfigSchema(),
 		},
 	}
 }
 
-func resourceVSphereComputeClusterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterIDString(d))
 
 	// We create the cluster here. This function creates a cluster with no
 	// configuration, as we want to add the hosts before applying the full
 	// configuration.
-	cluster, err := resourceVSphereComputeClusterApplyCreate(d, meta)
+	cluster, err := resourceVSphereComputeClusterApplyCreate(ctx, d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// The cluster can be tagged here now.
 	if err := resourceVSphereComputeClusterApplyTags(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err := resourceVSphereComputeClusterApplyCustomAttributes(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Move the hosts in now.
-	if err := resourceVSphereComputeClusterProcessHostUpdate(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterProcessHostUpdate(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	// Now that all the hosts that will be in the cluster have been added, apply
 	// the cluster configuration.
-	if err := resourceVSphereComputeClusterApplyClusterConfiguration(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyClusterConfiguration(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	// All done!
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterIDString(d))
-	return resourceVSphereComputeClusterRead(d, meta)
+	return resourceVSphereComputeClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterIDString(d))
+	if err := readDeletionProtection(d, meta); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 
 	cluster, err := resourceVSphereComputeClusterGetCluster(d, meta)
 	if err != nil {
@@ -635,80 +655,83 @@ func resourceVSphereComputeClusterRead(d *schema.ResourceData, meta interface{})
 			d.SetId("")
 			return nil
 		}
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterSaveDatacenter(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterSaveNameAndPath(d, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterFlattenData(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereComputeClusterReadTags(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterReadTags(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterReadCustomAttributes(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterIDString(d))
 
 	cluster, err := resourceVSphereComputeClusterGetCluster(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	cluster, err = resourceVSphereComputeClusterApplyNameChange(d, meta, cluster)
+	cluster, err = resourceVSphereComputeClusterApplyNameChange(ctx, d, meta, cluster)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	cluster, err = resourceVSphereComputeClusterApplyFolderChange(d, meta, cluster)
+	cluster, err = resourceVSphereComputeClusterApplyFolderChange(ctx, d, meta, cluster)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereComputeClusterProcessHostUpdate(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterProcessHostUpdate(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereComputeClusterApplyClusterConfiguration(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyClusterConfiguration(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterApplyTags(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterApplyCustomAttributes(d, meta, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterIDString(d))
-	return resourceVSphereComputeClusterRead(d, meta)
+	return resourceVSphereComputeClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterIDString(d))
+	if err := checkDeletionProtection(d, "vsphere_compute_cluster"); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	cluster, err := resourceVSphereComputeClusterGetCluster(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	client, err := resourceVSphereComputeClusterClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	version := viapi.ParseVersionFromClient(client)
@@ -719,33 +742,33 @@ func resourceVSphereComputeClusterDelete(d *schema.ResourceData, meta interface{
 			_ = v
 			log.Printf("[DEBUG] if Admission Control Policy set to Failover Host than turn HA OFF before removing hosts")
 			spec.DasConfig.Enabled = structure.BoolPtr(false)
-			if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-				return err
+			if err := clustercomputeresource.Reconfigure(ctx, cluster, spec); err != nil {
+				return errorDiagnostics(err, nil)
 			}
 		}
 	}
 
-	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(d, meta, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(ctx, d, meta, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereComputeClusterValidateEmptyCluster(d, cluster); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereComputeClusterApplyDelete(d, cluster); err != nil {
-		return err
+	if err := resourceVSphereComputeClusterApplyDelete(ctx, d, cluster); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	p := d.Id()
 	cluster, err := resourceVSphereComputeClusterGetClusterFromPath(meta, p, "")
 	if err != nil {
@@ -757,7 +780,7 @@ func resourceVSphereComputeClusterImport(d *schema.ResourceData, meta interface{
 		return nil, err
 	}
 
-	err = resourceVSphereComputeClusterRead(d, meta)
+	err = diagnosticsError(resourceVSphereComputeClusterRead(ctx, d, meta))
 	if err != nil {
 		return nil, err
 	}
@@ -783,7 +806,7 @@ func resourceVSphereComputeClusterImportSetDefaults(d *schema.ResourceData) erro
 
 // resourceVSphereComputeClusterApplyCreate processes the creation part of
 // resourceVSphereComputeClusterCreate.
-func resourceVSphereComputeClusterApplyCreate(d *schema.ResourceData, meta interface{}) (*object.ClusterComputeResource, error) {
+func resourceVSphereComputeClusterApplyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.ClusterComputeResource, error) {
 	log.Printf("[DEBUG] %s: Processing compute cluster creation", resourceVSphereComputeClusterIDString(d))
 	client, err := resourceVSphereComputeClusterClient(meta)
 	if err != nil {
@@ -805,7 +828,7 @@ func resourceVSphereComputeClusterApplyCreate(d *schema.ResourceData, meta inter
 	// Create the cluster. We use an empty config spec so that we can move the
 	// necessary hosts into the cluster *before* we send the full configuration,
 	// ensuring that any host-dependent configuration does not break.
-	cluster, err := clustercomputeresource.Create(f, d.Get("name").(string), types.ClusterConfigSpecEx{})
+	cluster, err := clustercomputeresource.Create(ctx, f, d.Get("name").(string), types.ClusterConfigSpecEx{})
 	if err != nil {
 		return nil, fmt.Errorf("error creating cluster: %s", err)
 	}
@@ -850,6 +873,7 @@ func resourceVSphereComputeClusterApplyCreate(d *schema.ResourceData, meta inter
 // the cluster. The host will be taken out of maintenance mode after being
 // removed.
 func resourceVSphereComputeClusterProcessHostUpdate(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -880,17 +904,17 @@ func resourceVSphereComputeClusterProcessHostUpdate(
 
 	// Add new hosts first
 	if len(newHosts) > 0 {
-		if err := clustercomputeresource.MoveHostsInto(client, cluster, newHosts); err != nil {
+		if err := clustercomputeresource.MoveHostsInto(ctx, client, cluster, newHosts); err != nil {
 			return fmt.Errorf("error moving new hosts into cluster: %s", err)
 		}
 
 		for _, hs := range newHosts {
-			hsProps, err := hostsystem.Properties(hs)
+			hsProps, err := hostsystem.Properties(hs, "runtime.inMaintenanceMode")
 			if err != nil {
 				return fmt.Errorf("while fetching properties for host %q: %s", hs.Reference().Value, err)
 			}
 			if hsProps.Runtime.InMaintenanceMode {
-				err := hostsystem.ExitMaintenanceMode(hs, provider.DefaultAPITimeout)
+				err := hostsystem.ExitMaintenanceMode(ctx, hs, provider.DefaultAPITimeout)
 				if err != nil {
 					return fmt.Errorf("while getting host %q out of maintenance mode: %s", hs.Reference().Value, err)
 				}
@@ -899,7 +923,7 @@ func resourceVSphereComputeClusterProcessHostUpdate(
 	}
 
 	// Remove hosts next
-	if err := clustercomputeresource.MoveHostsOutOf(cluster, oldHosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
+	if err := clustercomputeresource.MoveHostsOutOf(ctx, cluster, oldHosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
 		return fmt.Errorf("error moving old hosts out of cluster: %s", err)
 	}
 
@@ -921,6 +945,7 @@ func resourceVSphereComputeClusterGetHostSystemObjects(client *govmomi.Client, h
 }
 
 func resourceVSphereComputeClusterApplyClusterConfiguration(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -934,7 +959,7 @@ func resourceVSphereComputeClusterApplyClusterConfiguration(
 	log.Printf("[DEBUG] %s: Applying cluster configuration", resourceVSphereComputeClusterIDString(d))
 
 	// handle VSAN first to avoid race condition
-	if err := resourceVSphereComputeClusterApplyVsanConfig(d, meta, cluster); err != nil {
+	if err := resourceVSphereComputeClusterApplyVsanConfig(ctx, d, meta, cluster); err != nil {
 		return err
 	}
 
@@ -952,7 +977,7 @@ func resourceVSphereComputeClusterApplyClusterConfiguration(
 
 	// Note that the reconfigure for a cluster is the same as a standalone host,
 	// hence we send this to the computeresource helper's Reconfigure function.
-	return clustercomputeresource.Reconfigure(cluster, spec)
+	return clustercomputeresource.Reconfigure(ctx, cluster, spec)
 }
 
 // resourceVSphereComputeClusterApplyTags processes the tags step for both
@@ -970,15 +995,15 @@ func resourceVSphereComputeClusterApplyTags(d *schema.ResourceData, meta interfa
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereComputeClusterIDString(d))
-	return processTagDiff(tagsClient, d, cluster)
+	return processTagDiff(tagsClient, d, cluster, meta)
 }
 
 // resourceVSphereComputeClusterReadTags reads the tags for
 // vsphere_compute_cluster.
-func resourceVSphereComputeClusterReadTags(d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereComputeClusterIDString(d))
-		if err := readTagsForResource(tagsClient, cluster, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, cluster, d, meta); err != nil {
 			return err
 		}
 	} else {
@@ -1126,6 +1151,7 @@ func resourceVSphereComputeClusterSaveNameAndPath(d *schema.ResourceData, cluste
 // resourceVSphereComputeClusterApplyNameChange applies any changes to a
 // ClusterComputeResource's name.
 func resourceVSphereComputeClusterApplyNameChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1140,7 +1166,7 @@ func resourceVSphereComputeClusterApplyNameChange(
 	var err error
 
 	if d.HasChange("name") {
-		if err = clustercomputeresource.Rename(cluster, d.Get("name").(string)); err != nil {
+		if err = clustercomputeresource.Rename(ctx, cluster, d.Get("name").(string)); err != nil {
 			return nil, fmt.Errorf("error renaming cluster: %s", err)
 		}
 		changed = true
@@ -1166,6 +1192,7 @@ func resourceVSphereComputeClusterApplyNameChange(
 // resourceVSphereComputeClusterApplyFolderChange applies any changes to a
 // ClusterComputeResource's folder location.
 func resourceVSphereComputeClusterApplyFolderChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1182,7 +1209,7 @@ func resourceVSphereComputeClusterApplyFolderChange(
 	if d.HasChange("folder") {
 		f := d.Get("folder").(string)
 		client := meta.(*Client).vimClient
-		if err = clustercomputeresource.MoveToFolder(client, cluster, f); err != nil {
+		if err = clustercomputeresource.MoveToFolder(ctx, client, cluster, f); err != nil {
 			return nil, fmt.Errorf("could not move cluster to folder %q: %s", f, err)
 		}
 		changed = true
@@ -1234,6 +1261,7 @@ func resourceVSphereComputeClusterValidateEmptyCluster(
 // actually succeed depending on the resources actually in use in the
 // cluster, and specific constraints that exist in the cluster.
 func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1256,7 +1284,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
 		return err
 	}
 
-	if err := clustercomputeresource.MoveHostsOutOf(cluster, hosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
+	if err := clustercomputeresource.MoveHostsOutOf(ctx, cluster, hosts, d.Get("host_cluster_exit_timeout").(int)); err != nil {
 		return fmt.Errorf("error force-removing old hosts out of cluster: %s", err)
 	}
 
@@ -1271,6 +1299,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveHosts(
 // actually succeed depending on the resources actually in use in the
 // cluster, and specific constraints that exist in the cluster.
 func resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	cluster *object.ClusterComputeResource,
@@ -1289,7 +1318,7 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(
 	conf := vsantypes.VimVsanReconfigSpec{
 		DatastoreConfig: &vsantypes.VsanAdvancedDatastoreConfig{},
 	}
-	if err := vsanclient.Reconfigure(meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
+	if err := vsanclient.Reconfigure(ctx, meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
 		return fmt.Errorf("cannot force-evacuate remote datastores on cluster: %s, err: %s", d.Get("name").(string), err)
 	}
 
@@ -1298,9 +1327,9 @@ func resourceVSphereComputeClusterDeleteProcessForceRemoveVsanRemoteDatastore(
 
 // resourceVSphereComputeClusterApplyDelete process the removal of a
 // cluster.
-func resourceVSphereComputeClusterApplyDelete(d structure.ResourceIDStringer, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterApplyDelete(ctx context.Context, d structure.ResourceIDStringer, cluster *object.ClusterComputeResource) error {
 	log.Printf("[DEBUG] %s: Proceeding with cluster deletion", resourceVSphereComputeClusterIDString(d))
-	return clustercomputeresource.Delete(cluster)
+	return clustercomputeresource.Delete(ctx, cluster)
 }
 
 // resourceVSphereComputeClusterFlattenData saves the configuration attributes
@@ -1324,7 +1353,7 @@ func resourceVSphereComputeClusterFlattenData(
 	// attributes we need to set
 	version := viapi.ParseVersionFromClient(client)
 
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx", "host", "resourcePool")
 	if err != nil {
 		return err
 	}
@@ -1463,14 +1492,14 @@ func expandVsanDatastoreConfig(d *schema.ResourceData, meta interface{}) (*vsant
 	return conf, nil
 }
 
-func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
+func resourceVSphereComputeClusterApplyVsanConfig(ctx context.Context, d *schema.ResourceData, meta interface{}, cluster *object.ClusterComputeResource) error {
 	client, err := resourceVSphereComputeClusterClient(meta)
 	if err != nil {
 		return err
 	}
-	version := viapi.ParseVersionFromClient(client)
+	esaSupported := viapi.HasCapability(client, viapi.CapabilityVsanESA)
 
-	if version.AtLeast(viapi.VSphereVersion{Product: version.Product, Major: 8, Minor: 0}) {
+	if esaSupported {
 		if !d.Get("vsan_enabled").(bool) && d.Get("vsan_esa_enabled").(bool) {
 			return fmt.Errorf("vSAN ESA service cannot be enabled on cluster due to vSAN is disabled: %s", d.Get("name").(string))
 		}
@@ -1497,7 +1526,7 @@ func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta i
 		},
 	}
 
-	if version.AtLeast(viapi.VSphereVersion{Product: version.Product, Major: 8, Minor: 0}) {
+	if esaSupported {
 		vsanEsaEnabled := d.Get("vsan_esa_enabled").(bool)
 		conf.VsanClusterConfig.(*vsantypes.VsanClusterConfigInfo).VsanEsaEnabled = &vsanEsaEnabled
 	}
@@ -1521,7 +1550,7 @@ func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta i
 	}
 	conf.PerfsvcConfig = perfConfig
 
-	if err := vsanclient.Reconfigure(meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
+	if err := vsanclient.Reconfigure(ctx, meta.(*Client).vsanClient, cluster.Reference(), conf); err != nil {
 		return fmt.Errorf("cannot apply vsan service on cluster '%s': %s", d.Get("name").(string), err)
 	}
 
@@ -1535,7 +1564,7 @@ func resourceVSphereComputeClusterApplyVsanConfig(d *schema.ResourceData, meta i
 	if err != nil {
 		return err
 	}
-	if err := vsanclient.Reconfigure(meta.(*Client).vsanClient, cluster.Reference(), vsantypes.VimVsanReconfigSpec{
+	if err := vsanclient.Reconfigure(ctx, meta.(*Client).vsanClient, cluster.Reference(), vsantypes.VimVsanReconfigSpec{
 		Modify:          true,
 		DatastoreConfig: datastoreConfig,
 	}); err != nil {
@@ -2354,6 +2383,8 @@ func resourceVSphereComputeClusterHasClusterConfigChangeExcluded(k string) bool
 		"host_cluster_exit_timeout",
 		"force_evacuate_on_destroy",
 		vSphereTagAttributeKey,
+		vSphereTagsAllAttributeKey,
+		deletionProtectionAttributeKey,
 		customattribute.ConfigKey,
 	}
 
@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -23,12 +25,12 @@ const resourceVSphereComputeClusterHostGroupName = "vsphere_compute_cluster_host
 
 func resourceVSphereComputeClusterHostGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterHostGroupCreate,
-		Read:   resourceVSphereComputeClusterHostGroupRead,
-		Update: resourceVSphereComputeClusterHostGroupUpdate,
-		Delete: resourceVSphereComputeClusterHostGroupDelete,
+		CreateContext: resourceVSphereComputeClusterHostGroupCreate,
+		ReadContext:   resourceVSphereComputeClusterHostGroupRead,
+		UpdateContext: resourceVSphereComputeClusterHostGroupUpdate,
+		DeleteContext: resourceVSphereComputeClusterHostGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterHostGroupImport,
+			StateContext: resourceVSphereComputeClusterHostGroupImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -54,17 +56,17 @@ func resourceVSphereComputeClusterHostGroup() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterHostGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterHostGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterHostGroup(d, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -77,31 +79,31 @@ func resourceVSphereComputeClusterHostGroupCreate(d *schema.ResourceData, meta i
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterHostGroupFlattenID(cluster, name)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterHostGroupIDString(d))
-	return resourceVSphereComputeClusterHostGroupRead(d, meta)
+	return resourceVSphereComputeClusterHostGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterHostGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereComputeClusterHostGroupFindEntry(cluster, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -114,34 +116,34 @@ func resourceVSphereComputeClusterHostGroupRead(d *schema.ResourceData, meta int
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	// This is the "correct" way to set name here, even if it's a bit
 	// superfluous.
 	if err = d.Set("name", info.Name); err != nil {
-		return fmt.Errorf("error setting attribute \"name\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"name\": %s", err))
 	}
 
 	if err = flattenClusterHostGroup(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterHostGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterHostGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterHostGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterHostGroup(d, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -154,20 +156,20 @@ func resourceVSphereComputeClusterHostGroupUpdate(d *schema.ResourceData, meta i
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterHostGroupIDString(d))
-	return resourceVSphereComputeClusterHostGroupRead(d, meta)
+	return resourceVSphereComputeClusterHostGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterHostGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterHostGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterHostGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterHostGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -181,15 +183,15 @@ func resourceVSphereComputeClusterHostGroupDelete(d *schema.ResourceData, meta i
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterHostGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterHostGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterHostGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -286,7 +288,7 @@ func resourceVSphereComputeClusterHostGroupFindEntry(
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterHostGroup, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -26,7 +26,7 @@ const (
 )
 
 func TestAccResourceVSphereComputeCluster_basic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -64,7 +64,7 @@ func TestAccResourceVSphereComputeCluster_basic(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_haAdmissionControlPolicyDisabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -85,7 +85,7 @@ func TestAccResourceVSphereComputeCluster_haAdmissionControlPolicyDisabled(t *te
 }
 
 func TestAccResourceVSphereComputeCluster_drsHAEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -106,7 +106,7 @@ func TestAccResourceVSphereComputeCluster_drsHAEnabled(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_vsanDedupEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -129,7 +129,7 @@ func TestAccResourceVSphereComputeCluster_vsanDedupEnabled(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_vsanCompressionEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -152,7 +152,7 @@ func TestAccResourceVSphereComputeCluster_vsanCompressionEnabled(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_vsanPerfEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -174,7 +174,7 @@ func TestAccResourceVSphereComputeCluster_vsanPerfEnabled(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_vsanPerfVerboseEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -197,7 +197,7 @@ func TestAccResourceVSphereComputeCluster_vsanPerfVerboseEnabled(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_vsanPerfVerboseDiagnosticEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -221,7 +221,7 @@ func TestAccResourceVSphereComputeCluster_vsanPerfVerboseDiagnosticEnabled(t *te
 }
 
 func TestAccResourceVSphereComputeCluster_vsanUnmapEnabledwithVsanEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -243,7 +243,7 @@ func TestAccResourceVSphereComputeCluster_vsanUnmapEnabledwithVsanEnabled(t *tes
 }
 
 func TestAccResourceVSphereComputeCluster_vsanUnmapDisabledwithVsanDisabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -281,7 +281,7 @@ func TestAccResourceVSphereComputeCluster_vsanUnmapDisabledwithVsanDisabled(t *t
 }
 
 func TestAccResourceVSphereComputeCluster_vsanDITEncryption(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -305,7 +305,7 @@ func TestAccResourceVSphereComputeCluster_vsanDITEncryption(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_vsanEsaEnabled(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -328,7 +328,7 @@ func TestAccResourceVSphereComputeCluster_vsanEsaEnabled(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_explicitFailoverHost(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -352,7 +352,7 @@ func TestAccResourceVSphereComputeCluster_explicitFailoverHost(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_rename(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -380,7 +380,7 @@ func TestAccResourceVSphereComputeCluster_rename(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_inFolder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -401,7 +401,7 @@ func TestAccResourceVSphereComputeCluster_inFolder(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_moveToFolder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -429,7 +429,7 @@ func TestAccResourceVSphereComputeCluster_moveToFolder(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_singleTag(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -450,7 +450,7 @@ func TestAccResourceVSphereComputeCluster_singleTag(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_multipleTags(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -471,7 +471,7 @@ func TestAccResourceVSphereComputeCluster_multipleTags(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_switchTags(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -499,7 +499,7 @@ func TestAccResourceVSphereComputeCluster_switchTags(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_singleCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -520,7 +520,7 @@ func TestAccResourceVSphereComputeCluster_singleCustomAttribute(t *testing.T) {
 }
 
 func TestAccResourceVSphereComputeCluster_multipleCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -541,7 +541,7 @@ func TestAccResourceVSphereComputeCluster_multipleCustomAttribute(t *testing.T)
 }
 
 func TestAccResourceVSphereComputeCluster_switchCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -25,12 +27,12 @@ const resourceVSphereComputeClusterVMAffinityRuleName = "vsphere_compute_cluster
 
 func resourceVSphereComputeClusterVMAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMAffinityRuleCreate,
-		Read:   resourceVSphereComputeClusterVMAffinityRuleRead,
-		Update: resourceVSphereComputeClusterVMAffinityRuleUpdate,
-		Delete: resourceVSphereComputeClusterVMAffinityRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMAffinityRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMAffinityRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMAffinityRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMAffinityRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMAffinityRuleImport,
+			StateContext: resourceVSphereComputeClusterVMAffinityRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -66,17 +68,17 @@ func resourceVSphereComputeClusterVMAffinityRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
 	cluster, _, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterAffinityRuleSpec(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -89,36 +91,36 @@ func resourceVSphereComputeClusterVMAffinityRuleCreate(d *schema.ResourceData, m
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	info, err = resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMAffinityRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereComputeClusterVMAffinityRuleFindEntry(cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -131,28 +133,28 @@ func resourceVSphereComputeClusterVMAffinityRuleRead(d *schema.ResourceData, met
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	if err = flattenClusterAffinityRuleSpec(d, meta, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterAffinityRuleSpec(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -167,20 +169,20 @@ func resourceVSphereComputeClusterVMAffinityRuleUpdate(d *schema.ResourceData, m
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -194,15 +196,15 @@ func resourceVSphereComputeClusterVMAffinityRuleDelete(d *schema.ResourceData, m
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAffinityRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMAffinityRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -327,7 +329,7 @@ func resourceVSphereComputeClusterVMAffinityRuleFindEntry(
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -355,7 +357,7 @@ func resourceVSphereComputeClusterVMAffinityRuleFindEntryByName(
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -25,12 +27,12 @@ const resourceVSphereComputeClusterVMAntiAffinityRuleName = "vsphere_compute_clu
 
 func resourceVSphereComputeClusterVMAntiAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMAntiAffinityRuleCreate,
-		Read:   resourceVSphereComputeClusterVMAntiAffinityRuleRead,
-		Update: resourceVSphereComputeClusterVMAntiAffinityRuleUpdate,
-		Delete: resourceVSphereComputeClusterVMAntiAffinityRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMAntiAffinityRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMAntiAffinityRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMAntiAffinityRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMAntiAffinityRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMAntiAffinityRuleImport,
+			StateContext: resourceVSphereComputeClusterVMAntiAffinityRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -66,17 +68,17 @@ func resourceVSphereComputeClusterVMAntiAffinityRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
 	cluster, _, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -89,36 +91,36 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleCreate(d *schema.ResourceDat
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	info, err = resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMAntiAffinityRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -131,28 +133,28 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleRead(d *schema.ResourceData,
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	if err = flattenClusterAntiAffinityRuleSpec(d, meta, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -167,20 +169,20 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleUpdate(d *schema.ResourceDat
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMAntiAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -194,15 +196,15 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleDelete(d *schema.ResourceDat
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMAntiAffinityRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMAntiAffinityRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -326,7 +328,7 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleFindEntry(
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -354,7 +356,7 @@ func resourceVSphereComputeClusterVMAntiAffinityRuleFindEntryByName(
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -24,12 +26,12 @@ const resourceVSphereComputeClusterVMDependencyRuleName = "vsphere_compute_clust
 
 func resourceVSphereComputeClusterVMDependencyRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMDependencyRuleCreate,
-		Read:   resourceVSphereComputeClusterVMDependencyRuleRead,
-		Update: resourceVSphereComputeClusterVMDependencyRuleUpdate,
-		Delete: resourceVSphereComputeClusterVMDependencyRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMDependencyRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMDependencyRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMDependencyRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMDependencyRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMDependencyRuleImport,
+			StateContext: resourceVSphereComputeClusterVMDependencyRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -69,17 +71,17 @@ func resourceVSphereComputeClusterVMDependencyRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
 	cluster, _, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDependencyRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -92,36 +94,36 @@ func resourceVSphereComputeClusterVMDependencyRuleCreate(d *schema.ResourceData,
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	info, err = resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMDependencyRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
-	return resourceVSphereComputeClusterVMDependencyRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMDependencyRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereComputeClusterVMDependencyRuleFindEntry(cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -134,28 +136,28 @@ func resourceVSphereComputeClusterVMDependencyRuleRead(d *schema.ResourceData, m
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	if err = flattenClusterDependencyRuleInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDependencyRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -170,20 +172,20 @@ func resourceVSphereComputeClusterVMDependencyRuleUpdate(d *schema.ResourceData,
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
-	return resourceVSphereComputeClusterVMDependencyRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMDependencyRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMDependencyRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMDependencyRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -197,15 +199,15 @@ func resourceVSphereComputeClusterVMDependencyRuleDelete(d *schema.ResourceData,
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMDependencyRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMDependencyRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMDependencyRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -306,7 +308,7 @@ func resourceVSphereComputeClusterVMDependencyRuleFindEntry(
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterDependencyRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -334,7 +336,7 @@ func resourceVSphereComputeClusterVMDependencyRuleFindEntryByName(
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterDependencyRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
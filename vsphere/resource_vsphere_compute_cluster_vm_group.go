@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -24,12 +26,12 @@ const resourceVSphereComputeClusterVMGroupName = "vsphere_compute_cluster_vm_gro
 
 func resourceVSphereComputeClusterVMGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereComputeClusterVMGroupCreate,
-		Read:   resourceVSphereComputeClusterVMGroupRead,
-		Update: resourceVSphereComputeClusterVMGroupUpdate,
-		Delete: resourceVSphereComputeClusterVMGroupDelete,
+		CreateContext: resourceVSphereComputeClusterVMGroupCreate,
+		ReadContext:   resourceVSphereComputeClusterVMGroupRead,
+		UpdateContext: resourceVSphereComputeClusterVMGroupUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMGroupImport,
+			StateContext: resourceVSphereComputeClusterVMGroupImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -55,17 +57,17 @@ func resourceVSphereComputeClusterVMGroup() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterVMGroup(d, meta, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -78,31 +80,31 @@ func resourceVSphereComputeClusterVMGroupCreate(d *schema.ResourceData, meta int
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMGroupFlattenID(cluster, name)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMGroupIDString(d))
-	return resourceVSphereComputeClusterVMGroupRead(d, meta)
+	return resourceVSphereComputeClusterVMGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereComputeClusterVMGroupFindEntry(cluster, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -115,34 +117,34 @@ func resourceVSphereComputeClusterVMGroupRead(d *schema.ResourceData, meta inter
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	// This is the "correct" way to set name here, even if it's a bit
 	// superfluous.
 	if err = d.Set("name", info.Name); err != nil {
-		return fmt.Errorf("error setting attribute \"name\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"name\": %s", err))
 	}
 
 	if err = flattenClusterVMGroup(d, meta, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterVMGroup(d, meta, name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		GroupSpec: []types.ClusterGroupSpec{
@@ -155,20 +157,20 @@ func resourceVSphereComputeClusterVMGroupUpdate(d *schema.ResourceData, meta int
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMGroupIDString(d))
-	return resourceVSphereComputeClusterVMGroupRead(d, meta)
+	return resourceVSphereComputeClusterVMGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMGroupIDString(d))
 
 	cluster, name, err := resourceVSphereComputeClusterVMGroupObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -182,15 +184,15 @@ func resourceVSphereComputeClusterVMGroupDelete(d *schema.ResourceData, meta int
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMGroupIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -307,7 +309,7 @@ func resourceVSphereComputeClusterVMGroupFindEntry(
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterVmGroup, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
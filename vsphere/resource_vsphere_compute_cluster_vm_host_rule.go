@@ -12,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -25,13 +26,13 @@ const resourceVSphereComputeClusterVMHostRuleName = "vsphere_compute_cluster_vm_
 
 func resourceVSphereComputeClusterVMHostRule() *schema.Resource {
 	return &schema.Resource{
-		Create:        resourceVSphereComputeClusterVMHostRuleCreate,
-		Read:          resourceVSphereComputeClusterVMHostRuleRead,
-		Update:        resourceVSphereComputeClusterVMHostRuleUpdate,
-		Delete:        resourceVSphereComputeClusterVMHostRuleDelete,
+		CreateContext: resourceVSphereComputeClusterVMHostRuleCreate,
+		ReadContext:   resourceVSphereComputeClusterVMHostRuleRead,
+		UpdateContext: resourceVSphereComputeClusterVMHostRuleUpdate,
+		DeleteContext: resourceVSphereComputeClusterVMHostRuleDelete,
 		CustomizeDiff: resourceVSphereComputeClusterVMHostRuleCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereComputeClusterVMHostRuleImport,
+			StateContext: resourceVSphereComputeClusterVMHostRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -78,17 +79,17 @@ func resourceVSphereComputeClusterVMHostRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereComputeClusterVMHostRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
 	cluster, _, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterVMHostRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		RulesSpec: []types.ClusterRuleSpec{
@@ -101,36 +102,36 @@ func resourceVSphereComputeClusterVMHostRuleCreate(d *schema.ResourceData, meta
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	info, err = resourceVSphereComputeClusterVMHostRuleFindEntryByName(cluster, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereComputeClusterVMHostRuleFlattenID(cluster, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
-	return resourceVSphereComputeClusterVMHostRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMHostRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMHostRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereComputeClusterVMHostRuleFindEntry(cluster, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -143,28 +144,28 @@ func resourceVSphereComputeClusterVMHostRuleRead(d *schema.ResourceData, meta in
 	// completeness on import so that if the wrong cluster/VM combo was used, it
 	// will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	if err = flattenClusterVMHostRuleInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereComputeClusterVMHostRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterVMHostRuleInfo(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -179,20 +180,20 @@ func resourceVSphereComputeClusterVMHostRuleUpdate(d *schema.ResourceData, meta
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
-	return resourceVSphereComputeClusterVMHostRuleRead(d, meta)
+	return resourceVSphereComputeClusterVMHostRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereComputeClusterVMHostRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereComputeClusterVMHostRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereComputeClusterVMHostRuleIDString(d))
 
 	cluster, key, err := resourceVSphereComputeClusterVMHostRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -206,8 +207,8 @@ func resourceVSphereComputeClusterVMHostRuleDelete(d *schema.ResourceData, meta
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereComputeClusterVMHostRuleIDString(d))
@@ -242,7 +243,7 @@ func resourceVSphereComputeClusterVMHostRuleValidateHostRulesSpecified(d *schema
 	return nil
 }
 
-func resourceVSphereComputeClusterVMHostRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereComputeClusterVMHostRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -345,7 +346,7 @@ func resourceVSphereComputeClusterVMHostRuleFindEntry(
 	cluster *object.ClusterComputeResource,
 	key int32,
 ) (*types.ClusterVmHostRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -373,7 +374,7 @@ func resourceVSphereComputeClusterVMHostRuleFindEntryByName(
 	cluster *object.ClusterComputeResource,
 	name string,
 ) (*types.ClusterVmHostRuleInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -4,21 +4,38 @@
 package vsphere
 
 import (
+	"context"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 )
 
 func resourceVSphereContentLibrary() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereContentLibraryCreate,
-		Delete: resourceVSphereContentLibraryDelete,
-		Read:   resourceVSphereContentLibraryRead,
+		CreateContext: resourceVSphereContentLibraryCreate,
+		DeleteContext: resourceVSphereContentLibraryDelete,
+		ReadContext:   resourceVSphereContentLibraryRead,
+		UpdateContext: resourceVSphereContentLibraryUpdate,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereContentLibraryImport,
+			StateContext: resourceVSphereContentLibraryImport,
 		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
+		CustomizeDiff: customdiff.Sequence(
+			requireCapabilities(
+				capabilityRequirement{capability: viapi.CapabilityRestAPI},
+			),
+			deletionProtectionCustomizeDiff("vsphere_content_library", resourceVSphereContentLibrary),
+		),
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type:        schema.TypeString,
@@ -61,10 +78,14 @@ func resourceVSphereContentLibrary() *schema.Resource {
 						Optional: true,
 					},
 					"password": {
-						Type:     schema.TypeString,
-						ForceNew: true,
-						Computed: true,
-						Optional: true,
+						Type:             schema.TypeString,
+						ForceNew:         true,
+						Computed:         true,
+						Optional:         true,
+						Sensitive:        true,
+						StateFunc:        secret.StateFunc,
+						DiffSuppressFunc: secret.DiffSuppressFunc,
+						Description:      "The password that subscribers authenticate with, or file:// followed by the path of a local file that contains it. Only its fingerprint is saved in state.",
 					},
 					"published": {
 						Type:     schema.TypeBool,
@@ -107,10 +128,14 @@ func resourceVSphereContentLibrary() *schema.Resource {
 						Optional: true,
 					},
 					"password": {
-						Type:     schema.TypeString,
-						ForceNew: true,
-						Computed: true,
-						Optional: true,
+						Type:             schema.TypeString,
+						ForceNew:         true,
+						Computed:         true,
+						Optional:         true,
+						Sensitive:        true,
+						StateFunc:        secret.StateFunc,
+						DiffSuppressFunc: secret.DiffSuppressFunc,
+						Description:      "The password to authenticate to the published library with, or file:// followed by the path of a local file that contains it. Only its fingerprint is saved in state.",
 					},
 					"on_demand": {
 						Type:     schema.TypeBool,
@@ -127,12 +152,16 @@ func resourceVSphereContentLibrary() *schema.Resource {
 				},
 				},
 			},
+			deletionProtectionAttributeKey: deletionProtectionSchema(),
 		},
 	}
 }
 
-func resourceVSphereContentLibraryRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryRead : Beginning Content Library (%s) read", d.Id())
+	if err := readDeletionProtection(d, meta); err != nil {
+		return diag.FromErr(err)
+	}
 	c := meta.(*Client).restClient
 	lib, err := contentlibrary.FromID(c, d.Id())
 	if err != nil {
@@ -140,17 +169,17 @@ func resourceVSphereContentLibraryRead(d *schema.ResourceData, meta interface{})
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(lib.ID)
 	if err = contentlibrary.FlattenPublication(d, lib.Publication); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = contentlibrary.FlattenSubscription(d, lib.Subscription); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = contentlibrary.FlattenStorageBackings(d, lib.Storage); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("name", lib.Name)
 	_ = d.Set("description", lib.Description)
@@ -158,41 +187,50 @@ func resourceVSphereContentLibraryRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourceVSphereContentLibraryCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryCreate : Beginning Content Library (%s) creation", d.Get("name").(string))
 	vimClient := meta.(*Client).vimClient
 	restClient := meta.(*Client).restClient
 	backings, err := contentlibrary.ExpandStorageBackings(vimClient, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	id, err := contentlibrary.CreateLibrary(d, restClient, backings)
+	id, err := contentlibrary.CreateLibrary(ctx, d, restClient, backings)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(id)
 	log.Printf("[DEBUG] resourceVSphereContentLibraryCreate : Content Library (%s) creation is complete", d.Get("name").(string))
-	return resourceVSphereContentLibraryRead(d, meta)
+	return resourceVSphereContentLibraryRead(ctx, d, meta)
 }
 
-func resourceVSphereContentLibraryDelete(d *schema.ResourceData, meta interface{}) error {
+// resourceVSphereContentLibraryUpdate only saves deletion_protection, as all
+// the other arguments force a new content library.
+func resourceVSphereContentLibraryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return resourceVSphereContentLibraryRead(ctx, d, meta)
+}
+
+func resourceVSphereContentLibraryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryDelete : Deleting Content Library (%s)", d.Id())
+	if err := checkDeletionProtection(d, "vsphere_content_library"); err != nil {
+		return diag.FromErr(err)
+	}
 	c := meta.(*Client).restClient
 	lib, err := contentlibrary.FromID(c, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] resourceVSphereContentLibraryDelete : Content Library (%s) deleted", d.Id())
-	return contentlibrary.DeleteLibrary(c, lib)
+	return diag.FromErr(contentlibrary.DeleteLibrary(ctx, c, lib))
 }
 
-func resourceVSphereContentLibraryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereContentLibraryImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).restClient
 	_, err := contentlibrary.FromID(client, d.Id())
 	if err != nil {
 		return nil, err
 	}
-	err = resourceVSphereContentLibraryRead(d, meta)
+	err = diagnosticsError(resourceVSphereContentLibraryRead(ctx, d, meta))
 	if err != nil {
 		return nil, err
 	}
@@ -7,25 +7,35 @@ import (
 	"context"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
 )
 
 func resourceVSphereContentLibraryItem() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereContentLibraryItemCreate,
-		Delete: resourceVSphereContentLibraryItemDelete,
-		Read:   resourceVSphereContentLibraryItemRead,
+		CreateContext: resourceVSphereContentLibraryItemCreate,
+		DeleteContext: resourceVSphereContentLibraryItemDelete,
+		ReadContext:   resourceVSphereContentLibraryItemRead,
+		CustomizeDiff: requireCapabilities(
+			capabilityRequirement{capability: viapi.CapabilityRestAPI},
+		),
 		StateUpgraders: []schema.StateUpgrader{{
 			Version: 0,
 			Type:    resourceVSphereContentLibraryItemResourceV0().CoreConfigSchema().ImpliedType(),
 			Upgrade: resourceVSphereContentLibraryItemUpgradeV0,
 		}},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
+		},
 		SchemaVersion: 1,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereContentLibraryItemImport,
+			StateContext: resourceVSphereContentLibraryItemImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -88,7 +98,7 @@ func resourceVSphereContentLibraryItemUpgradeV0(_ context.Context, rawState map[
 	return rawState, nil
 }
 
-func resourceVSphereContentLibraryItemRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryItemRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemRead : Reading Content Library item (%s)", d.Id())
 	rc := meta.(*Client).restClient
 	item, err := contentlibrary.ItemFromID(rc, d.Id())
@@ -97,7 +107,7 @@ func resourceVSphereContentLibraryItemRead(d *schema.ResourceData, meta interfac
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("name", item.Name)
 	_ = d.Set("description", item.Description)
@@ -107,46 +117,46 @@ func resourceVSphereContentLibraryItemRead(d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func resourceVSphereContentLibraryItemCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryItemCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemCreate : Beginning Content Library item (%s) creation", d.Get("name").(string))
 	rc := meta.(*Client).restClient
 	lib, err := contentlibrary.FromID(rc, d.Get("library_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var moid virtualmachine.MOIDForUUIDResult
 	if uuid, ok := d.GetOk("source_uuid"); ok {
 		moid, err = virtualmachine.MOIDForUUID(meta.(*Client).vimClient, uuid.(string))
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
-	id, err := contentlibrary.CreateLibraryItem(rc, lib, d.Get("name").(string), d.Get("description").(string), d.Get("type").(string), d.Get("file_url").(string), moid.MOID)
+	id, err := contentlibrary.CreateLibraryItem(ctx, rc, lib, d.Get("name").(string), d.Get("description").(string), d.Get("type").(string), d.Get("file_url").(string), moid.MOID, meta.(*Client).transport)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(*id)
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemCreate : Content Library item (%s) creation complete", d.Get("name").(string))
-	return resourceVSphereContentLibraryItemRead(d, meta)
+	return resourceVSphereContentLibraryItemRead(ctx, d, meta)
 }
 
-func resourceVSphereContentLibraryItemDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereContentLibraryItemDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] resourceVSphereContentLibraryItemDelete : Deleting Content Library item (%s)", d.Id())
 	rc := meta.(*Client).restClient
 	item, err := contentlibrary.ItemFromID(rc, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return contentlibrary.DeleteLibraryItem(rc, item)
+	return diag.FromErr(contentlibrary.DeleteLibraryItem(ctx, rc, item))
 }
 
-func resourceVSphereContentLibraryItemImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereContentLibraryItemImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).restClient
 	_, err := contentlibrary.ItemFromID(client, d.Id())
 	if err != nil {
 		return nil, err
 	}
-	err = resourceVSphereContentLibraryItemRead(d, meta)
+	err = diagnosticsError(resourceVSphereContentLibraryItemRead(ctx, d, meta))
 	if err != nil {
 		return nil, err
 	}
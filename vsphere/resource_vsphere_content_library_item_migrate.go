@@ -3,15 +3,17 @@
 
 package vsphere
 
-import "github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+import (
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
 
 func resourceVSphereContentLibraryItemResourceV0() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereContentLibraryItemCreate,
-		Delete: resourceVSphereContentLibraryItemDelete,
-		Read:   resourceVSphereContentLibraryItemRead,
+		CreateContext: resourceVSphereContentLibraryItemCreate,
+		DeleteContext: resourceVSphereContentLibraryItemDelete,
+		ReadContext:   resourceVSphereContentLibraryItemRead,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereContentLibraryItemImport,
+			StateContext: resourceVSphereContentLibraryItemImport,
 		},
 		Schema: map[string]*schema.Schema{
 			"name": {
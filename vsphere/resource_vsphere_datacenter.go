@@ -12,6 +12,8 @@ import (
 
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -25,12 +27,16 @@ import (
 
 func resourceVSphereDatacenter() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDatacenterCreate,
-		Read:   resourceVSphereDatacenterRead,
-		Update: resourceVSphereDatacenterUpdate,
-		Delete: resourceVSphereDatacenterDelete,
+		CreateContext: resourceVSphereDatacenterCreate,
+		ReadContext:   resourceVSphereDatacenterRead,
+		UpdateContext: resourceVSphereDatacenterUpdate,
+		DeleteContext: resourceVSphereDatacenterDelete,
+		CustomizeDiff: customdiff.Sequence(
+			tagsAllCustomizeDiff,
+			deletionProtectionCustomizeDiff("vsphere_datacenter", resourceVSphereDatacenter),
+		),
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDatacenterImport,
+			StateContext: resourceVSphereDatacenterImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -52,7 +58,9 @@ func resourceVSphereDatacenter() *schema.Resource {
 			},
 
 			// Add tags schema
-			vSphereTagAttributeKey: tagsSchema(),
+			vSphereTagAttributeKey:         tagsSchema(),
+			vSphereTagsAllAttributeKey:     tagsAllSchema(),
+			deletionProtectionAttributeKey: deletionProtectionSchema(),
 
 			// Custom Attributes
 			customattribute.ConfigKey: customattribute.ConfigSchema(),
@@ -60,19 +68,19 @@ func resourceVSphereDatacenter() *schema.Resource {
 	}
 }
 
-func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	name := d.Get("name").(string)
@@ -80,21 +88,21 @@ func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) e
 	var f *object.Folder
 	if v, ok := d.GetOk("folder"); ok {
 		finder := find.NewFinder(client.Client, true)
-		f, err = finder.Folder(context.TODO(), v.(string))
+		f, err = finder.Folder(ctx, v.(string))
 		if err != nil {
-			return fmt.Errorf("failed to find folder that will contain the datacenter: %s", err)
+			return diag.FromErr(fmt.Errorf("failed to find folder that will contain the datacenter: %s", err))
 		}
 	} else {
 		f = object.NewRootFolder(client.Client)
 	}
 
-	dc, err := f.CreateDatacenter(context.TODO(), name)
+	dc, err := f.CreateDatacenter(ctx, name)
 	if err != nil {
-		return fmt.Errorf("failed to create datacenter: %s", err)
+		return diag.FromErr(fmt.Errorf("failed to create datacenter: %s", err))
 	}
 	// From govmomi code: "Response will be nil if this is an ESX host that does not belong to a vCenter"
 	if dc == nil {
-		return fmt.Errorf("ESX host does not belong to a vCenter")
+		return diag.FromErr(fmt.Errorf("ESX host does not belong to a vCenter"))
 	}
 
 	d.SetId(name)
@@ -111,24 +119,24 @@ func resourceVSphereDatacenterCreate(d *schema.ResourceData, meta interface{}) e
 
 	_, err = stateConf.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err)
+		return diag.FromErr(fmt.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err))
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, dc); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, dc, meta); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(dc); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
-	return resourceVSphereDatacenterRead(d, meta)
+	return resourceVSphereDatacenterRead(ctx, d, meta)
 }
 
 func resourceVSphereDatacenterStateRefreshFunc(d *schema.ResourceData, meta interface{}) resource.StateRefreshFunc {
@@ -163,7 +171,10 @@ func datacenterExists(d *schema.ResourceData, meta interface{}) (*object.Datacen
 	return dc, err
 }
 
-func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := readDeletionProtection(d, meta); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	dc, err := datacenterExists(d, meta)
 	if err != nil {
 		log.Printf("couldn't find the specified datacenter: %s", err)
@@ -173,15 +184,15 @@ func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) err
 
 	// TODO: In v2.0, the ID should be the MOID and this can be removed.
 	if err = d.Set("moid", dc.Reference().Value); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("name", dc.Name()); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, dc, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, dc, d, meta); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
@@ -190,7 +201,7 @@ func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) err
 	if customattribute.IsSupported(client) {
 		moDc, err := datacenterCustomAttributes(dc)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		customattribute.ReadFromResource(moDc.Entity(), d)
 	}
@@ -198,43 +209,46 @@ func resourceVSphereDatacenterRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func resourceVSphereDatacenterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	client := meta.(*Client).vimClient
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	dc, err := datacenterExists(d, meta)
 	if err != nil {
-		return fmt.Errorf("couldn't find the specified datacenter: %s", err)
+		return diag.FromErr(fmt.Errorf("couldn't find the specified datacenter: %s", err))
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, dc); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, dc, meta); err != nil {
+			return diag.FromErr(err)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(dc); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatacenterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "vsphere_datacenter"); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
 
@@ -244,7 +258,7 @@ func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) e
 	}
 
 	finder := find.NewFinder(client.Client, true)
-	dc, err := finder.Datacenter(context.TODO(), path)
+	dc, err := finder.Datacenter(ctx, path)
 	if err != nil {
 		log.Printf("couldn't find the specified datacenter: %s", err)
 		d.SetId("")
@@ -255,9 +269,9 @@ func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) e
 		This: dc.Common.Reference(),
 	}
 
-	_, err = methods.Destroy_Task(context.TODO(), client, req)
+	_, err = methods.Destroy_Task(ctx, client, req)
 	if err != nil {
-		return fmt.Errorf("%s", err)
+		return diag.FromErr(fmt.Errorf("%s", err))
 	}
 
 	// Wait for the datacenter resource to be destroyed
@@ -272,13 +286,13 @@ func resourceVSphereDatacenterDelete(d *schema.ResourceData, meta interface{}) e
 
 	_, err = stateConf.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err)
+		return diag.FromErr(fmt.Errorf("error waiting for datacenter (%s) to become ready: %s", name, err))
 	}
 
 	return nil
 }
 
-func resourceVSphereDatacenterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDatacenterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 	p := d.Id()
 	if !strings.HasPrefix(p, "/") {
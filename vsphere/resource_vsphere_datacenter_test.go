@@ -7,9 +7,11 @@ import (
 	"context"
 	"fmt"
 	"path"
+	"regexp"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/vmware/govmomi/find"
 )
@@ -38,6 +40,40 @@ resource "vsphere_datacenter" "testDC" {
 `, folder, name)
 }
 
+func testAccCheckVSphereDatacenterConfigDeletionProtection(name string, enabled bool) string {
+	return fmt.Sprintf(`
+resource "vsphere_datacenter" "testDC" {
+  name                = "%s"
+  deletion_protection = %t
+}
+`, name, enabled)
+}
+
+func testAccCheckVSphereDatacenterConfigDeletionProtectionCreateBeforeDestroy(name string) string {
+	return fmt.Sprintf(`
+resource "vsphere_datacenter" "testDC" {
+  name                = "%s"
+  deletion_protection = true
+
+  lifecycle {
+    create_before_destroy = true
+  }
+}
+`, name)
+}
+
+func testAccCheckVSphereDatacenterConfigProviderDeletionProtection(name string) string {
+	return fmt.Sprintf(`
+provider "vsphere" {
+  deletion_protection = true
+}
+
+resource "vsphere_datacenter" "testDC" {
+  name = "%s"
+}
+`, name)
+}
+
 const testAccCheckVSphereDatacenterConfigTags = `
 resource "vsphere_tag_category" "testacc-category" {
   name        = "testacc-tag-category"
@@ -187,6 +223,81 @@ func TestAccResourceVSphereDatacenter_createOnSubfolder(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereDatacenter_deletionProtection(t *testing.T) {
+	name := "testDC"
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereDatacenterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereDatacenterConfigDeletionProtection(name, true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatacenterExists(testAccCheckVSphereDatacenterResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckVSphereDatacenterResourceName, "deletion_protection", "true"),
+				),
+			},
+			{
+				// Renaming a datacenter replaces it.
+				Config:      testAccCheckVSphereDatacenterConfigDeletionProtection(name+"-renamed", true),
+				ExpectError: regexp.MustCompile("has deletion_protection enabled"),
+			},
+			{
+				// With create_before_destroy, the plan fails before the
+				// replacement is created.
+				Config:      testAccCheckVSphereDatacenterConfigDeletionProtectionCreateBeforeDestroy(name + "-renamed"),
+				ExpectError: regexp.MustCompile("has deletion_protection enabled and cannot be replaced"),
+			},
+			{
+				Config:      testAccCheckVSphereDatacenterConfigDeletionProtection(name, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("has deletion_protection enabled"),
+			},
+			{
+				Config: testAccCheckVSphereDatacenterConfigDeletionProtection(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatacenterExists(testAccCheckVSphereDatacenterResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckVSphereDatacenterResourceName, "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereDatacenter_providerDeletionProtection(t *testing.T) {
+	name := "testDC"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() { testAccPreCheck(t) },
+		// The configuration has its own provider block, which Providers would
+		// duplicate.
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"vsphere": func() (*schema.Provider, error) { return testAccProvider, nil },
+		},
+		CheckDestroy: testAccCheckVSphereDatacenterDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccCheckVSphereDatacenterConfigProviderDeletionProtection(name),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereDatacenterExists(testAccCheckVSphereDatacenterResourceName, true),
+					resource.TestCheckResourceAttr(testAccCheckVSphereDatacenterResourceName, "deletion_protection", "true"),
+				),
+			},
+			{
+				Config:      testAccCheckVSphereDatacenterConfigProviderDeletionProtection(name),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("has deletion_protection enabled"),
+			},
+			{
+				// Setting the argument overrides the default of the provider.
+				Config: testAccCheckVSphereDatacenterConfigDeletionProtection(name, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccCheckVSphereDatacenterResourceName, "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceVSphereDatacenter_singleTag(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
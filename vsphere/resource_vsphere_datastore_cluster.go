@@ -4,9 +4,11 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -33,12 +35,13 @@ var storageDrsSpaceLoadBalanceConfigSpaceThresholdModeAllowedValues = []string{
 
 func resourceVSphereDatastoreCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDatastoreClusterCreate,
-		Read:   resourceVSphereDatastoreClusterRead,
-		Update: resourceVSphereDatastoreClusterUpdate,
-		Delete: resourceVSphereDatastoreClusterDelete,
+		CreateContext: resourceVSphereDatastoreClusterCreate,
+		ReadContext:   resourceVSphereDatastoreClusterRead,
+		UpdateContext: resourceVSphereDatastoreClusterUpdate,
+		DeleteContext: resourceVSphereDatastoreClusterDelete,
+		CustomizeDiff: tagsAllCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDatastoreClusterImport,
+			StateContext: resourceVSphereDatastoreClusterImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -188,119 +191,120 @@ func resourceVSphereDatastoreCluster() *schema.Resource {
 				Description: "Advanced configuration options for storage DRS.",
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
-			vSphereTagAttributeKey:    tagsSchema(),
-			customattribute.ConfigKey: customattribute.ConfigSchema(),
+			vSphereTagAttributeKey:     tagsSchema(),
+			vSphereTagsAllAttributeKey: tagsAllSchema(),
+			customattribute.ConfigKey:  customattribute.ConfigSchema(),
 		},
 	}
 }
 
-func resourceVSphereDatastoreClusterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDatastoreClusterIDString(d))
 
 	pod, err := resourceVSphereDatastoreClusterApplyCreate(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterApplyTags(d, meta, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterApplyCustomAttributes(d, meta, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplySDRSConfig(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplySDRSConfig(ctx, d, meta, pod); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDatastoreClusterIDString(d))
-	return resourceVSphereDatastoreClusterRead(d, meta)
+	return resourceVSphereDatastoreClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDatastoreClusterIDString(d))
 	pod, err := resourceVSphereDatastoreClusterGetPod(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterSaveNameAndPath(d, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterFlattenSDRSData(d, meta, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereDatastoreClusterReadTags(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterReadTags(ctx, d, meta, pod); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterReadCustomAttributes(d, meta, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDatastoreClusterIDString(d))
 	return nil
 }
 
-func resourceVSphereDatastoreClusterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDatastoreClusterIDString(d))
 	pod, err := resourceVSphereDatastoreClusterGetPod(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	pod, err = resourceVSphereDatastoreClusterApplyNameChange(d, meta, pod)
+	pod, err = resourceVSphereDatastoreClusterApplyNameChange(ctx, d, meta, pod)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	pod, err = resourceVSphereDatastoreClusterApplyFolderChange(d, meta, pod)
+	pod, err = resourceVSphereDatastoreClusterApplyFolderChange(ctx, d, meta, pod)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplySDRSConfig(d, meta, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplySDRSConfig(ctx, d, meta, pod); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterApplyTags(d, meta, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if err := resourceVSphereDatastoreClusterApplyCustomAttributes(d, meta, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDatastoreClusterIDString(d))
-	return resourceVSphereDatastoreClusterRead(d, meta)
+	return resourceVSphereDatastoreClusterRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	resourceIDString := resourceVSphereDatastoreClusterIDString(d)
 	log.Printf("[DEBUG] %s: Beginning delete", resourceIDString)
 	pod, err := resourceVSphereDatastoreClusterGetPod(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Very similar to how we handle folders, we don't delete a storage pod if
 	// there is child items in it. If there is, we fail with an error that
 	// mentions this restriction.
 	if err := resourceVSphereDatastoreClusterValidateEmptyCluster(d, pod); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	if err := resourceVSphereDatastoreClusterApplyDelete(d, pod); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterApplyDelete(ctx, d, pod); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceIDString)
 	return nil
 }
 
-func resourceVSphereDatastoreClusterImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDatastoreClusterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	p := d.Id()
 	pod, err := resourceVSphereDatastoreClusterGetPodFromPath(meta, p, "")
 	if err != nil {
@@ -367,15 +371,15 @@ func resourceVSphereDatastoreClusterApplyTags(d *schema.ResourceData, meta inter
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereDatastoreClusterIDString(d))
-	return processTagDiff(tagsClient, d, pod)
+	return processTagDiff(tagsClient, d, pod, meta)
 }
 
 // resourceVSphereDatastoreClusterReadTags reads the tags for
 // vsphere_datastore_cluster.
-func resourceVSphereDatastoreClusterReadTags(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereDatastoreClusterIDString(d))
-		if err := readTagsForResource(tagsClient, pod, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, pod, d, meta); err != nil {
 			return err
 		}
 	} else {
@@ -424,7 +428,7 @@ func resourceVSphereDatastoreClusterReadCustomAttributes(d *schema.ResourceData,
 
 // resourceVSphereDatastoreClusterApplySDRSConfig applies the SDRS
 // configuration to a datastore cluster.
-func resourceVSphereDatastoreClusterApplySDRSConfig(d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterApplySDRSConfig(ctx context.Context, d *schema.ResourceData, meta interface{}, pod *object.StoragePod) error {
 	// This is a no-op if there is no SDRS config changed
 	if !resourceVSphereDatastoreClusterHasSDRSConfigChange(d) {
 		log.Printf("[DEBUG] %s: No SDRS configuration attributes have changed", resourceVSphereDatastoreClusterIDString(d))
@@ -446,7 +450,7 @@ func resourceVSphereDatastoreClusterApplySDRSConfig(d *schema.ResourceData, meta
 		PodConfigSpec: expandStorageDrsPodConfigSpec(d, version),
 	}
 
-	return storagepod.ApplyDRSConfiguration(client, pod, spec)
+	return storagepod.ApplyDRSConfiguration(ctx, client, pod, spec)
 }
 
 // resourceVSphereDatastoreClusterHasSDRSConfigChange checks all resource keys
@@ -475,6 +479,7 @@ func resourceVSphereDatastoreClusterHasSDRSConfigChangeExcluded(k string) bool {
 		"datacenter_id",
 		"folder",
 		vSphereTagAttributeKey,
+		vSphereTagsAllAttributeKey,
 		customattribute.ConfigKey,
 	}
 
@@ -547,6 +552,7 @@ func resourceVSphereDatastoreClusterSaveNameAndPath(d *schema.ResourceData, pod
 // resourceVSphereDatastoreClusterApplyNameChange applies any changes to a
 // StoragePod's name.
 func resourceVSphereDatastoreClusterApplyNameChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	pod *object.StoragePod,
@@ -561,7 +567,7 @@ func resourceVSphereDatastoreClusterApplyNameChange(
 	var err error
 
 	if d.HasChange("name") {
-		if err = storagepod.Rename(pod, d.Get("name").(string)); err != nil {
+		if err = storagepod.Rename(ctx, pod, d.Get("name").(string)); err != nil {
 			return nil, fmt.Errorf("error renaming datastore cluster: %s", err)
 		}
 		changed = true
@@ -587,6 +593,7 @@ func resourceVSphereDatastoreClusterApplyNameChange(
 // resourceVSphereDatastoreClusterApplyFolderChange applies any changes to a
 // StoragePod's folder location.
 func resourceVSphereDatastoreClusterApplyFolderChange(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	pod *object.StoragePod,
@@ -603,7 +610,7 @@ func resourceVSphereDatastoreClusterApplyFolderChange(
 	if d.HasChange("folder") {
 		f := d.Get("folder").(string)
 		client := meta.(*Client).vimClient
-		if err = storagepod.MoveToFolder(client, pod, f); err != nil {
+		if err = storagepod.MoveToFolder(ctx, client, pod, f); err != nil {
 			return nil, fmt.Errorf("could not move datastore cluster to folder %q: %s", f, err)
 		}
 		changed = true
@@ -647,9 +654,9 @@ func resourceVSphereDatastoreClusterValidateEmptyCluster(d structure.ResourceIDS
 
 // resourceVSphereDatastoreClusterApplyDelete process the removal of a
 // datastore cluster.
-func resourceVSphereDatastoreClusterApplyDelete(d *schema.ResourceData, pod *object.StoragePod) error {
+func resourceVSphereDatastoreClusterApplyDelete(ctx context.Context, d *schema.ResourceData, pod *object.StoragePod) error {
 	log.Printf("[DEBUG] %s: Proceeding with datastore cluster deletion", resourceVSphereDatastoreClusterIDString(d))
-	if err := storagepod.Delete(pod); err != nil {
+	if err := storagepod.Delete(ctx, pod); err != nil {
 		return err
 	}
 	d.SetId("")
@@ -672,7 +679,7 @@ func resourceVSphereDatastoreClusterFlattenSDRSData(d *schema.ResourceData, meta
 	// attributes we need to set
 	version := viapi.ParseVersionFromClient(client)
 
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(pod, "podStorageDrsEntry")
 	if err != nil {
 		return err
 	}
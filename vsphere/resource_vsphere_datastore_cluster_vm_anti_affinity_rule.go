@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/storagepod"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -24,12 +26,12 @@ const resourceVSphereDatastoreClusterVMAntiAffinityRuleName = "vsphere_datastore
 
 func resourceVSphereDatastoreClusterVMAntiAffinityRule() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate,
-		Read:   resourceVSphereDatastoreClusterVMAntiAffinityRuleRead,
-		Update: resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate,
-		Delete: resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete,
+		CreateContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate,
+		ReadContext:   resourceVSphereDatastoreClusterVMAntiAffinityRuleRead,
+		UpdateContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate,
+		DeleteContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDatastoreClusterVMAntiAffinityRuleImport,
+			StateContext: resourceVSphereDatastoreClusterVMAntiAffinityRuleImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -65,21 +67,21 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRule() *schema.Resource {
 	}
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleValidateRuleVMCount(d); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
 	pod, _, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := types.StorageDrsConfigSpec{
 		PodConfigSpec: &types.StorageDrsPodConfigSpec{
@@ -94,36 +96,36 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleCreate(d *schema.ResourceD
 		},
 	}
 
-	if err = resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(pod, spec); err != nil {
-		return err
+	if err = resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(ctx, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	info, err = resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(pod, info.Name)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFlattenID(pod, info.Key)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
 	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(pod, key)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -136,32 +138,32 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d *schema.ResourceDat
 	// completeness on import so that if the wrong pod/VM combo was used, it
 	// will be noted.
 	if err = d.Set("datastore_cluster_id", pod.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"datastore_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"datastore_cluster_id\": %s", err))
 	}
 
 	if err = flattenClusterAntiAffinityRuleSpec(d, meta, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleValidateRuleVMCount(d); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
 	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterAntiAffinityRuleSpec(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	info.Key = key
 
@@ -178,20 +180,20 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleUpdate(d *schema.ResourceD
 		},
 	}
 
-	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(pod, spec); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(ctx, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
-	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(d, meta)
+	return resourceVSphereDatastoreClusterVMAntiAffinityRuleRead(ctx, d, meta)
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 
 	pod, key, err := resourceVSphereDatastoreClusterVMAntiAffinityRuleObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := types.StorageDrsConfigSpec{
@@ -207,15 +209,15 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleDelete(d *schema.ResourceD
 		},
 	}
 
-	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(pod, spec); err != nil {
-		return err
+	if err := resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(ctx, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereDatastoreClusterVMAntiAffinityRuleIDString(d))
 	return nil
 }
 
-func resourceVSphereDatastoreClusterVMAntiAffinityRuleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDatastoreClusterVMAntiAffinityRuleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -288,7 +290,7 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntry(
 	pod *object.StoragePod,
 	key int32,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(pod, "podStorageDrsEntry")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching datastore cluster properties: %s", err)
 	}
@@ -317,7 +319,7 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleFindEntryByName(
 	pod *object.StoragePod,
 	name string,
 ) (*types.ClusterAntiAffinityRuleSpec, error) {
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(pod, "podStorageDrsEntry")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching datastore cluster properties: %s", err)
 	}
@@ -421,10 +423,12 @@ func resourceVSphereDatastoreClusterVMAntiAffinityRuleClient(meta interface{}) (
 // is mainly just vim25.Client with some additional session helper bits that is
 // not normally needed during normal operation.
 func resourceVSphereDatastoreClusterVMAntiAffinityRuleApplySDRSConfigSpec(
+	ctx context.Context,
 	pod *object.StoragePod,
 	spec types.StorageDrsConfigSpec,
 ) error {
 	return storagepod.ApplyDRSConfiguration(
+		ctx,
 		&govmomi.Client{
 			Client: pod.Client(),
 		},
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/dvportgroup"
@@ -30,7 +31,8 @@ func resourceVSphereDistributedPortGroup() *schema.Resource {
 			Computed:    true,
 		},
 		// Tagging
-		vSphereTagAttributeKey: tagsSchema(),
+		vSphereTagAttributeKey:     tagsSchema(),
+		vSphereTagsAllAttributeKey: tagsAllSchema(),
 		// Custom Attributes
 		customattribute.ConfigKey: customattribute.ConfigSchema(),
 	}
@@ -38,56 +40,57 @@ func resourceVSphereDistributedPortGroup() *schema.Resource {
 	structure.MergeSchema(s, schemaDVPortgroupConfigSpec())
 
 	return &schema.Resource{
-		Create: resourceVSphereDistributedPortGroupCreate,
-		Read:   resourceVSphereDistributedPortGroupRead,
-		Update: resourceVSphereDistributedPortGroupUpdate,
-		Delete: resourceVSphereDistributedPortGroupDelete,
+		CreateContext: resourceVSphereDistributedPortGroupCreate,
+		ReadContext:   resourceVSphereDistributedPortGroupRead,
+		UpdateContext: resourceVSphereDistributedPortGroupUpdate,
+		DeleteContext: resourceVSphereDistributedPortGroupDelete,
+		CustomizeDiff: tagsAllCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDistributedPortGroupImport,
+			StateContext: resourceVSphereDistributedPortGroupImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereDistributedPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	dvsID := d.Get("distributed_virtual_switch_uuid").(string)
 	dvs, err := dvsFromUUID(client, dvsID)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", dvsID, err)
+		return errorDiagnostics(fmt.Errorf("could not find DVS %q: %w", dvsID, err), nil)
 	}
 
 	spec := expandDVPortgroupConfigSpec(d)
 	task, err := dvportgroup.Create(client, dvs, spec)
 	if err != nil {
-		return fmt.Errorf("error creating portgroup: %s", err)
+		return errorDiagnostics(fmt.Errorf("error creating portgroup: %w", err), nil)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	info, err := task.WaitForResult(tctx, nil)
+	info, err := viapi.WaitForTask(tctx, task)
 	if err != nil {
-		return fmt.Errorf("error waiting for portgroup creation to complete: %s", err)
+		return errorDiagnostics(fmt.Errorf("error waiting for portgroup creation to complete: %w", err), nil)
 	}
 	pg, err := dvportgroup.FromMOID(client, info.Result.(types.ManagedObjectReference).Value)
 	if err != nil {
-		return fmt.Errorf("error fetching portgroup after creation: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching portgroup after creation: %w", err), nil)
 	}
 	props, err := dvportgroup.Properties(pg)
 	if err != nil {
-		return fmt.Errorf("error fetching portgroup properties after creation: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching portgroup properties after creation: %w", err), nil)
 	}
 
 	d.SetId(pg.Reference().Value)
@@ -95,45 +98,45 @@ func resourceVSphereDistributedPortGroupCreate(d *schema.ResourceData, meta inte
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, pg.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, pg.Reference()), meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(object.NewReference(client.Client, pg.Reference())); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
-	return resourceVSphereDistributedPortGroupRead(d, meta)
+	return resourceVSphereDistributedPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	pgID := d.Id()
 	pg, err := dvportgroup.FromMOID(client, pgID)
 	if err != nil {
-		return fmt.Errorf("could not find portgroup %q: %s", pgID, err)
+		return errorDiagnostics(fmt.Errorf("could not find portgroup %q: %w", pgID, err), nil)
 	}
-	props, err := dvportgroup.Properties(pg)
+	props, err := dvportgroup.Properties(pg, "config", "customValue", "key")
 	if err != nil {
-		return fmt.Errorf("error fetching portgroup properties: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching portgroup properties: %w", err), nil)
 	}
 
 	_ = d.Set("key", props.Key)
 
 	if err := flattenDVPortgroupConfigInfo(d, props.Config); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, pg, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, pg, d, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error reading tags: %w", err), nil)
 		}
 	}
 
@@ -144,82 +147,82 @@ func resourceVSphereDistributedPortGroupRead(d *schema.ResourceData, meta interf
 	return nil
 }
 
-func resourceVSphereDistributedPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	pgID := d.Id()
 	pg, err := dvportgroup.FromMOID(client, pgID)
 	if err != nil {
-		return fmt.Errorf("could not find portgroup %q: %s", pgID, err)
+		return errorDiagnostics(fmt.Errorf("could not find portgroup %q: %w", pgID, err), nil)
 	}
 	spec := expandDVPortgroupConfigSpec(d)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := pg.Reconfigure(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("error reconfiguring portgroup: %s", err)
+		return errorDiagnostics(fmt.Errorf("error reconfiguring portgroup: %w", err), nil)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.Wait(tctx); err != nil {
-		return fmt.Errorf("error waiting for portgroup update to complete: %s", err)
+	if _, err := viapi.WaitForTask(tctx, task); err != nil {
+		return errorDiagnostics(fmt.Errorf("error waiting for portgroup update to complete: %w", err), nil)
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, pg.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, pg.Reference()), meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Update custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(object.NewReference(client.Client, pg.Reference())); err != nil {
-			return fmt.Errorf("error updating custom attributes: %s", err)
+			return errorDiagnostics(fmt.Errorf("error updating custom attributes: %w", err), nil)
 		}
 	}
 
-	return resourceVSphereDistributedPortGroupRead(d, meta)
+	return resourceVSphereDistributedPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedPortGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	pgID := d.Id()
 	pg, err := dvportgroup.FromMOID(client, pgID)
 	if err != nil {
-		return fmt.Errorf("could not find portgroup %q: %s", pgID, err)
+		return errorDiagnostics(fmt.Errorf("could not find portgroup %q: %w", pgID, err), nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := pg.Destroy(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting portgroup: %s", err)
+		return errorDiagnostics(fmt.Errorf("error deleting portgroup: %w", err), nil)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.Wait(tctx); err != nil {
-		return fmt.Errorf("error waiting for portgroup deletion to complete: %s", err)
+	if _, err := viapi.WaitForTask(tctx, task); err != nil {
+		return errorDiagnostics(fmt.Errorf("error waiting for portgroup deletion to complete: %w", err), nil)
 	}
 	return nil
 }
 
-func resourceVSphereDistributedPortGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDistributedPortGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We use the inventory path to the portgroup to import. There is not
 	// checking to make sure that it belongs to the configured DVS, but on
 	// subsequent plans, if it is not, the resource will be in an unusable state
@@ -6,7 +6,10 @@ package vsphere
 import (
 	"context"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
@@ -36,139 +39,149 @@ func resourceVSphereDistributedVirtualSwitch() *schema.Resource {
 			Optional:    true,
 		},
 		// Tagging
-		vSphereTagAttributeKey:    tagsSchema(),
-		customattribute.ConfigKey: customattribute.ConfigSchema(),
+		vSphereTagAttributeKey:         tagsSchema(),
+		vSphereTagsAllAttributeKey:     tagsAllSchema(),
+		deletionProtectionAttributeKey: deletionProtectionSchema(),
+		customattribute.ConfigKey:      customattribute.ConfigSchema(),
 	}
 	structure.MergeSchema(s, schemaDVSCreateSpec())
 
 	return &schema.Resource{
-		Create: resourceVSphereDistributedVirtualSwitchCreate,
-		Read:   resourceVSphereDistributedVirtualSwitchRead,
-		Update: resourceVSphereDistributedVirtualSwitchUpdate,
-		Delete: resourceVSphereDistributedVirtualSwitchDelete,
+		CreateContext: resourceVSphereDistributedVirtualSwitchCreate,
+		ReadContext:   resourceVSphereDistributedVirtualSwitchRead,
+		UpdateContext: resourceVSphereDistributedVirtualSwitchUpdate,
+		DeleteContext: resourceVSphereDistributedVirtualSwitchDelete,
+		CustomizeDiff: customdiff.Sequence(
+			tagsAllCustomizeDiff,
+			deletionProtectionCustomizeDiff("vsphere_distributed_virtual_switch", resourceVSphereDistributedVirtualSwitch),
+		),
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDistributedVirtualSwitchImport,
+			StateContext: resourceVSphereDistributedVirtualSwitchImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereDistributedVirtualSwitchCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	dc, err := datacenterFromID(client, d.Get("datacenter_id").(string))
 	if err != nil {
-		return fmt.Errorf("cannot locate datacenter: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot locate datacenter: %w", err), nil)
 	}
 	fo, err := folder.FromPath(client, d.Get("folder").(string), folder.VSphereFolderTypeNetwork, dc)
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot locate folder: %w", err), nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
 	spec := expandDVSCreateSpec(d)
 	task, err := fo.CreateDVS(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("error creating DVS: %s", err)
+		return errorDiagnostics(fmt.Errorf("error creating DVS: %w", err), nil)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer tcancel()
-	info, err := task.WaitForResult(tctx, nil)
+	info, err := viapi.WaitForTask(ctx, task)
 	if err != nil {
-		return fmt.Errorf("error waiting for DVS creation to complete: %s", err)
+		return errorDiagnostics(fmt.Errorf("error waiting for DVS creation to complete: %w", err), nil)
 	}
 
 	dvs, err := dvsFromMOID(client, info.Result.(types.ManagedObjectReference).Value)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS after creation: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching DVS after creation: %w", err), nil)
 	}
 	props, err := dvsProperties(dvs)
 	if err != nil {
-		return fmt.Errorf("error fetching DVS properties after creation: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching DVS properties after creation: %w", err), nil)
 	}
 
 	d.SetId(props.Uuid)
 
 	// Enable network resource I/O control if it needs to be enabled
 	if d.Get("network_resource_control_enabled").(bool) {
-		err = enableDVSNetworkResourceManagement(client, dvs, true)
+		err = enableDVSNetworkResourceManagement(ctx, client, dvs, true)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, dvs.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, dvs.Reference()), meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(dvs); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
-	return resourceVSphereDistributedVirtualSwitchRead(d, meta)
+	return resourceVSphereDistributedVirtualSwitchRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := readDeletionProtection(d, meta); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	id := d.Id()
 	dvs, err := dvsFromUUID(client, id)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", id, err)
+		return errorDiagnostics(fmt.Errorf("could not find DVS %q: %w", id, err), nil)
 	}
-	props, err := dvsProperties(dvs)
+	props, err := dvsProperties(dvs, "config", "customValue")
 	if err != nil {
-		return fmt.Errorf("error fetching DVS properties: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching DVS properties: %w", err), nil)
 	}
 
 	// Set the datacenter ID, for completion's sake when importing
 	dcp, err := folder.RootPathParticleNetwork.SplitDatacenter(dvs.InventoryPath)
 	if err != nil {
-		return fmt.Errorf("error parsing datacenter from inventory path: %s", err)
+		return errorDiagnostics(fmt.Errorf("error parsing datacenter from inventory path: %w", err), nil)
 	}
 	dc, err := getDatacenter(client, dcp)
 	if err != nil {
-		return fmt.Errorf("error locating datacenter: %s", err)
+		return errorDiagnostics(fmt.Errorf("error locating datacenter: %w", err), nil)
 	}
 	_ = d.Set("datacenter_id", dc.Reference().Value)
 
 	// Set the folder
 	f, err := folder.RootPathParticleNetwork.SplitRelativeFolder(dvs.InventoryPath)
 	if err != nil {
-		return fmt.Errorf("error parsing DVS path %q: %s", dvs.InventoryPath, err)
+		return errorDiagnostics(fmt.Errorf("error parsing DVS path %q: %w", dvs.InventoryPath, err), nil)
 	}
 	_ = d.Set("folder", folder.NormalizePath(f))
 
 	// Read in config info
 	if err := flattenVMwareDVSConfigInfo(d, props.Config.(*types.VMwareDVSConfigInfo)); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, dvs, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, dvs, d, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error reading tags: %w", err), nil)
 		}
 	}
 
@@ -180,25 +193,25 @@ func resourceVSphereDistributedVirtualSwitchRead(d *schema.ResourceData, meta in
 	return nil
 }
 
-func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	id := d.Id()
 	dvs, err := dvsFromUUID(client, id)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", id, err)
+		return errorDiagnostics(fmt.Errorf("could not find DVS %q: %w", id, err), nil)
 	}
 
 	// If we have a pending version upgrade, do that first.
@@ -214,14 +227,14 @@ func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta
 			}
 		}
 		if nvi < ovi {
-			return fmt.Errorf("downgrading dvSwitches are not allowed (old: %s new: %s)", old, newValue)
+			return errorDiagnostics(fmt.Errorf("downgrading dvSwitches are not allowed (old: %s new: %s)", old, newValue), nil)
 		}
-		if err := upgradeDVS(client, dvs, newValue.(string)); err != nil {
-			return fmt.Errorf("could not upgrade DVS: %s", err)
+		if err := upgradeDVS(ctx, client, dvs, newValue.(string)); err != nil {
+			return errorDiagnostics(fmt.Errorf("could not upgrade DVS: %w", err), nil)
 		}
 		props, err := dvsProperties(dvs)
 		if err != nil {
-			return fmt.Errorf("could not get DVS properties after upgrade: %s", err)
+			return errorDiagnostics(fmt.Errorf("could not get DVS properties after upgrade: %w", err), nil)
 		}
 		// ConfigVersion increments after a DVS upgrade, which means this needs to
 		// be updated before the post-update read to ensure that we don't run into
@@ -230,62 +243,61 @@ func resourceVSphereDistributedVirtualSwitchUpdate(d *schema.ResourceData, meta
 	}
 
 	spec := expandVMwareDVSConfigSpec(d)
-	if err := updateDVSConfiguration(dvs, spec); err != nil {
-		return fmt.Errorf("could not update DVS: %s", err)
+	if err := updateDVSConfiguration(ctx, dvs, spec); err != nil {
+		return errorDiagnostics(fmt.Errorf("could not update DVS: %w", err), nil)
 	}
 
 	// Modify network I/O control if necessary
 	if d.HasChange("network_resource_control_enabled") {
-		err = enableDVSNetworkResourceManagement(client, dvs, d.Get("network_resource_control_enabled").(bool))
+		err = enableDVSNetworkResourceManagement(ctx, client, dvs, d.Get("network_resource_control_enabled").(bool))
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, dvs.Reference())); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, object.NewReference(client.Client, dvs.Reference()), meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Apply custom attribute updates
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(dvs); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
-	return resourceVSphereDistributedVirtualSwitchRead(d, meta)
+	return resourceVSphereDistributedVirtualSwitchRead(ctx, d, meta)
 }
 
-func resourceVSphereDistributedVirtualSwitchDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDistributedVirtualSwitchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "vsphere_distributed_virtual_switch"); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	id := d.Id()
 	dvs, err := dvsFromUUID(client, id)
 	if err != nil {
-		return fmt.Errorf("could not find DVS %q: %s", id, err)
+		return errorDiagnostics(fmt.Errorf("could not find DVS %q: %w", id, err), nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
 	task, err := dvs.Destroy(ctx)
 	if err != nil {
-		return fmt.Errorf("error deleting DVS: %s", err)
+		return errorDiagnostics(fmt.Errorf("error deleting DVS: %w", err), nil)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer tcancel()
-	if err := task.Wait(tctx); err != nil {
-		return fmt.Errorf("error waiting for DVS deletion to complete: %s", err)
+	if _, err := viapi.WaitForTask(ctx, task); err != nil {
+		return errorDiagnostics(fmt.Errorf("error waiting for DVS deletion to complete: %w", err), nil)
 	}
 
 	return nil
 }
 
-func resourceVSphereDistributedVirtualSwitchImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDistributedVirtualSwitchImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// Due to the relative difficulty in trying to fetch a DVS's UUID, we use the
 	// inventory path to the DVS instead, and just run it through finder. A full
 	// path is required unless the default datacenter can be utilized.
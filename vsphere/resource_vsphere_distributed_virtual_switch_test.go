@@ -25,7 +25,7 @@ const (
 )
 
 func TestAccResourceVSphereDistributedVirtualSwitch_basic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -62,7 +62,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_basic(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_noHosts(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -82,7 +82,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_noHosts(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_removeNIC(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -108,7 +108,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_removeNIC(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_standbyWithExplicitFailoverOrder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -131,7 +131,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_standbyWithExplicitFailoverO
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_basicToStandbyWithFailover(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -160,7 +160,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_basicToStandbyWithFailover(t
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_upgradeVersion(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -188,7 +188,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_upgradeVersion(t *testing.T)
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_networkResourceControl(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -212,7 +212,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_networkResourceControl(t *te
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_explicitUplinks(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -233,7 +233,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_explicitUplinks(t *testing.T
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_modifyUplinks(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -273,7 +273,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_modifyUplinks(t *testing.T)
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_inFolder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -294,7 +294,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_inFolder(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_singleTag(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -315,7 +315,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_singleTag(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_modifyTags(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -343,7 +343,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_modifyTags(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_netflow(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -364,7 +364,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_netflow(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_vlanRanges(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -386,7 +386,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_vlanRanges(t *testing.T) {
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_singleCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -407,7 +407,7 @@ func TestAccResourceVSphereDistributedVirtualSwitch_singleCustomAttribute(t *tes
 }
 
 func TestAccResourceVSphereDistributedVirtualSwitch_multiCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
@@ -25,12 +27,12 @@ const resourceVSphereDPMHostOverrideName = "vsphere_dpm_host_override"
 
 func resourceVSphereDPMHostOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDPMHostOverrideCreate,
-		Read:   resourceVSphereDPMHostOverrideRead,
-		Update: resourceVSphereDPMHostOverrideUpdate,
-		Delete: resourceVSphereDPMHostOverrideDelete,
+		CreateContext: resourceVSphereDPMHostOverrideCreate,
+		ReadContext:   resourceVSphereDPMHostOverrideRead,
+		UpdateContext: resourceVSphereDPMHostOverrideUpdate,
+		DeleteContext: resourceVSphereDPMHostOverrideDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDPMHostOverrideImport,
+			StateContext: resourceVSphereDPMHostOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -63,17 +65,17 @@ func resourceVSphereDPMHostOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereDPMHostOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDPMHostOverrideIDString(d))
 
 	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDpmHostConfigInfo(d, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DpmHostConfigSpec: []types.ClusterDpmHostConfigSpec{
@@ -86,31 +88,31 @@ func resourceVSphereDPMHostOverrideCreate(d *schema.ResourceData, meta interface
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereDPMHostOverrideFlattenID(cluster, host)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDPMHostOverrideIDString(d))
-	return resourceVSphereDPMHostOverrideRead(d, meta)
+	return resourceVSphereDPMHostOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDPMHostOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDPMHostOverrideIDString(d))
 
 	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereDPMHostOverrideFindEntry(cluster, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -123,32 +125,32 @@ func resourceVSphereDPMHostOverrideRead(d *schema.ResourceData, meta interface{}
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
 	if err = d.Set("host_system_id", host.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"host_system_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"host_system_id\": %s", err))
 	}
 
 	if err = flattenClusterDpmHostConfigInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDPMHostOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDPMHostOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDPMHostOverrideIDString(d))
 
 	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDpmHostConfigInfo(d, host)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DpmHostConfigSpec: []types.ClusterDpmHostConfigSpec{
@@ -161,20 +163,20 @@ func resourceVSphereDPMHostOverrideUpdate(d *schema.ResourceData, meta interface
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDPMHostOverrideIDString(d))
-	return resourceVSphereDPMHostOverrideRead(d, meta)
+	return resourceVSphereDPMHostOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDPMHostOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDPMHostOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereDPMHostOverrideIDString(d))
 
 	cluster, host, err := resourceVSphereDPMHostOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -188,15 +190,15 @@ func resourceVSphereDPMHostOverrideDelete(d *schema.ResourceData, meta interface
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereDPMHostOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDPMHostOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDPMHostOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -283,7 +285,7 @@ func resourceVSphereDPMHostOverrideFindEntry(
 	cluster *object.ClusterComputeResource,
 	host *object.HostSystem,
 ) (*types.ClusterDpmHostConfigInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
@@ -25,12 +27,12 @@ const resourceVSphereDRSVMOverrideName = "vsphere_drs_vm_override"
 
 func resourceVSphereDRSVMOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereDRSVMOverrideCreate,
-		Read:   resourceVSphereDRSVMOverrideRead,
-		Update: resourceVSphereDRSVMOverrideUpdate,
-		Delete: resourceVSphereDRSVMOverrideDelete,
+		CreateContext: resourceVSphereDRSVMOverrideCreate,
+		ReadContext:   resourceVSphereDRSVMOverrideRead,
+		UpdateContext: resourceVSphereDRSVMOverrideUpdate,
+		DeleteContext: resourceVSphereDRSVMOverrideDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereDRSVMOverrideImport,
+			StateContext: resourceVSphereDRSVMOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -63,17 +65,17 @@ func resourceVSphereDRSVMOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereDRSVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereDRSVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DrsVmConfigSpec: []types.ClusterDrsVmConfigSpec{
@@ -86,31 +88,31 @@ func resourceVSphereDRSVMOverrideCreate(d *schema.ResourceData, meta interface{}
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereDRSVMOverrideFlattenID(cluster, vm)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereDRSVMOverrideIDString(d))
-	return resourceVSphereDRSVMOverrideRead(d, meta)
+	return resourceVSphereDRSVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDRSVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereDRSVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereDRSVMOverrideFindEntry(cluster, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -123,36 +125,36 @@ func resourceVSphereDRSVMOverrideRead(d *schema.ResourceData, meta interface{})
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config.uuid")
 	if err != nil {
-		return fmt.Errorf("error getting properties of virtual machine: %s", err)
+		return diag.FromErr(fmt.Errorf("error getting properties of virtual machine: %s", err))
 	}
 	if err = d.Set("virtual_machine_id", props.Config.Uuid); err != nil {
-		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err))
 	}
 
 	if err = flattenClusterDrsVMConfigInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereDRSVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDRSVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereDRSVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DrsVmConfigSpec: []types.ClusterDrsVmConfigSpec{
@@ -168,20 +170,20 @@ func resourceVSphereDRSVMOverrideUpdate(d *schema.ResourceData, meta interface{}
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereDRSVMOverrideIDString(d))
-	return resourceVSphereDRSVMOverrideRead(d, meta)
+	return resourceVSphereDRSVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereDRSVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereDRSVMOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereDRSVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereDRSVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -195,15 +197,15 @@ func resourceVSphereDRSVMOverrideDelete(d *schema.ResourceData, meta interface{}
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereDRSVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereDRSVMOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereDRSVMOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -271,7 +273,7 @@ func resourceVSphereDRSVMOverrideIDString(d structure.ResourceIDStringer) string
 // vsphere_drs_vm_override resource.
 func resourceVSphereDRSVMOverrideFlattenID(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
 	clusterID := cluster.Reference().Value
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config.uuid")
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
@@ -296,7 +298,7 @@ func resourceVSphereDRSVMOverrideFindEntry(
 	cluster *object.ClusterComputeResource,
 	vm *object.VirtualMachine,
 ) (*types.ClusterDrsVmConfigInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
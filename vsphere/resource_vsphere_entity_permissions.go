@@ -12,9 +12,12 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/administrationroles"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/utils"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/types"
 )
@@ -42,16 +45,19 @@ func resourceVsphereEntityPermissions() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create:        resourceEntityPermissionsCreate,
-		Read:          resourceEntityPermissionsRead,
-		Update:        resourceEntityPermissionsUpdate,
-		Delete:        resourceEntityPermissionsDelete,
+		CreateContext: resourceEntityPermissionsCreate,
+		ReadContext:   resourceEntityPermissionsRead,
+		UpdateContext: resourceEntityPermissionsUpdate,
+		DeleteContext: resourceEntityPermissionsDelete,
 		CustomizeDiff: resourceVSphereEntityPermissionsCustomizeDiff,
-		Schema:        sch,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceEntityPermissionsImport,
+		},
+		Schema: sch,
 	}
 }
 
-func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning create permission for entity id %s", d.Get("entity_id").(string))
 	client := meta.(*Client).vimClient
 
@@ -62,7 +68,7 @@ func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) e
 	entityID := d.Get("entity_id").(string)
 	entityMoid, err := utils.GetMoid(client, entityType, entityID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	entityMor := types.ManagedObjectReference{
 		Type:  entityType,
@@ -74,12 +80,12 @@ func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) e
 	for _, permission := range permissions {
 		userOrGroup := permission.(map[string]interface{})["user_or_group"].(string)
 		if usersAndGroupsMap[userOrGroup] {
-			return fmt.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup)
+			return diag.FromErr(fmt.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup))
 		}
 		usersAndGroupsMap[userOrGroup] = true
 		roleIDInt, err := strconv.ParseInt(permission.(map[string]interface{})["role_id"].(string), 10, 32)
 		if err != nil {
-			return fmt.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string))
+			return diag.FromErr(fmt.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string)))
 		}
 		roleID := int32(roleIDInt)
 		permissionObjs = append(permissionObjs, types.Permission{
@@ -89,15 +95,15 @@ func resourceEntityPermissionsCreate(d *schema.ResourceData, meta interface{}) e
 			RoleId:    roleID,
 		})
 	}
-	err = authorizationManager.SetEntityPermissions(context.Background(), entityMor, permissionObjs)
+	err = authorizationManager.SetEntityPermissions(ctx, entityMor, permissionObjs)
 	if err != nil {
-		return fmt.Errorf("error while creating permission for entity id %s %s", entityID, err)
+		return diag.FromErr(fmt.Errorf("error while creating permission for entity id %s %s", entityID, err))
 	}
 	d.SetId(entityMoid)
-	return resourceEntityPermissionsRead(d, meta)
+	return resourceEntityPermissionsRead(ctx, d, meta)
 }
 
-func resourceEntityPermissionsRead(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	entityType := d.Get("entity_type").(string)
 	log.Printf(" [DEBUG] : Reading vm entity permissions for entity id %s and type %s", d.Id(), entityType)
 	client := meta.(*Client).vimClient
@@ -107,9 +113,9 @@ func resourceEntityPermissionsRead(d *schema.ResourceData, meta interface{}) err
 		Type:  entityType,
 		Value: d.Id(),
 	}
-	permissionsArr, err := authorizationManager.RetrieveEntityPermissions(context.Background(), entityMor, false)
+	permissionsArr, err := authorizationManager.RetrieveEntityPermissions(ctx, entityMor, false)
 	if err != nil {
-		return fmt.Errorf("error while reading permissions for entity %s %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("error while reading permissions for entity %s %s", d.Id(), err))
 	}
 	if len(permissionsArr) == 0 {
 		log.Printf(" [DEBUG] :the permissions for entity with id %s and type %s is not found", d.Id(), entityType)
@@ -135,7 +141,7 @@ func resourceEntityPermissionsRead(d *schema.ResourceData, meta interface{}) err
 	return nil
 }
 
-func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	if d.HasChange("permissions") {
 		oldPermissions, newPermissions := d.GetChange("permissions")
 		log.Printf(" [DEBUG] : Beginning update Permission with entity id %s", d.Id())
@@ -154,12 +160,12 @@ func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) e
 		for _, permission := range newPermissions.([]interface{}) {
 			userOrGroup := permission.(map[string]interface{})["user_or_group"].(string)
 			if usersAndGroups[strings.ToLower(userOrGroup)] {
-				return fmt.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup)
+				return diag.FromErr(fmt.Errorf("user/group %s repeated, there is already a permission defined for the user/group", userOrGroup))
 			}
 			usersAndGroups[strings.ToLower(userOrGroup)] = true
 			roleIDInt, err := strconv.ParseInt(permission.(map[string]interface{})["role_id"].(string), 10, 32)
 			if err != nil {
-				return fmt.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string))
+				return diag.FromErr(fmt.Errorf("error while converting role id %s to integer", permission.(map[string]interface{})["role_id"].(string)))
 			}
 			roleID := int32(roleIDInt)
 			permissionObjs = append(permissionObjs, types.Permission{
@@ -169,9 +175,9 @@ func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) e
 				RoleId:    roleID,
 			})
 		}
-		err := authorizationManager.SetEntityPermissions(context.Background(), entityMor, permissionObjs)
+		err := authorizationManager.SetEntityPermissions(ctx, entityMor, permissionObjs)
 		if err != nil {
-			return fmt.Errorf("error while updating permissions for entity id %s %s", d.Id(), err)
+			return diag.FromErr(fmt.Errorf("error while updating permissions for entity id %s %s", d.Id(), err))
 		}
 
 		// handle removed permissions
@@ -181,17 +187,17 @@ func resourceEntityPermissionsUpdate(d *schema.ResourceData, meta interface{}) e
 
 			if !usersAndGroups[strings.ToLower(userOrGroup)] {
 				log.Printf(" [DEBUG] Deleting permissions for user/group %s", userOrGroup)
-				err = authorizationManager.RemoveEntityPermission(context.Background(), entityMor, userOrGroup, isGroup)
+				err = authorizationManager.RemoveEntityPermission(ctx, entityMor, userOrGroup, isGroup)
 				if err != nil {
-					return fmt.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err)
+					return diag.FromErr(fmt.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err))
 				}
 			}
 		}
 	}
-	return resourceEntityPermissionsRead(d, meta)
+	return resourceEntityPermissionsRead(ctx, d, meta)
 }
 
-func resourceEntityPermissionsDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceEntityPermissionsDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf(" [DEBUG] Performing Delete of Entity permission %s", d.Id())
 	client := meta.(*Client).vimClient
 	authorizationManager := object.NewAuthorizationManager(client.Client)
@@ -206,9 +212,9 @@ func resourceEntityPermissionsDelete(d *schema.ResourceData, meta interface{}) e
 		userOrGroup := permission.(map[string]interface{})["user_or_group"].(string)
 		isGroup := permission.(map[string]interface{})["is_group"].(bool)
 		log.Printf(" [DEBUG] Deleting permissions for user/group %s", userOrGroup)
-		err := authorizationManager.RemoveEntityPermission(context.Background(), entityMor, userOrGroup, isGroup)
+		err := authorizationManager.RemoveEntityPermission(ctx, entityMor, userOrGroup, isGroup)
 		if err != nil {
-			return fmt.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err)
+			return diag.FromErr(fmt.Errorf("error while deleting permission for the user/group %s %s", userOrGroup, err))
 		}
 	}
 	d.SetId("")
@@ -216,6 +222,69 @@ func resourceEntityPermissionsDelete(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
+// resourceEntityPermissionsImport imports the permissions of the entity with
+// an ID in the form entity_type:moid, such as Folder:group-v123.
+func resourceEntityPermissionsImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts := strings.SplitN(d.Id(), ":", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("invalid import ID %q, expected entity_type:moid, such as VirtualMachine:vm-123", d.Id())
+	}
+	entityType, entityMoid := parts[0], parts[1]
+	client := meta.(*Client).vimClient
+	entityMor := types.ManagedObjectReference{
+		Type:  entityType,
+		Value: entityMoid,
+	}
+	permissions, err := object.NewAuthorizationManager(client.Client).RetrieveEntityPermissions(ctx, entityMor, false)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading permissions for entity %s %s", d.Id(), err)
+	}
+	if len(permissions) == 0 {
+		return nil, fmt.Errorf("no permissions are set on entity %s", d.Id())
+	}
+	entityID, err := entityPermissionsEntityID(client, entityMor)
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(entityMoid)
+	_ = d.Set("entity_id", entityID)
+	_ = d.Set("entity_type", entityType)
+	return []*schema.ResourceData{d}, nil
+}
+
+// entityPermissionsEntityID returns the ID that configuration refers to the
+// entity ref with. This is the UUID of virtual machines and distributed
+// switches, which is the ID of their resources and data sources, and the
+// managed object ID of other entities.
+func entityPermissionsEntityID(client *govmomi.Client, ref types.ManagedObjectReference) (string, error) {
+	switch ref.Type {
+	case utils.VM:
+		vm, err := virtualmachine.FromMOID(client, ref.Value)
+		if err != nil {
+			return "", err
+		}
+		props, err := virtualmachine.Properties(vm, "config.uuid")
+		if err != nil {
+			return "", err
+		}
+		if props.Config == nil {
+			return "", fmt.Errorf("virtual machine %s has no configuration", ref.Value)
+		}
+		return props.Config.Uuid, nil
+	case utils.DISTRIBUTEDVIRTUALSWITCH:
+		dvs, err := dvsFromMOID(client, ref.Value)
+		if err != nil {
+			return "", err
+		}
+		props, err := dvsProperties(dvs)
+		if err != nil {
+			return "", err
+		}
+		return props.Uuid, nil
+	}
+	return ref.Value, nil
+}
+
 func resourceVSphereEntityPermissionsCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
 	if d.HasChange("entity_id") {
 		oldEntityID, newEntityID := d.GetChange("entity_id")
@@ -4,9 +4,11 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 	"testing"
 
@@ -14,6 +16,8 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 const EntityPermissionResource = "entity_permission1"
@@ -35,6 +39,18 @@ func TestAccResourcevsphereEntityPermissions_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("vsphere_entity_permissions."+EntityPermissionResource, "permissions.0.is_group", "true"),
 				),
 			},
+			{
+				ResourceName: "vsphere_entity_permissions." + EntityPermissionResource,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					rs, ok := s.RootModule().Resources["vsphere_entity_permissions."+EntityPermissionResource]
+					if !ok {
+						return "", errors.New("entity permissions not found in state")
+					}
+					return "VirtualMachine:" + rs.Primary.ID, nil
+				},
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -56,7 +72,77 @@ func testAccResourceEntityPermissionsCheckExists(expected bool) resource.TestChe
 	}
 }
 
-func testAccResourceVsphereEntityPermissionsConfigBasic() string {
+func TestAccResourcevsphereEntityPermissions_import(t *testing.T) {
+	var state *terraform.State
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceEntityPermissionsCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVsphereEntityPermissionsConfigData(),
+				Check:  copyStatePtr(&state),
+			},
+			{
+				PreConfig: func() {
+					if err := testAccResourceVsphereEntityPermissionsCreateOOB(state); err != nil {
+						panic(err)
+					}
+				},
+				Config:       testAccResourceVsphereEntityPermissionsConfigBasic(),
+				ResourceName: "vsphere_entity_permissions." + EntityPermissionResource,
+				ImportState:  true,
+				ImportStateIdFunc: func(s *terraform.State) (string, error) {
+					vm, ok := s.RootModule().Resources["data.vsphere_virtual_machine.vm"]
+					if !ok {
+						return "", errors.New("virtual machine not found in state")
+					}
+					return "VirtualMachine:" + vm.Primary.Attributes["moid"], nil
+				},
+				ImportStatePersist: true,
+			},
+			{
+				Config:   testAccResourceVsphereEntityPermissionsConfigBasic(),
+				PlanOnly: true,
+			},
+		},
+	})
+}
+
+// testAccResourceVsphereEntityPermissionsCreateOOB sets the permissions of
+// testAccResourceVsphereEntityPermissionsConfigBasic on the virtual machine in
+// state, outside of Terraform.
+func testAccResourceVsphereEntityPermissionsCreateOOB(s *terraform.State) error {
+	vm, ok := s.RootModule().Resources["data.vsphere_virtual_machine.vm"]
+	if !ok {
+		return errors.New("virtual machine not found in state")
+	}
+	role, ok := s.RootModule().Resources["data.vsphere_role.role1"]
+	if !ok {
+		return errors.New("role not found in state")
+	}
+	roleID, err := strconv.ParseInt(role.Primary.ID, 10, 32)
+	if err != nil {
+		return err
+	}
+	client := testAccProvider.Meta().(*Client).vimClient
+	entity := types.ManagedObjectReference{
+		Type:  "VirtualMachine",
+		Value: vm.Primary.Attributes["moid"],
+	}
+	return object.NewAuthorizationManager(client.Client).SetEntityPermissions(context.Background(), entity, []types.Permission{
+		{
+			Principal: "root",
+			Group:     true,
+			Propagate: true,
+			RoleId:    int32(roleID),
+		},
+	})
+}
+
+func testAccResourceVsphereEntityPermissionsConfigData() string {
 	return fmt.Sprintf(`
 %s
 
@@ -68,6 +154,15 @@ func testAccResourceVsphereEntityPermissionsConfigBasic() string {
 	data "vsphere_role" "role1" {
 	  label = "Administrator"
 	}
+`,
+		testhelper.ConfigDataRootDC1(),
+		os.Getenv("TF_VAR_VSPHERE_VM_V1_PATH"),
+	)
+}
+
+func testAccResourceVsphereEntityPermissionsConfigBasic() string {
+	return fmt.Sprintf(`
+%s
 
    resource vsphere_entity_permissions "%s" {
 	   entity_id = data.vsphere_virtual_machine.vm.id
@@ -80,8 +175,7 @@ func testAccResourceVsphereEntityPermissionsConfigBasic() string {
 	   }
    }
 `,
-		testhelper.ConfigDataRootDC1(),
-		os.Getenv("TF_VAR_VSPHERE_VM_V1_PATH"),
+		testAccResourceVsphereEntityPermissionsConfigData(),
 		EntityPermissionResource,
 		"root",
 	)
@@ -8,16 +8,23 @@ import (
 	"fmt"
 	"log"
 	"path"
+	"regexp"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 	"github.com/vmware/govmomi/vim25/soap"
 )
 
+// resourceVSphereFileImportIDRegexp matches the ID a file is imported with,
+// capturing its datastore, datacenter and path.
+var resourceVSphereFileImportIDRegexp = regexp.MustCompile(`^\[([^\]]+)\] ([^/]*)/(.+)$`)
+
 type file struct {
 	sourceDatacenter  string
 	datacenter        string
@@ -31,10 +38,19 @@ type file struct {
 
 func resourceVSphereFile() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereFileCreate,
-		Read:   resourceVSphereFileRead,
-		Update: resourceVSphereFileUpdate,
-		Delete: resourceVSphereFileDelete,
+		CreateContext: resourceVSphereFileCreate,
+		ReadContext:   resourceVSphereFileRead,
+		UpdateContext: resourceVSphereFileUpdate,
+		DeleteContext: resourceVSphereFileDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereFileImport,
+		},
+		CustomizeDiff: resourceVSphereFileCustomizeDiff,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"datacenter": {
@@ -45,7 +61,6 @@ func resourceVSphereFile() *schema.Resource {
 			"source_datacenter": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 
 			"datastore": {
@@ -56,13 +71,11 @@ func resourceVSphereFile() *schema.Resource {
 			"source_datastore": {
 				Type:     schema.TypeString,
 				Optional: true,
-				ForceNew: true,
 			},
 
 			"source_file": {
 				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
 			},
 
 			"destination_file": {
@@ -73,12 +86,18 @@ func resourceVSphereFile() *schema.Resource {
 			"create_directories": {
 				Type:     schema.TypeBool,
 				Optional: true,
+				// Directories are only created along with the file, so changes
+				// to this once the file exists, such as after an import, have
+				// no effect.
+				DiffSuppressFunc: func(_, _, _ string, d *schema.ResourceData) bool {
+					return d.Id() != ""
+				},
 			},
 		},
 	}
 }
 
-func resourceVSphereFileCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] creating file: %#v", d)
 	client := meta.(*Client).vimClient
 
@@ -101,37 +120,37 @@ func resourceVSphereFileCreate(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("datastore"); ok {
 		f.datastore = v.(string)
 	} else {
-		return fmt.Errorf("datastore argument is required")
+		return diag.FromErr(fmt.Errorf("datastore argument is required"))
 	}
 
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
 	} else {
-		return fmt.Errorf("source_file argument is required")
+		return diag.FromErr(fmt.Errorf("source_file argument is required"))
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
-		return fmt.Errorf("destination_file argument is required")
+		return diag.FromErr(fmt.Errorf("destination_file argument is required"))
 	}
 
 	if v, ok := d.GetOk("create_directories"); ok {
 		f.createDirectories = v.(bool)
 	}
 
-	err := createFile(client, &f)
+	err := createFile(ctx, client, &f)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("[%v] %v/%v", f.datastore, f.datacenter, f.destinationFile))
 	log.Printf("[INFO] Created file: %s", f.destinationFile)
 
-	return resourceVSphereFileRead(d, meta)
+	return resourceVSphereFileRead(ctx, d, meta)
 }
 
-func createDirectory(datastoreFileManager *object.DatastoreFileManager, f *file) error {
+func createDirectory(ctx context.Context, datastoreFileManager *object.DatastoreFileManager, f *file) error {
 	directoryPathIndex := strings.LastIndex(f.destinationFile, "/")
 	if directoryPathIndex < 0 {
 		// there are no parent directories in the file's name - nothing to create
@@ -139,7 +158,7 @@ func createDirectory(datastoreFileManager *object.DatastoreFileManager, f *file)
 	}
 
 	targetPath := f.destinationFile[0:directoryPathIndex]
-	err := datastoreFileManager.FileManager.MakeDirectory(context.TODO(),
+	err := datastoreFileManager.FileManager.MakeDirectory(ctx,
 		datastoreFileManager.Datastore.Path(targetPath), datastoreFileManager.Datacenter, true)
 	if err != nil {
 		return err
@@ -148,14 +167,14 @@ func createDirectory(datastoreFileManager *object.DatastoreFileManager, f *file)
 }
 
 // fileUpload - upload file to a vSphere datastore
-func fileUpload(client *govmomi.Client, dc *object.Datacenter, ds *object.Datastore, source, destination string) error {
-	dsurl, err := ds.URL(context.TODO(), dc, destination)
+func fileUpload(ctx context.Context, client *govmomi.Client, dc *object.Datacenter, ds *object.Datastore, source, destination string) error {
+	dsurl, err := ds.URL(ctx, dc, destination)
 	if err != nil {
 		return err
 	}
 
 	p := soap.DefaultUpload
-	err = client.Client.UploadFile(context.TODO(), source, dsurl, &p)
+	err = client.Client.UploadFile(ctx, source, dsurl, &p)
 	if err != nil {
 		return err
 	}
@@ -163,23 +182,23 @@ func fileUpload(client *govmomi.Client, dc *object.Datacenter, ds *object.Datast
 	return nil
 }
 
-func createFile(client *govmomi.Client, f *file) error {
+func createFile(ctx context.Context, client *govmomi.Client, f *file) error {
 	finder := find.NewFinder(client.Client, true)
 
-	dstDatacenter, err := finder.Datacenter(context.TODO(), f.datacenter)
+	dstDatacenter, err := finder.Datacenter(ctx, f.datacenter)
 	if err != nil {
 		return fmt.Errorf("error %s", err)
 	}
 	finder = finder.SetDatacenter(dstDatacenter)
 
-	dstDatastore, err := getDatastore(finder, f.datastore)
+	dstDatastore, err := getDatastore(ctx, finder, f.datastore)
 	if err != nil {
 		return fmt.Errorf("error %s", err)
 	}
 	dstDfm := dstDatastore.NewFileManager(dstDatacenter, false)
 
 	if f.createDirectories {
-		err = createDirectory(dstDfm, f)
+		err = createDirectory(ctx, dstDfm, f)
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -187,18 +206,18 @@ func createFile(client *govmomi.Client, f *file) error {
 
 	switch {
 	case f.copyFile:
-		srcDatacenter, err := finder.Datacenter(context.TODO(), f.sourceDatacenter)
+		srcDatacenter, err := finder.Datacenter(ctx, f.sourceDatacenter)
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
-		srcDatastore, err := getDatastore(finder, f.sourceDatastore)
+		srcDatastore, err := getDatastore(ctx, finder, f.sourceDatastore)
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
 		srcDfm := srcDatastore.NewFileManager(srcDatacenter, false)
 		srcDfm.DatacenterTarget = dstDatacenter
 		dstFilePath := dstDfm.Path(f.destinationFile)
-		err = srcDfm.Copy(context.TODO(), f.sourceFile, dstFilePath.String())
+		err = srcDfm.Copy(ctx, f.sourceFile, dstFilePath.String())
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -207,17 +226,17 @@ func createFile(client *govmomi.Client, f *file) error {
 		// Temporary directory path to upload VMDK file.
 		tempDstFile := fmt.Sprintf("tfm-temp-%d/%s", time.Now().Nanosecond(), fileName)
 
-		err = fileUpload(client, dstDatacenter, dstDatastore, f.sourceFile, tempDstFile)
+		err = fileUpload(ctx, client, dstDatacenter, dstDatastore, f.sourceFile, tempDstFile)
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
-		err = dstDfm.Move(context.TODO(), tempDstFile, f.destinationFile)
+		err = dstDfm.Move(ctx, tempDstFile, f.destinationFile)
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
 
 	default:
-		err = fileUpload(client, dstDatacenter, dstDatastore, f.sourceFile, f.destinationFile)
+		err = fileUpload(ctx, client, dstDatacenter, dstDatastore, f.sourceFile, f.destinationFile)
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -226,7 +245,7 @@ func createFile(client *govmomi.Client, f *file) error {
 	return nil
 }
 
-func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] reading file: %#v", d)
 	f := file{}
 
@@ -245,50 +264,92 @@ func resourceVSphereFileRead(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("datastore"); ok {
 		f.datastore = v.(string)
 	} else {
-		return fmt.Errorf("datastore argument is required")
+		return diag.FromErr(fmt.Errorf("datastore argument is required"))
 	}
 
+	// The source of an imported file is not known, and is not needed here.
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
-	} else {
-		return fmt.Errorf("source_file argument is required")
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
-		return fmt.Errorf("destination_file argument is required")
+		return diag.FromErr(fmt.Errorf("destination_file argument is required"))
 	}
 
 	client := meta.(*Client).vimClient
 	finder := find.NewFinder(client.Client, true)
 
-	dc, err := finder.Datacenter(context.TODO(), f.datacenter)
+	dc, err := finder.Datacenter(ctx, f.datacenter)
 	if err != nil {
-		return fmt.Errorf("error %s", err)
+		return diag.FromErr(fmt.Errorf("error %s", err))
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, f.datastore)
+	ds, err := getDatastore(ctx, finder, f.datastore)
 	if err != nil {
-		return fmt.Errorf("error %s", err)
+		return diag.FromErr(fmt.Errorf("error %s", err))
 	}
 
-	_, err = ds.Stat(context.TODO(), f.destinationFile)
+	_, err = ds.Stat(ctx, f.destinationFile)
 	if err != nil {
 		log.Printf("[DEBUG] resourceVSphereFileRead - stat failed on: %v", f.destinationFile)
 		d.SetId("")
 
 		_, ok := err.(object.DatastoreNoSuchFileError)
 		if !ok {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVSphereFileUpdate(d *schema.ResourceData, meta interface{}) error {
+// resourceVSphereFileImport imports a file with an ID in the same form as the
+// ID of the resource, [datastore] datacenter/path. The datacenter can be left
+// out, as in [datastore] /path, when there is only one.
+func resourceVSphereFileImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	m := resourceVSphereFileImportIDRegexp.FindStringSubmatch(d.Id())
+	if m == nil {
+		return nil, fmt.Errorf("invalid import ID %q, expected [datastore] datacenter/path", d.Id())
+	}
+	if err := d.Set("datastore", m[1]); err != nil {
+		return nil, err
+	}
+	if err := d.Set("datacenter", m[2]); err != nil {
+		return nil, err
+	}
+	if err := d.Set("destination_file", m[3]); err != nil {
+		return nil, err
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceVSphereFileCustomizeDiff replaces a file when its source changes.
+//
+// The source of a file cannot be read back, so an import leaves source_file
+// empty. The first plan after an import updates the source in place instead,
+// which only records the source in the configuration in state and keeps the
+// file in the datastore. Changes to the source after that replace the file.
+func resourceVSphereFileCustomizeDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() == "" {
+		return nil
+	}
+	if old, _ := d.GetChange("source_file"); old.(string) == "" {
+		return nil
+	}
+	for _, k := range []string{"source_datacenter", "source_datastore", "source_file"} {
+		if d.HasChange(k) {
+			if err := d.ForceNew(k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func resourceVSphereFileUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] updating file: %#v", d)
 
 	if d.HasChange("destination_file") || d.HasChange("datacenter") || d.HasChange("datastore") {
@@ -323,40 +384,40 @@ func resourceVSphereFileUpdate(d *schema.ResourceData, meta interface{}) error {
 		client := meta.(*Client).vimClient
 		dcOld, err := getDatacenter(client, oldDataceneter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		dcNew, err := getDatacenter(client, newDatacenter)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 		finder := find.NewFinder(client.Client, true)
 		finder = finder.SetDatacenter(dcOld)
-		dsOld, err := getDatastore(finder, oldDatastore)
+		dsOld, err := getDatastore(ctx, finder, oldDatastore)
 		if err != nil {
-			return fmt.Errorf("error %s", err)
+			return diag.FromErr(fmt.Errorf("error %s", err))
 		}
 		finder = finder.SetDatacenter(dcNew)
-		dsNew, err := getDatastore(finder, newDatastore)
+		dsNew, err := getDatastore(ctx, finder, newDatastore)
 		if err != nil {
-			return fmt.Errorf("error %s", err)
+			return diag.FromErr(fmt.Errorf("error %s", err))
 		}
 
 		// Move file between old/new dataceter, datastore and path (destination_file)
 		fm := object.NewFileManager(client.Client)
-		task, err := fm.MoveDatastoreFile(context.TODO(), dsOld.Path(oldDestinationFile), dcOld, dsNew.Path(newDestinationFile), dcNew, true)
+		task, err := fm.MoveDatastoreFile(ctx, dsOld.Path(oldDestinationFile), dcOld, dsNew.Path(newDestinationFile), dcNew, true)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		_, err = task.WaitForResult(context.TODO(), nil)
+		_, err = viapi.WaitForTask(ctx, task)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFileDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] deleting file: %#v", d)
 	f := file{}
 
@@ -367,33 +428,31 @@ func resourceVSphereFileDelete(d *schema.ResourceData, meta interface{}) error {
 	if v, ok := d.GetOk("datastore"); ok {
 		f.datastore = v.(string)
 	} else {
-		return fmt.Errorf("datastore argument is required")
+		return diag.FromErr(fmt.Errorf("datastore argument is required"))
 	}
 
 	if v, ok := d.GetOk("source_file"); ok {
 		f.sourceFile = v.(string)
-	} else {
-		return fmt.Errorf("source_file argument is required")
 	}
 
 	if v, ok := d.GetOk("destination_file"); ok {
 		f.destinationFile = v.(string)
 	} else {
-		return fmt.Errorf("destination_file argument is required")
+		return diag.FromErr(fmt.Errorf("destination_file argument is required"))
 	}
 
 	client := meta.(*Client).vimClient
 
-	err := deleteFile(client, &f)
+	err := deleteFile(ctx, client, &f)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
 	return nil
 }
 
-func deleteFile(client *govmomi.Client, f *file) error {
+func deleteFile(ctx context.Context, client *govmomi.Client, f *file) error {
 
 	dc, err := getDatacenter(client, f.datacenter)
 	if err != nil {
@@ -403,7 +462,7 @@ func deleteFile(client *govmomi.Client, f *file) error {
 	finder := find.NewFinder(client.Client, true)
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, f.datastore)
+	ds, err := getDatastore(ctx, finder, f.datastore)
 	if err != nil {
 		return fmt.Errorf("error %s", err)
 	}
@@ -411,12 +470,12 @@ func deleteFile(client *govmomi.Client, f *file) error {
 	// If the source file is a VMDK, the Delete method uses the correct DeleteVirtualDisk_Task
 	if path.Ext(f.destinationFile) == ".vmdk" {
 		vdm := object.NewVirtualDiskManager(client.Client)
-		task, err := vdm.DeleteVirtualDisk(context.TODO(), ds.Path(f.destinationFile), dc)
+		task, err := vdm.DeleteVirtualDisk(ctx, ds.Path(f.destinationFile), dc)
 		if err != nil {
 			return err
 		}
 
-		_, err = task.WaitForResult(context.TODO(), nil)
+		_, err = viapi.WaitForTask(ctx, task)
 		if err != nil {
 			return err
 		}
@@ -425,12 +484,12 @@ func deleteFile(client *govmomi.Client, f *file) error {
 	} else {
 		// If the source file is not a VMDK, the Delete method uses the correct DeleteDatastoreFile_Task
 		fm := object.NewFileManager(client.Client)
-		task, err := fm.DeleteDatastoreFile(context.TODO(), ds.Path(f.destinationFile), dc)
+		task, err := fm.DeleteDatastoreFile(ctx, ds.Path(f.destinationFile), dc)
 		if err != nil {
 			return err
 		}
 
-		_, err = task.WaitForResult(context.TODO(), nil)
+		_, err = viapi.WaitForTask(ctx, task)
 		if err != nil {
 			return err
 		}
@@ -441,11 +500,11 @@ func deleteFile(client *govmomi.Client, f *file) error {
 }
 
 // getDatastore gets datastore object
-func getDatastore(f *find.Finder, ds string) (*object.Datastore, error) {
+func getDatastore(ctx context.Context, f *find.Finder, ds string) (*object.Datastore, error) {
 	if ds != "" {
-		dso, err := f.Datastore(context.TODO(), ds)
+		dso, err := f.Datastore(ctx, ds)
 		return dso, err
 	}
-	dso, err := f.DefaultDatastore(context.TODO())
+	dso, err := f.DefaultDatastore(ctx)
 	return dso, err
 }
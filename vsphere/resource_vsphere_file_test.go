@@ -11,6 +11,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
 	"github.com/vmware/govmomi/find"
 	"github.com/vmware/govmomi/object"
 )
@@ -55,11 +56,157 @@ func TestAccResourceVSphereFile_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(resourceName, "destination_file", destinationFile),
 				),
 			},
+			{
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("[%s] %s/%s", datastore, datacenter, destinationFile),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source_file"},
+			},
 		},
 	})
 	_ = os.Remove(testFile)
 }
 
+// Import of a file uploaded outside of Terraform, followed by a change to its
+// source
+func TestAccResourceVSphereFile_import(t *testing.T) {
+	testFileData := []byte("test file data")
+	testFile := "/tmp/tf_test.txt"
+	err := os.WriteFile(testFile, testFileData, 0600)
+	if err != nil {
+		t.Errorf("error %s", err)
+		return
+	}
+	changedFileData := []byte("changed test file data")
+	changedFile := "/tmp/tf_test_changed.txt"
+	err = os.WriteFile(changedFile, changedFileData, 0600)
+	if err != nil {
+		t.Errorf("error %s", err)
+		return
+	}
+
+	datacenter := os.Getenv("TF_VAR_VSPHERE_DATACENTER")
+	datastore := os.Getenv("TF_VAR_VSPHERE_NFS_DS_NAME")
+	testMethod := "import"
+	resourceName := "vsphere_file." + testMethod
+	destinationFile := "tf_file_test.txt"
+	sourceFile := testFile
+	config := fmt.Sprintf(
+		testAccCheckVSphereFileConfig,
+		testMethod,
+		datacenter,
+		datastore,
+		sourceFile,
+		destinationFile,
+	)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccCheckEnvVariables(t, []string{"TF_VAR_VSPHERE_DATACENTER", "TF_VAR_VSPHERE_NFS_DS_NAME"})
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccCheckVSphereFileDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testhelper.ConfigDataRootDC1(),
+			},
+			{
+				PreConfig: func() {
+					if err := testAccResourceVSphereFileUploadOOB(datacenter, datastore, sourceFile, destinationFile); err != nil {
+						panic(err)
+					}
+				},
+				Config:             config,
+				ResourceName:       resourceName,
+				ImportState:        true,
+				ImportStateId:      fmt.Sprintf("[%s] %s/%s", datastore, datacenter, destinationFile),
+				ImportStatePersist: true,
+			},
+			{
+				// The first apply after the import only records the source.
+				Config: config,
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereFileSize(resourceName, destinationFile, int64(len(testFileData))),
+					resource.TestCheckResourceAttr(resourceName, "source_file", sourceFile),
+				),
+			},
+			{
+				Config:   config,
+				PlanOnly: true,
+			},
+			{
+				Config:                  config,
+				ResourceName:            resourceName,
+				ImportState:             true,
+				ImportStateId:           fmt.Sprintf("[%s] %s/%s", datastore, datacenter, destinationFile),
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"source_file"},
+			},
+			{
+				Config: fmt.Sprintf(
+					testAccCheckVSphereFileConfig,
+					testMethod,
+					datacenter,
+					datastore,
+					changedFile,
+					destinationFile,
+				),
+				Check: resource.ComposeTestCheckFunc(
+					testAccCheckVSphereFileSize(resourceName, destinationFile, int64(len(changedFileData))),
+					resource.TestCheckResourceAttr(resourceName, "source_file", changedFile),
+				),
+			},
+		},
+	})
+	_ = os.Remove(testFile)
+	_ = os.Remove(changedFile)
+}
+
+func TestResourceVSphereFileCustomizeDiff(t *testing.T) {
+	cases := []struct {
+		name        string
+		source      string
+		requiresNew bool
+	}{
+		{name: "imported", source: "", requiresNew: false},
+		{name: "created", source: "/tmp/tf_test.txt", requiresNew: true},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &terraform.InstanceState{
+				ID: "[ds] dc/tf_file_test.txt",
+				Attributes: map[string]string{
+					"id":               "[ds] dc/tf_file_test.txt",
+					"datacenter":       "dc",
+					"datastore":        "ds",
+					"destination_file": "tf_file_test.txt",
+					"source_file":      tc.source,
+				},
+			}
+			config := terraform.NewResourceConfigRaw(map[string]interface{}{
+				"datacenter":       "dc",
+				"datastore":        "ds",
+				"destination_file": "tf_file_test.txt",
+				"source_file":      "/tmp/tf_test_changed.txt",
+			})
+			diff, err := resourceVSphereFile().Diff(context.Background(), state, config, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			attr, ok := diff.Attributes["source_file"]
+			if !ok {
+				t.Fatalf("expected a diff for source_file, got %#v", diff.Attributes)
+			}
+			if attr.RequiresNew != tc.requiresNew {
+				t.Fatalf("expected RequiresNew to be %t, got %t", tc.requiresNew, attr.RequiresNew)
+			}
+		})
+	}
+}
+
 // Test file creation (upload to vSphere) in non-existing folders with create_directories set to True
 func TestAccResourceVSphereFile_uploadWithCreateDirectories(t *testing.T) {
 	testFileData := []byte("test file data")
@@ -327,6 +474,25 @@ func TestAccResourceVSphereFile_uploadAndCopyAndUpdate(t *testing.T) {
 	_ = os.Remove(sourceFile)
 }
 
+// testAccResourceVSphereFileUploadOOB uploads source to destination in the
+// datastore, outside of Terraform.
+func testAccResourceVSphereFileUploadOOB(datacenter, datastore, source, destination string) error {
+	client := testAccProvider.Meta().(*Client).vimClient
+	finder := find.NewFinder(client.Client, true)
+
+	dc, err := finder.Datacenter(context.TODO(), datacenter)
+	if err != nil {
+		return err
+	}
+	finder = finder.SetDatacenter(dc)
+
+	ds, err := getDatastore(context.TODO(), finder, datastore)
+	if err != nil {
+		return err
+	}
+	return ds.UploadFile(context.TODO(), source, destination, nil)
+}
+
 func testAccCheckVSphereFileDestroy(s *terraform.State) error {
 	client := testAccProvider.Meta().(*Client).vimClient
 	finder := find.NewFinder(client.Client, true)
@@ -343,7 +509,7 @@ func testAccCheckVSphereFileDestroy(s *terraform.State) error {
 
 		finder = finder.SetDatacenter(dc)
 
-		ds, err := getDatastore(finder, rs.Primary.Attributes["datastore"])
+		ds, err := getDatastore(context.TODO(), finder, rs.Primary.Attributes["datastore"])
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -384,7 +550,7 @@ func testAccCheckVSphereFileExists(n string, df string, exists bool) resource.Te
 		}
 		finder = finder.SetDatacenter(dc)
 
-		ds, err := getDatastore(finder, rs.Primary.Attributes["datastore"])
+		ds, err := getDatastore(context.TODO(), finder, rs.Primary.Attributes["datastore"])
 		if err != nil {
 			return fmt.Errorf("error %s", err)
 		}
@@ -438,3 +604,37 @@ resource "vsphere_file" "%s" {
     create_directories = true
 }
 `
+
+// testAccCheckVSphereFileSize checks the size of the file df in the datastore
+// of the resource n.
+func testAccCheckVSphereFileSize(n string, df string, size int64) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[n]
+		if !ok {
+			return fmt.Errorf("Resource not found: %s", n)
+		}
+
+		client := testAccProvider.Meta().(*Client).vimClient
+		finder := find.NewFinder(client.Client, true)
+
+		dc, err := finder.Datacenter(context.TODO(), rs.Primary.Attributes["datacenter"])
+		if err != nil {
+			return fmt.Errorf("error %s", err)
+		}
+		finder = finder.SetDatacenter(dc)
+
+		ds, err := getDatastore(context.TODO(), finder, rs.Primary.Attributes["datastore"])
+		if err != nil {
+			return fmt.Errorf("error %s", err)
+		}
+
+		info, err := ds.Stat(context.TODO(), df)
+		if err != nil {
+			return err
+		}
+		if actual := info.GetFileInfo().FileSize; actual != size {
+			return fmt.Errorf("expected %s to be %d bytes, got %d", df, size, actual)
+		}
+		return nil
+	}
+}
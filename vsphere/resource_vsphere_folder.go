@@ -10,6 +10,7 @@ import (
 	"path"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -21,12 +22,13 @@ import (
 
 func resourceVSphereFolder() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereFolderCreate,
-		Read:   resourceVSphereFolderRead,
-		Update: resourceVSphereFolderUpdate,
-		Delete: resourceVSphereFolderDelete,
+		CreateContext: resourceVSphereFolderCreate,
+		ReadContext:   resourceVSphereFolderRead,
+		UpdateContext: resourceVSphereFolderUpdate,
+		DeleteContext: resourceVSphereFolderDelete,
+		CustomizeDiff: tagsAllCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereFolderImport,
+			StateContext: resourceVSphereFolderImport,
 		},
 
 		SchemaVersion: 1,
@@ -62,23 +64,24 @@ func resourceVSphereFolder() *schema.Resource {
 				Optional:    true,
 			},
 			// Tagging
-			vSphereTagAttributeKey: tagsSchema(),
+			vSphereTagAttributeKey:     tagsSchema(),
+			vSphereTagsAllAttributeKey: tagsAllSchema(),
 			// Custom Attributes
 			customattribute.ConfigKey: customattribute.ConfigSchema(),
 		},
 	}
 }
 
-func resourceVSphereFolderCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	ft := folder.VSphereFolderType(d.Get("type").(string))
@@ -87,10 +90,10 @@ func resourceVSphereFolderCreate(d *schema.ResourceData, meta interface{}) error
 		var err error
 		dc, err = datacenterFromID(client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return errorDiagnostics(fmt.Errorf("cannot locate datacenter: %w", err), nil)
 		}
 	} else if ft != folder.VSphereFolderTypeDatacenter {
-		return fmt.Errorf("datacenter_id cannot be empty when creating a targetFolder of type %s", ft)
+		return errorDiagnostics(fmt.Errorf("datacenter_id cannot be empty when creating a targetFolder of type %s", ft), nil)
 	}
 
 	p := d.Get("path").(string)
@@ -98,48 +101,48 @@ func resourceVSphereFolderCreate(d *schema.ResourceData, meta interface{}) error
 	// Determine the parent targetFolder
 	parent, err := folder.ParentFromPath(client, p, ft, dc)
 	if err != nil {
-		return fmt.Errorf("error trying to determine parent targetFolder: %s", err)
+		return errorDiagnostics(fmt.Errorf("error trying to determine parent targetFolder: %w", err), nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
 	targetFolder, err := parent.CreateFolder(ctx, path.Base(p))
 	if err != nil {
-		return fmt.Errorf("error creating targetFolder: %s", err)
+		return errorDiagnostics(fmt.Errorf("error creating targetFolder: %w", err), nil)
 	}
 
 	d.SetId(targetFolder.Reference().Value)
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, targetFolder); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, targetFolder, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(targetFolder); err != nil {
-			return fmt.Errorf("error setting custom attributes: %s", err)
+			return errorDiagnostics(fmt.Errorf("error setting custom attributes: %w", err), nil)
 		}
 	}
 
-	return resourceVSphereFolderRead(d, meta)
+	return resourceVSphereFolderRead(ctx, d, meta)
 }
 
-func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	fo, err := folder.FromID(client, d.Id())
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot locate folder: %w", err), nil)
 	}
 
 	// Determine the folder type first. We use the folder as the source of truth
 	// here versus the state so that we can support import.
 	ft, err := folder.FindType(fo)
 	if err != nil {
-		return fmt.Errorf("cannot determine folder type: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot determine folder type: %w", err), nil)
 	}
 
 	// Again, to support a clean import (which is done off of absolute path to
@@ -150,15 +153,15 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 		particle := folder.RootPathParticle(ft)
 		dcp, err := particle.SplitDatacenter(p)
 		if err != nil {
-			return fmt.Errorf("cannot determine datacenter path: %s", err)
+			return errorDiagnostics(fmt.Errorf("cannot determine datacenter path: %w", err), nil)
 		}
 		dc, err = getDatacenter(client, dcp)
 		if err != nil {
-			return fmt.Errorf("cannot find datacenter from path %q: %s", dcp, err)
+			return errorDiagnostics(fmt.Errorf("cannot find datacenter from path %q: %w", dcp, err), nil)
 		}
 		relative, err := particle.SplitRelative(p)
 		if err != nil {
-			return fmt.Errorf("cannot determine relative folder path: %s", err)
+			return errorDiagnostics(fmt.Errorf("cannot determine relative folder path: %w", err), nil)
 		}
 		p = relative
 	}
@@ -171,8 +174,8 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, fo, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, fo, d, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error reading tags: %w", err), nil)
 		}
 	}
 
@@ -180,7 +183,7 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 	if customattribute.IsSupported(client) {
 		moFolder, err := folder.Properties(fo)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		customattribute.ReadFromResource(moFolder.Entity(), d)
 	}
@@ -188,34 +191,34 @@ func resourceVSphereFolderRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceVSphereFolderUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	fo, err := folder.FromID(client, d.Id())
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot locate folder: %w", err), nil)
 	}
 
 	// Apply any pending tags first as it's the lesser expensive of the two
 	// operations
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, fo); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, fo, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(fo); err != nil {
-			return fmt.Errorf("error setting custom attributes: %s", err)
+			return errorDiagnostics(fmt.Errorf("error setting custom attributes: %w", err), nil)
 		}
 	}
 
@@ -224,7 +227,7 @@ func resourceVSphereFolderUpdate(d *schema.ResourceData, meta interface{}) error
 		var err error
 		dc, err = datacenterFromID(client, dcID.(string))
 		if err != nil {
-			return fmt.Errorf("cannot locate datacenter: %s", err)
+			return errorDiagnostics(fmt.Errorf("cannot locate datacenter: %w", err), nil)
 		}
 	}
 
@@ -235,46 +238,46 @@ func resourceVSphereFolderUpdate(d *schema.ResourceData, meta interface{}) error
 		oldp, newp := d.GetChange("path")
 		oldpa, err := folder.ParentFromPath(client, oldp.(string), ft, dc)
 		if err != nil {
-			return fmt.Errorf("error parsing parent folder from path %q: %s", oldp.(string), err)
+			return errorDiagnostics(fmt.Errorf("error parsing parent folder from path %q: %w", oldp.(string), err), nil)
 		}
 		newpa, err := folder.ParentFromPath(client, newp.(string), ft, dc)
 		if err != nil {
-			return fmt.Errorf("error parsing parent folder from path %q: %s", newp.(string), err)
+			return errorDiagnostics(fmt.Errorf("error parsing parent folder from path %q: %w", newp.(string), err), nil)
 		}
 		oldn := path.Base(oldp.(string))
 		newn := path.Base(newp.(string))
 
 		if oldn != newn {
 			// Folder base name has changed and needs a rename
-			if err := viapi.RenameObject(client, fo.Reference(), newn); err != nil {
-				return fmt.Errorf("could not rename folder: %s", err)
+			if err := viapi.RenameObject(ctx, client, fo.Reference(), newn); err != nil {
+				return errorDiagnostics(fmt.Errorf("could not rename folder: %w", err), nil)
 			}
 		}
 		if oldpa.Reference().Value != newpa.Reference().Value {
 			// The parent folder has changed - we need to move the folder into the
 			// new path
-			ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+			ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 			defer cancel()
 			task, err := newpa.MoveInto(ctx, []types.ManagedObjectReference{fo.Reference()})
 			if err != nil {
-				return fmt.Errorf("could not move folder: %s", err)
+				return errorDiagnostics(fmt.Errorf("could not move folder: %w", err), nil)
 			}
-			tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+			tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 			defer tcancel()
-			if err := task.Wait(tctx); err != nil {
-				return fmt.Errorf("error on waiting for move task completion: %s", err)
+			if _, err := viapi.WaitForTask(tctx, task); err != nil {
+				return errorDiagnostics(fmt.Errorf("error on waiting for move task completion: %w", err), nil)
 			}
 		}
 	}
 
-	return resourceVSphereFolderRead(d, meta)
+	return resourceVSphereFolderRead(ctx, d, meta)
 }
 
-func resourceVSphereFolderDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereFolderDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	fo, err := folder.FromID(client, d.Id())
 	if err != nil {
-		return fmt.Errorf("cannot locate folder: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot locate folder: %w", err), nil)
 	}
 
 	// We don't destroy if the folder has children. This might be flaggable in
@@ -282,28 +285,28 @@ func resourceVSphereFolderDelete(d *schema.ResourceData, meta interface{}) error
 	// better to have hardcoded safe behavior than hardcoded unsafe behavior.
 	ne, err := folder.HasChildren(fo)
 	if err != nil {
-		return fmt.Errorf("error checking for folder contents: %s", err)
+		return errorDiagnostics(fmt.Errorf("error checking for folder contents: %w", err), nil)
 	}
 	if ne {
-		return errors.New("folder is not empty, please remove all items before deleting")
+		return errorDiagnostics(errors.New("folder is not empty, please remove all items before deleting"), nil)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	task, err := fo.Destroy(ctx)
 	if err != nil {
-		return fmt.Errorf("cannot delete folder: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot delete folder: %w", err), nil)
 	}
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	if err := task.Wait(tctx); err != nil {
-		return fmt.Errorf("error on waiting for deletion task completion: %s", err)
+	if _, err := viapi.WaitForTask(tctx, task); err != nil {
+		return errorDiagnostics(fmt.Errorf("error on waiting for deletion task completion: %w", err), nil)
 	}
 
 	return nil
 }
 
-func resourceVSphereFolderImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereFolderImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// Our subject is the full path to a specific targetFolder, for which we just get
 	// the MOID for and then pass off to Read. Easy peasy.
 	p := d.Id()
@@ -4,10 +4,9 @@
 package vsphere
 
 import (
-	"log"
-
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
+	"log"
 )
 
 // resourceVSphereFolderMigrateState is the main state migration function for
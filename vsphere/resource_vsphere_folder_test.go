@@ -12,10 +12,12 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/folder"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/tags"
 )
 
 const testAccResourceVSphereFolderConfigExpectedName = "testacc-folder"
@@ -351,6 +353,34 @@ func TestAccResourceVSphereFolder_modifyTagsMultiStage(t *testing.T) {
 	})
 }
 
+func TestAccResourceVSphereFolder_defaultTags(t *testing.T) {
+	var tagID string
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			tagID = testAccResourceVSphereFolderCreateDefaultTag(t)
+		},
+		// The configuration has its own provider block, which Providers would
+		// duplicate.
+		ProviderFactories: map[string]func() (*schema.Provider, error){
+			"vsphere": func() (*schema.Provider, error) { return testAccProvider, nil },
+		},
+		CheckDestroy: testAccResourceVSphereFolderExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereFolderConfigDefaultTags(),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereFolderExists(true),
+					resource.TestCheckResourceAttr("vsphere_folder.folder", "tags.#", "0"),
+					resource.TestCheckResourceAttr("vsphere_folder.folder", "tags_all.#", "1"),
+					testAccResourceVSphereFolderCheckDefaultTag(&tagID),
+				),
+			},
+		},
+	})
+}
+
 func TestAccResourceVSphereFolder_customAttributes(t *testing.T) {
 	resource.Test(t, resource.TestCase{
 		PreCheck: func() {
@@ -578,6 +608,33 @@ func testAccResourceVSphereFolderCheckTags(tagResName string) resource.TestCheck
 	}
 }
 
+// testAccResourceVSphereFolderCheckDefaultTag is a check to ensure that the
+// default tag of the provider has been attached to the folder.
+func testAccResourceVSphereFolderCheckDefaultTag(tagID *string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		testFolder, err := testGetFolder(s, "folder")
+		if err != nil {
+			return err
+		}
+		tagsClient, err := testAccProvider.Meta().(*Client).TagsManager()
+		if err != nil {
+			return err
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		defer cancel()
+		ids, err := tagsClient.ListAttachedTags(ctx, testFolder)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			if id == *tagID {
+				return nil
+			}
+		}
+		return fmt.Errorf("expected default tag %q to be attached to the folder, got %v", *tagID, ids)
+	}
+}
+
 // testAccResourceVSphereFolderCheckNoTags is a check to ensure that a folder
 // has no tags on it. This is used by the vsphere_tag tests specifically to
 // test to make sure that complete tag removal is explicitly working without
@@ -622,6 +679,37 @@ func testAccResourceVSphereFolderCreateOOB(s *terraform.State) error {
 	return nil
 }
 
+// testAccResourceVSphereFolderCreateDefaultTag creates a tag out of band to
+// use as the default tag of the provider, and passes its ID to the
+// configuration in the default_tag_id variable. The tag is removed by the tag
+// sweeper.
+func testAccResourceVSphereFolderCreateDefaultTag(t *testing.T) string {
+	client, err := sweepVSphereClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tm, err := client.TagsManager()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	defer cancel()
+	categoryID, err := tm.CreateCategory(ctx, &tags.Category{
+		Name:            "testacc-default-tag-category",
+		Cardinality:     "MULTIPLE",
+		AssociableTypes: []string{"Folder"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tagID, err := tm.CreateTag(ctx, &tags.Tag{Name: "testacc-default-tag", CategoryID: categoryID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TF_VAR_default_tag_id", tagID)
+	return tagID
+}
+
 // testAccResourceVSphereFolderDeleteOOB wipes any child items in the test
 // folder resource. This is used to reverse the actions of
 // testAccResourceVSphereFolderCreateOOB so we can properly clean up the test.
@@ -752,6 +840,28 @@ resource "vsphere_folder" "folder" {
 	)
 }
 
+func testAccResourceVSphereFolderConfigDefaultTags() string {
+	return fmt.Sprintf(`
+%s
+
+variable "default_tag_id" {}
+
+provider "vsphere" {
+  default_tags = [var.default_tag_id]
+}
+
+resource "vsphere_folder" "folder" {
+  path          = "%s"
+  type          = "%s"
+  datacenter_id = "${data.vsphere_datacenter.rootdc1.id}"
+}
+`,
+		testhelper.ConfigDataRootDC1(),
+		testAccResourceVSphereFolderConfigExpectedName,
+		folder.VSphereFolderTypeVM,
+	)
+}
+
 func testAccResourceVSphereFolderConfigAllTag() string {
 	return fmt.Sprintf(`
 %s
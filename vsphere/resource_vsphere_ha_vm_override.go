@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
@@ -63,12 +65,12 @@ var vmOverrideClusterVMReactionOnAPDClearedAllowedValues = []string{
 
 func resourceVSphereHAVMOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereHAVMOverrideCreate,
-		Read:   resourceVSphereHAVMOverrideRead,
-		Update: resourceVSphereHAVMOverrideUpdate,
-		Delete: resourceVSphereHAVMOverrideDelete,
+		CreateContext: resourceVSphereHAVMOverrideCreate,
+		ReadContext:   resourceVSphereHAVMOverrideRead,
+		UpdateContext: resourceVSphereHAVMOverrideUpdate,
+		DeleteContext: resourceVSphereHAVMOverrideDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereHAVMOverrideImport,
+			StateContext: resourceVSphereHAVMOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -177,17 +179,17 @@ func resourceVSphereHAVMOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereHAVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereHAVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDasVMConfigInfo(d, meta, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
@@ -200,31 +202,31 @@ func resourceVSphereHAVMOverrideCreate(d *schema.ResourceData, meta interface{})
 		},
 	}
 
-	if err = clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err = clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereHAVMOverrideFlattenID(cluster, vm)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of created resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of created resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereHAVMOverrideIDString(d))
-	return resourceVSphereHAVMOverrideRead(d, meta)
+	return resourceVSphereHAVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereHAVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereHAVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereHAVMOverrideFindEntry(cluster, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -237,36 +239,36 @@ func resourceVSphereHAVMOverrideRead(d *schema.ResourceData, meta interface{}) e
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// cluster/VM combo was used, it will be noted.
 	if err = d.Set("compute_cluster_id", cluster.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"compute_cluster_id\": %s", err))
 	}
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config.uuid")
 	if err != nil {
-		return fmt.Errorf("error getting properties of virtual machine: %s", err)
+		return diag.FromErr(fmt.Errorf("error getting properties of virtual machine: %s", err))
 	}
 	if err = d.Set("virtual_machine_id", props.Config.Uuid); err != nil {
-		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err))
 	}
 
 	if err = flattenClusterDasVMConfigInfo(d, meta, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereHAVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereHAVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereHAVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandClusterDasVMConfigInfo(d, meta, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &types.ClusterConfigSpecEx{
 		DasVmConfigSpec: []types.ClusterDasVmConfigSpec{
@@ -282,20 +284,20 @@ func resourceVSphereHAVMOverrideUpdate(d *schema.ResourceData, meta interface{})
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereHAVMOverrideIDString(d))
-	return resourceVSphereHAVMOverrideRead(d, meta)
+	return resourceVSphereHAVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereHAVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHAVMOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereHAVMOverrideIDString(d))
 
 	cluster, vm, err := resourceVSphereHAVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := &types.ClusterConfigSpecEx{
@@ -309,15 +311,15 @@ func resourceVSphereHAVMOverrideDelete(d *schema.ResourceData, meta interface{})
 		},
 	}
 
-	if err := clustercomputeresource.Reconfigure(cluster, spec); err != nil {
-		return err
+	if err := clustercomputeresource.ReconfigureBatched(ctx, cluster, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereHAVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereHAVMOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereHAVMOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -406,7 +408,7 @@ func resourceVSphereHAVMOverrideIDString(d structure.ResourceIDStringer) string
 // vsphere_ha_vm_override resource.
 func resourceVSphereHAVMOverrideFlattenID(cluster *object.ClusterComputeResource, vm *object.VirtualMachine) (string, error) {
 	clusterID := cluster.Reference().Value
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config.uuid")
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
@@ -432,7 +434,7 @@ func resourceVSphereHAVMOverrideFindEntry(
 	cluster *object.ClusterComputeResource,
 	vm *object.VirtualMachine,
 ) (*types.ClusterDasVmConfigInfo, error) {
-	props, err := clustercomputeresource.Properties(cluster)
+	props, err := clustercomputeresource.Properties(cluster, "configurationEx")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching cluster properties: %s", err)
 	}
@@ -7,14 +7,17 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/provider"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/clustercomputeresource"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi/license"
 	"github.com/vmware/govmomi/object"
@@ -28,12 +31,18 @@ import (
 
 func resourceVsphereHost() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVsphereHostCreate,
-		Read:   resourceVsphereHostRead,
-		Update: resourceVsphereHostUpdate,
-		Delete: resourceVsphereHostDelete,
+		CreateContext: resourceVsphereHostCreate,
+		ReadContext:   resourceVsphereHostRead,
+		UpdateContext: resourceVsphereHostUpdate,
+		DeleteContext: resourceVsphereHostDelete,
+		CustomizeDiff: tagsAllCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: schema.ImportStatePassthrough,
+			StateContext: schema.ImportStatePassthroughContext,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
 		},
 		Schema: map[string]*schema.Schema{
 			"datacenter": {
@@ -64,10 +73,12 @@ func resourceVsphereHost() *schema.Resource {
 				Description: "Username of the administration account of the host.",
 			},
 			"password": {
-				Type:        schema.TypeString,
-				Required:    true,
-				Description: "Password of the administration account of the host.",
-				Sensitive:   true,
+				Type:             schema.TypeString,
+				Required:         true,
+				Description:      "Password of the administration account of the host, or file:// followed by the path of a local file that contains it. State only holds a fingerprint of the password.",
+				Sensitive:        true,
+				StateFunc:        secret.StateFunc,
+				DiffSuppressFunc: secret.DiffSuppressFunc,
 			},
 			"thumbprint": {
 				Type:        schema.TypeString,
@@ -106,7 +117,8 @@ func resourceVsphereHost() *schema.Resource {
 			},
 
 			// Tagging
-			vSphereTagAttributeKey: tagsSchema(),
+			vSphereTagAttributeKey:     tagsSchema(),
+			vSphereTagsAllAttributeKey: tagsAllSchema(),
 
 			// Custom Attributes
 			customattribute.ConfigKey: customattribute.ConfigSchema(),
@@ -114,26 +126,29 @@ func resourceVsphereHost() *schema.Resource {
 	}
 }
 
-func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := validateFields(d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	client := meta.(*Client).vimClient
 
-	hcs := buildHostConnectSpec(d)
+	hcs, err := buildHostConnectSpec(d)
+	if err != nil {
+		return errorDiagnostics(err, nil)
+	}
 
 	licenseKey := d.Get("license").(string)
 
 	if licenseKey != "" {
-		licFound, err := licenseExists(client.Client, licenseKey)
+		licFound, err := licenseExists(ctx, client.Client, licenseKey)
 		if err != nil {
-			return fmt.Errorf("error while looking for license key. Error: %s", err)
+			return errorDiagnostics(fmt.Errorf("error while looking for license key. Error: %w", err), nil)
 		}
 
 		if !licFound {
-			return fmt.Errorf("license key supplied (%s) did not match against known license keys", licenseKey)
+			return errorDiagnostics(fmt.Errorf("license key supplied (%s) did not match against known license keys", licenseKey), nil)
 		}
 	}
 
@@ -150,38 +165,38 @@ func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
 	if clusterID != "" {
 		ccr, err := clustercomputeresource.FromID(client, clusterID)
 		if err != nil {
-			return fmt.Errorf("error while searching cluster %s. Error: %s", clusterID, err)
+			return errorDiagnostics(fmt.Errorf("error while searching cluster %s. Error: %w", clusterID, err), nil)
 		}
 
-		task, err = ccr.AddHost(context.TODO(), hcs, connectedState, &licenseKey, nil)
+		task, err = ccr.AddHost(ctx, hcs, connectedState, &licenseKey, nil)
 		if err != nil {
-			return fmt.Errorf("error while adding host with hostname %s to cluster %s.  Error: %s", d.Get("hostname").(string), clusterID, err)
+			return errorDiagnostics(fmt.Errorf("error while adding host with hostname %s to cluster %s.  Error: %s", d.Get("hostname").(string), clusterID, err), nil)
 		}
 	} else {
 		dcID := d.Get("datacenter").(string)
 		dc, err := datacenterFromID(client, dcID)
 		if err != nil {
-			return fmt.Errorf("error while retrieving datacenter object for datacenter: %s. Error: %s", dcID, err)
+			return errorDiagnostics(fmt.Errorf("error while retrieving datacenter object for datacenter: %s. Error: %w", dcID, err), nil)
 		}
 
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 		defer cancel()
 		var dcProps mo.Datacenter
 		if err := dc.Properties(ctx, dc.Reference(), nil, &dcProps); err != nil {
-			return fmt.Errorf("error while retrieving properties for datacenter %s. Error: %s", dcID, err)
+			return errorDiagnostics(fmt.Errorf("error while retrieving properties for datacenter %s. Error: %w", dcID, err), nil)
 		}
 
 		hostFolder := object.NewFolder(client.Client, dcProps.HostFolder)
-		task, err = hostFolder.AddStandaloneHost(context.TODO(), hcs, connectedState, &licenseKey, nil)
+		task, err = hostFolder.AddStandaloneHost(ctx, hcs, connectedState, &licenseKey, nil)
 		if err != nil {
-			return fmt.Errorf("error while adding standalone host %s. Error: %s", hcs.HostName, err)
+			return errorDiagnostics(fmt.Errorf("error while adding standalone host %s. Error: %w", hcs.HostName, err), nil)
 		}
 	}
 
 	p := property.DefaultCollector(client.Client)
-	res, err := gtask.Wait(context.TODO(), task.Reference(), p, nil)
+	res, err := gtask.Wait(ctx, task.Reference(), p, nil)
 	if err != nil {
-		return fmt.Errorf("host addition failed. %s", err)
+		return errorDiagnostics(fmt.Errorf("host addition failed. %w", err), nil)
 	}
 	taskResult := res.Result
 
@@ -190,87 +205,87 @@ func resourceVsphereHostCreate(d *schema.ResourceData, meta interface{}) error {
 	switch taskResultType {
 	case "ComputeResource":
 		computeResource := object.NewComputeResource(client.Client, taskResult.(types.ManagedObjectReference))
-		crHosts, err := computeResource.Hosts(context.TODO())
+		crHosts, err := computeResource.Hosts(ctx)
 		if err != nil {
-			return fmt.Errorf("failed to retrieve created computeResource Hosts. Error: %s", err)
+			return errorDiagnostics(fmt.Errorf("failed to retrieve created computeResource Hosts. Error: %w", err), nil)
 		}
 		hostID = crHosts[0].Reference().Value
 		log.Printf("[DEBUG] standalone hostID: %s", hostID)
 	case "HostSystem":
 		hostID = taskResult.(types.ManagedObjectReference).Value
 	default:
-		return fmt.Errorf("unexpected task result type encountered. Got %s while waiting ComputeResourceType or Hostsystem", taskResultType)
+		return errorDiagnostics(fmt.Errorf("unexpected task result type encountered. Got %s while waiting ComputeResourceType or Hostsystem", taskResultType), nil)
 	}
 	log.Printf("[DEBUG] Host added with ID %s", hostID)
 	d.SetId(hostID)
 
 	host, err := hostsystem.FromID(client, hostID)
 	if err != nil {
-		return fmt.Errorf("failed while retrieving host object for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("failed while retrieving host object for host %s. Error: %w", hostID, err), nil)
 	}
 
 	// Load the tags client to validate the vCenter Sever connection before
 	// attempting to proceed if tags have been defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Verify the vCenter Server connection before
 	// attempting to proceed if custom attributes have been defined.
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Apply tags
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, host); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, host, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Apply custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(host); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	lockdownModeString := d.Get("lockdown").(string)
 	lockdownMode, err := hostLockdownType(lockdownModeString)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	if connectedState {
-		hostProps, err := hostsystem.Properties(host)
+		hostProps, err := hostsystem.Properties(host, "configManager.hostAccessManager")
 		if err != nil {
-			return fmt.Errorf("error while retrieving properties for host %s. Error: %s", hostID, err)
+			return errorDiagnostics(fmt.Errorf("error while retrieving properties for host %s. Error: %w", hostID, err), nil)
 		}
 
 		hamRef := hostProps.ConfigManager.HostAccessManager.Reference()
 		ham := NewHostAccessManager(client.Client, hamRef)
-		err = ham.ChangeLockdownMode(context.TODO(), lockdownMode)
+		err = ham.ChangeLockdownMode(ctx, lockdownMode)
 		if err != nil {
-			return fmt.Errorf("error while changing lockdown mode for host %s. Error: %s", hostID, err)
+			return errorDiagnostics(fmt.Errorf("error while changing lockdown mode for host %s. Error: %w", hostID, err), nil)
 		}
 	}
 
 	maintenanceMode := d.Get("maintenance").(bool)
 	if maintenanceMode {
-		err = hostsystem.EnterMaintenanceMode(host, provider.DefaultAPITimeout, true)
+		err = hostsystem.EnterMaintenanceMode(ctx, host, provider.DefaultAPITimeout, true)
 	} else {
-		err = hostsystem.ExitMaintenanceMode(host, provider.DefaultAPITimeout)
+		err = hostsystem.ExitMaintenanceMode(ctx, host, provider.DefaultAPITimeout)
 	}
 	if err != nil {
-		return fmt.Errorf("error while toggling maintenance mode for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while toggling maintenance mode for host %s. Error: %w", hostID, err), nil)
 	}
 
-	return resourceVsphereHostRead(d, meta)
+	return resourceVsphereHostRead(ctx, d, meta)
 }
 
-func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	// NOTE: Destroying the host without telling vsphere about it will result in us not
 	// knowing that the host does not exist any more.
 
@@ -285,20 +300,24 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("error while searching host %s. Error: %s ", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while searching host %s. Error: %s ", hostID, err), nil)
 	}
 
 	maintenanceState, err := hostsystem.HostInMaintenance(hs)
 	if err != nil {
-		return fmt.Errorf("error while checking maintenance status for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while checking maintenance status for host %s. Error: %w", hostID, err), nil)
 	}
 	_ = d.Set("maintenance", maintenanceState)
 
+	// The password cannot be read back. This replaces one saved in state
+	// before passwords were fingerprinted, or fingerprinted with another key.
+	_ = d.Set("password", secret.Refingerprint(d, "password", d.Get("password").(string)))
+
 	// Retrieve host's properties.
 	log.Printf("[DEBUG] Got host %s", hs.String())
-	host, err := hostsystem.Properties(hs)
+	host, err := hostsystem.Properties(hs, "parent", "config.lockdownMode")
 	if err != nil {
-		return fmt.Errorf("error while retrieving properties for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while retrieving properties for host %s. Error: %w", hostID, err), nil)
 	}
 
 	if host.Parent != nil && host.Parent.Type == "ClusterComputeResource" && !d.Get("cluster_managed").(bool) {
@@ -309,7 +328,7 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	connectionState, err := hostsystem.GetConnectionState(hs)
 	if err != nil {
-		return fmt.Errorf("error while getting connection state for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while getting connection state for host %s. Error: %w", hostID, err), nil)
 	}
 
 	if connectionState == types.HostSystemConnectionStateDisconnected {
@@ -320,9 +339,13 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 	}
 	_ = d.Set("connected", true)
 
-	lockdownMode, err := hostLockdownString(host.Config.LockdownMode)
+	var hostLockdownMode types.HostLockdownMode
+	if host.Config != nil {
+		hostLockdownMode = host.Config.LockdownMode
+	}
+	lockdownMode, err := hostLockdownString(hostLockdownMode)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("Setting lockdown to %s", lockdownMode)
@@ -330,9 +353,9 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	licenseKey := d.Get("license").(string)
 	if licenseKey != "" {
-		licFound, err := isLicenseAssigned(client.Client, hostID, licenseKey)
+		licFound, err := isLicenseAssigned(ctx, client.Client, hostID, licenseKey)
 		if err != nil {
-			return fmt.Errorf("error while checking license assignment for host %s. Error: %s", hostID, err)
+			return errorDiagnostics(fmt.Errorf("error while checking license assignment for host %s. Error: %w", hostID, err), nil)
 		}
 
 		if !licFound {
@@ -342,16 +365,16 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 
 	// Read tags
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, host, d); err != nil {
-			return fmt.Errorf("error reading tags: %s", err)
+		if err := readTagsForResource(ctx, tagsClient, host, d, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error reading tags: %w", err), nil)
 		}
 	}
 
 	// Read custom attributes
 	if customattribute.IsSupported(client) {
-		moHost, err := hostsystem.Properties(hs)
+		moHost, err := hostsystem.Properties(hs, "customValue")
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		customattribute.ReadFromResource(moHost.Entity(), d)
 	}
@@ -359,22 +382,22 @@ func resourceVsphereHostRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	err := validateFields(d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	client := meta.(*Client).vimClient
 
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// First let's establish where we are and where we want to go
@@ -389,12 +412,12 @@ func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
 	hostID := d.Id()
 	hostObject, err := hostsystem.FromID(client, hostID)
 	if err != nil {
-		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %w", hostID, err), nil)
 	}
 
 	actualConnectionState, err := hostsystem.GetConnectionState(hostObject)
 	if err != nil {
-		return fmt.Errorf("error while retrieving connection state for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while retrieving connection state for host %s. Error: %w", hostID, err), nil)
 	}
 
 	// Have there been any changes that warrant a reconnect?
@@ -410,25 +433,25 @@ func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
 	// Decide if we're going to reconnect or not
 	reconnectNeeded, err := shouldReconnect(d, meta, actualConnectionState, desiredConnectionState, reconnect)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	switch reconnectNeeded {
 	case 1:
-		err := resourceVSphereHostReconnect(d, meta)
+		err := resourceVSphereHostReconnect(ctx, d, meta)
 		if err != nil {
-			return fmt.Errorf("error while reconnecting host %s. Error: %s", hostID, err)
+			return errorDiagnostics(fmt.Errorf("error while reconnecting host %s. Error: %w", hostID, err), nil)
 		}
 	case -1:
-		err := resourceVSphereHostDisconnect(d, meta)
+		err := resourceVSphereHostDisconnect(ctx, d, meta)
 		if err != nil {
-			return fmt.Errorf("error while disconnecting host %s. Error: %s", hostID, err)
+			return errorDiagnostics(fmt.Errorf("error while disconnecting host %s. Error: %w", hostID, err), nil)
 		}
 	case 0:
 		break
 	}
 
-	mutableKeys := map[string]func(*schema.ResourceData, interface{}, interface{}, interface{}) error{
+	mutableKeys := map[string]func(context.Context, *schema.ResourceData, interface{}, interface{}, interface{}) error{
 		"license":     resourceVSphereHostUpdateLicense,
 		"cluster":     resourceVSphereHostUpdateCluster,
 		"maintenance": resourceVSphereHostUpdateMaintenanceMode,
@@ -442,54 +465,54 @@ func resourceVsphereHostUpdate(d *schema.ResourceData, meta interface{}) error {
 		}
 		log.Printf("[DEBUG] Key %s has change, processing", k)
 		old, newVal := d.GetChange(k)
-		err := v(d, meta, old, newVal)
+		err := v(ctx, d, meta, old, newVal)
 		if err != nil {
-			return fmt.Errorf("error while updating %s: %s", k, err)
+			return errorDiagnostics(fmt.Errorf("error while updating %s: %w", k, err), nil)
 		}
 	}
 
 	// Apply tags
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, hostObject); err != nil {
-			return fmt.Errorf("error updating tags: %s", err)
+		if err := processTagDiff(tagsClient, d, hostObject, meta); err != nil {
+			return errorDiagnostics(fmt.Errorf("error updating tags: %w", err), nil)
 		}
 	}
 
 	// Apply custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(hostObject); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
-	return resourceVsphereHostRead(d, meta)
+	return resourceVsphereHostRead(ctx, d, meta)
 }
 
-func resourceVsphereHostDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereHostDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 
 	hs, err := hostsystem.FromID(client, hostID)
 	if err != nil {
-		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %w", hostID, err), nil)
 	}
 
 	connectionState, err := hostsystem.GetConnectionState(hs)
 	if err != nil {
-		return fmt.Errorf("error while retrieving connection state for host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while retrieving connection state for host %s. Error: %w", hostID, err), nil)
 	}
 
 	if connectionState != types.HostSystemConnectionStateDisconnected {
 		// We cannot put a disconnected server in maintenance mode.
-		err = resourceVSphereHostDisconnect(d, meta)
+		err = resourceVSphereHostDisconnect(ctx, d, meta)
 		if err != nil {
-			return fmt.Errorf("error while disconnecting host: %s", err.Error())
+			return errorDiagnostics(fmt.Errorf("error while disconnecting host: %s", err.Error()), nil)
 		}
 	}
 
 	hostProps, err := hostsystem.Properties(hs)
 	if err != nil {
-		return fmt.Errorf("error while retrieving properties fort host %s. Error: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while retrieving properties fort host %s. Error: %w", hostID, err), nil)
 	}
 
 	// If this is a standalone host we need to destroy the ComputeResource object
@@ -497,25 +520,25 @@ func resourceVsphereHostDelete(d *schema.ResourceData, meta interface{}) error {
 	var task *object.Task
 	if hostProps.Parent.Type == "ComputeResource" {
 		cr := object.NewComputeResource(client.Client, *hostProps.Parent)
-		task, err = cr.Destroy(context.TODO())
+		task, err = cr.Destroy(ctx)
 		if err != nil {
-			return fmt.Errorf("error while submitting destroy task for compute resource %s. Error: %s", hostProps.Parent.Value, err)
+			return errorDiagnostics(fmt.Errorf("error while submitting destroy task for compute resource %s. Error: %w", hostProps.Parent.Value, err), nil)
 		}
 	} else {
-		task, err = hs.Destroy(context.TODO())
+		task, err = hs.Destroy(ctx)
 		if err != nil {
-			return fmt.Errorf("error while submitting destroy task for host system %s. Error: %s", hostProps.Parent.Value, err)
+			return errorDiagnostics(fmt.Errorf("error while submitting destroy task for host system %s. Error: %w", hostProps.Parent.Value, err), nil)
 		}
 	}
 	p := property.DefaultCollector(client.Client)
-	_, err = gtask.Wait(context.TODO(), task.Reference(), p, nil)
+	_, err = gtask.Wait(ctx, task.Reference(), p, nil)
 	if err != nil {
-		return fmt.Errorf("error while waiting for host (%s) to be removed: %s", hostID, err)
+		return errorDiagnostics(fmt.Errorf("error while waiting for host (%s) to be removed: %w", hostID, err), nil)
 	}
 	return nil
 }
 
-func resourceVSphereHostUpdateLockdownMode(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateLockdownMode(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 	host, err := hostsystem.FromID(client, hostID)
@@ -529,14 +552,14 @@ func resourceVSphereHostUpdateLockdownMode(d *schema.ResourceData, meta, _, newV
 	}
 
 	var hostProps mo.HostSystem
-	err = host.Properties(context.TODO(), host.ConfigManager().Reference(), []string{"configManager.hostAccessManager"}, &hostProps)
+	err = host.Properties(ctx, host.ConfigManager().Reference(), []string{"configManager.hostAccessManager"}, &hostProps)
 	if err != nil {
 		return fmt.Errorf("error while retrieving HostSystem properties for host ID %s. Error: %s", hostID, err)
 	}
 
 	hamRef := hostProps.ConfigManager.HostAccessManager.Reference()
 	ham := NewHostAccessManager(client.Client, hamRef)
-	err = ham.ChangeLockdownMode(context.TODO(), lockdownMode)
+	err = ham.ChangeLockdownMode(ctx, lockdownMode)
 	if err != nil {
 		return fmt.Errorf("error while changing lonckdown mode for host ID %s to %s. Error: %s", hostID, lockdownMode, err)
 	}
@@ -544,7 +567,7 @@ func resourceVSphereHostUpdateLockdownMode(d *schema.ResourceData, meta, _, newV
 	return nil
 }
 
-func resourceVSphereHostUpdateMaintenanceMode(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateMaintenanceMode(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 
@@ -555,9 +578,9 @@ func resourceVSphereHostUpdateMaintenanceMode(d *schema.ResourceData, meta, _, n
 
 	maintenanceMode := newVal.(bool)
 	if maintenanceMode {
-		err = hostsystem.EnterMaintenanceMode(host, provider.DefaultAPITimeout, true)
+		err = hostsystem.EnterMaintenanceMode(ctx, host, provider.DefaultAPITimeout, true)
 	} else {
-		err = hostsystem.ExitMaintenanceMode(host, provider.DefaultAPITimeout)
+		err = hostsystem.ExitMaintenanceMode(ctx, host, provider.DefaultAPITimeout)
 	}
 	if err != nil {
 		return fmt.Errorf("error while toggling maintenance mode for host %s. Error: %s", host.Name(), err)
@@ -565,21 +588,21 @@ func resourceVSphereHostUpdateMaintenanceMode(d *schema.ResourceData, meta, _, n
 	return nil
 }
 
-func resourceVSphereHostUpdateLicense(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateLicense(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	lm := license.NewManager(client.Client)
-	lam, err := lm.AssignmentManager(context.TODO())
+	lam, err := lm.AssignmentManager(ctx)
 	if err != nil {
 		return fmt.Errorf("error while accessing License Assignment Manager endpoint. Error: %s", err)
 	}
-	_, err = lam.Update(context.TODO(), d.Id(), newVal.(string), "")
+	_, err = lam.Update(ctx, d.Id(), newVal.(string), "")
 	if err != nil {
 		return fmt.Errorf("error while updating license. error: %s", err)
 	}
 	return nil
 }
 
-func resourceVSphereHostUpdateCluster(d *schema.ResourceData, meta, _, newVal interface{}) error {
+func resourceVSphereHostUpdateCluster(ctx context.Context, d *schema.ResourceData, meta, _, newVal interface{}) error {
 	client := meta.(*Client).vimClient
 	hostID := d.Id()
 	newClusterID := newVal.(string)
@@ -594,22 +617,22 @@ func resourceVSphereHostUpdateCluster(d *schema.ResourceData, meta, _, newVal in
 		return fmt.Errorf("error while retrieving HostSystem object for host ID %s. Error: %s", hostID, err)
 	}
 
-	err = hostsystem.EnterMaintenanceMode(hs, provider.DefaultAPITimeout, true)
+	err = hostsystem.EnterMaintenanceMode(ctx, hs, provider.DefaultAPITimeout, true)
 	if err != nil {
 		return fmt.Errorf("error while putting host to maintenance mode: %s", err.Error())
 	}
 
-	task, err := newCluster.MoveInto(context.TODO(), hs)
+	task, err := newCluster.MoveInto(ctx, hs)
 	if err != nil {
 		return fmt.Errorf("error while moving HostSystem with ID %s to new cluster. Error: %s", hostID, err)
 	}
 	p := property.DefaultCollector(client.Client)
-	_, err = gtask.Wait(context.TODO(), task.Reference(), p, nil)
+	_, err = gtask.Wait(ctx, task.Reference(), p, nil)
 	if err != nil {
 		return fmt.Errorf("error while moving host to new cluster (%s): %s", newClusterID, err)
 	}
 
-	err = hostsystem.ExitMaintenanceMode(hs, provider.DefaultAPITimeout)
+	err = hostsystem.ExitMaintenanceMode(ctx, hs, provider.DefaultAPITimeout)
 	if err != nil {
 		return fmt.Errorf("error while taking host out of maintenance mode: %s", err.Error())
 	}
@@ -617,23 +640,26 @@ func resourceVSphereHostUpdateCluster(d *schema.ResourceData, meta, _, newVal in
 	return nil
 }
 
-func resourceVSphereHostUpdateThumbprint(d *schema.ResourceData, meta, _, _ interface{}) error {
-	return resourceVSphereHostReconnect(d, meta)
+func resourceVSphereHostUpdateThumbprint(ctx context.Context, d *schema.ResourceData, meta, _, _ interface{}) error {
+	return resourceVSphereHostReconnect(ctx, d, meta)
 }
 
-func resourceVSphereHostReconnect(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostReconnect(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	hostID := d.Id()
 	client := meta.(*Client).vimClient
 	host := object.NewHostSystem(client.Client, types.ManagedObjectReference{Type: "HostSystem", Value: d.Id()})
-	hcs := buildHostConnectSpec(d)
+	hcs, err := buildHostConnectSpec(d)
+	if err != nil {
+		return err
+	}
 
-	task, err := host.Reconnect(context.TODO(), &hcs, nil)
+	task, err := host.Reconnect(ctx, &hcs, nil)
 	if err != nil {
 		return fmt.Errorf("error while reconnecting host with ID %s. Error: %s", hostID, err)
 	}
 
 	p := property.DefaultCollector(client.Client)
-	_, err = gtask.Wait(context.TODO(), task.Reference(), p, nil)
+	_, err = gtask.Wait(ctx, task.Reference(), p, nil)
 	if err != nil {
 		return fmt.Errorf("error while reconnecting host(%s): %s", hostID, err)
 	}
@@ -645,7 +671,7 @@ func resourceVSphereHostReconnect(d *schema.ResourceData, meta interface{}) erro
 
 	maintenanceConfig := d.Get("maintenance").(bool)
 	if maintenanceState && !maintenanceConfig {
-		err := hostsystem.ExitMaintenanceMode(host, provider.DefaultAPITimeout)
+		err := hostsystem.ExitMaintenanceMode(ctx, host, provider.DefaultAPITimeout)
 		if err != nil {
 			return fmt.Errorf("error while taking host %s out of maintenance mode. Error: %s", host.Name(), err)
 		}
@@ -653,17 +679,17 @@ func resourceVSphereHostReconnect(d *schema.ResourceData, meta interface{}) erro
 	return nil
 }
 
-func resourceVSphereHostDisconnect(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostDisconnect(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	hostID := d.Id()
 	client := meta.(*Client).vimClient
 	host := object.NewHostSystem(client.Client, types.ManagedObjectReference{Type: "HostSystem", Value: d.Id()})
-	task, err := host.Disconnect(context.TODO())
+	task, err := host.Disconnect(ctx)
 	if err != nil {
 		return fmt.Errorf("error while disconnecting host %s. Error: %s", host.Name(), err)
 	}
 
 	p := property.DefaultCollector(client.Client)
-	_, err = gtask.Wait(context.TODO(), task.Reference(), p, nil)
+	_, err = gtask.Wait(ctx, task.Reference(), p, nil)
 	if err != nil {
 		return fmt.Errorf("error while disconnecting host(%s): %s", hostID, err)
 	}
@@ -737,19 +763,24 @@ func hostLockdownString(lockdownMode types.HostLockdownMode) (string, error) {
 	return "", fmt.Errorf("unknown Lockdown mode encountered")
 }
 
-func buildHostConnectSpec(d *schema.ResourceData) types.HostConnectSpec {
+func buildHostConnectSpec(d *schema.ResourceData) (types.HostConnectSpec, error) {
+	// The password is read from the configuration, as state only holds its
+	// fingerprint.
+	password, err := secret.Get(d, "password")
+	if err != nil {
+		return types.HostConnectSpec{}, err
+	}
 	hcs := types.HostConnectSpec{
 		HostName:      d.Get("hostname").(string),
 		UserName:      d.Get("username").(string),
-		Password:      d.Get("password").(string),
+		Password:      password,
 		SslThumbprint: d.Get("thumbprint").(string),
 		Force:         d.Get("force").(bool),
 	}
-	return hcs
+	return hcs, nil
 }
 
-func isLicenseAssigned(client *vim25.Client, hostID, licenseKey string) (bool, error) {
-	ctx := context.TODO()
+func isLicenseAssigned(ctx context.Context, client *vim25.Client, hostID, licenseKey string) (bool, error) {
 	lm := license.NewManager(client)
 	am, err := lm.AssignmentManager(ctx)
 	if err != nil {
@@ -771,8 +802,7 @@ func isLicenseAssigned(client *vim25.Client, hostID, licenseKey string) (bool, e
 	return licFound, nil
 }
 
-func licenseExists(client *vim25.Client, licenseKey string) (bool, error) {
-	ctx := context.TODO()
+func licenseExists(ctx context.Context, client *vim25.Client, licenseKey string) (bool, error) {
 	lm := license.NewManager(client)
 	ll, err := lm.List(ctx)
 	if err != nil {
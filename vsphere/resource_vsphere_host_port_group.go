@@ -8,8 +8,10 @@ import (
 
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func resourceVSphereHostPortGroup() *schema.Resource {
@@ -46,114 +48,118 @@ func resourceVSphereHostPortGroup() *schema.Resource {
 	s["standby_nics"].Optional = true
 
 	return &schema.Resource{
-		Create: resourceVSphereHostPortGroupCreate,
-		Read:   resourceVSphereHostPortGroupRead,
-		Update: resourceVSphereHostPortGroupUpdate,
-		Delete: resourceVSphereHostPortGroupDelete,
+		CreateContext: resourceVSphereHostPortGroupCreate,
+		ReadContext:   resourceVSphereHostPortGroupRead,
+		UpdateContext: resourceVSphereHostPortGroupUpdate,
+		DeleteContext: resourceVSphereHostPortGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereHostPortGroupImport,
+			StateContext: resourceVSphereHostPortGroupImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereHostPortGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
 	hsID := d.Get("host_system_id").(string)
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading network system: %s", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandHostPortGroupSpec(d)
-	if err := ns.AddPortGroup(ctx, *spec); err != nil {
-		return fmt.Errorf("error adding port group: %s", err)
+	if _, err := updateHostNetworkConfig(ctx, ns, hostPortGroupChange(types.HostConfigChangeOperationAdd, spec)); err != nil {
+		return diag.FromErr(fmt.Errorf("error adding port group: %s", err))
 	}
 
 	saveHostPortGroupID(d, hsID, name)
-	return resourceVSphereHostPortGroupRead(d, meta)
+	return resourceVSphereHostPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereHostPortGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
 	pg, err := hostPortGroupFromName(meta.(*Client).vimClient, ns, name)
 	if err != nil {
-		return fmt.Errorf("error fetching port group data: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching port group data: %s", err))
 	}
 
 	if err := flattenHostPortGroupSpec(d, &pg.Spec); err != nil {
-		return fmt.Errorf("error setting resource data: %s", err)
+		return diag.FromErr(fmt.Errorf("error setting resource data: %s", err))
 	}
 
 	_ = d.Set("key", pg.Key)
 	cpm, err := calculateComputedPolicy(pg.ComputedPolicy)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err := d.Set("computed_policy", cpm); err != nil {
-		return fmt.Errorf("error saving effective policy to state: %s", err)
+		return diag.FromErr(fmt.Errorf("error saving effective policy to state: %s", err))
 	}
 	if err := d.Set("ports", calculatePorts(pg.Port)); err != nil {
-		return fmt.Errorf("error setting port list: %s", err)
+		return diag.FromErr(fmt.Errorf("error setting port list: %s", err))
 	}
 
 	return nil
 }
 
-func resourceVSphereHostPortGroupUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
-	hsID, name, err := portGroupIDsFromResourceID(d)
+	hsID, _, err := portGroupIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandHostPortGroupSpec(d)
-	if err := ns.UpdatePortGroup(ctx, name, *spec); err != nil {
-		return fmt.Errorf("error updating port group: %s", err)
+	if _, err := updateHostNetworkConfig(ctx, ns, hostPortGroupChange(types.HostConfigChangeOperationEdit, spec)); err != nil {
+		return diag.FromErr(fmt.Errorf("error updating port group: %s", err))
 	}
 
-	return resourceVSphereHostPortGroupRead(d, meta)
+	return resourceVSphereHostPortGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereHostPortGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostPortGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
-	if err := ns.RemovePortGroup(ctx, name); err != nil {
-		return fmt.Errorf("error deleting port group: %s", err)
+	spec := &types.HostPortGroupSpec{
+		Name:        name,
+		VswitchName: d.Get("virtual_switch_name").(string),
+	}
+	if _, err := updateHostNetworkConfig(ctx, ns, hostPortGroupChange(types.HostConfigChangeOperationRemove, spec)); err != nil {
+		return diag.FromErr(fmt.Errorf("error deleting port group: %s", err))
 	}
 
 	return nil
 }
 
-func resourceVSphereHostPortGroupImport(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereHostPortGroupImport(ctx context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	hsID, name, err := portGroupIDsFromResourceID(d)
 	if err != nil {
 		return []*schema.ResourceData{}, err
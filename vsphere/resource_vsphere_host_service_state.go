@@ -9,6 +9,7 @@ import (
 
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostservicestate"
@@ -18,10 +19,10 @@ import (
 
 func resourceVsphereHostServiceState() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereHostServiceStateCreate,
-		Read:   resourceVSphereHostServiceStateRead,
-		Update: resourceVSphereHostServiceStateUpdate,
-		Delete: resourceVSphereHostServiceStateDelete,
+		CreateContext: resourceVSphereHostServiceStateCreate,
+		ReadContext:   resourceVSphereHostServiceStateRead,
+		UpdateContext: resourceVSphereHostServiceStateUpdate,
+		DeleteContext: resourceVSphereHostServiceStateDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceVSphereHostServiceStateImport,
 		},
@@ -67,7 +68,7 @@ func resourceVsphereHostServiceState() *schema.Resource {
 	}
 }
 
-func resourceVSphereHostServiceStateRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostServiceStateRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] entering resource_vsphere_host_service_state read function")
 
 	client := meta.(*Client).vimClient
@@ -84,12 +85,12 @@ func resourceVSphereHostServiceStateRead(d *schema.ResourceData, meta interface{
 			provider.DefaultAPITimeout,
 		)
 		if err != nil {
-			return fmt.Errorf(
+			return diag.FromErr(fmt.Errorf(
 				"error trying to retrieve service state for host '%s' with key %s: %s",
 				hostID,
 				srv["key"].(string),
 				err,
-			)
+			))
 		}
 
 		updatedList = append(updatedList, ss)
@@ -101,7 +102,7 @@ func resourceVSphereHostServiceStateRead(d *schema.ResourceData, meta interface{
 	return nil
 }
 
-func resourceVSphereHostServiceStateCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostServiceStateCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] entering resource_vsphere_host_service_state create function")
 
 	client := meta.(*Client).vimClient
@@ -120,11 +121,11 @@ func resourceVSphereHostServiceStateCreate(d *schema.ResourceData, meta interfac
 			provider.DefaultAPITimeout,
 			true,
 		); err != nil {
-			return fmt.Errorf(
+			return diag.FromErr(fmt.Errorf(
 				"error trying to create service '%s' for host '%s': %s", srv["key"],
 				hostID,
 				err,
-			)
+			))
 		}
 	}
 
@@ -133,7 +134,7 @@ func resourceVSphereHostServiceStateCreate(d *schema.ResourceData, meta interfac
 	return nil
 }
 
-func resourceVSphereHostServiceStateUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostServiceStateUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] entering resource_vsphere_host_service_state update function")
 
 	client := meta.(*Client).vimClient
@@ -170,12 +171,12 @@ func resourceVSphereHostServiceStateUpdate(d *schema.ResourceData, meta interfac
 				provider.DefaultAPITimeout,
 				false,
 			); err != nil {
-				return fmt.Errorf(
+				return diag.FromErr(fmt.Errorf(
 					"error trying to update old service '%s' for host '%s': %s",
 					oldSrv["key"].(string),
 					hostID,
 					err,
-				)
+				))
 			}
 		}
 	}
@@ -190,19 +191,19 @@ func resourceVSphereHostServiceStateUpdate(d *schema.ResourceData, meta interfac
 			provider.DefaultAPITimeout,
 			true,
 		); err != nil {
-			return fmt.Errorf(
+			return diag.FromErr(fmt.Errorf(
 				"error trying to update new service '%s' for host '%s': %s",
 				newSrv["key"].(string),
 				hostID,
 				err,
-			)
+			))
 		}
 	}
 
 	return nil
 }
 
-func resourceVSphereHostServiceStateDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostServiceStateDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] entering resource_vsphere_host_service_state delete function")
 
 	client := meta.(*Client).vimClient
@@ -221,12 +222,12 @@ func resourceVSphereHostServiceStateDelete(d *schema.ResourceData, meta interfac
 			false,
 		)
 		if err != nil {
-			return fmt.Errorf(
+			return diag.FromErr(fmt.Errorf(
 				"error trying to delete service '%s' for host '%s': %s",
 				srv["key"].(string),
 				hostID,
 				err,
-			)
+			))
 		}
 	}
 
@@ -8,8 +8,10 @@ import (
 
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/govmomi/vim25/types"
 )
 
 func resourceVSphereHostVirtualSwitch() *schema.Resource {
@@ -46,104 +48,104 @@ func resourceVSphereHostVirtualSwitch() *schema.Resource {
 	s["shaping_enabled"].Default = false
 
 	return &schema.Resource{
-		Create:        resourceVSphereHostVirtualSwitchCreate,
-		Read:          resourceVSphereHostVirtualSwitchRead,
-		Update:        resourceVSphereHostVirtualSwitchUpdate,
-		Delete:        resourceVSphereHostVirtualSwitchDelete,
+		CreateContext: resourceVSphereHostVirtualSwitchCreate,
+		ReadContext:   resourceVSphereHostVirtualSwitchRead,
+		UpdateContext: resourceVSphereHostVirtualSwitchUpdate,
+		DeleteContext: resourceVSphereHostVirtualSwitchDelete,
 		CustomizeDiff: resourceVSphereHostVirtualSwitchCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereHostVirtualSwitchImport,
+			StateContext: resourceVSphereHostVirtualSwitchImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereHostVirtualSwitchCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostVirtualSwitchCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	name := d.Get("name").(string)
 	hsID := d.Get("host_system_id").(string)
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandHostVirtualSwitchSpec(d)
-	if err := ns.AddVirtualSwitch(ctx, name, spec); err != nil {
-		return fmt.Errorf("error adding host vSwitch: %s", err)
+	if _, err := updateHostNetworkConfig(ctx, ns, hostVirtualSwitchChange(types.HostConfigChangeOperationAdd, name, spec)); err != nil {
+		return diag.FromErr(fmt.Errorf("error adding host vSwitch: %s", err))
 	}
 
 	saveHostVirtualSwitchID(d, hsID, name)
 
-	return resourceVSphereHostVirtualSwitchRead(d, meta)
+	return resourceVSphereHostVirtualSwitchRead(ctx, d, meta)
 }
 
-func resourceVSphereHostVirtualSwitchRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostVirtualSwitchRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := virtualSwitchIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
 	sw, err := hostVSwitchFromName(client, ns, name)
 	if err != nil {
-		return fmt.Errorf("error fetching virtual switch data: %s", err)
+		return diag.FromErr(fmt.Errorf("error fetching virtual switch data: %s", err))
 	}
 
 	if err := flattenHostVirtualSwitchSpec(d, &sw.Spec); err != nil {
-		return fmt.Errorf("error setting resource data: %s", err)
+		return diag.FromErr(fmt.Errorf("error setting resource data: %s", err))
 	}
 
 	return nil
 }
 
-func resourceVSphereHostVirtualSwitchUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostVirtualSwitchUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := virtualSwitchIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	spec := expandHostVirtualSwitchSpec(d)
-	if err := ns.UpdateVirtualSwitch(ctx, name, *spec); err != nil {
-		return fmt.Errorf("error updating host vSwitch: %s", err)
+	if _, err := updateHostNetworkConfig(ctx, ns, hostVirtualSwitchChange(types.HostConfigChangeOperationEdit, name, spec)); err != nil {
+		return diag.FromErr(fmt.Errorf("error updating host vSwitch: %s", err))
 	}
 
-	return resourceVSphereHostVirtualSwitchRead(d, meta)
+	return resourceVSphereHostVirtualSwitchRead(ctx, d, meta)
 }
 
-func resourceVSphereHostVirtualSwitchDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereHostVirtualSwitchDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hsID, name, err := virtualSwitchIDsFromResourceID(d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	ns, err := hostNetworkSystemFromHostSystemID(client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host network system: %s", err)
+		return diag.FromErr(fmt.Errorf("error loading host network system: %s", err))
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
-	if err := ns.RemoveVirtualSwitch(ctx, name); err != nil {
-		return fmt.Errorf("error deleting host vSwitch: %s", err)
+	if _, err := updateHostNetworkConfig(ctx, ns, hostVirtualSwitchChange(types.HostConfigChangeOperationRemove, name, nil)); err != nil {
+		return diag.FromErr(fmt.Errorf("error deleting host vSwitch: %s", err))
 	}
 
 	return nil
 }
 
-func resourceVSphereHostVirtualSwitchImport(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereHostVirtualSwitchImport(ctx context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	hostID, switchName, err := splitHostVirtualSwitchID(d.Id())
 	if err != nil {
 		return []*schema.ResourceData{}, err
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/iscsi"
@@ -15,10 +16,10 @@ import (
 
 func resourceVSphereIscsiSoftwareAdapter() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereIscsiSoftwareAdapterCreate,
-		Read:   resourceVSphereIscsiSoftwareAdapterRead,
-		Update: resourceVSphereIscsiSoftwareAdapterUpdate,
-		Delete: resourceVSphereIscsiSoftwareAdapterDelete,
+		CreateContext: resourceVSphereIscsiSoftwareAdapterCreate,
+		ReadContext:   resourceVSphereIscsiSoftwareAdapterRead,
+		UpdateContext: resourceVSphereIscsiSoftwareAdapterUpdate,
+		DeleteContext: resourceVSphereIscsiSoftwareAdapterDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: resourceVSphereIscsiSoftwareAdapterImport,
 		},
@@ -45,35 +46,35 @@ func resourceVSphereIscsiSoftwareAdapter() *schema.Resource {
 	}
 }
 
-func resourceVSphereIscsiSoftwareAdapterCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereIscsiSoftwareAdapterCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 
 	hss, err := hostsystem.GetHostStorageSystemFromHost(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err = iscsi.UpdateSoftwareInternetScsi(client, hss.Reference(), hostID, true); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	if err = hss.RescanAllHba(context.Background()); err != nil {
-		return fmt.Errorf(
+	if err = hss.RescanAllHba(ctx); err != nil {
+		return diag.FromErr(fmt.Errorf(
 			"error trying to rescan storage adapters after enabling iscsi software adapter for host '%s': %s",
 			hostID,
 			err,
-		)
+		))
 	}
 
 	hssProps, err := hostsystem.HostStorageSystemProperties(hss)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	adapter, err := iscsi.GetIscsiSoftwareAdater(hssProps, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%s:%s", hostID, adapter.Device))
@@ -81,7 +82,7 @@ func resourceVSphereIscsiSoftwareAdapterCreate(d *schema.ResourceData, meta inte
 
 	if name, ok := d.GetOk("iscsi_name"); ok {
 		if err = iscsi.UpdateIscsiName(hostID, adapter.Device, name.(string), client, hssProps.Reference()); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		d.Set("iscsi_name", name.(string))
@@ -89,49 +90,49 @@ func resourceVSphereIscsiSoftwareAdapterCreate(d *schema.ResourceData, meta inte
 		d.Set("iscsi_name", adapter.IScsiName)
 	}
 
-	return resourceVSphereIscsiSoftwareAdapterRead(d, meta)
+	return resourceVSphereIscsiSoftwareAdapterRead(ctx, d, meta)
 }
 
-func resourceVSphereIscsiSoftwareAdapterRead(d *schema.ResourceData, meta interface{}) error {
-	return iscsiSoftwareAdapterRead(d, meta, false)
+func resourceVSphereIscsiSoftwareAdapterRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(iscsiSoftwareAdapterRead(d, meta, false))
 }
 
-func resourceVSphereIscsiSoftwareAdapterUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereIscsiSoftwareAdapterUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var err error
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 
 	hssProps, err := hostsystem.GetHostStorageSystemPropertiesFromHost(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if d.HasChange("iscsi_name") {
 		_, iscsiName := d.GetChange("iscsi_name")
 		adapter, err := iscsi.GetIscsiSoftwareAdater(hssProps, hostID)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		if err = iscsi.UpdateIscsiName(hostID, adapter.Device, iscsiName.(string), client, hssProps.Reference()); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	return nil
 }
 
-func resourceVSphereIscsiSoftwareAdapterDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereIscsiSoftwareAdapterDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	var err error
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 
 	hssProps, err := hostsystem.GetHostStorageSystemPropertiesFromHost(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
-	return iscsi.UpdateSoftwareInternetScsi(client, hssProps.Reference(), hostID, false)
+	return diag.FromErr(iscsi.UpdateSoftwareInternetScsi(client, hssProps.Reference(), hostID, false))
 }
 
 func resourceVSphereIscsiSoftwareAdapterImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
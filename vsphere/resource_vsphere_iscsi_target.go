@@ -5,21 +5,43 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/iscsi"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
 func resourceVSphereIscsiTarget() *schema.Resource {
+	sendTarget := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			iscsi.IPResourceKey:   iscsi.IPSchema(),
+			iscsi.PortResourceKey: iscsi.PortSchema(),
+			iscsi.ChapResourceKey: iscsi.ChapSchema(),
+		},
+	}
+	staticTarget := &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			iscsi.IPResourceKey:   iscsi.IPSchema(),
+			iscsi.PortResourceKey: iscsi.PortSchema(),
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The iqn of the storage device",
+			},
+			iscsi.ChapResourceKey: iscsi.ChapSchema(),
+		},
+	}
+
 	return &schema.Resource{
-		Create: resourceVSphereIscsiTargetCreate,
-		Read:   resourceVSphereIscsiTargetRead,
-		Delete: resourceVSphereIscsiTargetDelete,
-		Update: resourceVSphereIscsiTargetUpdate,
+		CreateContext: resourceVSphereIscsiTargetCreate,
+		ReadContext:   resourceVSphereIscsiTargetRead,
+		DeleteContext: resourceVSphereIscsiTargetDelete,
+		UpdateContext: resourceVSphereIscsiTargetUpdate,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereIscsiTargetImport,
+			StateContext: resourceVSphereIscsiTargetImport,
 		},
 
 		CustomizeDiff: resourceVSphereIscsiTargetCustomDiff,
@@ -39,47 +61,32 @@ func resourceVSphereIscsiTarget() *schema.Resource {
 			"send_target": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						iscsi.IPResourceKey:   iscsi.IPSchema(),
-						iscsi.PortResourceKey: iscsi.PortSchema(),
-						iscsi.ChapResourceKey: iscsi.ChapSchema(),
-					},
-				},
+				Elem:     sendTarget,
+				Set:      iscsi.TargetHashFunc(sendTarget),
 			},
 			"static_target": {
 				Type:     schema.TypeSet,
 				Optional: true,
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						iscsi.IPResourceKey:   iscsi.IPSchema(),
-						iscsi.PortResourceKey: iscsi.PortSchema(),
-						"name": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "The iqn of the storage device",
-						},
-						iscsi.ChapResourceKey: iscsi.ChapSchema(),
-					},
-				},
+				Elem:     staticTarget,
+				Set:      iscsi.TargetHashFunc(staticTarget),
 			},
 		},
 	}
 }
 
-func resourceVSphereIscsiTargetCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereIscsiTargetCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 	adapterID := d.Get("adapter_id").(string)
 
 	hss, err := hostsystem.GetHostStorageSystemFromHost(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hssProps, err := hostsystem.HostStorageSystemProperties(hss)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	sendTargets := d.Get("send_target").(*schema.Set).List()
@@ -87,8 +94,14 @@ func resourceVSphereIscsiTargetCreate(d *schema.ResourceData, meta interface{})
 
 	for _, v := range sendTargets {
 		sendTarget := v.(map[string]interface{})
-		outgoingCreds := iscsi.ExtractChapCredsFromTarget(sendTarget, true)
-		incomingCreds := iscsi.ExtractChapCredsFromTarget(sendTarget, false)
+		outgoingCreds, err := iscsi.ExtractChapCredsFromTarget(sendTarget, true)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		incomingCreds, err := iscsi.ExtractChapCredsFromTarget(sendTarget, false)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 		authSettings := &types.HostInternetScsiHbaAuthenticationProperties{}
 
 		if len(outgoingCreds["username"].(string)) > 0 {
@@ -120,7 +133,7 @@ func resourceVSphereIscsiTargetCreate(d *schema.ResourceData, meta interface{})
 			hssProps,
 			hbaSendTargets,
 		); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -129,8 +142,14 @@ func resourceVSphereIscsiTargetCreate(d *schema.ResourceData, meta interface{})
 
 	for _, v := range staticTargets {
 		staticTarget := v.(map[string]interface{})
-		outgoingCreds := iscsi.ExtractChapCredsFromTarget(staticTarget, true)
-		incomingCreds := iscsi.ExtractChapCredsFromTarget(staticTarget, false)
+		outgoingCreds, err := iscsi.ExtractChapCredsFromTarget(staticTarget, true)
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		incomingCreds, err := iscsi.ExtractChapCredsFromTarget(staticTarget, false)
+		if err != nil {
+			return diag.FromErr(err)
+		}
 		authSettings := &types.HostInternetScsiHbaAuthenticationProperties{}
 
 		if len(outgoingCreds["username"].(string)) > 0 {
@@ -163,41 +182,41 @@ func resourceVSphereIscsiTargetCreate(d *schema.ResourceData, meta interface{})
 			hssProps,
 			hbaStaticTargets,
 		); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if err = iscsi.RescanStorageDevices(hss); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	d.SetId(fmt.Sprintf("%s:%s", hostID, adapterID))
 
-	return nil
+	return resourceVSphereIscsiTargetRead(ctx, d, meta)
 }
 
-func resourceVSphereIscsiTargetRead(d *schema.ResourceData, meta interface{}) error {
-	return iscsiTargetRead(
+func resourceVSphereIscsiTargetRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(iscsiTargetRead(
 		d,
 		meta,
 		d.Get("host_system_id").(string),
 		d.Get("adapter_id").(string),
 		true,
-	)
+	))
 }
 
-func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereIscsiTargetUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 	adapterID := d.Get("adapter_id").(string)
 
 	hssProps, err := hostsystem.GetHostStorageSystemPropertiesFromHost(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if _, err = iscsi.GetIscsiAdater(hssProps, hostID, adapterID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if d.HasChange("send_target") {
@@ -220,8 +239,14 @@ func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{})
 
 		for _, v := range addTargets {
 			addTarget := v.(map[string]interface{})
-			outgoingCreds := iscsi.ExtractChapCredsFromTarget(addTarget, true)
-			incomingCreds := iscsi.ExtractChapCredsFromTarget(addTarget, false)
+			outgoingCreds, err := iscsi.ExtractChapCredsFromTarget(addTarget, true)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			incomingCreds, err := iscsi.ExtractChapCredsFromTarget(addTarget, false)
+			if err != nil {
+				return diag.FromErr(err)
+			}
 			authSettings := &types.HostInternetScsiHbaAuthenticationProperties{}
 
 			if len(outgoingCreds["username"].(string)) > 0 {
@@ -253,7 +278,7 @@ func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{})
 				hssProps,
 				hbaRemoveTargets,
 			); err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 
@@ -265,7 +290,7 @@ func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{})
 				hssProps,
 				hbaAddTargets,
 			); err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
@@ -291,8 +316,14 @@ func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{})
 
 		for _, v := range addTargets {
 			addTarget := v.(map[string]interface{})
-			outgoingCreds := iscsi.ExtractChapCredsFromTarget(addTarget, true)
-			incomingCreds := iscsi.ExtractChapCredsFromTarget(addTarget, false)
+			outgoingCreds, err := iscsi.ExtractChapCredsFromTarget(addTarget, true)
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			incomingCreds, err := iscsi.ExtractChapCredsFromTarget(addTarget, false)
+			if err != nil {
+				return diag.FromErr(err)
+			}
 			authSettings := &types.HostInternetScsiHbaAuthenticationProperties{}
 
 			if len(outgoingCreds["username"].(string)) > 0 {
@@ -325,7 +356,7 @@ func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{})
 				hssProps,
 				hbaRemoveTargets,
 			); err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 
@@ -337,27 +368,27 @@ func resourceVSphereIscsiTargetUpdate(d *schema.ResourceData, meta interface{})
 				hssProps,
 				hbaAddTargets,
 			); err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	return nil
+	return resourceVSphereIscsiTargetRead(ctx, d, meta)
 }
 
-func resourceVSphereIscsiTargetDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereIscsiTargetDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID := d.Get("host_system_id").(string)
 	adapterID := d.Get("adapter_id").(string)
 
 	hss, err := hostsystem.GetHostStorageSystemFromHost(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hssProps, err := hostsystem.HostStorageSystemProperties(hss)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	sendTargets := d.Get("send_target").(*schema.Set).List()
@@ -385,24 +416,24 @@ func resourceVSphereIscsiTargetDelete(d *schema.ResourceData, meta interface{})
 
 	if len(removeSendTargets) > 0 {
 		if err = iscsi.RemoveInternetScsiSendTargets(client, hostID, adapterID, hssProps, removeSendTargets); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if len(removeStaticTargets) > 0 {
 		if err = iscsi.RemoveInternetScsiStaticTargets(client, hostID, adapterID, hssProps, removeStaticTargets); err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
 	if err = iscsi.RescanStorageDevices(hss); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceVSphereIscsiTargetImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereIscsiTargetImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	idSplit := strings.Split(d.Id(), ":")
 
 	if len(idSplit) != 2 {
@@ -521,7 +552,7 @@ func iscsiTargetRead(d *schema.ResourceData, meta interface{}, hostID, adapterID
 
 				if currentSendTarget["ip"].(string) == target["ip"].(string) &&
 					int32(currentSendTarget["port"].(int)) == target["port"].(int32) {
-					target["chap"] = currentSendTarget["chap"]
+					target["chap"] = iscsi.FingerprintChap(currentSendTarget["chap"])
 				}
 			}
 		} else {
@@ -530,13 +561,13 @@ func iscsiTargetRead(d *schema.ResourceData, meta interface{}, hostID, adapterID
 					"outgoing_creds": []interface{}{
 						map[string]interface{}{
 							"username": sendTarget.AuthenticationProperties.ChapName,
-							"password": sendTarget.AuthenticationProperties.ChapSecret,
+							"password": secret.StateValue(sendTarget.AuthenticationProperties.ChapSecret),
 						},
 					},
 					"incoming_creds": []interface{}{
 						map[string]interface{}{
 							"username": sendTarget.AuthenticationProperties.MutualChapName,
-							"password": sendTarget.AuthenticationProperties.MutualChapSecret,
+							"password": secret.StateValue(sendTarget.AuthenticationProperties.MutualChapSecret),
 						},
 					},
 				},
@@ -562,7 +593,7 @@ func iscsiTargetRead(d *schema.ResourceData, meta interface{}, hostID, adapterID
 				if currentStaticTarget["ip"].(string) == target["ip"].(string) &&
 					int32(currentStaticTarget["port"].(int)) == target["port"].(int32) &&
 					currentStaticTarget["name"].(string) == target["name"].(string) {
-					target["chap"] = currentStaticTarget["chap"]
+					target["chap"] = iscsi.FingerprintChap(currentStaticTarget["chap"])
 				}
 			}
 		} else {
@@ -571,13 +602,13 @@ func iscsiTargetRead(d *schema.ResourceData, meta interface{}, hostID, adapterID
 					"outgoing_creds": []interface{}{
 						map[string]interface{}{
 							"username": staticTarget.AuthenticationProperties.ChapName,
-							"password": staticTarget.AuthenticationProperties.ChapSecret,
+							"password": secret.StateValue(staticTarget.AuthenticationProperties.ChapSecret),
 						},
 					},
 					"incoming_creds": []interface{}{
 						map[string]interface{}{
 							"username": staticTarget.AuthenticationProperties.MutualChapName,
-							"password": staticTarget.AuthenticationProperties.MutualChapSecret,
+							"password": secret.StateValue(staticTarget.AuthenticationProperties.MutualChapSecret),
 						},
 					},
 				},
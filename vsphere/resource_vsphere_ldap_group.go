@@ -4,25 +4,26 @@
 package vsphere
 
 import (
-	"fmt"
 	"context"
-	"strings"
+	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/ssoadmin"
-	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
 	"github.com/vmware/govmomi/ssoadmin/methods"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+	"strings"
 )
 
 // var identitynotfound = errors.New("could not find identity source - this might be expected")
 
 func resourceVSphereLDAPGroup() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereLDAPGroupCreate,
-		Read:   resourceVSphereLDAPGroupRead,
+		CreateContext: resourceVSphereLDAPGroupCreate,
+		ReadContext:   resourceVSphereLDAPGroupRead,
 		// Note: Due to all params on this resource being marked as ForceNew there is no need for an update function. If params change it simply runs a 'Delete' then a 'Create'
-		Delete: resourceVSphereLDAPGroupDelete,
+		DeleteContext: resourceVSphereLDAPGroupDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereLDAPGroupImport,
+			StateContext: resourceVSphereLDAPGroupImport,
 		},
 		CustomizeDiff: resourceVSphereLDAPGroupCustomDiff,
 		Schema: map[string]*schema.Schema{
@@ -46,10 +47,7 @@ func resourceVSphereLDAPGroup() *schema.Resource {
 }
 
 // function for sanity checking the passed in vsphere_group actually exists in vsphere (this resource does NOT create vsphere_group(s))
-func vsphereGroupExists(ssoclient *ssoadmin.Client, group_name string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
-
+func vsphereGroupExists(ctx context.Context, ssoclient *ssoadmin.Client, group_name string) error {
 	group, err := ssoclient.FindGroup(ctx, group_name)
 	if err != nil {
 		return fmt.Errorf("error fetching groups: %s\n", err)
@@ -65,58 +63,57 @@ func vsphereGroupExists(ssoclient *ssoadmin.Client, group_name string) error {
 }
 
 // function which sanity checks the vsphere_group exists AND checks if the given ldap_group is a member of the vsphere_group already
-func ldapGroupInVsphereGroupCheck(ssoclient *ssoadmin.Client, vsphere_group string, ldap_group string) (*ssoadmin_types.AdminGroup, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
+// - only the groups the ldap_group is a direct member of are read, not every member of the vsphere_group
+func ldapGroupInVsphereGroupCheck(ctx context.Context, ssoclient *ssoadmin.Client, vsphere_group string, ldap_group ssoadmin_types.PrincipalId) (bool, error) {
+	err := vsphereGroupExists(ctx, ssoclient, vsphere_group)
+	if err != nil {
+		return false, fmt.Errorf("error in ldapGroupInVsphereGroupCheck func - the vsphere group %s does not exist", vsphere_group)
+	}
+
+	return ssoIsDirectMember(ctx, ssoclient, ldap_group, vsphere_group)
+}
 
-	err := vsphereGroupExists(ssoclient, vsphere_group)
+// function which finds the ldap_group in the members of the vsphere_group, for an import ID that does not give the domain of the ldap_group
+func ldapGroupFindInVsphereGroup(ctx context.Context, ssoclient *ssoadmin.Client, vsphere_group string, ldap_group string) (*ssoadmin_types.AdminGroup, error) {
+	err := vsphereGroupExists(ctx, ssoclient, vsphere_group)
 	if err != nil {
-		return nil, fmt.Errorf("error in ldapGroupInVsphereGroupCheck func - the vsphere group %s does not exist", vsphere_group)
+		return nil, fmt.Errorf("the vsphere group %s does not exist", vsphere_group)
 	}
 
 	// NOTE: This function accepts a 'search' string as the last param but it does not seem to do anything.
 	// It seems to always return the entire array of groups within the group you are searching in.
 	groups_in_group, err := ssoclient.FindGroupsInGroup(ctx, vsphere_group, ldap_group)
-
 	if err != nil {
 		return nil, fmt.Errorf("error locating groups in group. error: %s\n", err)
 	}
 
-	// if we returned any groups at all..
-	if groups_in_group != nil {
-
-		// iterate the array of returned groups
-		for _, value := range groups_in_group {
-			// If we find the group we are trying to add already within the group..
-			if value.Id.Name == ldap_group {
-				// if the ldap_group is already a member of the vsphere_group
-				return &value, nil
-			}
+	for _, value := range groups_in_group {
+		if strings.EqualFold(value.Id.Name, ldap_group) {
+			return &value, nil
 		}
 	}
-	// if ldap_group is not currently a member of the vsphere_group...
 	return nil, nil
 }
 
-func resourceVSphereLDAPGroupCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLDAPGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ssoclient := meta.(*Client).ssoClient
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
-	err := vsphereGroupExists(ssoclient, d.Get("vsphere_group").(string))
+	err := vsphereGroupExists(ctx, ssoclient, d.Get("vsphere_group").(string))
 	if err != nil {
-		return fmt.Errorf("error in create func - the vsphere group %s does not exist", d.Get("vsphere_group").(string))
+		return diag.FromErr(fmt.Errorf("error in create func - the vsphere group %s does not exist", d.Get("vsphere_group").(string)))
 	}
 
-	group_to_add := ssoadmin_types.PrincipalId {
-		Name: d.Get("ldap_group").(string),
+	group_to_add := ssoadmin_types.PrincipalId{
+		Name:   d.Get("ldap_group").(string),
 		Domain: d.Get("domain_name").(string),
 	}
 
 	// add the ldap_group to the vsphere_group
 	err = ssoclient.AddGroupsToGroup(ctx, d.Get("vsphere_group").(string), group_to_add)
 	if err != nil {
-		return fmt.Errorf("error adding ldap_group to the vsphere_group: %s\n", err.Error())
+		return diag.FromErr(fmt.Errorf("error adding ldap_group to the vsphere_group: %s\n", err.Error()))
 
 	}
 
@@ -124,67 +121,72 @@ func resourceVSphereLDAPGroupCreate(d *schema.ResourceData, meta interface{}) er
 	id := d.Get("vsphere_group").(string) + ":" + d.Get("ldap_group").(string)
 	d.SetId(id)
 
-	return resourceVSphereLDAPGroupRead(d, meta)
+	return resourceVSphereLDAPGroupRead(ctx, d, meta)
 }
 
-func resourceVSphereLDAPGroupRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLDAPGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ssoclient := meta.(*Client).ssoClient
-	
-	group, err := ldapGroupInVsphereGroupCheck(ssoclient, d.Get("vsphere_group").(string), d.Get("ldap_group").(string))
-	if err != nil {
-		return fmt.Errorf("Read func - error checking if ldap_group '%s' is a member of vsphere_group '%s': %s", d.Get("ldap_group").(string), d.Get("vsphere_group").(string), err)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	ldap_group := ssoadmin_types.PrincipalId{
+		Name:   d.Get("ldap_group").(string),
+		Domain: d.Get("domain_name").(string),
 	}
-	// If the ldap_group is already a member of the vsphere_group
-	if group != nil {
-		d.Set("ldap_group", group.Id.Name)
-		d.Set("domain_name", group.Id.Domain)
 
-	} 
+	found, err := ldapGroupInVsphereGroupCheck(ctx, ssoclient, d.Get("vsphere_group").(string), ldap_group)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("Read func - error checking if ldap_group '%s' is a member of vsphere_group '%s': %s", d.Get("ldap_group").(string), d.Get("vsphere_group").(string), err))
+	}
+	// If the ldap_group was removed from the vsphere_group outside of terraform
+	if !found {
+		d.SetId("")
+	}
 
 	return nil
 }
 
-func resourceVSphereLDAPGroupDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLDAPGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	ssoclient := meta.(*Client).ssoClient
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
-	err := vsphereGroupExists(ssoclient, d.Get("vsphere_group").(string))
+	err := vsphereGroupExists(ctx, ssoclient, d.Get("vsphere_group").(string))
 	if err != nil {
-		return fmt.Errorf("Error in delete func - unable to locate vsphere group: %s", err)
+		return diag.FromErr(fmt.Errorf("Error in delete func - unable to locate vsphere group: %s", err))
 	}
 
-	group_to_remove := []ssoadmin_types.PrincipalId {
+	group_to_remove := []ssoadmin_types.PrincipalId{
 		{
-			Name: d.Get("ldap_group").(string),
+			Name:   d.Get("ldap_group").(string),
 			Domain: d.Get("domain_name").(string),
 		},
 	}
 
-	a := ssoadmin_types.RemovePrincipalsFromLocalGroup {
-		This: ssoclient.ServiceContent.PrincipalManagementService,
+	a := ssoadmin_types.RemovePrincipalsFromLocalGroup{
+		This:          ssoclient.ServiceContent.PrincipalManagementService,
 		PrincipalsIds: group_to_remove,
-		GroupName: d.Get("vsphere_group").(string),	
+		GroupName:     d.Get("vsphere_group").(string),
 	}
 
 	// remove the ldap_group from the vsphere_group
 	_, err = methods.RemovePrincipalsFromLocalGroup(ctx, ssoclient, &a)
 	if err != nil {
-		return fmt.Errorf("error removing ldap_group %s from vsphere_group %s. Error: %s", d.Get("ldap_group").(string), d.Get("vsphere_group").(string), err)
+		return diag.FromErr(fmt.Errorf("error removing ldap_group %s from vsphere_group %s. Error: %s", d.Get("ldap_group").(string), d.Get("vsphere_group").(string), err))
 	}
 	return nil
 }
 
 // NOTE: This import will create the resource within state successfully but the next 'terraform apply' WILL note some changes for it, even if there is nothing actually changing
 // this is due to our inability to fetch the currently configured passwords that LDAP is using and TF will enforce the ones defined in it.
-func resourceVSphereLDAPGroupImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereLDAPGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	ssoclient := meta.(*Client).ssoClient
 
 	d.SetId(d.Id())
 
-	error_msg := "Invalid Id format given. Only 2 strings seperated by a ':' is allowed. Proper format is: 'vsphere_group_name:ldap_group_name'"
+	error_msg := "Invalid Id format given. Only 2 strings seperated by a ':' is allowed. Proper format is: 'vsphere_group_name:ldap_group_name' or 'vsphere_group_name:ldap_group_name@domain_name'"
 
-	// ID format we want should be 'vsphere_group:ldap_group'
+	// ID format we want should be 'vsphere_group:ldap_group' or 'vsphere_group:ldap_group@domain_name'
 	id_split := strings.Split(d.Id(), ":")
 	if len(id_split) != 2 {
 		return nil, fmt.Errorf("%s", error_msg)
@@ -196,36 +198,63 @@ func resourceVSphereLDAPGroupImport(d *schema.ResourceData, meta interface{}) ([
 	vsphere_group := id_split[0]
 	ldap_group := id_split[1]
 
-	group, err := ldapGroupInVsphereGroupCheck(ssoclient, vsphere_group, ldap_group)
-	if err != nil {
-		return nil, fmt.Errorf("import func - error checking if ldap_group '%s' is a member of vsphere_group '%s': %s", ldap_group, vsphere_group, err)
-	}
-	// sanity check that the ldap_group given IS actually a member of the given vsphere_group
-	if group == nil {
-		return nil, fmt.Errorf("import func - ldap_group '%s' is NOT a member of vsphere_group '%s'", ldap_group, vsphere_group)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	// the ldap_group may be given as 'ldap_group@domain_name', which is checked directly.
+	// Otherwise the domain is found by listing the members of the vsphere_group.
+	var member ssoadmin_types.PrincipalId
+	if name, domain, ok := strings.Cut(ldap_group, "@"); ok {
+		member = ssoadmin_types.PrincipalId{Name: name, Domain: domain}
+		found, err := ldapGroupInVsphereGroupCheck(ctx, ssoclient, vsphere_group, member)
+		if err != nil {
+			return nil, fmt.Errorf("import func - error checking if ldap_group '%s' is a member of vsphere_group '%s': %s", ldap_group, vsphere_group, err)
+		}
+		if !found {
+			return nil, fmt.Errorf("import func - ldap_group '%s' is NOT a member of vsphere_group '%s'", ldap_group, vsphere_group)
+		}
+	} else {
+		group, err := ldapGroupFindInVsphereGroup(ctx, ssoclient, vsphere_group, ldap_group)
+		if err != nil {
+			return nil, fmt.Errorf("import func - error checking if ldap_group '%s' is a member of vsphere_group '%s': %s", ldap_group, vsphere_group, err)
+		}
+		// sanity check that the ldap_group given IS actually a member of the given vsphere_group
+		if group == nil {
+			return nil, fmt.Errorf("import func - ldap_group '%s' is NOT a member of vsphere_group '%s'", ldap_group, vsphere_group)
+		}
+		member = group.Id
 	}
 
+	d.SetId(vsphere_group + ":" + member.Name)
 	d.Set("vsphere_group", vsphere_group)
-	d.Set("ldap_group", ldap_group)
+	d.Set("ldap_group", member.Name)
+	d.Set("domain_name", member.Domain)
 
 	return []*schema.ResourceData{d}, nil
 }
 
-
 // this function sanity checks that the ldap_group you are trying to add to the vsphere_group with terraform is not going to create an error when you run a 'terraform apply'
 // - e.g. this function attempts to catch errors in a 'terraform plan'
 func resourceVSphereLDAPGroupCustomDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
 	// If the LDAP group does NOT exist in state yet...
-	if d.Id() == "" {
+	// The check needs all of the names, which may not be known until apply.
+	if d.Id() == "" && d.NewValueKnown("vsphere_group") && d.NewValueKnown("ldap_group") && d.NewValueKnown("domain_name") {
 		ssoclient := meta.(*Client).ssoClient
+		ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+		defer cancel()
+
+		ldap_group := ssoadmin_types.PrincipalId{
+			Name:   d.Get("ldap_group").(string),
+			Domain: d.Get("domain_name").(string),
+		}
 
-		// check to see if the vsphere_group exists and if the ldap_group is already a member of the vsphere_group 
+		// check to see if the vsphere_group exists and if the ldap_group is already a member of the vsphere_group
 		// - this is what alerts you to a possible issue via 'terraform plan' instead of the 'plan' saying all is good and the 'apply' actually failing
-		group, err := ldapGroupInVsphereGroupCheck(ssoclient, d.Get("vsphere_group").(string), d.Get("ldap_group").(string))
+		found, err := ldapGroupInVsphereGroupCheck(ctx, ssoclient, d.Get("vsphere_group").(string), ldap_group)
 		if err != nil {
 			return fmt.Errorf("error in custom diff func checking if ldap_group already in vsphere_group: %s", err)
 		}
-		if group != nil {
+		if found {
 			return fmt.Errorf("error: ldap_group: %s is already a member of vsphere_group %s - consider a 'terraform import'!", d.Get("ldap_group").(string), d.Get("vsphere_group").(string))
 		}
 	}
@@ -2,12 +2,14 @@
 package vsphere
 
 import (
+	"context"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"testing"
-	"fmt"
-	"strings"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
 	"os"
+	"strings"
+	"testing"
 )
 
 func TestAccResourceVSphereLdapGroup_basic(t *testing.T) {
@@ -16,7 +18,7 @@ func TestAccResourceVSphereLdapGroup_basic(t *testing.T) {
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
-			testAccCheckEnvVariables(t, []string{"ldap_group", "vsphere_group",  "domain_name"})
+			testAccCheckEnvVariables(t, []string{"ldap_group", "vsphere_group", "domain_name"})
 		},
 		Providers:    testAccProviders,
 		CheckDestroy: testAccVSphereLdapGroupDestroy,
@@ -29,9 +31,16 @@ func TestAccResourceVSphereLdapGroup_basic(t *testing.T) {
 				),
 			},
 			{
-				ResourceName: resource_name,
-				Config:       testAccResourceVSphereLdapGroupConfig(),
+				ResourceName:      resource_name,
+				Config:            testAccResourceVSphereLdapGroupConfig(),
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+			{
+				ResourceName:      resource_name,
+				Config:            testAccResourceVSphereLdapGroupConfig(),
 				ImportState:       true,
+				ImportStateId:     os.Getenv("vsphere_group") + ":" + os.Getenv("ldap_group") + "@" + os.Getenv("domain_name"),
 				ImportStateVerify: true,
 			},
 		},
@@ -47,12 +56,13 @@ func testAccVSphereLdapGroupDestroy(s *terraform.State) error {
 		ssoclient := testAccProvider.Meta().(*Client).ssoClient
 
 		id_split := strings.Split(rs.Primary.ID, ":")
-		
-		group, err := ldapGroupInVsphereGroupCheck(ssoclient, id_split[0], id_split[1])
+
+		ldap_group := ssoadmin_types.PrincipalId{Name: id_split[1], Domain: rs.Primary.Attributes["domain_name"]}
+		found, err := ldapGroupInVsphereGroupCheck(context.Background(), ssoclient, id_split[0], ldap_group)
 		if err != nil {
 			return fmt.Errorf("there was an error in the test destroy func: %s", err)
 		}
-		if group != nil {
+		if found {
 			return fmt.Errorf("the ldap_group is still a member of the vsphere_group but should have been removed during destroy action")
 		}
 	}
@@ -75,7 +85,6 @@ func testAccResourceVSphereLdapGroupConfig() string {
 	)
 }
 
-
 func testAccVSphereLdapGroupExists(name string) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		rs, ok := s.RootModule().Resources[name]
@@ -85,15 +94,15 @@ func testAccVSphereLdapGroupExists(name string) resource.TestCheckFunc {
 		}
 		ssoclient := testAccProvider.Meta().(*Client).ssoClient
 		id_split := strings.Split(rs.Primary.ID, ":")
-		
-		group, err := ldapGroupInVsphereGroupCheck(ssoclient, id_split[0], id_split[1])
+
+		ldap_group := ssoadmin_types.PrincipalId{Name: id_split[1], Domain: rs.Primary.Attributes["domain_name"]}
+		found, err := ldapGroupInVsphereGroupCheck(context.Background(), ssoclient, id_split[0], ldap_group)
 		if err != nil {
 			return fmt.Errorf("Error checking the group membership of %s: %s", id_split[0], err)
 		}
-		if group == nil {
+		if !found {
 			return fmt.Errorf("The vsphere group: %s was supposed to contain ldap group: %s and did not", id_split[0], id_split[1])
 		}
 		return nil
 	}
 }
-
@@ -4,28 +4,35 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"context"
+	"log"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/identitysource"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/vmware/govmomi/ssoadmin"
 	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
 
 	"github.com/vmware/govmomi/ssoadmin/methods"
-
 )
 
 var identitynotfound = errors.New("could not find identity source - this might be expected")
 
 func resourceVSphereLDAPIdentitySource() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereLDAPIdentitySourceCreate,
-		Read:   resourceVSphereLDAPIdentitySourceRead,
-		Update: resourceVSphereLDAPIdentitySourceUpdate,
-		Delete: resourceVSphereLDAPIdentitySourceDelete,
+		CreateContext: resourceVSphereLDAPIdentitySourceCreate,
+		ReadContext:   resourceVSphereLDAPIdentitySourceRead,
+		UpdateContext: resourceVSphereLDAPIdentitySourceUpdate,
+		DeleteContext: resourceVSphereLDAPIdentitySourceDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereLDAPIdentitySourceImport,
+			StateContext: resourceVSphereLDAPIdentitySourceImport,
 		},
 		CustomizeDiff: resourceVSphereLDAPIdentitySourceCustomDiff,
 		Schema: map[string]*schema.Schema{
@@ -34,8 +41,12 @@ func resourceVSphereLDAPIdentitySource() *schema.Resource {
 				Required: true,
 			},
 			"ldap_password": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				StateFunc:        secret.StateFunc,
+				DiffSuppressFunc: secret.DiffSuppressFunc,
+				Description:      "The password of ldap_username, or file:// followed by the path of a local file that contains it. Only a fingerprint of the password is saved in state.",
 			},
 			"domain_alias": {
 				Type:     schema.TypeString,
@@ -48,22 +59,26 @@ func resourceVSphereLDAPIdentitySource() *schema.Resource {
 				ForceNew: true,
 			},
 			"server_type": {
-				Type:     schema.TypeString,
-				ForceNew: true,
-				Default: "ActiveDirectory",
-				Optional: true,
+				Type:         schema.TypeString,
+				ForceNew:     true,
+				Default:      identitysource.ServerTypeActiveDirectory,
+				Optional:     true,
+				Description:  "The type of LDAP server. Can be one of ActiveDirectory or OpenLdap.",
+				ValidateFunc: validation.StringInSlice(identitysource.ServerTypes, false),
 			},
 			"friendly_name": {
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"user_base_dn": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: identitysource.ValidateDN,
 			},
 			"group_base_dn": {
-				Type:     schema.TypeString,
-				Required: true,
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: identitysource.ValidateDN,
 			},
 			"primary_url": {
 				Type:     schema.TypeString,
@@ -71,9 +86,30 @@ func resourceVSphereLDAPIdentitySource() *schema.Resource {
 			},
 			"failover_url": {
 				Type:     schema.TypeString,
-				Default: "",
+				Default:  "",
 				Optional: true,
 			},
+			"certificates": {
+				Type:        schema.TypeList,
+				Optional:    true,
+				Description: "The PEM encoded certificates of the LDAP servers, which vCenter uses to trust ldaps:// URLs.",
+				Elem: &schema.Schema{
+					Type:         schema.TypeString,
+					ValidateFunc: identitysource.ValidateCertificate,
+				},
+			},
+			"default_domain": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Make this identity source the default domain, so that users can log in without a domain suffix.",
+			},
+			"validate_connectivity": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "Have vCenter bind to the LDAP servers with ldap_username and ldap_password while planning, so that a wrong URL or credential fails the plan.",
+			},
 
 			// Add tags schema
 			vSphereTagAttributeKey: tagsSchema(),
@@ -84,44 +120,65 @@ func resourceVSphereLDAPIdentitySource() *schema.Resource {
 	}
 }
 
-func resourceVSphereLDAPIdentitySourceCreate(d *schema.ResourceData, meta interface{}) error {
-	ssoclient := meta.(*Client).ssoClient
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func resourceVSphereLDAPIdentitySourceCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
-
-	_, err := identitySourceExists(ssoclient, d.Get("domain_name").(string))
+	_, err = identitySourceExists(ssoclient, d.Get("domain_name").(string))
 	// check if the domain we are about to create already exists (we don't want it to)
 	if err == nil {
-		return fmt.Errorf("the domain %s already exists", d.Get("domain_name").(string))
+		return diag.FromErr(fmt.Errorf("the domain %s already exists", d.Get("domain_name").(string)))
 	}
 	if !errors.Is(err, identitynotfound) {
-		return fmt.Errorf("error getting currently configured ldap identity source: %s", err)
+		return diag.FromErr(fmt.Errorf("error getting currently configured ldap identity source: %s", err))
 	}
 
-	details := ssoadmin_types.LdapIdentitySourceDetails {
-		FriendlyName: d.Get("friendly_name").(string),
-		UserBaseDn: d.Get("user_base_dn").(string),
-		GroupBaseDn: d.Get("group_base_dn").(string),
-		PrimaryURL: d.Get("primary_url").(string),
-		FailoverURL: d.Get("failover_url").(string),
+	password, err := secret.Get(d, "ldap_password")
+	if err != nil {
+		return diag.FromErr(err)
 	}
-	auth := ssoadmin_types.SsoAdminIdentitySourceManagementServiceAuthenticationCredentails {
+	details, err := expandLDAPIdentitySourceDetails(d.Get)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	auth := ssoadmin_types.SsoAdminIdentitySourceManagementServiceAuthenticationCredentails{
 		Username: d.Get("ldap_username").(string),
-		Password: d.Get("ldap_password").(string),
+		Password: password,
 	}
 
 	// actually add the LDAP identity source to vcenter
-	err = ssoclient.RegisterLdap(ctx, d.Get("server_type").(string), d.Get("domain_name").(string), d.Get("domain_alias").(string), details, auth)
+	err = identitysource.RegisterLdap(ctx, ssoclient, d.Get("server_type").(string), d.Get("domain_name").(string), d.Get("domain_alias").(string), details, auth)
 	if err != nil {
-		return fmt.Errorf("error registering ldap: %s\n", err.Error())
+		return diag.FromErr(fmt.Errorf("error registering ldap: %s\n", err.Error()))
 
 	}
 
 	// add the resource into the terraform state
 	d.SetId(d.Get("domain_name").(string))
 
-	return resourceVSphereLDAPIdentitySourceRead(d, meta)
+	// vCenter can only search under the base DNs, and trust an ldaps:// URL,
+	// once the identity source is registered, so a failed check unregisters
+	// it again
+	if diags := resourceVSphereLDAPIdentitySourceVerify(ctx, d, ssoclient, password); diags.HasError() {
+		log.Printf("[DEBUG] Unregistering %s, as it failed verification", d.Id())
+		if err := ldapIdentitySourceDelete(ctx, ssoclient, d.Id()); err != nil {
+			return append(diags, diag.FromErr(fmt.Errorf("error unregistering %s after it failed verification: %s", d.Id(), err))...)
+		}
+		d.SetId("")
+		return diags
+	}
+
+	if d.Get("default_domain").(bool) {
+		if err := ssoclient.SetDefaultDomains(ctx, d.Id()); err != nil {
+			return diag.FromErr(fmt.Errorf("error setting %s as the default domain: %s", d.Id(), err))
+		}
+	}
+
+	return resourceVSphereLDAPIdentitySourceRead(ctx, d, meta)
 }
 
 func identitySourceExists(ssoclient *ssoadmin.Client, id string) (*ssoadmin_types.LdapIdentitySource, error) {
@@ -144,17 +201,20 @@ func identitySourceExists(ssoclient *ssoadmin.Client, id string) (*ssoadmin_type
 	return nil, identitynotfound
 }
 
-func resourceVSphereLDAPIdentitySourceRead(d *schema.ResourceData, meta interface{}) error {
-	ssoclient := meta.(*Client).ssoClient
+func resourceVSphereLDAPIdentitySourceRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
 
 	// if the user specifies a LDAP source to be created that already exists in vcenter this will fail to be created as there is a name conflict
 	identitySource, err := identitySourceExists(ssoclient, d.Id())
 	if err != nil {
-		return fmt.Errorf("Read func - error checking if existing ldap source exists: %s", err)
+		return diag.FromErr(fmt.Errorf("Read func - error checking if existing ldap source exists: %s", err))
 	}
 
 	d.Set("domain_name", identitySource.Name)
-	d.Set("domain_alias", identitySource.Name)
+	d.Set("domain_alias", ldapIdentitySourceAlias(identitySource))
 	d.Set("server_type", identitySource.Type)
 	d.Set("friendly_name", identitySource.Details.FriendlyName)
 	d.Set("user_base_dn", identitySource.Details.UserBaseDn)
@@ -162,83 +222,151 @@ func resourceVSphereLDAPIdentitySourceRead(d *schema.ResourceData, meta interfac
 	d.Set("primary_url", identitySource.Details.PrimaryURL)
 	d.Set("failover_url", identitySource.Details.FailoverURL)
 	d.Set("ldap_username", identitySource.AuthenticationDetails.Username)
-	// we are unable to get the password via the API for this
-	d.Set("ldap_password", d.Get("ldap_password"))
+	// we are unable to get the password via the API for this, so keep the
+	// fingerprint of the configured one
+	d.Set("ldap_password", secret.Refingerprint(d, "ldap_password", d.Get("ldap_password").(string)))
+	// the certificates are not returned by the API either, so the configured
+	// ones are kept as they are
+
+	defaultDomain, err := ldapIdentitySourceIsDefault(ctx, ssoclient, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("default_domain", defaultDomain)
 
 	return nil
 }
 
-func resourceVSphereLDAPIdentitySourceUpdate(d *schema.ResourceData, meta interface{}) error {
-	ssoclient := meta.(*Client).ssoClient
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func resourceVSphereLDAPIdentitySourceUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
-
-
-	_, err := identitySourceExists(ssoclient, d.Get("domain_name").(string))
+	_, err = identitySourceExists(ssoclient, d.Get("domain_name").(string))
 	if err != nil {
 		// check if the domain we are about to create already exists (it should...) and we get no other errors
 		if errors.Is(err, identitynotfound) {
-			return fmt.Errorf("unable to locate the domain which should exist: %s", err)
+			return diag.FromErr(fmt.Errorf("unable to locate the domain which should exist: %s", err))
 		} else {
-			return fmt.Errorf("error getting currently configured ldap identity sources for update: %s", err)
+			return diag.FromErr(fmt.Errorf("error getting currently configured ldap identity sources for update: %s", err))
 		}
 	}
 
+	password, err := secret.Get(d, "ldap_password")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	// vCenter keeps no copy of the previous password that could be restored,
+	// so new credentials are only applied once vCenter has bound to every
+	// LDAP server with them
+	credentialsChanged := d.HasChanges("ldap_username", "ldap_password")
+	if credentialsChanged {
+		if diags := resourceVSphereLDAPIdentitySourceProbeCredentials(ctx, d, ssoclient, password); diags.HasError() {
+			return diags
+		}
+	}
 
-	if d.HasChanges("ldap_username", "ldap_password") {
-		auth := ssoadmin_types.SsoAdminIdentitySourceManagementServiceAuthenticationCredentails {
-			Username: d.Get("ldap_username").(string),
-			Password: d.Get("ldap_password").(string),
+	detailsChanged := d.HasChanges("friendly_name", "user_base_dn", "group_base_dn", "primary_url", "failover_url", "certificates")
+	if detailsChanged {
+		details, err := expandLDAPIdentitySourceDetails(d.Get)
+		if err != nil {
+			return diag.FromErr(err)
 		}
 
-		err = ssoclient.UpdateLdapAuthnType(ctx, d.Get("domain_name").(string), auth)
+		err = identitysource.UpdateLdap(ctx, ssoclient, d.Get("domain_name").(string), details)
+
 		if err != nil {
-			return fmt.Errorf("error updating ldap username or password: %s", err)
+			return diag.FromErr(fmt.Errorf("error updating ldap details such as friendly name: %s", err))
 		}
 	}
 
-	if d.HasChanges("friendly_name", "user_base_dn", "group_base_dn", "primary_url", "secondary_url") {
-		details := ssoadmin_types.LdapIdentitySourceDetails {
-			FriendlyName: d.Get("friendly_name").(string),
-			UserBaseDn: d.Get("user_base_dn").(string),
-			GroupBaseDn: d.Get("group_base_dn").(string),
-			PrimaryURL: d.Get("primary_url").(string),
-			FailoverURL: d.Get("failover_url").(string),
+	diags := resourceVSphereLDAPIdentitySourceVerifyBind(ctx, d, ssoclient, password)
+	if !diags.HasError() && credentialsChanged {
+		auth := ssoadmin_types.SsoAdminIdentitySourceManagementServiceAuthenticationCredentails{
+			Username: d.Get("ldap_username").(string),
+			Password: password,
+		}
+		if err := ssoclient.UpdateLdapAuthnType(ctx, d.Get("domain_name").(string), auth); err != nil {
+			diags = diag.FromErr(fmt.Errorf("error updating ldap username or password: %s", err))
+		}
+	}
+	// vCenter searches for principals with the registered credentials, so the
+	// base DNs are only checked once the new ones are applied
+	if !diags.HasError() {
+		diags = resourceVSphereLDAPIdentitySourceVerifySearch(ctx, d, ssoclient)
+	}
+	if diags.HasError() {
+		// keep the previous values in state, so that the next plan applies
+		// the changes again
+		d.Partial(true)
+		if !detailsChanged {
+			return diags
+		}
+		log.Printf("[DEBUG] Restoring the previous details of %s, as the new ones failed verification", d.Id())
+		details, err := expandLDAPIdentitySourceDetails(func(k string) interface{} {
+			o, _ := d.GetChange(k)
+			return o
+		})
+		if err == nil {
+			err = identitysource.UpdateLdap(ctx, ssoclient, d.Id(), details)
 		}
-
-		err = ssoclient.UpdateLdap(ctx, d.Get("domain_name").(string), details)
-
 		if err != nil {
-			return fmt.Errorf("error updating ldap details such as friendly name: %s", err)
+			return append(diags, diag.FromErr(fmt.Errorf("error restoring the previous details of %s after the new ones failed verification: %s", d.Id(), err))...)
 		}
+		return diags
 	}
 
-	return nil
+	if d.HasChange("default_domain") {
+		if err := ldapIdentitySourceSetDefault(ctx, ssoclient, d.Id(), d.Get("default_domain").(bool)); err != nil {
+			return diag.FromErr(err)
+		}
+	}
+
+	return resourceVSphereLDAPIdentitySourceRead(ctx, d, meta)
 }
 
-func resourceVSphereLDAPIdentitySourceDelete(d *schema.ResourceData, meta interface{}) error {
-	ssoclient := meta.(*Client).ssoClient
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+func resourceVSphereLDAPIdentitySourceDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
-
-	_, err := identitySourceExists(ssoclient, d.Get("domain_name").(string))
+	_, err = identitySourceExists(ssoclient, d.Get("domain_name").(string))
 	if err != nil {
 		// check if the domain we are about to create already exists (it should...) and we get no other errors
 		if errors.Is(err, identitynotfound) {
-			return fmt.Errorf("unable to locate the domain which should exist: %s", err)
+			return diag.FromErr(fmt.Errorf("unable to locate the domain which should exist: %s", err))
 		} else {
-			return fmt.Errorf("error getting currently configured ldap identity sources for update: %s", err)
+			return diag.FromErr(fmt.Errorf("error getting currently configured ldap identity sources for update: %s", err))
 		}
 	}
 
-	a := ssoadmin_types.DeleteDomain {
+	// vCenter cannot delete the default domain, so hand that role back to the
+	// system domain first
+	if err := ldapIdentitySourceSetDefault(ctx, ssoclient, d.Get("domain_name").(string), false); err != nil {
+		return diag.FromErr(err)
+	}
+
+	if err := ldapIdentitySourceDelete(ctx, ssoclient, d.Get("domain_name").(string)); err != nil {
+		return diag.FromErr(err)
+	}
+	return nil
+}
+
+// ldapIdentitySourceDelete unregisters the LDAP identity source for the
+// domain name.
+func ldapIdentitySourceDelete(ctx context.Context, ssoclient *ssoadmin.Client, name string) error {
+	a := ssoadmin_types.DeleteDomain{
 		This: ssoclient.ServiceContent.DomainManagementService,
-		Name: d.Get("domain_name").(string),
+		Name: name,
 	}
 
-	_, err = methods.DeleteDomain(ctx, ssoclient, &a)
+	_, err := methods.DeleteDomain(ctx, ssoclient, &a)
 	if err != nil {
 		return fmt.Errorf("error deleting ldap identity source: %s", err)
 	}
@@ -247,12 +375,15 @@ func resourceVSphereLDAPIdentitySourceDelete(d *schema.ResourceData, meta interf
 
 // NOTE: This import will create the resource within state successfully but the next 'terraform apply' WILL note some changes for it, even if there is nothing actually changing
 // this is due to our inability to fetch the currently configured passwords that LDAP is using and TF will enforce the ones defined in it.
-func resourceVSphereLDAPIdentitySourceImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereLDAPIdentitySourceImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 
-	ssoclient := meta.(*Client).ssoClient
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return nil, err
+	}
 
 	// sanity check that the identity source actually exists in vcenter
-	_, err := identitySourceExists(ssoclient, d.Id())
+	_, err = identitySourceExists(ssoclient, d.Id())
 	// throw error if it does NOT exist or issue getting data via API
 	if err != nil {
 		return nil, fmt.Errorf("Import func - error checking if identity source exists: %s\n", err)
@@ -265,16 +396,33 @@ func resourceVSphereLDAPIdentitySourceImport(d *schema.ResourceData, meta interf
 
 }
 
-
 // this function sanity checks that the domain you are trying to create / update with terraform is not going to create an error when you run a 'terraform apply'
 // - e.g. this function attempts to catch errors in a 'terraform plan'
 func resourceVSphereLDAPIdentitySourceCustomDiff(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	// vCenter only trusts an ldaps:// URL with the certificates of the servers
+	if d.NewValueKnown("certificates") && len(d.Get("certificates").([]interface{})) == 0 {
+		for _, k := range []string{"primary_url", "failover_url"} {
+			if d.NewValueKnown(k) && identitysource.IsLdaps(d.Get(k).(string)) {
+				return fmt.Errorf("certificates are required when %s is an ldaps:// URL", k)
+			}
+		}
+	}
+
+	if d.Get("validate_connectivity").(bool) {
+		if err := resourceVSphereLDAPIdentitySourceProbe(ctx, d, meta); err != nil {
+			return err
+		}
+	}
+
 	// If the LDAP identity source does NOT exist in state yet...
 	if d.Id() == "" {
-		ssoclient := meta.(*Client).ssoClient
+		ssoclient, err := meta.(*Client).SSOClient()
+		if err != nil {
+			return err
+		}
 
 		// check to see if the identitysource exists - this is what alerts you to a possible issue via 'terraform plan' instead of the 'plan' saying all is good and the 'apply' actually failing
-		_, err := identitySourceExists(ssoclient, d.Get("domain_name").(string))
+		_, err = identitySourceExists(ssoclient, d.Get("domain_name").(string))
 
 		if err == nil {
 			return fmt.Errorf("the input domain: %s already exists - considering running a 'terraform import'!", d.Get("domain_name").(string))
@@ -286,3 +434,230 @@ func resourceVSphereLDAPIdentitySourceCustomDiff(ctx context.Context, d *schema.
 
 	return nil
 }
+
+// resourceVSphereLDAPIdentitySourceProbe has vCenter bind to the LDAP servers
+// of a new identity source, or of one whose URLs or credentials change, with
+// the planned credentials. The probe is skipped while any of them is unknown,
+// and is run again when the plan is applied and they are known.
+func resourceVSphereLDAPIdentitySourceProbe(ctx context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	keys := []string{"ldap_username", "ldap_password", "primary_url", "failover_url", "certificates"}
+	if d.Id() != "" && !d.HasChanges(keys...) {
+		return nil
+	}
+	for _, k := range keys {
+		if !d.NewValueKnown(k) {
+			log.Printf("[DEBUG] %s is not known yet, not probing the LDAP servers", k)
+			return nil
+		}
+	}
+
+	password, err := secret.GetFromDiff(d, "ldap_password")
+	if err != nil {
+		return err
+	}
+	var urls []string
+	for _, k := range []string{"primary_url", "failover_url"} {
+		url := d.Get(k).(string)
+		// vCenter only trusts an ldaps:// URL once the certificates of the
+		// identity source are registered
+		if url == "" || (identitysource.IsLdaps(url) && (d.Id() == "" || d.HasChange("certificates"))) {
+			continue
+		}
+		urls = append(urls, url)
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+	username := d.Get("ldap_username").(string)
+	for _, url := range urls {
+		log.Printf("[DEBUG] Probing %s as %s", url, username)
+		if err := identitysource.Probe(ctx, ssoclient, url, username, password); err != nil {
+			return fmt.Errorf("vCenter could not bind to %s as %s: %s", url, username, err)
+		}
+	}
+	return nil
+}
+
+// resourceVSphereLDAPIdentitySourceVerify checks what the probe made while
+// planning could not, once the identity source is registered: that vCenter
+// can bind to the ldaps:// URLs of a new identity source, or of one whose
+// certificates changed, and that it can search for users and groups under new
+// base DNs. A failed check is returned as an error, and the caller rolls back
+// the registration or update.
+func resourceVSphereLDAPIdentitySourceVerify(ctx context.Context, d *schema.ResourceData, ssoclient *ssoadmin.Client, password string) diag.Diagnostics {
+	if diags := resourceVSphereLDAPIdentitySourceVerifyBind(ctx, d, ssoclient, password); diags.HasError() {
+		return diags
+	}
+	return resourceVSphereLDAPIdentitySourceVerifySearch(ctx, d, ssoclient)
+}
+
+// resourceVSphereLDAPIdentitySourceProbeCredentials has vCenter bind to the
+// LDAP servers with new credentials before they are applied, which the probe
+// made while planning does again as the credentials may have changed since.
+// The ldaps:// URLs that vCenter can only trust once new certificates are
+// registered are left to resourceVSphereLDAPIdentitySourceVerifyBind.
+func resourceVSphereLDAPIdentitySourceProbeCredentials(ctx context.Context, d *schema.ResourceData, ssoclient *ssoadmin.Client, password string) diag.Diagnostics {
+	if !d.Get("validate_connectivity").(bool) {
+		return nil
+	}
+	var diags diag.Diagnostics
+	username := d.Get("ldap_username").(string)
+	for _, k := range []string{"primary_url", "failover_url"} {
+		url := d.Get(k).(string)
+		if url == "" || (identitysource.IsLdaps(url) && d.HasChanges(k, "certificates")) {
+			continue
+		}
+		log.Printf("[DEBUG] Probing %s as %s before applying the credentials", url, username)
+		if err := identitysource.Probe(ctx, ssoclient, url, username, password); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("vCenter could not bind to %s as %s, the credentials were not changed", url, username),
+				Detail:   err.Error(),
+			})
+		}
+	}
+	return diags
+}
+
+// resourceVSphereLDAPIdentitySourceVerifyBind checks that vCenter can bind to
+// the ldaps:// URLs of a new identity source, or of one whose certificates
+// changed, with the registered certificates.
+func resourceVSphereLDAPIdentitySourceVerifyBind(ctx context.Context, d *schema.ResourceData, ssoclient *ssoadmin.Client, password string) diag.Diagnostics {
+	if !d.Get("validate_connectivity").(bool) {
+		return nil
+	}
+	var diags diag.Diagnostics
+	username := d.Get("ldap_username").(string)
+	for _, k := range []string{"primary_url", "failover_url"} {
+		url := d.Get(k).(string)
+		if !identitysource.IsLdaps(url) || !(d.IsNewResource() || d.HasChanges(k, "certificates")) {
+			continue
+		}
+		log.Printf("[DEBUG] Probing %s as %s with the registered certificates", url, username)
+		if err := identitysource.Probe(ctx, ssoclient, url, username, password); err != nil {
+			diags = append(diags, diag.Diagnostic{
+				Severity: diag.Error,
+				Summary:  fmt.Sprintf("vCenter could not bind to %s as %s with the registered certificates", url, username),
+				Detail:   err.Error(),
+			})
+		}
+	}
+	return diags
+}
+
+// resourceVSphereLDAPIdentitySourceVerifySearch checks that vCenter can
+// search for users and groups under the base DNs of a new identity source, or
+// under new base DNs, with the registered credentials.
+func resourceVSphereLDAPIdentitySourceVerifySearch(ctx context.Context, d *schema.ResourceData, ssoclient *ssoadmin.Client) diag.Diagnostics {
+	if !d.Get("validate_connectivity").(bool) {
+		return nil
+	}
+	isNew := d.IsNewResource()
+	var diags diag.Diagnostics
+	fail := func(summary string, err error) {
+		diags = append(diags, diag.Diagnostic{
+			Severity: diag.Error,
+			Summary:  summary,
+			Detail:   err.Error(),
+		})
+	}
+
+	criteria := ssoadmin_types.AdminPrincipalDiscoveryServiceSearchCriteria{Domain: d.Id()}
+	if isNew || d.HasChange("user_base_dn") {
+		log.Printf("[DEBUG] Searching for users in %s", d.Id())
+		_, err := methods.FindPersonUsers(ctx, ssoclient, &ssoadmin_types.FindPersonUsers{
+			This:     ssoclient.ServiceContent.PrincipalDiscoveryService,
+			Criteria: criteria,
+			Limit:    1,
+		})
+		if err != nil {
+			fail(fmt.Sprintf("vCenter could not search for users under %s", d.Get("user_base_dn").(string)), err)
+		}
+	}
+	if isNew || d.HasChange("group_base_dn") {
+		log.Printf("[DEBUG] Searching for groups in %s", d.Id())
+		_, err := methods.FindGroups(ctx, ssoclient, &ssoadmin_types.FindGroups{
+			This:     ssoclient.ServiceContent.PrincipalDiscoveryService,
+			Criteria: criteria,
+			Limit:    1,
+		})
+		if err != nil {
+			fail(fmt.Sprintf("vCenter could not search for groups under %s", d.Get("group_base_dn").(string)), err)
+		}
+	}
+	return diags
+}
+
+// expandLDAPIdentitySourceDetails reads the connection details of an LDAP
+// identity source with get, which is the Get of the resource data for the
+// new values.
+func expandLDAPIdentitySourceDetails(get func(string) interface{}) (identitysource.Details, error) {
+	certificates, err := identitysource.EncodeCertificates(structure.SliceInterfacesToStrings(get("certificates").([]interface{})))
+	if err != nil {
+		return identitysource.Details{}, err
+	}
+	return identitysource.Details{
+		FriendlyName: get("friendly_name").(string),
+		UserBaseDn:   get("user_base_dn").(string),
+		GroupBaseDn:  get("group_base_dn").(string),
+		PrimaryURL:   get("primary_url").(string),
+		FailoverURL:  get("failover_url").(string),
+		Certificates: certificates,
+	}, nil
+}
+
+// ldapIdentitySourceAlias returns the alias of the domain of an LDAP identity
+// source, or its name if it has no alias.
+func ldapIdentitySourceAlias(identitySource *ssoadmin_types.LdapIdentitySource) string {
+	for _, domain := range identitySource.Domains {
+		if domain.Name == identitySource.Name && domain.Alias != "" {
+			return domain.Alias
+		}
+	}
+	return identitySource.Name
+}
+
+// ldapIdentitySourceIsDefault returns true if the domain name is the default
+// domain of vCenter SSO.
+func ldapIdentitySourceIsDefault(ctx context.Context, ssoclient *ssoadmin.Client, name string) (bool, error) {
+	domains, err := ssoclient.GetDefaultDomains(ctx)
+	if err != nil {
+		return false, fmt.Errorf("error reading the default domain: %s", err)
+	}
+	for _, domain := range domains {
+		if strings.EqualFold(domain, name) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ldapIdentitySourceSetDefault makes the domain name the default domain of
+// vCenter SSO. If isDefault is false and the domain name is the default, the
+// system domain becomes the default instead.
+func ldapIdentitySourceSetDefault(ctx context.Context, ssoclient *ssoadmin.Client, name string, isDefault bool) error {
+	if isDefault {
+		log.Printf("[DEBUG] Setting %s as the default domain", name)
+		if err := ssoclient.SetDefaultDomains(ctx, name); err != nil {
+			return fmt.Errorf("error setting %s as the default domain: %s", name, err)
+		}
+		return nil
+	}
+
+	current, err := ldapIdentitySourceIsDefault(ctx, ssoclient, name)
+	if err != nil || !current {
+		return err
+	}
+	log.Printf("[DEBUG] Setting the system domain %s as the default domain instead of %s", ssoclient.Domain, name)
+	if err := ssoclient.SetDefaultDomains(ctx, ssoclient.Domain); err != nil {
+		return fmt.Errorf("error setting %s as the default domain: %s", ssoclient.Domain, err)
+	}
+	return nil
+}
@@ -1,13 +1,14 @@
 package vsphere
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
-	"testing"
 	"os"
-	"fmt"
-	"errors"
-	"context"
+	"regexp"
+	"testing"
 )
 
 func TestAccResourceVSphereLdapIdentitySource_basic(t *testing.T) {
@@ -40,13 +41,100 @@ func TestAccResourceVSphereLdapIdentitySource_basic(t *testing.T) {
 			{
 				ResourceName: resource_name,
 				Config:       testAccResourceVSphereLdapIdentitySourceConfig(new_friendly_name),
-				ImportState:       true,
+				ImportState:  true,
 				// because we can't get the passwords from the vcenter API we cannot do ImportStateVerify: true here -- it will always be blank for a 'tf import'
 			},
 		},
 	})
 }
 
+func TestAccResourceVSphereLdapIdentitySource_ldaps(t *testing.T) {
+	resource_name := "vsphere_ldap_identity_source.test"
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccCheckEnvVariables(t, []string{"ldap_username", "ldap_password", "domain_name", "domain_alias", "user_base_dn", "group_base_dn", "ldaps_url", "ldaps_certificate_file"})
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVSphereLdapIdentitySourceDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereLdapIdentitySourceConfigLdaps(false),
+				Check: resource.ComposeTestCheckFunc(
+					testAccVSphereLdapIdentitySourceExists(resource_name),
+					resource.TestCheckResourceAttr(resource_name, "certificates.#", "1"),
+					resource.TestCheckResourceAttr(resource_name, "default_domain", "false"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereLdapIdentitySourceConfigLdaps(true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(resource_name, "default_domain", "true"),
+				),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereLdapIdentitySource_ldapsWithoutCertificates(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "vsphere_ldap_identity_source" "test" {
+  ldap_username = "cn=admin,dc=example,dc=com"
+  ldap_password = "password"
+  domain_name   = "example.com"
+  domain_alias  = "example"
+  server_type   = "OpenLdap"
+  friendly_name = "example.com"
+  user_base_dn  = "ou=users,dc=example,dc=com"
+  group_base_dn = "ou=groups,dc=example,dc=com"
+  primary_url   = "ldaps://ldap01.example.com:636"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("certificates are required when primary_url is an ldaps:// URL"),
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereLdapIdentitySource_probeFailure(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+resource "vsphere_ldap_identity_source" "test" {
+  ldap_username = "cn=admin,dc=example,dc=invalid"
+  ldap_password = "password"
+  domain_name   = "example.invalid"
+  domain_alias  = "example"
+  server_type   = "OpenLdap"
+  friendly_name = "example.invalid"
+  user_base_dn  = "ou=users,dc=example,dc=invalid"
+  group_base_dn = "ou=groups,dc=example,dc=invalid"
+  primary_url   = "ldap://ldap01.example.invalid:389"
+}
+`,
+				PlanOnly:    true,
+				ExpectError: regexp.MustCompile("vCenter could not bind to ldap://ldap01.example.invalid:389"),
+			},
+		},
+	})
+}
+
 func testAccVSphereLdapIdentitySourceDestroy(s *terraform.State) error {
 	found := false
 	for _, rs := range s.RootModule().Resources {
@@ -93,11 +181,11 @@ func testAccResourceVSphereLdapIdentitySourceConfig(friendly_name string) string
 		os.Getenv("ldap_password"),
 		os.Getenv("domain_name"),
 		os.Getenv("domain_alias"),
-                friendly_name,
+		friendly_name,
 		os.Getenv("user_base_dn"),
 		os.Getenv("group_base_dn"),
 		os.Getenv("primary_url"),
- 	)
+	)
 }
 
 func testAccVSphereLdapIdentitySourceExists(name string) resource.TestCheckFunc {
@@ -125,7 +213,7 @@ func testAccVSphereLdapIdentitySourceWithFriendlyName(resource_name string, frie
 	return func(s *terraform.State) error {
 		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
 		defer cancel()
-		
+
 		_, ok := s.RootModule().Resources[resource_name]
 
 		if !ok {
@@ -137,8 +225,8 @@ func testAccVSphereLdapIdentitySourceWithFriendlyName(resource_name string, frie
 		if err != nil {
 			return fmt.Errorf("error fetching identity sources: %s\n", err)
 		}
-		
-                for _, value := range identitysources.LDAPS {
+
+		for _, value := range identitysources.LDAPS {
 			if value.Details.FriendlyName == friendly_name {
 				return nil
 			}
@@ -147,3 +235,31 @@ func testAccVSphereLdapIdentitySourceWithFriendlyName(resource_name string, frie
 		return fmt.Errorf("unable to locate a matching identity source with friendly_name: %s", friendly_name)
 	}
 }
+
+func testAccResourceVSphereLdapIdentitySourceConfigLdaps(defaultDomain bool) string {
+	return fmt.Sprintf(
+		`
+		resource "vsphere_ldap_identity_source" "test" {
+		  ldap_username  = "%s"
+		  ldap_password  = "%s"
+		  domain_name    = "%s"
+		  domain_alias   = "%s"
+		  friendly_name  = "test_friendly_name"
+		  user_base_dn   = "%s"
+		  group_base_dn  = "%s"
+		  primary_url    = "%s"
+		  certificates   = [file("%s")]
+		  default_domain = %t
+		}
+	`,
+		os.Getenv("ldap_username"),
+		os.Getenv("ldap_password"),
+		os.Getenv("domain_name"),
+		os.Getenv("domain_alias"),
+		os.Getenv("user_base_dn"),
+		os.Getenv("group_base_dn"),
+		os.Getenv("ldaps_url"),
+		os.Getenv("ldaps_certificate_file"),
+		defaultDomain,
+	)
+}
@@ -10,6 +10,7 @@ import (
 
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/govmomi/license"
 	"github.com/vmware/govmomi/vim25/methods"
@@ -30,10 +31,13 @@ func resourceVSphereLicense() *schema.Resource {
 
 		SchemaVersion: 1,
 
-		Create: resourceVSphereLicenseCreate,
-		Read:   resourceVSphereLicenseRead,
-		Update: resourceVSphereLicenseUpdate,
-		Delete: resourceVSphereLicenseDelete,
+		CreateContext: resourceVSphereLicenseCreate,
+		ReadContext:   resourceVSphereLicenseRead,
+		UpdateContext: resourceVSphereLicenseUpdate,
+		DeleteContext: resourceVSphereLicenseDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereLicenseImport,
+		},
 
 		Schema: map[string]*schema.Schema{
 			"license_key": {
@@ -68,7 +72,7 @@ func resourceVSphereLicense() *schema.Resource {
 	}
 }
 
-func resourceVSphereLicenseCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLicenseCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Println("[INFO] Running the create method")
 
 	client := meta.(*Client).vimClient
@@ -88,42 +92,42 @@ func resourceVSphereLicenseCreate(d *schema.ResourceData, meta interface{}) erro
 	case "HostAgent":
 		// Labels are not allowed in ESXi
 		if len(labelMap) != 0 {
-			return errors.New("Labels are not allowed in ESXi")
+			return diag.FromErr(errors.New("Labels are not allowed in ESXi"))
 		}
-		info, err = manager.Update(context.TODO(), key, nil)
+		info, err = manager.Update(ctx, key, nil)
 
 	case "VirtualCenter":
-		info, err = manager.Add(context.TODO(), key, nil)
+		info, err = manager.Add(ctx, key, nil)
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
-		err = updateLabels(manager, key, labelMap)
+		err = updateLabels(ctx, manager, key, labelMap)
 
 	default:
-		return fmt.Errorf("unsupported ApiType: %s", t)
+		return diag.FromErr(fmt.Errorf("unsupported ApiType: %s", t))
 	}
 
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if err = DecodeError(info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// This can be used in the read method to set the computed parameters
 	d.SetId(info.LicenseKey)
 
-	return resourceVSphereLicenseRead(d, meta)
+	return resourceVSphereLicenseRead(ctx, d, meta)
 }
 
-func resourceVSphereLicenseRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLicenseRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Println("[INFO] Running the read method")
 
 	client := meta.(*Client).vimClient
 	manager := license.NewManager(client.Client)
 
-	if info := getLicenseInfoFromKey(d.Get("license_key").(string), manager); info != nil {
+	if info := getLicenseInfoFromKey(ctx, d.Get("license_key").(string), manager); info != nil {
 		log.Println("[INFO] Setting the values")
 		_ = d.Set("edition_key", info.EditionKey)
 		_ = d.Set("total", info.Total)
@@ -131,14 +135,14 @@ func resourceVSphereLicenseRead(d *schema.ResourceData, meta interface{}) error
 		_ = d.Set("name", info.Name)
 		_ = d.Set("labels", keyValuesToMap(info.Labels))
 	} else {
-		return ErrNoSuchKeyFound
+		return diag.FromErr(ErrNoSuchKeyFound)
 	}
 
 	return nil
 }
 
 // resourceVSphereLicenseUpdate check for change in labels of the key and updates them.
-func resourceVSphereLicenseUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLicenseUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Println("[INFO] Running the update method")
 
 	client := meta.(*Client).vimClient
@@ -146,26 +150,38 @@ func resourceVSphereLicenseUpdate(d *schema.ResourceData, meta interface{}) erro
 
 	if key, ok := d.GetOk("license_key"); ok {
 		licenseKey := key.(string)
-		if !isKeyPresent(licenseKey, manager) {
-			return ErrNoSuchKeyFound
+		if !isKeyPresent(ctx, licenseKey, manager) {
+			return diag.FromErr(ErrNoSuchKeyFound)
 		}
 
 		if d.HasChange("labels") {
 			labelMap := d.Get("labels").(map[string]interface{})
 
-			err := updateLabels(manager, licenseKey, labelMap)
+			err := updateLabels(ctx, manager, licenseKey, labelMap)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
 
-	return resourceVSphereLicenseRead(d, meta)
+	return resourceVSphereLicenseRead(ctx, d, meta)
 }
 
-func updateLabels(manager *license.Manager, licenseKey string, labelMap map[string]interface{}) error {
+// resourceVSphereLicenseImport imports a license by its key.
+func resourceVSphereLicenseImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*Client).vimClient
+	manager := license.NewManager(client.Client)
+
+	if !isKeyPresent(ctx, d.Id(), manager) {
+		return nil, ErrNoSuchKeyFound
+	}
+	_ = d.Set("license_key", d.Id())
+	return []*schema.ResourceData{d}, nil
+}
+
+func updateLabels(ctx context.Context, manager *license.Manager, licenseKey string, labelMap map[string]interface{}) error {
 	for key, value := range labelMap {
-		err := UpdateLabel(context.TODO(), manager, licenseKey, key, value.(string))
+		err := UpdateLabel(ctx, manager, licenseKey, key, value.(string))
 		if err != nil {
 			return err
 		}
@@ -173,33 +189,33 @@ func updateLabels(manager *license.Manager, licenseKey string, labelMap map[stri
 	return nil
 }
 
-func resourceVSphereLicenseDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereLicenseDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Println("[INFO] Running the delete method")
 
 	client := meta.(*Client).vimClient
 	manager := license.NewManager(client.Client)
 
-	if key := d.Get("license_key").(string); isKeyPresent(key, manager) {
-		err := manager.Remove(context.TODO(), key)
+	if key := d.Get("license_key").(string); isKeyPresent(ctx, key, manager) {
+		err := manager.Remove(ctx, key)
 
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 
 		// if the key is still present
-		if isKeyPresent(key, manager) {
-			return ErrKeyCannotBeDeleted
+		if isKeyPresent(ctx, key, manager) {
+			return diag.FromErr(ErrKeyCannotBeDeleted)
 		}
 		d.SetId("")
 		return nil
 	}
-	return ErrNoSuchKeyFound
+	return diag.FromErr(ErrNoSuchKeyFound)
 }
 
-func getLicenseInfoFromKey(key string, manager *license.Manager) *types.LicenseManagerLicenseInfo {
+func getLicenseInfoFromKey(ctx context.Context, key string, manager *license.Manager) *types.LicenseManagerLicenseInfo {
 	// Use of decode is not returning labels so using list instead
 	// Issue - https://github.com/vmware/govmomi/issues/797
-	infoList, _ := manager.List(context.TODO())
+	infoList, _ := manager.List(ctx)
 	for _, info := range infoList {
 		if info.LicenseKey == key {
 			return &info
@@ -209,8 +225,8 @@ func getLicenseInfoFromKey(key string, manager *license.Manager) *types.LicenseM
 }
 
 // isKeyPresent iterates over the InfoList to check if the license is present or not.
-func isKeyPresent(key string, manager *license.Manager) bool {
-	infoList, _ := manager.List(context.TODO())
+func isKeyPresent(ctx context.Context, key string, manager *license.Manager) bool {
+	infoList, _ := manager.List(ctx)
 
 	for _, info := range infoList {
 		if info.LicenseKey == key {
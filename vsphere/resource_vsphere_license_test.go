@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"os"
@@ -14,6 +15,7 @@ import (
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
 	"github.com/vmware/govmomi/license"
 )
 
@@ -39,6 +41,46 @@ func TestAccResourceVSphereLicense_basic(t *testing.T) {
 					testAccVSphereLicenseExists("vsphere_license.foo"),
 				),
 			},
+			{
+				ResourceName:      "vsphere_license.foo",
+				ImportState:       true,
+				ImportStateId:     os.Getenv("TF_VAR_VSPHERE_LICENSE"),
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereLicense_import(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+			testAccVSpherePreLicenseBasicCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccVSphereLicenseDestroy,
+		Steps: []resource.TestStep{
+			{
+				Config: testhelper.ConfigDataRootDC1(),
+			},
+			{
+				PreConfig: func() {
+					client := testAccProvider.Meta().(*Client).vimClient
+					if _, err := license.NewManager(client.Client).Add(context.Background(), os.Getenv("TF_VAR_VSPHERE_LICENSE"), nil); err != nil {
+						panic(err)
+					}
+				},
+				Config:             testAccVSphereLicenseBasicConfig(),
+				ResourceName:       "vsphere_license.foo",
+				ImportState:        true,
+				ImportStateId:      os.Getenv("TF_VAR_VSPHERE_LICENSE"),
+				ImportStatePersist: true,
+			},
+			{
+				Config:   testAccVSphereLicenseBasicConfig(),
+				PlanOnly: true,
+			},
 		},
 	})
 }
@@ -142,7 +184,7 @@ func testAccVSphereLicenseDestroy(s *terraform.State) error {
 		}
 
 		key := rs.Primary.ID
-		if isKeyPresent(key, manager) {
+		if isKeyPresent(context.TODO(), key, manager) {
 			message += fmt.Sprintf("%s is still present on the server", key)
 		}
 	}
@@ -163,7 +205,7 @@ func testAccVSphereLicenseExists(name string) resource.TestCheckFunc {
 		client := testAccProvider.Meta().(*Client).vimClient
 		manager := license.NewManager(client.Client)
 
-		if !isKeyPresent(rs.Primary.ID, manager) {
+		if !isKeyPresent(context.TODO(), rs.Primary.ID, manager) {
 			return fmt.Errorf("%s key not found on the server", rs.Primary.ID)
 		}
 
@@ -200,11 +242,11 @@ func testAccVSphereLicenseWithLabelExists(name string) resource.TestCheckFunc {
 		client := testAccProvider.Meta().(*Client).vimClient
 		manager := license.NewManager(client.Client)
 
-		if !isKeyPresent(rs.Primary.ID, manager) {
+		if !isKeyPresent(context.TODO(), rs.Primary.ID, manager) {
 			return fmt.Errorf("%s key not found on the server", rs.Primary.ID)
 		}
 
-		info := getLicenseInfoFromKey(rs.Primary.ID, manager)
+		info := getLicenseInfoFromKey(context.TODO(), rs.Primary.ID, manager)
 
 		if len(info.Labels) == 0 {
 			return fmt.Errorf("The labels were not set for the key %s", info.LicenseKey)
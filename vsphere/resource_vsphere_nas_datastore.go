@@ -4,9 +4,13 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/datastore"
@@ -49,40 +53,51 @@ func resourceVSphereNasDatastore() *schema.Resource {
 
 	// Add tags schema
 	s[vSphereTagAttributeKey] = tagsSchema()
+	s[vSphereTagsAllAttributeKey] = tagsAllSchema()
+	s[deletionProtectionAttributeKey] = deletionProtectionSchema()
 	// Add custom attribute schema
 	s[customattribute.ConfigKey] = customattribute.ConfigSchema()
 
 	return &schema.Resource{
-		Create: resourceVSphereNasDatastoreCreate,
-		Read:   resourceVSphereNasDatastoreRead,
-		Update: resourceVSphereNasDatastoreUpdate,
-		Delete: resourceVSphereNasDatastoreDelete,
+		CreateContext: resourceVSphereNasDatastoreCreate,
+		ReadContext:   resourceVSphereNasDatastoreRead,
+		UpdateContext: resourceVSphereNasDatastoreUpdate,
+		DeleteContext: resourceVSphereNasDatastoreDelete,
+		CustomizeDiff: customdiff.Sequence(
+			tagsAllCustomizeDiff,
+			deletionProtectionCustomizeDiff("vsphere_nas_datastore", resourceVSphereNasDatastore),
+		),
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereNasDatastoreImport,
+			StateContext: resourceVSphereNasDatastoreImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereNasDatastoreCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	hosts := structure.SliceInterfacesToStrings(d.Get("host_system_ids").(*schema.Set).List())
 	volSpec, err := expandHostNasVolumeSpec(d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	p := &nasDatastoreMountProcessor{
 		client:   client,
@@ -90,67 +105,70 @@ func resourceVSphereNasDatastoreCreate(d *schema.ResourceData, meta interface{})
 		newHSIDs: hosts,
 		volSpec:  volSpec,
 	}
-	ds, err := p.processMountOperations()
+	ds, err := p.processMountOperations(ctx)
 	if ds != nil {
 		d.SetId(ds.Reference().Value)
 	}
 	if err != nil {
-		return fmt.Errorf("error mounting datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("error mounting datastore: %w", err), nil)
 	}
 
 	// Move the datastore to the correct folder or datastore cluster first, if
 	// specified.
 	f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if !folder.PathIsEmpty(f) {
-		if err := datastore.MoveToFolderRelativeHostSystemID(client, ds, hosts[0], f); err != nil {
-			return fmt.Errorf("error moving datastore to folder: %s", err)
+		if err := datastore.MoveToFolderRelativeHostSystemID(ctx, client, ds, hosts[0], f); err != nil {
+			return errorDiagnostics(fmt.Errorf("error moving datastore to folder: %w", err), nil)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, ds, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Done
-	return resourceVSphereNasDatastoreRead(d, meta)
+	return resourceVSphereNasDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := readDeletionProtection(d, meta); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	id := d.Id()
 	ds, err := datastore.FromID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot find datastore: %w", err), nil)
 	}
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ds, "summary", "info", "host", "customValue")
 	if err != nil {
-		return fmt.Errorf("could not get properties for datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("could not get properties for datastore: %w", err), nil)
 	}
 	if err := flattenDatastoreSummary(d, &props.Summary); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Set the folder
 	if err := resourceVSphereDatastoreReadFolderOrStorageClusterPath(d, ds); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Update NAS spec
 	if err := flattenHostNasVolume(d, props.Info.(*types.NasDatastoreInfo).Nas); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Update mounted hosts
@@ -159,13 +177,13 @@ func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) e
 		mountedHosts = append(mountedHosts, mount.Key.Value)
 	}
 	if err := d.Set("host_system_ids", mountedHosts); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, ds, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, ds, d, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -177,31 +195,31 @@ func resourceVSphereNasDatastoreRead(d *schema.ResourceData, meta interface{}) e
 	return nil
 }
 
-func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	id := d.Id()
 	ds, err := datastore.FromID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot find datastore: %w", err), nil)
 	}
 
 	// Rename this datastore if our name has drifted.
 	if d.HasChange("name") {
-		if err := viapi.RenameObject(client, ds.Reference(), d.Get("name").(string)); err != nil {
-			return err
+		if err := viapi.RenameObject(ctx, client, ds.Reference(), d.Get("name").(string)); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -209,24 +227,24 @@ func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{})
 	if d.HasChange("folder") || d.HasChange("datastore_cluster_id") {
 		f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
-		if err := datastore.MoveToFolder(client, ds, f); err != nil {
-			return fmt.Errorf("could not move datastore to folder %q: %s", f, err)
+		if err := datastore.MoveToFolder(ctx, client, ds, f); err != nil {
+			return errorDiagnostics(fmt.Errorf("could not move datastore to folder %q: %w", f, err), nil)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, ds, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Apply custom attribute updates
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -234,7 +252,7 @@ func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{})
 	o, n := d.GetChange("host_system_ids")
 	volSpec, err := expandHostNasVolumeSpec(d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	p := &nasDatastoreMountProcessor{
 		client:   client,
@@ -244,24 +262,27 @@ func resourceVSphereNasDatastoreUpdate(d *schema.ResourceData, meta interface{})
 		ds:       ds,
 	}
 	// Unmount first
-	if err := p.processUnmountOperations(); err != nil {
-		return fmt.Errorf("error unmounting hosts: %s", err)
+	if err := p.processUnmountOperations(ctx); err != nil {
+		return errorDiagnostics(fmt.Errorf("error unmounting hosts: %w", err), nil)
 	}
 	// Now mount
-	if _, err := p.processMountOperations(); err != nil {
-		return fmt.Errorf("error mounting hosts: %s", err)
+	if _, err := p.processMountOperations(ctx); err != nil {
+		return errorDiagnostics(fmt.Errorf("error mounting hosts: %w", err), nil)
 	}
 
 	// Should be done with the update here.
-	return resourceVSphereNasDatastoreRead(d, meta)
+	return resourceVSphereNasDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereNasDatastoreDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "vsphere_nas_datastore"); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	dsID := d.Id()
 	ds, err := datastore.FromID(client, dsID)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot find datastore: %w", err), nil)
 	}
 
 	// Unmount the datastore from every host. Once the last host is unmounted we
@@ -269,7 +290,7 @@ func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{})
 	hosts := structure.SliceInterfacesToStrings(d.Get("host_system_ids").(*schema.Set).List())
 	volSpec, err := expandHostNasVolumeSpec(d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	p := &nasDatastoreMountProcessor{
 		client:   client,
@@ -278,14 +299,14 @@ func resourceVSphereNasDatastoreDelete(d *schema.ResourceData, meta interface{})
 		volSpec:  volSpec,
 		ds:       ds,
 	}
-	if err := p.processUnmountOperations(); err != nil {
-		return fmt.Errorf("error unmounting hosts: %s", err)
+	if err := p.processUnmountOperations(ctx); err != nil {
+		return errorDiagnostics(fmt.Errorf("error unmounting hosts: %w", err), nil)
 	}
 
 	return nil
 }
 
-func resourceVSphereNasDatastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereNasDatastoreImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We support importing a MoRef - so we need to load the datastore and check
 	// to make sure 1) it exists, and 2) it's a VMFS datastore. If it is, we are
 	// good to go (rest of the stuff will be handled by read on refresh).
@@ -4,9 +4,11 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -120,22 +122,24 @@ func resourceVSphereResourcePool() *schema.Resource {
 			Default:      string(types.ResourceConfigSpecScaleSharesBehaviorDisabled),
 			ValidateFunc: validation.StringInSlice(resourcePoolScaleDescendantsSharesAllowedValues, false),
 		},
-		vSphereTagAttributeKey:    tagsSchema(),
-		customattribute.ConfigKey: customattribute.ConfigSchema(),
+		vSphereTagAttributeKey:     tagsSchema(),
+		vSphereTagsAllAttributeKey: tagsAllSchema(),
+		customattribute.ConfigKey:  customattribute.ConfigSchema(),
 	}
 	return &schema.Resource{
-		Create: resourceVSphereResourcePoolCreate,
-		Read:   resourceVSphereResourcePoolRead,
-		Update: resourceVSphereResourcePoolUpdate,
-		Delete: resourceVSphereResourcePoolDelete,
+		CreateContext: resourceVSphereResourcePoolCreate,
+		ReadContext:   resourceVSphereResourcePoolRead,
+		UpdateContext: resourceVSphereResourcePoolUpdate,
+		DeleteContext: resourceVSphereResourcePoolDelete,
+		CustomizeDiff: tagsAllCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereResourcePoolImport,
+			StateContext: resourceVSphereResourcePoolImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereResourcePoolImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereResourcePoolImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
 		return nil, err
@@ -148,36 +152,36 @@ func resourceVSphereResourcePoolImport(d *schema.ResourceData, meta interface{})
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVSphereResourcePoolCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	prp, err := resourcepool.FromID(client, d.Get("parent_resource_pool_id").(string))
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	version := viapi.ParseVersionFromClient(client)
 	rpSpec := expandResourcePoolConfigSpec(d, version)
 	rp, err := resourcepool.Create(prp, d.Get("name").(string), rpSpec)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err = resourceVSphereResourcePoolApplyTags(d, meta, rp); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	d.SetId(rp.Reference().Value)
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereResourcePoolIDString(d))
-	return resourceVSphereResourcePoolRead(d, meta)
+	return resourceVSphereResourcePoolRead(ctx, d, meta)
 }
 
-func resourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	version := viapi.ParseVersionFromClient(client)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	rp, err := resourcepool.FromID(client, d.Id())
 	if err != nil {
@@ -186,53 +190,53 @@ func resourceVSphereResourcePoolRead(d *schema.ResourceData, meta interface{}) e
 			d.SetId("")
 			return nil
 		}
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	if err = resourceVSphereResourcePoolReadTags(d, meta, rp); err != nil {
-		return err
+	if err = resourceVSphereResourcePoolReadTags(ctx, d, meta, rp); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 	err = d.Set("name", rp.Name())
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	rpProps, err := resourcepool.Properties(rp)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err = d.Set("parent_resource_pool_id", rpProps.Parent.Value); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	err = flattenResourcePoolConfigSpec(d, rpProps.Config, version)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereResourcePoolIDString(d))
 	return nil
 }
 
-func resourceVSphereResourcePoolUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	rp, err := resourcepool.FromID(client, d.Id())
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err = resourceVSphereResourcePoolApplyTags(d, meta, rp); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	op, np := d.GetChange("parent_resource_pool_id")
 	if op != np {
 		log.Printf("[DEBUG] %s: Parent resource pool has changed. Moving from %s, to %s", resourceVSphereResourcePoolIDString(d), op, np)
 		p, err := resourcepool.FromID(client, np.(string))
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		err = resourcepool.MoveIntoResourcePool(p, rp.Reference())
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		log.Printf("[DEBUG] %s: Move finished successfully", resourceVSphereResourcePoolIDString(d))
 	}
@@ -240,29 +244,29 @@ func resourceVSphereResourcePoolUpdate(d *schema.ResourceData, meta interface{})
 	rpSpec := expandResourcePoolConfigSpec(d, version)
 	err = resourcepool.Update(rp, d.Get("name").(string), rpSpec)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereResourcePoolIDString(d))
 	return nil
 }
 
-func resourceVSphereResourcePoolDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereResourcePoolDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereResourcePoolIDString(d))
 	client, err := resourceVSphereResourcePoolClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	rp, err := resourcepool.FromID(client, d.Id())
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	err = resourceVSphereResourcePoolValidateEmpty(rp)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	err = resourcepool.Delete(rp)
+	err = resourcepool.Delete(ctx, rp)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereResourcePoolIDString(d))
 	return nil
@@ -377,15 +381,15 @@ func resourceVSphereResourcePoolApplyTags(d *schema.ResourceData, meta interface
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereResourcePoolIDString(d))
-	return processTagDiff(tagsClient, d, rp)
+	return processTagDiff(tagsClient, d, rp, meta)
 }
 
 // resourceVSphereResourcePoolReadTags reads the tags for
 // vsphere_resource_pool.
-func resourceVSphereResourcePoolReadTags(d *schema.ResourceData, meta interface{}, rp *object.ResourcePool) error {
+func resourceVSphereResourcePoolReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, rp *object.ResourcePool) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereResourcePoolIDString(d))
-		if err := readTagsForResource(tagsClient, rp, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, rp, d, meta); err != nil {
 			return err
 		}
 	} else {
@@ -11,6 +11,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
 	"github.com/vmware/govmomi/object"
@@ -40,18 +41,18 @@ func resourceVsphereRole() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceRoleCreate,
-		Read:   resourceRoleRead,
-		Update: resourceRoleUpdate,
-		Delete: resourceRoleDelete,
-		Schema: sch,
+		CreateContext: resourceRoleCreate,
+		ReadContext:   resourceRoleRead,
+		UpdateContext: resourceRoleUpdate,
+		DeleteContext: resourceRoleDelete,
+		Schema:        sch,
 		Importer: &schema.ResourceImporter{
-			State: resourceRoleImport,
+			StateContext: resourceRoleImport,
 		},
 	}
 }
 
-func resourceRoleCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceRoleCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning create role %s", d.Get("name").(string))
 	client := meta.(*Client).vimClient
 
@@ -60,20 +61,20 @@ func resourceRoleCreate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	rolePrivileges := structure.SliceInterfacesToStrings(d.Get("role_privileges").([]interface{}))
 
-	roleID, err := authorizationManager.AddRole(context.Background(), name, rolePrivileges)
+	roleID, err := authorizationManager.AddRole(ctx, name, rolePrivileges)
 	if err != nil {
-		return fmt.Errorf("error while creating role with name %s %s", name, err)
+		return diag.FromErr(fmt.Errorf("error while creating role with name %s %s", name, err))
 	}
 
 	d.SetId(strconv.Itoa(int(roleID)))
-	return resourceRoleRead(d, meta)
+	return resourceRoleRead(ctx, d, meta)
 }
 
-func resourceRoleRead(d *schema.ResourceData, meta interface{}) error {
-	return roleById(d, false, meta)
+func resourceRoleRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	return diag.FromErr(roleById(d, false, meta))
 }
 
-func resourceRoleImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceRoleImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	err := roleById(d, true, meta)
 	if err != nil {
 		return nil, err
@@ -122,7 +123,7 @@ func roleById(d *schema.ResourceData, excludeSystem bool, meta interface{}) erro
 	return nil
 }
 
-func resourceRoleUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceRoleUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning update role %s", d.Get("name").(string))
 	client := meta.(*Client).vimClient
 
@@ -130,31 +131,31 @@ func resourceRoleUpdate(d *schema.ResourceData, meta interface{}) error {
 	name := d.Get("name").(string)
 	roleIDInt, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err))
 	}
 	roleID := int32(roleIDInt)
 	rolePrivileges := structure.SliceInterfacesToStrings(d.Get("role_privileges").([]interface{}))
 
-	err = authorizationManager.UpdateRole(context.Background(), roleID, name, rolePrivileges)
+	err = authorizationManager.UpdateRole(ctx, roleID, name, rolePrivileges)
 	if err != nil {
-		return fmt.Errorf("error while updating role with is %d %s", roleIDInt, err)
+		return diag.FromErr(fmt.Errorf("error while updating role with is %d %s", roleIDInt, err))
 	}
-	return resourceRoleRead(d, meta)
+	return resourceRoleRead(ctx, d, meta)
 }
 
-func resourceRoleDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceRoleDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Performing Delete of Role with ID %s", d.Id())
 	client := meta.(*Client).vimClient
 	authorizationManager := object.NewAuthorizationManager(client.Client)
 
 	roleIDInt, err := strconv.ParseInt(d.Id(), 10, 32)
 	if err != nil {
-		return fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("error while coverting role id %s from string to int %s", d.Id(), err))
 	}
 	roleID := int32(roleIDInt)
-	err = authorizationManager.RemoveRole(context.Background(), roleID, true)
+	err = authorizationManager.RemoveRole(ctx, roleID, true)
 	if err != nil {
-		return fmt.Errorf("error while deleting role with id %d %s", roleIDInt, err)
+		return diag.FromErr(fmt.Errorf("error while deleting role with id %d %s", roleIDInt, err))
 	}
 
 	d.SetId("")
@@ -0,0 +1,153 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+func resourceVSphereSSOGroup() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereSSOGroupCreate,
+		ReadContext:   resourceVSphereSSOGroupRead,
+		UpdateContext: resourceVSphereSSOGroupUpdate,
+		DeleteContext: resourceVSphereSSOGroupDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereSSOGroupImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the group, without the domain.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The system domain of the group, such as vsphere.local.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the group.",
+			},
+		},
+	}
+}
+
+func resourceVSphereSSOGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	log.Printf("[DEBUG] Creating SSO group %s@%s", name, ssoclient.Domain)
+	if err := ssoclient.CreateGroup(ctx, name, expandSSOGroupDetails(d)); err != nil {
+		return diag.FromErr(fmt.Errorf("error creating SSO group %s: %s", name, err))
+	}
+	d.SetId(ssoPrincipalIDString(ssoadmin_types.PrincipalId{
+		Name:   name,
+		Domain: ssoclient.Domain,
+	}))
+
+	return resourceVSphereSSOGroupRead(ctx, d, meta)
+}
+
+func resourceVSphereSSOGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	group, err := ssoclient.FindGroup(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading SSO group %s: %s", d.Id(), err))
+	}
+	if group == nil {
+		log.Printf("[DEBUG] SSO group %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("name", group.Id.Name)
+	_ = d.Set("domain", group.Id.Domain)
+	_ = d.Set("description", group.Details.Description)
+
+	return nil
+}
+
+func resourceVSphereSSOGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	if err := ssoclient.UpdateGroup(ctx, d.Get("name").(string), expandSSOGroupDetails(d)); err != nil {
+		return diag.FromErr(fmt.Errorf("error updating SSO group %s: %s", d.Id(), err))
+	}
+
+	return resourceVSphereSSOGroupRead(ctx, d, meta)
+}
+
+func resourceVSphereSSOGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	if err := ssoclient.DeletePrincipal(ctx, d.Get("name").(string)); err != nil {
+		return diag.FromErr(fmt.Errorf("error deleting SSO group %s: %s", d.Id(), err))
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereSSOGroupImport imports a local SSO group by name@domain, or
+// by name in the system domain.
+func resourceVSphereSSOGroupImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return nil, err
+	}
+	principal, err := ssoLocalPrincipalID(ssoclient, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(ssoPrincipalIDString(principal))
+
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+	group, err := ssoclient.FindGroup(ctx, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("error reading SSO group %s: %s", d.Id(), err)
+	}
+	if group == nil {
+		return nil, fmt.Errorf("SSO group %s not found", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandSSOGroupDetails reads the details of an SSO group from the resource
+// data.
+func expandSSOGroupDetails(d *schema.ResourceData) ssoadmin_types.AdminGroupDetails {
+	return ssoadmin_types.AdminGroupDetails{
+		Description: d.Get("description").(string),
+	}
+}
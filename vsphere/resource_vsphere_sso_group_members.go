@@ -0,0 +1,340 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/vmware/govmomi/ssoadmin"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+// ssoGroupMemberKeys are the attributes of vsphere_sso_group_members that
+// hold members, one for each kind of principal.
+var ssoGroupMemberKeys = []string{"users", "groups", "solution_users"}
+
+func resourceVSphereSSOGroupMembers() *schema.Resource {
+	memberSchema := func(description string) *schema.Schema {
+		return &schema.Schema{
+			Type:        schema.TypeSet,
+			Optional:    true,
+			Description: description,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.StringMatch(regexp.MustCompile(`^[^@]+@[^@]+$`), "must be in the form name@domain"),
+			},
+		}
+	}
+
+	return &schema.Resource{
+		CreateContext: resourceVSphereSSOGroupMembersCreate,
+		ReadContext:   resourceVSphereSSOGroupMembersRead,
+		UpdateContext: resourceVSphereSSOGroupMembersUpdate,
+		DeleteContext: resourceVSphereSSOGroupMembersDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereSSOGroupMembersImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"group": {
+				Type:             schema.TypeString,
+				Required:         true,
+				ForceNew:         true,
+				Description:      "The name of the local SSO group, such as Administrators or Administrators@vsphere.local.",
+				DiffSuppressFunc: ssoGroupNameDiffSuppress,
+			},
+			"users":          memberSchema("The users that are members of the group, in the form name@domain. Users can be from any identity source."),
+			"groups":         memberSchema("The groups that are members of the group, in the form name@domain. Groups can be from any identity source."),
+			"solution_users": memberSchema("The solution users that are members of the group, in the form name@domain."),
+			"additive": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Only manage the configured members, and leave other members of the group in place. By default, members that are not configured are removed from the group.",
+			},
+		},
+	}
+}
+
+func resourceVSphereSSOGroupMembersCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	group, err := ssoLocalPrincipalID(ssoclient, d.Get("group").(string))
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	id := ssoPrincipalIDString(group)
+	found, err := ssoclient.FindGroup(ctx, id)
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading SSO group %s: %s", id, err))
+	}
+	if found == nil {
+		return diag.FromErr(fmt.Errorf("SSO group %s not found", id))
+	}
+	d.SetId(id)
+
+	diags := resourceVSphereSSOGroupMembersApply(ctx, d, meta.(*Client), ssoclient)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceVSphereSSOGroupMembersRead(ctx, d, meta)...)
+}
+
+func resourceVSphereSSOGroupMembersRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	group, err := ssoclient.FindGroup(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading SSO group %s: %s", d.Id(), err))
+	}
+	if group == nil {
+		log.Printf("[DEBUG] SSO group %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	members, err := resourceVSphereSSOGroupMembersFetch(ctx, ssoclient, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	self, err := ssoSessionPrincipal(ctx, meta.(*Client), ssoclient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	additive := d.Get("additive").(bool)
+	for _, key := range ssoGroupMemberKeys {
+		if !resourceVSphereSSOGroupMembersManaged(d, key) {
+			continue
+		}
+		configured := structure.SliceInterfacesToStrings(d.Get(key).(*schema.Set).List())
+		if err := d.Set(key, ssoMembersForState(members[key], configured, self, additive)); err != nil {
+			return diag.FromErr(fmt.Errorf("error setting %s: %s", key, err))
+		}
+	}
+
+	return nil
+}
+
+func resourceVSphereSSOGroupMembersUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	diags := resourceVSphereSSOGroupMembersApply(ctx, d, meta.(*Client), ssoclient)
+	if diags.HasError() {
+		return diags
+	}
+	return append(diags, resourceVSphereSSOGroupMembersRead(ctx, d, meta)...)
+}
+
+// resourceVSphereSSOGroupMembersDelete removes the members in state from the
+// group. The group itself is left in place.
+func resourceVSphereSSOGroupMembersDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	members, err := resourceVSphereSSOGroupMembersFetch(ctx, ssoclient, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	var remove []string
+	for _, key := range ssoGroupMemberKeys {
+		managed := structure.SliceInterfacesToStrings(d.Get(key).(*schema.Set).List())
+		remove = append(remove, ssoMemberIntersect(members[key], managed)...)
+	}
+	diags := ssoRemoveGroupMembers(ctx, meta.(*Client), ssoclient, d.Id(), remove)
+	if diags.HasError() {
+		return diags
+	}
+	d.SetId("")
+	return diags
+}
+
+// resourceVSphereSSOGroupMembersImport imports the members of a local SSO
+// group by name@domain, or by name in the system domain. An imported
+// resource manages all members of the group.
+func resourceVSphereSSOGroupMembersImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	group, err := ssoLocalPrincipalID(ssoclient, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(ssoPrincipalIDString(group))
+	_ = d.Set("group", d.Id())
+	_ = d.Set("additive", false)
+
+	// Every kind of member is set, even if empty, so that Read manages them
+	// all.
+	members, err := resourceVSphereSSOGroupMembersFetch(ctx, ssoclient, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	self, err := ssoSessionPrincipal(ctx, meta.(*Client), ssoclient)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range ssoGroupMemberKeys {
+		if err := d.Set(key, ssoMembersForState(members[key], nil, self, false)); err != nil {
+			return nil, fmt.Errorf("error setting %s: %s", key, err)
+		}
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// resourceVSphereSSOGroupMembersManaged returns true if the resource manages
+// the kind of member held by key. A kind is managed if it is set in the
+// configuration, even to an empty list. On refresh, when the configuration is
+// not known, a kind is managed if it is set in state. Members of a kind that
+// is not managed are never removed from the group.
+func resourceVSphereSSOGroupMembersManaged(d *schema.ResourceData, key string) bool {
+	if raw := d.GetRawConfig(); !raw.IsNull() {
+		return !raw.GetAttr(key).IsNull()
+	}
+	raw := d.GetRawState()
+	return !raw.IsNull() && !raw.GetAttr(key).IsNull()
+}
+
+// ssoGroupNameDiffSuppress suppresses the difference between a group name
+// with and without the system domain, such as Administrators and
+// Administrators@vsphere.local.
+func ssoGroupNameDiffSuppress(_, old, new string, _ *schema.ResourceData) bool {
+	if strings.EqualFold(old, new) {
+		return true
+	}
+	oldName, oldDomain, _ := strings.Cut(old, "@")
+	newName, newDomain, _ := strings.Cut(new, "@")
+	return old != "" && strings.EqualFold(oldName, newName) && (oldDomain == "" || newDomain == "")
+}
+
+// resourceVSphereSSOGroupMembersFetch returns the members of the group with
+// the given ID, by attribute of vsphere_sso_group_members.
+func resourceVSphereSSOGroupMembersFetch(ctx context.Context, ssoclient *ssoadmin.Client, id string) (map[string][]string, error) {
+	users, groups, solutionUsers, err := ssoGroupMembers(ctx, ssoclient, id)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]string{
+		"users":          users,
+		"groups":         groups,
+		"solution_users": solutionUsers,
+	}, nil
+}
+
+// resourceVSphereSSOGroupMembersApply adds the configured members that are
+// missing from the group, and removes the members of each configured kind
+// that are not in the configuration. In additive mode, only members that
+// were removed from the configuration are removed from the group.
+func resourceVSphereSSOGroupMembersApply(ctx context.Context, d *schema.ResourceData, client *Client, ssoclient *ssoadmin.Client) diag.Diagnostics {
+	members, err := resourceVSphereSSOGroupMembersFetch(ctx, ssoclient, d.Id())
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	group := ssoPrincipalID(ssoclient, d.Id())
+	additive := d.Get("additive").(bool)
+
+	var remove []string
+	for _, key := range ssoGroupMemberKeys {
+		o, n := d.GetChange(key)
+		previous := structure.SliceInterfacesToStrings(o.(*schema.Set).List())
+		desired := structure.SliceInterfacesToStrings(n.(*schema.Set).List())
+
+		if add := ssoMemberSubtract(desired, members[key]); len(add) > 0 {
+			ids := make([]ssoadmin_types.PrincipalId, 0, len(add))
+			for _, id := range add {
+				ids = append(ids, ssoPrincipalID(ssoclient, id))
+			}
+			log.Printf("[DEBUG] Adding %v to SSO group %s", add, d.Id())
+			if key == "groups" {
+				err = ssoclient.AddGroupsToGroup(ctx, group.Name, ids...)
+			} else {
+				err = ssoclient.AddUsersToGroup(ctx, group.Name, ids...)
+			}
+			if err != nil {
+				return diag.FromErr(fmt.Errorf("error adding %v to SSO group %s: %s", add, d.Id(), err))
+			}
+		}
+
+		switch {
+		case additive:
+			remove = append(remove, ssoMemberIntersect(members[key], ssoMemberSubtract(previous, desired))...)
+		case resourceVSphereSSOGroupMembersManaged(d, key):
+			remove = append(remove, ssoMemberSubtract(members[key], desired)...)
+		}
+	}
+
+	return ssoRemoveGroupMembers(ctx, client, ssoclient, d.Id(), remove)
+}
+
+// ssoRemoveGroupMembers removes the principals with the given IDs from the
+// SSO group with the ID group. The user that the provider is logged in as is
+// never removed, so that it cannot lock itself out of the group, and a warning
+// is returned instead.
+func ssoRemoveGroupMembers(ctx context.Context, client *Client, ssoclient *ssoadmin.Client, group string, remove []string) diag.Diagnostics {
+	if len(remove) == 0 {
+		return nil
+	}
+	self, err := ssoSessionPrincipal(ctx, client, ssoclient)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	remove, diags := ssoRemovableMembers(remove, self, group)
+	if len(remove) == 0 {
+		return diags
+	}
+	ids := make([]ssoadmin_types.PrincipalId, 0, len(remove))
+	for _, id := range remove {
+		ids = append(ids, ssoPrincipalID(ssoclient, id))
+	}
+	log.Printf("[DEBUG] Removing %v from SSO group %s", remove, group)
+	if err := ssoclient.RemoveUsersFromGroup(ctx, ssoPrincipalID(ssoclient, group).Name, ids...); err != nil {
+		return append(diags, diag.FromErr(fmt.Errorf("error removing %v from SSO group %s: %s", remove, group, err))...)
+	}
+	return diags
+}
+
+// ssoRemovableMembers returns the principal IDs in remove other than self, the
+// user that the provider is logged in as. If self is in remove, a warning is
+// returned that it was left in the group.
+func ssoRemovableMembers(remove []string, self, group string) ([]string, diag.Diagnostics) {
+	if !ssoMemberContains(remove, self) {
+		return remove, nil
+	}
+	return ssoMemberSubtract(remove, []string{self}), diag.Diagnostics{
+		{
+			Severity: diag.Warning,
+			Summary:  fmt.Sprintf("%s was not removed from SSO group %s", self, group),
+			Detail:   "This is the user that the provider is logged in as, and removing it could lock Terraform out of the group. Remove it outside of Terraform if required.",
+		},
+	}
+}
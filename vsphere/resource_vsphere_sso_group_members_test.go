@@ -0,0 +1,116 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourceVSphereSSOGroupMembersName = "vsphere_sso_group_members.members"
+
+func TestAccResourceVSphereSSOGroupMembers_basic(t *testing.T) {
+	testAccSkipIfSimulator(t, "vcsim does not implement FindUsersInGroup")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers: testAccProviders,
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereSSOGroupMembersConfig(false, "vsphere_sso_user.one.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOGroupMembersCount(1),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOGroupMembersName, "users.#", "1"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereSSOGroupMembersConfig(false, "vsphere_sso_user.one.id", "vsphere_sso_user.two.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOGroupMembersCount(2),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOGroupMembersName, "users.#", "2"),
+				),
+			},
+			{
+				ResourceName:            testAccResourceVSphereSSOGroupMembersName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"group"},
+			},
+			{
+				// An additive resource leaves the member that it no longer
+				// manages in the group.
+				Config: testAccResourceVSphereSSOGroupMembersConfig(true, "vsphere_sso_user.two.id"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOGroupMembersCount(1),
+				),
+			},
+		},
+	})
+}
+
+func TestSSOGroupNameDiffSuppress(t *testing.T) {
+	cases := []struct {
+		old, new string
+		expected bool
+	}{
+		{"Administrators@vsphere.local", "Administrators", true},
+		{"Administrators", "administrators@VSPHERE.LOCAL", true},
+		{"Administrators@vsphere.local", "Administrators@example.com", false},
+		{"Administrators@vsphere.local", "Operators", false},
+		{"", "Administrators", false},
+	}
+	for _, tc := range cases {
+		if got := ssoGroupNameDiffSuppress("group", tc.old, tc.new, nil); got != tc.expected {
+			t.Errorf("%q -> %q: expected %t, got %t", tc.old, tc.new, tc.expected, got)
+		}
+	}
+}
+
+func testAccResourceVSphereSSOGroupMembersCount(expected int) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ssoclient, err := testAccProvider.Meta().(*Client).SSOClient()
+		if err != nil {
+			return err
+		}
+		users, err := ssoclient.FindUsersInGroup(context.Background(), "terraform-test-group", "")
+		if err != nil {
+			return err
+		}
+		if len(users) != expected {
+			return fmt.Errorf("expected %d members in terraform-test-group, got %d", expected, len(users))
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereSSOGroupMembersConfig(additive bool, users ...string) string {
+	return fmt.Sprintf(`
+resource "vsphere_sso_group" "group" {
+  name = "terraform-test-group"
+}
+
+resource "vsphere_sso_user" "one" {
+  name     = "terraform-test-user-1"
+  password = "Terraform-1!"
+}
+
+resource "vsphere_sso_user" "two" {
+  name     = "terraform-test-user-2"
+  password = "Terraform-1!"
+}
+
+resource "vsphere_sso_group_members" "members" {
+  group    = vsphere_sso_group.group.name
+  users    = [%s]
+  additive = %t
+}
+`, strings.Join(users, ", "), additive)
+}
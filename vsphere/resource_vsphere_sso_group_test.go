@@ -0,0 +1,76 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+)
+
+const testAccResourceVSphereSSOGroupName = "vsphere_sso_group.group"
+
+func TestAccResourceVSphereSSOGroup_basic(t *testing.T) {
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereSSOGroupExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereSSOGroupConfig("Break-glass administrators"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOGroupExists(true),
+					resource.TestCheckResourceAttrSet(testAccResourceVSphereSSOGroupName, "domain"),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOGroupName, "description", "Break-glass administrators"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereSSOGroupConfig("Service accounts"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOGroupName, "description", "Service accounts"),
+				),
+			},
+			{
+				ResourceName:      testAccResourceVSphereSSOGroupName,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereSSOGroupExists(expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ssoclient, err := testAccProvider.Meta().(*Client).SSOClient()
+		if err != nil {
+			return err
+		}
+		group, err := ssoclient.FindGroup(context.Background(), "terraform-test-group")
+		if err != nil {
+			return err
+		}
+		switch {
+		case group == nil && expected:
+			return fmt.Errorf("SSO group terraform-test-group not found")
+		case group != nil && !expected:
+			return fmt.Errorf("SSO group terraform-test-group still exists")
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereSSOGroupConfig(description string) string {
+	return fmt.Sprintf(`
+resource "vsphere_sso_group" "group" {
+  name        = "terraform-test-group"
+  description = "%s"
+}
+`, description)
+}
@@ -0,0 +1,230 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+func resourceVSphereSSOUser() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceVSphereSSOUserCreate,
+		ReadContext:   resourceVSphereSSOUserRead,
+		UpdateContext: resourceVSphereSSOUserUpdate,
+		DeleteContext: resourceVSphereSSOUserDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVSphereSSOUserImport,
+		},
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "The name of the user, without the domain.",
+			},
+			"domain": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The system domain of the user, such as vsphere.local.",
+			},
+			"password": {
+				Type:             schema.TypeString,
+				Required:         true,
+				Sensitive:        true,
+				StateFunc:        secret.StateFunc,
+				DiffSuppressFunc: secret.DiffSuppressFunc,
+				Description:      "The password of the user, or file:// followed by the path of a local file that contains it. A change resets the password. Only a fingerprint of the password is saved in state.",
+			},
+			"first_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The first name of the user.",
+			},
+			"last_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The last name of the user.",
+			},
+			"email_address": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The email address of the user.",
+			},
+			"description": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The description of the user.",
+			},
+			"disabled": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Disable the user, so that it cannot log in.",
+			},
+			"locked": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Whether the user is locked out after too many failed logins.",
+			},
+		},
+	}
+}
+
+func resourceVSphereSSOUserCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	password, err := secret.Get(d, "password")
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	log.Printf("[DEBUG] Creating SSO user %s@%s", name, ssoclient.Domain)
+	if err := ssoclient.CreatePersonUser(ctx, name, expandSSOPersonDetails(d), password); err != nil {
+		return diag.FromErr(fmt.Errorf("error creating SSO user %s: %s", name, err))
+	}
+	principal := ssoadmin_types.PrincipalId{
+		Name:   name,
+		Domain: ssoclient.Domain,
+	}
+	d.SetId(ssoPrincipalIDString(principal))
+
+	if d.Get("disabled").(bool) {
+		if err := ssoSetUserDisabled(ctx, ssoclient, principal, true); err != nil {
+			return diag.FromErr(fmt.Errorf("error disabling SSO user %s: %s", d.Id(), err))
+		}
+	}
+
+	return resourceVSphereSSOUserRead(ctx, d, meta)
+}
+
+func resourceVSphereSSOUserRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	user, err := ssoclient.FindPersonUser(ctx, d.Id())
+	if err != nil {
+		return diag.FromErr(fmt.Errorf("error reading SSO user %s: %s", d.Id(), err))
+	}
+	if user == nil {
+		log.Printf("[DEBUG] SSO user %s not found, removing from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+
+	_ = d.Set("name", user.Id.Name)
+	_ = d.Set("domain", user.Id.Domain)
+	_ = d.Set("password", secret.Refingerprint(d, "password", d.Get("password").(string)))
+	_ = d.Set("first_name", user.Details.FirstName)
+	_ = d.Set("last_name", user.Details.LastName)
+	_ = d.Set("email_address", user.Details.EmailAddress)
+	_ = d.Set("description", user.Details.Description)
+	_ = d.Set("disabled", user.Disabled)
+	_ = d.Set("locked", user.Locked)
+
+	return nil
+}
+
+func resourceVSphereSSOUserUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	name := d.Get("name").(string)
+	if d.HasChanges("first_name", "last_name", "email_address", "description") {
+		if err := ssoclient.UpdatePersonUser(ctx, name, expandSSOPersonDetails(d)); err != nil {
+			return diag.FromErr(fmt.Errorf("error updating SSO user %s: %s", d.Id(), err))
+		}
+	}
+
+	if d.HasChange("password") {
+		password, err := secret.Get(d, "password")
+		if err != nil {
+			return diag.FromErr(err)
+		}
+		if err := ssoclient.ResetPersonPassword(ctx, name, password); err != nil {
+			return diag.FromErr(fmt.Errorf("error resetting password of SSO user %s: %s", d.Id(), err))
+		}
+	}
+
+	if d.HasChange("disabled") {
+		disabled := d.Get("disabled").(bool)
+		if err := ssoSetUserDisabled(ctx, ssoclient, ssoPrincipalID(ssoclient, d.Id()), disabled); err != nil {
+			return diag.FromErr(fmt.Errorf("error setting disabled to %t on SSO user %s: %s", disabled, d.Id(), err))
+		}
+	}
+
+	return resourceVSphereSSOUserRead(ctx, d, meta)
+}
+
+func resourceVSphereSSOUserDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+
+	if err := ssoclient.DeletePrincipal(ctx, d.Get("name").(string)); err != nil {
+		return diag.FromErr(fmt.Errorf("error deleting SSO user %s: %s", d.Id(), err))
+	}
+	d.SetId("")
+	return nil
+}
+
+// resourceVSphereSSOUserImport imports a local SSO user by name@domain, or by
+// name in the system domain. The password cannot be read back, so the next
+// plan resets it to the configured one.
+func resourceVSphereSSOUserImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	ssoclient, err := meta.(*Client).SSOClient()
+	if err != nil {
+		return nil, err
+	}
+	principal, err := ssoLocalPrincipalID(ssoclient, d.Id())
+	if err != nil {
+		return nil, err
+	}
+	d.SetId(ssoPrincipalIDString(principal))
+
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
+	defer cancel()
+	user, err := ssoclient.FindPersonUser(ctx, d.Id())
+	if err != nil {
+		return nil, fmt.Errorf("error reading SSO user %s: %s", d.Id(), err)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("SSO user %s not found", d.Id())
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// expandSSOPersonDetails reads the details of an SSO user from the resource
+// data.
+func expandSSOPersonDetails(d *schema.ResourceData) ssoadmin_types.AdminPersonDetails {
+	return ssoadmin_types.AdminPersonDetails{
+		FirstName:    d.Get("first_name").(string),
+		LastName:     d.Get("last_name").(string),
+		EmailAddress: d.Get("email_address").(string),
+		Description:  d.Get("description").(string),
+	}
+}
@@ -0,0 +1,128 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/secret"
+)
+
+const testAccResourceVSphereSSOUserName = "vsphere_sso_user.user"
+
+func TestAccResourceVSphereSSOUser_basic(t *testing.T) {
+	passwordFile := filepath.Join(t.TempDir(), "password")
+	if err := os.WriteFile(passwordFile, []byte("Terraform-2!\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereSSOUserExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereSSOUserConfig("Terraform-1!", "Test"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOUserExists(true),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "password", secret.Fingerprint("Terraform-1!")),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "first_name", "Test"),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "disabled", "false"),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "locked", "false"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereSSOUserConfig("file://"+passwordFile, "Updated"),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOUserExists(true),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "password", secret.Fingerprint("Terraform-2!")),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "first_name", "Updated"),
+				),
+			},
+			{
+				ResourceName:            testAccResourceVSphereSSOUserName,
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"password"},
+			},
+		},
+	})
+}
+
+func TestAccResourceVSphereSSOUser_disabled(t *testing.T) {
+	testAccSkipIfSimulator(t, "vcsim does not implement DisableUserAccount")
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVSphereSSOUserExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereSSOUserConfigDisabled(true),
+				Check: resource.ComposeTestCheckFunc(
+					testAccResourceVSphereSSOUserExists(true),
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "disabled", "true"),
+				),
+			},
+			{
+				Config: testAccResourceVSphereSSOUserConfigDisabled(false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(testAccResourceVSphereSSOUserName, "disabled", "false"),
+				),
+			},
+		},
+	})
+}
+
+func testAccResourceVSphereSSOUserExists(expected bool) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		ssoclient, err := testAccProvider.Meta().(*Client).SSOClient()
+		if err != nil {
+			return err
+		}
+		user, err := ssoclient.FindPersonUser(context.Background(), "terraform-test-user")
+		if err != nil {
+			return err
+		}
+		switch {
+		case user == nil && expected:
+			return fmt.Errorf("SSO user terraform-test-user not found")
+		case user != nil && !expected:
+			return fmt.Errorf("SSO user terraform-test-user still exists")
+		}
+		return nil
+	}
+}
+
+func testAccResourceVSphereSSOUserConfig(password, firstName string) string {
+	return fmt.Sprintf(`
+resource "vsphere_sso_user" "user" {
+  name       = "terraform-test-user"
+  password   = "%s"
+  first_name = "%s"
+  last_name  = "User"
+}
+`, password, firstName)
+}
+
+func testAccResourceVSphereSSOUserConfigDisabled(disabled bool) string {
+	return fmt.Sprintf(`
+resource "vsphere_sso_user" "user" {
+  name     = "terraform-test-user"
+  password = "Terraform-1!"
+  disabled = %t
+}
+`, disabled)
+}
@@ -4,12 +4,14 @@
 package vsphere
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/storagepod"
@@ -25,12 +27,12 @@ const resourceVSphereStorageDrsVMOverrideName = "vsphere_storage_drs_vm_override
 
 func resourceVSphereStorageDrsVMOverride() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereStorageDrsVMOverrideCreate,
-		Read:   resourceVSphereStorageDrsVMOverrideRead,
-		Update: resourceVSphereStorageDrsVMOverrideUpdate,
-		Delete: resourceVSphereStorageDrsVMOverrideDelete,
+		CreateContext: resourceVSphereStorageDrsVMOverrideCreate,
+		ReadContext:   resourceVSphereStorageDrsVMOverrideRead,
+		UpdateContext: resourceVSphereStorageDrsVMOverrideUpdate,
+		DeleteContext: resourceVSphereStorageDrsVMOverrideDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereStorageDrsVMOverrideImport,
+			StateContext: resourceVSphereStorageDrsVMOverrideImport,
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -70,22 +72,22 @@ func resourceVSphereStorageDrsVMOverride() *schema.Resource {
 	}
 }
 
-func resourceVSphereStorageDrsVMOverrideCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereStorageDrsVMOverrideIDString(d))
 
 	client, err := resourceVSphereStorageDrsVMOverrideClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandStorageDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := types.StorageDrsConfigSpec{
 		VmConfigSpec: []types.StorageDrsVmConfigSpec{
@@ -98,31 +100,31 @@ func resourceVSphereStorageDrsVMOverrideCreate(d *schema.ResourceData, meta inte
 		},
 	}
 
-	if err = storagepod.ApplyDRSConfiguration(client, pod, spec); err != nil {
-		return err
+	if err = storagepod.ApplyDRSConfiguration(ctx, client, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	id, err := resourceVSphereStorageDrsVMOverrideFlattenID(pod, vm)
 	if err != nil {
-		return fmt.Errorf("cannot compute ID of imported resource: %s", err)
+		return diag.FromErr(fmt.Errorf("cannot compute ID of imported resource: %s", err))
 	}
 	d.SetId(id)
 
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereStorageDrsVMOverrideIDString(d))
-	return resourceVSphereStorageDrsVMOverrideRead(d, meta)
+	return resourceVSphereStorageDrsVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereStorageDrsVMOverrideRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereStorageDrsVMOverrideIDString(d))
 
 	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := resourceVSphereStorageDrsVMOverrideFindEntry(pod, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	if info == nil {
@@ -135,41 +137,41 @@ func resourceVSphereStorageDrsVMOverrideRead(d *schema.ResourceData, meta interf
 	// ForceNew, but we set these for completeness on import so that if the wrong
 	// datastore cluster/VM combo was used, it will be noted.
 	if err = d.Set("datastore_cluster_id", pod.Reference().Value); err != nil {
-		return fmt.Errorf("error setting attribute \"datastore_cluster_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"datastore_cluster_id\": %s", err))
 	}
 
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config.uuid")
 	if err != nil {
-		return fmt.Errorf("error getting properties of virtual machine: %s", err)
+		return diag.FromErr(fmt.Errorf("error getting properties of virtual machine: %s", err))
 	}
 	if err = d.Set("virtual_machine_id", props.Config.Uuid); err != nil {
-		return fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err)
+		return diag.FromErr(fmt.Errorf("error setting attribute \"virtual_machine_id\": %s", err))
 	}
 
 	if err = flattenStorageDrsVMConfigInfo(d, info); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Read completed successfully", resourceVSphereStorageDrsVMOverrideIDString(d))
 	return nil
 }
 
-func resourceVSphereStorageDrsVMOverrideUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereStorageDrsVMOverrideIDString(d))
 
 	client, err := resourceVSphereStorageDrsVMOverrideClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	info, err := expandStorageDrsVMConfigInfo(d, vm)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := types.StorageDrsConfigSpec{
 		VmConfigSpec: []types.StorageDrsVmConfigSpec{
@@ -186,26 +188,26 @@ func resourceVSphereStorageDrsVMOverrideUpdate(d *schema.ResourceData, meta inte
 		},
 	}
 
-	if err := storagepod.ApplyDRSConfiguration(client, pod, spec); err != nil {
-		return err
+	if err := storagepod.ApplyDRSConfiguration(ctx, client, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereStorageDrsVMOverrideIDString(d))
-	return resourceVSphereStorageDrsVMOverrideRead(d, meta)
+	return resourceVSphereStorageDrsVMOverrideRead(ctx, d, meta)
 }
 
-func resourceVSphereStorageDrsVMOverrideDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereStorageDrsVMOverrideDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	resourceIDString := resourceVSphereStorageDrsVMOverrideIDString(d)
 	log.Printf("[DEBUG] %s: Beginning delete", resourceIDString)
 
 	client, err := resourceVSphereStorageDrsVMOverrideClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	pod, vm, err := resourceVSphereStorageDrsVMOverrideObjects(d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	spec := types.StorageDrsConfigSpec{
@@ -219,8 +221,8 @@ func resourceVSphereStorageDrsVMOverrideDelete(d *schema.ResourceData, meta inte
 		},
 	}
 
-	if err := storagepod.ApplyDRSConfiguration(client, pod, spec); err != nil {
-		return err
+	if err := storagepod.ApplyDRSConfiguration(ctx, client, pod, spec); err != nil {
+		return diag.FromErr(err)
 	}
 
 	d.SetId("")
@@ -229,7 +231,7 @@ func resourceVSphereStorageDrsVMOverrideDelete(d *schema.ResourceData, meta inte
 	return nil
 }
 
-func resourceVSphereStorageDrsVMOverrideImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereStorageDrsVMOverrideImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	var data map[string]string
 	if err := json.Unmarshal([]byte(d.Id()), &data); err != nil {
 		return nil, err
@@ -314,7 +316,7 @@ func resourceVSphereStorageDrsVMOverrideIDString(d structure.ResourceIDStringer)
 // vsphere_storage_drs_vm_override resource.
 func resourceVSphereStorageDrsVMOverrideFlattenID(pod *object.StoragePod, vm *object.VirtualMachine) (string, error) {
 	podID := pod.Reference().Value
-	props, err := virtualmachine.Properties(vm)
+	props, err := virtualmachine.Properties(vm, "config.uuid")
 	if err != nil {
 		return "", fmt.Errorf("cannot compute ID off of properties of virtual machine: %s", err)
 	}
@@ -339,7 +341,7 @@ func resourceVSphereStorageDrsVMOverrideFindEntry(
 	pod *object.StoragePod,
 	vm *object.VirtualMachine,
 ) (*types.StorageDrsVmConfigInfo, error) {
-	props, err := storagepod.Properties(pod)
+	props, err := storagepod.Properties(pod, "podStorageDrsEntry")
 	if err != nil {
 		return nil, fmt.Errorf("error fetching datastore cluster properties: %s", err)
 	}
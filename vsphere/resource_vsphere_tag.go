@@ -11,19 +11,24 @@ import (
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi/vapi/tags"
 )
 
 func resourceVSphereTag() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereTagCreate,
-		Read:   resourceVSphereTagRead,
-		Update: resourceVSphereTagUpdate,
-		Delete: resourceVSphereTagDelete,
+		CreateContext: resourceVSphereTagCreate,
+		ReadContext:   resourceVSphereTagRead,
+		UpdateContext: resourceVSphereTagUpdate,
+		DeleteContext: resourceVSphereTagDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereTagImport,
+			StateContext: resourceVSphereTagImport,
 		},
+		CustomizeDiff: requireCapabilities(
+			capabilityRequirement{capability: viapi.CapabilityRestAPI},
+		),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -46,38 +51,38 @@ func resourceVSphereTag() *schema.Resource {
 	}
 }
 
-func resourceVSphereTagCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	spec := &tags.Tag{
 		CategoryID:  d.Get("category_id").(string),
 		Description: d.Get("description").(string),
 		Name:        d.Get("name").(string),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	id, err := tm.CreateTag(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("could not create tag: %s", err)
+		return diag.FromErr(fmt.Errorf("could not create tag: %s", err))
 	}
 	if id == "" {
-		return errors.New("no ID was returned")
+		return diag.FromErr(errors.New("no ID was returned"))
 	}
 	d.SetId(id)
-	return resourceVSphereTagRead(d, meta)
+	return resourceVSphereTagRead(ctx, d, meta)
 }
 
-func resourceVSphereTagRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	tag, err := tm.GetTag(ctx, id)
 	if err != nil {
@@ -86,7 +91,7 @@ func resourceVSphereTagRead(d *schema.ResourceData, meta interface{}) error {
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("name", tag.Name)
 	_ = d.Set("description", tag.Description)
@@ -95,10 +100,10 @@ func resourceVSphereTagRead(d *schema.ResourceData, meta interface{}) error {
 	return nil
 }
 
-func resourceVSphereTagUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
@@ -107,37 +112,37 @@ func resourceVSphereTagUpdate(d *schema.ResourceData, meta interface{}) error {
 		Description: d.Get("description").(string),
 		Name:        d.Get("name").(string),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	err = tm.UpdateTag(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("could not update tag with id %q: %s", id, err)
+		return diag.FromErr(fmt.Errorf("could not update tag with id %q: %s", id, err))
 	}
-	return resourceVSphereTagRead(d, meta)
+	return resourceVSphereTagRead(ctx, d, meta)
 }
 
-func resourceVSphereTagDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	tag, err := tm.GetTag(ctx, id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	err = tm.DeleteTag(ctx, tag)
 	if err != nil {
-		return fmt.Errorf("could not delete tag with id %q: %s", id, err)
+		return diag.FromErr(fmt.Errorf("could not delete tag with id %q: %s", id, err))
 	}
 	return nil
 }
 
-func resourceVSphereTagImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereTagImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// Import takes the tag and category names through JSON to make sure we can
 	// search on special characters, since there does not seem to be any sort of
 	// prohibited kind of character when dealing with either tags or categories.
@@ -10,9 +10,11 @@ import (
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/vmware/govmomi/vapi/tags"
 )
 
@@ -29,13 +31,16 @@ const (
 
 func resourceVSphereTagCategory() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereTagCategoryCreate,
-		Read:   resourceVSphereTagCategoryRead,
-		Update: resourceVSphereTagCategoryUpdate,
-		Delete: resourceVSphereTagCategoryDelete,
+		CreateContext: resourceVSphereTagCategoryCreate,
+		ReadContext:   resourceVSphereTagCategoryRead,
+		UpdateContext: resourceVSphereTagCategoryUpdate,
+		DeleteContext: resourceVSphereTagCategoryDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereTagCategoryImport,
+			StateContext: resourceVSphereTagCategoryImport,
 		},
+		CustomizeDiff: requireCapabilities(
+			capabilityRequirement{capability: viapi.CapabilityRestAPI},
+		),
 
 		Schema: map[string]*schema.Schema{
 			"name": {
@@ -71,14 +76,14 @@ func resourceVSphereTagCategory() *schema.Resource {
 	}
 }
 
-func resourceVSphereTagCategoryCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagCategoryCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	associableTypesRaw := structure.SliceInterfacesToStrings(d.Get("associable_types").(*schema.Set).List())
 	if err := validateAssociableTypes(associableTypesRaw); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	associableTypes := appendPrefix(associableTypesRaw)
 
@@ -88,28 +93,28 @@ func resourceVSphereTagCategoryCreate(d *schema.ResourceData, meta interface{})
 		Description:     d.Get("description").(string),
 		Name:            d.Get("name").(string),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	id, err := tm.CreateCategory(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("could not create category: %s", err)
+		return diag.FromErr(fmt.Errorf("could not create category: %s", err))
 	}
 	if id == "" {
-		return errors.New("no ID was returned")
+		return diag.FromErr(errors.New("no ID was returned"))
 	}
 	d.SetId(id)
-	return resourceVSphereTagCategoryRead(d, meta)
+	return resourceVSphereTagCategoryRead(ctx, d, meta)
 }
 
-func resourceVSphereTagCategoryRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagCategoryRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	id := d.Id()
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	category, err := tm.GetCategory(ctx, id)
 	if err != nil {
@@ -118,23 +123,23 @@ func resourceVSphereTagCategoryRead(d *schema.ResourceData, meta interface{}) er
 			d.SetId("")
 			return nil
 		}
-		return err
+		return diag.FromErr(err)
 	}
 	_ = d.Set("name", category.Name)
 	_ = d.Set("description", category.Description)
 	_ = d.Set("cardinality", category.Cardinality)
 
-	if err := d.Set("associable_types", category.AssociableTypes); err != nil {
-		return fmt.Errorf("could not set associable type data for category: %s", err)
+	if err := d.Set("associable_types", trimPrefix(category.AssociableTypes)); err != nil {
+		return diag.FromErr(fmt.Errorf("could not set associable type data for category: %s", err))
 	}
 
 	return nil
 }
 
-func resourceVSphereTagCategoryUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagCategoryUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	// Block the update if the user has removed types
@@ -147,14 +152,14 @@ func resourceVSphereTagCategoryUpdate(d *schema.ResourceData, meta interface{})
 			}
 		}
 		if !found {
-			return fmt.Errorf("cannot remove type %q (removal of associable types is not supported)", v1)
+			return diag.FromErr(fmt.Errorf("cannot remove type %q (removal of associable types is not supported)", v1))
 		}
 	}
 
 	id := d.Id()
 	associableTypesRaw := structure.SliceInterfacesToStrings(d.Get("associable_types").(*schema.Set).List())
 	if err := validateAssociableTypes(associableTypesRaw); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	associableTypes := appendPrefix(associableTypesRaw)
 
@@ -165,36 +170,36 @@ func resourceVSphereTagCategoryUpdate(d *schema.ResourceData, meta interface{})
 		Description:     d.Get("description").(string),
 		Name:            d.Get("name").(string),
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	err = tm.UpdateCategory(ctx, spec)
 	if err != nil {
-		return fmt.Errorf("could not update category with id %q: %s", id, err)
+		return diag.FromErr(fmt.Errorf("could not update category with id %q: %s", id, err))
 	}
-	return resourceVSphereTagCategoryRead(d, meta)
+	return resourceVSphereTagCategoryRead(ctx, d, meta)
 }
 
-func resourceVSphereTagCategoryDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereTagCategoryDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	id := d.Id()
 
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 	tag, err := tm.GetCategory(ctx, id)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	err = tm.DeleteCategory(ctx, tag)
 	if err != nil {
-		return fmt.Errorf("could not delete category with id %q: %s", id, err)
+		return diag.FromErr(fmt.Errorf("could not delete category with id %q: %s", id, err))
 	}
 	return nil
 }
 
-func resourceVSphereTagCategoryImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereTagCategoryImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	tm, err := meta.(*Client).TagsManager()
 	if err != nil {
 		return nil, err
@@ -216,6 +221,16 @@ func appendPrefix(associableTypes []string) []string {
 	return appendedTypes
 }
 
+// trimPrefix removes the vim25 prefix added by appendPrefix, for endpoints
+// that echo back the types as they were supplied.
+func trimPrefix(associableTypes []string) []string {
+	var trimmedTypes []string
+	for _, associableType := range associableTypes {
+		trimmedTypes = append(trimmedTypes, strings.TrimPrefix(associableType, vim25Prefix))
+	}
+	return trimmedTypes
+}
+
 func validateAssociableTypes(types []string) error {
 
 	mapOf := func(s []string) map[string]struct{} {
@@ -219,7 +219,7 @@ func testAccResourceVSphereTagCategoryHasTypes(expected []string) resource.TestC
 		}
 		// We use a *schema.Set for types, so the list may not be in the order that
 		// we expect it in. Sort both lists to make sure.
-		actual := cat.AssociableTypes
+		actual := trimPrefix(cat.AssociableTypes)
 		sort.Strings(expected)
 		sort.Strings(actual)
 		if !reflect.DeepEqual(expected, actual) {
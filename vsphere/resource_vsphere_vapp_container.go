@@ -4,10 +4,12 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -116,22 +118,24 @@ func resourceVSphereVAppContainer() *schema.Resource {
 			Optional:    true,
 			Default:     -1,
 		},
-		vSphereTagAttributeKey:    tagsSchema(),
-		customattribute.ConfigKey: customattribute.ConfigSchema(),
+		vSphereTagAttributeKey:     tagsSchema(),
+		vSphereTagsAllAttributeKey: tagsAllSchema(),
+		customattribute.ConfigKey:  customattribute.ConfigSchema(),
 	}
 	return &schema.Resource{
-		Create: resourceVSphereVAppContainerCreate,
-		Read:   resourceVSphereVAppContainerRead,
-		Update: resourceVSphereVAppContainerUpdate,
-		Delete: resourceVSphereVAppContainerDelete,
+		CreateContext: resourceVSphereVAppContainerCreate,
+		ReadContext:   resourceVSphereVAppContainerRead,
+		UpdateContext: resourceVSphereVAppContainerUpdate,
+		DeleteContext: resourceVSphereVAppContainerDelete,
+		CustomizeDiff: tagsAllCustomizeDiff,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVAppContainerImport,
+			StateContext: resourceVSphereVAppContainerImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereVAppContainerImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVAppContainerImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
 		return nil, err
@@ -144,15 +148,15 @@ func resourceVSphereVAppContainerImport(d *schema.ResourceData, meta interface{}
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVSphereVAppContainerCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	prp, err := resourcepool.FromID(client, d.Get("parent_resource_pool_id").(string))
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	rpSpec := expandVAppContainerConfigSpec(d)
 	vcSpec := &types.VAppConfigSpec{}
@@ -164,42 +168,42 @@ func resourceVSphereVAppContainerCreate(d *schema.ResourceData, meta interface{}
 		if pf, ok := d.GetOk("parent_folder_id"); ok {
 			f, err = folder.FromID(client, pf.(string))
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 		} else {
 			p := strings.Split(prp.InventoryPath, "/")
 			if len(p) < 2 {
-				return fmt.Errorf("unable to locate datacenter name from parent resource pool")
+				return errorDiagnostics(fmt.Errorf("unable to locate datacenter name from parent resource pool"), nil)
 			}
 			var dc *object.Datacenter
 			dc, err = getDatacenter(client, p[1])
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 			f, err = folder.FromPath(client, "", folder.VSphereFolderTypeVM, dc)
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 		}
 	}
 
 	vc, err := vappcontainer.Create(prp, d.Get("name").(string), rpSpec, vcSpec, f)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err = resourceVSphereVAppContainerApplyTags(d, meta, vc); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	d.SetId(vc.Reference().Value)
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereVAppContainerIDString(d))
-	return resourceVSphereVAppContainerRead(d, meta)
+	return resourceVSphereVAppContainerRead(ctx, d, meta)
 }
 
-func resourceVSphereVAppContainerRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	vc, err := vappcontainer.FromID(client, d.Id())
 	if err != nil {
@@ -208,48 +212,48 @@ func resourceVSphereVAppContainerRead(d *schema.ResourceData, meta interface{})
 			d.SetId("")
 			return nil
 		}
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	if err = resourceVSphereVAppContainerReadTags(d, meta, vc); err != nil {
-		return err
+	if err = resourceVSphereVAppContainerReadTags(ctx, d, meta, vc); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 	if err = d.Set("name", vc.Name()); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	vcProps, err := vappcontainer.Properties(vc)
+	vcProps, err := vappcontainer.Properties(vc, "config", "parent", "parentFolder")
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if vcProps.Parent != nil {
 		if err = d.Set("parent_resource_pool_id", vcProps.Parent.Value); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	if vcProps.ParentFolder != nil {
 		if err = d.Set("parent_folder_id", vcProps.ParentFolder.Value); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 	if err = flattenVAppContainerConfigSpec(d, vcProps.Config); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereVAppContainerIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppContainerUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	vc, err := vappcontainer.FromID(client, d.Id())
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err = resourceVSphereVAppContainerApplyTags(d, meta, vc); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	op, np := d.GetChange("parent_resource_pool_id")
 	if op != np {
@@ -257,37 +261,37 @@ func resourceVSphereVAppContainerUpdate(d *schema.ResourceData, meta interface{}
 		var p *object.ResourcePool
 		p, err = resourcepool.FromID(client, np.(string))
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		if err = resourcepool.MoveIntoResourcePool(p, vc.Reference()); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		log.Printf("[DEBUG] %s: Move finished successfully", resourceVSphereVAppContainerIDString(d))
 	}
 
 	vcSpec := types.VAppConfigSpec{}
 	if err = vappcontainer.Update(vc, vcSpec); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereVAppContainerIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppContainerDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppContainerDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereVAppContainerIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	vc, err := vappcontainer.FromID(client, d.Id())
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if err = resourceVSphereVAppContainerValidateEmpty(vc); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
-	if err = vappcontainer.Delete(vc); err != nil {
-		return err
+	if err = vappcontainer.Delete(ctx, vc); err != nil {
+		return errorDiagnostics(err, nil)
 	}
 	log.Printf("[DEBUG] %s: Deleted successfully", resourceVSphereVAppContainerIDString(d))
 	return nil
@@ -367,15 +371,15 @@ func resourceVSphereVAppContainerApplyTags(d *schema.ResourceData, meta interfac
 	}
 
 	log.Printf("[DEBUG] %s: Applying any pending tags", resourceVSphereVAppContainerIDString(d))
-	return processTagDiff(tagsClient, d, va)
+	return processTagDiff(tagsClient, d, va, meta)
 }
 
 // resourceVSphereVAppContainerReadTags reads the tags for
 // vsphere_vapp_container.
-func resourceVSphereVAppContainerReadTags(d *schema.ResourceData, meta interface{}, va *object.VirtualApp) error {
+func resourceVSphereVAppContainerReadTags(ctx context.Context, d *schema.ResourceData, meta interface{}, va *object.VirtualApp) error {
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
 		log.Printf("[DEBUG] %s: Reading tags", resourceVSphereVAppContainerIDString(d))
-		if err := readTagsForResource(tagsClient, va, d); err != nil {
+		if err := readTagsForResource(ctx, tagsClient, va, d, meta); err != nil {
 			return err
 		}
 	} else {
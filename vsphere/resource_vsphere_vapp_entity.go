@@ -4,10 +4,12 @@
 package vsphere
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/structure"
@@ -75,18 +77,18 @@ func resourceVSphereVAppEntity() *schema.Resource {
 		customattribute.ConfigKey: customattribute.ConfigSchema(),
 	}
 	return &schema.Resource{
-		Create: resourceVSphereVAppEntityCreate,
-		Read:   resourceVSphereVAppEntityRead,
-		Update: resourceVSphereVAppEntityUpdate,
-		Delete: resourceVSphereVAppEntityDelete,
+		CreateContext: resourceVSphereVAppEntityCreate,
+		ReadContext:   resourceVSphereVAppEntityRead,
+		UpdateContext: resourceVSphereVAppEntityUpdate,
+		DeleteContext: resourceVSphereVAppEntityDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVAppEntityImport,
+			StateContext: resourceVSphereVAppEntityImport,
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereVAppEntityImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVAppEntityImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client, err := resourceVSphereVAppEntityClient(meta)
 	if err != nil {
 		return nil, err
@@ -110,55 +112,55 @@ func resourceVSphereVAppEntityImport(d *schema.ResourceData, meta interface{}) (
 	return []*schema.ResourceData{d}, nil
 }
 
-func resourceVSphereVAppEntityCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	container, err := vappcontainer.FromID(client, d.Get("container_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps, err := vappcontainer.Properties(container)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	d.SetId(resourceVSphereVAppEntityIDFromKeys(d.Get("container_id").(string), d.Get("target_id").(string)))
 	entityConfig, err := expandVAppEntityConfigSpec(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps.VAppConfig.EntityConfig = append(cProps.VAppConfig.EntityConfig, *entityConfig)
 	updateSpec := types.VAppConfigSpec{
 		EntityConfig: cProps.VAppConfig.EntityConfig,
 	}
 	if err = vappcontainer.Update(container, updateSpec); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Create finished successfully", resourceVSphereVAppEntityIDString(d))
-	return resourceVSphereVAppEntityRead(d, meta)
+	return resourceVSphereVAppEntityRead(ctx, d, meta)
 }
 
-func resourceVSphereVAppEntityRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning read", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	vcID, vmID, err := resourceVSphereVAppEntitySplitID(d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("container_id", vcID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if err = d.Set("target_id", vmID); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	entity, err := resourceVSphereVAppEntityFind(client, d.Id())
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	if entity == nil {
 		log.Printf("[DEBUG] %s: Resource has been deleted", resourceVSphereVAppEntityIDString(d))
@@ -167,58 +169,58 @@ func resourceVSphereVAppEntityRead(d *schema.ResourceData, meta interface{}) err
 	}
 	err = flattenVAppEntityConfigSpec(client, d, entity)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Read finished successfully", resourceVSphereVAppEntityIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppEntityUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning update", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	container, err := vappcontainer.FromID(client, d.Get("container_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps, err := vappcontainer.Properties(container)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	entityConfig, err := expandVAppEntityConfigSpec(client, d)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps.VAppConfig.EntityConfig = []types.VAppEntityConfigInfo{*entityConfig}
 	updateSpec := types.VAppConfigSpec{
 		EntityConfig: cProps.VAppConfig.EntityConfig,
 	}
 	if err = vappcontainer.Update(container, updateSpec); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Update finished successfully", resourceVSphereVAppEntityIDString(d))
 	return nil
 }
 
-func resourceVSphereVAppEntityDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVAppEntityDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning delete", resourceVSphereVAppEntityIDString(d))
 	client, err := resourceVSphereVAppContainerClient(meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	container, err := vappcontainer.FromID(client, d.Get("container_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	cProps, err := vappcontainer.Properties(container)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	vm, err := virtualmachine.FromMOID(client, d.Get("target_id").(string))
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	var el []types.VAppEntityConfigInfo
 	for _, e := range cProps.VAppConfig.EntityConfig {
@@ -230,7 +232,7 @@ func resourceVSphereVAppEntityDelete(d *schema.ResourceData, meta interface{}) e
 		EntityConfig: el,
 	}
 	if err = vappcontainer.Update(container, updateSpec); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] %s: Delete finished successfully", resourceVSphereVAppEntityIDString(d))
 	return nil
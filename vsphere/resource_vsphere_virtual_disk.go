@@ -10,10 +10,13 @@ import (
 
 	"errors"
 	"path"
+	"time"
 
 	"context"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualdisk"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/find"
@@ -34,11 +37,15 @@ type virtualDisk struct {
 // Define VirtualDisk args
 func resourceVSphereVirtualDisk() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereVirtualDiskCreate,
-		Read:   resourceVSphereVirtualDiskRead,
-		Delete: resourceVSphereVirtualDiskDelete,
+		CreateContext: resourceVSphereVirtualDiskCreate,
+		ReadContext:   resourceVSphereVirtualDiskRead,
+		DeleteContext: resourceVSphereVirtualDiskDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVirtualDiskImport,
+			StateContext: resourceVSphereVirtualDiskImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(120 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 
 		Schema: map[string]*schema.Schema{
@@ -119,7 +126,7 @@ func resourceVSphereVirtualDisk() *schema.Resource {
 	}
 }
 
-func resourceVSphereVirtualDiskCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[INFO] Creating Virtual Disk")
 	client := meta.(*Client).vimClient
 
@@ -155,13 +162,13 @@ func resourceVSphereVirtualDiskCreate(d *schema.ResourceData, meta interface{})
 
 	dc, err := getDatacenter(client, d.Get("datacenter").(string))
 	if err != nil {
-		return fmt.Errorf("Error finding Datacenter: %s: %s", vDisk.datacenter, err)
+		return errorDiagnostics(fmt.Errorf("Error finding Datacenter: %s: %w", vDisk.datacenter, err), nil)
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, vDisk.datastore)
+	ds, err := getDatastore(ctx, finder, vDisk.datastore)
 	if err != nil {
-		return fmt.Errorf("Error finding Datastore: %s: %s", vDisk.datastore, err)
+		return errorDiagnostics(fmt.Errorf("Error finding Datastore: %s: %w", vDisk.datastore, err), nil)
 	}
 
 	fm := object.NewFileManager(client.Client)
@@ -171,32 +178,32 @@ func resourceVSphereVirtualDiskCreate(d *schema.ResourceData, meta interface{})
 		if directoryPathIndex > 0 {
 			vmdkPath := vDisk.vmdkPath[0:directoryPathIndex]
 			log.Printf("[DEBUG] Creating parent directories: %v", ds.Path(vmdkPath))
-			err = fm.MakeDirectory(context.TODO(), ds.Path(vmdkPath), dc, true)
+			err = fm.MakeDirectory(ctx, ds.Path(vmdkPath), dc, true)
 			if err != nil && !isAlreadyExists(err) {
 				log.Printf("[DEBUG] Failed to create parent directories:  %v", err)
-				return err
+				return errorDiagnostics(err, nil)
 			}
 
-			err = searchForDirectory(client, vDisk.datacenter, vDisk.datastore, vmdkPath)
+			err = searchForDirectory(ctx, client, vDisk.datacenter, vDisk.datastore, vmdkPath)
 			if err != nil {
 				log.Printf("[DEBUG] Failed to find newly created parent directories:  %v", err)
-				return err
+				return errorDiagnostics(err, nil)
 			}
 		}
 	}
 
-	err = createHardDisk(client, vDisk.size, ds.Path(vDisk.vmdkPath), vDisk.initType, vDisk.adapterType, vDisk.datacenter)
+	err = createHardDisk(ctx, client, vDisk.size, ds.Path(vDisk.vmdkPath), vDisk.initType, vDisk.adapterType, vDisk.datacenter)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	d.SetId(ds.Path(vDisk.vmdkPath))
 	log.Printf("[DEBUG] Virtual Disk id: %v", ds.Path(vDisk.vmdkPath))
 
-	return resourceVSphereVirtualDiskRead(d, meta)
+	return resourceVSphereVirtualDiskRead(ctx, d, meta)
 }
 
-func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Reading virtual disk.")
 	client := meta.(*Client).vimClient
 
@@ -226,21 +233,20 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 
 	dc, err := getDatacenter(client, d.Get("datacenter").(string))
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	finder := find.NewFinder(client.Client, true)
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := finder.Datastore(context.TODO(), d.Get("datastore").(string))
+	ds, err := finder.Datastore(ctx, d.Get("datastore").(string))
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	ctx := context.TODO()
 	b, err := ds.Browser(ctx)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// `Datastore.Stat` does not allow to query `VmDiskFileQuery`. Instead, we
@@ -260,14 +266,14 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	dsPath := ds.Path(path.Dir(vDisk.vmdkPath))
-	task, err := b.SearchDatastore(context.TODO(), dsPath, &spec)
+	task, err := b.SearchDatastore(ctx, dsPath, &spec)
 
 	if err != nil {
 		log.Printf("[DEBUG] resourceVSphereVirtualDiskRead - could not search datastore for: %v", vDisk.vmdkPath)
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	info, err := task.WaitForResult(context.TODO(), nil)
+	info, err := viapi.WaitForTask(ctx, task)
 	if err != nil {
 		if info != nil && info.Error != nil {
 			_, ok := info.Error.Fault.(*types.FileNotFound)
@@ -279,7 +285,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 		}
 
 		log.Printf("[DEBUG] resourceVSphereVirtualDiskRead - could not search datastore for: %v", vDisk.vmdkPath)
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	res := info.Result.(types.HostDatastoreBrowserSearchResults)
@@ -291,7 +297,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	if len(res.File) != 1 {
-		return errors.New("Datastore search did not return exactly one result")
+		return errorDiagnostics(errors.New("Datastore search did not return exactly one result"), nil)
 	}
 
 	fileInfo := res.File[0]
@@ -314,7 +320,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	}
 
 	if err != nil {
-		return errors.New("Failed to query disk type")
+		return errorDiagnostics(errors.New("Failed to query disk type"), nil)
 	}
 
 	// adapter_type is deprecated, so just default.
@@ -332,7 +338,7 @@ func resourceVSphereVirtualDiskRead(d *schema.ResourceData, meta interface{}) er
 	return nil
 }
 
-func resourceVSphereVirtualDiskDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualDiskDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	vDisk := virtualDisk{}
@@ -346,30 +352,30 @@ func resourceVSphereVirtualDiskDelete(d *schema.ResourceData, meta interface{})
 
 	dc, err := getDatacenter(client, d.Get("datacenter").(string))
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	finder := find.NewFinder(client.Client, true)
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := getDatastore(finder, vDisk.datastore)
+	ds, err := getDatastore(ctx, finder, vDisk.datastore)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	diskPath := ds.Path(vDisk.vmdkPath)
 
 	virtualDiskManager := object.NewVirtualDiskManager(client.Client)
 
-	task, err := virtualDiskManager.DeleteVirtualDisk(context.TODO(), diskPath, dc)
+	task, err := virtualDiskManager.DeleteVirtualDisk(ctx, diskPath, dc)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
-	_, err = task.WaitForResult(context.TODO(), nil)
+	_, err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		log.Printf("[INFO] Failed to delete disk:  %v", err)
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	log.Printf("[INFO] Deleted disk: %v", diskPath)
@@ -383,7 +389,7 @@ func isAlreadyExists(err error) bool {
 }
 
 // createHardDisk creates a new Hard Disk.
-func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType string, adapterType string, dc string) error {
+func createHardDisk(ctx context.Context, client *govmomi.Client, size int, diskPath string, diskType string, adapterType string, dc string) error {
 	var vDiskType string
 	switch diskType {
 	case "thin":
@@ -408,12 +414,12 @@ func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType
 	}
 	log.Printf("[DEBUG] Disk spec: %v", spec)
 
-	task, err := virtualDiskManager.CreateVirtualDisk(context.TODO(), diskPath, datacenter, spec)
+	task, err := virtualDiskManager.CreateVirtualDisk(ctx, diskPath, datacenter, spec)
 	if err != nil {
 		return err
 	}
 
-	_, err = task.WaitForResult(context.TODO(), nil)
+	_, err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		log.Printf("[INFO] Failed to create disk:  %v", err)
 		return err
@@ -424,7 +430,7 @@ func createHardDisk(client *govmomi.Client, size int, diskPath string, diskType
 }
 
 // Searches for the presence of a directory path.
-func searchForDirectory(client *govmomi.Client, datacenter string, datastore string, directoryPath string) error {
+func searchForDirectory(ctx context.Context, client *govmomi.Client, datacenter string, datastore string, directoryPath string) error {
 	log.Printf("[DEBUG] Searching for Directory")
 	finder := find.NewFinder(client.Client, true)
 
@@ -434,12 +440,11 @@ func searchForDirectory(client *govmomi.Client, datacenter string, datastore str
 	}
 	finder = finder.SetDatacenter(dc)
 
-	ds, err := finder.Datastore(context.TODO(), datastore)
+	ds, err := finder.Datastore(ctx, datastore)
 	if err != nil {
 		return fmt.Errorf("Error finding Datastore: %s: %s", datastore, err)
 	}
 
-	ctx := context.TODO()
 	b, err := ds.Browser(ctx)
 	if err != nil {
 		return err
@@ -457,14 +462,14 @@ func searchForDirectory(client *govmomi.Client, datacenter string, datastore str
 	}
 
 	dsPath := ds.Path(path.Dir(directoryPath))
-	task, err := b.SearchDatastore(context.TODO(), dsPath, &spec)
+	task, err := b.SearchDatastore(ctx, dsPath, &spec)
 
 	if err != nil {
 		log.Printf("[DEBUG] searchForDirectory - could not search datastore for: %v", directoryPath)
 		return err
 	}
 
-	info, err := task.WaitForResult(context.TODO(), nil)
+	info, err := viapi.WaitForTask(ctx, task)
 	if err != nil {
 		if info != nil && info.Error != nil {
 			_, ok := info.Error.Fault.(*types.FileNotFound)
@@ -495,7 +500,7 @@ func searchForDirectory(client *govmomi.Client, datacenter string, datastore str
 	return nil
 }
 
-func resourceVSphereVirtualDiskImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVirtualDiskImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 	p := d.Id()
 	if !strings.HasPrefix(p, "/") {
@@ -16,6 +16,9 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/contentlibrary"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/ovfdeploy"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -33,6 +36,7 @@ import (
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/vmworkflow"
 	"github.com/vmware/govmomi"
 	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vapi/library"
 	"github.com/vmware/govmomi/vapi/vcenter"
 	"github.com/vmware/govmomi/vim25/types"
 )
@@ -42,7 +46,7 @@ import (
 const formatVirtualMachinePostCloneRollbackError = `
 WARNING:
 There was an error performing post-clone changes to virtual machine %q:
-%s
+%w
 Additionally, there was an error removing the cloned virtual machine:
 %s
 
@@ -281,20 +285,36 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 			Computed:    true,
 			Description: "The power state of the virtual machine.",
 		},
-		vSphereTagAttributeKey:    tagsSchema(),
-		customattribute.ConfigKey: customattribute.ConfigSchema(),
+		vSphereTagAttributeKey:         tagsSchema(),
+		vSphereTagsAllAttributeKey:     tagsAllSchema(),
+		deletionProtectionAttributeKey: deletionProtectionSchema(),
+		customattribute.ConfigKey:      customattribute.ConfigSchema(),
 	}
 	structure.MergeSchema(s, schemaVirtualMachineConfigSpec())
 	structure.MergeSchema(s, schemaVirtualMachineGuestInfo())
 
 	return &schema.Resource{
-		Create:        resourceVSphereVirtualMachineCreate,
-		Read:          resourceVSphereVirtualMachineRead,
-		Update:        resourceVSphereVirtualMachineUpdate,
-		Delete:        resourceVSphereVirtualMachineDelete,
-		CustomizeDiff: resourceVSphereVirtualMachineCustomizeDiff,
+		CreateContext: resourceVSphereVirtualMachineCreate,
+		ReadContext:   resourceVSphereVirtualMachineRead,
+		UpdateContext: resourceVSphereVirtualMachineUpdate,
+		DeleteContext: resourceVSphereVirtualMachineDelete,
+		CustomizeDiff: customdiff.Sequence(
+			requireCapabilities(
+				capabilityRequirement{capability: viapi.CapabilityEFISecureBoot, attribute: "efi_secure_boot_enabled"},
+				capabilityRequirement{capability: viapi.CapabilityVBS, attribute: "vbs_enabled"},
+				capabilityRequirement{capability: viapi.CapabilityVVTD, attribute: "vvtd_enabled"},
+			),
+			resourceVSphereVirtualMachineCustomizeDiff,
+			tagsAllCustomizeDiff,
+			deletionProtectionCustomizeDiff("vsphere_virtual_machine", resourceVSphereVirtualMachine),
+		),
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVirtualMachineImport,
+			StateContext: resourceVSphereVirtualMachineImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(90 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 		SchemaVersion: 3,
 		MigrateState:  resourceVSphereVirtualMachineMigrateState,
@@ -302,17 +322,17 @@ func resourceVSphereVirtualMachine() *schema.Resource {
 	}
 }
 
-func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Beginning create", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	var vm *object.VirtualMachine
@@ -322,35 +342,35 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 	// The VM should also be returned powered on.
 	switch {
 	case len(d.Get("clone").([]interface{})) > 0:
-		vm, err = resourceVSphereVirtualMachineCreateClone(d, meta)
+		vm, err = resourceVSphereVirtualMachineCreateClone(ctx, d, meta)
 	case len(d.Get("ovf_deploy").([]interface{})) > 0:
-		vm, err = resourceVsphereMachineDeployOvfAndOva(d, meta)
+		vm, err = resourceVsphereMachineDeployOvfAndOva(ctx, d, meta)
 	default:
-		vm, err = resourceVSphereVirtualMachineCreateBare(d, meta)
+		vm, err = resourceVSphereVirtualMachineCreateBare(ctx, d, meta)
 	}
 
 	if err != nil {
-		return err
+		return resourceVSphereVirtualMachineErrorDiagnostics(d, err)
 	}
 
 	// Tag the VM
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, vm); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, vm, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(vm); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Verify that host_system_id is set if pci_device_id is used.
 	pciDev := d.Get("pci_device_id").(*schema.Set)
 	if pciDev.Len() > 0 && d.Get("host_system_id").(string) == "" {
-		return fmt.Errorf("host_system_id must be set when using pci_device_id")
+		return errorDiagnostics(fmt.Errorf("host_system_id must be set when using pci_device_id"), nil)
 	}
 
 	// The host attribute of CreateVM_Task seems to be ignored in vCenter 6.7.
@@ -359,37 +379,38 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 	// ResourceData.
 	vprops, err := virtualmachine.Properties(vm)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if hid, ok := d.GetOk("host_system_id"); hid.(string) != vprops.Runtime.Host.Reference().Value && ok {
-		err = resourceVSphereVirtualMachineRead(d, meta)
-		if err != nil {
-			return err
+		if diags := resourceVSphereVirtualMachineRead(ctx, d, meta); diags.HasError() {
+			return diags
 		}
 		// Restore the old host_system_id so we can still tell if a relocation is
 		// necessary.
 		err = d.Set("host_system_id", hid.(string))
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
-		if err = resourceVSphereVirtualMachineUpdateLocation(d, meta); err != nil {
-			return err
+		if err = resourceVSphereVirtualMachineUpdateLocation(ctx, d, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Wait for guest IP address if we have been set to wait for one
 	err = virtualmachine.WaitForGuestIP(
+		ctx,
 		client,
 		vm,
 		d.Get("wait_for_guest_ip_timeout").(int),
 		d.Get("ignored_guest_ips").([]interface{}),
 	)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Wait for a routable address if we have been set to wait for one
 	err = virtualmachine.WaitForGuestNet(
+		ctx,
 		client,
 		vm,
 		d.Get("wait_for_guest_net_routable").(bool),
@@ -397,16 +418,32 @@ func resourceVSphereVirtualMachineCreate(d *schema.ResourceData, meta interface{
 		d.Get("ignored_guest_ips").([]interface{}),
 	)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// All done!
 	log.Printf("[DEBUG] %s: Create complete", resourceVSphereVirtualMachineIDString(d))
-	return resourceVSphereVirtualMachineRead(d, meta)
+	return resourceVSphereVirtualMachineRead(ctx, d, meta)
 }
 
-func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{}) error {
+// resourceVSphereVirtualMachineReadPaths are the properties of a virtual
+// machine that are read into its state.
+var resourceVSphereVirtualMachineReadPaths = []string{
+	"config",
+	"customValue",
+	"datastore",
+	"guest",
+	"parentVApp",
+	"resourcePool",
+	"runtime.host",
+	"runtime.powerState",
+}
+
+func resourceVSphereVirtualMachineRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Reading state of virtual machine", resourceVSphereVirtualMachineIDString(d))
+	if err := readDeletionProtection(d, meta); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	id := d.Id()
 	vm, err := virtualmachine.FromUUID(client, id)
@@ -416,12 +453,12 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("error searching for with UUID %q: %s", id, err)
+		return errorDiagnostics(fmt.Errorf("error searching for with UUID %q: %w", id, err), nil)
 	}
 
-	vprops, err := virtualmachine.Properties(vm)
+	vprops, err := virtualmachine.Properties(vm, resourceVSphereVirtualMachineReadPaths...)
 	if err != nil {
-		return fmt.Errorf("error fetching VM properties: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching VM properties: %w", err), nil)
 	}
 
 	// Set the managed object id.
@@ -432,6 +469,10 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	// Reset reboot_required. This is an update only variable and should not be
 	// set across TF runs.
 	_ = d.Set("reboot_required", false)
+	// Keep only fingerprints of the customization passwords in state.
+	if err := vmworkflow.FingerprintCustomizationPasswords(d); err != nil {
+		return errorDiagnostics(fmt.Errorf("error setting clone: %w", err), nil)
+	}
 	// Check to see if VMware Tools is running.
 	if vprops.Guest != nil {
 		_ = d.Set("vmware_tools_status", vprops.Guest.ToolsRunningStatus)
@@ -452,7 +493,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	if !vappcontainer.IsVApp(client, vmContainer) {
 		f, err := folder.RootPathParticleVM.SplitRelativeFolder(vm.InventoryPath)
 		if err != nil {
-			return fmt.Errorf("error parsing virtual machine path %q: %s", vm.InventoryPath, err)
+			return errorDiagnostics(fmt.Errorf("error parsing virtual machine path %q: %w", vm.InventoryPath, err), nil)
 		}
 		_ = d.Set("folder", folder.NormalizePath(f))
 	}
@@ -464,7 +505,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	// Set the VMX path and default datastore
 	dp := &object.DatastorePath{}
 	if ok := dp.FromString(vprops.Config.Files.VmPathName); !ok {
-		return fmt.Errorf("could not parse VMX file path: %s", vprops.Config.Files.VmPathName)
+		return errorDiagnostics(fmt.Errorf("could not parse VMX file path: %s", vprops.Config.Files.VmPathName), nil)
 	}
 	// The easiest path for us to get an exact match on the datastore in use is
 	// to look for the datastore name in the list of used datastores. This is
@@ -475,25 +516,25 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	for _, dsRef := range vprops.Datastore {
 		dsx, err := datastore.FromID(client, dsRef.Value)
 		if err != nil {
-			return fmt.Errorf("error locating VMX datastore: %s", err)
+			return errorDiagnostics(fmt.Errorf("error locating VMX datastore: %w", err), nil)
 		}
-		dsxProps, err := datastore.Properties(dsx)
+		dsxProps, err := datastore.Properties(dsx, "summary")
 		if err != nil {
-			return fmt.Errorf("error fetching VMX datastore properties: %s", err)
+			return errorDiagnostics(fmt.Errorf("error fetching VMX datastore properties: %w", err), nil)
 		}
 		if dsxProps.Summary.Name == dp.Datastore {
 			ds = dsx
 		}
 	}
 	if ds == nil {
-		return fmt.Errorf("VMX datastore %s not found", dp.Datastore)
+		return errorDiagnostics(fmt.Errorf("VMX datastore %s not found", dp.Datastore), nil)
 	}
 	_ = d.Set("datastore_id", ds.Reference().Value)
 	_ = d.Set("vmx_path", dp.Path)
 
 	// Read general VM config info
 	if err := flattenVirtualMachineConfigInfo(d, vprops.Config, client); err != nil {
-		return fmt.Errorf("error reading virtual machine configuration: %s", err)
+		return errorDiagnostics(fmt.Errorf("error reading virtual machine configuration: %w", err), nil)
 	}
 
 	// Check if running for ESXi or vCenter.
@@ -501,7 +542,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 		// Read the VM Home storage policy if associated.
 		polID, err := spbm.PolicyIDByVirtualMachine(client, moid)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		d.Set("storage_policy_id", polID)
 	}
@@ -520,7 +561,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	}
 	err = d.Set("pci_device_id", pciDevs)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Perform pending device read operations.
@@ -530,21 +571,21 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	_ = d.Set("scsi_bus_sharing", virtualdevice.ReadSCSIBusSharing(devices, d.Get("scsi_controller_count").(int)))
 	// Disks first
 	if err := virtualdevice.DiskRefreshOperation(d, client, devices); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Network devices
 	if err := virtualdevice.NetworkInterfaceRefreshOperation(d, client, devices); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// CDROM
 	if err := virtualdevice.CdromRefreshOperation(d, client, devices); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, vm, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, vm, d, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -558,7 +599,7 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	// user.
 	if vprops.Guest != nil {
 		if err := buildAndSelectGuestIPs(d, *vprops.Guest); err != nil {
-			return fmt.Errorf("error reading virtual machine guest data: %s", err)
+			return errorDiagnostics(fmt.Errorf("error reading virtual machine guest data: %w", err), nil)
 		}
 	}
 
@@ -576,52 +617,52 @@ func resourceVSphereVirtualMachineRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Performing update", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 	timeout := meta.(*Client).timeout
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	id := d.Id()
 	vm, err := virtualmachine.FromUUID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
+		return errorDiagnostics(fmt.Errorf("cannot locate virtual machine with UUID %q: %w", id, err), nil)
 	}
 
 	if d.HasChange("resource_pool_id") {
 		var rp *object.ResourcePool
 		rp, err = resourcepool.FromID(client, d.Get("resource_pool_id").(string))
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 
 		// Before we move the VM to the new RP we need to make sure the new one is on the same
 		// cluster or host as the old one, otherwise vsphere will throw an error.
 		dstRPProps, err := resourcepool.Properties(rp)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 
 		vmProps, err := virtualmachine.Properties(vm)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		srcRPID := vmProps.ResourcePool.Value
 		srcResourcePool, err := resourcepool.FromID(client, srcRPID)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 		srcRPProps, err := resourcepool.Properties(srcResourcePool)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 
 		// If the source and destination RPs have different owners (i.e hosts or clusters)
@@ -629,7 +670,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 		if dstRPProps.Owner == srcRPProps.Owner {
 			err = resourcepool.MoveIntoResourcePool(rp, vm.Reference())
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 		} else {
 			// If we're migrating away from the current host we're setting the host system ID
@@ -642,29 +683,29 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 		// refresh the VM after moving into a new resource pool.
 		vm, err = virtualmachine.FromMOID(client, vm.Reference().Value)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Update folder if necessary
 	if d.HasChange("folder") && !vappcontainer.IsVApp(client, d.Get("resource_pool_id").(string)) {
 		vmFolder := d.Get("folder").(string)
-		if err := virtualmachine.MoveToFolder(client, vm, vmFolder); err != nil {
-			return fmt.Errorf("could not move virtual machine to folder %q: %s", vmFolder, err)
+		if err := virtualmachine.MoveToFolder(ctx, client, vm, vmFolder); err != nil {
+			return errorDiagnostics(fmt.Errorf("could not move virtual machine to folder %q: %w", vmFolder, err), nil)
 		}
 	}
 
 	// Apply any pending tags
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, vm); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, vm, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Update custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(vm); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -674,17 +715,17 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 
 	vprops, err := virtualmachine.Properties(vm)
 	if err != nil {
-		return fmt.Errorf("error fetching VM properties: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching VM properties: %w", err), nil)
 	}
 
 	spec, changed, err := expandVirtualMachineConfigSpecChanged(d, client, vprops.Config)
 	if err != nil {
-		return fmt.Errorf("error in virtual machine configuration: %s", err)
+		return errorDiagnostics(fmt.Errorf("error in virtual machine configuration: %w", err), nil)
 	}
 
 	devices := object.VirtualDeviceList(vprops.Config.Hardware.Device)
 	if spec.DeviceChange, err = applyVirtualDevices(d, client, devices); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Only carry out the reconfigure if we actually have a change to process.
 	cv := virtualmachine.GetHardwareVersionNumber(vprops.Config.Version)
@@ -698,8 +739,8 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 			// Attempt a graceful shutdown of this process. We wrap this in a VM helper.
 			timeout := d.Get("shutdown_wait_timeout").(int)
 			force := d.Get("force_power_off").(bool)
-			if err := virtualmachine.GracefulPowerOff(client, vm, timeout, force); err != nil {
-				return fmt.Errorf("error shutting down virtual machine: %s", err)
+			if err := virtualmachine.GracefulPowerOff(ctx, client, vm, timeout, force); err != nil {
+				return errorDiagnostics(fmt.Errorf("error shutting down virtual machine: %w", err), nil)
 			}
 		}
 
@@ -734,7 +775,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 						}
 						qMsg := q.Message[0].Id
 						if response, ok := questions[qMsg]; ok {
-							if err = vm.Answer(context.TODO(), q.Id, response); err != nil {
+							if err = vm.Answer(ctx, q.Id, response); err != nil {
 								log.Printf("[DEBUG] Failed to answer question. Error: %s", err)
 								break
 							}
@@ -748,18 +789,18 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 
 		// Perform updates.
 		if _, ok := d.GetOk("datastore_cluster_id"); ok {
-			err = resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(d, meta, vm, spec)
+			err = resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(ctx, d, meta, vm, spec)
 		} else {
-			err = virtualmachine.Reconfigure(vm, spec, timeout)
+			err = virtualmachine.Reconfigure(ctx, vm, spec, timeout)
 		}
 		if err != nil {
-			return err
+			return resourceVSphereVirtualMachineErrorDiagnostics(d, err)
 		}
 
 		// Upgrade the VM's hardware version if needed.
-		err = virtualmachine.SetHardwareVersion(vm, d.Get("hardware_version").(int))
+		err = virtualmachine.SetHardwareVersion(ctx, vm, d.Get("hardware_version").(int))
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 
 		// Regardless of the result we no longer need to watch for pending questions.
@@ -768,29 +809,31 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 		// Re-fetch properties
 		vprops, err = virtualmachine.Properties(vm)
 		if err != nil {
-			return fmt.Errorf("error re-fetching VM properties after update: %s", err)
+			return errorDiagnostics(fmt.Errorf("error re-fetching VM properties after update: %w", err), nil)
 		}
 		// Power back on the VM, and wait for network if necessary.
 		if vprops.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOn {
 			pTimeoutStr := fmt.Sprintf("%ds", d.Get("poweron_timeout").(int))
 			pTimeout, err := time.ParseDuration(pTimeoutStr)
 			if err != nil {
-				return fmt.Errorf("failed to parse poweron_timeout as a valid duration: %s", err)
+				return errorDiagnostics(fmt.Errorf("failed to parse poweron_timeout as a valid duration: %w", err), nil)
 			}
 			// Start the virtual machine
-			if err := virtualmachine.PowerOn(vm, pTimeout); err != nil {
-				return fmt.Errorf("error powering on virtual machine: %s", err)
+			if err := virtualmachine.PowerOn(ctx, vm, pTimeout); err != nil {
+				return errorDiagnostics(fmt.Errorf("error powering on virtual machine: %w", err), nil)
 			}
 			err = virtualmachine.WaitForGuestIP(
+				ctx,
 				client,
 				vm,
 				d.Get("wait_for_guest_ip_timeout").(int),
 				d.Get("ignored_guest_ips").([]interface{}),
 			)
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 			err = virtualmachine.WaitForGuestNet(
+				ctx,
 				client,
 				vm,
 				d.Get("wait_for_guest_net_routable").(bool),
@@ -798,7 +841,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 				d.Get("ignored_guest_ips").([]interface{}),
 			)
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 		}
 	}
@@ -810,13 +853,13 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 	// Now that any pending changes have been done (namely, any disks that don't
 	// need to be migrated have been deleted), proceed with vMotion if we have
 	// one pending.
-	if err := resourceVSphereVirtualMachineUpdateLocation(d, meta); err != nil {
-		return fmt.Errorf("error running VM migration: %s", err)
+	if err := resourceVSphereVirtualMachineUpdateLocation(ctx, d, meta); err != nil {
+		return errorDiagnostics(fmt.Errorf("error running VM migration: %w", err), nil)
 	}
 
 	// All done with updates.
 	log.Printf("[DEBUG] %s: Update complete", resourceVSphereVirtualMachineIDString(d))
-	return resourceVSphereVirtualMachineRead(d, meta)
+	return resourceVSphereVirtualMachineRead(ctx, d, meta)
 }
 
 // resourceVSphereVirtualMachineUpdateReconfigureWithSDRS runs the reconfigure
@@ -824,6 +867,7 @@ func resourceVSphereVirtualMachineUpdate(d *schema.ResourceData, meta interface{
 // designed to be run when a storage cluster is specified, versus simply
 // specifying datastores.
 func resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	vm *object.VirtualMachine,
@@ -834,7 +878,7 @@ func resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(
 	timeout := meta.(*Client).timeout
 	if !storagepod.HasDiskCreationOperations(spec.DeviceChange) {
 		log.Printf("[DEBUG] No disk operations for reconfiguration of VM %q, deferring to standard API", vm.InventoryPath)
-		return virtualmachine.Reconfigure(vm, spec, timeout)
+		return virtualmachine.Reconfigure(ctx, vm, spec, timeout)
 	}
 
 	client := meta.(*Client).vimClient
@@ -848,25 +892,28 @@ func resourceVSphereVirtualMachineUpdateReconfigureWithSDRS(
 		return fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
-	err = storagepod.ReconfigureVM(client, vm, spec, pod)
+	err = storagepod.ReconfigureVM(ctx, client, vm, spec, pod)
 	if err != nil {
 		return fmt.Errorf("error reconfiguring VM on datastore cluster %q: %s", pod.Name(), err)
 	}
 	return nil
 }
 
-func resourceVSphereVirtualMachineDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Performing delete", resourceVSphereVirtualMachineIDString(d))
+	if err := checkDeletionProtection(d, "vsphere_virtual_machine"); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	timeout := meta.(*Client).timeout
 	id := d.Id()
 	vm, err := virtualmachine.FromUUID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot locate virtual machine with UUID %q: %s", id, err)
+		return errorDiagnostics(fmt.Errorf("cannot locate virtual machine with UUID %q: %w", id, err), nil)
 	}
 	vprops, err := virtualmachine.Properties(vm)
 	if err != nil {
-		return fmt.Errorf("error fetching VM properties: %s", err)
+		return errorDiagnostics(fmt.Errorf("error fetching VM properties: %w", err), nil)
 	}
 	// Shutdown the VM first. We do attempt a graceful shutdown for the purpose
 	// of catching any edge data issues with associated virtual disks that we may
@@ -874,26 +921,26 @@ func resourceVSphereVirtualMachineDelete(d *schema.ResourceData, meta interface{
 	// flag.
 	if vprops.Runtime.PowerState != types.VirtualMachinePowerStatePoweredOff {
 		timeout := d.Get("shutdown_wait_timeout").(int)
-		if err := virtualmachine.GracefulPowerOff(client, vm, timeout, true); err != nil {
-			return fmt.Errorf("error shutting down virtual machine: %s", err)
+		if err := virtualmachine.GracefulPowerOff(ctx, client, vm, timeout, true); err != nil {
+			return errorDiagnostics(fmt.Errorf("error shutting down virtual machine: %w", err), nil)
 		}
 	}
 	// Now attempt to detach any virtual disks that may need to be preserved.
 	devices := object.VirtualDeviceList(vprops.Config.Hardware.Device)
 	spec := types.VirtualMachineConfigSpec{}
 	if spec.DeviceChange, err = virtualdevice.DiskDestroyOperation(d, client, devices); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Only run the reconfigure operation if there's actually disks in the spec.
 	if len(spec.DeviceChange) > 0 {
-		if err := virtualmachine.Reconfigure(vm, spec, timeout); err != nil {
-			return fmt.Errorf("error detaching virtual disks: %s", err)
+		if err := virtualmachine.Reconfigure(ctx, vm, spec, timeout); err != nil {
+			return errorDiagnostics(fmt.Errorf("error detaching virtual disks: %w", err), nil)
 		}
 	}
 
 	// The final operation here is to destroy the VM.
-	if err := virtualmachine.Destroy(vm); err != nil {
-		return fmt.Errorf("error destroying virtual machine: %s", err)
+	if err := virtualmachine.Destroy(ctx, vm); err != nil {
+		return errorDiagnostics(fmt.Errorf("error destroying virtual machine: %w", err), nil)
 	}
 	d.SetId("")
 	log.Printf("[DEBUG] %s: Delete complete", resourceVSphereVirtualMachineIDString(d))
@@ -904,26 +951,6 @@ func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.Res
 	log.Printf("[DEBUG] %s: Performing diff customization and validation", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 
-	// Block certain options from being set depending on the vSphere version.
-	version := viapi.ParseVersionFromClient(client)
-	if d.Get("efi_secure_boot_enabled").(bool) {
-		if version.Older(viapi.VSphereVersion{Product: version.Product, Major: 6, Minor: 5}) {
-			return fmt.Errorf("efi_secure_boot_enabled is only supported on vSphere 6.5 and higher")
-		}
-	}
-
-	if d.Get("vbs_enabled").(bool) {
-		if version.Older(viapi.VSphereVersion{Product: version.Product, Major: 6, Minor: 7}) {
-			return fmt.Errorf("vbs_enabled is only supported on vSphere 6.7 and higher")
-		}
-	}
-
-	if d.Get("vvtd_enabled").(bool) {
-		if version.Older(viapi.VSphereVersion{Product: version.Product, Major: 6, Minor: 7}) {
-			return fmt.Errorf("vvtd_enabled is only supported on vSphere 6.7 and higher")
-		}
-	}
-
 	if len(d.Get("ovf_deploy").([]interface{})) == 0 && len(d.Get("network_interface").([]interface{})) == 0 {
 		return fmt.Errorf("network_interface parameter is required when not deploying from ovf template")
 	}
@@ -1010,7 +1037,9 @@ func resourceVSphereVirtualMachineCustomizeDiff(_ context.Context, d *schema.Res
 				if k == "clone.0.timeout" {
 					continue
 				}
-				_ = d.ForceNew(k)
+				if err := deletionProtectionForceNew(d, "vsphere_virtual_machine", k); err != nil {
+					return err
+				}
 			}
 		}
 	}
@@ -1112,7 +1141,7 @@ func datastoreClusterDiffOperationCheckMembership(d *schema.ResourceDiff, client
 	return nil
 }
 
-func resourceVSphereVirtualMachineImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVirtualMachineImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	client := meta.(*Client).vimClient
 
 	name := d.Id()
@@ -1196,7 +1225,7 @@ func controllerCount(bus []bool) int {
 
 // resourceVSphereVirtualMachineCreateBare contains the "bare metal" VM
 // deploy path. The VM is returned.
-func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
+func resourceVSphereVirtualMachineCreateBare(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] %s: VM being created from scratch", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 	poolID := d.Get("resource_pool_id").(string)
@@ -1255,9 +1284,9 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 	// cluster, use the SDRS API, if not, use the standard API.
 	var vm *object.VirtualMachine
 	if _, ok := d.GetOk("datastore_cluster_id"); ok {
-		vm, err = resourceVSphereVirtualMachineCreateBareWithSDRS(d, meta, fo, spec, pool, hs)
+		vm, err = resourceVSphereVirtualMachineCreateBareWithSDRS(ctx, d, meta, fo, spec, pool, hs)
 	} else {
-		vm, err = resourceVSphereVirtualMachineCreateBareStandard(d, meta, fo, spec, pool, hs)
+		vm, err = resourceVSphereVirtualMachineCreateBareStandard(ctx, d, meta, fo, spec, pool, hs)
 	}
 	if err != nil {
 		return nil, err
@@ -1278,7 +1307,7 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 		return nil, fmt.Errorf("failed to parse poweron_timeout as a valid duration: %s", err)
 	}
 	// Start the virtual machine
-	if err := virtualmachine.PowerOn(vm, pTimeout); err != nil {
+	if err := virtualmachine.PowerOn(ctx, vm, pTimeout); err != nil {
 		return nil, fmt.Errorf("error powering on virtual machine: %s", err)
 	}
 	return vm, nil
@@ -1289,6 +1318,7 @@ func resourceVSphereVirtualMachineCreateBare(d *schema.ResourceData, meta interf
 // to be run when a storage cluster is specified, versus simply specifying
 // datastores.
 func resourceVSphereVirtualMachineCreateBareWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	fo *object.Folder,
@@ -1308,7 +1338,7 @@ func resourceVSphereVirtualMachineCreateBareWithSDRS(
 	}
 
 	timeout := meta.(*Client).timeout
-	vm, err := storagepod.CreateVM(client, fo, spec, pool, hs, pod, timeout)
+	vm, err := storagepod.CreateVM(ctx, client, fo, spec, pool, hs, pod, timeout)
 	if err != nil {
 		return nil, fmt.Errorf("error creating virtual machine on datastore cluster %q: %s", pod.Name(), err)
 	}
@@ -1319,6 +1349,7 @@ func resourceVSphereVirtualMachineCreateBareWithSDRS(
 // during resourceVSphereVirtualMachineCreateBare to create a virtual machine
 // when a datastore cluster is not supplied.
 func resourceVSphereVirtualMachineCreateBareStandard(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	fo *object.Folder,
@@ -1338,19 +1369,20 @@ func resourceVSphereVirtualMachineCreateBareStandard(
 	}
 
 	timeout := meta.(*Client).timeout
-	vm, err := virtualmachine.Create(client, fo, spec, pool, hs, timeout)
+	vm, err := virtualmachine.Create(ctx, client, fo, spec, pool, hs, timeout)
 	if err != nil {
-		return nil, fmt.Errorf("error creating virtual machine: %s", err)
+		return nil, fmt.Errorf("error creating virtual machine: %w", err)
 	}
 	return vm, nil
 }
 
 // Deploy vm from ovf/ova template
-func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
+func resourceVsphereMachineDeployOvfAndOva(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
 	client := meta.(*Client).vimClient
 	timeout := meta.(*Client).timeout
 
 	ovfParams := NewOvfHelperParamsFromVMResource(d)
+	ovfParams.Transport = meta.(*Client).transport
 	ovfHelper, err := ovfdeploy.NewOvfHelper(client, ovfParams)
 	if err != nil {
 		return nil, fmt.Errorf("while extracting OVF parameters: %s", err)
@@ -1362,7 +1394,7 @@ func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interfac
 	}
 
 	log.Print(" [DEBUG] start deploying from ovf/ova Template")
-	err = ovfHelper.DeployOvf(client, ovfImportspec)
+	err = ovfHelper.DeployOvf(ctx, client, ovfImportspec)
 	if err != nil {
 		return nil, fmt.Errorf("error while importing ovf/ova template, %s", err)
 	}
@@ -1398,13 +1430,13 @@ func resourceVsphereMachineDeployOvfAndOva(d *schema.ResourceData, meta interfac
 		vmConfigSpec := types.VirtualMachineConfigSpec{
 			VAppConfig: vappConfig,
 		}
-		err = virtualmachine.Reconfigure(vm, vmConfigSpec, timeout)
+		err = virtualmachine.Reconfigure(ctx, vm, vmConfigSpec, timeout)
 		if err != nil {
 			return nil, fmt.Errorf("error while applying vapp config %s", err)
 		}
 	}
 
-	return vm, resourceVSphereVirtualMachinePostDeployChanges(d, meta, vm, true)
+	return vm, resourceVSphereVirtualMachinePostDeployChanges(ctx, d, meta, vm, true)
 }
 
 func createVCenterDeploy(d *schema.ResourceData, meta interface{}) (*virtualmachine.VCenterDeploy, error) {
@@ -1446,7 +1478,7 @@ func createVCenterDeploy(d *schema.ResourceData, meta interface{}) (*virtualmach
 
 // resourceVSphereVirtualMachineCreateClone contains the clone VM deploy
 // path. The VM is returned.
-func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
+func resourceVSphereVirtualMachineCreateClone(ctx context.Context, d *schema.ResourceData, meta interface{}) (*object.VirtualMachine, error) {
 	log.Printf("[DEBUG] %s: VM being created from clone", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 
@@ -1474,7 +1506,16 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 		if err != nil {
 			return nil, err
 		}
-		vmoid, err := virtualmachine.Deploy(deploySpec)
+		class := viapi.TaskClassClone
+		if deploySpec.LibraryItem.Type == library.ItemTypeOVF {
+			class = viapi.TaskClassOvfDeploy
+		}
+		release, err := viapi.AcquireTask(ctx, client.Client, class, fmt.Sprintf("deployment of %q", deploySpec.VMName))
+		if err != nil {
+			return nil, err
+		}
+		vmoid, err := virtualmachine.Deploy(ctx, deploySpec)
+		release()
 		if err != nil {
 			return nil, err
 		}
@@ -1492,15 +1533,15 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 			return nil, err
 		}
 		if _, ok := d.GetOk("datastore_cluster_id"); ok {
-			vm, err = resourceVSphereVirtualMachineCreateCloneWithSDRS(d, meta, srcVM, fo, name, cloneSpec, timeout)
+			vm, err = resourceVSphereVirtualMachineCreateCloneWithSDRS(ctx, d, meta, srcVM, fo, name, cloneSpec, timeout)
 		} else {
-			vm, err = virtualmachine.Clone(client, srcVM, fo, name, cloneSpec, timeout)
+			vm, err = virtualmachine.Clone(ctx, client, srcVM, fo, name, cloneSpec, timeout)
 		}
 		if err != nil {
-			return nil, fmt.Errorf("error cloning virtual machine: %s", err)
+			return nil, fmt.Errorf("error cloning virtual machine: %w", err)
 		}
 	}
-	return vm, resourceVSphereVirtualMachinePostDeployChanges(d, meta, vm, false)
+	return vm, resourceVSphereVirtualMachinePostDeployChanges(ctx, d, meta, vm, false)
 }
 
 // resourceVSphereVirtualMachinePostDeployChanges will do post-clone
@@ -1510,12 +1551,13 @@ func resourceVSphereVirtualMachineCreateClone(d *schema.ResourceData, meta inter
 //
 // It's generally safe to not rollback after the initial re-configuration is
 // fully complete and we move on to sending the customization spec.
-func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta interface{}, vm *object.VirtualMachine, postOvf bool) error {
+func resourceVSphereVirtualMachinePostDeployChanges(ctx context.Context, d *schema.ResourceData, meta interface{}, vm *object.VirtualMachine, postOvf bool) error {
 	client := meta.(*Client).vimClient
 	poolID := d.Get("resource_pool_id").(string)
 	pool, err := resourcepool.FromID(client, poolID)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1526,6 +1568,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	vprops, err := virtualmachine.Properties(vm)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1548,6 +1591,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	storageControllercfgSpec, err := expandVirtualMachineConfigSpec(d, client)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1557,6 +1601,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.NormalizeBus(devices, d)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1566,13 +1611,14 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	storageControllercfgSpec.DeviceChange = virtualdevice.AppendDeviceChangeSpec(storageControllercfgSpec.DeviceChange, delta...)
 
 	timeout := meta.(*Client).timeout
-	err = virtualmachine.Reconfigure(vm, storageControllercfgSpec, timeout)
+	err = virtualmachine.Reconfigure(ctx, vm, storageControllercfgSpec, timeout)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
-			fmt.Errorf("error reconfiguring virtual machine: %s", err),
+			fmt.Errorf("error reconfiguring virtual machine: %w", err),
 		)
 	}
 
@@ -1582,6 +1628,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	vm, err = virtualmachine.FromUUID(client, vprops.Config.Uuid)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1591,6 +1638,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	vprops, err = virtualmachine.Properties(vm)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1607,6 +1655,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	cfgSpec, err := expandVirtualMachineConfigSpec(d, client)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1619,6 +1668,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.DiskPostCloneOperation(d, client, devices, postOvf)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1630,6 +1680,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.NetworkInterfacePostCloneOperation(d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1641,6 +1692,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.CdromPostCloneOperation(d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1652,6 +1704,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	devices, delta, err = virtualdevice.PciPassthroughPostCloneOperation(d, client, devices)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
@@ -1663,13 +1716,14 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	log.Printf("[DEBUG] %s: Final device change cfgSpec: %s", resourceVSphereVirtualMachineIDString(d), virtualdevice.DeviceChangeString(cfgSpec.DeviceChange))
 
 	// Perform updates
-	err = virtualmachine.Reconfigure(vm, cfgSpec, timeout)
+	err = virtualmachine.Reconfigure(ctx, vm, cfgSpec, timeout)
 	if err != nil {
 		return resourceVSphereVirtualMachineRollbackCreate(
+			ctx,
 			d,
 			meta,
 			vm,
-			fmt.Errorf("error reconfiguring virtual machine: %s", err),
+			fmt.Errorf("error reconfiguring virtual machine: %w", err),
 		)
 	}
 
@@ -1682,7 +1736,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	_ = d.Set("guest_id", vmprops.Config.GuestId)
 
 	// Upgrade the VM's hardware version if needed.
-	err = virtualmachine.SetHardwareVersion(vm, d.Get("hardware_version").(int))
+	err = virtualmachine.SetHardwareVersion(ctx, vm, d.Get("hardware_version").(int))
 	if err != nil {
 		return err
 	}
@@ -1700,11 +1754,18 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 		if err != nil {
 			return fmt.Errorf("cannot find OS family for guest ID %q: %s", d.Get("guest_id").(string), err)
 		}
-		custSpec := vmworkflow.ExpandCustomizationSpec(d, family)
+		custSpec, err := vmworkflow.ExpandCustomizationSpec(d, family)
+		if err != nil {
+			if derr := diagnosticsError(resourceVSphereVirtualMachineDelete(ctx, d, meta)); derr != nil {
+				return fmt.Errorf(formatVirtualMachinePostCloneRollbackError, vm.InventoryPath, err, derr)
+			}
+			d.SetId("")
+			return fmt.Errorf("error building customization spec: %s", err)
+		}
 		cw = newVirtualMachineCustomizationWaiter(client, vm, d.Get("clone.0.customize.0.timeout").(int))
-		if err := virtualmachine.Customize(vm, custSpec); err != nil {
+		if err := virtualmachine.Customize(ctx, vm, custSpec); err != nil {
 			// Roll back the VMs as per the error handling in reconfigure.
-			if derr := resourceVSphereVirtualMachineDelete(d, meta); derr != nil {
+			if derr := diagnosticsError(resourceVSphereVirtualMachineDelete(ctx, d, meta)); derr != nil {
 				return fmt.Errorf(formatVirtualMachinePostCloneRollbackError, vm.InventoryPath, err, derr)
 			}
 			d.SetId("")
@@ -1713,7 +1774,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 	}
 	// Finally time to power on the virtual machine!
 	pTimeout := time.Duration(d.Get("poweron_timeout").(int)) * time.Second
-	if err := virtualmachine.PowerOn(vm, pTimeout); err != nil {
+	if err := virtualmachine.PowerOn(ctx, vm, pTimeout); err != nil {
 		return fmt.Errorf("error powering on virtual machine: %s", err)
 	}
 	// If we customized, wait on customization.
@@ -1732,6 +1793,7 @@ func resourceVSphereVirtualMachinePostDeployChanges(d *schema.ResourceData, meta
 // to be run when a storage cluster is specified, versus simply specifying
 // datastores.
 func resourceVSphereVirtualMachineCreateCloneWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	srcVM *object.VirtualMachine,
@@ -1751,7 +1813,7 @@ func resourceVSphereVirtualMachineCreateCloneWithSDRS(
 		return nil, fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
-	vm, err := storagepod.CloneVM(client, srcVM, fo, name, spec, timeout, pod)
+	vm, err := storagepod.CloneVM(ctx, client, srcVM, fo, name, spec, timeout, pod)
 	if err != nil {
 		return nil, fmt.Errorf("error cloning on datastore cluster %q: %s", pod.Name(), err)
 	}
@@ -1768,6 +1830,7 @@ func resourceVSphereVirtualMachineCreateCloneWithSDRS(
 // If the rollback fails, an error is displayed prompting the user to manually
 // delete the virtual machine before trying again.
 func resourceVSphereVirtualMachineRollbackCreate(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	vm *object.VirtualMachine,
@@ -1778,10 +1841,51 @@ func resourceVSphereVirtualMachineRollbackCreate(
 	// keep_on_remove were attached, but just in case, we run this through delete
 	// to make sure to safely remove any disk that may have been attached as part
 	// of this process if it was flagged as such.
-	if err := resourceVSphereVirtualMachineDelete(d, meta); err != nil {
+	if err := diagnosticsError(resourceVSphereVirtualMachineDelete(ctx, d, meta)); err != nil {
 		return fmt.Errorf(formatVirtualMachinePostCloneRollbackError, vm.InventoryPath, origErr, err)
 	}
-	return fmt.Errorf("error reconfiguring virtual machine: %s", origErr)
+	return fmt.Errorf("error reconfiguring virtual machine: %w", origErr)
+}
+
+// resourceVSphereVirtualMachineErrorDiagnostics returns err as diagnostics. If
+// vSphere rejected one of the device changes sent for the virtual machine, the
+// diagnostic points at the device in the configuration and the detail names
+// the device and its key.
+func resourceVSphereVirtualMachineErrorDiagnostics(d *schema.ResourceData, err error) diag.Diagnostics {
+	var dce *virtualmachine.DeviceChangeError
+	if !errors.As(err, &dce) {
+		return errorDiagnostics(err, nil)
+	}
+	device := dce.Change.GetVirtualDeviceConfigSpec().Device
+	diags := errorDiagnostics(err, resourceVSphereVirtualMachineDevicePath(d, device))
+	diags[0].Detail += fmt.Sprintf("\nDevice: %s", virtualdevice.DeviceChangeString([]types.BaseVirtualDeviceConfigSpec{dce.Change}))
+	return diags
+}
+
+// resourceVSphereVirtualMachineDevicePath returns the path of the sub-resource
+// in the configuration that manages device. Devices are matched on their key,
+// which is only known for devices that already exist, so the path of the
+// sub-resource block as a whole is returned for new devices. A nil path is
+// returned for devices that are not managed through a sub-resource.
+func resourceVSphereVirtualMachineDevicePath(d *schema.ResourceData, device types.BaseVirtualDevice) cty.Path {
+	var name string
+	switch device.(type) {
+	case *types.VirtualDisk:
+		name = "disk"
+	case types.BaseVirtualEthernetCard:
+		name = "network_interface"
+	case *types.VirtualCdrom:
+		name = "cdrom"
+	default:
+		return nil
+	}
+	key := int(device.GetVirtualDevice().Key)
+	for i, r := range d.Get(name).([]interface{}) {
+		if m, ok := r.(map[string]interface{}); ok && key > 0 && m["key"] == key {
+			return cty.GetAttrPath(name).IndexInt(i)
+		}
+	}
+	return cty.GetAttrPath(name)
 }
 
 // resourceVSphereVirtualMachineUpdateLocation manages vMotion. This includes
@@ -1790,7 +1894,7 @@ func resourceVSphereVirtualMachineRollbackCreate(
 //
 // This function is responsible for building the top-level relocate spec. For
 // disks, we call out to relocate functionality in the disk sub-resource.
-func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineUpdateLocation(ctx context.Context, d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] %s: Checking for pending migration operations", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
 
@@ -1863,9 +1967,9 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 	// Ready to perform migration
 	timeout := d.Get("migrate_wait_timeout").(int)
 	if _, ok := d.GetOk("datastore_cluster_id"); ok {
-		err = resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(d, meta, vm, spec, timeout)
+		err = resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(ctx, d, meta, vm, spec, timeout)
 	} else {
-		err = virtualmachine.Relocate(vm, spec, timeout)
+		err = virtualmachine.Relocate(ctx, vm, spec, timeout)
 	}
 	return err
 }
@@ -1875,6 +1979,7 @@ func resourceVSphereVirtualMachineUpdateLocation(d *schema.ResourceData, meta in
 // It's designed to be run when a storage cluster is specified, versus simply
 // specifying datastores.
 func resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(
+	ctx context.Context,
 	d *schema.ResourceData,
 	meta interface{},
 	vm *object.VirtualMachine,
@@ -1892,7 +1997,7 @@ func resourceVSphereVirtualMachineUpdateLocationRelocateWithSDRS(
 		return fmt.Errorf("error getting datastore cluster: %s", err)
 	}
 
-	err = storagepod.RelocateVM(client, vm, spec, timeout, pod)
+	err = storagepod.RelocateVM(ctx, client, vm, spec, timeout, pod)
 	if err != nil {
 		return fmt.Errorf("error running vMotion on datastore cluster %q: %s", pod.Name(), err)
 	}
@@ -9,16 +9,18 @@ import (
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/viapi"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
 	"github.com/vmware/govmomi/vim25/types"
 )
 
 func resourceVSphereVirtualMachineSnapshot() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVSphereVirtualMachineSnapshotCreate,
-		Read:   resourceVSphereVirtualMachineSnapshotRead,
-		Delete: resourceVSphereVirtualMachineSnapshotDelete,
+		CreateContext: resourceVSphereVirtualMachineSnapshotCreate,
+		ReadContext:   resourceVSphereVirtualMachineSnapshotRead,
+		DeleteContext: resourceVSphereVirtualMachineSnapshotDelete,
 
 		Schema: map[string]*schema.Schema{
 			"virtual_machine_uuid": {
@@ -60,27 +62,27 @@ func resourceVSphereVirtualMachineSnapshot() *schema.Resource {
 	}
 }
 
-func resourceVSphereVirtualMachineSnapshotCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineSnapshotCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	vm, err := virtualmachine.FromUUID(client, d.Get("virtual_machine_uuid").(string))
 	if err != nil {
-		return fmt.Errorf("Error while getting the VirtualMachine :%s", err)
+		return errorDiagnostics(fmt.Errorf("Error while getting the VirtualMachine :%w", err), nil)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout) // This is 5 mins
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout) // This is 5 mins
 	defer cancel()
 	task, err := vm.CreateSnapshot(ctx, d.Get("snapshot_name").(string), d.Get("description").(string), d.Get("memory").(bool), d.Get("quiesce").(bool))
 	if err != nil {
 		log.Printf("[DEBUG] Error While Creating the Task for Create Snapshot: %v", err)
-		return fmt.Errorf("error While Creating the Task for Create Snapshot:: %s", err)
+		return errorDiagnostics(fmt.Errorf("error While Creating the Task for Create Snapshot:: %w", err), nil)
 	}
 	log.Printf("[DEBUG] Task created for Create Snapshot: %v", task)
 
-	tctx, tcancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	tctx, tcancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer tcancel()
-	taskInfo, err := task.WaitForResult(tctx, nil)
+	taskInfo, err := viapi.WaitForTask(tctx, task)
 	if err != nil {
 		log.Printf("[DEBUG] Error While waiting for the Task for Create Snapshot: %v", err)
-		return fmt.Errorf(" Error While waiting for the Task for Create Snapshot: %s", err)
+		return errorDiagnostics(fmt.Errorf(" Error While waiting for the Task for Create Snapshot: %w", err), nil)
 	}
 	log.Printf("[DEBUG] Create Snapshot completed %v", d.Get("snapshot_name").(string))
 	log.Println("[DEBUG] Managed Object Reference: " + taskInfo.Result.(types.ManagedObjectReference).Value)
@@ -88,11 +90,11 @@ func resourceVSphereVirtualMachineSnapshotCreate(d *schema.ResourceData, meta in
 	return nil
 }
 
-func resourceVSphereVirtualMachineSnapshotDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineSnapshotDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	vm, err := virtualmachine.FromUUID(client, d.Get("virtual_machine_uuid").(string))
 	if err != nil {
-		return fmt.Errorf("Error while getting the VirtualMachine :%s", err)
+		return errorDiagnostics(fmt.Errorf("Error while getting the VirtualMachine :%w", err), nil)
 	}
 
 	if d.Id() == "" {
@@ -115,32 +117,32 @@ func resourceVSphereVirtualMachineSnapshotDelete(d *schema.ResourceData, meta in
 	} else {
 		removeChildren = false
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout) // This is 5 mins
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout) // This is 5 mins
 	defer cancel()
 	task, err := vm.RemoveSnapshot(ctx, d.Id(), removeChildren, consolidatePtr)
 	if err != nil {
 		log.Printf("[DEBUG] Error While Creating the Task for Delete Snapshot: %v", err)
-		return fmt.Errorf("Error While Creating the Task for Delete Snapshot: %s", err)
+		return errorDiagnostics(fmt.Errorf("Error While Creating the Task for Delete Snapshot: %w", err), nil)
 	}
 	log.Printf("[DEBUG] Task created for Delete Snapshot: %v", task)
 
-	err = task.Wait(ctx)
+	_, err = viapi.WaitForTask(ctx, task)
 	if err != nil {
 		log.Printf("[DEBUG] Error While waiting for the Task of Delete Snapshot: %v", err)
-		return fmt.Errorf("Error While waiting for the Task of Delete Snapshot: %s", err)
+		return errorDiagnostics(fmt.Errorf("Error While waiting for the Task of Delete Snapshot: %w", err), nil)
 	}
 	log.Printf("[DEBUG] Delete Snapshot completed %v", d.Get("snapshot_name").(string))
 
 	return nil
 }
 
-func resourceVSphereVirtualMachineSnapshotRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVirtualMachineSnapshotRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	vm, err := virtualmachine.FromUUID(client, d.Get("virtual_machine_uuid").(string))
 	if err != nil {
-		return fmt.Errorf("Error while getting the VirtualMachine :%s", err)
+		return errorDiagnostics(fmt.Errorf("Error while getting the VirtualMachine :%w", err), nil)
 	}
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout) // This is 5 mins
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout) // This is 5 mins
 	defer cancel()
 	snapshot, err := vm.FindSnapshot(ctx, d.Id())
 	if err != nil {
@@ -150,7 +152,7 @@ func resourceVSphereVirtualMachineSnapshotRead(d *schema.ResourceData, meta inte
 			return nil
 		}
 		log.Printf("[DEBUG] Error While finding the Snapshot: %v", err)
-		return fmt.Errorf("Error while finding the Snapshot :%s", err)
+		return errorDiagnostics(fmt.Errorf("Error while finding the Snapshot :%w", err), nil)
 	}
 	log.Printf("[DEBUG] Snapshot found: %v", snapshot)
 	return nil
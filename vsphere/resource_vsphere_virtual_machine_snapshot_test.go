@@ -16,7 +16,7 @@ import (
 )
 
 func TestAccResourceVSphereVirtualMachineSnapshot_basic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -163,11 +163,6 @@ variable "snapshot_enabled" {
   default = "%t"
 }
 
-data "vsphere_resource_pool" "pool" {
-  name          = "${var.resource_pool}"
-  datacenter_id = "${data.vsphere_datacenter.rootdc1.id}"
-}
-
 data "vsphere_virtual_machine" "template" {
   name          = "${var.template}"
   datacenter_id = "${data.vsphere_datacenter.rootdc1.id}"
@@ -196,7 +191,11 @@ resource "vsphere_virtual_machine" "vm" {
     template_uuid = "${data.vsphere_virtual_machine.template.id}"
     linked_clone  = true
 
-
+    customize {
+      linux_options {
+        host_name = "terraform-test"
+        domain    = "test.internal"
+      }
 
       network_interface {
         ipv4_address = "${var.ipv4_address}"
@@ -217,10 +216,10 @@ resource "vsphere_virtual_machine_snapshot" "snapshot" {
   quiesce              = true
 }
 `,
+		testhelper.CombineConfigs(testhelper.ConfigDataRootDC1(), testhelper.ConfigDataRootHost1(), testhelper.ConfigDataRootHost2(), testhelper.ConfigResDS1(), testhelper.ConfigDataRootComputeCluster1(), testhelper.ConfigResResourcePool1(), testhelper.ConfigDataRootPortGroup1()),
 		os.Getenv("TF_VAR_VSPHERE_IPV4_ADDRESS"),
 		os.Getenv("TF_VAR_VSPHERE_IPV4_PREFIX"),
 		os.Getenv("TF_VAR_VSPHERE_IPV4_GATEWAY"),
-		testhelper.CombineConfigs(testhelper.ConfigDataRootDC1(), testhelper.ConfigDataRootHost1(), testhelper.ConfigDataRootHost2(), testhelper.ConfigResDS1(), testhelper.ConfigDataRootComputeCluster1(), testhelper.ConfigResResourcePool1(), testhelper.ConfigDataRootPortGroup1()),
 		os.Getenv("TF_VAR_VSPHERE_TEMPLATE"),
 		enabled,
 	)
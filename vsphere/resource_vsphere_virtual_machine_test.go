@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net"
@@ -40,7 +41,7 @@ const (
 )
 
 func TestAccResourceVSphereVirtualMachine_basic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -82,7 +83,7 @@ func TestAccResourceVSphereVirtualMachine_basic(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_TestAccResourceVSphereVirtualMachine_hardwareVersionBare(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -103,7 +104,7 @@ func TestAccResourceVSphereVirtualMachine_TestAccResourceVSphereVirtualMachine_h
 }
 
 func TestAccResourceVSphereVirtualMachine_hardwareVersionUpgrade(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -131,7 +132,7 @@ func TestAccResourceVSphereVirtualMachine_hardwareVersionUpgrade(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_hardwareVersionInvalidVersion(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -153,7 +154,7 @@ func TestAccResourceVSphereVirtualMachine_hardwareVersionInvalidVersion(t *testi
 }
 
 func TestAccResourceVSphereVirtualMachine_hardwareVersionDowngrade(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -178,7 +179,7 @@ func TestAccResourceVSphereVirtualMachine_hardwareVersionDowngrade(t *testing.T)
 }
 
 func TestAccResourceVSphereVirtualMachine_TestAccResourceVSphereVirtualMachine_hardwareVersionClone(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -199,7 +200,7 @@ func TestAccResourceVSphereVirtualMachine_TestAccResourceVSphereVirtualMachine_h
 }
 
 func TestAccResourceVSphereVirtualMachineContentLibrary_basic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -220,7 +221,7 @@ func TestAccResourceVSphereVirtualMachineContentLibrary_basic(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_ignoreValidationOnComputedValue(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -239,7 +240,7 @@ func TestAccResourceVSphereVirtualMachine_ignoreValidationOnComputedValue(t *tes
 }
 
 func TestAccResourceVSphereVirtualMachine_highLatencySensitivity(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -260,7 +261,7 @@ func TestAccResourceVSphereVirtualMachine_highLatencySensitivity(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_ESXiOnly(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -283,7 +284,7 @@ func TestAccResourceVSphereVirtualMachine_ESXiOnly(t *testing.T) {
 func TestAccResourceVSphereVirtualMachine_shutdownOK(t *testing.T) {
 	var state *terraform.State
 
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -315,7 +316,7 @@ func TestAccResourceVSphereVirtualMachine_shutdownOK(t *testing.T) {
 func TestAccResourceVSphereVirtualMachine_reCreateOnDeletion(t *testing.T) {
 	var state *terraform.State
 
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -351,7 +352,7 @@ func TestAccResourceVSphereVirtualMachine_reCreateOnDeletion(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_multiDevice(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -372,7 +373,7 @@ func TestAccResourceVSphereVirtualMachine_multiDevice(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_addDevices(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -400,7 +401,7 @@ func TestAccResourceVSphereVirtualMachine_addDevices(t *testing.T) {
 
 func TestAccResourceVSphereVirtualMachine_removeMiddleDevices(t *testing.T) {
 	var state *terraform.State
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -439,7 +440,7 @@ func TestAccResourceVSphereVirtualMachine_removeMiddleDevices(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_removeMiddleDevicesChangeDiskUnit(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -467,7 +468,7 @@ func TestAccResourceVSphereVirtualMachine_removeMiddleDevicesChangeDiskUnit(t *t
 }
 
 func TestAccResourceVSphereVirtualMachine_highDiskUnitNumbers(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -493,7 +494,7 @@ func TestAccResourceVSphereVirtualMachine_highDiskUnitNumbers(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_highDiskUnitInsufficientBus(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -511,7 +512,7 @@ func TestAccResourceVSphereVirtualMachine_highDiskUnitInsufficientBus(t *testing
 }
 
 func TestAccResourceVSphereVirtualMachine_highDiskUnitsToRegularSingleController(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -543,7 +544,7 @@ func TestAccResourceVSphereVirtualMachine_highDiskUnitsToRegularSingleController
 }
 
 func TestAccResourceVSphereVirtualMachine_scsiBusSharing(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -564,7 +565,7 @@ func TestAccResourceVSphereVirtualMachine_scsiBusSharing(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_scsiBusSharingUpdate(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -593,7 +594,7 @@ func TestAccResourceVSphereVirtualMachine_scsiBusSharingUpdate(t *testing.T) {
 
 func TestAccResourceVSphereVirtualMachine_disksKeepOnRemove(t *testing.T) {
 	var disks []map[string]string
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -621,7 +622,7 @@ func TestAccResourceVSphereVirtualMachine_disksKeepOnRemove(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cdromClientMapping(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -642,7 +643,7 @@ func TestAccResourceVSphereVirtualMachine_cdromClientMapping(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_vAppIsoBasic(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -663,7 +664,7 @@ func TestAccResourceVSphereVirtualMachine_vAppIsoBasic(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_vAppIsoNoVApp(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -686,7 +687,7 @@ func TestAccResourceVSphereVirtualMachine_vAppIsoNoVApp(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_vAppIsoNoCdrom(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -708,7 +709,7 @@ func TestAccResourceVSphereVirtualMachine_vAppIsoNoCdrom(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_vAppIsoConfigIsoIgnored(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -732,7 +733,7 @@ func TestAccResourceVSphereVirtualMachine_vAppIsoConfigIsoIgnored(t *testing.T)
 
 func TestAccResourceVSphereVirtualMachine_vAppIsoChangeCdromBacking(t *testing.T) {
 	var state *terraform.State
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -761,7 +762,7 @@ func TestAccResourceVSphereVirtualMachine_vAppIsoChangeCdromBacking(t *testing.T
 
 func TestAccResourceVSphereVirtualMachine_vAppIsoPoweredOffCdromRead(t *testing.T) {
 	var state *terraform.State
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -793,7 +794,7 @@ func TestAccResourceVSphereVirtualMachine_vAppIsoPoweredOffCdromRead(t *testing.
 }
 
 func TestAccResourceVSphereVirtualMachine_vvtdAndVbs(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			testAccResourceVSphereVirtualMachinePreCheck(t)
@@ -812,7 +813,7 @@ func TestAccResourceVSphereVirtualMachine_vvtdAndVbs(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cdromNoParameters(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -833,7 +834,7 @@ func TestAccResourceVSphereVirtualMachine_cdromNoParameters(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cdromIsoBacking(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -852,7 +853,7 @@ func TestAccResourceVSphereVirtualMachine_cdromIsoBacking(t *testing.T) {
 	})
 }
 func TestAccResourceVSphereVirtualMachine_cdromConflictingParameters(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -870,7 +871,7 @@ func TestAccResourceVSphereVirtualMachine_cdromConflictingParameters(t *testing.
 }
 
 func TestAccResourceVSphereVirtualMachine_maximumNumberOfNICs(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -891,7 +892,7 @@ func TestAccResourceVSphereVirtualMachine_maximumNumberOfNICs(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_upgradeCPUAndRam(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -922,7 +923,7 @@ func TestAccResourceVSphereVirtualMachine_upgradeCPUAndRam(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_modifyAnnotation(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -950,7 +951,7 @@ func TestAccResourceVSphereVirtualMachine_modifyAnnotation(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_growDisk(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -978,7 +979,7 @@ func TestAccResourceVSphereVirtualMachine_growDisk(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_swapSCSIBus(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1006,7 +1007,7 @@ func TestAccResourceVSphereVirtualMachine_swapSCSIBus(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_extraConfig(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1035,7 +1036,7 @@ func TestAccResourceVSphereVirtualMachine_extraConfig(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_extraConfigSwapKeys(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1064,7 +1065,7 @@ func TestAccResourceVSphereVirtualMachine_extraConfigSwapKeys(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_attachExistingVmdk(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1085,7 +1086,7 @@ func TestAccResourceVSphereVirtualMachine_attachExistingVmdk(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_attachExistingVmdkTaint(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1114,7 +1115,7 @@ func TestAccResourceVSphereVirtualMachine_attachExistingVmdkTaint(t *testing.T)
 }
 
 func TestAccResourceVSphereVirtualMachine_resourcePoolMove(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1142,7 +1143,7 @@ func TestAccResourceVSphereVirtualMachine_resourcePoolMove(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_vAppContainerAndFolder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1160,7 +1161,7 @@ func TestAccResourceVSphereVirtualMachine_vAppContainerAndFolder(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_vAppContainerMove(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1195,7 +1196,7 @@ func TestAccResourceVSphereVirtualMachine_vAppContainerMove(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_inFolder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1216,7 +1217,7 @@ func TestAccResourceVSphereVirtualMachine_inFolder(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_moveToFolder(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1243,7 +1244,7 @@ func TestAccResourceVSphereVirtualMachine_moveToFolder(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_staticMAC(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1264,7 +1265,7 @@ func TestAccResourceVSphereVirtualMachine_staticMAC(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_singleTag(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1285,7 +1286,7 @@ func TestAccResourceVSphereVirtualMachine_singleTag(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_multipleTags(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1306,7 +1307,7 @@ func TestAccResourceVSphereVirtualMachine_multipleTags(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_switchTags(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1336,7 +1337,7 @@ func TestAccResourceVSphereVirtualMachine_switchTags(t *testing.T) {
 func TestAccResourceVSphereVirtualMachine_renamedDiskInPlaceOfExisting(t *testing.T) {
 	var state *terraform.State
 
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1386,7 +1387,7 @@ func TestAccResourceVSphereVirtualMachine_renamedDiskInPlaceOfExisting(t *testin
 }
 
 func TestAccResourceVSphereVirtualMachine_blockComputedDiskName(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1410,7 +1411,7 @@ func TestAccResourceVSphereVirtualMachine_blockComputedDiskName(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_blockVAppSettingsOnNonClones(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1428,7 +1429,7 @@ func TestAccResourceVSphereVirtualMachine_blockVAppSettingsOnNonClones(t *testin
 }
 
 func TestAccResourceVSphereVirtualMachine_blockVAppSettingsOnNonClonesAfterCreation(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1458,7 +1459,7 @@ func TestAccResourceVSphereVirtualMachine_blockVAppSettingsOnNonClonesAfterCreat
 }
 
 func TestAccResourceVSphereVirtualMachine_blockDiskLabelStartingWithOrphanedPrefix(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1477,7 +1478,7 @@ func TestAccResourceVSphereVirtualMachine_blockDiskLabelStartingWithOrphanedPref
 }
 
 func TestAccResourceVSphereVirtualMachine_createIntoEmptyClusterNoEnvironmentBrowser(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1495,7 +1496,7 @@ func TestAccResourceVSphereVirtualMachine_createIntoEmptyClusterNoEnvironmentBro
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneFromTemplate(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1515,7 +1516,7 @@ func TestAccResourceVSphereVirtualMachine_cloneFromTemplate(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_clonePoweredOn(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1535,7 +1536,7 @@ func TestAccResourceVSphereVirtualMachine_clonePoweredOn(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneCustomizeWithNewResourcePool(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1557,7 +1558,7 @@ func TestAccResourceVSphereVirtualMachine_cloneCustomizeWithNewResourcePool(t *t
 func TestAccResourceVSphereVirtualMachine_cloneCustomizeForceNewWithDatastore(t *testing.T) {
 	var state *terraform.State
 
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1608,7 +1609,7 @@ func TestAccResourceVSphereVirtualMachine_cloneCustomizeForceNewWithDatastore(t
 func TestAccResourceVSphereVirtualMachine_cloneModifyDiskAndSCSITypeAtSameTime(t *testing.T) {
 	var state *terraform.State
 
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1644,7 +1645,7 @@ func TestAccResourceVSphereVirtualMachine_cloneModifyDiskAndSCSITypeAtSameTime(t
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneMultiNICFromSingleNICTemplate(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1664,7 +1665,7 @@ func TestAccResourceVSphereVirtualMachine_cloneMultiNICFromSingleNICTemplate(t *
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneWithDifferentTimezone(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1684,7 +1685,7 @@ func TestAccResourceVSphereVirtualMachine_cloneWithDifferentTimezone(t *testing.
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneBlockESXi(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1708,7 +1709,7 @@ func TestAccResourceVSphereVirtualMachine_cloneBlockESXi(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneWithBadTimezone(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1750,7 +1751,7 @@ func TestAccResourceVSphereVirtualMachine_cloneWithBadTimezone(t *testing.T) {
 //}
 
 func TestAccResourceVSphereVirtualMachine_cloneWithBadSizeWithLinkedClone(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1769,7 +1770,7 @@ func TestAccResourceVSphereVirtualMachine_cloneWithBadSizeWithLinkedClone(t *tes
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneWithBadSizeWithoutLinkedClone(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1788,7 +1789,7 @@ func TestAccResourceVSphereVirtualMachine_cloneWithBadSizeWithoutLinkedClone(t *
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneIntoEmptyCluster(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			testAccResourceVSphereVirtualMachinePreCheck(t)
@@ -1809,7 +1810,7 @@ func TestAccResourceVSphereVirtualMachine_cloneIntoEmptyCluster(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneWithDifferentHostname(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1829,7 +1830,8 @@ func TestAccResourceVSphereVirtualMachine_cloneWithDifferentHostname(t *testing.
 	})
 }
 func TestAccResourceVSphereVirtualMachine_cloneWithDiskTypeChange(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSkipIfSimulator(t, "vcsim does not find clusters referred to as ComputeResource, which creating a virtual machine without a template relies on")
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1850,7 +1852,7 @@ func TestAccResourceVSphereVirtualMachine_cloneWithDiskTypeChange(t *testing.T)
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneOnDsCuster(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1871,7 +1873,7 @@ func TestAccResourceVSphereVirtualMachine_cloneOnDsCuster(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_cpuHotAdd(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1902,7 +1904,7 @@ func TestAccResourceVSphereVirtualMachine_cpuHotAdd(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_memoryHotAdd(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1933,7 +1935,7 @@ func TestAccResourceVSphereVirtualMachine_memoryHotAdd(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_dualStackIPv4AndIPv6(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1954,7 +1956,7 @@ func TestAccResourceVSphereVirtualMachine_dualStackIPv4AndIPv6(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_hostCheck(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -1982,7 +1984,7 @@ func TestAccResourceVSphereVirtualMachine_hostCheck(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_hostVMotion(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2010,7 +2012,7 @@ func TestAccResourceVSphereVirtualMachine_hostVMotion(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_resourcePoolVMotion(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2038,7 +2040,7 @@ func TestAccResourceVSphereVirtualMachine_resourcePoolVMotion(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_storageVMotionGlobalSetting(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2074,7 +2076,7 @@ func TestAccResourceVSphereVirtualMachine_storageVMotionGlobalSetting(t *testing
 }
 
 func TestAccResourceVSphereVirtualMachine_storageVMotionSingleDisk(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2109,7 +2111,7 @@ func TestAccResourceVSphereVirtualMachine_storageVMotionSingleDisk(t *testing.T)
 }
 
 func TestAccResourceVSphereVirtualMachine_storageVMotionPinDatastore(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2144,7 +2146,7 @@ func TestAccResourceVSphereVirtualMachine_storageVMotionPinDatastore(t *testing.
 }
 
 func TestAccResourceVSphereVirtualMachine_storageVMotionRenamedVirtualMachine(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2187,7 +2189,7 @@ func TestAccResourceVSphereVirtualMachine_storageVMotionRenamedVirtualMachine(t
 func TestAccResourceVSphereVirtualMachine_storageVMotionLinkedClones(t *testing.T) {
 	var state *terraform.State
 
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2226,7 +2228,7 @@ func TestAccResourceVSphereVirtualMachine_storageVMotionLinkedClones(t *testing.
 }
 
 func TestAccResourceVSphereVirtualMachine_storageVMotionBlockExternallyAttachedDisks(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2257,7 +2259,7 @@ func TestAccResourceVSphereVirtualMachine_storageVMotionBlockExternallyAttachedD
 }
 
 func TestAccResourceVSphereVirtualMachine_singleCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2278,7 +2280,7 @@ func TestAccResourceVSphereVirtualMachine_singleCustomAttribute(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_multiCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2299,7 +2301,7 @@ func TestAccResourceVSphereVirtualMachine_multiCustomAttribute(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_switchCustomAttribute(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2327,7 +2329,7 @@ func TestAccResourceVSphereVirtualMachine_switchCustomAttribute(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_multipleDisksAtDifferentSCSISlotsImport(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2368,7 +2370,7 @@ func TestAccResourceVSphereVirtualMachine_multipleDisksAtDifferentSCSISlotsImpor
 }
 
 func TestAccResourceVSphereVirtualMachine_cloneImport(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2449,7 +2451,7 @@ func TestAccResourceVSphereVirtualMachine_cloneImport(t *testing.T) {
 }
 
 func TestAccResourceVSphereVirtualMachine_interpolatedDisk(t *testing.T) {
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2473,7 +2475,7 @@ func TestAccResourceVSphereVirtualMachine_interpolatedDisk(t *testing.T) {
 
 func TestAccResourceVSphereVirtualMachine_deployOvfFromUrl(t *testing.T) {
 	vmName := "terraform_test_vm_" + acctest.RandStringFromCharSet(4, acctest.CharSetAlphaNum)
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			RunSweepers()
 			testAccPreCheck(t)
@@ -2495,7 +2497,7 @@ func TestAccResourceVSphereVirtualMachine_deployOvfFromUrl(t *testing.T) {
 
 func TestAccResourceVSphereVirtualMachine_deployOvaFromUrl(t *testing.T) {
 	vmName := "terraform_test_vm_" + acctest.RandStringFromCharSet(4, acctest.CharSetAlphaNum)
-	resource.Test(t, resource.TestCase{
+	testAccSimulatorTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 			testAccResourceVSphereVirtualMachinePreCheck(t)
@@ -2557,7 +2559,8 @@ func testAccResourceVSphereVirtualMachinePreCheck(t *testing.T) {
 	if os.Getenv("TF_VAR_VSPHERE_ESXI2") == "" {
 		t.Skip("set TF_VAR_VSPHERE_ESXI_HOST2 to run vsphere_virtual_machine acceptance tests")
 	}
-	if os.Getenv("TF_VAR_VSPHERE_NAS_HOST") == "" {
+	// The simulator looks up its own datastore in place of the NAS datastore.
+	if os.Getenv("TF_VAR_VSPHERE_NAS_HOST") == "" && !testAccUsingSimulator() {
 		t.Skip("set TF_VAR_VSPHERE_NAS_HOST to run vsphere_virtual_machine acceptance tests")
 	}
 }
@@ -3406,7 +3409,7 @@ func testCheckVMDiskFileExists(name string) resource.TestCheckFunc {
 			return err
 		}
 		p := path.Join(path.Dir(vmxPath.Path), name)
-		exists, err := datastore.FileExists(ds, p)
+		exists, err := datastore.FileExists(context.Background(), ds, p)
 		if err != nil {
 			return err
 		}
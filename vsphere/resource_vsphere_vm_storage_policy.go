@@ -9,6 +9,7 @@ import (
 	"log"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/virtualdevice"
 	"github.com/vmware/govmomi/pbm"
@@ -40,22 +41,25 @@ func resourceVMStoragePolicy() *schema.Resource {
 	}
 
 	return &schema.Resource{
-		Create: resourceVMStoragePolicyCreate,
-		Read:   resourceVMStoragePolicyRead,
-		Update: resourceVMStoragePolicyUpdate,
-		Delete: resourceVMStoragePolicyDelete,
+		CreateContext: resourceVMStoragePolicyCreate,
+		ReadContext:   resourceVMStoragePolicyRead,
+		UpdateContext: resourceVMStoragePolicyUpdate,
+		DeleteContext: resourceVMStoragePolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: resourceVMStoragePolicyImport,
+		},
 		Schema: sch,
 	}
 }
 
-func resourceVMStoragePolicyCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Beginning create storage policy profile %s", d.Get("name").(string))
 	client := meta.(*Client).vimClient
 	rc := meta.(*Client).restClient
 
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.FromErr(fmt.Errorf("error while creating pbm client %s", err))
 	}
 
 	tagsManager := tags.NewManager(rc)
@@ -67,7 +71,7 @@ func resourceVMStoragePolicyCreate(d *schema.ResourceData, meta interface{}) err
 		tagCategory := tagRule.(map[string]interface{})["tag_category"].(string)
 		_, err := tagCategoryByName(tagsManager, tagCategory)
 		if err != nil {
-			return fmt.Errorf("error while getting the tag %s %s", tagCategory, err)
+			return diag.FromErr(fmt.Errorf("error while getting the tag %s %s", tagCategory, err))
 		}
 
 		tagValues := tagRule.(map[string]interface{})["tags"].([]interface{})
@@ -108,40 +112,40 @@ func resourceVMStoragePolicyCreate(d *schema.ResourceData, meta interface{}) err
 	}
 	pbmCapabilityProfileCreateSpec, err := pbm.CreateCapabilityProfileSpec(capabilityProfileCreateSpec)
 	if err != nil {
-		return fmt.Errorf("error while creating storage policy spec %s", err)
+		return diag.FromErr(fmt.Errorf("error while creating storage policy spec %s", err))
 	}
 
-	profileID, err := pbmClient.CreateProfile(context.Background(), *pbmCapabilityProfileCreateSpec)
+	profileID, err := pbmClient.CreateProfile(ctx, *pbmCapabilityProfileCreateSpec)
 	if err != nil {
-		return fmt.Errorf("error while creating storage policy %s", err)
+		return diag.FromErr(fmt.Errorf("error while creating storage policy %s", err))
 	}
 
 	d.SetId(profileID.UniqueId)
 	log.Printf("[DEBUG] Storage policy create complete with Id %s", profileID.UniqueId)
 
-	return resourceVMStoragePolicyRead(d, meta)
+	return resourceVMStoragePolicyRead(ctx, d, meta)
 }
 
-func resourceVMStoragePolicyRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] %s: Reading vm storage policy profile", resourceVSphereVirtualMachineIDString(d))
 	client := meta.(*Client).vimClient
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.FromErr(fmt.Errorf("error while creating pbm client %s", err))
 	}
 	profileID := types2.PbmProfileId{
 		UniqueId: d.Id(),
 	}
 	pbmProfileIds := []types2.PbmProfileId{profileID}
 
-	vmStoragePolicies, err := pbmClient.RetrieveContent(context.Background(), pbmProfileIds)
+	vmStoragePolicies, err := pbmClient.RetrieveContent(ctx, pbmProfileIds)
 	if err != nil {
 		if strings.Contains(err.Error(), "Profile not found") {
 			log.Printf("[DEBUG] storage policy profile %s: Resource has been deleted", d.Id())
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("error while reading vm storage policy profile with Id %s %s", d.Id(), err.Error())
+		return diag.FromErr(fmt.Errorf("error while reading vm storage policy profile with Id %s %s", d.Id(), err.Error()))
 	}
 	if len(vmStoragePolicies) == 0 {
 		d.SetId("")
@@ -198,13 +202,68 @@ func resourceVMStoragePolicyRead(d *schema.ResourceData, meta interface{}) error
 	return nil
 }
 
-func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) error {
+// resourceVMStoragePolicyImport imports a storage policy by its ID or name.
+func resourceVMStoragePolicyImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	client := meta.(*Client).vimClient
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating pbm client %s", err)
+	}
+
+	policies, err := pbmClient.RetrieveContent(ctx, []types2.PbmProfileId{{UniqueId: d.Id()}})
+	if err != nil || len(policies) == 0 {
+		log.Printf("[DEBUG] No storage policy with ID %q, looking it up by name", d.Id())
+		policies, err = vmStoragePoliciesByName(ctx, pbmClient, d.Id())
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(policies) == 0 {
+		return nil, fmt.Errorf("storage policy %q not found", d.Id())
+	}
+	if len(policies) > 1 {
+		return nil, fmt.Errorf("found %d storage policies named %q, import the policy by its ID instead", len(policies), d.Id())
+	}
+	policy, ok := policies[0].(*types2.PbmCapabilityProfile)
+	if !ok {
+		return nil, fmt.Errorf("storage policy %q is not a capability based policy", d.Id())
+	}
+	d.SetId(policy.ProfileId.UniqueId)
+	return []*schema.ResourceData{d}, nil
+}
+
+// vmStoragePoliciesByName returns the storage policies named name.
+func vmStoragePoliciesByName(ctx context.Context, pbmClient *pbm.Client, name string) ([]types2.BasePbmProfile, error) {
+	resourceType := types2.PbmProfileResourceType{
+		ResourceType: string(types2.PbmProfileResourceTypeEnumSTORAGE),
+	}
+	ids, err := pbmClient.QueryProfile(ctx, resourceType, "")
+	if err != nil {
+		return nil, fmt.Errorf("error while listing storage policies %s", err)
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	profiles, err := pbmClient.RetrieveContent(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("error while reading storage policies %s", err)
+	}
+	var policies []types2.BasePbmProfile
+	for _, profile := range profiles {
+		if profile.GetPbmProfile().Name == name {
+			policies = append(policies, profile)
+		}
+	}
+	return policies, nil
+}
+
+func resourceVMStoragePolicyUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Print("[DEBUG] :  Performing update")
 	client := meta.(*Client).vimClient
 	rc := meta.(*Client).restClient
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.FromErr(fmt.Errorf("error while creating pbm client %s", err))
 	}
 
 	updateSpec := types2.PbmCapabilityProfileUpdateSpec{}
@@ -222,7 +281,7 @@ func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) err
 			tagCategory := tagRule.(map[string]interface{})["tag_category"].(string)
 			_, err := tagCategoryByName(tagsManager, tagCategory)
 			if err != nil {
-				return fmt.Errorf("error while getting the tag %s %s", tagCategory, err)
+				return diag.FromErr(fmt.Errorf("error while getting the tag %s %s", tagCategory, err))
 			}
 
 			tagValues := tagRule.(map[string]interface{})["tags"].([]interface{})
@@ -258,7 +317,7 @@ func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) err
 		}
 		pbmCapabilityProfileCreateSpec, err := pbm.CreateCapabilityProfileSpec(capabilityProfileCreateSpec)
 		if err != nil {
-			return fmt.Errorf("error while creating profile updatespec %s", err)
+			return diag.FromErr(fmt.Errorf("error while creating profile updatespec %s", err))
 		}
 		updateSpec.Constraints = pbmCapabilityProfileCreateSpec.Constraints
 	}
@@ -266,20 +325,20 @@ func resourceVMStoragePolicyUpdate(d *schema.ResourceData, meta interface{}) err
 	policyIDToUpdate := types2.PbmProfileId{
 		UniqueId: d.Id(),
 	}
-	err = pbmClient.UpdateProfile(context.Background(), policyIDToUpdate, updateSpec)
+	err = pbmClient.UpdateProfile(ctx, policyIDToUpdate, updateSpec)
 	if err != nil {
-		return fmt.Errorf("error while performing profile update for Id %s %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("error while performing profile update for Id %s %s", d.Id(), err))
 	}
 	log.Print("[DEBUG] : update complete")
-	return resourceVMStoragePolicyRead(d, meta)
+	return resourceVMStoragePolicyRead(ctx, d, meta)
 }
 
-func resourceVMStoragePolicyDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVMStoragePolicyDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] Performing delete of VM storage policy with ID %s", d.Id())
 	client := meta.(*Client).vimClient
-	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	pbmClient, err := pbm.NewClient(ctx, client.Client)
 	if err != nil {
-		return fmt.Errorf("error while creating pbm client %s", err)
+		return diag.FromErr(fmt.Errorf("error while creating pbm client %s", err))
 	}
 	var policyIdsToDelete []types2.PbmProfileId
 	policyIdsToDelete = append(policyIdsToDelete, types2.PbmProfileId{
@@ -287,13 +346,13 @@ func resourceVMStoragePolicyDelete(d *schema.ResourceData, meta interface{}) err
 	})
 
 	var deleteProfileOutcome []types2.PbmProfileOperationOutcome
-	deleteProfileOutcome, err = pbmClient.DeleteProfile(context.Background(), policyIdsToDelete)
+	deleteProfileOutcome, err = pbmClient.DeleteProfile(ctx, policyIdsToDelete)
 	if err != nil {
-		return fmt.Errorf("error while deleting policy with ID %s %s", d.Id(), err)
+		return diag.FromErr(fmt.Errorf("error while deleting policy with ID %s %s", d.Id(), err))
 	}
 	if len(deleteProfileOutcome) > 0 && deleteProfileOutcome[0].Fault != nil {
-		return fmt.Errorf("error while deleting policy with ID %s %s",
-			d.Id(), deleteProfileOutcome[0].Fault.LocalizedMessage)
+		return diag.FromErr(fmt.Errorf("error while deleting policy with ID %s %s",
+			d.Id(), deleteProfileOutcome[0].Fault.LocalizedMessage))
 	}
 	d.SetId("")
 	log.Printf("[DEBUG] %s: Delete complete", d.Id())
@@ -4,6 +4,7 @@
 package vsphere
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/vmware/govmomi/pbm"
 )
 
 const policyResource = "policy1"
@@ -38,10 +40,84 @@ func TestAccResourceVMStoragePolicy_basic(t *testing.T) {
 					resource.TestCheckResourceAttr("vsphere_vm_storage_policy."+policyResource, "tag_rules.1.tags.1", "tag3"),
 				),
 			},
+			{
+				ResourceName:      "vsphere_vm_storage_policy." + policyResource,
+				ImportState:       true,
+				ImportStateId:     policyName,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func TestAccResourceVMStoragePolicy_import(t *testing.T) {
+	policyName := "terraform_test_policy" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	resource.Test(t, resource.TestCase{
+		PreCheck: func() {
+			RunSweepers()
+			testAccPreCheck(t)
+		},
+		Providers:    testAccProviders,
+		CheckDestroy: testAccResourceVMStoragePolicyCheckExists(false),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccResourceVSphereVMStoragePolicyConfigTags(),
+			},
+			{
+				PreConfig: func() {
+					if err := testAccResourceVMStoragePolicyCreateOOB(policyName); err != nil {
+						panic(err)
+					}
+				},
+				Config:             testAccResourceVSphereVMStoragePolicyonfigBasic(policyName),
+				ResourceName:       "vsphere_vm_storage_policy." + policyResource,
+				ImportState:        true,
+				ImportStateId:      policyName,
+				ImportStatePersist: true,
+			},
+			{
+				Config:   testAccResourceVSphereVMStoragePolicyonfigBasic(policyName),
+				PlanOnly: true,
+			},
 		},
 	})
 }
 
+// testAccResourceVMStoragePolicyCreateOOB creates the storage policy of
+// testAccResourceVSphereVMStoragePolicyonfigBasic outside of Terraform.
+func testAccResourceVMStoragePolicyCreateOOB(policyName string) error {
+	client := testAccProvider.Meta().(*Client).vimClient
+	pbmClient, err := pbm.NewClient(context.Background(), client.Client)
+	if err != nil {
+		return err
+	}
+	var capabilities []pbm.Capability
+	for _, rule := range [][2]string{{"cat1", "tag1"}, {"cat2", "tag2,tag3"}} {
+		capabilities = append(capabilities, pbm.Capability{
+			ID:        rule[0],
+			Namespace: TagNamespace,
+			PropertyList: []pbm.Property{
+				{
+					ID:       "com.vmware.storage.tag." + rule[0] + ".property",
+					DataType: "Set",
+					Value:    rule[1],
+				},
+			},
+		})
+	}
+	spec, err := pbm.CreateCapabilityProfileSpec(pbm.CapabilityProfileCreateSpec{
+		Name:           policyName,
+		Description:    "description",
+		CapabilityList: capabilities,
+		SubProfileName: TagPlacement,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = pbmClient.CreateProfile(context.Background(), *spec)
+	return err
+}
+
 func testAccResourceVMStoragePolicyCheckExists(expected bool) resource.TestCheckFunc {
 	return func(s *terraform.State) error {
 		_, err := testGetVMStoragePolicy(s, policyResource)
@@ -59,8 +135,8 @@ func testAccResourceVMStoragePolicyCheckExists(expected bool) resource.TestCheck
 	}
 }
 
-func testAccResourceVSphereVMStoragePolicyonfigBasic(policyName string) string {
-	return fmt.Sprintf(`
+func testAccResourceVSphereVMStoragePolicyConfigTags() string {
+	return `
 resource "vsphere_tag_category" "category1" {
   name = "cat1"
   cardinality = "SINGLE"
@@ -87,6 +163,12 @@ resource "vsphere_tag" "tag3" {
   name        = "tag3"
   category_id = "${vsphere_tag_category.category2.id}"
 }
+`
+}
+
+func testAccResourceVSphereVMStoragePolicyonfigBasic(policyName string) string {
+	return fmt.Sprintf(`
+%s
 
 resource "vsphere_vm_storage_policy" "%s" {
   name = "%s"
@@ -103,7 +185,8 @@ resource "vsphere_vm_storage_policy" "%s" {
   }
   
 }
-`, policyResource,
+`, testAccResourceVSphereVMStoragePolicyConfigTags(),
+		policyResource,
 		policyName,
 	)
 }
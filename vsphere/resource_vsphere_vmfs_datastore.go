@@ -10,6 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/customdiff"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/customattribute"
@@ -79,41 +81,52 @@ func resourceVSphereVmfsDatastore() *schema.Resource {
 
 	// Add tags schema
 	s[vSphereTagAttributeKey] = tagsSchema()
+	s[vSphereTagsAllAttributeKey] = tagsAllSchema()
+	s[deletionProtectionAttributeKey] = deletionProtectionSchema()
 	// Add custom attributes schema
 	s[customattribute.ConfigKey] = customattribute.ConfigSchema()
 
 	return &schema.Resource{
-		Create:        resourceVSphereVmfsDatastoreCreate,
-		Read:          resourceVSphereVmfsDatastoreRead,
-		Update:        resourceVSphereVmfsDatastoreUpdate,
-		Delete:        resourceVSphereVmfsDatastoreDelete,
-		CustomizeDiff: resourceVSphereVmfsDatastoreCustomizeDiff,
+		CreateContext: resourceVSphereVmfsDatastoreCreate,
+		ReadContext:   resourceVSphereVmfsDatastoreRead,
+		UpdateContext: resourceVSphereVmfsDatastoreUpdate,
+		DeleteContext: resourceVSphereVmfsDatastoreDelete,
+		CustomizeDiff: customdiff.Sequence(
+			resourceVSphereVmfsDatastoreCustomizeDiff,
+			tagsAllCustomizeDiff,
+			deletionProtectionCustomizeDiff("vsphere_vmfs_datastore", resourceVSphereVmfsDatastore),
+		),
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereVmfsDatastoreImport,
+			StateContext: resourceVSphereVmfsDatastoreImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(30 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(30 * time.Minute),
 		},
 		Schema: s,
 	}
 }
 
-func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	hsID := d.Get("host_system_id").(string)
-	dss, err := hostDatastoreSystemFromHostSystemID(client, hsID)
+	dss, err := hostDatastoreSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host datastore system: %s", err)
+		return errorDiagnostics(fmt.Errorf("error loading host datastore system: %w", err), nil)
 	}
 
 	// To ensure the datastore is fully created with all the disks that we want
@@ -121,42 +134,38 @@ func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}
 	// the rest of the extents.
 	disks := d.Get("disks").([]interface{})
 	disk := disks[0].(string)
-	spec, err := diskSpecForCreate(dss, disk)
+	spec, err := diskSpecForCreate(ctx, dss, disk)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	spec.Vmfs.VolumeName = d.Get("name").(string)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-	defer cancel()
 	ds, err := dss.CreateVmfsDatastore(ctx, *spec)
 	if err != nil {
-		return fmt.Errorf("error creating datastore with disk %s: %s", disk, err)
+		return errorDiagnostics(fmt.Errorf("error creating datastore with disk %s: %w", disk, err), nil)
 	}
 
 	// Add any remaining disks.
 	for _, disk := range disks[1:] {
 		var extendSpec *types.VmfsDatastoreExtendSpec
-		extendSpec, err = diskSpecForExtend(dss, ds, disk.(string))
+		extendSpec, err = diskSpecForExtend(ctx, dss, ds, disk.(string))
 		if err != nil {
 			// We have to destroy the created datastore here.
-			if remErr := removeDatastore(dss, ds); remErr != nil {
+			if remErr := removeDatastore(ctx, dss, ds); remErr != nil {
 				// We could not destroy the created datastore and there is now a dangling
 				// resource. We need to instruct the user to remove the datastore
 				// manually.
-				return fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, err, remErr)
+				return errorDiagnostics(fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, err, remErr), nil)
 			}
-			return fmt.Errorf("error fetching datastore extend spec for disk %q: %s", disk, err)
+			return errorDiagnostics(fmt.Errorf("error fetching datastore extend spec for disk %q: %w", disk, err), nil)
 		}
-		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-		defer cancel()
 		if _, err = extendVmfsDatastore(ctx, dss, ds, *extendSpec); err != nil {
-			if remErr := removeDatastore(dss, ds); remErr != nil {
+			if remErr := removeDatastore(ctx, dss, ds); remErr != nil {
 				// We could not destroy the created datastore and there is now a dangling
 				// resource. We need to instruct the user to remove the datastore
 				// manually.
-				return fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, err, remErr)
+				return errorDiagnostics(fmt.Errorf(formatVmfsDatastoreCreateRollbackErrorUpdate, disk, err, remErr), nil)
 			}
-			return fmt.Errorf("error extending datastore with disk %q: %s", disk, err)
+			return errorDiagnostics(fmt.Errorf("error extending datastore with disk %q: %w", disk, err), nil)
 		}
 	}
 
@@ -167,50 +176,53 @@ func resourceVSphereVmfsDatastoreCreate(d *schema.ResourceData, meta interface{}
 	// Move the datastore to the correct folder first, if specified.
 	f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	if !folder.PathIsEmpty(f) {
-		if err := datastore.MoveToFolderRelativeHostSystemID(client, ds, hsID, f); err != nil {
-			return fmt.Errorf("could not move datastore to folder %q: %s", f, err)
+		if err := datastore.MoveToFolderRelativeHostSystemID(ctx, client, ds, hsID, f); err != nil {
+			return errorDiagnostics(fmt.Errorf("could not move datastore to folder %q: %w", f, err), nil)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, ds, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Set custom attributes
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Done
-	return resourceVSphereVmfsDatastoreRead(d, meta)
+	return resourceVSphereVmfsDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := readDeletionProtection(d, meta); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	id := d.Id()
 	ds, err := datastore.FromID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot find datastore: %w", err), nil)
 	}
-	props, err := datastore.Properties(ds)
+	props, err := datastore.Properties(ds, "summary", "info", "customValue")
 	if err != nil {
-		return fmt.Errorf("could not get properties for datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("could not get properties for datastore: %w", err), nil)
 	}
 	if err := flattenDatastoreSummary(d, &props.Summary); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Set the folder
 	if err := resourceVSphereDatastoreReadFolderOrStorageClusterPath(d, ds); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// We also need to update the disk list from the summary.
@@ -219,13 +231,13 @@ func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{})
 		disks = append(disks, disk.DiskName)
 	}
 	if err := d.Set("disks", disks); err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	// Read tags if we have the ability to do so
 	if tagsClient, _ := meta.(*Client).TagsManager(); tagsClient != nil {
-		if err := readTagsForResource(tagsClient, ds, d); err != nil {
-			return err
+		if err := readTagsForResource(ctx, tagsClient, ds, d, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -237,37 +249,37 @@ func resourceVSphereVmfsDatastoreRead(d *schema.ResourceData, meta interface{})
 	return nil
 }
 
-func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 
 	// Load up the tags client, which will validate a proper vCenter before
 	// attempting to proceed if we have tags defined.
 	tagsClient, err := tagsManagerIfDefined(d, meta)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 	// Verify a proper vCenter before proceeding if custom attributes are defined
 	attrsProcessor, err := customattribute.GetDiffProcessorIfAttributesDefined(client, d)
 	if err != nil {
-		return err
+		return errorDiagnostics(err, nil)
 	}
 
 	hsID := d.Get("host_system_id").(string)
-	dss, err := hostDatastoreSystemFromHostSystemID(client, hsID)
+	dss, err := hostDatastoreSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host datastore system: %s", err)
+		return errorDiagnostics(fmt.Errorf("error loading host datastore system: %w", err), nil)
 	}
 
 	id := d.Id()
 	ds, err := datastore.FromID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot find datastore: %w", err), nil)
 	}
 
 	// Rename this datastore if our name has drifted.
 	if d.HasChange("name") {
-		if err := viapi.RenameObject(client, ds.Reference(), d.Get("name").(string)); err != nil {
-			return err
+		if err := viapi.RenameObject(ctx, client, ds.Reference(), d.Get("name").(string)); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -275,24 +287,24 @@ func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}
 	if d.HasChange("folder") || d.HasChange("datastore_cluster_id") {
 		f, err := resourceVSphereDatastoreApplyFolderOrStorageClusterPath(d, meta)
 		if err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
-		if err := datastore.MoveToFolder(client, ds, f); err != nil {
-			return fmt.Errorf("could not move datastore to folder %q: %s", f, err)
+		if err := datastore.MoveToFolder(ctx, client, ds, f); err != nil {
+			return errorDiagnostics(fmt.Errorf("could not move datastore to folder %q: %w", f, err), nil)
 		}
 	}
 
 	// Apply any pending tags now
 	if tagsClient != nil {
-		if err := processTagDiff(tagsClient, d, ds); err != nil {
-			return err
+		if err := processTagDiff(tagsClient, d, ds, meta); err != nil {
+			return errorDiagnostics(err, nil)
 		}
 	}
 
 	// Apply custom attribute updates
 	if attrsProcessor != nil {
 		if err := attrsProcessor.ProcessDiff(ds); err != nil {
-			return err
+			return errorDiagnostics(err, nil)
 		}
 	}
 
@@ -307,7 +319,7 @@ func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}
 			}
 		}
 		if !found {
-			return fmt.Errorf("disk %s found in state but not config (removal of disks is not supported)", v1)
+			return errorDiagnostics(fmt.Errorf("disk %s found in state but not config (removal of disks is not supported)", v1), nil)
 		}
 	}
 
@@ -322,34 +334,35 @@ func resourceVSphereVmfsDatastoreUpdate(d *schema.ResourceData, meta interface{}
 		}
 		if !found {
 			// Add the disk
-			spec, err := diskSpecForExtend(dss, ds, v1.(string))
+			spec, err := diskSpecForExtend(ctx, dss, ds, v1.(string))
 			if err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
-			ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
-			defer cancel()
 			if _, err := extendVmfsDatastore(ctx, dss, ds, *spec); err != nil {
-				return err
+				return errorDiagnostics(err, nil)
 			}
 		}
 	}
 
 	// Should be done with the update here.
-	return resourceVSphereVmfsDatastoreRead(d, meta)
+	return resourceVSphereVmfsDatastoreRead(ctx, d, meta)
 }
 
-func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVSphereVmfsDatastoreDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	if err := checkDeletionProtection(d, "vsphere_vmfs_datastore"); err != nil {
+		return errorDiagnostics(err, nil)
+	}
 	client := meta.(*Client).vimClient
 	hsID := d.Get("host_system_id").(string)
-	dss, err := hostDatastoreSystemFromHostSystemID(client, hsID)
+	dss, err := hostDatastoreSystemFromHostSystemID(ctx, client, hsID)
 	if err != nil {
-		return fmt.Errorf("error loading host datastore system: %s", err)
+		return errorDiagnostics(fmt.Errorf("error loading host datastore system: %w", err), nil)
 	}
 
 	id := d.Id()
 	ds, err := datastore.FromID(client, id)
 	if err != nil {
-		return fmt.Errorf("cannot find datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("cannot find datastore: %w", err), nil)
 	}
 
 	// This is a race that more than likely will only come up during tests, but
@@ -361,7 +374,7 @@ func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}
 	// minimum delay to make an honest first attempt at trying to delete the
 	// datastore without spamming the task log with errors.
 	deleteRetryFunc := func() (interface{}, string, error) {
-		err := removeDatastore(dss, ds)
+		err := removeDatastore(ctx, dss, ds)
 		if err != nil {
 			if viapi.IsResourceInUseError(err) {
 				// Pending
@@ -385,7 +398,7 @@ func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}
 
 	_, err = deleteRetry.WaitForState()
 	if err != nil {
-		return fmt.Errorf("could not delete datastore: %s", err)
+		return errorDiagnostics(fmt.Errorf("could not delete datastore: %w", err), nil)
 	}
 
 	// We need to make sure the datastore is completely removed. There appears to
@@ -416,7 +429,7 @@ func resourceVSphereVmfsDatastoreDelete(d *schema.ResourceData, meta interface{}
 
 	_, err = waitForDelete.WaitForState()
 	if err != nil {
-		return fmt.Errorf("error waiting for datastore to delete: %s", err.Error())
+		return errorDiagnostics(fmt.Errorf("error waiting for datastore to delete: %s", err.Error()), nil)
 	}
 
 	return nil
@@ -437,7 +450,7 @@ func resourceVSphereVmfsDatastoreCustomizeDiff(_ context.Context, d *schema.Reso
 	return nil
 }
 
-func resourceVSphereVmfsDatastoreImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereVmfsDatastoreImport(ctx context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 	// We support importing a MoRef - so we need to load the datastore and check
 	// to make sure 1) it exists, and 2) it's a VMFS datastore. If it is, we are
 	// good to go (rest of the stuff will be handled by read on refresh).
@@ -10,6 +10,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/hostsystem"
@@ -34,12 +35,12 @@ var vnicServiceTypeAllowedValues = []string{
 
 func resourceVsphereNic() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceVsphereNicCreate,
-		Read:   resourceVsphereNicRead,
-		Update: resourceVsphereNicUpdate,
-		Delete: resourceVsphereNicDelete,
+		CreateContext: resourceVsphereNicCreate,
+		ReadContext:   resourceVsphereNicRead,
+		UpdateContext: resourceVsphereNicUpdate,
+		DeleteContext: resourceVsphereNicDelete,
 		Importer: &schema.ResourceImporter{
-			State: resourceVSphereNicImport,
+			StateContext: resourceVSphereNicImport,
 		},
 		Schema: vNicSchema(),
 	}
@@ -57,9 +58,8 @@ func vNicSchema() map[string]*schema.Schema {
 	return base
 }
 
-func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereNicRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	log.Printf("[DEBUG] starting resource_vnic")
-	ctx := context.TODO()
 	client := meta.(*Client).vimClient
 
 	hostID, nicID := splitHostIDNicID(d)
@@ -95,7 +95,7 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 		}
 		err = d.Set("ipv4", []map[string]interface{}{ipv4dict})
 		if err != nil {
-			return err
+			return diag.FromErr(err)
 		}
 	}
 
@@ -128,7 +128,7 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 			}
 			err = d.Set("ipv6", []map[string]interface{}{ipv6dict})
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 		}
 	}
@@ -136,7 +136,7 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 	// get enabled services
 	hostSystem, err := hostsystem.FromID(client, hostID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	hostVnicMgr, err := hostSystem.ConfigManager().VirtualNicManager(ctx)
@@ -158,51 +158,51 @@ func resourceVsphereNicRead(d *schema.ResourceData, meta interface{}) error {
 		}
 	}
 	if err := d.Set("services", schema.NewSet(schema.HashString, structure.SliceStringsToInterfaces(services))); err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 
 	return nil
 }
 
-func resourceVsphereNicCreate(d *schema.ResourceData, meta interface{}) error {
-	nicID, err := createVNic(d, meta)
+func resourceVsphereNicCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	nicID, err := createVNic(ctx, d, meta)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
 	log.Printf("[DEBUG] Created NIC with ID: %s", nicID)
 	hostID := d.Get("host")
 	tfNicID := fmt.Sprintf("%s_%s", hostID, nicID)
 	d.SetId(tfNicID)
-	return resourceVsphereNicRead(d, meta)
+	return resourceVsphereNicRead(ctx, d, meta)
 }
 
-func resourceVsphereNicUpdate(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereNicUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	for _, k := range []string{
 		"portgroup", "distributed_switch_port", "distributed_port_group",
 		"mac", "mtu", "ipv4", "ipv6", "netstack", "services"} {
 		if d.HasChange(k) {
-			_, err := updateVNic(d, meta)
+			_, err := updateVNic(ctx, d, meta)
 			if err != nil {
-				return err
+				return diag.FromErr(err)
 			}
 			break
 		}
 	}
-	return resourceVsphereNicRead(d, meta)
+	return resourceVsphereNicRead(ctx, d, meta)
 }
 
-func resourceVsphereNicDelete(d *schema.ResourceData, meta interface{}) error {
+func resourceVsphereNicDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
 	client := meta.(*Client).vimClient
 	hostID, nicID := splitHostIDNicID(d)
 
-	err := removeVnic(client, hostID, nicID)
+	err := removeVnic(ctx, client, hostID, nicID)
 	if err != nil {
-		return err
+		return diag.FromErr(err)
 	}
-	return resourceVsphereNicRead(d, meta)
+	return resourceVsphereNicRead(ctx, d, meta)
 }
 
-func resourceVSphereNicImport(d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
+func resourceVSphereNicImport(ctx context.Context, d *schema.ResourceData, _ interface{}) ([]*schema.ResourceData, error) {
 	hostID, _ := splitHostIDNicID(d)
 
 	err := d.Set("host", hostID)
@@ -327,7 +327,7 @@ func BaseVMKernelSchema() map[string]*schema.Schema {
 	return sch
 }
 
-func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
+func updateVNic(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, error) {
 	err := precheckEnableServices(d)
 	if err != nil {
 		return "", err
@@ -335,24 +335,23 @@ func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 
 	client := meta.(*Client).vimClient
 	hostID, nicID := splitHostIDNicID(d)
-	ctx := context.TODO()
 
 	nic, err := getNicSpecFromSchema(d)
 	if err != nil {
 		return "", err
 	}
 
-	hns, err := getHostNetworkSystem(client, hostID)
+	hns, err := getHostNetworkSystem(ctx, client, hostID)
 	if err != nil {
 		return "", err
 	}
 
-	err = hns.UpdateVirtualNic(ctx, nicID, *nic)
+	_, err = updateHostNetworkConfig(ctx, hns, hostVirtualNicChange(types.HostConfigChangeOperationEdit, nicID, "", nic))
 	if err != nil {
 		return "", err
 	}
 
-	err = updateVnicService(d, hostID, nicID, meta)
+	err = updateVnicService(ctx, d, hostID, nicID, meta)
 	if err != nil {
 		return "", err
 	}
@@ -360,13 +359,12 @@ func updateVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	return nicID, nil
 }
 
-func updateVnicService(d *schema.ResourceData, hostID string, nicID string, meta interface{}) error {
+func updateVnicService(ctx context.Context, d *schema.ResourceData, hostID string, nicID string, meta interface{}) error {
 	serviceOld, serviceNew := d.GetChange("services")
 	deleteList := serviceOld.(*schema.Set).List()
 	addList := serviceNew.(*schema.Set).List()
 
 	client := meta.(*Client).vimClient
-	ctx := context.TODO()
 	hostSystem, err := hostsystem.FromID(client, hostID)
 	if err != nil {
 		return err
@@ -400,14 +398,13 @@ func precheckEnableServices(d *schema.ResourceData) error {
 	return nil
 }
 
-func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
+func createVNic(ctx context.Context, d *schema.ResourceData, meta interface{}) (string, error) {
 	err := precheckEnableServices(d)
 	if err != nil {
 		return "", err
 	}
 
 	client := meta.(*Client).vimClient
-	ctx := context.TODO()
 
 	nic, err := getNicSpecFromSchema(d)
 	if err != nil {
@@ -415,19 +412,19 @@ func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	}
 
 	hostID := d.Get("host").(string)
-	hns, err := getHostNetworkSystem(client, hostID)
+	hns, err := getHostNetworkSystem(ctx, client, hostID)
 	if err != nil {
 		return "", err
 	}
 
 	portgroup := d.Get("portgroup").(string)
-	nicID, err := hns.AddVirtualNic(ctx, portgroup, *nic)
+	nicID, err := updateHostNetworkConfig(ctx, hns, hostVirtualNicChange(types.HostConfigChangeOperationAdd, "", portgroup, nic))
 	if err != nil {
 		return "", err
 	}
 	d.SetId(fmt.Sprintf("%s_%s", hostID, nicID))
 
-	err = updateVnicService(d, hostID, nicID, meta)
+	err = updateVnicService(ctx, d, hostID, nicID, meta)
 	if err != nil {
 		return "", err
 	}
@@ -435,18 +432,17 @@ func createVNic(d *schema.ResourceData, meta interface{}) (string, error) {
 	return nicID, nil
 }
 
-func removeVnic(client *govmomi.Client, hostID, nicID string) error {
-	hns, err := getHostNetworkSystem(client, hostID)
+func removeVnic(ctx context.Context, client *govmomi.Client, hostID, nicID string) error {
+	hns, err := getHostNetworkSystem(ctx, client, hostID)
 	if err != nil {
 		return err
 	}
 
-	return hns.RemoveVirtualNic(context.TODO(), nicID)
+	_, err = updateHostNetworkConfig(ctx, hns, hostVirtualNicChange(types.HostConfigChangeOperationRemove, nicID, "", nil))
+	return err
 }
 
-func getHostNetworkSystem(client *govmomi.Client, hostID string) (*object.HostNetworkSystem, error) {
-	ctx := context.TODO()
-
+func getHostNetworkSystem(ctx context.Context, client *govmomi.Client, hostID string) (*object.HostNetworkSystem, error) {
 	host, err := hostsystem.FromID(client, hostID)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,248 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vmware/govmomi/ssoadmin"
+	"github.com/vmware/govmomi/ssoadmin/methods"
+	ssoadmin_types "github.com/vmware/govmomi/ssoadmin/types"
+)
+
+// ssoPrincipalID parses the ID of an SSO principal, in the form name@domain.
+// A name without a domain is in the system domain of the SSO client, such as
+// vsphere.local.
+func ssoPrincipalID(ssoclient *ssoadmin.Client, id string) ssoadmin_types.PrincipalId {
+	parts := strings.SplitN(id, "@", 2)
+	principal := ssoadmin_types.PrincipalId{
+		Name:   parts[0],
+		Domain: ssoclient.Domain,
+	}
+	if len(parts) == 2 {
+		principal.Domain = parts[1]
+	}
+	return principal
+}
+
+// ssoPrincipalIDString returns the ID of an SSO principal in the form
+// name@domain.
+func ssoPrincipalIDString(principal ssoadmin_types.PrincipalId) string {
+	return principal.Name + "@" + principal.Domain
+}
+
+// ssoLocalPrincipalID parses the ID of an SSO principal and checks that it
+// is in the system domain, which is the only domain where users and groups
+// can be created.
+func ssoLocalPrincipalID(ssoclient *ssoadmin.Client, id string) (ssoadmin_types.PrincipalId, error) {
+	principal := ssoPrincipalID(ssoclient, id)
+	if principal.Name == "" {
+		return principal, fmt.Errorf("invalid ID %q: expected name@domain", id)
+	}
+	if !strings.EqualFold(principal.Domain, ssoclient.Domain) {
+		return principal, fmt.Errorf("%s is not in the system domain %s", id, ssoclient.Domain)
+	}
+	return principal, nil
+}
+
+// ssoSetUserDisabled disables or enables the SSO user with the given ID.
+func ssoSetUserDisabled(ctx context.Context, ssoclient *ssoadmin.Client, principal ssoadmin_types.PrincipalId, disabled bool) error {
+	if disabled {
+		_, err := methods.DisableUserAccount(ctx, ssoclient, &ssoadmin_types.DisableUserAccount{
+			This:   ssoclient.ServiceContent.PrincipalManagementService,
+			UserId: principal,
+		})
+		return err
+	}
+	_, err := methods.EnableUserAccount(ctx, ssoclient, &ssoadmin_types.EnableUserAccount{
+		This:   ssoclient.ServiceContent.PrincipalManagementService,
+		UserId: principal,
+	})
+	return err
+}
+
+// ssoMemberSubtract returns the principal IDs in a that are not in b. IDs are
+// compared without regard to case, as SSO does.
+func ssoMemberSubtract(a, b []string) []string {
+	var result []string
+	for _, id := range a {
+		if !ssoMemberContains(b, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// ssoMemberIntersect returns the principal IDs in a that are also in b.
+func ssoMemberIntersect(a, b []string) []string {
+	var result []string
+	for _, id := range a {
+		if ssoMemberContains(b, id) {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// ssoMemberContains returns true if ids contains id.
+func ssoMemberContains(ids []string, id string) bool {
+	for _, v := range ids {
+		if strings.EqualFold(v, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// ssoMembersForState returns the members of a group to save in state, from
+// the members that SSO reports and the configured ones. A member that is
+// configured keeps the configured spelling. If additive is true, members
+// that are not configured are left out, so that they are not reported as
+// drift. self, the user that the provider is logged in as, is left out
+// unless it is configured, as it is never removed from the group.
+func ssoMembersForState(actual, configured []string, self string, additive bool) []string {
+	result := make([]string, 0, len(actual))
+	for _, id := range actual {
+		switch {
+		case ssoMemberContains(configured, id):
+			for _, v := range configured {
+				if strings.EqualFold(v, id) {
+					result = append(result, v)
+					break
+				}
+			}
+		case !additive && !strings.EqualFold(id, self):
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// ssoGroupMembers returns the IDs of the users, groups and solution users
+// that are direct members of the SSO group with the given ID.
+func ssoGroupMembers(ctx context.Context, ssoclient *ssoadmin.Client, group string) (users, groups, solutionUsers []string, err error) {
+	foundUsers, err := ssoclient.FindUsersInGroup(ctx, group, "")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error reading users in SSO group %s: %s", group, err)
+	}
+	for _, user := range foundUsers {
+		if user.Kind == "solution" {
+			solutionUsers = append(solutionUsers, ssoPrincipalIDString(user.Id))
+		} else {
+			users = append(users, ssoPrincipalIDString(user.Id))
+		}
+	}
+
+	foundGroups, err := ssoclient.FindGroupsInGroup(ctx, group, "")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("error reading groups in SSO group %s: %s", group, err)
+	}
+	for _, g := range foundGroups {
+		groups = append(groups, ssoPrincipalIDString(g.Id))
+	}
+
+	return users, groups, solutionUsers, nil
+}
+
+// ssoIsLocal returns true if domain is the system domain of the SSO client,
+// where local users and groups live. Principals in any other domain come
+// from an external identity source, such as Active Directory.
+func ssoIsLocal(ssoclient *ssoadmin.Client, domain string) bool {
+	return strings.EqualFold(domain, ssoclient.Domain)
+}
+
+// ssoParentGroups returns the IDs of the groups that the SSO principal is a
+// member of, directly or through other groups. The groups of a user are
+// computed by SSO, while the ones of a group are found by walking up its
+// direct parents.
+func ssoParentGroups(ctx context.Context, ssoclient *ssoadmin.Client, principal ssoadmin_types.PrincipalId, isGroup bool) ([]string, error) {
+	var result []string
+	if !isGroup {
+		parents, err := ssoclient.FindParentGroups(ctx, principal)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the groups of %s: %s", ssoPrincipalIDString(principal), err)
+		}
+		for _, parent := range parents {
+			result = append(result, ssoPrincipalIDString(parent))
+		}
+		sort.Strings(result)
+		return result, nil
+	}
+
+	seen := map[string]bool{}
+	queue := []ssoadmin_types.PrincipalId{principal}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		parents, err := ssoDirectParentGroups(ctx, ssoclient, current)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			id := strings.ToLower(ssoPrincipalIDString(parent.Id))
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			result = append(result, ssoPrincipalIDString(parent.Id))
+			queue = append(queue, parent.Id)
+		}
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// ssoDirectParentGroups returns the groups that the SSO principal is a
+// direct member of.
+func ssoDirectParentGroups(ctx context.Context, ssoclient *ssoadmin.Client, principal ssoadmin_types.PrincipalId) ([]ssoadmin_types.AdminGroup, error) {
+	res, err := methods.FindDirectParentGroups(ctx, ssoclient, &ssoadmin_types.FindDirectParentGroups{
+		This:        ssoclient.ServiceContent.PrincipalDiscoveryService,
+		PrincipalId: principal,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading the groups of %s: %s", ssoPrincipalIDString(principal), err)
+	}
+	return res.Returnval, nil
+}
+
+// ssoIsDirectMember returns true if the SSO principal is a direct member of
+// the local SSO group with the given name. Only the parents of the principal
+// are read, rather than every member of the group.
+func ssoIsDirectMember(ctx context.Context, ssoclient *ssoadmin.Client, principal ssoadmin_types.PrincipalId, group string) (bool, error) {
+	parents, err := ssoDirectParentGroups(ctx, ssoclient, principal)
+	if err != nil {
+		return false, err
+	}
+	for _, parent := range parents {
+		if strings.EqualFold(parent.Id.Name, group) && ssoIsLocal(ssoclient, parent.Id.Domain) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// ssoSessionPrincipal returns the ID of the user that the provider is logged
+// in as, in the form name@domain.
+func ssoSessionPrincipal(ctx context.Context, client *Client, ssoclient *ssoadmin.Client) (string, error) {
+	s, err := client.vimClient.SessionManager.UserSession(ctx)
+	if err != nil {
+		return "", fmt.Errorf("error reading the current session: %s", err)
+	}
+	if s == nil {
+		return "", fmt.Errorf("the provider is not logged in")
+	}
+	return ssoSessionUserID(ssoclient, s.UserName), nil
+}
+
+// ssoSessionUserID converts the user name of a session, which vCenter Server
+// reports as DOMAIN\name, to the form name@domain.
+func ssoSessionUserID(ssoclient *ssoadmin.Client, userName string) string {
+	if domain, name, ok := strings.Cut(userName, "\\"); ok {
+		return name + "@" + domain
+	}
+	return ssoPrincipalIDString(ssoPrincipalID(ssoclient, userName))
+}
@@ -0,0 +1,106 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/vmware/govmomi/ssoadmin"
+)
+
+func TestSSOMemberSubtract(t *testing.T) {
+	got := ssoMemberSubtract(
+		[]string{"alice@vsphere.local", "bob@VSPHERE.LOCAL", "carol@example.com"},
+		[]string{"BOB@vsphere.local"},
+	)
+	expected := []string{"alice@vsphere.local", "carol@example.com"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestSSOMemberIntersect(t *testing.T) {
+	got := ssoMemberIntersect(
+		[]string{"alice@vsphere.local", "bob@vsphere.local"},
+		[]string{"Bob@vsphere.local", "dave@vsphere.local"},
+	)
+	expected := []string{"bob@vsphere.local"}
+	if !reflect.DeepEqual(got, expected) {
+		t.Fatalf("expected %v, got %v", expected, got)
+	}
+}
+
+func TestSSOMembersForState(t *testing.T) {
+	actual := []string{"alice@vsphere.local", "bob@example.com", "terraform@vsphere.local"}
+
+	cases := []struct {
+		name       string
+		configured []string
+		additive   bool
+		expected   []string
+	}{
+		{
+			name:       "authoritative",
+			configured: []string{"Alice@VSPHERE.LOCAL", "carol@vsphere.local"},
+			additive:   false,
+			expected:   []string{"Alice@VSPHERE.LOCAL", "bob@example.com"},
+		},
+		{
+			name:       "additive",
+			configured: []string{"Alice@VSPHERE.LOCAL", "carol@vsphere.local"},
+			additive:   true,
+			expected:   []string{"Alice@VSPHERE.LOCAL"},
+		},
+		{
+			name:       "session user configured",
+			configured: []string{"Terraform@vsphere.local"},
+			additive:   false,
+			expected:   []string{"alice@vsphere.local", "bob@example.com", "Terraform@vsphere.local"},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := ssoMembersForState(actual, tc.configured, "TERRAFORM@vsphere.local", tc.additive)
+			if !reflect.DeepEqual(got, tc.expected) {
+				t.Fatalf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestSSORemovableMembers(t *testing.T) {
+	remove, diags := ssoRemovableMembers(
+		[]string{"alice@vsphere.local", "Terraform@VSPHERE.LOCAL"},
+		"terraform@vsphere.local",
+		"Administrators@vsphere.local",
+	)
+	expected := []string{"alice@vsphere.local"}
+	if !reflect.DeepEqual(remove, expected) {
+		t.Fatalf("expected %v, got %v", expected, remove)
+	}
+	if len(diags) != 1 || diags[0].Severity != diag.Warning {
+		t.Fatalf("expected a warning, got %#v", diags)
+	}
+
+	remove, diags = ssoRemovableMembers([]string{"alice@vsphere.local"}, "terraform@vsphere.local", "Administrators@vsphere.local")
+	if !reflect.DeepEqual(remove, expected) || len(diags) != 0 {
+		t.Fatalf("expected %v and no diagnostics, got %v, %#v", expected, remove, diags)
+	}
+}
+
+func TestSSOSessionUserID(t *testing.T) {
+	ssoclient := &ssoadmin.Client{Domain: "vsphere.local"}
+	cases := map[string]string{
+		"VSPHERE.LOCAL\\Administrator": "Administrator@VSPHERE.LOCAL",
+		"jdoe@example.com":             "jdoe@example.com",
+		"terraform":                    "terraform@vsphere.local",
+	}
+	for userName, expected := range cases {
+		if got := ssoSessionUserID(ssoclient, userName); got != expected {
+			t.Fatalf("expected %s for %s, got %s", expected, userName, got)
+		}
+	}
+}
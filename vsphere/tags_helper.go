@@ -82,26 +82,20 @@ this issue, please use a tag name unique within your vCenter system.
 // This will ensure that the correct key and schema is used across all resources.
 const vSphereTagAttributeKey = "tags"
 
-// tagsMinVersion is the minimum vSphere version required for tags.
-var tagsMinVersion = viapi.VSphereVersion{
-	Product: "VMware vCenter Server",
-	Major:   6,
-	Minor:   0,
-	Patch:   0,
-	Build:   2559268,
-}
+// vSphereTagsAllAttributeKey is the key of the computed attribute holding all
+// the tags attached to a resource, including the default tags of the provider.
+// It is added next to vSphereTagAttributeKey in the schema, along with its
+// CustomizeDiff function:
+//
+//	vSphereTagsAllAttributeKey: tagsAllSchema(),
+//	...
+//	CustomizeDiff: tagsAllCustomizeDiff,
+const vSphereTagsAllAttributeKey = "tags_all"
 
 // isEligibleRestEndpoint is a meta-validation that is used on login to see if
 // the connected endpoint supports the CIS REST API, which we use for tags.
 func isEligibleRestEndpoint(client *govmomi.Client) bool {
-	if err := viapi.ValidateVirtualCenter(client); err != nil {
-		return false
-	}
-	clientVer := viapi.ParseVersionFromClient(client)
-	if !clientVer.ProductEqual(tagsMinVersion) || clientVer.Older(tagsMinVersion) {
-		return false
-	}
-	return true
+	return viapi.HasCapability(client, viapi.CapabilityRestAPI)
 }
 
 // isEligiblePBMEndpoint is a meta-validation that is used on login to see if
@@ -207,12 +201,58 @@ func tagsSchema() *schema.Schema {
 	}
 }
 
+// tagsAllSchema returns the schema for the tags_all attribute, which holds the
+// tags set on the resource merged with the default tags of the provider.
+func tagsAllSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:        schema.TypeSet,
+		Description: "The IDs of all the tags applied to this object, including the default tags of the provider.",
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+	}
+}
+
+// tagsWithDefaults returns the tag IDs in tagIDs along with the default tags
+// of the provider.
+func tagsWithDefaults(tagIDs *schema.Set, meta interface{}) *schema.Set {
+	merged := schema.NewSet(tagIDs.F, tagIDs.List())
+	for _, id := range meta.(*Client).defaultTags {
+		merged.Add(id)
+	}
+	return merged
+}
+
+// tagsAllCustomizeDiff is the CustomizeDiff function for resources with a
+// tags_all attribute. It plans tags_all as the tags of the resource merged
+// with the default tags of the provider, so that a change to the default tags
+// shows up in the plan of every resource.
+func tagsAllCustomizeDiff(_ context.Context, d *schema.ResourceDiff, meta interface{}) error {
+	config := d.GetRawConfig()
+	if config.IsNull() {
+		return nil
+	}
+	// A set holding an unknown tag ID, such as the ID of a tag created in the
+	// same run, reads as an empty set here.
+	if !config.GetAttr(vSphereTagAttributeKey).IsWhollyKnown() {
+		return d.SetNewComputed(vSphereTagsAllAttributeKey)
+	}
+	merged := tagsWithDefaults(d.Get(vSphereTagAttributeKey).(*schema.Set), meta)
+	if d.Get(vSphereTagsAllAttributeKey).(*schema.Set).Equal(merged) {
+		return nil
+	}
+	return d.SetNew(vSphereTagsAllAttributeKey, merged)
+}
+
 // readTagsForResource reads the tags for a given reference and saves the list
 // in the supplied ResourceData. It returns an error if there was an issue
 // reading the tags.
-func readTagsForResource(tm *tags.Manager, obj object.Reference, d *schema.ResourceData) error {
+//
+// All the attached tags are saved in tags_all. The default tags of the
+// provider are left out of tags unless they were already there, so that they
+// do not show up as a change to remove them from the configuration.
+func readTagsForResource(ctx context.Context, tm *tags.Manager, obj object.Reference, d *schema.ResourceData, meta interface{}) error {
 	log.Printf("[DEBUG] Reading tags for object %q", obj.Reference().Value)
-	ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+	ctx, cancel := context.WithTimeout(ctx, defaultAPITimeout)
 	defer cancel()
 
 	ids, err := tm.ListAttachedTags(ctx, obj)
@@ -220,7 +260,18 @@ func readTagsForResource(tm *tags.Manager, obj object.Reference, d *schema.Resou
 	if err != nil {
 		return err
 	}
-	if err := d.Set(vSphereTagAttributeKey, ids); err != nil {
+	current := d.Get(vSphereTagAttributeKey).(*schema.Set)
+	defaults := schema.NewSet(current.F, structure.SliceStringsToInterfaces(meta.(*Client).defaultTags))
+	tagIDs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if current.Contains(id) || !defaults.Contains(id) {
+			tagIDs = append(tagIDs, id)
+		}
+	}
+	if err := d.Set(vSphereTagAttributeKey, tagIDs); err != nil {
+		return fmt.Errorf("error saving tag IDs to resource data: %s", err)
+	}
+	if err := d.Set(vSphereTagsAllAttributeKey, ids); err != nil {
 		return fmt.Errorf("error saving tag IDs to resource data: %s", err)
 	}
 	return nil
@@ -317,7 +368,9 @@ func (p *tagDiffProcessor) processDetachOperations() error {
 // client should be checked for nil before passing it to processTagDiff.
 func tagsManagerIfDefined(d *schema.ResourceData, meta interface{}) (*tags.Manager, error) {
 	old, newValue := d.GetChange(vSphereTagAttributeKey)
-	if len(old.(*schema.Set).List()) > 0 || len(newValue.(*schema.Set).List()) > 0 {
+	oldAll, _ := d.GetChange(vSphereTagsAllAttributeKey)
+	if len(old.(*schema.Set).List()) > 0 || len(newValue.(*schema.Set).List()) > 0 ||
+		len(oldAll.(*schema.Set).List()) > 0 || len(meta.(*Client).defaultTags) > 0 {
 		log.Printf("[DEBUG] tagsClientIfDefined: Loading tagging client")
 		tm, err := meta.(*Client).TagsManager()
 		if err != nil {
@@ -331,14 +384,20 @@ func tagsManagerIfDefined(d *schema.ResourceData, meta interface{}) (*tags.Manag
 
 // processTagDiff wraps the whole tag diffing operation into a nice clean
 // function that resources can use.
-func processTagDiff(tm *tags.Manager, d *schema.ResourceData, obj object.Reference) error {
+//
+// The tags of the resource are merged with the default tags of the provider
+// here rather than read from the planned tags_all, which is unknown when the
+// tags of the resource are. The old tags include the ones in tags, for state
+// saved before tags_all was added.
+func processTagDiff(tm *tags.Manager, d *schema.ResourceData, obj object.Reference, meta interface{}) error {
 	log.Printf("[DEBUG] Processing tags for object %q", obj.Reference().Value)
 	old, newValue := d.GetChange(vSphereTagAttributeKey)
+	oldAll, _ := d.GetChange(vSphereTagsAllAttributeKey)
 	tdp := &tagDiffProcessor{
 		manager:   tm,
 		subject:   obj,
-		oldTagIDs: structure.SliceInterfacesToStrings(old.(*schema.Set).List()),
-		newTagIDs: structure.SliceInterfacesToStrings(newValue.(*schema.Set).List()),
+		oldTagIDs: structure.SliceInterfacesToStrings(old.(*schema.Set).Union(oldAll.(*schema.Set)).List()),
+		newTagIDs: structure.SliceInterfacesToStrings(tagsWithDefaults(newValue.(*schema.Set), meta).List()),
 	}
 	if err := tdp.processDetachOperations(); err != nil {
 		return fmt.Errorf("error detaching tags to object ID %q: %s", obj.Reference().Value, err)
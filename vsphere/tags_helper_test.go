@@ -0,0 +1,198 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vapi/tags"
+	"github.com/vmware/govmomi/vim25"
+)
+
+// testTagsResource returns a resource with the tags and tags_all attributes,
+// the way the taggable resources declare them.
+func testTagsResource() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			vSphereTagAttributeKey:     tagsSchema(),
+			vSphereTagsAllAttributeKey: tagsAllSchema(),
+		},
+		CustomizeDiff: tagsAllCustomizeDiff,
+	}
+}
+
+// testTagsDiff plans a resource of testTagsResource with the tags in config,
+// from a state with the tags in tagIDs and allTagIDs, and returns the keys of
+// the attributes that change. An unknown tag ID is given as the unknown value.
+func testTagsDiff(t *testing.T, defaultTags, tagIDs, allTagIDs []string, config []cty.Value) map[string]*terraform.ResourceAttrDiff {
+	t.Helper()
+	r := testTagsResource()
+	d := r.Data(nil)
+	d.SetId("obj")
+	if err := d.Set(vSphereTagAttributeKey, tagIDs); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Set(vSphereTagsAllAttributeKey, allTagIDs); err != nil {
+		t.Fatal(err)
+	}
+	state := d.State()
+
+	tagsVal := cty.SetValEmpty(cty.String)
+	if len(config) > 0 {
+		tagsVal = cty.SetVal(config)
+	}
+	val := cty.ObjectVal(map[string]cty.Value{
+		"id":                       cty.NullVal(cty.String),
+		vSphereTagAttributeKey:     tagsVal,
+		vSphereTagsAllAttributeKey: cty.NullVal(cty.Set(cty.String)),
+	})
+	// Terraform sends the configuration along with the prior state, which is
+	// where CustomizeDiff reads it from.
+	state.RawConfig = val
+
+	meta := &Client{defaultTags: defaultTags}
+	diff, err := r.SimpleDiff(context.Background(), state, terraform.NewResourceConfigShimmed(val, r.CoreConfigSchema()), meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff == nil {
+		return nil
+	}
+	return diff.Attributes
+}
+
+func TestTagsAllCustomizeDiff(t *testing.T) {
+	t.Run("defaults not in tags", func(t *testing.T) {
+		// The default tag is attached and in tags_all, but not configured.
+		attrs := testTagsDiff(t, []string{"urn:default"}, []string{"urn:a"}, []string{"urn:a", "urn:default"}, []cty.Value{cty.StringVal("urn:a")})
+		for k, attr := range attrs {
+			t.Errorf("expected no changes, got %s: %q => %q", k, attr.Old, attr.New)
+		}
+	})
+
+	t.Run("default changes plan only tags_all", func(t *testing.T) {
+		attrs := testTagsDiff(t, []string{"urn:new"}, []string{"urn:a"}, []string{"urn:a", "urn:old"}, []cty.Value{cty.StringVal("urn:a")})
+		if len(attrs) == 0 {
+			t.Fatal("expected tags_all to change")
+		}
+		var added []string
+		for k, attr := range attrs {
+			if !strings.HasPrefix(k, vSphereTagsAllAttributeKey+".") {
+				t.Errorf("expected only tags_all to change, got %s: %q => %q", k, attr.Old, attr.New)
+				continue
+			}
+			if k != vSphereTagsAllAttributeKey+".#" && !attr.NewRemoved {
+				added = append(added, attr.New)
+			}
+		}
+		sort.Strings(added)
+		if strings.Join(added, ",") != "urn:a,urn:new" {
+			t.Errorf("expected tags_all to be planned as urn:a,urn:new, got %s", strings.Join(added, ","))
+		}
+	})
+
+	t.Run("unknown tag IDs", func(t *testing.T) {
+		// A tag created in the same run has an unknown ID until it is applied.
+		attrs := testTagsDiff(t, []string{"urn:default"}, []string{"urn:a"}, []string{"urn:a", "urn:default"}, []cty.Value{cty.StringVal("urn:a"), cty.UnknownVal(cty.String)})
+		attr, ok := attrs[vSphereTagsAllAttributeKey+".#"]
+		if !ok || !attr.NewComputed {
+			t.Errorf("expected tags_all to be unknown, got %#v", attrs)
+		}
+	})
+}
+
+func TestReadTagsForResource(t *testing.T) {
+	simulator.Test(func(ctx context.Context, c *vim25.Client) {
+		vm, err := find.NewFinder(c).VirtualMachine(ctx, "DC0_H0_VM0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rc := rest.NewClient(c)
+		if err := rc.Login(ctx, simulator.DefaultLogin); err != nil {
+			t.Fatal(err)
+		}
+		tm := tags.NewManager(rc)
+		categoryID, err := tm.CreateCategory(ctx, &tags.Category{Name: "env", Cardinality: "MULTIPLE", AssociableTypes: []string{"VirtualMachine"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids := make(map[string]string)
+		for _, name := range []string{"configured", "default", "external"} {
+			id, err := tm.CreateTag(ctx, &tags.Tag{Name: name, CategoryID: categoryID})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := tm.AttachTag(ctx, id, vm); err != nil {
+				t.Fatal(err)
+			}
+			ids[name] = id
+		}
+		meta := &Client{defaultTags: []string{ids["default"]}}
+
+		cases := []struct {
+			name       string
+			configured []string
+			tags       []string
+		}{
+			{
+				// A default tag that is not configured is left out of tags, while
+				// a tag attached outside of Terraform is read as drift.
+				name:       "defaults not in tags",
+				configured: []string{ids["configured"]},
+				tags:       []string{ids["configured"], ids["external"]},
+			},
+			{
+				name:       "defaults also in tags",
+				configured: []string{ids["configured"], ids["default"]},
+				tags:       []string{ids["configured"], ids["default"], ids["external"]},
+			},
+		}
+		for _, tc := range cases {
+			t.Run(tc.name, func(t *testing.T) {
+				r := testTagsResource()
+				d := r.Data(nil)
+				d.SetId(vm.Reference().Value)
+				if err := d.Set(vSphereTagAttributeKey, tc.configured); err != nil {
+					t.Fatal(err)
+				}
+				if err := readTagsForResource(ctx, tm, vm, d, meta); err != nil {
+					t.Fatal(err)
+				}
+				if actual := testTagsList(d, vSphereTagAttributeKey); !reflect.DeepEqual(actual, testTagsSorted(tc.tags)) {
+					t.Errorf("expected tags %v, got %v", testTagsSorted(tc.tags), actual)
+				}
+				all := []string{ids["configured"], ids["default"], ids["external"]}
+				if actual := testTagsList(d, vSphereTagsAllAttributeKey); !reflect.DeepEqual(actual, testTagsSorted(all)) {
+					t.Errorf("expected tags_all %v, got %v", testTagsSorted(all), actual)
+				}
+			})
+		}
+	})
+}
+
+// testTagsSorted returns a sorted copy of tagIDs.
+func testTagsSorted(tagIDs []string) []string {
+	sorted := append([]string(nil), tagIDs...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+// testTagsList returns the sorted tag IDs in the set attribute k.
+func testTagsList(d *schema.ResourceData, k string) []string {
+	var tagIDs []string
+	for _, v := range d.Get(k).(*schema.Set).List() {
+		tagIDs = append(tagIDs, v.(string))
+	}
+	return testTagsSorted(tagIDs)
+}
@@ -0,0 +1,157 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/recorder"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/virtualmachine"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/virtualdevice"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/find"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+)
+
+// testReplayVirtualMachineProperties returns the properties of the virtual
+// machine in the named fixture. When recording, the virtual machine named by
+// TF_VAR_VSPHERE_TEMPLATE in TF_VAR_VSPHERE_DATACENTER is used.
+func testReplayVirtualMachineProperties(t *testing.T, name string) (*govmomi.Client, *mo.VirtualMachine) {
+	t.Helper()
+	rec, client := testReplayClient(t, name)
+
+	if rec.Mode() == recorder.ModeRecord {
+		testAccCheckEnvVariablesF(t, []string{"TF_VAR_VSPHERE_DATACENTER", "TF_VAR_VSPHERE_TEMPLATE"})
+		ctx, cancel := context.WithTimeout(context.Background(), defaultAPITimeout)
+		defer cancel()
+		finder := find.NewFinder(client.Client, true)
+		dc, err := finder.Datacenter(ctx, os.Getenv("TF_VAR_VSPHERE_DATACENTER"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		finder.SetDatacenter(dc)
+		vm, err := finder.VirtualMachine(ctx, os.Getenv("TF_VAR_VSPHERE_TEMPLATE"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.Set("vm_moid", vm.Reference().Value)
+	}
+
+	vm, err := virtualmachine.FromMOID(client, rec.Get("vm_moid"))
+	if err != nil {
+		t.Fatalf("error locating virtual machine: %s", err)
+	}
+	props, err := virtualmachine.Properties(vm)
+	if err != nil {
+		t.Fatalf("error fetching virtual machine properties: %s", err)
+	}
+	return client, props
+}
+
+// testFlattenVirtualMachineConfigInfo flattens the supplied config into a
+// fresh vsphere_virtual_machine ResourceData, and reads the state back in so
+// that it looks like a refreshed resource. Controller counts are derived the
+// same way as on import.
+func testFlattenVirtualMachineConfigInfo(t *testing.T, client *govmomi.Client, config *types.VirtualMachineConfigInfo) *schema.ResourceData {
+	t.Helper()
+	d := resourceVSphereVirtualMachine().Data(&terraform.InstanceState{})
+	d.SetId(config.Uuid)
+
+	scsiBus := make([]bool, 4)
+	sataBus := make([]bool, 4)
+	ideBus := make([]bool, 2)
+	for _, device := range config.Hardware.Device {
+		switch dev := device.(type) {
+		case types.BaseVirtualSCSIController:
+			scsiBus[dev.GetVirtualSCSIController().BusNumber] = true
+		case types.BaseVirtualSATAController:
+			sataBus[dev.GetVirtualSATAController().BusNumber] = true
+		case *types.VirtualIDEController:
+			ideBus[dev.GetVirtualController().BusNumber] = true
+		}
+	}
+	_ = d.Set("scsi_controller_count", controllerCount(scsiBus))
+	_ = d.Set("sata_controller_count", controllerCount(sataBus))
+	_ = d.Set("ide_controller_count", controllerCount(ideBus))
+
+	if err := flattenVirtualMachineConfigInfo(d, config, client); err != nil {
+		t.Fatalf("error flattening config: %s", err)
+	}
+	return resourceVSphereVirtualMachine().Data(d.State())
+}
+
+func TestFlattenVirtualMachineConfigInfoReplay(t *testing.T) {
+	client, props := testReplayVirtualMachineProperties(t, "virtual_machine_config_info")
+	d := testFlattenVirtualMachineConfigInfo(t, client, props.Config)
+
+	expected := map[string]interface{}{
+		"name":                 props.Config.Name,
+		"guest_id":             props.Config.GuestId,
+		"num_cpus":             int(props.Config.Hardware.NumCPU),
+		"num_cores_per_socket": int(props.Config.Hardware.NumCoresPerSocket),
+		"memory":               int(props.Config.Hardware.MemoryMB),
+		"uuid":                 props.Config.Uuid,
+		"hardware_version":     virtualmachine.GetHardwareVersionNumber(props.Config.Version),
+	}
+	for k, v := range expected {
+		if actual := d.Get(k); actual != v {
+			t.Errorf("%s: expected %#v, got %#v", k, v, actual)
+		}
+	}
+
+	// Expanding the flattened config must not produce a change against the
+	// config it was flattened from, otherwise every refresh would lead to a
+	// reconfigure.
+	_, changed, err := expandVirtualMachineConfigSpecChanged(d, client, props.Config)
+	if err != nil {
+		t.Fatalf("error expanding config: %s", err)
+	}
+	if changed {
+		t.Fatalf("expected no config spec change after flatten/expand round trip")
+	}
+}
+
+func TestDeviceRefreshOperationsReplay(t *testing.T) {
+	client, props := testReplayVirtualMachineProperties(t, "virtual_machine_devices")
+	d := testFlattenVirtualMachineConfigInfo(t, client, props.Config)
+	devices := object.VirtualDeviceList(props.Config.Hardware.Device)
+
+	if err := virtualdevice.DiskRefreshOperation(d, client, devices); err != nil {
+		t.Fatalf("error refreshing disks: %s", err)
+	}
+	disks := devices.SelectByType((*types.VirtualDisk)(nil))
+	if actual := len(d.Get("disk").([]interface{})); actual != len(disks) {
+		t.Fatalf("expected %d disks, got %d", len(disks), actual)
+	}
+	for i, device := range disks {
+		disk := device.(*types.VirtualDisk)
+		m := d.Get("disk").([]interface{})[i].(map[string]interface{})
+		if m["key"].(int) != int(disk.Key) {
+			t.Errorf("disk %d: expected key %d, got %d", i, disk.Key, m["key"].(int))
+		}
+		if m["size"].(int) == 0 {
+			t.Errorf("disk %d: expected a size to be read", i)
+		}
+	}
+
+	if err := virtualdevice.NetworkInterfaceRefreshOperation(d, client, devices); err != nil {
+		t.Fatalf("error refreshing network interfaces: %s", err)
+	}
+	nics := devices.SelectByType((*types.VirtualEthernetCard)(nil))
+	var count int
+	for _, nic := range d.Get("network_interface").([]interface{}) {
+		if nic != nil {
+			count++
+		}
+	}
+	if count != len(nics) {
+		t.Fatalf("expected %d network interfaces, got %d", len(nics), count)
+	}
+}
@@ -0,0 +1,367 @@
+// Copyright (c) HashiCorp, Inc.
+// SPDX-License-Identifier: MPL-2.0
+
+package vsphere
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/hashicorp/terraform-provider-vsphere/vsphere/internal/helper/testhelper"
+	"github.com/vmware/govmomi"
+	"github.com/vmware/govmomi/object"
+	"github.com/vmware/govmomi/simulator"
+	"github.com/vmware/govmomi/view"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/mo"
+
+	// The following imports register the REST, SSO, STS, lookup, SPBM and
+	// vSAN endpoints with the simulator so that Config.Client can set up all
+	// of its clients against it.
+	_ "github.com/vmware/govmomi/lookup/simulator"
+	_ "github.com/vmware/govmomi/pbm/simulator"
+	_ "github.com/vmware/govmomi/ssoadmin/simulator"
+	_ "github.com/vmware/govmomi/sts/simulator"
+	_ "github.com/vmware/govmomi/vapi/simulator"
+	_ "github.com/vmware/govmomi/vsan/simulator"
+)
+
+// testAccSimulatorEnvVar is the environment variable that, when set to a
+// non-empty value, runs the acceptance tests against an in-process vcsim
+// instance instead of a live vCenter.
+const testAccSimulatorEnvVar = "VSPHERE_SIMULATOR"
+
+// testAccSimulatorEnvDefaults maps the environment variables consumed by the
+// acceptance tests to the inventory names generated by the simulator's VPX
+// model. Any variable already set in the environment is left untouched.
+var testAccSimulatorEnvDefaults = map[string]string{
+	"TF_VAR_VSPHERE_DATACENTER":    "DC0",
+	"TF_VAR_VSPHERE_CLUSTER":       "DC0_C0",
+	"TF_VAR_VSPHERE_RESOURCE_POOL": "DC0_C0/Resources",
+	"TF_VAR_VSPHERE_ESXI1":         "DC0_C0_H0",
+	"TF_VAR_VSPHERE_ESXI2":         "DC0_C0_H1",
+	"TF_VAR_VSPHERE_ESXI3":         "DC0_C0_H2",
+	"TF_VAR_VSPHERE_ESXI4":         "DC0_H0",
+	"TF_VAR_VSPHERE_NFS_DS_NAME":   "LocalDS_0",
+	"TF_VAR_VSPHERE_PG_NAME":       "DC0_DVPG0",
+	"TF_VAR_VSPHERE_TEMPLATE":      "DC0_C0_RP0_VM0",
+	"TF_VAR_VSPHERE_IPV4_ADDRESS":  "192.0.2.10",
+	"TF_VAR_VSPHERE_IPV4_PREFIX":   "24",
+	"TF_VAR_VSPHERE_IPV4_GATEWAY":  "192.0.2.1",
+}
+
+// testAccSimulator holds the state of a running simulator instance.
+type testAccSimulator struct {
+	model  *simulator.Model
+	server *simulator.Server
+
+	// The directory that backs the datastore created by
+	// testAccSimulatorCreateDatastore.
+	dsDir string
+}
+
+// testAccUsingSimulator returns true if the acceptance tests are running
+// against the simulator.
+func testAccUsingSimulator() bool {
+	return os.Getenv(testAccSimulatorEnvVar) != ""
+}
+
+// testAccSkipIfSimulator skips the current test when running against the
+// simulator. Use it for tests that exercise functionality vcsim does not
+// implement, and supply the reason so that it shows up in the test output.
+func testAccSkipIfSimulator(t *testing.T, reason string) {
+	t.Helper()
+	if testAccUsingSimulator() {
+		t.Skipf("skipping on simulator: %s", reason)
+	}
+}
+
+// testAccSimulatorUnsupportedConfigs lists the configurations that cannot be
+// applied against the simulator, with the reason why.
+var testAccSimulatorUnsupportedConfigs = []struct {
+	match  func(config string) bool
+	reason string
+}{
+	{
+		match:  testAccSimulatorConfigHasResource("vsphere_nas_datastore"),
+		reason: "vcsim does not implement RemoveDatastore, and cannot mount a NAS datastore on more than one host",
+	},
+	{
+		match:  testAccSimulatorConfigHasResource("vsphere_distributed_virtual_switch"),
+		reason: "vcsim creates switches as DistributedVirtualSwitch rather than VmwareDistributedVirtualSwitch",
+	},
+	{
+		match: func(config string) bool {
+			return testAccSimulatorConfigHasResource("vsphere_compute_cluster")(config) && strings.Contains(config, "host_system_ids")
+		},
+		reason: "vcsim does not support moving hosts out of a cluster, which destroying a cluster with hosts does",
+	},
+}
+
+// testAccSimulatorConfigHasResource returns a function that returns true if a
+// configuration has a resource of type typ.
+func testAccSimulatorConfigHasResource(typ string) func(string) bool {
+	return func(config string) bool {
+		return strings.Contains(config, fmt.Sprintf("resource %q", typ))
+	}
+}
+
+// testAccSimulatorTest runs c with resource.Test. Against the simulator, the
+// configuration of each step is rewritten with testAccSimulatorConfig, and the
+// first step whose configuration is listed in
+// testAccSimulatorUnsupportedConfigs is skipped, along with the steps that
+// follow it, as they build on the state it would have left. The steps before
+// it still run. If every step is skipped, the test is skipped with the
+// reason.
+func testAccSimulatorTest(t *testing.T, c resource.TestCase) {
+	t.Helper()
+	if !testAccUsingSimulator() {
+		resource.Test(t, c)
+		return
+	}
+
+	var reason string
+	skipped := 0
+	steps := make([]resource.TestStep, len(c.Steps))
+	for i, step := range c.Steps {
+		step.Config = testAccSimulatorConfig(step.Config)
+		if reason == "" {
+			reason = testAccSimulatorUnsupportedReason(step.Config)
+		}
+		if reason != "" {
+			n, r := i+1, reason
+			step.SkipFunc = func() (bool, error) {
+				log.Printf("[DEBUG] Skipping step %d on simulator: %s", n, r)
+				return true, nil
+			}
+			skipped++
+		}
+		steps[i] = step
+	}
+	if skipped == len(steps) && reason != "" {
+		t.Skipf("skipping on simulator: %s", reason)
+	}
+	c.Steps = steps
+	resource.Test(t, c)
+}
+
+// testAccSimulatorUnsupportedReason returns the reason why config cannot be
+// applied against the simulator, or an empty string if it can.
+func testAccSimulatorUnsupportedReason(config string) string {
+	for _, u := range testAccSimulatorUnsupportedConfigs {
+		if u.match(config) {
+			return u.reason
+		}
+	}
+	return ""
+}
+
+// testAccSimulatorDatastoreConfig looks up the datastore created by
+// testAccSimulatorCreateDatastore, under the name of the NAS datastore of
+// testhelper.ConfigResDS1.
+const testAccSimulatorDatastoreConfig = `
+data "vsphere_datastore" "ds1" {
+  datacenter_id = data.vsphere_datacenter.rootdc1.id
+  name          = "%s"
+}
+`
+
+// testAccSimulatorConfig returns config with the NAS datastore of
+// testhelper.ConfigResDS1, which vcsim cannot remove, replaced by a lookup of
+// the datastore of the same name that the simulator inventory already has.
+func testAccSimulatorConfig(config string) string {
+	if !strings.Contains(config, testhelper.ConfigResDS1()) {
+		return config
+	}
+	config = strings.ReplaceAll(config, testhelper.ConfigResDS1(), fmt.Sprintf(testAccSimulatorDatastoreConfig, testhelper.NfsDsName2))
+	return strings.ReplaceAll(config, "vsphere_nas_datastore.ds1", "data.vsphere_datastore.ds1")
+}
+
+// testAccStartSimulator creates a VPX simulator model, starts a TLS server for
+// it with all registered endpoints, and exports the connection details and
+// inventory names through the environment so that the provider and the
+// existing acceptance tests pick them up.
+func testAccStartSimulator() (*testAccSimulator, error) {
+	model := simulator.VPX()
+	if err := model.Create(); err != nil {
+		return nil, fmt.Errorf("error creating simulator model: %s", err)
+	}
+	model.Service.TLS = new(tls.Config)
+	model.Service.RegisterEndpoints = true
+	server := model.Service.NewServer()
+
+	dsDir, err := testAccSimulatorCreateDatastore(server, testhelper.NfsDsName2)
+	if err != nil {
+		server.Close()
+		model.Remove()
+		return nil, err
+	}
+
+	password, _ := server.URL.User.Password()
+	env := map[string]string{
+		"VSPHERE_SERVER":               server.URL.Host,
+		"VSPHERE_USER":                 server.URL.User.Username(),
+		"VSPHERE_PASSWORD":             password,
+		"VSPHERE_ALLOW_UNVERIFIED_SSL": "true",
+	}
+	for k, v := range testAccSimulatorEnvDefaults {
+		if _, ok := os.LookupEnv(k); !ok {
+			env[k] = v
+		}
+	}
+	// Keep the generated key of secret fingerprints out of the home directory.
+	if _, ok := os.LookupEnv("VSPHERE_VIM_SESSION_PATH"); !ok && sweepSessionDir != "" {
+		env["VSPHERE_VIM_SESSION_PATH"] = sweepSessionDir
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			server.Close()
+			model.Remove()
+			_ = os.RemoveAll(dsDir)
+			return nil, fmt.Errorf("error setting %s: %s", k, err)
+		}
+	}
+
+	log.Printf("[DEBUG] vSphere simulator listening on %s", server.URL.Host)
+	return &testAccSimulator{
+		model:  model,
+		server: server,
+		dsDir:  dsDir,
+	}, nil
+}
+
+// testSimulatorProvider returns a provider configured for the simulator that c
+// is connected to.
+func testSimulatorProvider(ctx context.Context, t *testing.T, c *vim25.Client) *schema.Provider {
+	t.Helper()
+	password, _ := simulator.DefaultLogin.Password()
+	p := Provider()
+	diags := p.Configure(ctx, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"user":                 simulator.DefaultLogin.Username(),
+		"password":             password,
+		"vsphere_server":       c.URL().Host,
+		"allow_unverified_ssl": true,
+		"vim_session_path":     t.TempDir(),
+	}))
+	if diags.HasError() {
+		t.Fatalf("error configuring the provider: %#v", diags)
+	}
+	return p
+}
+
+// testAccSimulatorCreateDatastore creates a local datastore named name on
+// every host of the simulator inventory, the way vcsim creates its LocalDS_*
+// datastores, and returns the directory that backs it. The tests that create
+// the NAS datastore of testhelper.ConfigResDS1 use it instead, see
+// testAccSimulatorConfig.
+func testAccSimulatorCreateDatastore(server *simulator.Server, name string) (string, error) {
+	ctx := context.Background()
+	c, err := govmomi.NewClient(ctx, server.URL, true)
+	if err != nil {
+		return "", fmt.Errorf("error connecting to simulator: %s", err)
+	}
+	defer func() { _ = c.Logout(ctx) }()
+
+	v, err := view.NewManager(c.Client).CreateContainerView(ctx, c.ServiceContent.RootFolder, []string{"HostSystem"}, true)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = v.Destroy(ctx) }()
+	var hosts []mo.HostSystem
+	if err := v.Retrieve(ctx, []string{"HostSystem"}, []string{"configManager.datastoreSystem"}, &hosts); err != nil {
+		return "", err
+	}
+
+	dir, err := os.MkdirTemp("", "tf-vsphere-simulator-"+name)
+	if err != nil {
+		return "", fmt.Errorf("error creating simulator datastore directory: %s", err)
+	}
+	for _, host := range hosts {
+		dss := object.NewHostDatastoreSystem(c.Client, *host.ConfigManager.DatastoreSystem)
+		if _, err := dss.CreateLocalDatastore(ctx, name, dir); err != nil {
+			_ = os.RemoveAll(dir)
+			return "", fmt.Errorf("error creating simulator datastore %s: %s", name, err)
+		}
+	}
+	return dir, nil
+}
+
+// Stop shuts down the simulator server and removes the model's backing files.
+func (s *testAccSimulator) Stop() {
+	s.server.Close()
+	s.model.Remove()
+	_ = os.RemoveAll(s.dsDir)
+}
+
+// testAccRunWithSimulator runs the test suite against a simulator instance and
+// returns the exit code.
+func testAccRunWithSimulator(m *testing.M) int {
+	s, err := testAccStartSimulator()
+	if err != nil {
+		log.Printf("[ERROR] %s", err)
+		return 1
+	}
+	defer s.Stop()
+	return m.Run()
+}
+
+func TestSimulatorConfig(t *testing.T) {
+	config := testhelper.CombineConfigs(
+		testhelper.ConfigDataRootDC1(),
+		testhelper.ConfigDataRootHost1(),
+		testhelper.ConfigResDS1(),
+	) + `
+resource "vsphere_virtual_disk" "disk" {
+  datastore = vsphere_nas_datastore.ds1.name
+}
+`
+	actual := testAccSimulatorConfig(config)
+	if strings.Contains(actual, "vsphere_nas_datastore") {
+		t.Fatalf("expected NAS datastore to be replaced, got:\n%s", actual)
+	}
+	if !strings.Contains(actual, `data "vsphere_datastore" "ds1"`) || !strings.Contains(actual, "data.vsphere_datastore.ds1.name") {
+		t.Fatalf("expected datastore data source, got:\n%s", actual)
+	}
+
+	config = testhelper.ConfigDataRootDC1()
+	if actual := testAccSimulatorConfig(config); actual != config {
+		t.Fatalf("expected config without NAS datastore to be unchanged, got:\n%s", actual)
+	}
+}
+
+func TestSimulatorCreateDatastore(t *testing.T) {
+	model := simulator.VPX()
+	defer model.Remove()
+	if err := model.Create(); err != nil {
+		t.Fatalf("error creating simulator model: %s", err)
+	}
+	server := model.Service.NewServer()
+	defer server.Close()
+
+	dir, err := testAccSimulatorCreateDatastore(server, testhelper.NfsDsName2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, obj := range simulator.Map.All("HostSystem") {
+		host := obj.(*simulator.HostSystem)
+		found := false
+		for _, ref := range host.Datastore {
+			if simulator.Map.Get(ref).(*simulator.Datastore).Name == testhelper.NfsDsName2 {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("expected datastore %s on host %s", testhelper.NfsDsName2, host.Name)
+		}
+	}
+}
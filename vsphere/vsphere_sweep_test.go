@@ -4,28 +4,64 @@
 package vsphere
 
 import (
+	"log"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
+// sweepSessionDir is the directory the sweeper clients persist their sessions
+// to while the tests run. Sessions are not persisted when it is empty, as when
+// running the sweepers alone.
+var sweepSessionDir string
+
+// testAccMain runs the tests with a scratch session directory for the
+// sweepers, which is removed when the tests finish.
+type testAccMain struct {
+	m *testing.M
+}
+
+func (t testAccMain) Run() int {
+	dir, err := os.MkdirTemp("", "tf-vsphere-test-sessions")
+	if err != nil {
+		log.Printf("[ERROR] error creating session directory: %s", err)
+		return 1
+	}
+	defer func() {
+		_ = os.RemoveAll(dir)
+	}()
+	sweepSessionDir = dir
+
+	if testAccUsingSimulator() {
+		return testAccRunWithSimulator(t.m)
+	}
+	return t.m.Run()
+}
+
 func TestMain(m *testing.M) {
-	resource.TestMain(m)
+	resource.TestMain(testAccMain{m: m})
 }
 
 func sweepVSphereClient() (*Client, error) {
+	// The sweepers set no secrets, but the client needs a directory for the key
+	// of secret fingerprints.
+	sessionDir := sweepSessionDir
+	if sessionDir == "" {
+		sessionDir = filepath.Join(os.TempDir(), "tf-vsphere-sweep-sessions")
+	}
 	config := Config{
 		InsecureFlag:    true,
 		Debug:           false,
-		Persist:         true,
+		Persist:         sweepSessionDir != "",
 		User:            os.Getenv("VSPHERE_USER"),
 		Password:        os.Getenv("VSPHERE_PASSWORD"),
 		VSphereServer:   os.Getenv("VSPHERE_SERVER"),
 		DebugPath:       "",
 		DebugPathRun:    "",
-		VimSessionPath:  "",
-		RestSessionPath: "",
+		VimSessionPath:  sessionDir,
+		RestSessionPath: sessionDir,
 		KeepAlive:       0,
 	}
 	return config.Client()